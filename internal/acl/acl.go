@@ -0,0 +1,116 @@
+package acl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Rule holds the roles allowed to read and write a single field
+type Rule struct {
+	Resource string   `json:"resource"`
+	Field    string   `json:"field"`
+	Read     []string `json:"read"`
+	Write    []string `json:"write"`
+}
+
+// Extract collects field-level access rules from all definitions.
+//
+// Rules are declared with @acl(read=..., write=...), listing roles separated
+// by "|" (e.g. @acl(read="admin|support", write=admin)). An @acl on a
+// definition is the default for its fields; a field-level @acl overrides the
+// operations it names. Fields without any rule are omitted.
+func Extract(val cue.Value) ([]Rule, error) {
+	var rules []Rule
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate definitions: %w", err)
+	}
+
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		resource := strings.TrimPrefix(iter.Selector().String(), "#")
+		def := iter.Value()
+
+		// Only structs have fields; enums and scalars have none, and the
+		// members of unions of structs are definitions of their own
+		if _, union := platoCue.UnionOf(def); union || def.IncompleteKind() != cue.StructKind {
+			continue
+		}
+		defaults, _ := platoCue.GetAttr(def, "acl")
+
+		fields, err := def.Fields(cue.Optional(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate fields of %s: %w", resource, err)
+		}
+
+		for fields.Next() {
+			if fields.Selector().IsDefinition() {
+				continue
+			}
+
+			rule := Rule{
+				Resource: resource,
+				Field:    fields.Selector().Unquoted(),
+				Read:     roles(defaults.Param("read")),
+				Write:    roles(defaults.Param("write")),
+			}
+
+			if attr, ok := platoCue.GetAttr(fields.Value(), "acl"); ok {
+				if _, set := attr.Params["read"]; set {
+					rule.Read = roles(attr.Param("read"))
+				}
+				if _, set := attr.Params["write"]; set {
+					rule.Write = roles(attr.Param("write"))
+				}
+			}
+
+			if len(rule.Read) == 0 && len(rule.Write) == 0 {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Resource != rules[j].Resource {
+			return rules[i].Resource < rules[j].Resource
+		}
+		return rules[i].Field < rules[j].Field
+	})
+
+	return rules, nil
+}
+
+// roles splits a role list such as "admin|support" into sorted role names
+func roles(value string) []string {
+	result := []string{}
+	for _, role := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' }) {
+		if role = strings.TrimSpace(role); role != "" {
+			result = append(result, role)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Tree groups rules by resource and field
+func Tree(rules []Rule) map[string]map[string]map[string][]string {
+	tree := make(map[string]map[string]map[string][]string)
+	for _, r := range rules {
+		if tree[r.Resource] == nil {
+			tree[r.Resource] = make(map[string]map[string][]string)
+		}
+		tree[r.Resource][r.Field] = map[string][]string{
+			"read":  r.Read,
+			"write": r.Write,
+		}
+	}
+	return tree
+}
@@ -0,0 +1,37 @@
+package acl
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestExtractSkipsNonStructDefinitions(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Status: "active" | "inactive"
+#A: {kind: "a"}
+#B: {kind: "b"}
+#AB: #A | #B
+#User: {
+	email:  string @acl(read="admin")
+	status: #Status
+} @acl(read="user")
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := Extract(val)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	tree := Tree(rules)
+	if _, ok := tree["User"]["email"]; !ok {
+		t.Errorf("no rule for User.email: %v", tree)
+	}
+	for _, name := range []string{"Status", "AB"} {
+		if _, ok := tree[name]; ok {
+			t.Errorf("unexpected rules for %s", name)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package attest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// StatementType is the in-toto statement version of attestations
+	StatementType = "https://in-toto.io/Statement/v1"
+
+	// PredicateType is the SLSA provenance version of the predicate
+	PredicateType = "https://slsa.dev/provenance/v1"
+
+	// BuildType identifies how platosl build turns the inputs into the
+	// subjects, for verifiers checking the external parameters
+	BuildType = "https://github.com/platoorg/plato-sl-cli/build/v1"
+
+	// BuilderID identifies the tool that produced the artifacts
+	BuilderID = "https://github.com/platoorg/plato-sl-cli"
+)
+
+// Statement is an in-toto statement about the generated artifacts
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Resource `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Resource is an artifact or input, identified by its digests
+type Resource struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is an SLSA provenance predicate
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build
+type BuildDefinition struct {
+	BuildType            string                 `json:"buildType"`
+	ExternalParameters   map[string]interface{} `json:"externalParameters"`
+	InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+	ResolvedDependencies []Resource             `json:"resolvedDependencies"`
+}
+
+// RunDetails describes the tool and run that produced the artifacts
+type RunDetails struct {
+	Builder  Builder  `json:"builder"`
+	Metadata Metadata `json:"metadata"`
+}
+
+// Builder identifies the tool that produced the artifacts
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+// Metadata identifies a build run
+type Metadata struct {
+	InvocationID string    `json:"invocationId,omitempty"`
+	StartedOn    time.Time `json:"startedOn"`
+	FinishedOn   time.Time `json:"finishedOn"`
+}
+
+// Build describes a build to attest
+type Build struct {
+	Project    string
+	ConfigFile string
+	Schemas    []string // schema paths, walked for .cue files
+	Generators []string
+	Artifacts  []string // generated files, the subjects
+	Tool       string   // platosl version
+	Started    time.Time
+	Finished   time.Time
+}
+
+// New returns the provenance statement of a build: the digests of the
+// artifacts as subjects, and of the config, schema files and source
+// commit as inputs, with the tool version and build environment
+func New(b Build) (*Statement, error) {
+	s := &Statement{
+		Type:          StatementType,
+		PredicateType: PredicateType,
+	}
+
+	for _, path := range b.Artifacts {
+		r, err := fileResource(path)
+		if err != nil {
+			return nil, err
+		}
+		s.Subject = append(s.Subject, r)
+	}
+	sort.Slice(s.Subject, func(i, j int) bool { return s.Subject[i].Name < s.Subject[j].Name })
+
+	var deps []Resource
+	if source, ok := gitSource(); ok {
+		deps = append(deps, source)
+	}
+	config, err := fileResource(b.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	deps = append(deps, config)
+	files, err := schemaFiles(b.Schemas)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		r, err := fileResource(path)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, r)
+	}
+
+	ci, invocation := ciEnvironment()
+	environment := map[string]interface{}{
+		"os":   runtime.GOOS,
+		"arch": runtime.GOARCH,
+	}
+	if len(ci) > 0 {
+		environment["ci"] = ci
+	}
+
+	s.Predicate = Provenance{
+		BuildDefinition: BuildDefinition{
+			BuildType: BuildType,
+			ExternalParameters: map[string]interface{}{
+				"project":    b.Project,
+				"config":     filepath.ToSlash(b.ConfigFile),
+				"generators": b.Generators,
+			},
+			InternalParameters:   map[string]interface{}{"environment": environment},
+			ResolvedDependencies: deps,
+		},
+		RunDetails: RunDetails{
+			Builder: Builder{
+				ID:      BuilderID,
+				Version: map[string]string{"platosl": b.Tool, "go": runtime.Version()},
+			},
+			Metadata: Metadata{
+				InvocationID: invocation,
+				StartedOn:    b.Started.UTC(),
+				FinishedOn:   b.Finished.UTC(),
+			},
+		},
+	}
+	return s, nil
+}
+
+// fileResource returns a file with its SHA-256
+func fileResource(path string) (Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Resource{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return Resource{
+		Name:   filepath.ToSlash(filepath.Clean(path)),
+		Digest: map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	}, nil
+}
+
+// schemaFiles returns the CUE files under the schema paths, sorted
+func schemaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if info.Name() == "cue.mod" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".cue") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk schema path %s: %w", root, err)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// gitSource returns the commit the working directory is checked out at,
+// e.g. git+https://github.com/acme/schemas@refs/heads/main, when it is a
+// git checkout
+func gitSource() (Resource, bool) {
+	commit, err := git("rev-parse", "HEAD")
+	if err != nil {
+		return Resource{}, false
+	}
+	uri := "git+"
+	if remote, err := git("config", "--get", "remote.origin.url"); err == nil {
+		uri += remote
+	} else {
+		uri += "file:" + filepath.ToSlash(mustAbs("."))
+	}
+	if ref, err := git("symbolic-ref", "-q", "HEAD"); err == nil {
+		uri += "@" + ref
+	}
+	return Resource{URI: uri, Digest: map[string]string{"gitCommit": commit}}, true
+}
+
+// git runs a git command in the current directory, returning its output
+func git(args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+func mustAbs(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// ciVariables are the variables recorded for each CI provider, detected by
+// the first variable
+var ciVariables = []struct {
+	provider  string
+	variables []string
+}{
+	{"github-actions", []string{"GITHUB_ACTIONS", "GITHUB_REPOSITORY", "GITHUB_REF", "GITHUB_SHA", "GITHUB_WORKFLOW_REF", "GITHUB_RUN_ID", "GITHUB_RUN_ATTEMPT", "RUNNER_OS", "RUNNER_ENVIRONMENT"}},
+	{"gitlab-ci", []string{"GITLAB_CI", "CI_PROJECT_PATH", "CI_COMMIT_REF_NAME", "CI_COMMIT_SHA", "CI_PIPELINE_ID", "CI_JOB_ID", "CI_JOB_URL", "CI_RUNNER_ID"}},
+	{"buildkite", []string{"BUILDKITE", "BUILDKITE_REPO", "BUILDKITE_BRANCH", "BUILDKITE_COMMIT", "BUILDKITE_BUILD_URL", "BUILDKITE_JOB_ID"}},
+	{"circleci", []string{"CIRCLECI", "CIRCLE_PROJECT_REPONAME", "CIRCLE_BRANCH", "CIRCLE_SHA1", "CIRCLE_BUILD_URL"}},
+}
+
+// ciEnvironment returns the CI provider and variables of the build, and an
+// identifier of the run, such as the URL of a GitHub Actions run attempt
+func ciEnvironment() (map[string]string, string) {
+	for _, ci := range ciVariables {
+		if os.Getenv(ci.variables[0]) == "" {
+			continue
+		}
+		env := map[string]string{"provider": ci.provider}
+		for _, name := range ci.variables[1:] {
+			if value := os.Getenv(name); value != "" {
+				env[name] = value
+			}
+		}
+
+		var invocation string
+		switch ci.provider {
+		case "github-actions":
+			server := os.Getenv("GITHUB_SERVER_URL")
+			if server == "" {
+				server = "https://github.com"
+			}
+			if env["GITHUB_RUN_ID"] != "" {
+				invocation = fmt.Sprintf("%s/%s/actions/runs/%s", server, env["GITHUB_REPOSITORY"], env["GITHUB_RUN_ID"])
+				if env["GITHUB_RUN_ATTEMPT"] != "" {
+					invocation += "/attempts/" + env["GITHUB_RUN_ATTEMPT"]
+				}
+			}
+		case "gitlab-ci":
+			invocation = env["CI_JOB_URL"]
+		case "buildkite":
+			invocation = env["BUILDKITE_BUILD_URL"]
+		case "circleci":
+			invocation = env["CIRCLE_BUILD_URL"]
+		}
+		return env, invocation
+	}
+	if os.Getenv("CI") != "" {
+		return map[string]string{"provider": "ci"}, ""
+	}
+	return nil, ""
+}
@@ -0,0 +1,73 @@
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewAndSign(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	cfg := write("platosl.yaml", "version: v2\n")
+	write("schema.cue", "#A: {}\n")
+	artifact := write("types.ts", "export {};\n")
+
+	s, err := New(Build{
+		Project:    "test",
+		ConfigFile: cfg,
+		Schemas:    []string{dir},
+		Generators: []string{"typescript"},
+		Artifacts:  []string{artifact},
+		Started:    time.Now(),
+		Finished:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	digest := sha256.Sum256([]byte("export {};\n"))
+	if len(s.Subject) != 1 || s.Subject[0].Digest["sha256"] != hex.EncodeToString(digest[:]) {
+		t.Errorf("Subject = %v, want the digest of types.ts", s.Subject)
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := LoadKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("LoadKey: %v", err)
+	}
+
+	env, err := Sign(s, key)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	payload, _ := base64.StdEncoding.DecodeString(env.Payload)
+	sig, _ := base64.StdEncoding.DecodeString(env.Signatures[0].Sig)
+	if !ed25519.Verify(priv.Public().(ed25519.PublicKey), pae(PayloadType, payload), sig) {
+		t.Errorf("signature does not verify over the DSSE encoding of the payload")
+	}
+
+	if _, err := LoadKey([]byte("not a key")); err == nil {
+		t.Errorf("LoadKey accepted data without a PEM block")
+	}
+}
@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// DefaultPath is the local audit log used when none is configured
+const DefaultPath = ".platosl/audit.log"
+
+// Record is a single audit log entry
+type Record struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	Host       string    `json:"host"`
+	Operation  string    `json:"operation"`
+	Project    string    `json:"project"`
+	Version    string    `json:"version"`
+	SchemaHash string    `json:"schemaHash"`
+	Targets    []Target  `json:"targets"`
+}
+
+// Target describes one produced or published artifact
+type Target struct {
+	Name   string `json:"name"`
+	Output string `json:"output"`
+	Hash   string `json:"hash,omitempty"`
+}
+
+// NewRecord creates a record for an operation, filling in who and when.
+// PLATOSL_AUDIT_USER overrides the OS user (e.g. a CI job identity).
+func NewRecord(operation, project, version string) Record {
+	host, _ := os.Hostname()
+	return Record{
+		Time:      time.Now().UTC(),
+		User:      currentUser(),
+		Host:      host,
+		Operation: operation,
+		Project:   project,
+		Version:   version,
+	}
+}
+
+// Hash returns the SHA-256 of data as "sha256:<hex>"
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// SchemaHash hashes all CUE files under the given schema paths. Files are
+// hashed in sorted order together with their paths, so the result only
+// changes when schema content or layout changes.
+func SchemaHash(paths []string) (string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if info.Name() == "cue.mod" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".cue") {
+				files = append(files, filepath.ToSlash(path))
+			}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to walk schema path %s: %w", root, err)
+		}
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00", file)
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Append writes a record as one JSON line to the local log file
+func Append(path string, rec Record) error {
+	if path == "" {
+		path = DefaultPath
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Send posts a record to a remote audit endpoint
+func Send(client *httpclient.Client, url string, rec Record) error {
+	if err := client.DoJSON("POST", url, rec, nil); err != nil {
+		return fmt.Errorf("failed to send audit record: %w", err)
+	}
+	return nil
+}
+
+// currentUser returns the identity recorded in audit entries
+func currentUser() string {
+	if name := os.Getenv("PLATOSL_AUDIT_USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
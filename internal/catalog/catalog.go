@@ -0,0 +1,154 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Dataset holds the catalog metadata of a single definition
+type Dataset struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Fields      []Field  `json:"fields"`
+}
+
+// Field holds the catalog metadata of a single field
+type Field struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Optional    bool     `json:"optional"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// Extract collects catalog metadata from all definitions in a CUE value.
+//
+// Metadata is read from doc comments and attributes:
+//
+//	@title / @description title and description (else the doc comment)
+//	@owner("team")        owning team of a definition or field
+//	@pii() / @pii(email)  marks personal data (tag PII or PII.<kind>)
+//	@tag(a, b)            free-form tags
+//	@unit / @currency     appended to the field description
+func Extract(val cue.Value) ([]Dataset, error) {
+	var datasets []Dataset
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate definitions: %w", err)
+	}
+
+	for iter.Next() {
+		label := iter.Selector().String()
+		if !strings.HasPrefix(label, "#") {
+			continue
+		}
+		def := iter.Value()
+
+		dataset := Dataset{
+			Name:        strings.TrimPrefix(label, "#"),
+			Title:       platoCue.Title(def),
+			Description: platoCue.Description(def),
+			Owner:       owner(def),
+			Tags:        tags(def),
+		}
+
+		fields, err := def.Fields(cue.Optional(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate fields of %s: %w", label, err)
+		}
+
+		for fields.Next() {
+			fieldVal := fields.Value()
+			dataset.Fields = append(dataset.Fields, Field{
+				Name:        strings.TrimRight(fields.Selector().String(), "?!"),
+				Type:        fieldType(fieldVal),
+				Optional:    fields.IsOptional(),
+				Title:       platoCue.Title(fieldVal),
+				Description: fieldDescription(fieldVal),
+				Owner:       owner(fieldVal),
+				Tags:        tags(fieldVal),
+			})
+		}
+
+		datasets = append(datasets, dataset)
+	}
+
+	sort.Slice(datasets, func(i, j int) bool {
+		return datasets[i].Name < datasets[j].Name
+	})
+
+	return datasets, nil
+}
+
+// fieldDescription returns the description of a field, followed by its
+// @unit / @currency annotation
+func fieldDescription(val cue.Value) string {
+	desc := platoCue.Description(val)
+	measure := platoCue.MeasureOf(val)
+	if measure.IsZero() {
+		return desc
+	}
+	if desc == "" {
+		return "(" + measure.String() + ")"
+	}
+	return desc + " (" + measure.String() + ")"
+}
+
+// owner reads the @owner attribute
+func owner(val cue.Value) string {
+	if attr, ok := platoCue.GetAttr(val, "owner"); ok {
+		return attr.Arg(0)
+	}
+	return ""
+}
+
+// tags reads the @pii and @tag attributes
+func tags(val cue.Value) []string {
+	var result []string
+
+	if attr, ok := platoCue.GetAttr(val, "pii"); ok {
+		if kind := attr.Arg(0); kind != "" {
+			result = append(result, "PII."+kind)
+		} else {
+			result = append(result, "PII")
+		}
+	}
+
+	if attr, ok := platoCue.GetAttr(val, "tag"); ok {
+		result = append(result, attr.Args...)
+	}
+
+	return result
+}
+
+// fieldType returns a coarse type name for a field
+func fieldType(val cue.Value) string {
+	kind := val.IncompleteKind()
+
+	switch {
+	case kind&cue.StringKind != 0:
+		return "string"
+	case kind&cue.IntKind != 0:
+		return "int"
+	case kind&cue.FloatKind != 0, kind&cue.NumberKind != 0:
+		return "number"
+	case kind&cue.BoolKind != 0:
+		return "bool"
+	case kind&cue.ListKind != 0:
+		return "list"
+	case kind&cue.StructKind != 0:
+		return "struct"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,229 @@
+package catalog
+
+import (
+	"fmt"
+)
+
+// Payload is a single catalog API request body together with its endpoint
+type Payload struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Body   interface{} `json:"body"`
+}
+
+// Options configures how datasets are mapped onto a catalog
+type Options struct {
+	// Service is the catalog service / platform name
+	Service string
+
+	// Namespace qualifies dataset names (e.g. the project name)
+	Namespace string
+}
+
+// Build converts datasets into catalog payloads in the given format
+func Build(format string, datasets []Dataset, opts Options) ([]Payload, error) {
+	switch format {
+	case "openmetadata":
+		return buildOpenMetadata(datasets, opts), nil
+	case "datahub":
+		return buildDataHub(datasets, opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported catalog format: %s (use openmetadata or datahub)", format)
+	}
+}
+
+// buildOpenMetadata emits CreateTable requests for the OpenMetadata API
+func buildOpenMetadata(datasets []Dataset, opts Options) []Payload {
+	var payloads []Payload
+
+	for _, ds := range datasets {
+		var columns []map[string]interface{}
+		for _, f := range ds.Fields {
+			column := map[string]interface{}{
+				"name":       f.Name,
+				"dataType":   openMetadataType(f.Type),
+				"constraint": "NOT_NULL",
+			}
+			if f.Optional {
+				column["constraint"] = "NULL"
+			}
+			if f.Title != "" {
+				column["displayName"] = f.Title
+			}
+			if f.Description != "" {
+				column["description"] = f.Description
+			}
+			if len(f.Tags) > 0 {
+				column["tags"] = openMetadataTags(f.Tags)
+			}
+			columns = append(columns, column)
+		}
+
+		table := map[string]interface{}{
+			"name":           ds.Name,
+			"databaseSchema": fmt.Sprintf("%s.%s", opts.Service, opts.Namespace),
+			"columns":        columns,
+		}
+		if ds.Title != "" {
+			table["displayName"] = ds.Title
+		}
+		if ds.Description != "" {
+			table["description"] = ds.Description
+		}
+		if ds.Owner != "" {
+			table["owners"] = []map[string]string{{"type": "team", "name": ds.Owner}}
+		}
+		if len(ds.Tags) > 0 {
+			table["tags"] = openMetadataTags(ds.Tags)
+		}
+
+		payloads = append(payloads, Payload{
+			Method: "PUT",
+			Path:   "/api/v1/tables",
+			Body:   table,
+		})
+	}
+
+	return payloads
+}
+
+// openMetadataType maps a field type to an OpenMetadata column data type
+func openMetadataType(typ string) string {
+	switch typ {
+	case "string":
+		return "STRING"
+	case "int":
+		return "INT"
+	case "number":
+		return "DOUBLE"
+	case "bool":
+		return "BOOLEAN"
+	case "list":
+		return "ARRAY"
+	case "struct":
+		return "STRUCT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// openMetadataTags converts tags to OpenMetadata tag labels
+func openMetadataTags(tags []string) []map[string]string {
+	var labels []map[string]string
+	for _, tag := range tags {
+		labels = append(labels, map[string]string{
+			"tagFQN": tag,
+			"source": "Classification",
+		})
+	}
+	return labels
+}
+
+// buildDataHub emits metadata change proposals for the DataHub API
+func buildDataHub(datasets []Dataset, opts Options) []Payload {
+	var payloads []Payload
+	platform := fmt.Sprintf("urn:li:dataPlatform:%s", opts.Service)
+
+	for _, ds := range datasets {
+		urn := fmt.Sprintf("urn:li:dataset:(%s,%s.%s,PROD)", platform, opts.Namespace, ds.Name)
+
+		var fields []map[string]interface{}
+		for _, f := range ds.Fields {
+			field := map[string]interface{}{
+				"fieldPath":      f.Name,
+				"nativeDataType": f.Type,
+				"nullable":       f.Optional,
+				"type": map[string]interface{}{
+					"type": map[string]interface{}{dataHubType(f.Type): map[string]interface{}{}},
+				},
+			}
+			if f.Title != "" {
+				field["label"] = f.Title
+			}
+			if f.Description != "" {
+				field["description"] = f.Description
+			}
+			if len(f.Tags) > 0 {
+				field["globalTags"] = dataHubTags(f.Tags)
+			}
+			fields = append(fields, field)
+		}
+
+		payloads = append(payloads, dataHubProposal(urn, "schemaMetadata", map[string]interface{}{
+			"schemaName": ds.Name,
+			"platform":   platform,
+			"version":    0,
+			"hash":       "",
+			"platformSchema": map[string]interface{}{
+				"com.linkedin.schema.OtherSchema": map[string]string{"rawSchema": ""},
+			},
+			"fields": fields,
+		}))
+
+		if ds.Description != "" {
+			payloads = append(payloads, dataHubProposal(urn, "datasetProperties", map[string]interface{}{
+				"name":        ds.Name,
+				"description": ds.Description,
+			}))
+		}
+
+		if ds.Owner != "" {
+			payloads = append(payloads, dataHubProposal(urn, "ownership", map[string]interface{}{
+				"owners": []map[string]string{{
+					"owner": fmt.Sprintf("urn:li:corpGroup:%s", ds.Owner),
+					"type":  "TECHNICAL_OWNER",
+				}},
+			}))
+		}
+
+		if len(ds.Tags) > 0 {
+			payloads = append(payloads, dataHubProposal(urn, "globalTags", dataHubTags(ds.Tags)))
+		}
+	}
+
+	return payloads
+}
+
+// dataHubProposal wraps an aspect in an ingestProposal request
+func dataHubProposal(urn, aspectName string, aspect interface{}) Payload {
+	return Payload{
+		Method: "POST",
+		Path:   "/aspects?action=ingestProposal",
+		Body: map[string]interface{}{
+			"proposal": map[string]interface{}{
+				"entityType": "dataset",
+				"entityUrn":  urn,
+				"changeType": "UPSERT",
+				"aspectName": aspectName,
+				"aspect":     aspect,
+			},
+		},
+	}
+}
+
+// dataHubType maps a field type to a DataHub schema field type
+func dataHubType(typ string) string {
+	switch typ {
+	case "string":
+		return "com.linkedin.schema.StringType"
+	case "int", "number":
+		return "com.linkedin.schema.NumberType"
+	case "bool":
+		return "com.linkedin.schema.BooleanType"
+	case "list":
+		return "com.linkedin.schema.ArrayType"
+	case "struct":
+		return "com.linkedin.schema.RecordType"
+	default:
+		return "com.linkedin.schema.NullType"
+	}
+}
+
+// dataHubTags converts tags to a DataHub globalTags aspect
+func dataHubTags(tags []string) map[string]interface{} {
+	var refs []map[string]string
+	for _, tag := range tags {
+		refs = append(refs, map[string]string{"tag": "urn:li:tag:" + tag})
+	}
+	return map[string]interface{}{"tags": refs}
+}
@@ -0,0 +1,20 @@
+package catalog
+
+import (
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// Push sends payloads to the catalog API at baseURL
+func Push(client *httpclient.Client, baseURL string, payloads []Payload) error {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	for _, p := range payloads {
+		if err := client.DoJSON(p.Method, baseURL+p.Path, p.Body, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/lint"
+	"github.com/platoorg/plato-sl-cli/internal/severity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adviseFormat    string
+	adviseMinScore  int
+	adviseMaxFields int
+	adviseMaxDepth  int
+	adviseExamples  int
+)
+
+var adviseCmd = &cobra.Command{
+	Use:   "advise",
+	Short: "Score schema quality and suggest improvements",
+	Long: `Score the schemas from 0 to 100 and list what to improve first.
+
+The score weighs five categories, each scoring the share of definitions or
+fields that follow the lint rules:
+
+  documentation       30  doc comments on definitions and fields
+  constraints         25  bounds, lengths, patterns or enums on strings and numbers
+  naming              15  PascalCase definitions, one field naming style
+  unused definitions  15  enum and scalar definitions that are used
+  definition size     15  definitions within --max-fields and --max-depth
+
+Suggestions are ordered by the points they add to the score, with examples
+and a link to the lint rule explaining it.
+
+Use --min-score in CI to fail when the score drops below a threshold.
+
+Findings that validation.severity in platosl.yaml ignores, such as
+"ignore PSL2003 in schemas/legacy/**", are left out of the score.
+
+Examples:
+  platosl advise
+  platosl advise --format json
+  platosl advise --min-score 70`,
+	Args: cobra.NoArgs,
+	RunE: runAdvise,
+}
+
+func init() {
+	rootCmd.AddCommand(adviseCmd)
+	adviseCmd.Flags().StringVar(&adviseFormat, "format", "text", "output format (text, json)")
+	adviseCmd.Flags().IntVar(&adviseMinScore, "min-score", 0, "fail when the score is below this")
+	adviseCmd.Flags().IntVar(&adviseMaxFields, "max-fields", lint.DefaultOptions.MaxFields, "fields, nested included, above which a definition is oversized")
+	adviseCmd.Flags().IntVar(&adviseMaxDepth, "max-depth", lint.DefaultOptions.MaxDepth, "struct nesting depth above which a definition is oversized")
+	adviseCmd.Flags().IntVar(&adviseExamples, "examples", 3, "examples shown per suggestion")
+}
+
+func runAdvise(cmd *cobra.Command, args []string) error {
+	switch adviseFormat {
+	case "text", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected text or json)", adviseFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	val, err := loadAndValidateSchemas(cfg, "advise")
+	if err != nil {
+		return err
+	}
+
+	policy, err := severityPolicy(cfg)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	result, err := lint.Run(val, lint.Options{MaxFields: adviseMaxFields, MaxDepth: adviseMaxDepth})
+	if err != nil {
+		PrintError("Failed to lint schemas: %v", err)
+		return err
+	}
+	// Grandfathered findings count neither against the score nor as checked
+	result.Drop(func(f lint.Finding) bool {
+		return policy.Level(findingNames(f.Code), f.File, severity.Warn) == severity.Ignore
+	})
+	report := lint.Advise(result)
+
+	if adviseFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if err := printAdvice(report); err != nil {
+		return err
+	}
+
+	if report.Score < adviseMinScore {
+		err := fmt.Errorf("schema quality score %d is below the minimum of %d", report.Score, adviseMinScore)
+		PrintError("%v", err)
+		return err
+	}
+	return nil
+}
+
+// printAdvice prints the score by category and the suggestions
+func printAdvice(report *lint.Report) error {
+	PrintInfo("Schema quality: %d/100\n", report.Score)
+
+	t := newTable("CATEGORY", "WEIGHT", "SCORE", "FINDINGS")
+	for _, c := range report.Categories {
+		t.AddRow(c.Name, fmt.Sprint(c.Weight), fmt.Sprint(c.Score), fmt.Sprintf("%d/%d", c.Findings, c.Checked))
+	}
+	if err := t.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	if len(report.Suggestions) == 0 {
+		fmt.Println()
+		PrintSuccess("Nothing to improve")
+		return nil
+	}
+
+	PrintInfo("\nSuggestions:")
+	for i, s := range report.Suggestions {
+		PrintInfo("\n%d. %s (+%.1f points)  %s %s", i+1, s.Action, s.Points, s.Rule.Code, s.Rule.Name)
+		PrintInfo("   %s", s.Rule.Fix)
+		for j, f := range s.Findings {
+			if j == adviseExamples {
+				PrintInfo("     … and %d more", len(s.Findings)-j)
+				break
+			}
+			PrintInfo("     %s  %s", f.Location(), f.Message)
+			if f.Suggestion != "" {
+				PrintInfo("       → %s", f.Suggestion)
+			}
+		}
+		PrintInfo("   %s", s.URL)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/attest"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// writeAttestation writes the provenance of a build's artifacts to path: an
+// in-toto statement, or with a signing key (a file, or the PEM key in
+// PLATOSL_ATTEST_KEY) a DSSE envelope holding the signed statement
+func writeAttestation(cfg *config.Config, path, keyFile string, started time.Time) error {
+	var generators, artifacts []string
+	for name, genCfg := range cfg.Generate {
+		if genCfg.Enabled {
+			generators = append(generators, name)
+		}
+	}
+	sort.Strings(generators)
+	for _, name := range generators {
+		files, err := artifactFiles(name, cfg, cfg.Generate[name])
+		if err != nil {
+			PrintError("Attestation: %v", err)
+			return err
+		}
+		for _, file := range files {
+			// A previous attestation in an output directory is no artifact
+			if filepath.Clean(file) != filepath.Clean(path) {
+				artifacts = append(artifacts, file)
+			}
+		}
+	}
+
+	statement, err := attest.New(attest.Build{
+		Project:    cfg.Name,
+		ConfigFile: GetConfigFile(),
+		Schemas:    cfg.Schemas,
+		Generators: generators,
+		Artifacts:  artifacts,
+		Tool:       Version,
+		Started:    started,
+		Finished:   time.Now(),
+	})
+	if err != nil {
+		PrintError("Attestation: %v", err)
+		return err
+	}
+
+	var doc interface{} = statement
+	signed := false
+	if keyFile != "" || os.Getenv("PLATOSL_ATTEST_KEY") != "" {
+		key, err := attestKey(keyFile)
+		if err != nil {
+			PrintError("Attestation: %v", err)
+			return err
+		}
+		if doc, err = attest.Sign(statement, key); err != nil {
+			PrintError("Attestation: %v", err)
+			return err
+		}
+		signed = true
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write attestation: %w", err)
+	}
+
+	if signed {
+		PrintSuccess("attestation (signed): %s", path)
+	} else {
+		PrintSuccess("attestation: %s", path)
+	}
+	return nil
+}
+
+// attestKey loads the signing key from a file, or from PLATOSL_ATTEST_KEY
+func attestKey(keyFile string) (crypto.Signer, error) {
+	data := []byte(os.Getenv("PLATOSL_ATTEST_KEY"))
+	if keyFile != "" {
+		var err error
+		if data, err = os.ReadFile(keyFile); err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+	}
+	return attest.LoadKey(data)
+}
+
+// artifactFiles returns the files a generator wrote: its outputs and their
+// copies, every file of a multi-file generator's directory, and its package
+// manifest
+func artifactFiles(name string, cfg *config.Config, genCfg config.GenConfig) ([]string, error) {
+	outputs := append([]string{genCfg.Output}, genCfg.ExtraOutputs()...)
+
+	var files []string
+	for _, output := range outputs {
+		info, err := os.Stat(output)
+		if err != nil {
+			return nil, fmt.Errorf("output of %s not found: %s", name, output)
+		}
+		if !info.IsDir() {
+			files = append(files, output)
+			continue
+		}
+		err = filepath.Walk(output, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				files = append(files, path)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk output directory %s: %w", output, err)
+		}
+	}
+
+	pkg, err := packageFor(name, cfg, genCfg)
+	if err != nil {
+		return nil, err
+	}
+	if pkg != nil {
+		files = append(files, pkg.Path)
+	}
+	return files, nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/platoorg/plato-sl-cli/internal/audit"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// recordAudit appends an audit record for a completed operation when the
+// audit log is enabled in platosl.yaml. Failing to record locally is an
+// error, since audited artifacts must not be produced without a trail;
+// failing to reach the audit URL is only a warning unless --strict-network
+// is set, as the record is kept in the local log. The author in the user
+// config is recorded unless PLATOSL_AUDIT_USER is set.
+func recordAudit(cfg *config.Config, operation string, targets []audit.Target) error {
+	if !cfg.Audit.Enabled {
+		return nil
+	}
+
+	rec := audit.NewRecord(operation, cfg.Name, Version)
+	if os.Getenv("PLATOSL_AUDIT_USER") == "" && userCfg.Author.String() != "" {
+		rec.User = userCfg.Author.String()
+	}
+	rec.Targets = targets
+
+	schemaHash, err := audit.SchemaHash(cfg.Schemas)
+	if err != nil {
+		PrintError("Audit log: %v", err)
+		return err
+	}
+	rec.SchemaHash = schemaHash
+
+	if err := audit.Append(cfg.Audit.Path, rec); err != nil {
+		PrintError("Audit log: %v", err)
+		return err
+	}
+
+	if cfg.Audit.URL != "" {
+		client, err := newHTTPClient(cfg, "")
+		if err != nil {
+			PrintError("Audit log: %v", err)
+			return err
+		}
+		if err := audit.Send(client, cfg.Audit.URL, rec); err != nil {
+			if err := degraded("Audit endpoint", err); err != nil {
+				return err
+			}
+		}
+	}
+
+	PrintVerbose("Recorded %s in audit log (%d target(s))", operation, len(targets))
+	return nil
+}
+
+// outputTarget describes a generated file for the audit log
+func outputTarget(name, output string, data []byte) audit.Target {
+	return audit.Target{
+		Name:   name,
+		Output: output,
+		Hash:   audit.Hash(data),
+	}
+}
+
+// auditOperation names an audited operation for a single generator
+func auditOperation(name string) string {
+	return fmt.Sprintf("gen %s", name)
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/errors"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
+)
+
+// budgetBreakdownLimit is the number of definitions listed when a target
+// is over its size budget
+const budgetBreakdownLimit = 10
+
+// sizeBudget is the maxBytes and maxGzipBytes options of a target; zero
+// means no limit
+type sizeBudget struct {
+	maxBytes     int64
+	maxGzipBytes int64
+}
+
+// sizeBudgetOf reads the size budget of a target from its options: a
+// number of bytes or a size such as 50K or 1.5MiB
+func sizeBudgetOf(ctx *generator.Context) (sizeBudget, error) {
+	var b sizeBudget
+	for _, opt := range []struct {
+		key   string
+		limit *int64
+	}{{"maxBytes", &b.maxBytes}, {"maxGzipBytes", &b.maxGzipBytes}} {
+		raw, ok := ctx.GetOption(opt.key)
+		if !ok {
+			continue
+		}
+		var err error
+		switch v := raw.(type) {
+		case int:
+			*opt.limit = int64(v)
+		case int64:
+			*opt.limit = v
+		case float64:
+			*opt.limit = int64(v)
+		case string:
+			*opt.limit, err = workers.ParseSize(v)
+		default:
+			err = fmt.Errorf("invalid size %v", raw)
+		}
+		if err != nil || *opt.limit < 0 {
+			return b, fmt.Errorf("invalid %s option: %v", opt.key, raw)
+		}
+	}
+	return b, nil
+}
+
+// checkSizeBudget fails when the output of a target is larger than its
+// budget, listing the definitions taking most of the output. Files of
+// multi-file generators are gzipped one by one, as they are served.
+func checkSizeBudget(name string, ctx *generator.Context, output []byte, files []generator.File) *errors.Error {
+	budget, err := sizeBudgetOf(ctx)
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeConfig, err, fmt.Sprintf("invalid size budget of %s", name))
+		return e.WithSuggestion("Set maxBytes and maxGzipBytes to a number of bytes or a size such as 50K or 1.5MiB")
+	}
+	if budget.maxBytes == 0 && budget.maxGzipBytes == 0 {
+		return nil
+	}
+
+	size, gzipped := int64(len(output)), int64(0)
+	if files != nil {
+		size = 0
+		for _, f := range files {
+			size += int64(len(f.Content))
+			gzipped += gzipSize(f.Content)
+		}
+	} else {
+		gzipped = gzipSize(output)
+	}
+
+	var over []string
+	if budget.maxBytes > 0 && size > budget.maxBytes {
+		over = append(over, fmt.Sprintf("%d B, over the maxBytes budget of %d B by %d B", size, budget.maxBytes, size-budget.maxBytes))
+	}
+	if budget.maxGzipBytes > 0 && gzipped > budget.maxGzipBytes {
+		over = append(over, fmt.Sprintf("%d B gzipped, over the maxGzipBytes budget of %d B by %d B", gzipped, budget.maxGzipBytes, gzipped-budget.maxGzipBytes))
+	}
+	if len(over) == 0 {
+		PrintVerbose("Output is %d B (%d B gzipped), within its budget", size, gzipped)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "output is %s", strings.Join(over, "; "))
+	if sizes := definitionSizes(ctx.Value, output, files); len(sizes) > 0 {
+		b.WriteString("\n\nLargest definitions:\n")
+		t := newTable("DEFINITION", "BYTES", "SHARE")
+		for i, s := range sizes {
+			if i == budgetBreakdownLimit {
+				t.AddRow(fmt.Sprintf("(%d more)", len(sizes)-i), "", "")
+				break
+			}
+			t.AddRow(s.name, fmt.Sprint(s.bytes), fmt.Sprintf("%d%%", s.bytes*100/size))
+		}
+		t.Render(&b)
+	}
+	e := errors.Wrap(errors.ErrorTypeGeneration, fmt.Errorf("%s", strings.TrimRight(b.String(), "\n")), fmt.Sprintf("%s is over its size budget", name))
+	return e.WithSuggestion("Split or trim the largest definitions, or raise maxBytes / maxGzipBytes in platosl.yaml")
+}
+
+// definitionSize is the part of an output generated for a definition
+type definitionSize struct {
+	name  string
+	bytes int64
+}
+
+// definitionSizes attributes the output of a target to definitions, largest
+// first. Files of multi-file generators belong to the definition they are
+// named after. In single outputs, a line at the top level naming a
+// definition starts its part, preferring the longest name, so OrderLine
+// goes to #OrderLine rather than #Order. Indented lines and closing lines
+// continue the part before them, and comments and annotations go with the
+// line they precede. Top-level lines after a blank line naming no
+// definition, such as imports and helpers, and comments followed by a blank
+// line, such as the generated header, are shared.
+func definitionSizes(val cue.Value, output []byte, files []generator.File) []definitionSize {
+	var names []string
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			names = append(names, iter.Selector().String())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	owner := func(text string) string {
+		text = normalizeName(text)
+		for _, name := range names {
+			if strings.Contains(text, normalizeName(name)) {
+				return name
+			}
+		}
+		return sharedPart
+	}
+
+	sizes := make(map[string]int64)
+	if files != nil {
+		for _, f := range files {
+			sizes[owner(strings.TrimSuffix(path.Base(f.Path), path.Ext(f.Path)))] += int64(len(f.Content))
+		}
+	} else {
+		last, pending, blank, comment := sharedPart, int64(0), true, false
+		for _, line := range bytes.SplitAfter(output, []byte("\n")) {
+			n := int64(len(line))
+			text := strings.TrimSpace(string(line))
+			switch {
+			case text == "" && comment:
+				// A comment set apart, such as the generated header
+				sizes[sharedPart] += pending
+				pending, comment = 0, false
+				fallthrough
+			case text == "":
+				pending += n
+				blank = true
+				continue
+			case isComment(text) && blank:
+				pending += n
+				comment = true
+				continue
+			case isComment(text) || line[0] == ' ' || line[0] == '\t':
+			default:
+				if part := owner(text); part != sharedPart || blank {
+					last = part
+				}
+			}
+			sizes[last] += pending + n
+			pending, blank, comment = 0, false, false
+		}
+		sizes[last] += pending
+	}
+
+	var result []definitionSize
+	for name, n := range sizes {
+		if n > 0 {
+			result = append(result, definitionSize{name: name, bytes: n})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].bytes != result[j].bytes {
+			return result[i].bytes > result[j].bytes
+		}
+		return result[i].name < result[j].name
+	})
+	return result
+}
+
+// sharedPart is the part of an output generated for no definition in
+// particular
+const sharedPart = "(shared)"
+
+// isComment reports whether a line is a comment, in the syntax of any of
+// the target languages, or an annotation such as @dataclass
+func isComment(line string) bool {
+	for _, prefix := range []string{"//", "/*", "*", "#", "--", ";", "\"\"\"", "{-", "(*", "<!--", "@", "["} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeName lowercases letters and digits and drops everything else,
+// so #order_line matches OrderLine
+func normalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// gzipSize is the size of data gzipped at the best compression, as served
+// by web servers and CDNs
+func gzipSize(data []byte) int64 {
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	w.Write(data)
+	w.Close()
+	return int64(buf.Len())
+}
@@ -1,23 +1,63 @@
 package cli
 
 import (
-	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildRecursive    bool
+	buildAttest       bool
+	buildAttestOutput string
+	buildAttestKey    string
 )
 
 var buildCmd = &cobra.Command{
-	Use:   "build",
-	Short: "Validate schemas and generate all enabled targets",
+	Use:     "build",
+	Aliases: []string{"b"},
+	Short:   "Validate schemas and generate all enabled targets",
 	Long: `Build validates all CUE schemas and generates code for all enabled targets
 configured in platosl.yaml.
 
 This is equivalent to running 'platosl validate' followed by generating all
-enabled generators.`,
+enabled generators.
+
+Subdirectories with their own platosl.yaml are sub-projects, built after
+the root project. A sub-project can use the definitions of another with
+dependsOn, and is built after the projects it depends on:
+
+  # services/orders/platosl.yaml
+  name: orders
+  schemas: [schemas/]
+  dependsOn: [../../shared]
+
+Only the schema paths listed in publish (default: all schema paths) of a
+dependency are visible. A root without schemas of its own only builds its
+sub-projects. Use --recursive=false to build the root project alone.
+
+--attest writes an SLSA provenance attestation of the generated artifacts
+(an in-toto statement with their SHA-256 digests, the hashes of the config
+and schema files, the source commit, tool version and CI environment) for
+release pipelines to upload with the packages. With --attest-key (or a PEM
+key in PLATOSL_ATTEST_KEY) the statement is signed into a DSSE envelope.
+
+Examples:
+  platosl build --attest
+  platosl build --attest --attest-key release.key --attest-output dist/provenance.json`,
 	RunE: runBuild,
 }
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
+	buildCmd.Flags().BoolVar(&buildRecursive, "recursive", true, "also build sub-projects in subdirectories")
+	buildCmd.Flags().BoolVar(&buildAttest, "attest", false, "write a provenance attestation of the generated artifacts")
+	buildCmd.Flags().StringVar(&buildAttestOutput, "attest-output", "generated/provenance.json", "attestation file path")
+	buildCmd.Flags().StringVar(&buildAttestKey, "attest-key", "", "PEM private key signing the attestation (Ed25519, ECDSA or RSA)")
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
@@ -27,8 +67,47 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	PrintInfo("Building project: %s", cfg.Name)
+	var projects []*project.Project
+	if buildRecursive {
+		found, err := project.Discover(filepath.Dir(GetConfigFile()))
+		if err != nil {
+			PrintError("Failed to discover sub-projects: %v", err)
+			return err
+		}
+		if projects, err = project.Order(found); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	}
+
+	if len(projects) == 0 || hasSchemas(cfg) {
+		if err := buildProject(cmd, cfg, cfg.Name); err != nil {
+			return err
+		}
+	} else {
+		PrintVerbose("Skipping root project %s: no schemas of its own", cfg.Name)
+	}
+
+	for _, p := range projects {
+		PrintInfo("")
+		if err := buildSubProject(cmd, p); err != nil {
+			return err
+		}
+	}
+
+	if len(projects) > 0 {
+		PrintInfo("")
+		PrintSuccess("Built %d sub-project(s)", len(projects))
+	}
+	return nil
+}
+
+// buildProject validates the schemas of the project in the working
+// directory and generates all enabled targets
+func buildProject(cmd *cobra.Command, cfg *config.Config, label string) error {
+	PrintInfo("Building project: %s", label)
 	PrintInfo("")
+	started := time.Now()
 
 	// Step 1: Validate
 	PrintInfo("Step 1: Validating schemas...")
@@ -42,8 +121,53 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	if err := runGenAll(cfg); err != nil {
 		return err
 	}
+	if buildAttest || buildAttestKey != "" {
+		if err := writeAttestation(cfg, buildAttestOutput, buildAttestKey, started); err != nil {
+			return err
+		}
+	}
 
 	PrintInfo("")
 	PrintSuccess("Build complete")
 	return nil
 }
+
+// buildSubProject builds a sub-project from its own directory, with its own
+// config, like running platosl build there
+func buildSubProject(cmd *cobra.Command, p *project.Project) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(wd, p.Dir)
+	if err != nil {
+		rel = p.Dir
+	}
+
+	savedConfig, savedStrict := cfgFile, validateStrict
+	if err := os.Chdir(p.Dir); err != nil {
+		PrintError("Failed to enter %s: %v", rel, err)
+		return err
+	}
+	cfgFile = project.ConfigFile
+	defer func() {
+		os.Chdir(wd)
+		cfgFile, validateStrict = savedConfig, savedStrict
+	}()
+
+	if err := buildProject(cmd, p.Config, p.Name()+" ("+rel+")"); err != nil {
+		PrintError("Sub-project %s failed", rel)
+		return err
+	}
+	return nil
+}
+
+// hasSchemas reports whether any schema path of a project exists
+func hasSchemas(cfg *config.Config) bool {
+	for _, path := range cfg.Schemas {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
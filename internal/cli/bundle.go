@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/webbundle"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOutput      string
+	bundleDefinitions []string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Compile schemas to a compact bundle for browser-side validation",
+	Long: `Compile the schemas to compact JSON with a small TypeScript validator, for
+client-side form validation without shipping CUE or a JSON Schema engine.
+
+The output directory contains:
+  schemas/<Name>.json  one compact schema per definition
+  bundle.json          all schemas in one file
+  runtime.ts           the validator
+  index.ts             load(name) with one dynamic import per definition, so
+                       bundlers split each schema into its own chunk
+
+Use --definitions to include only some definitions (and the ones they
+reference). Constraints the runtime cannot check (e.g. most builtin
+functions) are listed; keep validating on the server.
+
+Examples:
+  platosl bundle
+  platosl bundle -o web/src/schemas --definitions '#ContactForm,#Signup'`,
+	Args: cobra.NoArgs,
+	RunE: runBundle,
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", "generated/web", "output directory")
+	bundleCmd.Flags().StringSliceVar(&bundleDefinitions, "definitions", nil, "definitions to include (default: all)")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	val, err := loadAndValidateSchemas(cfg, "bundle")
+	if err != nil {
+		return err
+	}
+
+	bundle, err := webbundle.Build(val, bundleDefinitions)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(bundle.Schemas) == 0 {
+		PrintError("No definitions to bundle")
+		return fmt.Errorf("no definitions found")
+	}
+
+	files, err := bundle.Write(bundleOutput)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	for _, f := range files {
+		rel, err := filepath.Rel(bundleOutput, f.Path)
+		if err != nil {
+			rel = f.Path
+		}
+		PrintVerbose("%-30s %6d B  %6d B gzip", rel, f.Size, f.GzipSize)
+	}
+	for _, note := range bundle.Unsupported {
+		PrintInfo("  Not checked in the browser: %s", note)
+	}
+
+	PrintSuccess("Bundled %d definition(s) to %s", len(bundle.Schemas), bundleOutput)
+	return nil
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheFormat    string
+	cacheUnusedFor time.Duration
+	cacheDryRun    bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clean the import cache",
+	Long: `Inspect and clean the cache of modules imported by schemas.
+
+Modules imported from the CUE registry (CUE_REGISTRY) are downloaded once
+and kept in the import cache. Its backend is set in the user config:
+
+  cache:
+    backend: sqlite   # fs (default) or sqlite
+    dir: /var/cache/platosl
+
+fs keeps a module.cue and module.zip file per module version under
+<dir>/modules. sqlite keeps them in a single database, <dir>/modules.db,
+which a long-running serve or watch process can share safely with other
+invocations, and lists entries without reading files. With either backend
+modules are extracted under <dir>/extract for loading. dir defaults to the
+cache directory shown by 'platosl config'.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached modules",
+	Long: `List the cached module versions with their size and when they were last
+used.
+
+Examples:
+  platosl cache list
+  platosl cache list --format json`,
+	Args: cobra.NoArgs,
+	RunE: runCacheList,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove cached modules",
+	Long: `Remove cached module versions, all of them or those not used for a while.
+They are downloaded again when next imported.
+
+Examples:
+  platosl cache clean
+  platosl cache clean --unused-for 720h
+  platosl cache clean --unused-for 168h --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheListCmd.Flags().StringVar(&cacheFormat, "format", "table", "output format (table, json)")
+
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCleanCmd.Flags().DurationVar(&cacheUnusedFor, "unused-for", 0, "only remove modules not used for this long, e.g. 720h")
+	cacheCleanCmd.Flags().BoolVar(&cacheDryRun, "dry-run", false, "list what would be removed without removing it")
+}
+
+// cachedModule is a module version in the cache, with its entries
+type cachedModule struct {
+	Module string    `json:"module"`
+	Size   int64     `json:"size"`
+	Used   time.Time `json:"used"`
+}
+
+// cachedModules groups the cache entries by module version
+func cachedModules() ([]*cachedModule, error) {
+	if importCache == nil {
+		_, err := config.ImportCacheDir(userCfg.Cache)
+		return nil, err
+	}
+	store, err := importCache.Store()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the import cache: %w", err)
+	}
+
+	byModule := make(map[string]*cachedModule)
+	var modules []*cachedModule
+	for _, e := range entries {
+		name := e.Key
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[:i]
+		}
+		m, ok := byModule[name]
+		if !ok {
+			m = &cachedModule{Module: name}
+			byModule[name] = m
+			modules = append(modules, m)
+		}
+		m.Size += e.Size
+		if e.Used.After(m.Used) {
+			m.Used = e.Used
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Module < modules[j].Module })
+	return modules, nil
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	switch cacheFormat {
+	case "table", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected table or json)", cacheFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	modules, err := cachedModules()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if cacheFormat == "json" {
+		if modules == nil {
+			modules = []*cachedModule{}
+		}
+		data, err := json.MarshalIndent(modules, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(modules) == 0 {
+		PrintInfo("The import cache is empty")
+		return nil
+	}
+	t := newTable("MODULE", "SIZE", "LAST USED")
+	var total int64
+	for _, m := range modules {
+		t.AddRow(m.Module, byteSize(m.Size), m.Used.Local().Format("2006-01-02 15:04"))
+		total += m.Size
+	}
+	if err := t.Render(os.Stdout); err != nil {
+		return err
+	}
+	PrintInfo("\n%d module(s), %s", len(modules), byteSize(total))
+	return nil
+}
+
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	modules, err := cachedModules()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	cutoff := time.Now().Add(-cacheUnusedFor)
+	removed := 0
+	var freed int64
+	for _, m := range modules {
+		if cacheUnusedFor > 0 && m.Used.After(cutoff) {
+			continue
+		}
+		if cacheDryRun {
+			PrintInfo("Would remove %s (%s)", m.Module, byteSize(m.Size))
+		} else {
+			if err := importCache.Remove(m.Module); err != nil {
+				PrintError("Failed to remove %s: %v", m.Module, err)
+				return err
+			}
+			PrintVerbose("Removed %s", m.Module)
+		}
+		removed++
+		freed += m.Size
+	}
+
+	if cacheDryRun {
+		PrintInfo("%d module(s) would be removed, freeing %s", removed, byteSize(freed))
+		return nil
+	}
+	PrintSuccess("Removed %d module(s), freeing %s", removed, byteSize(freed))
+	return nil
+}
+
+// byteSize formats a size in binary units, e.g. 1.5 MiB
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/platoorg/plato-sl-cli/internal/audit"
+	"github.com/platoorg/plato-sl-cli/internal/catalog"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	catalogFormat  string
+	catalogOutput  string
+	catalogService string
+	catalogPush    string
+	catalogToken   string
+)
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Export field-level metadata for data catalogs",
+	Long: `Export field-level metadata (types, descriptions, PII tags, ownership) as
+an OpenMetadata or DataHub compatible payload, and optionally push it to the
+catalog API.
+
+Metadata is read from doc comments and attributes:
+  @owner("team")         owning team of a definition or field
+  @pii() / @pii(email)   marks personal data (tag PII / PII.email)
+  @tag(a, b)             free-form tags
+
+The API token for --push is read from --token or PLATOSL_CATALOG_TOKEN, then
+from the shared network token sources (PLATOSL_TOKEN_<HOST>, PLATOSL_TOKEN,
+network.tokens in platosl.yaml).
+
+Examples:
+  platosl catalog --format openmetadata -o generated/catalog.json
+  platosl catalog --format datahub --push https://datahub.example.com/api/gms`,
+	Args: cobra.NoArgs,
+	RunE: runCatalog,
+}
+
+func init() {
+	rootCmd.AddCommand(catalogCmd)
+	catalogCmd.Flags().StringVar(&catalogFormat, "format", "openmetadata", "payload format (openmetadata, datahub)")
+	catalogCmd.Flags().StringVarP(&catalogOutput, "output", "o", "", "output file path (default stdout)")
+	catalogCmd.Flags().StringVar(&catalogService, "service", "platosl", "catalog service / platform name")
+	catalogCmd.Flags().StringVar(&catalogPush, "push", "", "push payloads to the catalog API at this base URL")
+	catalogCmd.Flags().StringVar(&catalogToken, "token", "", "catalog API token")
+}
+
+func runCatalog(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	// Load and validate schemas
+	val, err := loadAndValidateSchemas(cfg, "catalog")
+	if err != nil {
+		return err
+	}
+
+	datasets, err := catalog.Extract(val)
+	if err != nil {
+		PrintError("Failed to extract metadata: %v", err)
+		return err
+	}
+
+	payloads, err := catalog.Build(catalogFormat, datasets, catalog.Options{
+		Service:   catalogService,
+		Namespace: cfg.Name,
+	})
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	data, err := json.MarshalIndent(payloads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+
+	// Push to the catalog API
+	if catalogPush != "" {
+		token := catalogToken
+		if token == "" {
+			token = os.Getenv("PLATOSL_CATALOG_TOKEN")
+		}
+
+		client, err := newHTTPClient(cfg, token)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+
+		PrintVerbose("Pushing %d payload(s) to %s", len(payloads), catalogPush)
+		if err := catalog.Push(client, catalogPush, payloads); err != nil {
+			PrintError("Push failed: %v", err)
+			return err
+		}
+		PrintSuccess("Pushed %d definition(s) to %s", len(datasets), catalogPush)
+		return recordAudit(cfg, "publish catalog", []audit.Target{outputTarget(catalogFormat, catalogPush, data)})
+	}
+
+	if catalogOutput == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(catalogOutput), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(catalogOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	PrintSuccess("Exported %d definition(s) to %s", len(datasets), catalogOutput)
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	composeFormat string
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose <definition>",
+	Short: "Show where each field of a definition comes from",
+	Long: `Show the fully unified definition annotated with the source file (and
+import package, for vendored schemas) of every field and constraint.
+
+This is useful once imports and overlays layer a definition from multiple
+packages and it is no longer obvious which file contributes what.
+
+Examples:
+  platosl compose '#Order'
+  platosl compose Order --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCompose,
+}
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+	composeCmd.Flags().StringVar(&composeFormat, "format", "text", "output format (text, json, yaml)")
+}
+
+func runCompose(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	// Load and validate schemas
+	val, err := loadAndValidateSchemas(cfg, "compose")
+	if err != nil {
+		return err
+	}
+
+	comp, err := platoCue.Compose(val, args[0])
+	if err != nil {
+		PrintError("Failed to compose definition: %v", err)
+		return err
+	}
+
+	// Format output
+	switch composeFormat {
+	case "json":
+		data, err := json.MarshalIndent(comp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+
+	case "yaml":
+		data, err := yaml.Marshal(comp)
+		if err != nil {
+			return fmt.Errorf("failed to format as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+
+	case "text":
+		fallthrough
+	default:
+		fmt.Print(platoCue.FormatComposition(comp))
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/contract"
+	"github.com/platoorg/plato-sl-cli/internal/endpoint"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	contractBaseURL string
+	contractMethods []string
+	contractParams  []string
+	contractHeaders []string
+	contractToken   string
+	contractFormat  string
+	contractSeed    int64
+)
+
+var contractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Check live services against the schemas",
+}
+
+var contractTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Call the @http endpoints of a deployment and validate the responses",
+	Long: `Call the endpoints declared with @http attributes on a live deployment and
+check each response against the schemas: the status code, a JSON content
+type and the body against the response definition. Results are printed as
+TAP (default) or JSON, and the command fails if any endpoint fails, so it
+can gate a release.
+
+Only GET endpoints are called by default. --method adds others; their
+request bodies are random valid instances of the request definition, so
+only include them against environments where test data may be written.
+
+Path parameters need values from --param, e.g. --param id=42 for
+/users/:id. Endpoints without them are skipped.
+
+Requests are authenticated with --token, or the usual token sources for the
+host (platosl login <host>), and --header adds headers.
+
+Examples:
+  platosl contract test --base-url https://staging.example.com/api
+  platosl contract test --base-url http://localhost:8080 --param id=42 --format json
+  platosl contract test --base-url https://staging.example.com --method GET,POST \
+    --header 'X-Tenant: qa'`,
+	Args: cobra.NoArgs,
+	RunE: runContractTest,
+}
+
+func init() {
+	rootCmd.AddCommand(contractCmd)
+	contractCmd.AddCommand(contractTestCmd)
+	contractTestCmd.Flags().StringVar(&contractBaseURL, "base-url", "", "base URL of the deployment (required)")
+	contractTestCmd.Flags().StringSliceVar(&contractMethods, "method", []string{"GET"}, "HTTP methods to test ("+strings.Join(endpoint.Methods, ", ")+")")
+	contractTestCmd.Flags().StringArrayVar(&contractParams, "param", nil, "path parameter value as name=value (repeatable)")
+	contractTestCmd.Flags().StringArrayVar(&contractHeaders, "header", nil, "request header as 'Name: value' (repeatable)")
+	contractTestCmd.Flags().StringVar(&contractToken, "token", "", "bearer token for the requests")
+	contractTestCmd.Flags().StringVar(&contractFormat, "format", "tap", "output format (tap, json)")
+	contractTestCmd.Flags().Int64Var(&contractSeed, "seed", 0, "random seed for generated request bodies")
+	contractTestCmd.MarkFlagRequired("base-url")
+}
+
+func runContractTest(cmd *cobra.Command, args []string) error {
+	if contractFormat != "tap" && contractFormat != "json" {
+		PrintError("Unknown format %q (expected tap or json)", contractFormat)
+		return fmt.Errorf("unknown format: %s", contractFormat)
+	}
+	if u, err := url.Parse(contractBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		PrintError("Invalid --base-url %q (expected an http or https URL)", contractBaseURL)
+		return fmt.Errorf("invalid base URL: %s", contractBaseURL)
+	}
+
+	params, err := parsePairs(contractParams, "=", "--param")
+	if err != nil {
+		return err
+	}
+	headers, err := parsePairs(contractHeaders, ":", "--header")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "contract")
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := endpoint.Extract(schemas)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(endpoints) == 0 {
+		PrintError("No endpoints found; declare them with @http(<method>, \"<path>\") on definitions")
+		return fmt.Errorf("no endpoints")
+	}
+
+	// Responses are what is checked, so failed requests are not retried:
+	// a retry would hide a 5xx, and repeat requests that are not idempotent
+	opts, err := httpClientOptions(cfg, contractToken)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	opts.Retries = 0
+	client, err := httpclient.New(opts)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	results := contract.Run(schemas, endpoints, client, contract.Options{
+		BaseURL: contractBaseURL,
+		Methods: contractMethods,
+		Params:  params,
+		Headers: headers,
+		Seed:    contractSeed,
+	})
+
+	if contractFormat == "json" {
+		err = contract.WriteJSON(os.Stdout, contractBaseURL, results)
+	} else {
+		err = contract.WriteTAP(os.Stdout, contractBaseURL, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	summary := contract.Summarize(contractBaseURL, results)
+	if summary.Failed > 0 {
+		PrintError("%d of %d endpoint(s) failed", summary.Failed, summary.Total)
+		return fmt.Errorf("contract test failed")
+	}
+	if summary.Passed == 0 {
+		PrintError("No endpoints were tested (%d skipped)", summary.Skipped)
+		return fmt.Errorf("no endpoints tested")
+	}
+	return nil
+}
+
+// parsePairs splits name<sep>value flag values
+func parsePairs(values []string, sep, flag string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, sep)
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			PrintError("Invalid %s %q (expected name%svalue)", flag, v, sep)
+			return nil, fmt.Errorf("invalid %s: %s", flag, v)
+		}
+		pairs[name] = strings.TrimSpace(value)
+	}
+	return pairs, nil
+}
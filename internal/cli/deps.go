@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/deps"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depsFormat string
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "List the schema modules the project depends on",
+	Long: `List the CUE modules imported by the schemas, direct and transitive, with
+their versions, sources, licenses and the local definitions that use them.
+
+Dependencies are declared in cue.mod/module.cue and resolved like 'cue'
+does, through the CUE module cache and CUE_REGISTRY. Licenses are read from
+each module's package metadata:
+
+  custom: platosl: license: "Apache-2.0"
+
+in its cue.mod/module.cue, falling back to detecting its LICENSE file.
+
+Formats:
+  table    aligned columns (default)
+  compact  tab-separated rows without a header, for grep, cut and awk
+  json     machine-readable, including the cache directory of each module
+
+Use 'platosl deps why <module>' to explain why a module is pulled in.
+
+Examples:
+  platosl deps
+  platosl deps --format json
+  platosl deps why example.com/geo`,
+	Args: cobra.NoArgs,
+	RunE: runDeps,
+}
+
+var depsWhyCmd = &cobra.Command{
+	Use:   "why <module>",
+	Short: "Explain why a module is a dependency",
+	Long: `Show the import chains that pull a module in, from the local definitions
+that reference it through the modules that import it.
+
+The module is given by its path, with or without the major version, or by
+any import path inside it.
+
+Examples:
+  platosl deps why example.com/geo
+  platosl deps why example.com/geo@v0
+  platosl deps why example.com/geo/countries`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDepsWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+	depsCmd.AddCommand(depsWhyCmd)
+	depsCmd.Flags().StringVar(&depsFormat, "format", "table", "output format (table, compact, json)")
+}
+
+// resolveDeps resolves the dependencies of the configured schemas
+func resolveDeps() (*deps.Graph, error) {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var dirs []string
+	for _, schemaPath := range cfg.Schemas {
+		absPath, err := filepath.Abs(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema path: %w", err)
+		}
+		info, err := os.Stat(absPath)
+		if err != nil {
+			err = fmt.Errorf("schema path not found: %s", schemaPath)
+			PrintError("%v", err)
+			return nil, err
+		}
+		if !info.IsDir() {
+			absPath = filepath.Dir(absPath)
+		}
+		dirs = append(dirs, absPath)
+	}
+
+	PrintVerbose("Resolving dependencies of %d schema path(s)", len(dirs))
+	graph, err := deps.Resolve(root, dirs)
+	if err != nil {
+		PrintError("%v", err)
+		return nil, err
+	}
+	return graph, nil
+}
+
+func runDeps(cmd *cobra.Command, args []string) error {
+	switch depsFormat {
+	case "table", "compact", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected table, compact or json)", depsFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	graph, err := resolveDeps()
+	if err != nil {
+		return err
+	}
+
+	if depsFormat == "json" {
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(graph.Modules) == 0 {
+		PrintInfo("%s has no dependencies", graph.Module)
+		return nil
+	}
+
+	t := newTable("MODULE", "VERSION", "KIND", "LICENSE", "SOURCE", "USED BY")
+	for _, m := range graph.Modules {
+		kind, users := "direct", m.UsedBy
+		if !m.Direct {
+			kind, users = "indirect", m.ImportedBy
+		}
+		t.AddRow(m.Path, m.Version, kind, orDash(m.License), orDash(m.Source), orDash(strings.Join(users, ", ")))
+	}
+	if depsFormat == "compact" {
+		return t.RenderCompact(os.Stdout)
+	}
+	return t.Render(os.Stdout)
+}
+
+func runDepsWhy(cmd *cobra.Command, args []string) error {
+	graph, err := resolveDeps()
+	if err != nil {
+		return err
+	}
+
+	m := graph.Find(args[0])
+	if m == nil {
+		err := fmt.Errorf("%s is not a dependency of %s", args[0], graph.Module)
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintInfo("%s %s is pulled in by:", m.Path, m.Version)
+	for _, chain := range graph.Why(m) {
+		PrintInfo("  %s", strings.Join(chain, " → "))
+	}
+	return nil
+}
+
+// orDash returns s, or "-" for an empty table cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
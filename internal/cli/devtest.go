@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/devtest"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	devtestScenarios []string
+	devtestOptions   []string
+	devtestFormat    string
+	devtestTimeout   time.Duration
+	devtestShow      bool
+	devtestList      bool
+	devtestChild     string
+)
+
+var devtestCmd = &cobra.Command{
+	Use:    "devtest <generator>",
+	Short:  "Run canonical schemas through a generator",
+	Hidden: true,
+	Long: `Run a battery of canonical schemas through a generator and report the
+features it does not handle, for generator authors working towards parity
+with the other generators.
+
+Each scenario exercises one feature: scalars, optional and nullable
+fields, nested structs, references, lists, maps, enums, unions of
+definitions and of kinds, defaults, constraints, recursion, embedding,
+unicode and quoted field names, keywords of target languages, doc
+comments and open structs. A scenario passes when the generator succeeds,
+its output carries the names of the schema (ignoring case and
+punctuation) and two runs give the same output.
+
+Scenarios run in child processes, so a generator that panics, recurses
+without end or hangs fails its scenario instead of the whole run. The
+command exits with an error when a scenario does not pass.
+
+Examples:
+  platosl devtest --list
+  platosl devtest typescript
+  platosl devtest go --scenario recursion,unions --show
+  platosl devtest typescript --option zod=true --format json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if devtestList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runDevtest,
+}
+
+func init() {
+	rootCmd.AddCommand(devtestCmd)
+	devtestCmd.Flags().StringSliceVar(&devtestScenarios, "scenario", nil, "scenarios to run (default: all)")
+	devtestCmd.Flags().StringArrayVar(&devtestOptions, "option", nil, "generator option as key=value, e.g. zod=true (repeatable)")
+	devtestCmd.Flags().StringVar(&devtestFormat, "format", "table", "output format (table, json)")
+	devtestCmd.Flags().DurationVar(&devtestTimeout, "timeout", 30*time.Second, "time limit per scenario")
+	devtestCmd.Flags().BoolVar(&devtestShow, "show", false, "print the generated output of each scenario")
+	devtestCmd.Flags().BoolVar(&devtestList, "list", false, "list the scenarios")
+	devtestCmd.Flags().StringVar(&devtestChild, "child", "", "run one scenario in this process and print its result")
+	devtestCmd.Flags().MarkHidden("child")
+}
+
+func runDevtest(cmd *cobra.Command, args []string) error {
+	if devtestList {
+		t := newTable("SCENARIO", "FEATURE")
+		for _, s := range devtest.Scenarios {
+			t.AddRow(s.Name, s.Feature)
+		}
+		return t.Render(os.Stdout)
+	}
+
+	name := generator.Resolve(args[0])
+	gen, err := generator.Get(name)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	genCfg, err := devtestGenConfig(name)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if devtestChild != "" {
+		s, ok := devtest.Find(devtestChild)
+		if !ok {
+			return fmt.Errorf("unknown scenario %q", devtestChild)
+		}
+		// Fail fast on runaway recursion instead of growing to the
+		// default gigabyte stack
+		debug.SetMaxStack(64 << 20)
+		return json.NewEncoder(os.Stdout).Encode(devtest.Run(gen, s, genCfg))
+	}
+
+	switch devtestFormat {
+	case "table", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected table or json)", devtestFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	scenarios := devtest.Scenarios
+	if len(devtestScenarios) > 0 {
+		scenarios = nil
+		for _, n := range devtestScenarios {
+			s, ok := devtest.Find(n)
+			if !ok {
+				err := fmt.Errorf("unknown scenario %q (see platosl devtest --list)", n)
+				PrintError("%v", err)
+				return err
+			}
+			scenarios = append(scenarios, s)
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	var results []devtest.Result
+	passed := 0
+	for _, s := range scenarios {
+		PrintVerbose("Running %s", s.Name)
+		r := runDevtestChild(exe, name, s)
+		if r.Status == devtest.Pass {
+			passed++
+		}
+		results = append(results, r)
+	}
+
+	if devtestFormat == "json" {
+		if !devtestShow {
+			for i := range results {
+				results[i].Output = ""
+			}
+		}
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		t := newTable("SCENARIO", "RESULT", "DETAIL")
+		for _, r := range results {
+			t.AddRow(r.Scenario, r.Status, firstLine(r.Detail))
+		}
+		if err := t.Render(os.Stdout); err != nil {
+			return err
+		}
+		if devtestShow {
+			for _, r := range results {
+				fmt.Printf("\n==> %s <==\n%s", r.Scenario, r.Output)
+			}
+		}
+		fmt.Println()
+	}
+
+	if passed < len(results) {
+		var features []string
+		for _, r := range results {
+			if r.Status != devtest.Pass {
+				features = append(features, r.Feature)
+			}
+		}
+		PrintError("%s handles %d of %d scenarios; unhandled: %s", name, passed, len(results), strings.Join(features, "; "))
+		return fmt.Errorf("%d scenario(s) did not pass", len(results)-passed)
+	}
+	if devtestFormat != "json" {
+		PrintSuccess("%s handles all %d scenarios", name, len(results))
+	}
+	return nil
+}
+
+// runDevtestChild runs a scenario in a child process, turning crashes and
+// timeouts into results
+func runDevtestChild(exe, name string, s devtest.Scenario) devtest.Result {
+	ctx, cancel := context.WithTimeout(context.Background(), devtestTimeout)
+	defer cancel()
+
+	args := []string{"devtest", name, "--child", s.Name}
+	for _, opt := range devtestOptions {
+		args = append(args, "--option", opt)
+	}
+	child := exec.CommandContext(ctx, exe, args...)
+	var stdout, stderr bytes.Buffer
+	child.Stdout, child.Stderr = &stdout, &stderr
+	err := child.Run()
+
+	result := devtest.Result{Scenario: s.Name, Feature: s.Feature}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Status = devtest.Timeout
+		result.Detail = fmt.Sprintf("no result after %s", devtestTimeout)
+		return result
+	}
+	if jsonErr := json.Unmarshal(stdout.Bytes(), &result); jsonErr == nil {
+		return result
+	}
+	result.Status = devtest.Crash
+	result.Detail = crashReason(stderr.String())
+	if result.Detail == "" && err != nil {
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// crashReason picks the fatal error out of a crashed child's stderr
+func crashReason(stderr string) string {
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "fatal error:") || strings.HasPrefix(line, "panic:") ||
+			strings.HasPrefix(line, "runtime:") {
+			return line
+		}
+	}
+	return firstLine(strings.TrimSpace(stderr))
+}
+
+// devtestGenConfig returns the generator config of the scenarios, with the
+// --option values
+func devtestGenConfig(name string) (config.GenConfig, error) {
+	genCfg := config.GenConfig{
+		Enabled: true,
+		Output:  "generated/" + getDefaultOutput(name),
+		Options: make(map[string]interface{}),
+	}
+	for _, opt := range devtestOptions {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok || key == "" {
+			return genCfg, fmt.Errorf("invalid option %q (expected key=value)", opt)
+		}
+		if b, err := strconv.ParseBool(value); err == nil {
+			genCfg.Options[key] = b
+		} else if n, err := strconv.Atoi(value); err == nil {
+			genCfg.Options[key] = n
+		} else {
+			genCfg.Options[key] = value
+		}
+	}
+	return genCfg, nil
+}
+
+// firstLine returns the first line of a message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/protobuf"
+	"github.com/platoorg/plato-sl-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffGenerated []string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <from-ref> [to-ref]",
+	Short: "Show schema changes between git refs",
+	Long: `Show how the schemas changed between two git refs, or between a ref and
+the working tree when to-ref is omitted.
+
+By default the schema files are diffed. With --generated, both versions are
+rendered through the given generators and the generated code is diffed
+instead, showing what changes in e.g. types.ts. Both sides use the generator
+options of the current platosl.yaml. Nothing is written to the project.
+
+Examples:
+  platosl diff main
+  platosl diff main --generated typescript
+  platosl diff v1.2.0 v1.3.0 --generated typescript,go`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringSliceVar(&diffGenerated, "generated", nil, "diff the output of these generators instead of the schema files")
+}
+
+// diffSide is one side of a diff: a snapshot at a git ref, or the working
+// tree when snap is nil
+type diffSide struct {
+	label string
+	snap  *snapshot.Snapshot
+}
+
+// path returns where a project-relative path lives on this side
+func (s *diffSide) path(path string) string {
+	if s.snap == nil {
+		return path
+	}
+	return s.snap.Path(path)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range diffGenerated {
+		if _, err := generator.Get(name); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	}
+
+	// Extract the schemas, CUE module and any state generators read
+	paths := append([]string{"cue.mod"}, cfg.Schemas...)
+	for _, name := range diffGenerated {
+		if name == "protobuf" {
+			paths = append(paths, protobufLockFile(cfg))
+		}
+	}
+
+	sides := make([]*diffSide, 2)
+	for i := range sides {
+		if i >= len(args) {
+			sides[i] = &diffSide{label: "working tree"}
+			continue
+		}
+		snap, err := snapshot.Extract(args[i], paths)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		defer snap.Remove()
+		sides[i] = &diffSide{label: args[i], snap: snap}
+	}
+
+	var out strings.Builder
+	if len(diffGenerated) == 0 {
+		if err := diffSchemaFiles(&out, cfg, sides[0], sides[1]); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	} else {
+		if err := diffGeneratedCode(&out, cfg, sides[0], sides[1]); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	}
+
+	if out.Len() == 0 {
+		PrintInfo("No changes between %s and %s", sides[0].label, sides[1].label)
+		return nil
+	}
+	fmt.Print(colorizeDiff(out.String()))
+	return nil
+}
+
+// diffSchemaFiles diffs every .cue file under the schema paths
+func diffSchemaFiles(out *strings.Builder, cfg *config.Config, from, to *diffSide) error {
+	files := make(map[string]bool)
+	for _, side := range []*diffSide{from, to} {
+		for _, schemaPath := range cfg.Schemas {
+			root := side.path(schemaPath)
+			filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() || !strings.HasSuffix(path, ".cue") {
+					return nil
+				}
+				rel, err := filepath.Rel(side.path("."), path)
+				if err == nil {
+					files[rel] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a, _ := os.ReadFile(from.path(name))
+		b, _ := os.ReadFile(to.path(name))
+		out.WriteString(diff.Unified(diffName(name, from), diffName(name, to), a, b))
+	}
+	return nil
+}
+
+// diffGeneratedCode renders both sides through each generator and diffs
+// the output
+func diffGeneratedCode(out *strings.Builder, cfg *config.Config, from, to *diffSide) error {
+	tmp, err := os.MkdirTemp("", "platosl-diff-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	values := make([]cue.Value, 2)
+	for i, side := range []*diffSide{from, to} {
+		val, err := loadSideSchemas(platoCue.NewLoader(), cfg, side)
+		if err != nil {
+			return fmt.Errorf("%s: %w", side.label, err)
+		}
+		values[i] = val
+	}
+
+	for _, name := range diffGenerated {
+		outputs, output, err := generateSides(cfg, name, values, []*diffSide{from, to}, tmp)
+		if err != nil {
+			return err
+		}
+		out.WriteString(diff.Unified(diffName(output, from), diffName(output, to), outputs[0], outputs[1]))
+	}
+	return nil
+}
+
+// generateSides renders the schemas of both sides through a generator, in
+// memory, and returns the output of each and the configured output path.
+// State files generators keep are copied into tmp first.
+func generateSides(cfg *config.Config, name string, values []cue.Value, sides []*diffSide, tmp string) ([2][]byte, string, error) {
+	var outputs [2][]byte
+	gen, err := generator.Get(name)
+	if err != nil {
+		return outputs, "", err
+	}
+
+	var output string
+	for i, side := range sides {
+		genCfg := diffGenConfig(cfg, name)
+		output = genCfg.Output
+		var lock string
+
+		// Generators that keep state next to the project work on a
+		// copy, so diffing never updates the real files
+		if name == "protobuf" {
+			lock = filepath.Join(tmp, fmt.Sprintf("%d-%s", i, filepath.Base(protobufLockFile(cfg))))
+			if data, err := os.ReadFile(side.path(protobufLockFile(cfg))); err == nil {
+				if err := os.WriteFile(lock, data, 0644); err != nil {
+					return outputs, "", fmt.Errorf("failed to copy lock file: %w", err)
+				}
+			}
+			genCfg.Options["lockFile"] = lock
+		}
+
+		ctx := generator.NewContext(values[i], cfg, genCfg)
+		if err := gen.Validate(ctx); err != nil {
+			return outputs, "", fmt.Errorf("%s: %s generator validation failed: %w", side.label, name, err)
+		}
+		data, err := gen.Generate(ctx)
+		if err != nil {
+			return outputs, "", fmt.Errorf("%s: %s generation failed: %w", side.label, name, err)
+		}
+		if lock != "" {
+			data = bytes.ReplaceAll(data, []byte(lock), []byte(protobufLockFile(cfg)))
+		}
+		outputs[i] = data
+	}
+	return outputs, output, nil
+}
+
+// loadSideSchemas loads and validates the schemas of one side with loader
+func loadSideSchemas(loader *platoCue.Loader, cfg *config.Config, side *diffSide) (cue.Value, error) {
+	var paths []string
+	for _, schemaPath := range cfg.Schemas {
+		path, err := filepath.Abs(side.path(schemaPath))
+		if err != nil {
+			return cue.Value{}, err
+		}
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return cue.Value{}, fmt.Errorf("no schema paths found")
+	}
+
+	val, err := loader.LoadPaths(paths)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	if err := val.Err(); err != nil {
+		return cue.Value{}, fmt.Errorf("invalid schemas: %w", err)
+	}
+	return val, nil
+}
+
+// diffGenConfig returns the configured generator settings, as gen would use
+func diffGenConfig(cfg *config.Config, name string) config.GenConfig {
+	genCfg, ok := cfg.Generate[name]
+	if !ok {
+		genCfg = config.GenConfig{
+			Enabled: true,
+			Output:  fmt.Sprintf("generated/%s", getDefaultOutput(name)),
+		}
+	}
+
+	// Copy options, so per-side overrides do not leak into the config
+	options := make(map[string]interface{}, len(genCfg.Options))
+	for k, v := range genCfg.Options {
+		options[k] = v
+	}
+	genCfg.Options = options
+	return genCfg
+}
+
+// protobufLockFile returns the configured protobuf numbering lock file
+func protobufLockFile(cfg *config.Config) string {
+	if lock, ok := cfg.Generate["protobuf"].Options["lockFile"].(string); ok && lock != "" {
+		return lock
+	}
+	return protobuf.DefaultLockFile
+}
+
+// diffName labels a file in a diff header with the side it comes from
+func diffName(path string, side *diffSide) string {
+	return fmt.Sprintf("%s (%s)", filepath.ToSlash(path), side.label)
+}
+
+// colorizeDiff colors added and removed lines when color is enabled
+func colorizeDiff(text string) string {
+	if !colorEnabled() {
+		return text
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = ansiBold + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = ansiCyan + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		case strings.HasPrefix(line, "+"):
+			lines[i] = ansiGreen + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		case strings.HasPrefix(line, "-"):
+			lines[i] = ansiRed + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		}
+	}
+	return strings.Join(lines, "")
+}
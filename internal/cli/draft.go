@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/platoorg/plato-sl-cli/internal/draft"
+	"github.com/platoorg/plato-sl-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	draftOutput   string
+	draftEndpoint string
+	draftModel    string
+	draftYes      bool
+	draftForce    bool
+	draftDryRun   bool
+)
+
+var draftCmd = &cobra.Command{
+	Use:   "draft <description>",
+	Short: "Draft a schema with a language model",
+	Long: `Ask a language model for a first draft of a schema, as a starting point
+instead of a blank file. Bring your own model: an OpenAI-compatible chat
+completions endpoint, or a command reading the prompt on stdin and writing
+the draft to stdout. Configure it in the user config, or in platosl.yaml
+for the whole team:
+
+  draft:
+    endpoint: https://api.openai.com/v1/chat/completions
+    model: gpt-4o
+    apiKey: $OPENAI_API_KEY
+
+  draft:
+    command: [ollama, run, llama3]
+
+The API key is read from PLATOSL_DRAFT_KEY, then apiKey; no other token
+is sent to the endpoint. Only the description and the names of the
+existing definitions are sent.
+
+The draft goes through the usual pipeline before anything is written: it
+must parse, is formatted, gets the package of the target directory, and is
+validated together with the existing schemas; it may not redefine existing
+definitions. The change is then shown as a diff to review, and written
+only when confirmed (or with --yes). Nothing is written without a TTY
+unless --yes is given.
+
+The file is written to --output, by default the first schema directory,
+named after the first definition of the draft.
+
+Examples:
+  platosl draft "an invoice with line items and EU VAT"
+  platosl draft "a shipment with tracking events" -o schemas/shipping.cue
+  platosl draft "a product catalog" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDraft,
+}
+
+func init() {
+	rootCmd.AddCommand(draftCmd)
+	draftCmd.Flags().StringVarP(&draftOutput, "output", "o", "", "file to write the draft to")
+	draftCmd.Flags().StringVar(&draftEndpoint, "endpoint", "", "chat completions endpoint (overrides draft.endpoint)")
+	draftCmd.Flags().StringVar(&draftModel, "model", "", "model name (overrides draft.model)")
+	draftCmd.Flags().BoolVarP(&draftYes, "yes", "y", false, "write the draft without asking")
+	draftCmd.Flags().BoolVar(&draftForce, "force", false, "replace an existing file")
+	draftCmd.Flags().BoolVar(&draftDryRun, "dry-run", false, "show the draft without writing it")
+}
+
+func runDraft(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	model, err := draftModelFor(cfg)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	// The existing schemas give the package, and definitions to reference
+	dir, err := draftDir(cfg)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	existing, err := loadAndValidateSchemas(cfg, "draft")
+	if err != nil {
+		return err
+	}
+	pkg := packageOf(dir)
+	defs := definitionNames(existing)
+
+	PrintInfo("Drafting a schema for %q...", args[0])
+	completion, err := model.Complete(draft.NewPrompt(draft.Request{
+		Description: args[0],
+		Package:     pkg,
+		Definitions: defs,
+	}))
+	if err != nil {
+		PrintError("Draft failed: %v", err)
+		return err
+	}
+
+	src, err := draft.Normalize(completion, pkg)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	path := draftOutput
+	if path == "" {
+		path = filepath.Join(dir, draftFileName(src))
+	}
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		PrintError("%v", err)
+		return err
+	}
+	if old != nil && !draftForce && !draftDryRun {
+		err := fmt.Errorf("%s already exists", path)
+		PrintError("%v (use --force to replace it, or -o for another file)", err)
+		return err
+	}
+
+	if err := validateDraft(cfg, existing, src, path, old); err != nil {
+		fmt.Fprint(os.Stderr, string(src))
+		return err
+	}
+
+	from := "/dev/null"
+	if old != nil {
+		from = filepath.ToSlash(path)
+	}
+	fmt.Print(colorizeDiff(diff.Unified(from, filepath.ToSlash(path), old, src)))
+
+	if draftDryRun {
+		return nil
+	}
+	if !draftYes {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			err := fmt.Errorf("not writing %s without confirmation", path)
+			PrintError("%v (review the draft and re-run with --yes)", err)
+			return err
+		}
+		if !confirm(fmt.Sprintf("Write %s?", path), false, "The draft is shown above as a diff") {
+			PrintInfo("Draft discarded")
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	PrintSuccess("Wrote %s", path)
+	PrintInfo("Review it, then run 'platosl advise' for what to refine")
+	return nil
+}
+
+// draftModelFor returns the configured model, flags over platosl.yaml over
+// the user config
+func draftModelFor(cfg *config.Config) (draft.Model, error) {
+	draftCfg := config.MergeDraft(cfg.Draft, userCfg.Draft)
+	if draftEndpoint != "" {
+		draftCfg.Endpoint, draftCfg.Command = draftEndpoint, nil
+	}
+	if draftModel != "" {
+		draftCfg.Model = draftModel
+	}
+
+	if draftCfg.Endpoint == "" {
+		if len(draftCfg.Command) > 0 {
+			return &draft.Command{Args: draftCfg.Command}, nil
+		}
+		return nil, fmt.Errorf("no model configured: set draft.endpoint or draft.command in the user config or platosl.yaml, or pass --endpoint")
+	}
+
+	key := os.Getenv("PLATOSL_DRAFT_KEY")
+	if key == "" {
+		key = os.ExpandEnv(draftCfg.APIKey)
+	}
+	client, err := newKeyedHTTPClient(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+	return &draft.HTTP{Client: client, Endpoint: draftCfg.Endpoint, Model: draftCfg.Model}, nil
+}
+
+// draftDir returns the directory drafts go to: that of --output, or the
+// first schema path
+func draftDir(cfg *config.Config) (string, error) {
+	if draftOutput != "" {
+		return filepath.Dir(draftOutput), nil
+	}
+	if len(cfg.Schemas) == 0 {
+		return "", fmt.Errorf("no schema paths configured in platosl.yaml")
+	}
+	dir := cfg.Schemas[0]
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	return dir, nil
+}
+
+// packageOf returns the package of the CUE files in a directory, if any
+func packageOf(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.cue"))
+	for _, file := range files {
+		f, err := parser.ParseFile(file, nil, parser.PackageClauseOnly)
+		if err == nil && f.PackageName() != "" {
+			return f.PackageName()
+		}
+	}
+	return ""
+}
+
+// definitionNames returns the top-level definitions of a value, sorted
+func definitionNames(val cue.Value) []string {
+	var names []string
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			names = append(names, iter.Selector().String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// draftFileName names a draft after its first definition, e.g.
+// line_item.cue for #LineItem
+func draftFileName(src []byte) string {
+	if defs := definitionLabels(src); len(defs) > 0 {
+		var b strings.Builder
+		for i, r := range strings.TrimPrefix(defs[0], "#") {
+			if unicode.IsUpper(r) && i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		}
+		return b.String() + ".cue"
+	}
+	return "draft.cue"
+}
+
+// definitionLabels returns the top-level definitions declared in a file
+func definitionLabels(src []byte) []string {
+	f, err := parser.ParseFile("draft.cue", src)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if name, _, err := ast.LabelName(field.Label); err == nil && strings.HasPrefix(name, "#") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validateDraft validates a draft the way the project's schemas are loaded,
+// in a copy of the schemas with the draft in place. The draft may reference
+// existing definitions but not redefine them, except those of the file it
+// replaces.
+func validateDraft(cfg *config.Config, existing cue.Value, src []byte, path string, old []byte) error {
+	replaced := make(map[string]bool)
+	for _, name := range definitionLabels(old) {
+		replaced[name] = true
+	}
+	for _, name := range definitionLabels(src) {
+		if existing.LookupPath(cue.ParsePath(name)).Exists() && !replaced[name] {
+			err := fmt.Errorf("the draft redefines %s", name)
+			PrintError("%v, which already exists", err)
+			return err
+		}
+	}
+
+	rel, err := filepath.Rel(".", path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		err := fmt.Errorf("%s is outside the project", path)
+		PrintError("%v", err)
+		return err
+	}
+	snap, err := snapshot.Copy(append([]string{"cue.mod"}, cfg.Schemas...))
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	defer snap.Remove()
+	if err := os.MkdirAll(filepath.Dir(snap.Path(rel)), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(snap.Path(rel), src, 0644); err != nil {
+		return err
+	}
+
+	// Load the schema paths, and the draft when it is outside of them
+	var paths []string
+	covered := false
+	for _, schemaPath := range cfg.Schemas {
+		abs, err := filepath.Abs(snap.Path(schemaPath))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(abs); err == nil {
+			paths = append(paths, abs)
+		}
+		if inside, err := filepath.Rel(filepath.Clean(schemaPath), rel); err == nil && !strings.HasPrefix(inside, "..") {
+			covered = true
+		}
+	}
+	if !covered {
+		paths = append(paths, snap.Path(rel))
+	}
+
+	val, err := loadSchemaPaths(platoCue.NewLoader(), cfg, paths)
+	if err != nil {
+		PrintError("The draft does not load with the schemas: %v", err)
+		return err
+	}
+	if errs := validateSchemas(cfg, val, "draft"); len(errs) > 0 {
+		PrintError("The draft failed validation with %d error(s):\n", len(errs))
+		for _, err := range errs {
+			PrintError(err.Format())
+		}
+		return fmt.Errorf("draft validation failed")
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/drift"
+	"github.com/spf13/cobra"
+)
+
+var (
+	driftInputs []string
+	driftSchema string
+	driftPath   string
+	driftSample int
+	driftSeed   int64
+	driftFormat string
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Compare real payloads with a definition and report drift",
+	Long: `Analyse a sample of real payloads, one JSON value per line (NDJSON), and
+report where they have drifted from a definition:
+
+  unknown-field     present in data, not declared in the schema
+  absent-field      declared in the schema, never present in data
+  missing-required  required in the schema, missing from some data
+  type-mismatch     values of a type the schema rejects
+  unknown-value     strings outside a string enum
+
+Fields in data get a suggested declaration with the type inferred from the
+values, optional when they are not always present, so the schema can be
+brought in line with reality.
+
+Large logs are sampled uniformly (--sample lines, 0 for all). When payloads
+are wrapped in log records, --path selects them, e.g. --path body for
+{"ts": ..., "body": {...}}. Use - to read stdin.
+
+Examples:
+  platosl drift --input logs.ndjson --schema '#Event'
+  platosl drift --input day1.ndjson --input day2.ndjson --schema '#Order' --sample 0
+  kubectl logs deploy/api | platosl drift --input - --schema '#Request' --path request`,
+	Args: cobra.NoArgs,
+	RunE: runDrift,
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+	driftCmd.Flags().StringArrayVar(&driftInputs, "input", nil, "NDJSON file of payloads, - for stdin (repeatable, required)")
+	driftCmd.Flags().StringVar(&driftSchema, "schema", "", "definition the payloads should conform to (required)")
+	driftCmd.Flags().StringVar(&driftPath, "path", "", "dot-separated path of the payload within each line")
+	driftCmd.Flags().IntVar(&driftSample, "sample", 10000, "lines to sample (0 analyses every line)")
+	driftCmd.Flags().Int64Var(&driftSeed, "seed", 0, "random seed for sampling (default: time-based)")
+	driftCmd.Flags().StringVar(&driftFormat, "format", "text", "output format (text, json)")
+	driftCmd.MarkFlagRequired("input")
+	driftCmd.MarkFlagRequired("schema")
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	if driftFormat != "text" && driftFormat != "json" {
+		err := fmt.Errorf("unsupported format %q (supported: text, json)", driftFormat)
+		PrintError("%v", err)
+		return err
+	}
+	if driftSample < 0 {
+		return fmt.Errorf("--sample must not be negative")
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "drift")
+	if err != nil {
+		return err
+	}
+
+	def, err := platoCue.LookupDefinition(schemas, driftSchema)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	seed := driftSeed
+	if !cmd.Flags().Changed("seed") {
+		seed = time.Now().UnixNano()
+	}
+
+	sampler := drift.NewSampler(driftSample, seed)
+	for _, input := range driftInputs {
+		if err := readDriftInput(sampler, input); err != nil {
+			PrintError("%s: %v", input, err)
+			return err
+		}
+	}
+
+	report := sampler.Analyze(drift.NewAnalyzer(def, driftSchema), driftPath)
+
+	if driftFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if report.Malformed > 0 {
+		PrintInfo("Skipped %d line(s) that are not JSON or have no payload at --path", report.Malformed)
+	}
+	if report.Records == 0 {
+		PrintError("No payloads to analyse")
+		return fmt.Errorf("no payloads")
+	}
+
+	sampled := fmt.Sprintf("%d payload(s)", report.Records)
+	if report.Records+report.Malformed < report.Total {
+		sampled = fmt.Sprintf("%d of %d payload(s) (sampled)", report.Records, report.Total)
+	}
+
+	if len(report.Findings) == 0 {
+		PrintSuccess("%s match %s, no drift found", sampled, driftSchema)
+		return nil
+	}
+
+	fmt.Printf("%s: %d finding(s) in %s\n\n", driftSchema, len(report.Findings), sampled)
+	pathWidth, kindWidth := 0, 0
+	for _, f := range report.Findings {
+		pathWidth = max(pathWidth, len(f.Path))
+		kindWidth = max(kindWidth, len(f.Kind))
+	}
+	for _, f := range report.Findings {
+		fmt.Printf("  %-*s  %-*s  %s\n", pathWidth, f.Path, kindWidth, f.Kind, f.Message)
+	}
+
+	printDriftSuggestions(report)
+	return nil
+}
+
+// readDriftInput adds the lines of a file, or stdin for -, to the sample
+func readDriftInput(sampler *drift.Sampler, input string) error {
+	if input == "-" {
+		return sampler.Read(os.Stdin)
+	}
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return sampler.Read(f)
+}
+
+// printDriftSuggestions prints the suggested declarations grouped by the
+// struct they belong in
+func printDriftSuggestions(report *drift.Report) {
+	var parents []string
+	byParent := make(map[string][]string)
+	for _, f := range report.Findings {
+		if f.Suggestion == "" {
+			continue
+		}
+		if _, ok := byParent[f.Parent]; !ok {
+			parents = append(parents, f.Parent)
+		}
+		byParent[f.Parent] = append(byParent[f.Parent], f.Suggestion)
+	}
+	if len(parents) == 0 {
+		return
+	}
+	// Top-level fields first
+	sort.SliceStable(parents, func(i, j int) bool { return parents[i] == "" && parents[j] != "" })
+
+	fmt.Printf("\nSuggested changes to %s:\n", report.Definition)
+	for _, parent := range parents {
+		fmt.Println()
+		if parent != "" {
+			fmt.Printf("\t// in %s\n", parent)
+		}
+		for _, s := range byParent[parent] {
+			fmt.Printf("\t%s\n", strings.ReplaceAll(s, "\n", "\n\t"))
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	entryOut    string
+	entryFormat string
+	entryForce  bool
+)
+
+var entryCmd = &cobra.Command{
+	Use:   "entry",
+	Short: "Create content entries from definitions",
+	Long:  `Create content data files that conform to a definition.`,
+}
+
+var entryNewCmd = &cobra.Command{
+	Use:   "new <definition>",
+	Short: "Interactively create a content entry",
+	Long: `Prompt for each field of a definition and write a valid YAML or JSON file.
+
+Prompts follow the schema: booleans are confirmed, string enums are picked
+from a list, and every answer is checked against the field's constraints
+(types, bounds, regular expressions) as it is entered. Defaults are
+pre-filled, optional fields can be left empty, and doc comments are shown
+as help (press ?).
+
+If --output is a directory, the file is named after the entry's slug, id, name
+or title field.
+
+Examples:
+  platosl entry new '#Article' -o content/articles/
+  platosl entry new Author -o content/authors/jane.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEntryNew,
+}
+
+func init() {
+	rootCmd.AddCommand(entryCmd)
+	entryCmd.AddCommand(entryNewCmd)
+	entryNewCmd.Flags().StringVarP(&entryOut, "output", "o", ".", "output file or directory")
+	entryNewCmd.Flags().StringVar(&entryOut, "out", ".", "output file or directory")
+	entryNewCmd.Flags().MarkHidden("out")
+	entryNewCmd.Flags().StringVar(&entryFormat, "format", "", "output format: yaml, json (default from --output extension, else yaml)")
+	entryNewCmd.Flags().BoolVar(&entryForce, "force", false, "overwrite an existing file")
+}
+
+func runEntryNew(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	val, err := loadAndValidateSchemas(cfg, "entry")
+	if err != nil {
+		return err
+	}
+
+	def, err := platoCue.LookupDefinition(val, args[0])
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	format, err := entryOutputFormat()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	entry, err := promptStruct(def, "")
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	// Check the complete entry, including cross-field constraints
+	result := platoCue.ValidateData(def, def.Context().Encode(entry.plain()))
+	if !result.Valid {
+		PrintError("Entry does not satisfy %s", args[0])
+		for _, verr := range result.Errors {
+			fmt.Fprintln(os.Stderr, platoCue.FormatError(verr))
+		}
+		return fmt.Errorf("entry is invalid")
+	}
+
+	data, err := entry.encode(format)
+	if err != nil {
+		PrintError("Failed to encode entry: %v", err)
+		return err
+	}
+
+	path := entryPath(entry, def, args[0], format)
+	if _, err := os.Stat(path); err == nil && !entryForce {
+		PrintError("%s already exists (use --force to overwrite)", path)
+		return fmt.Errorf("file exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		PrintError("Failed to create directory: %v", err)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		PrintError("Failed to write %s: %v", path, err)
+		return err
+	}
+
+	PrintSuccess("Created %s", path)
+	return nil
+}
+
+// entryOutputFormat returns the output format from --format or --output
+func entryOutputFormat() (string, error) {
+	switch entryFormat {
+	case "yaml", "json":
+		return entryFormat, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported format %q (use yaml or json)", entryFormat)
+	}
+
+	switch strings.ToLower(filepath.Ext(entryOut)) {
+	case ".json":
+		return "json", nil
+	default:
+		return "yaml", nil
+	}
+}
+
+// entryPath returns the file to write; directories get a file named after
+// the entry
+func entryPath(entry *orderedEntry, def cue.Value, name, format string) string {
+	info, err := os.Stat(entryOut)
+	isDir := (err == nil && info.IsDir()) || strings.HasSuffix(entryOut, "/") || strings.HasSuffix(entryOut, string(os.PathSeparator))
+	if !isDir {
+		return entryOut
+	}
+
+	base := strings.ToLower(strings.TrimPrefix(name, "#"))
+	for _, key := range []string{"slug", "id", "name", "title"} {
+		if s, ok := entry.values[key].(string); ok && slugify(s) != "" {
+			base = slugify(s)
+			break
+		}
+	}
+
+	ext := ".yaml"
+	if format == "json" {
+		ext = ".json"
+	}
+	return filepath.Join(entryOut, base+ext)
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a title into a file name
+func slugify(s string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// orderedEntry is an entry that keeps fields in schema order
+type orderedEntry struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedEntry() *orderedEntry {
+	return &orderedEntry{values: make(map[string]interface{})}
+}
+
+func (e *orderedEntry) set(key string, value interface{}) {
+	if _, ok := e.values[key]; !ok {
+		e.keys = append(e.keys, key)
+	}
+	e.values[key] = value
+}
+
+// plain converts the entry into maps and slices for CUE encoding
+func (e *orderedEntry) plain() map[string]interface{} {
+	out := make(map[string]interface{}, len(e.keys))
+	for _, k := range e.keys {
+		out[k] = plainValue(e.values[k])
+	}
+	return out
+}
+
+func plainValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *orderedEntry:
+		return v.plain()
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = plainValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// encode renders the entry as YAML or JSON in field order
+func (e *orderedEntry) encode(format string) ([]byte, error) {
+	if format == "json" {
+		var buf bytes.Buffer
+		if err := e.writeJSON(&buf, ""); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n")
+		return buf.Bytes(), nil
+	}
+
+	node, err := e.yamlNode()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *orderedEntry) writeJSON(buf *bytes.Buffer, indent string) error {
+	if len(e.keys) == 0 {
+		buf.WriteString("{}")
+		return nil
+	}
+	buf.WriteString("{\n")
+	for i, k := range e.keys {
+		key, _ := json.Marshal(k)
+		fmt.Fprintf(buf, "%s  %s: ", indent, key)
+		if err := writeJSONValue(buf, e.values[k], indent+"  "); err != nil {
+			return err
+		}
+		if i < len(e.keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(indent + "}")
+	return nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}, indent string) error {
+	switch v := v.(type) {
+	case *orderedEntry:
+		return v.writeJSON(buf, indent)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(indent + "  ")
+			if err := writeJSONValue(buf, item, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+		return nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		return nil
+	}
+}
+
+func (e *orderedEntry) yamlNode() (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range e.keys {
+		value, err := yamlValue(e.values[k])
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, value)
+	}
+	return node, nil
+}
+
+func yamlValue(v interface{}) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case *orderedEntry:
+		return v.yamlNode()
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			child, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
+
+// maxEntryDepth limits how deep nested structs are prompted
+const maxEntryDepth = 8
+
+// promptStruct prompts for every field of a struct value
+func promptStruct(val cue.Value, prefix string) (*orderedEntry, error) {
+	entry := newOrderedEntry()
+	if strings.Count(prefix, ".") >= maxEntryDepth {
+		return entry, nil
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		path := label
+		if prefix != "" {
+			path = prefix + "." + label
+		}
+
+		value, ok, err := promptField(path, iter.Value(), iter.IsOptional())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			entry.set(label, value)
+		}
+	}
+
+	return entry, nil
+}
+
+// promptField asks for a single field; ok is false for skipped optional
+// fields
+func promptField(path string, field cue.Value, optional bool) (interface{}, bool, error) {
+	docs := platoCue.DocsOf(field)
+	help := docs.Description
+	// Fields with a @title are asked for by it
+	label := path
+	if docs.Title != "" {
+		label = docs.Title
+	}
+	def, hasDefault := field.Default()
+	hasDefault = hasDefault && def.IsConcrete()
+
+	// Fixed values need no prompt
+	if field.IsConcrete() && !hasDefault && field.Kind() != cue.StructKind && field.Kind() != cue.ListKind {
+		var v interface{}
+		if err := field.Decode(&v); err == nil {
+			return v, true, nil
+		}
+	}
+
+	switch kind := field.IncompleteKind(); {
+	case kind == cue.BoolKind:
+		if optional && !confirm(fmt.Sprintf("Set %s?", label), false, help) {
+			return nil, false, nil
+		}
+		answer := false
+		if hasDefault {
+			_ = def.Decode(&answer)
+		}
+		err := survey.AskOne(&survey.Confirm{Message: label, Default: answer, Help: help}, &answer)
+		return answer, err == nil, err
+
+	case kind == cue.StringKind && len(stringEnumOf(field)) > 0:
+		options := stringEnumOf(field)
+		if optional {
+			options = append([]string{"(none)"}, options...)
+		}
+		prompt := &survey.Select{Message: label, Options: options, Help: help}
+		if hasDefault {
+			if s, err := def.String(); err == nil {
+				prompt.Default = s
+			}
+		}
+		var answer string
+		if err := survey.AskOne(prompt, &answer); err != nil {
+			return nil, false, err
+		}
+		if answer == "(none)" {
+			return nil, false, nil
+		}
+		return answer, true, nil
+
+	case kind == cue.StructKind:
+		if optional && !confirm(fmt.Sprintf("Fill in %s?", label), false, help) {
+			return nil, false, nil
+		}
+		entry, err := promptStruct(field, path)
+		return entry, err == nil, err
+
+	case kind == cue.ListKind:
+		return promptList(path, field, optional, help)
+
+	default:
+		return promptScalar(label, field, optional, help, def, hasDefault)
+	}
+}
+
+// promptScalar asks for a string or number, validating the answer against
+// the field as it is typed
+func promptScalar(label string, field cue.Value, optional bool, help string, def cue.Value, hasDefault bool) (interface{}, bool, error) {
+	kind := field.IncompleteKind()
+
+	prompt := &survey.Input{Message: fmt.Sprintf("%s (%s)", label, kindName(kind)), Help: help}
+	if hasDefault {
+		var v interface{}
+		if err := def.Decode(&v); err == nil {
+			prompt.Default = fmt.Sprint(v)
+		}
+	}
+
+	validate := func(ans interface{}) error {
+		s, _ := ans.(string)
+		if s == "" && (optional || hasDefault) {
+			return nil
+		}
+		_, err := checkScalar(field, s)
+		return err
+	}
+
+	var answer string
+	if err := survey.AskOne(prompt, &answer, survey.WithValidator(validate)); err != nil {
+		return nil, false, err
+	}
+	if answer == "" && !hasDefault {
+		return nil, false, nil
+	}
+
+	value, err := checkScalar(field, answer)
+	return value, err == nil, err
+}
+
+// promptList asks for list items: comma-separated for scalars, one struct at
+// a time otherwise
+func promptList(path string, field cue.Value, optional bool, help string) (interface{}, bool, error) {
+	elem := field.LookupPath(cue.MakePath(cue.AnyIndex))
+	if !elem.Exists() {
+		elem = field.Context().CompileString("_")
+	}
+
+	items := []interface{}{}
+	if elem.IncompleteKind() == cue.StructKind {
+		for confirm(fmt.Sprintf("Add an item to %s?", path), len(items) == 0 && !optional, help) {
+			item, err := promptStruct(elem, fmt.Sprintf("%s[%d]", path, len(items)))
+			if err != nil {
+				return nil, false, err
+			}
+			items = append(items, item)
+		}
+	} else {
+		validate := func(ans interface{}) error {
+			s, _ := ans.(string)
+			for _, part := range splitList(s) {
+				if _, err := checkScalar(elem, part); err != nil {
+					return fmt.Errorf("%q: %v", part, err)
+				}
+			}
+			return nil
+		}
+
+		var answer string
+		prompt := &survey.Input{Message: fmt.Sprintf("%s (comma-separated %s)", path, kindName(elem.IncompleteKind())), Help: help}
+		if err := survey.AskOne(prompt, &answer, survey.WithValidator(validate)); err != nil {
+			return nil, false, err
+		}
+		for _, part := range splitList(answer) {
+			v, err := checkScalar(elem, part)
+			if err != nil {
+				return nil, false, err
+			}
+			items = append(items, v)
+		}
+	}
+
+	if len(items) == 0 && optional {
+		return nil, false, nil
+	}
+	return items, true, nil
+}
+
+// checkScalar parses an answer for the field's kind and checks it against
+// the field's constraints
+func checkScalar(field cue.Value, s string) (interface{}, error) {
+	kind := field.IncompleteKind()
+
+	var value interface{} = s
+	switch {
+	case kind&cue.StringKind != 0:
+		// Keep strings as typed, even if they look like numbers
+	case kind&cue.IntKind != 0 && kind&cue.FloatKind == 0:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer")
+		}
+		value = i
+	case kind&cue.NumberKind != 0:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number")
+		}
+		if f == float64(int64(f)) && kind&cue.IntKind != 0 {
+			value = int64(f)
+		} else {
+			value = f
+		}
+	case kind&cue.BoolKind != 0:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		value = b
+	}
+
+	unified := field.Unify(field.Context().Encode(value))
+	if err := unified.Validate(cue.Concrete(true)); err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(lastLine(err.Error())))
+	}
+	return value, nil
+}
+
+// confirm asks a yes/no question, treating errors as no
+func confirm(message string, def bool, help string) bool {
+	answer := def
+	if err := survey.AskOne(&survey.Confirm{Message: message, Default: def, Help: help}, &answer); err != nil {
+		return false
+	}
+	return answer
+}
+
+// stringEnumOf returns the members of a disjunction of string literals
+func stringEnumOf(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// kindName describes a kind in prompts
+func kindName(kind cue.Kind) string {
+	switch {
+	case kind == cue.IntKind:
+		return "integer"
+	case kind&cue.NumberKind != 0 && kind&cue.StringKind == 0:
+		return "number"
+	case kind == cue.StringKind:
+		return "string"
+	default:
+		return kind.String()
+	}
+}
+
+// splitList splits a comma-separated answer, dropping empty items
+func splitList(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// lastLine returns the last line of a multi-line error message
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
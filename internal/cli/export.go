@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	exportFormat string
+	exportPath   string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <package or expression>",
+	Short: "Export concrete CUE data as JSON, YAML or NDJSON",
+	Long: `Evaluate concrete CUE data (catalogs, fixtures) and export it with stable
+key ordering.
+
+The argument is either a CUE file or package directory, or a CUE path
+expression (e.g. 'catalog.items') evaluated against the schema paths from
+platosl.yaml.
+
+Use --path to select part of the exported value. Path segments are separated
+by dots; [N] selects a list element and [*] fans out over all elements.
+
+Examples:
+  platosl export content/catalog
+  platosl export catalog --path 'items[*]' --format ndjson
+  platosl export fixtures/orders.cue --format yaml -o orders.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "output format (json, yaml, ndjson)")
+	exportCmd.Flags().StringVar(&exportPath, "path", "", "select part of the value (e.g. 'items[*]')")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file path (default stdout)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	val, err := loadExportValue(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Require concrete data
+	if err := val.Validate(cue.Concrete(true)); err != nil {
+		PrintError("Value is not concrete: %v", err)
+		return fmt.Errorf("value is not concrete")
+	}
+
+	evaluator := platoCue.NewEvaluator(platoCue.NewLoader())
+	data, err := evaluator.Evaluate(val)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	// Apply path filter
+	items := []interface{}{data}
+	if exportPath != "" {
+		items, err = platoCue.SelectPath(data, exportPath)
+		if err != nil {
+			PrintError("Invalid --path: %v", err)
+			return err
+		}
+	}
+
+	output, err := formatExport(items, platoCue.SelectsMany(exportPath))
+	if err != nil {
+		return err
+	}
+
+	if exportOutput == "" {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(exportOutput), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(exportOutput, output, 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	PrintSuccess("Exported %s (%d bytes)", exportOutput, len(output))
+	return nil
+}
+
+// loadExportValue loads the value to export from a path or an expression
+func loadExportValue(arg string) (cue.Value, error) {
+	loader := platoCue.NewLoader()
+
+	if info, err := os.Stat(arg); err == nil {
+		PrintVerbose("Loading: %s", arg)
+		var val cue.Value
+		if info.IsDir() {
+			val, err = loader.LoadDir(arg)
+		} else {
+			val, err = loader.LoadFile(arg)
+		}
+		if err != nil {
+			PrintError("%v", err)
+			return cue.Value{}, err
+		}
+		return val, nil
+	}
+
+	// Treat the argument as an expression over the configured schemas
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	val, err := loadAndValidateSchemas(cfg, "export")
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	selected := val.LookupPath(cue.ParsePath(arg))
+	if !selected.Exists() {
+		PrintError("%s not found in configured schemas", arg)
+		return cue.Value{}, fmt.Errorf("%s not found", arg)
+	}
+
+	return selected, nil
+}
+
+// formatExport renders the exported items in the requested format. The
+// matches of a path with a [*] segment are exported as a list, however
+// many there are.
+func formatExport(items []interface{}, many bool) ([]byte, error) {
+	var data interface{} = items
+	if !many {
+		data = items[0]
+	}
+
+	switch exportFormat {
+	case "json":
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		return append(out, '\n'), nil
+
+	case "yaml":
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format as YAML: %w", err)
+		}
+		return out, nil
+
+	case "ndjson":
+		// One record per line; a single list value is spread into its elements
+		records := items
+		if !many {
+			if list, ok := items[0].([]interface{}); ok {
+				records = list
+			}
+		}
+
+		var buf bytes.Buffer
+		for _, record := range records {
+			line, err := json.Marshal(record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format as NDJSON: %w", err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (use json, yaml or ndjson)", exportFormat)
+	}
+}
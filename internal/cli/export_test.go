@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+func TestFormatExportWildcardWithOneMatch(t *testing.T) {
+	data := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"name": "a"}},
+	}
+	exportFormat = "json"
+	defer func() { exportFormat = "json" }()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"items[*]", "[\n  {\n    \"name\": \"a\"\n  }\n]\n"},
+		{"items[0]", "{\n  \"name\": \"a\"\n}\n"},
+	}
+	for _, tt := range tests {
+		items, err := platoCue.SelectPath(data, tt.path)
+		if err != nil {
+			t.Fatalf("SelectPath(%q): %v", tt.path, err)
+		}
+		out, err := formatExport(items, platoCue.SelectsMany(tt.path))
+		if err != nil {
+			t.Fatalf("formatExport: %v", err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("--path %s exported\n%s\nwant\n%s", tt.path, out, tt.want)
+		}
+	}
+
+	// NDJSON writes the one match as one line, not its fields
+	exportFormat = "ndjson"
+	items, _ := platoCue.SelectPath(data, "items[*]")
+	out, err := formatExport(items, true)
+	if err != nil {
+		t.Fatalf("formatExport: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != `{"name":"a"}` {
+		t.Errorf("ndjson = %q, want one record", got)
+	}
+}
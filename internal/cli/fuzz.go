@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/fuzz"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fuzzTargets    []string
+	fuzzIterations int
+	fuzzSeed       int64
+	fuzzExamples   int
+	fuzzWorkDir    string
+	fuzzKeep       bool
+)
+
+var fuzzCmd = &cobra.Command{
+	Use:   "fuzz <definition>",
+	Short: "Cross-check generated validators against CUE with random instances",
+	Long: `Generate random valid and near-invalid instances of a definition and run
+them through the validators generated for other languages, reporting every
+instance on which a target disagrees with CUE about validity.
+
+Near-invalid instances are valid ones with a single mutation: a dropped or
+unknown field, a value of the wrong type, or a value nudged past a likely
+boundary. CUE decides which instances are valid.
+
+Targets:
+  go   decodes into the structs of 'platosl gen go' with unknown fields
+       disallowed (requires the go toolchain)
+  zod  parses with the schemas of 'platosl gen zod' in node (requires node,
+       and zod installed in the project's node_modules)
+
+Harnesses are generated under --work-dir and removed afterwards unless --keep
+is set. Use --seed to reproduce a run.
+
+Examples:
+  platosl fuzz '#Order'
+  platosl fuzz '#Order' --target zod,go --iterations 10000
+  platosl fuzz '#Order' --target go --seed 42 --keep`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFuzz,
+}
+
+func init() {
+	rootCmd.AddCommand(fuzzCmd)
+	fuzzCmd.Flags().StringSliceVar(&fuzzTargets, "target", []string{"zod", "go"}, "targets to check ("+strings.Join(fuzz.Targets, ", ")+")")
+	fuzzCmd.Flags().IntVarP(&fuzzIterations, "iterations", "n", 1000, "number of instances to generate")
+	fuzzCmd.Flags().Int64Var(&fuzzSeed, "seed", 0, "random seed (default: time-based)")
+	fuzzCmd.Flags().IntVar(&fuzzExamples, "examples", 5, "disagreements to print per run (0 prints all)")
+	fuzzCmd.Flags().StringVar(&fuzzWorkDir, "work-dir", ".platosl/fuzz", "directory for generated harnesses")
+	fuzzCmd.Flags().BoolVar(&fuzzKeep, "keep", false, "keep generated harnesses after the run")
+}
+
+func runFuzz(cmd *cobra.Command, args []string) error {
+	if fuzzIterations <= 0 {
+		return fmt.Errorf("--iterations must be positive")
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "fuzz")
+	if err != nil {
+		return err
+	}
+
+	seed := fuzzSeed
+	if !cmd.Flags().Changed("seed") {
+		seed = time.Now().UnixNano()
+	}
+
+	if !fuzzKeep {
+		defer os.RemoveAll(fuzzWorkDir)
+	}
+
+	PrintInfo("Fuzzing %s against %s with %d instances (seed %d)", args[0], strings.Join(fuzzTargets, ", "), fuzzIterations, seed)
+
+	result, err := fuzz.Run(schemas, cfg, fuzz.Options{
+		Definition: args[0],
+		Targets:    fuzzTargets,
+		Iterations: fuzzIterations,
+		Seed:       seed,
+		WorkDir:    fuzzWorkDir,
+	})
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintInfo("%d instances: %d valid, %d invalid according to CUE\n", result.Instances, result.Valid, result.Instances-result.Valid)
+	for _, t := range result.Targets {
+		PrintInfo("  %-6s %d agreed, %d false accepts, %d false rejects", t.Name, t.Agreed, t.FalseAccepts, t.FalseRejects)
+	}
+
+	if len(result.Disagreements) == 0 {
+		fmt.Println()
+		PrintSuccess("All targets agree with CUE")
+		return nil
+	}
+
+	shown := result.Disagreements
+	if fuzzExamples > 0 && len(shown) > fuzzExamples {
+		shown = shown[:fuzzExamples]
+	}
+	for _, d := range shown {
+		fmt.Printf("\n%s\n", d.Instance)
+		if d.Valid {
+			fmt.Println("  cue:   valid")
+		} else {
+			fmt.Printf("  cue:   invalid (%s)\n", d.Reason)
+		}
+
+		names := make([]string, 0, len(d.Verdicts))
+		for name := range d.Verdicts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if v := d.Verdicts[name]; v.Valid {
+				fmt.Printf("  %-6s valid\n", name+":")
+			} else {
+				fmt.Printf("  %-6s invalid (%s)\n", name+":", v.Reason)
+			}
+		}
+	}
+	if len(shown) < len(result.Disagreements) {
+		fmt.Printf("\n... and %d more (use --examples 0 to show all)\n", len(result.Disagreements)-len(shown))
+	}
+
+	fmt.Println()
+	PrintError("%d of %d instances disagree across languages (reproduce with --seed %d)", len(result.Disagreements), result.Instances, seed)
+	return fmt.Errorf("validators disagree")
+}
@@ -1,33 +1,76 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"cuelang.org/go/cue"
 	"github.com/spf13/cobra"
+	"github.com/platoorg/plato-sl-cli/internal/audit"
 	"github.com/platoorg/plato-sl-cli/internal/config"
 	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/errors"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/packaging"
+	"github.com/platoorg/plato-sl-cli/internal/severity"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
 
 	// Import generators to register them
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/acl"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/clojure"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/valibot"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/yup"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/joi"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/effect"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/typebox"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/mongoose"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/ecto"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/absinthe"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/gleam"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/terraform"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/bigquery"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/arrow"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/pyspark"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/jsonld"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/html"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/c"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/csharp"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/dart"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/haskell"
 	_ "github.com/platoorg/plato-sl-cli/internal/generator/elixir"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/encrypt"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/events"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/flags"
 	_ "github.com/platoorg/plato-sl-cli/internal/generator/golang"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/graphql"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/java"
 	_ "github.com/platoorg/plato-sl-cli/internal/generator/jsonschema"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/php"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/protobuf"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/sql"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/trpc"
 	_ "github.com/platoorg/plato-sl-cli/internal/generator/typescript"
 	_ "github.com/platoorg/plato-sl-cli/internal/generator/zod"
 )
 
 var (
-	genOutput string
+	genOutput     string
+	genCodecs     []string
+	genCanonical  bool
+	genLenient    bool
+	genTypeGuards bool
+	genUnicode    string
 )
 
 var genCmd = &cobra.Command{
-	Use:   "gen",
-	Short: "Generate code from CUE schemas",
+	Use:     "gen",
+	Aliases: []string{"g"},
+	Short:   "Generate code from CUE schemas",
 	Long: `Generate code from CUE schemas to various target languages.
 
 Available generators:
@@ -35,7 +78,45 @@ Available generators:
   zod         - Generate Zod schemas with inferred TypeScript types
   jsonschema  - Generate JSON Schema
   go          - Generate Go structs
-  elixir      - Generate Elixir typespecs`,
+  elixir      - Generate Elixir typespecs
+  graphql     - Generate GraphQL SDL (optionally with federation v2 directives)
+  trpc        - Generate a tRPC router skeleton for @rpc definitions
+  events      - Generate NATS/EventBridge publish/subscribe helpers for @event definitions
+  flags       - Generate typed feature flag / settings accessors
+  acl         - Export @acl field access rules (OPA, Rego, Casbin, TS, Go)
+  encrypt     - Generate envelope-encryption helpers for @encrypt fields
+  protobuf    - Generate proto3 messages with field numbers pinned in a lock file
+  sql         - Generate CREATE TABLE statements (postgres, mysql, sqlite)
+  csharp      - Generate C# records with System.Text.Json attributes
+  java        - Generate Java records or classes with Jackson annotations, one file per type
+  dart        - Generate Dart classes with fromJson/toJson
+  php         - Generate PHP 8.1 readonly classes, one PSR-4 file per type
+  haskell     - Generate Haskell data types with aeson instances
+  clojure     - Generate clojure.spec definitions or malli schemas
+  valibot     - Generate Valibot schemas with inferred TypeScript types
+  yup         - Generate Yup schemas for Formik forms
+  joi         - Generate Joi schemas for hapi services
+  effect      - Generate Effect Schema definitions with branded types
+  typebox     - Generate TypeBox schemas for Fastify services
+  mongoose    - Generate Mongoose schemas and models for MongoDB
+  elixir-ecto - Generate Ecto embedded schemas with changesets
+  elixir-absinthe - Generate Absinthe GraphQL object and input types
+  gleam       - Generate Gleam custom types with JSON decoders
+  terraform   - Generate Terraform variables with validation blocks
+  bigquery    - Generate BigQuery JSON table schemas, one file per table
+  arrow       - Generate Apache Arrow schemas for pyarrow, or Parquet message types
+  pyspark     - Generate PySpark StructType schemas
+  jsonld      - Generate a JSON-LD context mapping fields to IRIs
+  html        - Generate a static HTML documentation site
+  c           - Generate a C header with structs, enums and cJSON helpers
+
+Generators also answer to short names: ts (typescript), ex (elixir),
+golang (go), json (jsonschema), proto (protobuf), cs (csharp), hs (haskell),
+clj (clojure), gql (graphql), ecto (elixir-ecto), absinthe (elixir-absinthe),
+tf (terraform), bq (bigquery), spark (pyspark), site (html) and cheader (c),
+e.g. platosl g ts. The short names work in --generators of init too.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runGenUnknown,
 }
 
 var genTypescriptCmd = &cobra.Command{
@@ -44,41 +125,867 @@ var genTypescriptCmd = &cobra.Command{
 	Long: `Generate TypeScript interfaces from CUE definitions.
 
 By default, generates to the output specified in platosl.yaml.
-Use --output to override.`,
+Use --output to override.
+
+A definition that is a disjunction of structs, e.g. #OrderCreated |
+#OrderCancelled, becomes a union type. Literal fields such as kind:
+"order.created" have literal types, so TypeScript narrows the union on them.
+Doc comments, or @description, of definitions and fields become JSDoc, and
+fields with a default are documented with a JSDoc @default tag.
+
+Disjunctions of strings, e.g. "member" | "admin", are enums. --enum-style
+(or options.enumStyle) picks their form, to suit your lint rules:
+  union  literal union, type Role = "member" | "admin" (default)
+  const  const object, Role.Member, and type Role = (typeof Role)[keyof
+         typeof Role]
+  enum   enum Role { Member = "member", Admin = "admin" }
+With const and enum, the enum of a field is named after its interface and
+field, e.g. UserRole.
+
+Definitions of strings, numbers and lists are type aliases. Those tagged
+@brand, e.g. #UserId: string @brand("UserId"), are branded types, string &
+{ readonly __brand: "UserId" }, so IDs of different entities cannot be
+mixed up; --no-brands (or brands: false) leaves them plain. With --readonly
+(or readonly: true), the fields of interfaces are readonly.
+
+With --codecs msgpack,cbor, each interface also gets encode/decode helpers
+(e.g. encodeArticleMsgpack, decodeArticleCbor) using @msgpack/msgpack and
+cbor-x.
+
+With --canonical, each interface also gets a canonicalize function (e.g.
+canonicalizeArticle) returning RFC 8785 canonical JSON, byte-identical to
+the Go generator's CanonicalJSON, for hashing and signing payloads.
+
+With --lenient, each interface also gets a decode function (e.g.
+decodeArticleLenient) for messy upstream data: numbers and booleans given as
+strings are coerced, missing required fields get zero values, and unknown
+fields are returned separately as extras.
+
+With --type-guards, each interface also gets a runtime type guard (e.g.
+isArticle(value): value is Article) checking the kinds, required fields,
+enums, bounds, lengths and patterns of the definition, for code that
+validates untrusted data without a Zod dependency.
+
+With --unicode transliterate or escape, non-ASCII interface names are
+spelled in ASCII; property names keep the exact field names.`,
 	RunE: runGenTypescript,
 }
 
 var genJsonSchemaCmd = &cobra.Command{
 	Use:   "jsonschema",
 	Short: "Generate JSON Schema",
-	Long:  `Generate JSON Schema (draft 2020-12) from CUE definitions.`,
+	Long: `Generate JSON Schema (draft 2020-12) from CUE definitions.
+
+Definitions go into $defs, and references to them, recursive ones
+included, become $ref. Patterns, bounds, lengths, enums, fixed values,
+defaults and required fields become pattern, minimum/maximum,
+minLength/maxLength, enum, const, default and required keywords.
+
+Use --draft (or options.draft) for tools that only accept an older draft:
+2019-09 writes tuples as items and additionalItems, and draft-07 also puts
+definitions under definitions and wraps a $ref with annotations in allOf.
+
+With --split (or options.split), the output is a directory with a schema
+file per definition, e.g. generated/schemas/Person.json, and references
+between definitions are $refs to their files, e.g. {"$ref": "Address.json"}.
+
+Examples:
+  platosl gen jsonschema
+  platosl gen jsonschema --draft draft-07
+  platosl gen jsonschema --split -o generated/schemas`,
 	RunE:  runGenJsonSchema,
 }
 
 var genGoCmd = &cobra.Command{
 	Use:   "go",
 	Short: "Generate Go structs",
-	Long:  `Generate Go struct types with JSON tags from CUE definitions.`,
-	RunE:  runGenGo,
+	Long: `Generate Go struct types with JSON tags from CUE definitions.
+
+With --codecs msgpack,cbor, fields also get msgpack and cbor tags with the
+same names, for vmihailenco/msgpack and fxamacker/cbor.
+
+Fields with a default (role: *"member" | "admin") get a default tag, and
+types with defaults on required fields a New<Name> constructor setting
+them; decode into its result to keep the defaults of missing fields.
+
+With --canonical, each type gets a CanonicalJSON method returning RFC 8785
+canonical JSON (sorted keys, shortest number form), byte-identical to the
+TypeScript generator's canonicalize, for hashing and signing payloads.
+
+With --lenient, each type also gets a Decode<Name>Lenient function for
+messy upstream data: numbers and booleans given as strings are coerced, and
+unknown fields are returned separately as extras.
+
+With --unicode transliterate or escape, non-ASCII names are spelled in
+ASCII in type and field names (Straße becomes Strasse or StraU00DFe); JSON
+tags keep the exact field names.
+
+With --validate, each type gets a Validate method checking the patterns,
+bounds and length limits of its fields. Messages come from @errmsg
+attributes where set:
+
+  email: string & =~"^[^@]+@[^@]+$" @errmsg("Please enter a valid email")
+
+List-typed definitions (#Articles: [...#Article]) become slice types. With
+--streaming, each also gets a reader that decodes one record at a time from
+a JSON array or NDJSON stream, with an optional per-record Validate hook,
+for ingesting large exports without loading them into memory:
+
+  r := types.NewArticlesReader(file)
+  r.Validate = func(i int, a *types.Article) error { ... }
+  for {
+      article, err := r.Next()
+      if err == io.EOF {
+          break
+      }
+      ...
+  }`,
+	RunE: runGenGo,
 }
 
 var genElixirCmd = &cobra.Command{
 	Use:   "elixir",
 	Short: "Generate Elixir typespecs",
-	Long:  `Generate Elixir typespecs and structs from CUE definitions.`,
-	RunE:  runGenElixir,
+	Long: `Generate Elixir typespecs and structs from CUE definitions.
+
+With --codecs msgpack,cbor, the module also gets to_msgpack/from_msgpack
+(Msgpax) and to_cbor/from_cbor (cbor) functions.`,
+	RunE: runGenElixir,
 }
 
 var genZodCmd = &cobra.Command{
 	Use:   "zod",
 	Short: "Generate Zod schemas with TypeScript types",
-	Long:  `Generate Zod validation schemas with inferred TypeScript types from CUE definitions.`,
+	Long: `Generate Zod validation schemas with inferred TypeScript types from CUE definitions.
+
+Constraints become Zod checks: =~ patterns are .regex(), bounds .min(),
+.max(), .gt() and .lt(), list.MinItems and list.MaxItems .min() and .max()
+on arrays, and disjunctions of string literals z.enum() (other literals a
+z.union of z.literal). @errmsg messages are passed to the checks.
+
+A definition that is a disjunction of structs becomes
+z.discriminatedUnion on the field the members tell themselves apart by,
+one every member requires with a string literal of its own, e.g. kind:
+"order.created" | kind: "order.cancelled". Without such a field it is a
+z.union. Literal fields are z.literal(), and fields with a default
+.default(), so parsing fills them in.
+
+References to definitions are their schemas, declared first; in recursive
+definitions, references back up the cycle are z.lazy() and the schemas are
+typed z.ZodTypeAny, as TypeScript cannot infer them.
+
+String length limits count characters as CUE does, by code point, so an
+emoji counts once. With --unicode transliterate or escape, non-ASCII schema
+names are spelled in ASCII; property names keep the exact field names.`,
 	RunE:  runGenZod,
 }
 
+var genGraphQLCmd = &cobra.Command{
+	Use:   "graphql",
+	Short: "Generate GraphQL SDL",
+	Long: `Generate GraphQL object types from CUE definitions.
+
+With --federation, the SDL links Apollo Federation v2 and carries directives
+from attributes, so it can be composed into a supergraph directly:
+
+  #Product: {
+    @key("id")            // @key(fields: "id"); one per argument, or
+                          // @key(fields="id sku", resolvable=false)
+    @shareable()          // type-level @shareable
+    id:    string
+    name:  string @shareable()
+    price: int @external()
+  }
+
+Keys can also be set per type with the 'keys' option in platosl.yaml.`,
+	RunE: runGenGraphQL,
+}
+
+var genTRPCCmd = &cobra.Command{
+	Use:   "trpc",
+	Short: "Generate a tRPC router skeleton",
+	Long: `Generate tRPC procedures for definitions annotated with @rpc.
+
+The annotated definition is the procedure input; input and output schemas are
+imported from the Zod generator output:
+
+  #CreateOrder: {
+    @rpc(mutation, output=#Order)   // name defaults to createOrder
+    customerId: string
+  }
+
+The generated module exports the procedure schemas, a Handlers interface to
+implement, createRouter(handlers) and the AppRouter type.`,
+	RunE: runGenTRPC,
+}
+
+var genEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Generate event publish/subscribe helpers",
+	Long: `Generate typed publish/subscribe helpers for definitions annotated with @event.
+
+Payloads are wrapped in a standard envelope (id, type, source, time, data) and
+validated before publishing and after receiving:
+
+  #OrderCreated: {
+    @event(subject="orders.created")                   // NATS
+    @event(bus=orders, source=shop, detailType=Created) // EventBridge
+    orderId: string
+  }
+
+Unset names default to a subject derived from the definition name
+(order.created), the 'bus' option, the 'source' option or project name, and the
+definition name as detail type. Go helpers expect the payload types in the same
+package (gen go); TypeScript helpers validate with the gen zod schemas.`,
+	RunE: runGenEvents,
+}
+
+var genFlagsCmd = &cobra.Command{
+	Use:   "flags",
+	Short: "Generate typed feature flag / settings accessors",
+	Long: `Generate a typed accessor layer for the settings definition, so runtime
+configuration access is schema-checked.
+
+The settings definition is the one marked with @flags(), or the one named by
+--definition. Flag keys default to field names; override with @flag(key=...).
+
+  typescript  settings interface, defaults and a typed client for
+              LaunchDarkly (allFlags) and ConfigCat (getAllValuesAsync) payloads
+  go          getters with schema defaults over a map[string]interface{} payload`,
+	RunE: runGenFlags,
+}
+
+var genACLCmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Export field-level access control rules",
+	Long: `Export field-level access rules declared with @acl(read=..., write=...)
+so authorization layers can derive field rules from the schema. Roles are
+separated by "|"; an @acl on a definition is the default for its fields.
+
+Formats:
+  opa         OPA data document ({"acl": {Resource: {field: {read, write}}}})
+  rego        Rego module with the rules and can_read / can_write helpers
+  casbin      Casbin policy lines (p, role, Resource.field, read|write)
+  typescript  constant map with a canAccess helper
+  go          ACL map with a CanAccess helper`,
+	RunE: runGenACL,
+}
+
+var genEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Generate envelope-encryption helpers for @encrypt fields",
+	Long: `Generate helpers for fields encrypted at rest, declared in the schema with
+@encrypt("<kms key alias>").
+
+Formats:
+  go    EncryptedFields map, a KeyService interface for your KMS and
+        Encrypt<Type> / Decrypt<Type> helpers applying AES-256-GCM envelope
+        encryption to JSON documents (map[string]interface{})
+  json  encrypted field paths and key aliases per definition`,
+	RunE: runGenEncrypt,
+}
+
+var genProtobufCmd = &cobra.Command{
+	Use:   "protobuf",
+	Short: "Generate proto3 messages",
+	Long: `Generate proto3 messages from CUE definitions.
+
+Field and enum value numbers are persisted in a lock file (default
+platosl.proto.lock) so they stay stable across runs: existing fields keep
+their numbers, new fields get the next unused number, and removed fields are
+emitted as reserved so their numbers are never reused. Commit the lock file
+with the schema.
+
+Mappings:
+  string, bytes, bool     string, bytes, bool
+  int / float, number     int64 / double
+  "a" | "b"               nested enum (with an _UNSPECIFIED zero value)
+  [...T]                  repeated T
+  [string]: T             map<string, T>
+  #Def / {...}            message / nested message
+  null | T, field?: T     optional T
+
+Use --frozen-lock in CI to fail instead of updating the lock file.`,
+	RunE: runGenProtobuf,
+}
+
+var genSQLCmd = &cobra.Command{
+	Use:   "sql",
+	Short: "Generate SQL CREATE TABLE statements",
+	Long: `Generate one CREATE TABLE statement per CUE definition, to bootstrap
+migrations from the schema.
+
+Columns are snake_case. Optional fields and fields that allow null are
+nullable; all others are NOT NULL. Concrete defaults become DEFAULT values,
+a column named id becomes the primary key, and string enums become ENUM
+columns (mysql) or CHECK constraints (postgres, sqlite). Structs, lists and
+references to other definitions are stored as JSON.
+
+Override column types per type kind (string, int, float, bool, bytes, enum,
+json) or per column (Table.field, using the definition and CUE field names):
+
+  platosl gen sql --dialect mysql --type string=TEXT --type Order.total="DECIMAL(12,2)"`,
+	RunE: runGenSQL,
+}
+
+var genCSharpCmd = &cobra.Command{
+	Use:   "csharp",
+	Short: "Generate C# records",
+	Long: `Generate one C# record per CUE definition, with System.Text.Json
+attributes so the records read and write the same JSON as the schema.
+
+Properties are PascalCase with [JsonPropertyName] keeping the CUE field
+name. Required fields use the required modifier; optional fields are
+nullable and omitted from JSON when null. String enums become enums with a
+converter that keeps their original strings, and anonymous structs become
+records named after their parent, e.g. ArticleAuthor. Requires C# 11
+(.NET 7 or later).
+
+The namespace comes from --namespace, options.namespace, or the project name.`,
+	RunE: runGenCSharp,
+}
+
+var genJavaCmd = &cobra.Command{
+	Use:   "java",
+	Short: "Generate Java records or classes",
+	Long: `Generate one Java file per CUE definition, with Jackson annotations so the
+types read and write the same JSON as the schema.
+
+The output is a source directory: files are written to the package's
+directory below it, e.g. generated/java/com/acme/types/Article.java, and
+files generated earlier for removed definitions are deleted.
+
+For Java 16 and later (the default target is 17), definitions become
+records. With --target 11, they become immutable classes with a Jackson
+creator, getters, equals, hashCode and toString. String enums become enums,
+and anonymous structs become nested types.
+
+Examples:
+  platosl gen java --package com.acme.types
+  platosl gen java --package com.acme.types --target 11 -o src/main/java`,
+	RunE: runGenJava,
+}
+
+var genDartCmd = &cobra.Command{
+	Use:   "dart",
+	Short: "Generate Dart classes",
+	Long: `Generate Dart classes with fromJson/toJson from CUE definitions.
+
+Each definition becomes a class with final fields and a const constructor;
+required fields are required named parameters and optional fields are
+nullable and left out of toJson when null. Fields are camelCase and keep the
+CUE field name in JSON. String enums become enhanced enums that keep their
+original strings, and anonymous structs become classes named after their
+parent, e.g. ArticleAuthor.
+
+By default fromJson/toJson are written out, so no build step is needed.
+With --json-serializable, classes are annotated for json_serializable
+instead and delegate to the generated part file (types.g.dart for
+types.dart); run build_runner after generating:
+
+  platosl gen dart --json-serializable -o lib/models/types.dart
+  dart run build_runner build`,
+	RunE: runGenDart,
+}
+
+var genPHPCmd = &cobra.Command{
+	Use:   "php",
+	Short: "Generate PHP classes",
+	Long: `Generate one PHP 8.1+ class per CUE definition, with readonly promoted
+constructor properties, a fromArray factory and JsonSerializable.
+
+The output is the PSR-4 base directory of the namespace: each class is
+written to <output>/<Class>.php, and files generated earlier for removed
+definitions are deleted. Map the namespace to the directory in composer.json:
+
+  "autoload": { "psr-4": { "App\\Schemas\\": "app/Schemas/" } }
+
+Properties are camelCase and keep the CUE field name in fromArray and
+jsonSerialize. Optional fields are nullable, default to null and are left
+out of JSON when null. String enums become string-backed enums, and
+anonymous structs become classes named after their parent, e.g.
+ArticleAuthor.
+
+Examples:
+  platosl gen php --namespace 'App\Schemas' -o app/Schemas`,
+	RunE: runGenPHP,
+}
+
+var genHaskellCmd = &cobra.Command{
+	Use:   "haskell",
+	Short: "Generate Haskell data types",
+	Long: `Generate a Haskell module with a data type per CUE definition and aeson
+FromJSON/ToJSON instances derived via Generic.
+
+Record fields are prefixed with the type name (personName) and mapped back
+to the CUE field names by the instances. Optional and nullable fields are
+Maybe, and are left out of JSON when Nothing. String enums become sum types
+encoded as their strings, list-typed definitions become type synonyms and
+anonymous structs become records named after their parent, e.g.
+ArticleAuthor.
+
+The module needs the aeson, containers and text packages.
+
+Examples:
+  platosl gen haskell --module Api.Types -o src/Api/Types.hs`,
+	RunE: runGenHaskell,
+}
+
+var genClojureCmd = &cobra.Command{
+	Use:   "clojure",
+	Short: "Generate clojure.spec definitions or malli schemas",
+	Long: `Generate a Clojure namespace that validates payloads against the CUE
+definitions, with clojure.spec (default) or malli (--style malli).
+
+With spec, each definition gets a spec named after it (::user for #User),
+structs become s/keys specs with unqualified keys, and bounds, lengths and
+patterns become predicates. With malli, the namespace has a registry of
+schemas keyed the same way and a var per definition (User) holding the
+compiled schema.
+
+Both check maps with keyword keys, as read by
+(json/read-str s :key-fn keyword). The output is a .cljc file, so it
+works from Clojure and ClojureScript.
+
+Examples:
+  platosl gen clojure --namespace my-app.schemas -o src/my_app/schemas.cljc
+  platosl gen clojure --style malli`,
+	RunE: runGenClojure,
+}
+
+var genValibotCmd = &cobra.Command{
+	Use:   "valibot",
+	Short: "Generate Valibot schemas with TypeScript types",
+	Long: `Generate Valibot schemas with inferred TypeScript types from CUE
+definitions, a lighter-weight alternative to gen zod.
+
+Each definition becomes a v.object schema (UserSchema) and a type inferred
+from it (User). Bounds, lengths and patterns become actions in a v.pipe,
+e.g. v.pipe(v.string(), v.minLength(3), v.regex(/^[a-z]+$/)), carrying the
+@errmsg messages. Fields with defaults become v.optional with the default,
+and recursive definitions refer to themselves through v.lazy.
+
+Examples:
+  platosl gen valibot -o src/schemas.ts`,
+	RunE: runGenValibot,
+}
+
+var genYupCmd = &cobra.Command{
+	Use:   "yup",
+	Short: "Generate Yup schemas with TypeScript types",
+	Long: `Generate Yup validation schemas with inferred TypeScript types from CUE
+definitions, e.g. as the validationSchema of Formik forms.
+
+Each definition becomes a yup.object schema (UserSchema) and a type inferred
+from it (User). Regular fields are .required(), optional fields are not, and
+fields with defaults get .default(). Bounds, lengths and patterns become
+.min, .max, .moreThan, .lessThan and .matches tests, carrying the @errmsg
+messages. Yup has no unions, so other disjunctions are checked with a test
+accepting values valid against any branch.
+
+Examples:
+  platosl gen yup -o src/forms/schemas.ts`,
+	RunE: runGenYup,
+}
+
+var genJoiCmd = &cobra.Command{
+	Use:   "joi",
+	Short: "Generate Joi schemas",
+	Long: `Generate a JavaScript module with a Joi schema per CUE definition, e.g.
+for the route validation of hapi services.
+
+Regular fields are .required(), fields with defaults get .default() and
+field comments become .description(). Patterns become .pattern(), bounds
+.min(), .max(), .greater() and .less(), and string and list lengths .min()
+and .max(), with @errmsg messages in .messages(). Strings allow "" unless
+they have a minimum length, as CUE does, and closed definitions reject
+unknown keys.
+
+The module is CommonJS by default; --module esm writes ES module exports.
+
+Examples:
+  platosl gen joi -o lib/schemas.js
+  platosl gen joi --module esm -o src/schemas.mjs`,
+	RunE: runGenJoi,
+}
+
+var genEffectCmd = &cobra.Command{
+	Use:   "effect",
+	Short: "Generate Effect Schema definitions",
+	Long: `Generate a TypeScript module with an Effect Schema.Struct and a type per
+CUE definition, for backends built on Effect.
+
+Patterns become Schema.pattern, bounds Schema.greaterThanOrEqualTo,
+Schema.lessThanOrEqualTo, Schema.greaterThan and Schema.lessThan, and string
+and list lengths Schema.minLength, Schema.maxLength, Schema.minItems and
+Schema.maxItems, with @errmsg messages. Fields with defaults decode with
+Schema.optionalWith, and definitions that refer to each other are
+Schema.suspend'ed.
+
+Definitions of a string or number, e.g. #UserId: string & =~"^u_", become
+branded types with Schema.brand so they cannot be mixed up with plain
+strings; --no-brands leaves them plain.
+
+Schema is imported from "effect"; --package @effect/schema imports it from
+the standalone package instead.
+
+Examples:
+  platosl gen effect -o src/domain/schemas.ts
+  platosl gen effect --package @effect/schema --no-brands`,
+	RunE: runGenEffect,
+}
+
+var genTypeBoxCmd = &cobra.Command{
+	Use:   "typebox",
+	Short: "Generate TypeBox schemas",
+	Long: `Generate a TypeScript module with a TypeBox schema and a Static type per
+CUE definition. The schemas are JSON Schema, so Fastify validates requests
+with them at runtime while the types check handlers at compile time.
+
+Constraints become JSON Schema keywords (pattern, minimum, maximum,
+exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems and
+maxItems), with @errmsg messages in errorMessage for ajv-errors. Field
+comments become descriptions, defaults become default, and closed
+definitions set additionalProperties: false.
+
+Definitions that refer to themselves are Type.Recursive, and definitions in
+a cycle get an $id the others reference with Type.Ref; register them with
+fastify.addSchema.
+
+Examples:
+  platosl gen typebox -o src/schemas.ts`,
+	RunE: runGenTypeBox,
+}
+
+var genMongooseCmd = &cobra.Command{
+	Use:   "mongoose",
+	Short: "Generate Mongoose schemas",
+	Long: `Generate a JavaScript module with a Mongoose schema per CUE struct
+definition, for persisting the same shapes in MongoDB.
+
+Regular fields are required: true, and fields with defaults get default.
+Patterns become match, bounds min and max, string lengths minLength and
+maxLength, and literal enums enum, with @errmsg messages. Integers,
+exclusive bounds of floats and list lengths are checked by validators.
+Definitions of other types, such as enums, are inlined where they are used,
+and nested structs become subdocuments.
+
+Definitions no other definition uses become models; --models names them
+instead. The module is CommonJS by default; --module esm writes ES module
+exports.
+
+Examples:
+  platosl gen mongoose -o src/models.js
+  platosl gen mongoose --models User,Order --module esm -o src/models.mjs`,
+	RunE: runGenMongoose,
+}
+
+var genEctoCmd = &cobra.Command{
+	Use:   "elixir-ecto",
+	Short: "Generate Ecto embedded schemas",
+	Long: `Generate an Elixir module with an Ecto embedded_schema, a typespec and
+a changeset function per CUE struct definition, so params are cast and
+validated like the schemas describe.
+
+Regular fields are cast and listed in validate_required, unless they may
+be null or have a default. Bounds become validate_number, string and list
+lengths validate_length, patterns validate_format and non-string literal
+enums validate_inclusion, with @errmsg messages. String enums become
+Ecto.Enum fields. References to struct definitions and nested structs
+become embeds_one and embeds_many, cast with cast_embed.
+
+Modules are named after the definitions under --module (default
+MyApp.Schemas); nested structs get a module inside their parent's.
+
+Examples:
+  platosl gen elixir-ecto -o lib/my_app/schemas.ex
+  platosl gen ecto --module Shop.Schemas -o lib/shop/schemas.ex`,
+	RunE: runGenEcto,
+}
+
+var genAbsintheCmd = &cobra.Command{
+	Use:   "elixir-absinthe",
+	Short: "Generate Absinthe GraphQL types",
+	Long: `Generate an Elixir module of Absinthe type notation: an object and an
+input_object per CUE struct definition, and an enum per string enum, so an
+Elixir GraphQL API serves the same types as the shared schemas.
+
+Required fields are non_null; optional fields and fields that may be null
+are nullable, and input fields with a default get a default_value. Field
+identifiers are snake_case; fields whose GraphQL name Absinthe would not
+derive from the identifier keep their CUE name with name:. Enum values keep
+their CUE strings as internal values. Maps and disjunctions of different
+kinds use a JSON scalar declared in the module (parsed with Jason).
+
+Import the types into a schema with import_types. --no-inputs skips the
+input objects.
+
+Examples:
+  platosl gen elixir-absinthe -o lib/my_app_web/schema/types.ex
+  platosl gen absinthe --module ShopWeb.Schema.Types -o lib/shop_web/schema/types.ex`,
+	RunE: runGenAbsinthe,
+}
+
+var genGleamCmd = &cobra.Command{
+	Use:   "gleam",
+	Short: "Generate Gleam types and JSON decoders",
+	Long: `Generate a Gleam module with a custom type per CUE definition and a
+decoder for it, written with gleam/dynamic/decode, plus a
+<type>_from_json function parsing a JSON string with gleam_json.
+
+Struct definitions become records, string enums custom types with a
+constructor per value, and nested structs records of their own. Optional
+fields and fields that may be null are Options; fields with a default
+decode to it when the key is missing. Decoders of recursive types are
+built lazily with decode.recursive.
+
+The module name is the file name, so choose the output path accordingly.
+
+Examples:
+  platosl gen gleam -o src/schemas.gleam`,
+	RunE: runGenGleam,
+}
+
+var genTerraformCmd = &cobra.Command{
+	Use:   "terraform",
+	Short: "Generate Terraform variables",
+	Long: `Generate a Terraform variable block per CUE struct definition, so
+infrastructure modules accept the same contracts as the services using them.
+
+Each variable's type is an object type expression. Optional fields and
+fields with a default become optional attributes (Terraform 1.3+), with the
+default; definitions are inlined where they are referenced, as Terraform
+has no named types. Disjunctions of different kinds, and references back to
+a definition being inlined, are typed any.
+
+Constraints become validation blocks: enums check contains(), bounds and
+lengths compare, patterns use regex(), and int fields must be whole
+numbers. Checks of optional and nullable attributes apply when they are
+set, and checks of list items and map values to each of them. @errmsg sets
+the error messages. Variables with @sensitive or @pii fields are sensitive.
+--no-validations leaves out the validation blocks.
+
+Examples:
+  platosl gen terraform -o modules/shop/variables.tf
+  platosl gen tf --no-validations`,
+	RunE: runGenTerraform,
+}
+
+var genBigQueryCmd = &cobra.Command{
+	Use:   "bigquery",
+	Short: "Generate BigQuery table schemas",
+	Long: `Generate a BigQuery JSON table schema per table definition, in the form
+bq mk, bq load and the BigQuery API take, so warehouse loaders create tables
+matching the services writing the data. Each table is written to
+<output>/<table>.json, named in snake_case after its definition.
+
+Definitions no other definition uses become tables; --tables names them
+instead. Referenced definitions and nested structs become RECORD columns,
+lists REPEATED columns, and maps, disjunctions of different kinds, nested
+lists and references back to a definition being inlined JSON columns.
+Required fields without a default are REQUIRED, other fields NULLABLE.
+
+Doc comments become descriptions, string length limits maxLength, and
+defaults defaultValueExpression. Set a column type CUE has no kind for with
+@bigquery, e.g. createdAt: string @bigquery(TIMESTAMP).
+
+Examples:
+  platosl gen bigquery -o warehouse/schemas
+  platosl gen bq --tables Order,Customer`,
+	RunE: runGenBigQuery,
+}
+
+var genArrowCmd = &cobra.Command{
+	Use:   "arrow",
+	Short: "Generate Apache Arrow or Parquet schemas",
+	Long: `Generate an Apache Arrow schema per CUE struct definition, as a Python
+module of pyarrow schemas, so data pipelines read and write files with the
+declared types instead of inferring them from samples. The module has a
+constant per schema, e.g. ORDER_SCHEMA, and a SCHEMAS dict by definition
+name. With --parquet, Parquet message types are generated instead.
+
+Required fields are not nullable; optional fields and fields that may be
+null are. Lists become list types, maps map types with string keys, string
+enums dictionary types, and referenced definitions and nested structs
+struct types. Disjunctions of different kinds and references back to a
+definition being inlined are JSON-encoded strings. Titles and descriptions
+become field metadata.
+
+Set a type CUE has no kind for with @arrow: int8 to int64, uint8 to
+uint64, float32, float64, timestamp (microseconds, UTC), date, time,
+large_string, or decimal with a precision and scale, e.g.
+@arrow(decimal, precision=12, scale=2).
+
+Examples:
+  platosl gen arrow -o pipeline/schemas.py
+  platosl gen arrow --parquet -o pipeline/order.parquet.schema`,
+	RunE: runGenArrow,
+}
+
+var genPySparkCmd = &cobra.Command{
+	Use:   "pyspark",
+	Short: "Generate PySpark StructType schemas",
+	Long: `Generate a PySpark StructType per CUE struct definition, as a Python
+module, so Spark jobs read event payloads with the declared schema instead
+of inferring it. The module has a constant per schema, e.g. ORDER_SCHEMA,
+and a SCHEMAS dict by definition name:
+
+  df = spark.read.schema(ORDER_SCHEMA).json("s3://events/orders/")
+
+Required fields are not nullable; optional fields and fields that may be
+null are. Ints become LongType, floats DoubleType, lists ArrayType, maps
+MapType with string keys, and referenced definitions and nested structs
+StructType. Enums, disjunctions of different kinds and references back to
+a definition being inlined are strings. Descriptions become the comment
+of a field's metadata, and titles its title.
+
+Set a type CUE has no kind for with @spark: byte, short, integer, long,
+float, double, date, timestamp, timestamp_ntz, binary, or decimal with a
+precision and scale, e.g. @spark(decimal, precision=12, scale=2).
+
+Examples:
+  platosl gen pyspark -o jobs/schemas.py
+  platosl g spark`,
+	RunE: runGenPySpark,
+}
+
+var genJSONLDCmd = &cobra.Command{
+	Use:   "jsonld",
+	Short: "Generate a JSON-LD context",
+	Long: `Generate a JSON-LD @context document that maps the field names of CUE
+definitions to IRIs, so content published as JSON is also linked data.
+
+Map a field with @jsonld and the IRI of its property, absolute or compact:
+
+  #Person: {
+  	@jsonld(schema:Person)
+  	id:    string @jsonld("@id")
+  	name:  string @jsonld(schema:name)
+  	born?: string @jsonld(schema:birthDate, type=xsd:date)
+  	knows: [...string] @jsonld(foaf:knows, type="@id", container="@set")
+  	notes: string @jsonld(-)
+  }
+
+type coerces values to a datatype IRI, "@id" (IRI references) or "@json";
+container is "@list", "@set", "@language" or "@index". "@id" and "@type"
+make a field an alias of the keyword, and - leaves it out of the linked
+data. @jsonld in the body of a definition names its type.
+
+Prefixes such as schema, xsd, rdf, rdfs, owl, foaf, dcterms and skos are
+declared when used; declare others with --prefix. Fields without @jsonld
+are mapped by --vocab, when set. A field mapped differently by two
+definitions is scoped to the type of the second (JSON-LD 1.1).
+
+Examples:
+  platosl gen jsonld -o public/context.jsonld
+  platosl gen jsonld --vocab https://example.com/vocab# --prefix ex=https://example.com/ns/`,
+	RunE: runGenJSONLD,
+}
+
+var genHTMLCmd = &cobra.Command{
+	Use:   "html",
+	Short: "Generate a static HTML documentation site",
+	Long: `Generate a static HTML site documenting the CUE definitions, so the
+consumers of a schema can browse its contracts without reading CUE.
+
+The site has an index of the definitions and a page per definition,
+written to <output>/<Definition>.html. Pages list the fields of structs,
+with nested structs indented under their parent, or the values of enums.
+Each field shows its type, whether it is required, its default, its
+constraints, and its @title and @description or doc comment. References
+to definitions are links, and each page lists the fields using it.
+
+The search box finds definitions and fields by name and description. The
+index is in search.js next to the pages, so search works when the site is
+opened from disk as well as when served. Pages of removed definitions are
+deleted.
+
+Examples:
+  platosl gen html -o docs/schemas
+  platosl gen site --title "Orders API"`,
+	RunE: runGenHTML,
+}
+
+var genCCmd = &cobra.Command{
+	Use:   "c",
+	Short: "Generate a C header",
+	Long: `Generate a C99 header with a struct per struct definition, an enum per
+string enum and a typedef per scalar definition, for firmware and other
+C code exchanging the same messages.
+
+Integers use the smallest <stdint.h> type their bounds allow, or int64_t.
+Strings are char * owned by the struct. Lists are a pointer and a
+<field>_count, maps parallel <field>_keys and <field>_values arrays.
+Optional and nullable fields have a has_<field> flag, or are pointers
+when structs. Values C cannot type, such as unions, are kept as JSON text.
+Enums get <enum>_to_string and <enum>_from_string.
+
+With --cjson, the header also declares <type>_to_json, <type>_from_json
+and <type>_free, built on cJSON. The header is single-file: define
+<GUARD>_IMPLEMENTATION in one .c file before including it to compile the
+helpers there.
+
+Examples:
+  platosl gen c -o firmware/include/schemas.h --prefix shop_
+  platosl gen cheader --cjson --cjson-include "<cjson/cJSON.h>"`,
+	RunE: runGenC,
+}
+
 var (
 	genGoPackage     string
+	genGoStreaming   bool
+	genGoValidate    bool
 	genElixirModule  string
+	genFlagsLanguage   string
+	genFlagsDefinition string
+	genFlagsPackage    string
+	genACLFormat       string
+	genACLPackage      string
+	genEncryptFormat   string
+	genEncryptPackage  string
+	genGraphQLFederation bool
+	genTRPCZodImport     string
+	genEventsLanguage    string
+	genEventsTransport   string
+	genEventsPackage     string
+	genProtobufPackage   string
+	genProtobufGoPackage string
+	genProtobufLockFile  string
+	genProtobufFrozen    bool
+	genSQLDialect        string
+	genSQLTypes          []string
+	genCSharpNamespace   string
+	genJavaPackage       string
+	genJavaTarget        int
+	genDartSerializable  bool
+	genPHPNamespace      string
+	genHaskellModule     string
+	genClojureNamespace  string
+	genClojureStyle      string
+	genTSEnumStyle       string
+	genTSReadonly        bool
+	genTSNoBrands        bool
+	genJoiModule         string
+	genEffectPackage     string
+	genEffectNoBrands    bool
+	genMongooseModule    string
+	genMongooseModels    []string
+	genEctoModule        string
+	genAbsintheModule    string
+	genAbsintheNoInputs  bool
+	genTerraformNoChecks bool
+	genBigQueryTables    []string
+	genJSONLDVocab       string
+	genJSONLDBase        string
+	genJSONLDPrefixes    map[string]string
+	genHTMLTitle         string
+	genCPrefix           string
+	genCGuard            string
+	genCJSON             bool
+	genCJSONInclude      string
+	genArrowParquet      bool
+	genJsonSchemaDraft   string
+	genJsonSchemaSplit   bool
 )
 
 func init() {
@@ -88,23 +995,211 @@ func init() {
 	genCmd.AddCommand(genGoCmd)
 	genCmd.AddCommand(genElixirCmd)
 	genCmd.AddCommand(genZodCmd)
+	genCmd.AddCommand(genGraphQLCmd)
+	genCmd.AddCommand(genTRPCCmd)
+	genCmd.AddCommand(genEventsCmd)
+	genCmd.AddCommand(genFlagsCmd)
+	genCmd.AddCommand(genACLCmd)
+	genCmd.AddCommand(genEncryptCmd)
+	genCmd.AddCommand(genProtobufCmd)
+	genCmd.AddCommand(genSQLCmd)
+	genCmd.AddCommand(genCSharpCmd)
+	genCmd.AddCommand(genJavaCmd)
+	genCmd.AddCommand(genDartCmd)
+	genCmd.AddCommand(genPHPCmd)
+	genCmd.AddCommand(genHaskellCmd)
+	genCmd.AddCommand(genClojureCmd)
+	genCmd.AddCommand(genValibotCmd)
+	genCmd.AddCommand(genYupCmd)
+	genCmd.AddCommand(genJoiCmd)
+	genCmd.AddCommand(genEffectCmd)
+	genCmd.AddCommand(genTypeBoxCmd)
+	genCmd.AddCommand(genMongooseCmd)
+	genCmd.AddCommand(genEctoCmd)
+	genCmd.AddCommand(genAbsintheCmd)
+	genCmd.AddCommand(genGleamCmd)
+	genCmd.AddCommand(genTerraformCmd)
+	genCmd.AddCommand(genBigQueryCmd)
+	genCmd.AddCommand(genArrowCmd)
+	genCmd.AddCommand(genPySparkCmd)
+	genCmd.AddCommand(genJSONLDCmd)
+	genCmd.AddCommand(genHTMLCmd)
+	genCmd.AddCommand(genCCmd)
+
+	// Generator aliases double as subcommand aliases, e.g. gen ts
+	for _, sub := range genCmd.Commands() {
+		sub.Aliases = append(sub.Aliases, generator.Aliases(sub.Name())...)
+	}
 
 	// TypeScript flags
 	genTypescriptCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genTypescriptCmd.Flags().StringSliceVar(&genCodecs, "codecs", nil, "binary codec helpers to generate: msgpack, cbor")
+	genTypescriptCmd.Flags().BoolVar(&genCanonical, "canonical", false, "generate canonical JSON (RFC 8785) helpers for hashing and signing")
+	genTypescriptCmd.Flags().BoolVar(&genLenient, "lenient", false, "generate lenient decoders that coerce loosely formed JSON")
+	genTypescriptCmd.Flags().BoolVar(&genTypeGuards, "type-guards", false, "generate runtime type guards (isName) implementing the CUE constraints")
+	genTypescriptCmd.Flags().StringVar(&genTSEnumStyle, "enum-style", "", "disjunctions of strings as: union, const, enum (default union)")
+	genTypescriptCmd.Flags().BoolVar(&genTSReadonly, "readonly", false, "mark the fields of interfaces readonly")
+	genTypescriptCmd.Flags().BoolVar(&genTSNoBrands, "no-brands", false, "leave definitions tagged @brand unbranded")
+	genTypescriptCmd.Flags().StringVar(&genUnicode, "unicode", "", "non-ASCII names in identifiers: keep, transliterate, escape (default keep)")
 
 	// JSON Schema flags
-	genJsonSchemaCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genJsonSchemaCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path, or directory with --split")
+	genJsonSchemaCmd.Flags().StringVar(&genJsonSchemaDraft, "draft", "", "JSON Schema draft: 2020-12, 2019-09, draft-07 (default: 2020-12)")
+	genJsonSchemaCmd.Flags().BoolVar(&genJsonSchemaSplit, "split", false, "write a schema file per definition into the output directory")
 
 	// Go flags
 	genGoCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
 	genGoCmd.Flags().StringVar(&genGoPackage, "package", "", "Go package name")
+	genGoCmd.Flags().BoolVar(&genGoStreaming, "streaming", false, "generate streaming JSON/NDJSON readers for list-typed definitions")
+	genGoCmd.Flags().StringSliceVar(&genCodecs, "codecs", nil, "binary codec struct tags to generate: msgpack, cbor")
+	genGoCmd.Flags().BoolVar(&genCanonical, "canonical", false, "generate canonical JSON (RFC 8785) methods for hashing and signing")
+	genGoCmd.Flags().BoolVar(&genLenient, "lenient", false, "generate lenient decoders that coerce loosely formed JSON")
+	genGoCmd.Flags().BoolVar(&genGoValidate, "validate", false, "generate Validate methods from schema constraints")
+	genGoCmd.Flags().StringVar(&genUnicode, "unicode", "", "non-ASCII names in identifiers: keep, transliterate, escape (default keep)")
 
 	// Elixir flags
 	genElixirCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
 	genElixirCmd.Flags().StringVar(&genElixirModule, "module", "", "Elixir module name")
+	genElixirCmd.Flags().StringSliceVar(&genCodecs, "codecs", nil, "binary codec functions to generate: msgpack, cbor")
 
 	// Zod flags
 	genZodCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genZodCmd.Flags().StringVar(&genUnicode, "unicode", "", "non-ASCII names in identifiers: keep, transliterate, escape (default keep)")
+
+	// GraphQL flags
+	genGraphQLCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genGraphQLCmd.Flags().BoolVar(&genGraphQLFederation, "federation", false, "emit Apollo Federation v2 directives")
+
+	// tRPC flags
+	genTRPCCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genTRPCCmd.Flags().StringVar(&genTRPCZodImport, "zod-import", "", "module path of the Zod schemas (default \"./schemas\")")
+
+	// Event flags
+	genEventsCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genEventsCmd.Flags().StringVar(&genEventsLanguage, "language", "", "helper language: go (default), typescript")
+	genEventsCmd.Flags().StringVar(&genEventsTransport, "transport", "", "event transport: nats (default), eventbridge")
+	genEventsCmd.Flags().StringVar(&genEventsPackage, "package", "", "Go package name (default \"events\")")
+
+	// Feature flag flags
+	genFlagsCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genFlagsCmd.Flags().StringVar(&genFlagsLanguage, "language", "", "target language (typescript, go)")
+	genFlagsCmd.Flags().StringVar(&genFlagsDefinition, "definition", "", "settings definition (default: the one marked @flags())")
+	genFlagsCmd.Flags().StringVar(&genFlagsPackage, "package", "", "Go package name")
+
+	// Access control flags
+	genACLCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genACLCmd.Flags().StringVar(&genACLFormat, "format", "", "output format (opa, rego, casbin, typescript, go)")
+	genACLCmd.Flags().StringVar(&genACLPackage, "package", "", "Rego or Go package name")
+
+	// Encryption flags
+	genEncryptCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genEncryptCmd.Flags().StringVar(&genEncryptFormat, "format", "", "output format (go, json)")
+	genEncryptCmd.Flags().StringVar(&genEncryptPackage, "package", "", "Go package name")
+
+	// Protobuf flags
+	genProtobufCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genProtobufCmd.Flags().StringVar(&genProtobufPackage, "package", "", "proto package (default: project name)")
+	genProtobufCmd.Flags().StringVar(&genProtobufGoPackage, "go-package", "", "go_package option")
+	genProtobufCmd.Flags().StringVar(&genProtobufLockFile, "lock-file", "", "field numbering lock file (default \"platosl.proto.lock\")")
+	genProtobufCmd.Flags().BoolVar(&genProtobufFrozen, "frozen-lock", false, "fail instead of updating the lock file")
+
+	// SQL flags
+	genSQLCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genSQLCmd.Flags().StringVar(&genSQLDialect, "dialect", "", "SQL dialect: postgres, mysql, sqlite (default \"postgres\")")
+	genSQLCmd.Flags().StringArrayVar(&genSQLTypes, "type", nil, "column type override, e.g. string=TEXT or Order.total=NUMERIC(12,2) (repeatable)")
+
+	// C# flags
+	genCSharpCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genCSharpCmd.Flags().StringVar(&genCSharpNamespace, "namespace", "", "C# namespace (default: project name)")
+
+	// Java flags
+	genJavaCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output source directory")
+	genJavaCmd.Flags().StringVar(&genJavaPackage, "package", "", "Java package (default: project name)")
+	genJavaCmd.Flags().IntVar(&genJavaTarget, "target", 0, "Java release; below 16 generates classes instead of records (default 17)")
+
+	// Dart flags
+	genDartCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genDartCmd.Flags().BoolVar(&genDartSerializable, "json-serializable", false, "annotate classes for json_serializable instead of writing fromJson/toJson")
+
+	// PHP flags
+	genPHPCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output directory (PSR-4 base directory of the namespace)")
+	genPHPCmd.Flags().StringVar(&genPHPNamespace, "namespace", "", "PHP namespace, e.g. App\\Schemas (default: project name)")
+
+	// Haskell flags
+	genHaskellCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genHaskellCmd.Flags().StringVar(&genHaskellModule, "module", "", "Haskell module name (default: Types)")
+
+	// Clojure flags
+	genClojureCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genClojureCmd.Flags().StringVar(&genClojureNamespace, "namespace", "", "Clojure namespace (default: platosl.types)")
+	genClojureCmd.Flags().StringVar(&genClojureStyle, "style", "", "validation library: spec, malli (default: spec)")
+
+	// Valibot flags
+	genValibotCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+
+	// Yup flags
+	genYupCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+
+	// Joi flags
+	genJoiCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genJoiCmd.Flags().StringVar(&genJoiModule, "module", "", "module format: commonjs, esm (default: commonjs)")
+
+	// Effect flags
+	genEffectCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genEffectCmd.Flags().StringVar(&genEffectPackage, "package", "", "package to import Schema from (default: effect)")
+	genEffectCmd.Flags().BoolVar(&genEffectNoBrands, "no-brands", false, "leave scalar definitions unbranded")
+
+	// TypeBox flags
+	genTypeBoxCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+
+	// Mongoose flags
+	genMongooseCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genMongooseCmd.Flags().StringVar(&genMongooseModule, "module", "", "module format: commonjs, esm (default: commonjs)")
+	genMongooseCmd.Flags().StringSliceVar(&genMongooseModels, "models", nil, "definitions to compile into models (default: definitions no other definition uses)")
+
+	// Ecto flags
+	genEctoCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genEctoCmd.Flags().StringVar(&genEctoModule, "module", "", "module name prefix (default: MyApp.Schemas)")
+
+	// Absinthe flags
+	genAbsintheCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genAbsintheCmd.Flags().StringVar(&genAbsintheModule, "module", "", "module name (default: MyAppWeb.Schema.Types)")
+	genAbsintheCmd.Flags().BoolVar(&genAbsintheNoInputs, "no-inputs", false, "skip input objects")
+
+	// Gleam flags
+	genGleamCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+
+	// Terraform flags
+	genTerraformCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genTerraformCmd.Flags().BoolVar(&genTerraformNoChecks, "no-validations", false, "skip validation blocks")
+
+	// BigQuery flags
+	genBigQueryCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output directory")
+	genBigQueryCmd.Flags().StringSliceVar(&genBigQueryTables, "tables", nil, "definitions to generate tables for (default: definitions no other definition uses)")
+
+	// Arrow flags
+	genArrowCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genArrowCmd.Flags().BoolVar(&genArrowParquet, "parquet", false, "generate Parquet message types instead of pyarrow schemas")
+
+	// PySpark flags
+	genPySparkCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+
+	// JSON-LD flags
+	genJSONLDCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genJSONLDCmd.Flags().StringVar(&genJSONLDVocab, "vocab", "", "vocabulary IRI mapping fields without @jsonld (@vocab)")
+	genJSONLDCmd.Flags().StringVar(&genJSONLDBase, "base", "", "base IRI of relative IRIs (@base)")
+	genJSONLDCmd.Flags().StringToStringVar(&genJSONLDPrefixes, "prefix", nil, "declare a prefix, e.g. ex=https://example.com/ns/ (repeatable)")
+
+	// HTML flags
+	genHTMLCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output directory")
+	genHTMLCmd.Flags().StringVar(&genHTMLTitle, "title", "", "site title (default: \"<project name> schemas\")")
+
+	// C flags
+	genCCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path")
+	genCCmd.Flags().StringVar(&genCPrefix, "prefix", "", "prefix of type and function names, e.g. shop_")
+	genCCmd.Flags().StringVar(&genCGuard, "guard", "", "include guard (default: from the output file name, e.g. SCHEMAS_H)")
+	genCCmd.Flags().BoolVar(&genCJSON, "cjson", false, "generate cJSON serialization helpers")
+	genCCmd.Flags().StringVar(&genCJSONInclude, "cjson-include", "", "cJSON header to include (default: cJSON.h)")
 }
 
 func runGenTypescript(cmd *cobra.Command, args []string) error {
@@ -127,6 +1222,40 @@ func runGenTypescript(cmd *cobra.Command, args []string) error {
 	// Override output if specified
 	if genOutput != "" {
 		genCfg.Output = genOutput
+		genCfg.Outputs = nil
+	}
+	overrides := make(map[string]interface{})
+	if len(genCodecs) > 0 {
+		overrides["codecs"] = genCodecs
+	}
+	if genCanonical {
+		overrides["canonical"] = true
+	}
+	if genLenient {
+		overrides["lenient"] = true
+	}
+	if genTypeGuards {
+		overrides["typeGuards"] = true
+	}
+	if genTSEnumStyle != "" {
+		overrides["enumStyle"] = genTSEnumStyle
+	}
+	if genTSReadonly {
+		overrides["readonly"] = true
+	}
+	if genTSNoBrands {
+		overrides["brands"] = false
+	}
+	if genUnicode != "" {
+		overrides["unicode"] = genUnicode
+	}
+	if len(overrides) > 0 {
+		for k, v := range genCfg.Options {
+			if _, ok := overrides[k]; !ok {
+				overrides[k] = v
+			}
+		}
+		genCfg.Options = overrides
 	}
 
 	// Validate output path
@@ -172,6 +1301,11 @@ func runGenTypescript(cmd *cobra.Command, args []string) error {
 		PrintError(e.Format())
 		return e
 	}
+	output = generator.StampMetadata(output, cfg.Metadata)
+	if e := checkSizeBudget("TypeScript", ctx, output, nil); e != nil {
+		PrintError("%s", e.Format())
+		return e
+	}
 
 	// Ensure output directory exists
 	outputDir := filepath.Dir(genCfg.Output)
@@ -189,22 +1323,37 @@ func runGenTypescript(cmd *cobra.Command, args []string) error {
 		PrintError(e.Format())
 		return e
 	}
+	if err := writeExtraOutputs("typescript", genCfg, output, nil); err != nil {
+		e := errors.Wrap(errors.ErrorTypeFileSystem, err, "failed to write extra output")
+		PrintError(e.Format())
+		return e
+	}
+	if err := writePackage("typescript", cfg, genCfg); err != nil {
+		e := errors.Wrap(errors.ErrorTypeFileSystem, err, "failed to write package manifest")
+		PrintError(e.Format())
+		return e
+	}
 
 	// Success
 	stats := fmt.Sprintf("%d bytes", len(output))
 	PrintSuccess("Generated TypeScript: %s (%s)", filepath.Base(genCfg.Output), stats)
 
-	return nil
+	return recordAudit(cfg, auditOperation("typescript"), []audit.Target{outputTarget("typescript", genCfg.Output, output)})
 }
 
 func runGenZod(cmd *cobra.Command, args []string) error {
-	return runGenerator("zod", map[string]interface{}{})
+	opts := make(map[string]interface{})
+	if genUnicode != "" {
+		opts["unicode"] = genUnicode
+	}
+	return runGenerator("zod", opts)
 }
 
 // runGenAll generates all enabled generators
 func runGenAll(cfg *config.Config) error {
 	var generated []string
 	var genErrors []string
+	var targets []audit.Target
 
 	// Load and validate schemas once for all generators
 	PrintVerbose("Loading and validating schemas for all generators")
@@ -227,14 +1376,14 @@ func runGenAll(cfg *config.Config) error {
 		return e
 	}
 
-	val, err := loader.LoadPaths(allPaths)
+	val, err := loadSchemaPaths(loader, cfg, allPaths)
 	if err != nil {
 		// Provide context-specific suggestions
 		suggestion := "Check your CUE files for syntax errors. Run 'cue vet' directly for more details"
 		if strings.Contains(err.Error(), "cannot use absolute directory") {
-			suggestion = "CUE module configuration issue. Try using relative paths in platosl.yaml or ensure you have a cue.mod directory"
+			suggestion = "CUE module configuration issue. Try using relative paths in platosl.yaml, or create the module with 'platosl mod init <module>'"
 		} else if strings.Contains(err.Error(), "import failed") {
-			suggestion = "Check that all imported packages are available in your cue.mod directory"
+			suggestion = "Run 'platosl mod tidy' to require the modules providing the imported packages"
 		} else if strings.Contains(err.Error(), "cannot find package") {
 			suggestion = "Verify that the schema paths in platosl.yaml point to valid CUE packages"
 		}
@@ -246,7 +1395,7 @@ func runGenAll(cfg *config.Config) error {
 	}
 
 	// Validate schemas once
-	validationErrors := validateSchemas(val, "all generators")
+	validationErrors := validateSchemas(cfg, val, "all generators")
 	if len(validationErrors) > 0 {
 		PrintError("Schema validation failed with %d error(s):\n", len(validationErrors))
 		for _, err := range validationErrors {
@@ -256,50 +1405,114 @@ func runGenAll(cfg *config.Config) error {
 		return fmt.Errorf("schema validation failed")
 	}
 
-	// Generate for each enabled generator
+	// Collect enabled generators in a stable order
+	var names []string
 	for name, genCfg := range cfg.Generate {
 		if !genCfg.Enabled {
 			PrintVerbose("Skipping disabled generator: %s", name)
 			continue
 		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
+	// Generate in parallel. CUE values must not be shared between goroutines,
+	// so the first worker reuses the validated schemas and others reload them.
+	type genResult struct {
+		output []byte
+		err    string
+	}
+	results := make([]genResult, len(names))
+	var reuse sync.Once
+	err = workers.Run(len(names), func() (cue.Value, error) {
+		schemas := cue.Value{}
+		reuse.Do(func() { schemas = val })
+		if schemas.Exists() {
+			return schemas, nil
+		}
+		return loadSchemaPaths(platoCue.NewLoader(), cfg, allPaths)
+	}, func(schemas cue.Value, i int) error {
+		name := names[i]
+		genCfg := cfg.Generate[name]
 		PrintInfo("Generating %s...", name)
 
 		// Get generator
 		gen, err := generator.Get(name)
 		if err != nil {
-			genErrors = append(genErrors, fmt.Sprintf("%s: generator not registered", name))
-			continue
+			results[i].err = fmt.Sprintf("%s: %v", name, err)
+			return nil
 		}
 
 		// Create context and generate
-		ctx := generator.NewContext(val, cfg, genCfg)
+		ctx := generator.NewContext(schemas, cfg, genCfg)
 
 		if err := gen.Validate(ctx); err != nil {
-			genErrors = append(genErrors, fmt.Sprintf("%s: validation failed: %v", name, err))
-			continue
+			results[i].err = fmt.Sprintf("%s: validation failed: %v", name, err)
+			return nil
 		}
 
-		output, err := gen.Generate(ctx)
+		output, files, err := generateOutput(gen, ctx)
 		if err != nil {
-			genErrors = append(genErrors, fmt.Sprintf("%s: generation failed: %v", name, err))
-			continue
+			results[i].err = fmt.Sprintf("%s: generation failed: %v", name, err)
+			return nil
+		}
+		if e := checkSizeBudget(name, ctx, output, files); e != nil {
+			results[i].err = fmt.Sprintf("%s: %v", e.Message, e.Cause)
+			return nil
+		}
+
+		// Multi-file generators write a directory
+		if files != nil {
+			if err := writeGeneratedFiles(name, genCfg.Output, files); err != nil {
+				results[i].err = fmt.Sprintf("%s: %v", name, err)
+				return nil
+			}
+			if err := writeExtraOutputs(name, genCfg, output, files); err != nil {
+				results[i].err = fmt.Sprintf("%s: %v", name, err)
+				return nil
+			}
+			results[i].output = output
+			return nil
 		}
 
 		// Write output
 		outputDir := filepath.Dir(genCfg.Output)
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			genErrors = append(genErrors, fmt.Sprintf("%s: failed to create output directory: %s", name, outputDir))
-			continue
+			results[i].err = fmt.Sprintf("%s: failed to create output directory: %s", name, outputDir)
+			return nil
 		}
 
 		if err := os.WriteFile(genCfg.Output, output, 0644); err != nil {
-			genErrors = append(genErrors, fmt.Sprintf("%s: failed to write output file: %s", name, genCfg.Output))
-			continue
+			results[i].err = fmt.Sprintf("%s: failed to write output file: %s", name, genCfg.Output)
+			return nil
+		}
+		if err := writeExtraOutputs(name, genCfg, output, nil); err != nil {
+			results[i].err = fmt.Sprintf("%s: %v", name, err)
+			return nil
+		}
+		if err := writePackage(name, cfg, genCfg); err != nil {
+			results[i].err = fmt.Sprintf("%s: %v", name, err)
+			return nil
 		}
 
+		results[i].output = output
+		return nil
+	})
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeValidation, err, "failed to load schemas")
+		PrintError(e.Format())
+		return e
+	}
+
+	for i, name := range names {
+		if results[i].err != "" {
+			genErrors = append(genErrors, results[i].err)
+			continue
+		}
+		output := cfg.Generate[name].Output
 		generated = append(generated, name)
-		PrintSuccess("  ✓ %s: %s", name, genCfg.Output)
+		targets = append(targets, outputTarget(name, output, results[i].output))
+		PrintSuccess("  ✓ %s: %s", name, output)
 	}
 
 	// Report results
@@ -319,11 +1532,18 @@ func runGenAll(cfg *config.Config) error {
 		return fmt.Errorf("generation completed with %d error(s)", len(genErrors))
 	}
 
-	return nil
+	return recordAudit(cfg, "build", targets)
 }
 
 func runGenJsonSchema(cmd *cobra.Command, args []string) error {
-	return runGenerator("jsonschema", map[string]interface{}{})
+	opts := make(map[string]interface{})
+	if genJsonSchemaDraft != "" {
+		opts["draft"] = genJsonSchemaDraft
+	}
+	if genJsonSchemaSplit {
+		opts["split"] = true
+	}
+	return runGenerator("jsonschema", opts)
 }
 
 func runGenGo(cmd *cobra.Command, args []string) error {
@@ -331,6 +1551,24 @@ func runGenGo(cmd *cobra.Command, args []string) error {
 	if genGoPackage != "" {
 		opts["package"] = genGoPackage
 	}
+	if genGoStreaming {
+		opts["streaming"] = true
+	}
+	if len(genCodecs) > 0 {
+		opts["codecs"] = genCodecs
+	}
+	if genCanonical {
+		opts["canonical"] = true
+	}
+	if genLenient {
+		opts["lenient"] = true
+	}
+	if genGoValidate {
+		opts["validate"] = true
+	}
+	if genUnicode != "" {
+		opts["unicode"] = genUnicode
+	}
 	return runGenerator("go", opts)
 }
 
@@ -339,9 +1577,315 @@ func runGenElixir(cmd *cobra.Command, args []string) error {
 	if genElixirModule != "" {
 		opts["module"] = genElixirModule
 	}
+	if len(genCodecs) > 0 {
+		opts["codecs"] = genCodecs
+	}
 	return runGenerator("elixir", opts)
 }
 
+func runGenGraphQL(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genGraphQLFederation {
+		opts["federation"] = true
+	}
+	return runGenerator("graphql", opts)
+}
+
+func runGenTRPC(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genTRPCZodImport != "" {
+		opts["zodImport"] = genTRPCZodImport
+	}
+	return runGenerator("trpc", opts)
+}
+
+func runGenEvents(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genEventsLanguage != "" {
+		opts["language"] = genEventsLanguage
+	}
+	if genEventsTransport != "" {
+		opts["transport"] = genEventsTransport
+	}
+	if genEventsPackage != "" {
+		opts["package"] = genEventsPackage
+	}
+	return runGenerator("events", opts)
+}
+
+func runGenFlags(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genFlagsLanguage != "" {
+		opts["language"] = genFlagsLanguage
+	}
+	if genFlagsDefinition != "" {
+		opts["definition"] = genFlagsDefinition
+	}
+	if genFlagsPackage != "" {
+		opts["package"] = genFlagsPackage
+	}
+	return runGenerator("flags", opts)
+}
+
+func runGenACL(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genACLFormat != "" {
+		opts["format"] = genACLFormat
+	}
+	if genACLPackage != "" {
+		opts["package"] = genACLPackage
+	}
+	return runGenerator("acl", opts)
+}
+
+func runGenProtobuf(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genProtobufPackage != "" {
+		opts["package"] = genProtobufPackage
+	}
+	if genProtobufGoPackage != "" {
+		opts["goPackage"] = genProtobufGoPackage
+	}
+	if genProtobufLockFile != "" {
+		opts["lockFile"] = genProtobufLockFile
+	}
+	if genProtobufFrozen {
+		opts["frozenLock"] = true
+	}
+	return runGenerator("protobuf", opts)
+}
+
+func runGenSQL(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genSQLDialect != "" {
+		opts["dialect"] = genSQLDialect
+	}
+	if len(genSQLTypes) > 0 {
+		types := make(map[string]interface{})
+		for _, t := range genSQLTypes {
+			key, value, ok := strings.Cut(t, "=")
+			if !ok || key == "" || value == "" {
+				PrintError("Invalid --type %q (expected <kind or Table.field>=<SQL type>)", t)
+				return fmt.Errorf("invalid type override: %s", t)
+			}
+			types[key] = value
+		}
+		opts["types"] = types
+	}
+	return runGenerator("sql", opts)
+}
+
+func runGenCSharp(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genCSharpNamespace != "" {
+		opts["namespace"] = genCSharpNamespace
+	}
+	return runGenerator("csharp", opts)
+}
+
+func runGenJava(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genJavaPackage != "" {
+		opts["package"] = genJavaPackage
+	}
+	if genJavaTarget != 0 {
+		opts["target"] = genJavaTarget
+	}
+	return runGenerator("java", opts)
+}
+
+func runGenDart(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genDartSerializable {
+		opts["jsonSerializable"] = true
+	}
+	return runGenerator("dart", opts)
+}
+
+func runGenPHP(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genPHPNamespace != "" {
+		opts["namespace"] = genPHPNamespace
+	}
+	return runGenerator("php", opts)
+}
+
+func runGenHaskell(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genHaskellModule != "" {
+		opts["module"] = genHaskellModule
+	}
+	return runGenerator("haskell", opts)
+}
+
+func runGenClojure(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genClojureNamespace != "" {
+		opts["namespace"] = genClojureNamespace
+	}
+	if genClojureStyle != "" {
+		opts["style"] = genClojureStyle
+	}
+	return runGenerator("clojure", opts)
+}
+
+// runGenUnknown shows help without a generator, and suggests the closest
+// generator for an unknown one
+func runGenUnknown(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	_, err := generator.Get(args[0])
+	if err == nil {
+		err = fmt.Errorf("generator '%s' has no gen command", args[0])
+	}
+	PrintError("%v", err)
+	return err
+}
+
+func runGenValibot(cmd *cobra.Command, args []string) error {
+	return runGenerator("valibot", map[string]interface{}{})
+}
+
+func runGenYup(cmd *cobra.Command, args []string) error {
+	return runGenerator("yup", map[string]interface{}{})
+}
+
+func runGenJoi(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genJoiModule != "" {
+		opts["module"] = genJoiModule
+	}
+	return runGenerator("joi", opts)
+}
+
+func runGenEffect(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genEffectPackage != "" {
+		opts["package"] = genEffectPackage
+	}
+	if genEffectNoBrands {
+		opts["brands"] = false
+	}
+	return runGenerator("effect", opts)
+}
+
+func runGenTypeBox(cmd *cobra.Command, args []string) error {
+	return runGenerator("typebox", map[string]interface{}{})
+}
+
+func runGenMongoose(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genMongooseModule != "" {
+		opts["module"] = genMongooseModule
+	}
+	if len(genMongooseModels) > 0 {
+		opts["models"] = genMongooseModels
+	}
+	return runGenerator("mongoose", opts)
+}
+
+func runGenEcto(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genEctoModule != "" {
+		opts["module"] = genEctoModule
+	}
+	return runGenerator("elixir-ecto", opts)
+}
+
+func runGenAbsinthe(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genAbsintheModule != "" {
+		opts["module"] = genAbsintheModule
+	}
+	if genAbsintheNoInputs {
+		opts["inputs"] = false
+	}
+	return runGenerator("elixir-absinthe", opts)
+}
+
+func runGenGleam(cmd *cobra.Command, args []string) error {
+	return runGenerator("gleam", make(map[string]interface{}))
+}
+
+func runGenTerraform(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genTerraformNoChecks {
+		opts["validations"] = false
+	}
+	return runGenerator("terraform", opts)
+}
+
+func runGenBigQuery(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if len(genBigQueryTables) > 0 {
+		opts["tables"] = genBigQueryTables
+	}
+	return runGenerator("bigquery", opts)
+}
+
+func runGenArrow(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genArrowParquet {
+		opts["parquet"] = true
+	}
+	return runGenerator("arrow", opts)
+}
+
+func runGenPySpark(cmd *cobra.Command, args []string) error {
+	return runGenerator("pyspark", make(map[string]interface{}))
+}
+
+func runGenJSONLD(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genJSONLDVocab != "" {
+		opts["vocab"] = genJSONLDVocab
+	}
+	if genJSONLDBase != "" {
+		opts["base"] = genJSONLDBase
+	}
+	if len(genJSONLDPrefixes) > 0 {
+		opts["prefixes"] = genJSONLDPrefixes
+	}
+	return runGenerator("jsonld", opts)
+}
+
+func runGenHTML(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genHTMLTitle != "" {
+		opts["title"] = genHTMLTitle
+	}
+	return runGenerator("html", opts)
+}
+
+func runGenC(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genCPrefix != "" {
+		opts["prefix"] = genCPrefix
+	}
+	if genCGuard != "" {
+		opts["guard"] = genCGuard
+	}
+	if genCJSON {
+		opts["cjson"] = true
+	}
+	if genCJSONInclude != "" {
+		opts["cjsonInclude"] = genCJSONInclude
+	}
+	return runGenerator("c", opts)
+}
+
+func runGenEncrypt(cmd *cobra.Command, args []string) error {
+	opts := make(map[string]interface{})
+	if genEncryptFormat != "" {
+		opts["format"] = genEncryptFormat
+	}
+	if genEncryptPackage != "" {
+		opts["package"] = genEncryptPackage
+	}
+	return runGenerator("encrypt", opts)
+}
+
 // runGenerator is a generic function to run any generator
 func runGenerator(name string, opts map[string]interface{}) error {
 	// Load config
@@ -363,6 +1907,7 @@ func runGenerator(name string, opts map[string]interface{}) error {
 	// Override output if specified
 	if genOutput != "" {
 		genCfg.Output = genOutput
+		genCfg.Outputs = nil
 	}
 
 	// Merge options
@@ -409,13 +1954,37 @@ func runGenerator(name string, opts map[string]interface{}) error {
 
 	// Generate
 	PrintVerbose("Generating %s code", name)
-	output, err := gen.Generate(ctx)
+	output, files, err := generateOutput(gen, ctx)
 	if err != nil {
 		e := errors.Wrap(errors.ErrorTypeGeneration, err, fmt.Sprintf("%s generation failed", name))
 		e = e.WithSuggestion("Check that your schema definitions are valid and exportable")
 		PrintError(e.Format())
 		return e
 	}
+	if e := checkSizeBudget(name, ctx, output, files); e != nil {
+		PrintError("%s", e.Format())
+		return e
+	}
+
+	// Multi-file generators write a directory
+	if files != nil {
+		if err := writeGeneratedFiles(name, genCfg.Output, files); err != nil {
+			e := errors.Wrap(errors.ErrorTypeFileSystem, err, fmt.Sprintf("failed to write output directory: %s", genCfg.Output))
+			e = e.WithSuggestion("Check that you have write permissions for the output directory")
+			PrintError(e.Format())
+			return e
+		}
+		for _, f := range files {
+			PrintVerbose("  %s", filepath.Join(genCfg.Output, filepath.FromSlash(f.Path)))
+		}
+		if err := writeExtraOutputs(name, genCfg, output, files); err != nil {
+			e := errors.Wrap(errors.ErrorTypeFileSystem, err, "failed to write extra output")
+			PrintError(e.Format())
+			return e
+		}
+		PrintSuccess("Generated %s: %d file(s) in %s", name, len(files), genCfg.Output)
+		return recordAudit(cfg, auditOperation(name), []audit.Target{outputTarget(name, genCfg.Output, output)})
+	}
 
 	// Ensure output directory exists
 	outputDir := filepath.Dir(genCfg.Output)
@@ -434,13 +2003,191 @@ func runGenerator(name string, opts map[string]interface{}) error {
 		return e
 	}
 
+	if err := writeExtraOutputs(name, genCfg, output, nil); err != nil {
+		e := errors.Wrap(errors.ErrorTypeFileSystem, err, "failed to write extra output")
+		PrintError(e.Format())
+		return e
+	}
+	if err := writePackage(name, cfg, genCfg); err != nil {
+		e := errors.Wrap(errors.ErrorTypeFileSystem, err, "failed to write package manifest")
+		PrintError(e.Format())
+		return e
+	}
+
 	// Success
 	stats := fmt.Sprintf("%d bytes", len(output))
 	PrintSuccess("Generated %s: %s (%s)", name, filepath.Base(genCfg.Output), stats)
 
+	return recordAudit(cfg, auditOperation(name), []audit.Target{outputTarget(name, genCfg.Output, output)})
+}
+
+// writeExtraOutputs copies generated output to the outputs listed after the
+// primary one: files to each directory, or the output to each file
+func writeExtraOutputs(name string, genCfg config.GenConfig, output []byte, files []generator.File) error {
+	for _, path := range genCfg.ExtraOutputs() {
+		if files != nil {
+			if err := writeGeneratedFiles(name, path, files); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %s", filepath.Dir(path))
+		}
+		if err := os.WriteFile(path, output, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %s", path)
+		}
+		PrintVerbose("Copied output to: %s", path)
+	}
+	return nil
+}
+
+// writePackage writes the package manifest of a generator's output when its
+// package option is set: package.json or mix.exs, carrying the metadata of
+// platosl.yaml. The packageName option names the package (default: the
+// project name).
+func writePackage(name string, cfg *config.Config, genCfg config.GenConfig) error {
+	pkg, err := packageFor(name, cfg, genCfg)
+	if err != nil || pkg == nil {
+		return err
+	}
+	if err := os.WriteFile(pkg.Path, generator.StampMetadata(pkg.Content, cfg.Metadata), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", pkg.Path, err)
+	}
+	PrintVerbose("Wrote package manifest: %s", pkg.Path)
 	return nil
 }
 
+// packageFor returns the package manifest of a generator's output, or nil
+// when its package option is not set
+func packageFor(name string, cfg *config.Config, genCfg config.GenConfig) (*packaging.Package, error) {
+	if enabled, _ := genCfg.Options["package"].(bool); !enabled {
+		return nil, nil
+	}
+	pkgName, _ := genCfg.Options["packageName"].(string)
+	if pkgName == "" {
+		pkgName = cfg.Name
+	}
+	return packaging.Build(name, genCfg.Output, pkgName, cfg.Metadata)
+}
+
+// generateOutput runs a generator and stamps the package metadata into the
+// headers of its output. Multi-file generators also return their files;
+// output is then the files joined, which identifies them in the audit log.
+func generateOutput(gen generator.Generator, ctx *generator.Context) ([]byte, []generator.File, error) {
+	multi, ok := gen.(generator.MultiFileGenerator)
+	if splitter, isSplitter := gen.(generator.FileSplitter); isSplitter && !splitter.SplitFiles(ctx) {
+		ok = false
+	}
+	if !ok {
+		output, err := gen.Generate(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return generator.StampMetadata(output, ctx.Config.Metadata), nil, nil
+	}
+	files, err := multi.GenerateFiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if files == nil {
+		files = []generator.File{}
+	}
+	for i := range files {
+		files[i].Content = generator.StampMetadata(files[i].Content, ctx.Config.Metadata)
+	}
+	return generator.JoinFiles(files), files, nil
+}
+
+// writeGeneratedFiles writes the files of a multi-file generator into dir
+// and removes the files the generator wrote there on its previous run that
+// it no longer writes, e.g. those of removed definitions. The files of each
+// generator are recorded in the manifest of the directory, so the files of
+// other generators and files that are not generated are left alone.
+func writeGeneratedFiles(name, dir string, files []generator.File) error {
+	written := make(map[string]bool)
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %s", filepath.Dir(path))
+		}
+		if err := os.WriteFile(path, f.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %s", path)
+		}
+		written[filepath.ToSlash(f.Path)] = true
+	}
+
+	// Generators of a build run concurrently and may share a directory
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	manifest := readManifest(dir)
+	for _, previous := range manifest[name] {
+		if written[previous] {
+			continue
+		}
+		path, ok := manifestPath(dir, previous)
+		if !ok {
+			PrintWarning("Ignoring %s entry outside %s: %s", manifestFile, dir, previous)
+			continue
+		}
+		PrintVerbose("Removing stale generated file: %s", path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale file: %s", path)
+		}
+	}
+
+	manifest[name] = make([]string, 0, len(written))
+	for path := range written {
+		manifest[name] = append(manifest[name], path)
+	}
+	sort.Strings(manifest[name])
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifestFile, err)
+	}
+	return nil
+}
+
+// manifestFile lists the files each generator wrote into an output
+// directory, keyed by generator
+const manifestFile = ".platosl-files.json"
+
+var manifestMu sync.Mutex
+
+// manifestPath joins a manifest entry to its output directory. Absolute
+// entries and entries escaping the directory, which only a corrupted or
+// crafted manifest has, are rejected so stale-file removal stays inside it.
+func manifestPath(dir, entry string) (string, bool) {
+	if entry == "" || filepath.IsAbs(filepath.FromSlash(entry)) || filepath.VolumeName(entry) != "" {
+		return "", false
+	}
+	path := filepath.Join(dir, filepath.FromSlash(entry))
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// readManifest reads the manifest of an output directory; directories
+// without one, or with an unreadable one, have an empty manifest
+func readManifest(dir string) map[string][]string {
+	manifest := make(map[string][]string)
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		PrintWarning("Ignoring invalid %s: %v", filepath.Join(dir, manifestFile), err)
+		return make(map[string][]string)
+	}
+	return manifest
+}
+
 func getDefaultOutput(generatorName string) string {
 	switch generatorName {
 	case "typescript":
@@ -448,28 +2195,114 @@ func getDefaultOutput(generatorName string) string {
 	case "zod":
 		return "schemas.ts"
 	case "jsonschema":
+		if genJsonSchemaSplit {
+			return "schemas"
+		}
 		return "schema.json"
 	case "go":
 		return "types.go"
 	case "elixir":
 		return "types.ex"
+	case "graphql":
+		return "schema.graphql"
+	case "trpc":
+		return "router.ts"
+	case "events":
+		if genEventsLanguage == "typescript" || genEventsLanguage == "ts" {
+			return "events.ts"
+		}
+		return "events.go"
+	case "flags":
+		return "flags.ts"
+	case "acl":
+		return "acl.json"
+	case "encrypt":
+		return "encryption.go"
+	case "protobuf":
+		return "schema.proto"
+	case "sql":
+		return "schema.sql"
+	case "csharp":
+		return "Types.cs"
+	case "java":
+		return "java"
+	case "dart":
+		return "types.dart"
+	case "haskell":
+		return "Types.hs"
+	case "clojure":
+		return "types.cljc"
+	case "valibot":
+		return "valibot.ts"
+	case "yup":
+		return "yup.ts"
+	case "joi":
+		return "schemas.js"
+	case "effect":
+		return "effect.ts"
+	case "typebox":
+		return "typebox.ts"
+	case "mongoose":
+		return "models.js"
+	case "elixir-ecto":
+		return "schemas.ex"
+	case "elixir-absinthe":
+		return "types.ex"
+	case "gleam":
+		return "types.gleam"
+	case "terraform":
+		return "variables.tf"
+	case "bigquery":
+		return "bigquery"
+	case "arrow":
+		if genArrowParquet {
+			return "schema.parquet"
+		}
+		return "schemas.py"
+	case "pyspark":
+		return "spark_schemas.py"
+	case "jsonld":
+		return "context.jsonld"
+	case "html":
+		return "site"
+	case "c":
+		return "schemas.h"
+	case "php":
+		return "php"
 	default:
 		return "output.txt"
 	}
 }
 
 // validateSchemas performs validation on loaded schemas and returns structured errors
-func validateSchemas(val cue.Value, generatorName string) []*errors.Error {
+func validateSchemas(cfg *config.Config, val cue.Value, generatorName string) []*errors.Error {
 	var errs []*errors.Error
 
+	policy, err := severityPolicy(cfg)
+	if err != nil {
+		return []*errors.Error{errors.Wrap(errors.ErrorTypeConfig, err, "invalid validation.severity")}
+	}
+
 	// Create validator
 	validator := platoCue.NewValidator(false)
 	result := validator.Validate(val)
 
 	if !result.Valid {
 		for _, valErr := range result.Errors {
+			// Convention checks follow validation.severity
+			if valErr.Code != "" {
+				switch policy.Level(findingNames(valErr.Code), valErr.File, severity.Error) {
+				case severity.Ignore:
+					continue
+				case severity.Warn:
+					PrintWarning("%s:%d: %s [%s]", valErr.File, valErr.Line, valErr.Message, valErr.Code)
+					continue
+				}
+			}
+
 			err := errors.New(errors.ErrorTypeValidation, valErr.Message).
-				WithLocation(valErr.File, valErr.Line, valErr.Column)
+				WithLocation(valErr.File, valErr.Line, valErr.Column).
+				WithCode(valErr.Code)
 
 			if valErr.Suggestion != "" {
 				err = err.WithSuggestion(valErr.Suggestion)
@@ -521,14 +2354,14 @@ func loadAndValidateSchemas(cfg *config.Config, generatorName string) (cue.Value
 	PrintVerbose("Loading %d schema path(s) for %s generation", len(allPaths), generatorName)
 
 	// Load all schemas
-	val, err := loader.LoadPaths(allPaths)
+	val, err := loadSchemaPaths(loader, cfg, allPaths)
 	if err != nil {
 		// Provide context-specific suggestions
 		suggestion := "Check your CUE files for syntax errors. Run 'cue vet' directly for more details"
 		if strings.Contains(err.Error(), "cannot use absolute directory") {
-			suggestion = "CUE module configuration issue. Try using relative paths in platosl.yaml or ensure you have a cue.mod directory"
+			suggestion = "CUE module configuration issue. Try using relative paths in platosl.yaml, or create the module with 'platosl mod init <module>'"
 		} else if strings.Contains(err.Error(), "import failed") {
-			suggestion = "Check that all imported packages are available in your cue.mod directory"
+			suggestion = "Run 'platosl mod tidy' to require the modules providing the imported packages"
 		} else if strings.Contains(err.Error(), "cannot find package") {
 			suggestion = "Verify that the schema paths in platosl.yaml point to valid CUE packages"
 		}
@@ -540,7 +2373,7 @@ func loadAndValidateSchemas(cfg *config.Config, generatorName string) (cue.Value
 	}
 
 	// Validate schemas
-	validationErrors := validateSchemas(val, generatorName)
+	validationErrors := validateSchemas(cfg, val, generatorName)
 	if len(validationErrors) > 0 {
 		PrintError("Schema validation failed with %d error(s):\n", len(validationErrors))
 		for _, err := range validationErrors {
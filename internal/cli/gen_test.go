@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+func TestWriteGeneratedFilesKeepsOtherGenerators(t *testing.T) {
+	dir := t.TempDir()
+	header := []byte("// Generated by PlatoSL\n")
+
+	// Single-file output of another generator, and a hand-written file
+	if err := os.WriteFile(filepath.Join(dir, "types.ts"), header, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := []generator.File{{Path: "Person.json", Content: []byte("{}")}, {Path: "Address.json", Content: []byte("{}")}}
+	if err := writeGeneratedFiles("jsonschema", dir, first); err != nil {
+		t.Fatal(err)
+	}
+	other := []generator.File{{Path: "Person.java", Content: header}}
+	if err := writeGeneratedFiles("java", dir, other); err != nil {
+		t.Fatal(err)
+	}
+
+	// Address is removed from the schemas
+	second := []generator.File{{Path: "Person.json", Content: []byte("{}")}}
+	if err := writeGeneratedFiles("jsonschema", dir, second); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"types.ts", "notes.md", "Person.java", "Person.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s was removed: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Address.json")); !os.IsNotExist(err) {
+		t.Errorf("stale Address.json was kept")
+	}
+}
+
+func TestWriteGeneratedFilesStaysInOutputDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "out")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(root, "keep.txt")
+	if err := os.WriteFile(outside, []byte("keep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crafted manifest pointing outside the output directory
+	manifest := `{"jsonschema": ["../keep.txt", "` + filepath.ToSlash(outside) + `", ".", "Person.json"]}`
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := []generator.File{{Path: "Person.json", Content: []byte("{}")}}
+	if err := writeGeneratedFiles("jsonschema", dir, files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the output directory was removed: %v", err)
+	}
+
+	for _, entry := range []string{"../x", "a/../../x", "/etc/passwd", "", "."} {
+		if _, ok := manifestPath(dir, entry); ok {
+			t.Errorf("manifestPath(%q) accepted", entry)
+		}
+	}
+	if path, ok := manifestPath(dir, "nested/A.json"); !ok || path != filepath.Join(dir, "nested", "A.json") {
+		t.Errorf("manifestPath(nested/A.json) = %q, %v", path, ok)
+	}
+}
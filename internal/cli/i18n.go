@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var (
+	i18nFormat       string
+	i18nOutput       string
+	i18nSourceLocale string
+	i18nTargetLocale string
+	i18nDefinition   string
+	i18nLocales      []string
+)
+
+var i18nCmd = &cobra.Command{
+	Use:   "i18n",
+	Short: "Work with translatable (@i18n) fields",
+	Long: `Work with string fields marked translatable with the @i18n attribute:
+
+  title: string @i18n()
+  cta:   string @i18n("button label")  // argument is passed as context`,
+}
+
+var i18nExtractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Export translatable fields as a message catalog skeleton",
+	Long: `Export all @i18n fields as a message catalog skeleton, keyed by
+<Definition>.<path> (e.g. Product.variants[*].name). Doc comments and @i18n
+arguments are included as translator notes.
+
+Formats:
+  json   { "<id>": { "message": "", "description": "...", "context": "..." } }
+  xliff  XLIFF 2.0 with one <file> per definition
+
+Examples:
+  platosl i18n extract -o locales/messages.json
+  platosl i18n extract --format xliff --target-locale de -o locales/de.xlf`,
+	Args: cobra.NoArgs,
+	RunE: runI18nExtract,
+}
+
+var i18nCheckCmd = &cobra.Command{
+	Use:   "check <data directory>",
+	Short: "Check that localized data files cover all required locales",
+	Long: `Check localized data files laid out as <dir>/<locale>/<file>. Every file
+must exist for each locale, and every required @i18n field of the definition
+must be present and non-empty.
+
+Locales default to i18n.locales in platosl.yaml.
+
+Examples:
+  platosl i18n check content/products --definition '#Product' --locales en,de,fr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runI18nCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(i18nCmd)
+	i18nCmd.AddCommand(i18nExtractCmd)
+	i18nCmd.AddCommand(i18nCheckCmd)
+
+	i18nExtractCmd.Flags().StringVar(&i18nFormat, "format", "json", "catalog format (json, xliff)")
+	i18nExtractCmd.Flags().StringVarP(&i18nOutput, "output", "o", "", "output file path (default stdout)")
+	i18nExtractCmd.Flags().StringVar(&i18nSourceLocale, "source-locale", "", "source locale (default i18n.sourceLocale or \"en\")")
+	i18nExtractCmd.Flags().StringVar(&i18nTargetLocale, "target-locale", "", "target locale for XLIFF")
+
+	i18nCheckCmd.Flags().StringVar(&i18nDefinition, "definition", "", "definition the data files conform to (required)")
+	i18nCheckCmd.Flags().StringSliceVar(&i18nLocales, "locales", nil, "required locales (default i18n.locales)")
+	i18nCheckCmd.MarkFlagRequired("definition")
+}
+
+func runI18nExtract(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	// Load and validate schemas
+	val, err := loadAndValidateSchemas(cfg, "i18n")
+	if err != nil {
+		return err
+	}
+
+	messages, err := i18n.Extract(val)
+	if err != nil {
+		PrintError("Failed to extract messages: %v", err)
+		return err
+	}
+
+	sourceLocale := i18nSourceLocale
+	if sourceLocale == "" {
+		sourceLocale = cfg.I18n.SourceLocale
+	}
+	if sourceLocale == "" {
+		sourceLocale = "en"
+	}
+
+	var data []byte
+	switch i18nFormat {
+	case "json":
+		data, err = i18n.JSONCatalog(messages)
+	case "xliff":
+		data, err = i18n.XLIFFCatalog(messages, sourceLocale, i18nTargetLocale)
+	default:
+		err = fmt.Errorf("unsupported format: %s (supported: json, xliff)", i18nFormat)
+	}
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if i18nOutput == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(i18nOutput), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(i18nOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	PrintSuccess("Exported %d message(s) to %s", len(messages), i18nOutput)
+	return nil
+}
+
+func runI18nCheck(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	locales := i18nLocales
+	if len(locales) == 0 {
+		locales = cfg.I18n.Locales
+	}
+	if len(locales) == 0 {
+		err := fmt.Errorf("no locales given (use --locales or set i18n.locales in platosl.yaml)")
+		PrintError("%v", err)
+		return err
+	}
+
+	// Load and validate schemas
+	val, err := loadAndValidateSchemas(cfg, "i18n")
+	if err != nil {
+		return err
+	}
+
+	messages, err := i18n.ExtractDefinition(val, i18nDefinition)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(messages) == 0 {
+		PrintInfo("%s has no @i18n fields", i18nDefinition)
+		return nil
+	}
+
+	problems, err := i18n.Check(messages, args[0], locales)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if len(problems) > 0 {
+		for _, p := range problems {
+			PrintError("%s", p)
+		}
+		PrintError("Localization incomplete: %d problem(s)", len(problems))
+		return fmt.Errorf("localization incomplete: %d problem(s)", len(problems))
+	}
+
+	PrintSuccess("All %d translatable field(s) covered for %d locale(s)", len(messages), len(locales))
+	return nil
+}
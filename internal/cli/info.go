@@ -3,7 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -18,14 +20,25 @@ var infoCmd = &cobra.Command{
 	Use:   "info <schema>",
 	Short: "Show schema information",
 	Long: `Show detailed information about a CUE schema including fields, types,
-and definitions.`,
+and definitions.
+
+Formats:
+  text     human-readable summary (default)
+  table    aligned columns, one row per field including definition fields
+  compact  tab-separated rows without a header, for grep, cut and awk
+  json     machine-readable
+  yaml     machine-readable
+
+Examples:
+  platosl info schemas/order.cue --format table
+  platosl info schemas/order.cue --format compact | awk -F'\t' '$3 == "optional"'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInfo,
 }
 
 func init() {
 	rootCmd.AddCommand(infoCmd)
-	infoCmd.Flags().StringVar(&infoFormat, "format", "text", "output format (text, json, yaml)")
+	infoCmd.Flags().StringVar(&infoFormat, "format", "text", "output format (text, table, compact, json, yaml)")
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
@@ -43,13 +56,17 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	loader := platoCue.NewLoader()
 	val, err := loader.LoadFile(absPath)
 	if err != nil {
-		return fmt.Errorf("failed to load schema: %w", err)
+		err = fmt.Errorf("failed to load schema: %w", err)
+		PrintError("%v", err)
+		return err
 	}
 
 	// Introspect schema
 	info, err := platoCue.Introspect(val)
 	if err != nil {
-		return fmt.Errorf("failed to introspect schema: %w", err)
+		err = fmt.Errorf("failed to introspect schema: %w", err)
+		PrintError("%v", err)
+		return err
 	}
 
 	// Format output
@@ -68,6 +85,19 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Print(string(data))
 
+	case "table", "compact":
+		defs, err := platoCue.IntrospectDefinitions(val)
+		if err != nil {
+			err = fmt.Errorf("failed to introspect schema: %w", err)
+			PrintError("%v", err)
+			return err
+		}
+		t := fieldTable(info, defs)
+		if infoFormat == "table" {
+			return t.Render(os.Stdout)
+		}
+		return t.RenderCompact(os.Stdout)
+
 	case "text":
 		fallthrough
 	default:
@@ -76,3 +106,36 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// fieldTable lists top-level fields and the fields of every definition
+func fieldTable(info *platoCue.SchemaInfo, defs []platoCue.DefinitionInfo) *table {
+	t := newTable("PATH", "TYPE", "PRESENCE", "MEASURE")
+	add := func(f platoCue.FieldInfo) {
+		presence := "required"
+		if f.Optional {
+			presence = "optional"
+		}
+		measure := "-"
+		if !f.Measure.IsZero() {
+			measure = f.Measure.String()
+		}
+		t.AddRow(f.Path, f.Type, presence, measure)
+	}
+
+	for _, f := range info.Fields {
+		if !strings.HasPrefix(f.Name, "#") {
+			add(f)
+		}
+	}
+	for _, def := range defs {
+		kind := "definition"
+		if def.Kind != "struct" {
+			kind += " (" + def.Kind + ")"
+		}
+		t.AddRow(def.Name, kind, "-", "-")
+		for _, f := range def.Fields {
+			add(f)
+		}
+	}
+	return t
+}
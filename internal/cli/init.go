@@ -4,17 +4,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/cuemod"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 var (
 	initBase       string
 	initName       string
 	initGenerators string
+	initYes        bool
+	initModule     string
+	initStrict     bool
+	initFailOnWarn bool
+	initOutputs    []string
+	initOptions    []string
 )
 
 var initCmd = &cobra.Command{
@@ -30,6 +41,23 @@ Otherwise, it will be initialized in the current directory.
 By default, the command will interactively prompt you to select which
 generators to enable. You can also specify generators using the --generators flag.
 
+The directory is inspected for existing projects, and the generators
+matching them are preselected with idiomatic output paths:
+
+  package.json    typescript (and zod if it is a dependency) in src/generated/
+  go.mod          go in internal/types/
+  mix.exs         elixir in lib/<app>/types.ex
+  pyproject.toml  jsonschema in the Python package
+
+Without a match, typescript and zod are preselected.
+
+For scripted scaffolding, --yes skips the prompt and enables the preselected
+generators, or the ones already configured. Every other choice has a flag:
+--output and --option set the output path and options of a generator,
+--strict and --fail-on-warning the validation settings, and --module creates
+a CUE module (cue.mod/module.cue) with the given path. Without a terminal,
+init fails instead of prompting unless --yes or --generators is given.
+
 Available generators:
   - typescript  : TypeScript interfaces
   - zod         : Zod validation schemas
@@ -47,8 +75,17 @@ Examples:
   # Initialize with specific generators (non-interactive)
   platosl init --generators typescript,go,jsonschema
 
+  # Generator aliases work too (ts, ex, json, ...)
+  platosl init --generators ts,ex
+
   # Update generators in existing project (non-interactive)
-  platosl init --generators typescript,zod,jsonschema,go,elixir`,
+  platosl init --generators typescript,zod,jsonschema,go,elixir
+
+  # Scaffold headlessly with explicit outputs and options
+  platosl init services/billing --yes --name billing \
+    --generators go,jsonschema --module example.com/billing@v0 \
+    --output go=internal/billing/types.go --option go.package=billing \
+    --strict=false`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -58,9 +95,29 @@ func init() {
 	initCmd.Flags().StringVar(&initBase, "base", "", "base schema to import (e.g., platosl.org/base/address/us@v1)")
 	initCmd.Flags().StringVar(&initName, "name", "", "project name (defaults to directory name)")
 	initCmd.Flags().StringVar(&initGenerators, "generators", "typescript,zod", "comma-separated list of generators to enable (typescript,zod,jsonschema,go,elixir)")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "accept the preselected generators without prompting")
+	initCmd.Flags().StringVar(&initModule, "module", "", "create a CUE module with this path (e.g., example.com/schemas@v0)")
+	initCmd.Flags().BoolVar(&initStrict, "strict", true, "enable strict validation")
+	initCmd.Flags().BoolVar(&initFailOnWarn, "fail-on-warning", false, "fail validation on warnings")
+	initCmd.Flags().StringArrayVar(&initOutputs, "output", nil, "output path of a generator as name=path (repeatable)")
+	initCmd.Flags().StringArrayVar(&initOptions, "option", nil, "generator option as name.key=value (repeatable)")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
+	if err := initProject(cmd, args); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	return nil
+}
+
+// initProject creates or updates the project in the directory of args
+func initProject(cmd *cobra.Command, args []string) error {
+	outputs, options, err := parseInitOverrides()
+	if err != nil {
+		return err
+	}
+
 	// Determine target directory
 	targetDir := "."
 	if len(args) > 0 {
@@ -117,6 +174,20 @@ func runInit(cmd *cobra.Command, args []string) error {
 		PrintVerbose("Initializing new project: %s", projectName)
 	}
 
+	// Preselect the generators of the ecosystems in the directory, with
+	// their idiomatic output paths
+	presets := make(map[string]config.GenConfig)
+	var detectedGenerators []string
+	for _, eco := range config.DetectEcosystems(absDir) {
+		PrintInfo("Detected %s: %s", eco.Name, strings.Join(eco.Generators(), ", "))
+		for _, name := range eco.Generators() {
+			if _, ok := presets[name]; !ok {
+				detectedGenerators = append(detectedGenerators, name)
+			}
+			presets[name] = eco.Generate[name]
+		}
+	}
+
 	// Parse selected generators
 	var selectedGenerators []string
 
@@ -124,21 +195,36 @@ func runInit(cmd *cobra.Command, args []string) error {
 	generatorsFlag := cmd.Flags().Lookup("generators")
 	flagWasSet := generatorsFlag != nil && generatorsFlag.Changed
 
+	if !flagWasSet && !initYes && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("generator selection needs a terminal; pass --yes or --generators")
+	}
+
+	defaultGenerators := currentGenerators
+	if len(defaultGenerators) == 0 {
+		defaultGenerators = detectedGenerators
+	}
+	if len(defaultGenerators) == 0 {
+		defaultGenerators = []string{"typescript", "zod"}
+	}
+
 	if flagWasSet {
-		// Use the provided generators from flag
+		// Use the provided generators from flag, resolving aliases such as ts
 		selectedGenerators = strings.Split(initGenerators, ",")
-		// Trim whitespace
 		for i, gen := range selectedGenerators {
-			selectedGenerators[i] = strings.TrimSpace(gen)
+			name, err := resolveGenerator(strings.TrimSpace(gen))
+			if err != nil {
+				return err
+			}
+			selectedGenerators[i] = name
 		}
 		PrintVerbose("Enabling generators: %s", strings.Join(selectedGenerators, ", "))
+	} else if initYes {
+		// Non-interactive mode - accept the preselection
+		selectedGenerators = defaultGenerators
+		PrintInfo("Selected generators: %s", strings.Join(selectedGenerators, ", "))
 	} else {
 		// Interactive mode - prompt user to select generators
-		availableGenerators := []string{"typescript", "zod", "go", "jsonschema", "elixir"}
-		defaultGenerators := currentGenerators
-		if len(defaultGenerators) == 0 {
-			defaultGenerators = []string{"typescript", "zod"}
-		}
+		availableGenerators := []string{"typescript", "zod", "go", "jsonschema", "elixir", "graphql"}
 
 		message := "Select generators to enable:"
 		if existingConfig {
@@ -159,6 +245,14 @@ func runInit(cmd *cobra.Command, args []string) error {
 		PrintInfo("Selected generators: %s", strings.Join(selectedGenerators, ", "))
 	}
 
+	// Generators configured before keep their settings
+	configured := make(map[string]bool)
+	if existingConfig {
+		for name := range cfg.Generate {
+			configured[name] = true
+		}
+	}
+
 	// Create or update config with selected generators
 	if existingConfig {
 		// Update existing config with new generator selection
@@ -168,16 +262,71 @@ func runInit(cmd *cobra.Command, args []string) error {
 		cfg = config.DefaultWithGenerators(projectName, selectedGenerators)
 	}
 
+	for _, gen := range selectedGenerators {
+		if preset, ok := presets[gen]; ok && !configured[gen] {
+			cfg.Generate[gen] = preset
+		}
+	}
+
+	// Apply the outputs and options given as flags
+	for gen, output := range outputs {
+		if !slices.Contains(selectedGenerators, gen) {
+			return fmt.Errorf("--output %s: generator %s is not selected", gen, gen)
+		}
+		genCfg := cfg.Generate[gen]
+		genCfg.Enabled = true
+		genCfg.Output = output
+		cfg.Generate[gen] = genCfg
+	}
+	for gen, opts := range options {
+		if !slices.Contains(selectedGenerators, gen) {
+			return fmt.Errorf("--option %s: generator %s is not selected", gen, gen)
+		}
+		genCfg, ok := cfg.Generate[gen]
+		if !ok {
+			return fmt.Errorf("--option %s: generator %s has no output; set one with --output %s=<path>", gen, gen, gen)
+		}
+		if genCfg.Options == nil {
+			genCfg.Options = make(map[string]interface{})
+		}
+		for key, value := range opts {
+			genCfg.Options[key] = value
+		}
+		cfg.Generate[gen] = genCfg
+	}
+	for _, gen := range selectedGenerators {
+		if _, ok := cfg.Generate[gen]; !ok {
+			return fmt.Errorf("generator %s has no default output; set one with --output %s=<path>", gen, gen)
+		}
+	}
+
+	// Validation settings are only changed when given
+	if cmd.Flags().Changed("strict") {
+		cfg.Validation.Strict = initStrict
+	}
+	if cmd.Flags().Changed("fail-on-warning") {
+		cfg.Validation.FailOnWarning = initFailOnWarn
+	}
+
 	// Add base schema if specified
 	if initBase != "" {
 		PrintVerbose("Adding base schema: %s", initBase)
-		cfg.Imports = append(cfg.Imports, initBase)
+		cfg.Imports = append(cfg.Imports, config.Import{Pkg: initBase})
 	}
 
-	// Create directory structure
-	dirs := []string{
-		filepath.Join(absDir, "schemas"),
-		filepath.Join(absDir, "generated"),
+	// Create directory structure: schemas and the output directories
+	dirs := []string{filepath.Join(absDir, "schemas")}
+	var outputDirs []string
+	for _, gen := range selectedGenerators {
+		genCfg, ok := cfg.Generate[gen]
+		if !ok || genCfg.Output == "" {
+			continue
+		}
+		dir := filepath.Dir(genCfg.Output) + "/"
+		if !slices.Contains(outputDirs, dir) {
+			outputDirs = append(outputDirs, dir)
+			dirs = append(dirs, filepath.Join(absDir, dir))
+		}
 	}
 
 	for _, dir := range dirs {
@@ -193,6 +342,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	// Create the CUE module
+	if initModule != "" {
+		if err := writeModuleFile(absDir, initModule); err != nil {
+			return err
+		}
+	}
+
 	// Create example schema
 	exampleSchema := filepath.Join(absDir, "schemas", "example.cue")
 	exampleContent := `package schemas
@@ -225,7 +381,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 		PrintInfo("  platosl.yaml        - Configuration file")
 		PrintInfo("  schemas/            - Schema directory")
 		PrintInfo("  schemas/example.cue - Example schema")
-		PrintInfo("  generated/          - Generated code output")
+		if initModule != "" {
+			PrintInfo("  cue.mod/            - CUE module %s", initModule)
+		}
+		for _, dir := range outputDirs {
+			PrintInfo("  %-19s - Generated code output", dir)
+		}
 		PrintInfo("")
 		PrintInfo("Next steps:")
 		PrintInfo("  1. Edit schemas/example.cue or add your own schemas")
@@ -235,3 +396,64 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseInitOverrides parses the --output (name=path) and --option
+// (name.key=value) flags. Option values are read as YAML scalars, so
+// true and 3 become a bool and an int.
+func parseInitOverrides() (map[string]string, map[string]map[string]interface{}, error) {
+	outputs := make(map[string]string)
+	for _, entry := range initOutputs {
+		gen, path, ok := strings.Cut(entry, "=")
+		if !ok || gen == "" || path == "" {
+			return nil, nil, fmt.Errorf("invalid --output %q (expected name=path)", entry)
+		}
+		gen, err := resolveGenerator(gen)
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs[gen] = path
+	}
+
+	options := make(map[string]map[string]interface{})
+	for _, entry := range initOptions {
+		name, raw, ok := strings.Cut(entry, "=")
+		gen, key, dotted := strings.Cut(name, ".")
+		if !ok || !dotted || gen == "" || key == "" {
+			return nil, nil, fmt.Errorf("invalid --option %q (expected name.key=value)", entry)
+		}
+		gen, err := resolveGenerator(gen)
+		if err != nil {
+			return nil, nil, err
+		}
+		var value interface{} = raw
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+			value = raw
+		}
+		if options[gen] == nil {
+			options[gen] = make(map[string]interface{})
+		}
+		options[gen][key] = value
+	}
+	return outputs, options, nil
+}
+
+// resolveGenerator resolves a generator name or alias, suggesting the
+// closest generator for an unknown one
+func resolveGenerator(name string) (string, error) {
+	if _, err := generator.Get(name); err != nil {
+		return "", err
+	}
+	return generator.Resolve(name), nil
+}
+
+// writeModuleFile creates cue.mod/module.cue declaring a CUE module, unless
+// the directory already is one
+func writeModuleFile(dir, module string) error {
+	PrintVerbose("Creating CUE module: %s", module)
+	if _, err := cuemod.Init(dir, module); err == cuemod.ErrExists {
+		PrintVerbose("Keeping existing cue.mod/module.cue")
+	} else if err != nil {
+		return fmt.Errorf("failed to create cue.mod/module.cue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/platoorg/plato-sl-cli/internal/credentials"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	loginToken      string
+	loginTokenStdin bool
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login [registry]",
+	Short: "Store an auth token for a registry",
+	Long: `Store an auth token for a registry host. Tokens are kept in the OS keychain
+(macOS Keychain via security, Linux Secret Service via secret-tool) when
+available, otherwise in a credentials file readable only by the current user
+(<user config dir>/platosl/credentials.json).
+
+Stored tokens are used for remote operations against that host, after
+PLATOSL_TOKEN_<HOST> / PLATOSL_TOKEN and before network.tokens in platosl.yaml.
+
+Without a registry, the registry of the user config is used.
+
+Examples:
+  platosl login registry.example.com
+  echo "$TOKEN" | platosl login registry.example.com --token-stdin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogin,
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout [registry]",
+	Short: "Remove the stored auth token for a registry",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLogout,
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami [registry]",
+	Short: "Show registries with stored credentials",
+	Long: `Show the registries with stored credentials, the credential store in use
+and which token source is active for each registry.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWhoami,
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(whoamiCmd)
+	loginCmd.Flags().StringVar(&loginToken, "token", "", "auth token (prefer --token-stdin to keep it out of shell history)")
+	loginCmd.Flags().BoolVar(&loginTokenStdin, "token-stdin", false, "read the auth token from stdin")
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	registry, err := registryArg(args)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	token, err := readLoginToken(registry)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	store, err := credentials.Default()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if err := store.Set(registry, token); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintSuccess("Logged in to %s (stored in %s)", registry, store.Name())
+	return nil
+}
+
+func runLogout(cmd *cobra.Command, args []string) error {
+	registry, err := registryArg(args)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	store, err := credentials.Default()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if err := store.Delete(registry); err != nil {
+		if errors.Is(err, credentials.ErrNotFound) {
+			PrintInfo("Not logged in to %s", registry)
+			return nil
+		}
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintSuccess("Logged out of %s", registry)
+	return nil
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	store, err := credentials.Default()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	var registries []string
+	if len(args) == 1 {
+		registry, err := registryHost(args[0])
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		registries = []string{registry}
+	} else {
+		registries, err = store.List()
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	}
+
+	fmt.Printf("Credential store: %s\n", store.Name())
+	if len(registries) == 0 {
+		fmt.Println("Not logged in to any registry")
+		return nil
+	}
+
+	// PLATOSL_TOKEN is only sent to the registry of the user config
+	configured, _ := registryHost(userCfg.Registry)
+	if userCfg.Registry == "" {
+		configured = ""
+	}
+
+	fmt.Println()
+	for _, registry := range registries {
+		stored, _ := store.Get(registry)
+
+		// Environment tokens take precedence over stored credentials
+		switch {
+		case os.Getenv(httpclient.HostEnvVar(registry)) != "":
+			fmt.Printf("  %s: %s (from %s)\n", registry, credentials.Mask(os.Getenv(httpclient.HostEnvVar(registry))), httpclient.HostEnvVar(registry))
+		case os.Getenv("PLATOSL_TOKEN") != "" && registry == configured:
+			fmt.Printf("  %s: %s (from PLATOSL_TOKEN)\n", registry, credentials.Mask(os.Getenv("PLATOSL_TOKEN")))
+		case stored != "":
+			fmt.Printf("  %s: %s (stored)\n", registry, credentials.Mask(stored))
+		default:
+			fmt.Printf("  %s: not logged in\n", registry)
+		}
+	}
+
+	return nil
+}
+
+// readLoginToken reads the token from --token, stdin or an interactive prompt
+func readLoginToken(registry string) (string, error) {
+	var token string
+
+	switch {
+	case loginToken != "" && loginTokenStdin:
+		return "", fmt.Errorf("--token and --token-stdin are mutually exclusive")
+	case loginToken != "":
+		token = loginToken
+	case loginTokenStdin:
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read token from stdin: %w", err)
+		}
+		token = line
+	default:
+		prompt := &survey.Password{
+			Message: fmt.Sprintf("Token for %s:", registry),
+		}
+		if err := survey.AskOne(prompt, &token, survey.WithValidator(survey.Required)); err != nil {
+			return "", err
+		}
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("token must not be empty")
+	}
+	return token, nil
+}
+
+// registryArg returns the registry host given as argument, or the registry
+// of the user config
+func registryArg(args []string) (string, error) {
+	if len(args) == 1 {
+		return registryHost(args[0])
+	}
+	if userCfg.Registry == "" {
+		return "", fmt.Errorf("no registry given and none set in the user config")
+	}
+	return registryHost(userCfg.Registry)
+}
+
+// registryHost normalizes a registry argument (host or URL) to a host name,
+// matching how tokens are looked up for outgoing requests
+func registryHost(arg string) (string, error) {
+	raw := arg
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid registry %q", arg)
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
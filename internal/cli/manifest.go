@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/audit"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestOutput  string
+	manifestVersion string
+	manifestNotes   string
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Write a build manifest of generated artifacts",
+	Long: `Write a build manifest listing the outputs of all enabled generators with
+their SHA-256 hashes, the schema hash and the release version. The package
+metadata of platosl.yaml (license, authors, homepage) is attached.
+
+Publish the manifest alongside a schema release so consumers can check their
+vendored copies with 'platosl verify artifacts'. Run it after 'platosl build'.
+With --notes, the release notes (see 'platosl release notes') are attached,
+so they travel with the release.
+
+Examples:
+  platosl manifest --version v1.4.0 -o generated/manifest.json
+  platosl manifest --version v1.4.0 --notes RELEASE_NOTES.md`,
+	Args: cobra.NoArgs,
+	RunE: runManifest,
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.Flags().StringVarP(&manifestOutput, "output", "o", "generated/manifest.json", "manifest file path")
+	manifestCmd.Flags().StringVar(&manifestVersion, "version", "", "release version recorded in the manifest")
+	manifestCmd.Flags().StringVar(&manifestNotes, "notes", "", "release notes file to attach")
+}
+
+func runManifest(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemaHash, err := audit.SchemaHash(cfg.Schemas)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	m := &manifest.Manifest{
+		Project:    cfg.Name,
+		Version:    manifestVersion,
+		SchemaHash: schemaHash,
+		Created:    time.Now().UTC(),
+	}
+	if !cfg.Metadata.IsZero() {
+		m.Metadata = &cfg.Metadata
+	}
+	if manifestNotes != "" {
+		notes, err := os.ReadFile(manifestNotes)
+		if err != nil {
+			err = fmt.Errorf("failed to read release notes: %w", err)
+			PrintError("%v", err)
+			return err
+		}
+		m.Notes = string(notes)
+	}
+
+	var names []string
+	for name, genCfg := range cfg.Generate {
+		if genCfg.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := m.AddOutput(name, cfg.Generate[name].Output); err != nil {
+			PrintError("%v", err)
+			PrintInfo("Run 'platosl build' first to generate all enabled targets")
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format as JSON: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(manifestOutput), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(manifestOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	PrintSuccess("Wrote manifest with %d artifact(s) to %s", len(m.Artifacts), manifestOutput)
+	return nil
+}
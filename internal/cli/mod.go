@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/cuemod"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/platoorg/plato-sl-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modTidyDryRun bool
+	modVerifyJSON bool
+)
+
+var modCmd = &cobra.Command{
+	Use:   "mod",
+	Short: "Create and maintain the CUE module",
+	Long: `Create and maintain cue.mod/module.cue, the CUE module of the project: its
+module path, language version and the modules it requires.
+
+Requirements are derived from the imports of platosl.yaml and of the
+schemas, so cue.mod never needs editing by hand. Without a module, schemas
+importing other modules cannot be loaded as a CUE package, and their files
+are loaded one by one instead.`,
+}
+
+var modInitCmd = &cobra.Command{
+	Use:   "init <module>",
+	Short: "Create cue.mod/module.cue",
+	Long: `Create cue.mod/module.cue declaring a module with the given path, at the
+CUE language version of this CLI, and require the modules the project
+imports as 'mod tidy' does. A path without a major version gets @v0.
+
+Examples:
+  platosl mod init example.com/schemas
+  platosl mod init example.com/schemas@v1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runModInit,
+}
+
+var modTidyCmd = &cobra.Command{
+	Use:   "tidy",
+	Short: "Sync the module requirements with the imports",
+	Long: `Rewrite the requirements of cue.mod/module.cue to match the imports of the
+project:
+
+  - modules of the imports of platosl.yaml, at the version they pin (e.g.
+    example.com/geo@v1.2.0) or the latest of their major version (@v1)
+  - modules providing the packages imported by the schemas
+  - modules those modules require, at the highest version required
+
+Modules the project imports keep the version already required unless
+platosl.yaml pins another; new ones get their latest release from the
+registry. The modules they require follow their requirements, so pinning
+a lower version may lower those too. Modules no longer imported are
+removed. Local imports (./path) are skipped.
+
+Use --dry-run to print a diff of module.cue without writing it.
+
+Examples:
+  platosl mod tidy
+  platosl mod tidy --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runModTidy,
+}
+
+var modVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cue.mod/module.cue against the imports",
+	Long: `Check that cue.mod/module.cue is valid and that its requirements match the
+imports of the project, without changing it: every imported module is
+required at the version platosl.yaml pins or the modules need, and nothing
+else is required. The language version must not be newer than this CLI
+supports.
+
+Modules module.cue does not mention are not looked up, so verify only uses
+the registry for the requirements of required modules, which are usually
+in the import cache. It exits non-zero when there are problems, for CI.
+
+Examples:
+  platosl mod verify
+  platosl mod verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runModVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(modCmd)
+	modCmd.AddCommand(modInitCmd)
+	modCmd.AddCommand(modTidyCmd)
+	modCmd.AddCommand(modVerifyCmd)
+	modTidyCmd.Flags().BoolVar(&modTidyDryRun, "dry-run", false, "show a diff of module.cue without writing it")
+	modVerifyCmd.Flags().BoolVar(&modVerifyJSON, "json", false, "print the problems as JSON")
+}
+
+// modProject describes the project for deriving its requirements: the
+// working directory holds cue.mod, and the schemas and imports come from
+// platosl.yaml
+func modProject() (cuemod.Project, error) {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return cuemod.Project{}, err
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cuemod.Project{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	p := cuemod.Project{Root: root, Imports: cfg.ImportPaths()}
+	p.Policies = make(map[string]string)
+	for _, imp := range cfg.Imports {
+		if imp.Update != "" {
+			base, _, _ := strings.Cut(imp.Pkg, "@")
+			p.Policies[base] = imp.Update
+		}
+		if imp.As != "" {
+			p.Aliases = append(p.Aliases, cuemod.Alias{Name: imp.As, Path: imp.Pkg})
+		}
+	}
+	for _, schemaPath := range cfg.Schemas {
+		absPath, err := filepath.Abs(schemaPath)
+		if err != nil {
+			return cuemod.Project{}, fmt.Errorf("failed to resolve schema path: %w", err)
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return cuemod.Project{}, fmt.Errorf("schema path not found: %s", schemaPath)
+		}
+		p.Dirs = append(p.Dirs, absPath)
+	}
+	return p, nil
+}
+
+// modRegistry returns the registry modules are resolved through: the
+// import cache, or CUE's own registry client when it is disabled
+func modRegistry() (modconfig.Registry, error) {
+	if reg := platoCue.Registry(); reg != nil {
+		return reg, nil
+	}
+	return modconfig.NewRegistry(nil)
+}
+
+// tidyModule derives the requirements of the project and writes them to
+// module.cue, unless dryRun, in which case the diff is printed
+func tidyModule(p cuemod.Project, f *modfile.File, dryRun bool) ([]cuemod.Change, error) {
+	reg, err := modRegistry()
+	if err != nil {
+		return nil, err
+	}
+	PrintVerbose("Resolving the imports of %d schema path(s)", len(p.Dirs))
+	tidied, changes, err := cuemod.Tidy(context.Background(), reg, p, f)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if dryRun {
+		before, err := modfile.Format(f)
+		if err != nil {
+			return nil, err
+		}
+		after, err := modfile.Format(tidied)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join("cue.mod", "module.cue")
+		fmt.Print(diff.Unified(path, path+" (tidied)", before, after))
+		return changes, nil
+	}
+	return changes, cuemod.Save(p.Root, tidied)
+}
+
+// syncLayers writes the re-export layers of the import aliases into the
+// schema directories and removes layers no longer needed, unless dryRun,
+// in which case their diff is printed
+func syncLayers(p cuemod.Project, dryRun bool) ([]cuemod.LayerChange, error) {
+	changes, err := cuemod.Layers(p.Dirs, p.Aliases)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	if !dryRun {
+		return changes, cuemod.WriteLayers(changes)
+	}
+	for _, c := range changes {
+		before, _ := os.ReadFile(c.Path)
+		rel, err := filepath.Rel(p.Root, c.Path)
+		if err != nil {
+			rel = c.Path
+		}
+		fmt.Print(diff.Unified(rel, rel+" (tidied)", before, c.Content))
+	}
+	return changes, nil
+}
+
+// layerSummary describes a layer change for the output of tidy
+func layerSummary(root string, c cuemod.LayerChange) string {
+	rel, err := filepath.Rel(root, c.Path)
+	if err != nil {
+		rel = c.Path
+	}
+	if c.Content == nil {
+		return "remove " + rel
+	}
+	return "write " + rel
+}
+
+func runModInit(cmd *cobra.Command, args []string) error {
+	p, err := modProject()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	f, err := cuemod.Init(p.Root, args[0])
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeConfig, err, "failed to create the CUE module")
+		if err == cuemod.ErrExists {
+			e = e.WithSuggestion("Run 'platosl mod tidy' to update its requirements")
+		} else {
+			e = e.WithSuggestion("Use a module path such as example.com/schemas or example.com/schemas@v1")
+		}
+		PrintError("%s", e.Format())
+		return e
+	}
+	PrintSuccess("Created cue.mod/module.cue for %s (language %s)", f.QualifiedModule(), f.Language.Version)
+
+	layers, err := syncLayers(p, false)
+	if err != nil {
+		PrintWarning("Could not write the re-export layers: %v", err)
+	}
+	for _, c := range layers {
+		PrintInfo("  %s", layerSummary(p.Root, c))
+	}
+
+	changes, err := tidyModule(p, f, false)
+	if err != nil {
+		PrintWarning("Could not require the imported modules: %v", err)
+		PrintWarning("Run 'platosl mod tidy' once the registry is reachable")
+		return nil
+	}
+	for _, change := range changes {
+		PrintInfo("  %s", change)
+	}
+	return nil
+}
+
+func runModTidy(cmd *cobra.Command, args []string) error {
+	p, err := modProject()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	f, err := cuemod.Load(p.Root)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	// Layers first, so the imports they declare are required
+	layers, err := syncLayers(p, modTidyDryRun)
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeConfig, err, "failed to write the re-export layers")
+		e = e.WithSuggestion("Check the import aliases (as) of platosl.yaml")
+		PrintError("%s", e.Format())
+		return e
+	}
+
+	changes, err := tidyModule(p, f, modTidyDryRun)
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeConfig, err, "failed to tidy cue.mod/module.cue")
+		e = e.WithSuggestion("Check the imports of platosl.yaml and the schemas, and that the registry (CUE_REGISTRY) is reachable")
+		PrintError("%s", e.Format())
+		return e
+	}
+	if len(changes) == 0 && len(layers) == 0 {
+		PrintSuccess("cue.mod/module.cue is tidy")
+		return nil
+	}
+
+	if modTidyDryRun {
+		PrintInfo("\n%d change(s) would be made:", len(changes)+len(layers))
+	} else {
+		PrintSuccess("Tidied cue.mod/module.cue")
+	}
+	for _, c := range layers {
+		PrintInfo("  %s", layerSummary(p.Root, c))
+	}
+	for _, change := range changes {
+		PrintInfo("  %s", change)
+	}
+	return nil
+}
+
+func runModVerify(cmd *cobra.Command, args []string) error {
+	p, err := modProject()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	f, err := cuemod.Load(p.Root)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	reg, err := modRegistry()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	problems, err := cuemod.Verify(context.Background(), reg, p, f)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if modVerifyJSON {
+		if problems == nil {
+			problems = []cuemod.Problem{}
+		}
+		data, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else if len(problems) == 0 {
+		PrintSuccess("cue.mod/module.cue matches the imports of %s", f.QualifiedModule())
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if !modVerifyJSON {
+		for _, problem := range problems {
+			PrintInfo("  %s", problem.Message)
+		}
+	}
+	e := errors.New(errors.ErrorTypeValidation, fmt.Sprintf("cue.mod/module.cue has %d problem(s)", len(problems)))
+	e = e.WithSuggestion("Run 'platosl mod tidy' to fix the requirements")
+	PrintError("%s", e.Format())
+	return e
+}
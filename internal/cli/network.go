@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/credentials"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// newHTTPClient creates the shared HTTP client for remote operations from
+// the project's network config, over the defaults of the user config. An
+// explicit token, if given, takes precedence over tokens resolved from the
+// environment, stored credentials and config. PLATOSL_TOKEN only goes to
+// the registry of the user config and the given registries.
+func newHTTPClient(cfg *config.Config, token string, registries ...string) (*httpclient.Client, error) {
+	opts, err := httpClientOptions(cfg, token, registries...)
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.New(opts)
+}
+
+// httpClientOptions returns the options of the client of newHTTPClient
+func httpClientOptions(cfg *config.Config, token string, registries ...string) (httpclient.Options, error) {
+	network := config.MergeNetwork(cfg.Network, userCfg.Network)
+	opts, err := httpclient.OptionsFromConfig(network)
+	if err != nil {
+		return opts, err
+	}
+
+	if registry, err := registryHost(userCfg.Registry); err == nil && userCfg.Registry != "" {
+		registries = append(registries, registry)
+	}
+	opts.UserAgent = "platosl/" + Version
+	opts.Token = httpclient.TokenChain(
+		func(string) string { return token },
+		httpclient.EnvTokens(registries...),
+		credentials.Token,
+		httpclient.ConfigTokens(network.Tokens),
+	)
+	return opts, nil
+}
+
+// newKeyedHTTPClient creates an HTTP client like newHTTPClient that only
+// sends the given key, for services that must not get any other token
+func newKeyedHTTPClient(cfg *config.Config, key string) (*httpclient.Client, error) {
+	opts, err := httpclient.OptionsFromConfig(config.MergeNetwork(cfg.Network, userCfg.Network))
+	if err != nil {
+		return nil, err
+	}
+	opts.UserAgent = "platosl/" + Version
+	opts.Token = func(string) string { return key }
+	return httpclient.New(opts)
+}
+
+// degraded handles the failure of an optional network subsystem, one the
+// command can complete without: a warning by default, so flaky networks do
+// not block local work, or an error with --strict-network
+func degraded(subsystem string, err error) error {
+	if strictNetwork {
+		PrintError("%s: %v", subsystem, err)
+		return fmt.Errorf("%s: %w", subsystem, err)
+	}
+	PrintWarning("%s unavailable, continuing without it: %v", subsystem, err)
+	PrintWarning("Use --strict-network to make this an error")
+	return nil
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/platoorg/plato-sl-cli/internal/cuemod"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outdatedFormat string
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List newer versions of the imported modules",
+	Long: `List the modules the project imports directly, from platosl.yaml or the
+schemas, that have a newer release in the registry than the version they
+are pinned or required at.
+
+Each module shows:
+
+  CURRENT  the version pinned in platosl.yaml or required by module.cue
+  WANTED   the newest version its update policy allows, '-' for none
+  LATEST   the newest release
+  CHANGE   patch, minor or major from CURRENT to LATEST, marked breaking
+           for major changes and minor changes of v0 modules
+  POLICY   the update policy of the import
+
+The update policy of an import is set with update in platosl.yaml:
+
+  imports:
+    - pkg: example.com/geo@v1.2.0
+      update: patch
+
+  none   keep the version
+  patch  patch releases of the same minor version
+  minor  releases of the same major version (default)
+  major  any release
+
+Use 'platosl upgrade <module>' to move an import to its wanted version.
+
+Formats:
+  table    aligned columns (default)
+  compact  tab-separated rows without a header, for grep, cut and awk
+  json     machine-readable
+
+Examples:
+  platosl outdated
+  platosl outdated --format json`,
+	Args: cobra.NoArgs,
+	RunE: runOutdated,
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().StringVar(&outdatedFormat, "format", "table", "output format (table, compact, json)")
+}
+
+// outdatedModules returns the newer versions of the modules the project
+// imports directly
+func outdatedModules() (cuemod.Project, []cuemod.Update, error) {
+	p, err := modProject()
+	if err != nil {
+		return p, nil, err
+	}
+	f, err := cuemod.Load(p.Root)
+	if err != nil {
+		return p, nil, err
+	}
+	reg, err := modRegistry()
+	if err != nil {
+		return p, nil, err
+	}
+	PrintVerbose("Looking up the versions of the imports of %d schema path(s)", len(p.Dirs))
+	updates, err := cuemod.Outdated(context.Background(), reg, p, f)
+	return p, updates, err
+}
+
+func runOutdated(cmd *cobra.Command, args []string) error {
+	switch outdatedFormat {
+	case "table", "compact", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected table, compact or json)", outdatedFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	_, updates, err := outdatedModules()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if outdatedFormat == "json" {
+		if updates == nil {
+			updates = []cuemod.Update{}
+		}
+		data, err := json.MarshalIndent(updates, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(updates) == 0 {
+		PrintSuccess("All imported modules are up to date")
+		return nil
+	}
+
+	t := newTable("MODULE", "CURRENT", "WANTED", "LATEST", "CHANGE", "POLICY")
+	for _, u := range updates {
+		current := u.Current
+		if u.Pinned {
+			current += " (pinned)"
+		}
+		t.AddRow(u.Module, current, orDash(u.Wanted), u.Latest, changeLabel(u.Change, u.Breaking), u.Policy)
+	}
+	if outdatedFormat == "compact" {
+		return t.RenderCompact(os.Stdout)
+	}
+	return t.Render(os.Stdout)
+}
+
+// changeLabel describes a version change, e.g. "major (breaking)"
+func changeLabel(change string, breaking bool) string {
+	if breaking {
+		return change + " (breaking)"
+	}
+	return change
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/project"
+)
+
+// dependencyLoader returns a loader for the schemas of the current project
+// with the definitions published by the projects it depends on in scope,
+// and those definitions. ok is false when the project has no dependencies.
+func dependencyLoader(loader *platoCue.Loader, cfg *config.Config) (*platoCue.Loader, cue.Value, bool, error) {
+	deps, ok, err := project.Published(loader, filepath.Dir(GetConfigFile()), cfg)
+	if err != nil || !ok {
+		return loader, cue.Value{}, false, err
+	}
+	return loader.WithScope(deps), deps, true, nil
+}
+
+// loadSchemaPaths loads schema paths of the current project with the
+// definitions published by its dependencies in scope and included, so
+// generators see the definitions the schemas refer to
+func loadSchemaPaths(loader *platoCue.Loader, cfg *config.Config, paths []string) (cue.Value, error) {
+	scoped, deps, ok, err := dependencyLoader(loader, cfg)
+	if err != nil {
+		return cue.Value{}, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	val, err := scoped.LoadPaths(paths)
+	if err != nil || !ok {
+		return val, err
+	}
+	val = val.Unify(deps)
+	return val, val.Err()
+}
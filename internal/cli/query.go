@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryData    string
+	querySchema  string
+	queryCompact bool
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "Query the schema model or data with a jq-like expression",
+	Long: `Query the introspected schema model, or a validated data file, with a
+small jq-like language and print the results as JSON.
+
+Without --data, the input is the schema model of the configured schemas: an
+object keyed by definition name, each with a 'name' and a list of 'fields'
+(name, type, optional, path).
+
+With --data, the input is the given JSON, YAML or CUE data file. Use --schema
+to validate the data against a definition before querying it.
+
+Supported syntax:
+  .  .a.b  .#Def        identity and field access
+  .a[]  .a[0]           iterate or index lists
+  select(cond)          filter; cond is an expression optionally compared
+                        to a JSON literal (==, !=, <, <=, >, >=), joined
+                        by and/or
+  keys  length          struct keys and collection length
+  a | b                 pipe results of a into b
+
+Examples:
+  platosl query '.#Order.fields[] | select(.optional)'
+  platosl query '.[] | select(.fields | length > 10) | .name'
+  platosl query '.items[] | select(.price > 100)' --data catalog.json --schema '#Catalog'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.Flags().StringVar(&queryData, "data", "", "query a data file instead of the schema model")
+	queryCmd.Flags().StringVar(&querySchema, "schema", "", "definition to validate --data against before querying")
+	queryCmd.Flags().BoolVarP(&queryCompact, "compact", "c", false, "print each result on a single line")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	input, err := loadQueryInput()
+	if err != nil {
+		return err
+	}
+
+	results, err := query.Run(args[0], input)
+	if err != nil {
+		PrintError("Query failed: %v", err)
+		return err
+	}
+
+	for _, result := range results {
+		var data []byte
+		if queryCompact {
+			data, err = json.Marshal(result)
+		} else {
+			data, err = json.MarshalIndent(result, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+
+	return nil
+}
+
+// loadQueryInput builds the query input from the schema model or a data file
+func loadQueryInput() (interface{}, error) {
+	if queryData == "" && querySchema != "" {
+		return nil, fmt.Errorf("--schema requires --data")
+	}
+
+	loader := platoCue.NewLoader()
+	var schemas cue.Value
+
+	if queryData == "" || querySchema != "" {
+		cfg, err := config.Load(GetConfigFile())
+		if err != nil {
+			return nil, err
+		}
+
+		schemas, err = loadAndValidateSchemas(cfg, "query")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Schema model mode
+	if queryData == "" {
+		defs, err := platoCue.IntrospectDefinitions(schemas)
+		if err != nil {
+			err = fmt.Errorf("failed to introspect schemas: %w", err)
+			PrintError("%v", err)
+			return nil, err
+		}
+		return toGeneric(defsByName(defs))
+	}
+
+	// Data mode
+	PrintVerbose("Loading data: %s", queryData)
+	data, err := loader.LoadDataFile(queryData)
+	if err != nil {
+		PrintError("%v", err)
+		return nil, err
+	}
+
+	if querySchema != "" {
+		def, err := platoCue.LookupDefinition(schemas, querySchema)
+		if err != nil {
+			PrintError("%v", err)
+			return nil, err
+		}
+
+		result := platoCue.ValidateData(def, data)
+		if !result.Valid {
+			PrintError("%s does not match %s:", queryData, querySchema)
+			for _, verr := range result.Errors {
+				PrintError("  %s", platoCue.FormatError(verr))
+			}
+			return nil, fmt.Errorf("data validation failed")
+		}
+		data = def.Unify(data)
+	}
+
+	return platoCue.NewEvaluator(loader).Evaluate(data)
+}
+
+// defsByName indexes definitions by name for querying
+func defsByName(defs []platoCue.DefinitionInfo) map[string]platoCue.DefinitionInfo {
+	byName := make(map[string]platoCue.DefinitionInfo, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	return byName
+}
+
+// toGeneric converts a typed value into plain maps and slices via JSON
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/platoorg/plato-sl-cli/internal/project"
+	"github.com/platoorg/plato-sl-cli/internal/release"
+	"github.com/platoorg/plato-sl-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseNotesVersion  string
+	releaseNotesTemplate string
+	releaseNotesOutput   string
+	releaseNotesFormat   string
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Prepare schema releases",
+}
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes <from-ref> [to-ref]",
+	Short: "Write the release notes of a schema version",
+	Long: `Write the release notes of the schemas between two git refs, or between a
+ref and the working tree when to-ref is omitted, usually the previous
+release tag. The notes combine:
+
+  - schema changes: definitions and fields added, removed or changed,
+    breaking ones first
+  - generated code: how the output of each enabled generator changes, in
+    lines added and removed, rendered in memory like 'platosl diff
+    --generated'
+  - affected consumers: the projects of the repository depending on these
+    schemas (dependsOn) that reference changed definitions
+
+Removed definitions and fields, new required fields, fields becoming
+required or optional and narrowed types are breaking.
+
+The notes are markdown rendered from a Go text/template; --template gives
+your own, with the same data (see the JSON format for the fields). Attach
+them to the release with 'platosl manifest --notes'.
+
+Formats:
+  markdown  rendered release notes (default)
+  json      the data the template gets
+
+Examples:
+  platosl release notes v1.3.0 --version v1.4.0 -o RELEASE_NOTES.md
+  platosl release notes v1.3.0 v1.4.0 --template .platosl/notes.tmpl
+  platosl release notes v1.3.0 --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runReleaseNotes,
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.AddCommand(releaseNotesCmd)
+	releaseNotesCmd.Flags().StringVar(&releaseNotesVersion, "version", "", "version being released (default: metadata.version of platosl.yaml)")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesTemplate, "template", "", "markdown template file (default: built-in)")
+	releaseNotesCmd.Flags().StringVarP(&releaseNotesOutput, "output", "o", "", "output file path (default stdout)")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesFormat, "format", "markdown", "output format (markdown, json)")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	if releaseNotesFormat != "markdown" && releaseNotesFormat != "json" {
+		err := fmt.Errorf("unknown format %q (expected markdown or json)", releaseNotesFormat)
+		PrintError("%v", err)
+		return err
+	}
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	var tmpl string
+	if releaseNotesTemplate != "" {
+		data, err := os.ReadFile(releaseNotesTemplate)
+		if err != nil {
+			err = fmt.Errorf("failed to read template: %w", err)
+			PrintError("%v", err)
+			return err
+		}
+		tmpl = string(data)
+	}
+
+	var names []string
+	for name, genCfg := range cfg.Generate {
+		if genCfg.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Extract the schemas, CUE module and any state generators read
+	paths := append([]string{"cue.mod"}, cfg.Schemas...)
+	if cfg.Generate["protobuf"].Enabled {
+		paths = append(paths, protobufLockFile(cfg))
+	}
+	sides := make([]*diffSide, 2)
+	for i := range sides {
+		if i >= len(args) {
+			sides[i] = &diffSide{label: "working tree"}
+			continue
+		}
+		snap, err := snapshot.Extract(args[i], paths)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		defer snap.Remove()
+		sides[i] = &diffSide{label: args[i], snap: snap}
+	}
+
+	// Both sides share a context, so their definitions can be compared
+	loader := platoCue.NewLoader()
+	values := make([]cue.Value, 2)
+	for i, side := range sides {
+		val, err := loadSideSchemas(loader, cfg, side)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", side.label, err)
+			PrintError("%v", err)
+			return err
+		}
+		values[i] = val
+	}
+
+	notes := &release.Notes{
+		Project: cfg.Name,
+		Version: releaseNotesVersion,
+		From:    sides[0].label,
+		To:      sides[1].label,
+		Date:    time.Now().UTC(),
+		Changes: release.DiffSchemas(values[0], values[1]),
+	}
+	if notes.Version == "" {
+		notes.Version = cfg.Metadata.Version
+	}
+
+	tmp, err := os.MkdirTemp("", "platosl-release-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	for _, name := range names {
+		PrintVerbose("Rendering %s at %s and %s", name, sides[0].label, sides[1].label)
+		outputs, output, err := generateSides(cfg, name, values, sides, tmp)
+		if err != nil {
+			PrintWarning("Leaving %s out of the release notes: %v", name, err)
+			continue
+		}
+		a := release.Artifact{Generator: name, Path: filepath.ToSlash(output), Status: "unchanged"}
+		a.Added, a.Removed = diff.Stat(outputs[0], outputs[1])
+		if a.Added+a.Removed > 0 {
+			a.Status = "changed"
+		}
+		notes.Artifacts = append(notes.Artifacts, a)
+	}
+
+	consumers, err := releaseConsumers(notes.Changes)
+	if err != nil {
+		PrintWarning("Could not find the affected consumers: %v", err)
+	}
+	notes.Consumers = consumers
+
+	var data []byte
+	if releaseNotesFormat == "json" {
+		if notes.Changes == nil {
+			notes.Changes = []release.Change{}
+		}
+		if notes.Artifacts == nil {
+			notes.Artifacts = []release.Artifact{}
+		}
+		if notes.Consumers == nil {
+			notes.Consumers = []release.Consumer{}
+		}
+		if data, err = json.MarshalIndent(notes, "", "  "); err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		data = append(data, '\n')
+	} else if data, err = notes.Render(tmpl); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if releaseNotesOutput == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(releaseNotesOutput), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(releaseNotesOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write release notes: %w", err)
+	}
+	PrintSuccess("Wrote release notes to %s (%d change(s), %d breaking)", releaseNotesOutput, len(notes.Changes), len(notes.Breaking()))
+	return nil
+}
+
+// releaseConsumers finds the projects of the repository that depend on
+// this one and reference changed definitions
+func releaseConsumers(changes []release.Change) ([]release.Consumer, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(GetConfigFile()))
+	if err != nil {
+		return nil, err
+	}
+	root, err := snapshot.Toplevel()
+	if err != nil {
+		return nil, err
+	}
+	projects, err := project.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	return release.Consumers(root, dir, projects, changes)
+}
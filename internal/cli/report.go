@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportOutput     string
+	reportDefinition string
+	reportTitle      string
+	reportFormat     string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Produce shareable reports",
+	Long:  `Produce reports that can be read without the terminal, e.g. by content teams.`,
+}
+
+var reportValidateCmd = &cobra.Command{
+	Use:   "validate <data file or directory>...",
+	Short: "Validate data files and write an HTML report",
+	Long: `Validate JSON, YAML and CUE data files against a definition and write a
+self-contained HTML report: pass/fail per file, each error with its field
+path, and charts breaking errors down by kind and by field.
+
+Directories are scanned recursively. The report is written even when files
+fail validation; the command then exits with an error, so it can gate CI
+while the report is published as an artifact.
+
+Examples:
+  platosl report validate content/products --definition '#Product'
+  platosl report validate content/ --definition '#Article' -o build/content-report.html`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReportValidate,
+}
+
+var reportCoverageCmd = &cobra.Command{
+	Use:   "coverage <data file or directory>...",
+	Short: "Report schema constraints a data corpus never exercises",
+	Long: `Analyse a corpus of data files against a definition and report the parts of
+the schema that no file exercises, so schema owners can prune or reconsider
+them:
+
+  optional-field  an optional field that is never present
+  branch          a disjunction branch never used, e.g. an enum value or
+                  one of several accepted types or definitions
+  empty-list      a list that is always empty
+
+Directories are scanned recursively. Files that cannot be loaded are skipped.
+
+Examples:
+  platosl report coverage content/products --definition '#Product'
+  platosl report coverage content/ --definition '#Article' --format json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReportCoverage,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportValidateCmd)
+
+	reportValidateCmd.Flags().StringVarP(&reportOutput, "output", "o", "report.html", "output file path")
+	reportValidateCmd.Flags().StringVar(&reportDefinition, "definition", "", "definition the data files conform to (required)")
+	reportValidateCmd.Flags().StringVar(&reportTitle, "title", "", "report title (default \"<project> validation report\")")
+	reportValidateCmd.MarkFlagRequired("definition")
+
+	reportCmd.AddCommand(reportCoverageCmd)
+	reportCoverageCmd.Flags().StringVar(&reportDefinition, "definition", "", "definition the data files conform to (required)")
+	reportCoverageCmd.Flags().StringVar(&reportFormat, "format", "text", "output format (text, json)")
+	reportCoverageCmd.MarkFlagRequired("definition")
+}
+
+func runReportValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "report")
+	if err != nil {
+		return err
+	}
+
+	if _, err := platoCue.LookupDefinition(schemas, reportDefinition); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	files, err := report.Validate(args, definitionLoader(cfg, reportDefinition))
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	title := reportTitle
+	if title == "" {
+		title = cfg.Name + " validation report"
+	}
+	validation := &report.Validation{
+		Title:      title,
+		Definition: reportDefinition,
+		Generated:  time.Now(),
+		Files:      files,
+	}
+
+	if dir := filepath.Dir(reportOutput); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			PrintError("Failed to create output directory: %v", err)
+			return err
+		}
+	}
+
+	out, err := os.Create(reportOutput)
+	if err != nil {
+		PrintError("Failed to create report: %v", err)
+		return err
+	}
+	if err := report.WriteHTML(out, validation); err != nil {
+		out.Close()
+		PrintError("%v", err)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		PrintError("Failed to write report: %v", err)
+		return err
+	}
+
+	for _, f := range files {
+		if !f.Passed() {
+			PrintVerbose("%s: %d error(s)", f.Path, len(f.Errors))
+		}
+	}
+
+	if failed := validation.Failed(); failed > 0 {
+		PrintError("%d of %d data file(s) failed validation (%d errors), report written to %s",
+			failed, len(files), validation.ErrorCount(), reportOutput)
+		return fmt.Errorf("data validation failed")
+	}
+
+	PrintSuccess("All %d data file(s) are valid, report written to %s", len(files), reportOutput)
+	return nil
+}
+
+func runReportCoverage(cmd *cobra.Command, args []string) error {
+	if reportFormat != "text" && reportFormat != "json" {
+		err := fmt.Errorf("unsupported format %q (supported: text, json)", reportFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "report")
+	if err != nil {
+		return err
+	}
+
+	def, err := platoCue.LookupDefinition(schemas, reportDefinition)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	coverage, err := report.AnalyzeCoverage(platoCue.NewLoader(), def, reportDefinition, args)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if reportFormat == "json" {
+		data, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, path := range coverage.Skipped {
+		PrintInfo("Skipped %s: not a loadable data file", path)
+	}
+
+	if coverage.Files == 0 {
+		PrintError("No data files to analyse")
+		return fmt.Errorf("no data files")
+	}
+
+	if len(coverage.Findings) == 0 {
+		PrintSuccess("%d data file(s) exercise every part of %s", coverage.Files, reportDefinition)
+		return nil
+	}
+
+	width := 0
+	for _, f := range coverage.Findings {
+		if len(f.Path) > width {
+			width = len(f.Path)
+		}
+	}
+
+	fmt.Printf("%s: %d unexercised constraint(s) across %d data file(s)\n\n", reportDefinition, len(coverage.Findings), coverage.Files)
+	for _, f := range coverage.Findings {
+		fmt.Printf("  %-*s  %s\n", width, f.Path, f.Message)
+	}
+	return nil
+}
+
+// schemaLoader returns a function that loads the configured schemas into a
+// new loader, for worker pools that need a CUE context per worker
+func schemaLoader(cfg *config.Config) func() (*platoCue.Loader, cue.Value, error) {
+	return func() (*platoCue.Loader, cue.Value, error) {
+		var paths []string
+		for _, schemaPath := range cfg.Schemas {
+			absPath, err := filepath.Abs(schemaPath)
+			if err != nil {
+				return nil, cue.Value{}, fmt.Errorf("failed to resolve schema path %s: %w", schemaPath, err)
+			}
+			paths = append(paths, absPath)
+		}
+
+		loader := platoCue.NewLoader()
+		schemas, err := loadSchemaPaths(loader, cfg, paths)
+		if err != nil {
+			return nil, cue.Value{}, err
+		}
+		return loader, schemas, nil
+	}
+}
+
+// definitionLoader is schemaLoader followed by looking up a definition
+func definitionLoader(cfg *config.Config, definition string) func() (*platoCue.Loader, cue.Value, error) {
+	load := schemaLoader(cfg)
+	return func() (*platoCue.Loader, cue.Value, error) {
+		loader, schemas, err := load()
+		if err != nil {
+			return nil, cue.Value{}, err
+		}
+		def, err := platoCue.LookupDefinition(schemas, definition)
+		if err != nil {
+			return nil, cue.Value{}, err
+		}
+		return loader, def, nil
+	}
+}
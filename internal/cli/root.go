@@ -3,13 +3,32 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/deprecation"
+	"github.com/platoorg/plato-sl-cli/internal/importcache"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile     string
+	verbose     bool
+	maxWorkers  int
+	memoryLimit string
+	noColor     bool
+
+	// strictNetwork makes failures of optional network subsystems fatal
+	strictNetwork bool
+
+	// userCfg holds the user-level defaults, loaded before every command
+	userCfg = &config.UserConfig{}
+
+	// importCache caches the modules imported by schemas
+	importCache *importcache.Registry
 )
 
 var rootCmd = &cobra.Command{
@@ -18,18 +37,118 @@ var rootCmd = &cobra.Command{
 	Long: `PlatoSL is a CLI tool for managing CUE-based schemas for content validation.
 It provides commands for initialization, validation, and code generation from
 CUE schemas to TypeScript, JSON Schema, Go, and Elixir.`,
-	SilenceUsage:  true,
-	SilenceErrors: true,
-	Version:       Version,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	Version:           Version,
+	PersistentPreRunE: setup,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if importCache != nil {
+		importCache.Close()
+	}
+	// Cobra's own errors (unknown commands, with suggestions) are not
+	// printed by a command
+	if err != nil && strings.HasPrefix(err.Error(), "unknown command") {
+		PrintError("%s", strings.ReplaceAll(strings.TrimSpace(err.Error()), "\n\n", "\n"))
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", rootCmd.Name())
+	}
+	return err
 }
 
 func init() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		PrintError("%v", err)
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		return err
+	})
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is platosl.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().IntVar(&maxWorkers, "max-workers", 0, "maximum parallel workers (default: number of CPUs, env PLATOSL_MAX_WORKERS)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&memoryLimit, "memory-limit", "", "memory budget, e.g. 512MiB (default: unlimited, env PLATOSL_MEMORY_LIMIT)")
+	rootCmd.PersistentFlags().BoolVar(&strictNetwork, "strict-network", false, "fail when an optional network service such as the audit endpoint is unreachable (env PLATOSL_STRICT_NETWORK=1)")
+}
+
+// setup loads the user config, warns about deprecated flags and applies the
+// resource flags before every command
+func setup(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadUser()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	userCfg = cfg
+
+	for _, d := range deprecation.Flags(cmd.CommandPath()) {
+		if cmd.Flags().Changed(d.Name) {
+			deprecation.Warn(d)
+		}
+	}
+	if !cmd.Flags().Changed("strict-network") && os.Getenv("PLATOSL_STRICT_NETWORK") == "1" {
+		strictNetwork = true
+	}
+	configureImportCache()
+	return configureWorkers(cmd)
+}
+
+// configureImportCache fetches imported modules through the cache backend
+// of the user config; the store is opened when a module is first needed.
+// Without a cache directory, CUE's own module cache is used.
+func configureImportCache() {
+	dir, err := config.ImportCacheDir(userCfg.Cache)
+	if err != nil {
+		PrintVerbose("Import cache disabled: %v", err)
+		return
+	}
+	importCache = importcache.NewRegistry(userCfg.Cache.Backend, dir)
+	importCache.Offline = func(err error) error {
+		return degraded("Module registry", err)
+	}
+	platoCue.SetRegistry(importCache)
+}
+
+// configureWorkers applies the resource flags, falling back to environment
+// variables so CI containers can set them once for every invocation, and
+// then to the user config
+func configureWorkers(cmd *cobra.Command) error {
+	workerCount := maxWorkers
+	if !cmd.Flags().Changed("max-workers") {
+		workerCount = userCfg.MaxWorkers
+		if env := os.Getenv("PLATOSL_MAX_WORKERS"); env != "" {
+			n, err := strconv.Atoi(env)
+			if err != nil {
+				PrintError("Invalid PLATOSL_MAX_WORKERS %q", env)
+				return err
+			}
+			workerCount = n
+		}
+	}
+	if workerCount < 0 {
+		PrintError("--max-workers must not be negative")
+		return fmt.Errorf("invalid worker count: %d", workerCount)
+	}
+
+	limit := memoryLimit
+	if limit == "" {
+		limit = os.Getenv("PLATOSL_MEMORY_LIMIT")
+	}
+	if limit == "" {
+		limit = userCfg.MemoryLimit
+	}
+	var budget int64
+	if limit != "" {
+		var err error
+		if budget, err = workers.ParseSize(limit); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+	}
+
+	workers.Configure(workerCount, budget)
+	PrintVerbose("Using up to %d worker(s)", workers.MaxWorkers())
+	return nil
 }
 
 // IsVerbose returns whether verbose mode is enabled
@@ -50,6 +169,11 @@ func PrintError(msg string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, "✗ "+msg+"\n", args...)
 }
 
+// PrintWarning prints a warning that does not stop the command
+func PrintWarning(msg string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "warning: "+msg+"\n", args...)
+}
+
 // PrintSuccess prints a success message with formatting
 func PrintSuccess(msg string, args ...interface{}) {
 	fmt.Printf("✓ "+msg+"\n", args...)
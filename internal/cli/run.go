@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/examples"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runExamplesFormat string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run suites against the schemas",
+}
+
+var runExamplesCmd = &cobra.Command{
+	Use:   "examples [directory]",
+	Short: "Run example data files as an acceptance suite",
+	Long: `Run the examples in a directory (default examples/) as an acceptance suite
+for the schemas. Each subdirectory is one example, with a data file (.json,
+.yaml or .cue) and an expect.cue describing the expected outcome:
+
+  definition:  "#Order"          // definition to validate against
+  valid:       false             // whether the data should pass
+  errors:      ["quantity"]      // optional: substrings of expected errors
+  description: "negative quantity is rejected"
+
+Every expected error must match the message or field path of at least one
+validation error. Results are printed as TAP (default) or JSON, and the
+command fails if any example fails.
+
+Examples:
+  platosl run examples
+  platosl run examples test/examples --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExamples,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.AddCommand(runExamplesCmd)
+	runExamplesCmd.Flags().StringVar(&runExamplesFormat, "format", "tap", "output format (tap, json)")
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	if runExamplesFormat != "tap" && runExamplesFormat != "json" {
+		PrintError("Unknown format %q (expected tap or json)", runExamplesFormat)
+		return fmt.Errorf("unknown format: %s", runExamplesFormat)
+	}
+
+	dir := "examples"
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	exs, err := examples.Discover(dir)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(exs) == 0 {
+		PrintError("No examples found in %s (each example needs an %s)", dir, examples.ExpectFile)
+		return fmt.Errorf("no examples found")
+	}
+
+	// Each worker loads the schemas into its own CUE context
+	type state struct {
+		loader  *platoCue.Loader
+		schemas cue.Value
+	}
+	load := schemaLoader(cfg)
+	results := make([]examples.Result, len(exs))
+	err = workers.Run(len(exs), func() (state, error) {
+		loader, schemas, err := load()
+		return state{loader: loader, schemas: schemas}, err
+	}, func(s state, i int) error {
+		results[i] = examples.Run(s.loader, s.schemas, exs[i])
+		return nil
+	})
+	if err != nil {
+		PrintError("Failed to load schemas: %v", err)
+		return err
+	}
+
+	if runExamplesFormat == "json" {
+		err = examples.WriteJSON(os.Stdout, results)
+	} else {
+		err = examples.WriteTAP(os.Stdout, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	if summary := examples.Summarize(results); summary.Failed > 0 {
+		PrintError("%d of %d example(s) failed", summary.Failed, summary.Total)
+		return fmt.Errorf("examples failed")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchFormat    string
+	searchNoImports bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search-schema <text>",
+	Short: "Search definitions and fields by name, doc comment or attribute",
+	Long: `Search the definitions and fields of the configured schemas, and of the
+packages they import, for text in their names, doc comments and attributes.
+The search ignores case.
+
+Matches on names come first, exact ones before partial ones, followed by
+matches in doc comments and attributes such as @description or @unit. Each
+match shows the path of the field, e.g. #Person.email, and where it is
+declared, with the import path for imported packages.
+
+Imports are resolved like 'platosl deps' does, when the project is a CUE
+module. When they cannot be resolved, e.g. offline, only the project's own
+schemas are searched.
+
+Formats:
+  table    aligned columns (default)
+  compact  tab-separated rows without a header, for grep, cut and awk
+  json     machine-readable
+
+Examples:
+  platosl search-schema email
+  platosl search-schema "billing address" --format json
+  platosl search-schema @unit --no-imports`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringVar(&searchFormat, "format", "table", "output format (table, compact, json)")
+	searchCmd.Flags().BoolVar(&searchNoImports, "no-imports", false, "search only the project's own schemas")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	switch searchFormat {
+	case "table", "compact", "json":
+	default:
+		err := fmt.Errorf("unknown format %q (expected table, compact or json)", searchFormat)
+		PrintError("%v", err)
+		return err
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var dirs []string
+	for _, schemaPath := range cfg.Schemas {
+		absPath, err := filepath.Abs(schemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve schema path: %w", err)
+		}
+		info, err := os.Stat(absPath)
+		if err != nil {
+			err = fmt.Errorf("schema path not found: %s", schemaPath)
+			PrintError("%v", err)
+			return err
+		}
+		if !info.IsDir() {
+			absPath = filepath.Dir(absPath)
+		}
+		dirs = append(dirs, absPath)
+	}
+
+	files, err := search.LocalFiles(dirs)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if !searchNoImports {
+		PrintVerbose("Resolving imports of %d schema path(s)", len(dirs))
+		imported, err := search.ImportedFiles(root, dirs)
+		if err != nil {
+			PrintWarning("Searching the project's schemas only: %v", err)
+		}
+		files = append(files, imported...)
+	}
+
+	matches := search.Search(files, args[0], root)
+
+	if searchFormat == "json" {
+		if matches == nil {
+			matches = []search.Match{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(matches) == 0 {
+		PrintInfo("No definitions or fields match %q", args[0])
+		return nil
+	}
+
+	t := newTable("PATH", "KIND", "MATCH", "SOURCE")
+	for _, m := range matches {
+		match := m.In
+		if m.In != "name" {
+			line, _, _ := strings.Cut(m.Text, "\n")
+			match += ": " + strconv.Quote(line)
+		}
+		t.AddRow(m.Path, m.Kind, match, m.Source.String())
+	}
+	if searchFormat == "compact" {
+		return t.RenderCompact(os.Stdout)
+	}
+	return t.Render(os.Stdout)
+}
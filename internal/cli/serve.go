@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/endpoint"
+	"github.com/platoorg/plato-sl-cli/internal/mock"
+	"github.com/platoorg/plato-sl-cli/internal/proxy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr        string
+	serveMock        bool
+	serveLatency     time.Duration
+	serveJitter      time.Duration
+	serveErrorRate   float64
+	serveErrorStatus int
+	serveSeed        int64
+	serveProxy       string
+	serveBlock       bool
+	serveRecord      string
+	serveCorpus      []string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the HTTP endpoints declared in the schemas",
+	Long: `Serve the endpoints declared with @http attributes, so frontends can be
+developed against schema-accurate fakes before the API exists.
+
+An @http attribute on a definition declares an endpoint returning it:
+
+  #User: {
+      @http(GET, "/users/:id")
+      id:    int
+      email: string
+  }
+
+  #CreateUser: {
+      @http(POST, "/users", response=#User, status=201)
+      email: string
+  }
+
+The annotated definition is the response body unless response is set; it
+is then the request body unless request is set, and request bodies are
+validated against it. Path
+parameters (:id or {id}) fill fields of the same name.
+
+With --mock, responses are random instances of the response definition.
+The same method and path always get the same data for a given --seed.
+--latency, --jitter and --error-rate simulate slow and failing backends.
+--corpus '#User=users.ndjson' shapes the mock data of a response
+definition after sample data; see platosl mock.
+
+With --proxy, requests are forwarded to a running service and the bodies
+of declared endpoints are validated on the way through: request bodies
+against the request definition and successful responses against the
+response definition. Violations are logged, appended as JSON lines to
+--record, and with --block rejected (400 for requests, 502 for responses).
+Point a copy of production traffic at the proxy to discover schema drift.
+
+Examples:
+  platosl serve --mock
+  platosl serve --mock --addr :8080 --latency 200ms --jitter 300ms
+  platosl serve --mock --error-rate 0.1 --error-status 503
+  platosl serve --mock --corpus '#User=samples/users.ndjson'
+  platosl serve --proxy http://localhost:8080 --record violations.jsonl
+  platosl serve --proxy https://api.internal --addr :9000 --block`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:4010", "address to listen on")
+	serveCmd.Flags().BoolVar(&serveMock, "mock", false, "answer with mock data generated from the response definitions")
+	serveCmd.Flags().DurationVar(&serveLatency, "latency", 0, "delay every response")
+	serveCmd.Flags().DurationVar(&serveJitter, "jitter", 0, "add a random delay of up to this duration")
+	serveCmd.Flags().Float64Var(&serveErrorRate, "error-rate", 0, "fraction of requests (0-1) answered with an error")
+	serveCmd.Flags().IntVar(&serveErrorStatus, "error-status", http.StatusInternalServerError, "status code of injected errors")
+	serveCmd.Flags().Int64Var(&serveSeed, "seed", 0, "random seed for mock data")
+	serveCmd.Flags().StringArrayVar(&serveCorpus, "corpus", nil, "learn the mock data of a definition from sample data, '#Definition=file' (repeatable)")
+	serveCmd.Flags().StringVar(&serveProxy, "proxy", "", "forward requests to this URL and validate the bodies")
+	serveCmd.Flags().BoolVar(&serveBlock, "block", false, "reject requests and responses that do not match their definitions (with --proxy)")
+	serveCmd.Flags().StringVar(&serveRecord, "record", "", "append violations to this file as JSON lines (with --proxy)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if serveMock == (serveProxy != "") {
+		PrintError("Choose one mode: --mock or --proxy <upstream>")
+		return fmt.Errorf("no serve mode selected")
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	schemas, err := loadAndValidateSchemas(cfg, "serve")
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := endpoint.Extract(schemas)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(endpoints) == 0 {
+		PrintError("No endpoints found; declare them with @http(<method>, \"<path>\") on definitions")
+		return fmt.Errorf("no endpoints")
+	}
+
+	if serveProxy != "" {
+		return runServeProxy(schemas, endpoints)
+	}
+
+	profiles, err := loadProfiles(schemas, serveCorpus)
+	if err != nil {
+		return err
+	}
+	for name, p := range profiles {
+		PrintVerbose("Learned %s from %d record(s)", name, p.Records())
+	}
+
+	server, err := mock.NewServer(schemas, endpoints, mock.Options{
+		Latency:     serveLatency,
+		Jitter:      serveJitter,
+		ErrorRate:   serveErrorRate,
+		ErrorStatus: serveErrorStatus,
+		Seed:        serveSeed,
+		Profiles:    profiles,
+		Logf:        PrintInfo,
+	})
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	for _, e := range endpoints {
+		PrintVerbose("%-30s → %s", e, e.Response)
+	}
+	PrintSuccess("Serving %d mock endpoint(s) on http://%s", len(endpoints), serveAddr)
+
+	if err := http.ListenAndServe(serveAddr, server); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	return nil
+}
+
+// runServeProxy serves the proxy mode
+func runServeProxy(schemas cue.Value, endpoints []endpoint.Endpoint) error {
+	opts := proxy.Options{
+		Upstream: serveProxy,
+		Block:    serveBlock,
+		Logf:     PrintInfo,
+	}
+	if serveRecord != "" {
+		f, err := os.OpenFile(serveRecord, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			PrintError("Failed to open record file: %v", err)
+			return err
+		}
+		defer f.Close()
+		opts.Record = f
+	}
+
+	server, err := proxy.NewProxy(schemas, endpoints, opts)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	for _, e := range endpoints {
+		PrintVerbose("%-30s → %s", e, e.Response)
+	}
+	mode := "Checking"
+	if serveBlock {
+		mode = "Enforcing"
+	}
+	PrintSuccess("%s %d endpoint(s) on http://%s → %s", mode, len(endpoints), serveAddr, serveProxy)
+
+	if err := http.ListenAndServe(serveAddr, server); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	return nil
+}
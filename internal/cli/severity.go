@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/lint"
+	"github.com/platoorg/plato-sl-cli/internal/severity"
+)
+
+// severityPolicy reads validation.severity of a config, checking that every
+// override selects a known code, rule name or category
+func severityPolicy(cfg *config.Config) (*severity.Policy, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	policy, err := severity.Parse(root, cfg.Validation.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{"*": true}
+	for _, code := range findingCodes() {
+		for _, name := range findingNames(code) {
+			known[strings.ToLower(name)] = true
+		}
+	}
+	for _, o := range policy.Overrides() {
+		if !known[strings.ToLower(o.Selector)] {
+			return nil, fmt.Errorf("invalid validation.severity entry %q: unknown code, rule or category %q\n\nSee 'platosl advise --help' for the lint rules and categories", o, o.Selector)
+		}
+	}
+	return policy, nil
+}
+
+// findingCodes lists the codes of the built-in checks: the @unit and
+// @currency conventions, then the lint rules
+func findingCodes() []string {
+	var codes []string
+	for code := range platoCue.MeasureRules {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, r := range lint.Rules {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+// findingNames returns the names a severity override can select a code
+// by: the code, its rule name and its category
+func findingNames(code string) []string {
+	if name, ok := platoCue.MeasureRules[code]; ok {
+		return []string{code, name, platoCue.MeasureCategory}
+	}
+	if r, ok := lint.Lookup(code); ok {
+		return []string{code, r.Name, lint.CategoryOf(code)}
+	}
+	return []string{code}
+}
+
+// lintRaised reports whether the policy reports findings of some lint rule,
+// which validate does not check by default
+func lintRaised(policy *severity.Policy) bool {
+	for _, r := range lint.Rules {
+		if policy.Raises(findingNames(r.Code)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/audit"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/share"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareOutput   string
+	shareGist     bool
+	sharePublic   bool
+	shareRegistry string
+	shareToken    string
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <definition>",
+	Short: "Bundle the minimal schema for a definition to share it",
+	Long: `Extract a definition and every declaration it references into a single,
+self-contained CUE file, to ask questions about a schema in chat or issues
+without sharing the whole project.
+
+The bundle contains schema.cue and a README with the source files and the
+cue commands to try it. It is written to --output unless it is uploaded:
+
+  --gist             create a GitHub gist (secret unless --public). The token
+                     comes from --token, GITHUB_TOKEN, or the usual token
+                     sources for api.github.com (platosl login api.github.com)
+  --registry <url>   create a scratch entry on a registry (POST <url>/scratch)
+
+Uploads publish the schema outside the project; check the bundle locally first.
+
+Examples:
+  platosl share '#Person'
+  platosl share '#Person' -o /tmp/person
+  platosl share '#Person' --gist
+  platosl share '#Person' --registry https://registry.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+	shareCmd.Flags().StringVarP(&shareOutput, "output", "o", "", "bundle directory (default .platosl/share/<definition>)")
+	shareCmd.Flags().BoolVar(&shareGist, "gist", false, "upload the bundle as a GitHub gist")
+	shareCmd.Flags().BoolVar(&sharePublic, "public", false, "make the gist public")
+	shareCmd.Flags().StringVar(&shareRegistry, "registry", "", "upload the bundle as a scratch entry to this registry")
+	shareCmd.Flags().StringVar(&shareToken, "token", "", "auth token for the upload")
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	definition := args[0]
+	if !strings.HasPrefix(definition, "#") {
+		definition = "#" + definition
+	}
+	if shareGist && shareRegistry != "" {
+		PrintError("--gist and --registry cannot be combined")
+		return fmt.Errorf("conflicting upload targets")
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return err
+	}
+
+	bundle, err := share.Build(cfg.Schemas, definition, Version)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintInfo("Bundled %s: %d declaration(s) from %d file(s)", definition, len(bundle.Declarations), len(bundle.Sources))
+	PrintVerbose("Declarations: %s", strings.Join(bundle.Declarations, ", "))
+	if len(bundle.External) > 0 {
+		PrintInfo("  Note: imports %s, which the bundle does not include", strings.Join(bundle.External, ", "))
+	}
+
+	switch {
+	case shareGist:
+		token := shareToken
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		client, err := newHTTPClient(cfg, token)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		url, err := share.Gist(client, bundle, sharePublic)
+		if err != nil {
+			PrintError("Upload failed: %v", err)
+			return err
+		}
+		PrintSuccess("Shared %s: %s", definition, url)
+		return recordAudit(cfg, "share", []audit.Target{outputTarget("gist", url, bundle.Files[share.SchemaFile])})
+
+	case shareRegistry != "":
+		registry := shareRegistry
+		if !strings.Contains(registry, "://") {
+			registry = "https://" + registry
+		}
+		host, err := registryHost(registry)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		client, err := newHTTPClient(cfg, shareToken, host)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		url, err := share.Registry(client, registry, bundle, cfg.Metadata)
+		if err != nil {
+			PrintError("Upload failed: %v", err)
+			return err
+		}
+		PrintSuccess("Shared %s: %s", definition, url)
+		return recordAudit(cfg, "share", []audit.Target{outputTarget("registry", url, bundle.Files[share.SchemaFile])})
+	}
+
+	dir := shareOutput
+	if dir == "" {
+		dir = filepath.Join(".platosl", "share", strings.TrimPrefix(strings.SplitN(definition, ".", 2)[0], "#"))
+	}
+	if err := bundle.WriteDir(dir); err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	for _, name := range bundle.FileNames() {
+		PrintInfo("  %s", filepath.Join(dir, name))
+	}
+	PrintSuccess("Wrote bundle to %s", dir)
+	return nil
+}
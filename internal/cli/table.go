@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// table renders rows as aligned columns for --format table
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func newTable(headers ...string) *table {
+	return &table{headers: headers}
+}
+
+// AddRow appends a row; missing cells are left empty
+func (t *table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render writes the table with a header row, bold when color is enabled
+func (t *table) Render(w io.Writer) error {
+	var buf strings.Builder
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Color the aligned header line, so escape codes do not skew the widths
+	out := buf.String()
+	if colorEnabled() {
+		header, rest, _ := strings.Cut(out, "\n")
+		out = ansiBold + header + ansiReset + "\n" + rest
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// RenderCompact writes one tab-separated line per row, without a header,
+// for piping into grep, cut or awk
+func (t *table) RenderCompact(w io.Writer) error {
+	for _, row := range t.rows {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// colorEnabled reports whether output may use ANSI colors: stdout must be a
+// terminal, and neither --no-color nor NO_COLOR may be set. color in the
+// user config can force colors on or off.
+func colorEnabled() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || userCfg.Color == "never" {
+		return false
+	}
+	return userCfg.Color == "always" || term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trimDryRun bool
+)
+
+var trimCmd = &cobra.Command{
+	Use:   "trim [directory]",
+	Short: "Remove fields implied by definitions and defaults",
+	Long: `Trim removes fields from CUE files that are already implied by the
+definitions and defaults they are unified with, keeping large content data
+files minimal.
+
+If a directory is specified, trims only the packages under that path.
+Otherwise, trims all schema paths from platosl.yaml.
+
+Use --dry-run to print a diff of the changes without writing them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTrim,
+}
+
+func init() {
+	rootCmd.AddCommand(trimCmd)
+	trimCmd.Flags().BoolVar(&trimDryRun, "dry-run", false, "show a diff of the changes without writing files")
+}
+
+func runTrim(cmd *cobra.Command, args []string) error {
+	// Determine what to trim
+	var paths []string
+
+	if len(args) > 0 {
+		if _, err := os.Stat(args[0]); os.IsNotExist(err) {
+			return fmt.Errorf("path does not exist: %s", args[0])
+		}
+		paths = []string{args[0]}
+	} else {
+		cfg, err := config.Load(GetConfigFile())
+		if err != nil {
+			return err
+		}
+		paths = cfg.Schemas
+
+		if len(paths) == 0 {
+			return fmt.Errorf("no schema paths configured in platosl.yaml")
+		}
+	}
+
+	// Expand directories to packages
+	var packages []string
+	for _, path := range paths {
+		subPaths, err := findCuePackages(path)
+		if err != nil {
+			return fmt.Errorf("failed to scan directory %s: %w", path, err)
+		}
+		packages = append(packages, subPaths...)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	loader := platoCue.NewLoader()
+	changed := 0
+
+	for _, pkg := range packages {
+		PrintVerbose("Trimming: %s", pkg)
+
+		results, err := loader.Trim(pkg)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+
+		for _, result := range results {
+			if !result.Changed() {
+				continue
+			}
+			changed++
+
+			name := result.File
+			if rel, err := filepath.Rel(cwd, result.File); err == nil {
+				name = rel
+			}
+
+			if trimDryRun {
+				fmt.Print(diff.Unified(name, name+" (trimmed)", result.Original, result.Trimmed))
+				continue
+			}
+
+			if err := os.WriteFile(result.File, result.Trimmed, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", name, err)
+			}
+			PrintVerbose("Trimmed: %s", name)
+		}
+	}
+
+	switch {
+	case changed == 0:
+		PrintSuccess("Nothing to trim")
+	case trimDryRun:
+		PrintInfo("\n%d file(s) would be trimmed", changed)
+	default:
+		PrintSuccess("Trimmed %d file(s)", changed)
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/mod/modfile"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/cuemod"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/platoorg/plato-sl-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeLatest bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <module> [version]",
+	Short: "Upgrade an imported module and regenerate",
+	Long: `Upgrade a module the project imports directly to a newer version, then
+check and regenerate everything that depends on it:
+
+  1. bump the import in platosl.yaml (a pinned version, or the major
+     version when it changes) and the requirement in cue.mod/module.cue
+  2. re-resolve the requirements and re-export layers, as 'mod tidy'
+  3. re-validate the schemas, as 'platosl validate'
+  4. regenerate the enabled targets, as 'platosl build'
+  5. summarize how the generated artifacts changed
+
+The module moves to the version 'platosl outdated' shows as wanted: the
+newest its update policy allows. Use --latest to move to the newest release
+instead, or give the version. When the upgraded schemas do not validate,
+platosl.yaml, module.cue and the layers are restored.
+
+Together with 'platosl outdated --format json' this makes one upgrade per
+module, e.g. one pull request each in CI.
+
+Examples:
+  platosl upgrade example.com/geo
+  platosl upgrade example.com/geo --latest
+  platosl upgrade example.com/geo v1.4.0`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runUpgrade,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().BoolVar(&upgradeLatest, "latest", false, "upgrade to the latest release, past the update policy")
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	base, _, _ := strings.Cut(args[0], "@")
+	p, updates, err := outdatedModules()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	f, err := cuemod.Load(p.Root)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	i := slices.IndexFunc(updates, func(u cuemod.Update) bool { return u.Base() == base })
+	if i < 0 && len(args) == 1 {
+		if !imports(p, f, base) {
+			err := fmt.Errorf("%s is not imported directly by %s", base, f.QualifiedModule())
+			PrintError("%v", err)
+			return err
+		}
+		PrintSuccess("%s is up to date", base)
+		return nil
+	}
+
+	var u cuemod.Update
+	if i >= 0 {
+		u = updates[i]
+	}
+	target := u.Wanted
+	switch {
+	case len(args) == 2:
+		if target, err = upgradeTarget(p, f, base, args[1]); err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		if u.Module == "" {
+			u.Module, u.Current = currentRequirement(f, base)
+		}
+	case upgradeLatest:
+		target = u.Latest
+	case target == "":
+		PrintInfo("%s %s: its update policy (%s) allows no newer version; the latest is %s", u.Module, u.Current, u.Policy, u.Latest)
+		PrintInfo("Use --latest or give the version to upgrade anyway")
+		return nil
+	}
+
+	change, breaking := cuemod.Classify(u.Current, target)
+	if breaking {
+		change += ", breaking"
+	}
+	PrintInfo("Upgrading %s %s → %s (%s)", base, u.Current, target, change)
+	PrintInfo("")
+
+	before := snapshotArtifacts()
+	restore, err := bumpImport(p, f, u, base, target)
+	if err != nil {
+		e := errors.Wrap(errors.ErrorTypeConfig, err, fmt.Sprintf("failed to upgrade %s", base))
+		e = e.WithSuggestion("Check the imports of platosl.yaml and that the registry (CUE_REGISTRY) is reachable")
+		restore()
+		PrintError("%s", e.Format())
+		return e
+	}
+
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		restore()
+		return err
+	}
+	PrintInfo("Validating schemas...")
+	if err := runValidate(cmd, []string{}); err != nil {
+		restore()
+		e := errors.Wrap(errors.ErrorTypeValidation, err, fmt.Sprintf("the schemas do not validate with %s %s", base, target))
+		e = e.WithSuggestion("platosl.yaml and cue.mod were restored; adapt the schemas to the new version and run the upgrade again")
+		PrintError("%s", e.Format())
+		return e
+	}
+	PrintInfo("")
+
+	PrintInfo("Generating code...")
+	if err := runGenAll(cfg); err != nil {
+		PrintWarning("%s was upgraded, but generation failed; run 'platosl build' once fixed", base)
+		return err
+	}
+
+	PrintInfo("")
+	PrintSuccess("Upgraded %s to %s", base, target)
+	printArtifactChanges(before, snapshotArtifacts())
+	return nil
+}
+
+// imports reports whether the project imports a module directly, through
+// platosl.yaml or a requirement of module.cue
+func imports(p cuemod.Project, f *modfile.File, base string) bool {
+	for _, imp := range p.Imports {
+		if impBase, _, _ := strings.Cut(imp, "@"); impBase == base {
+			return true
+		}
+	}
+	mpath, _ := currentRequirement(f, base)
+	return mpath != ""
+}
+
+// currentRequirement returns the requirement of a module in module.cue,
+// with its major version, and its version
+func currentRequirement(f *modfile.File, base string) (string, string) {
+	for mpath, dep := range f.Deps {
+		if depBase, _, _ := strings.Cut(mpath, "@"); depBase == base {
+			return mpath, dep.Version
+		}
+	}
+	return "", ""
+}
+
+// upgradeTarget checks a version given to upgrade: a release of the module
+// newer than the one required
+func upgradeTarget(p cuemod.Project, f *modfile.File, base, version string) (string, error) {
+	reg, err := modRegistry()
+	if err != nil {
+		return "", err
+	}
+	versions, err := reg.ModuleVersions(context.Background(), base)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the versions of %s: %w", base, err)
+	}
+	if !slices.Contains(versions, version) {
+		return "", fmt.Errorf("%s has no version %s in the registry", base, version)
+	}
+	if !imports(p, f, base) {
+		return "", fmt.Errorf("%s is not imported directly by %s", base, f.QualifiedModule())
+	}
+	if _, current := currentRequirement(f, base); current != "" && !cuemod.Newer(version, current) {
+		return "", fmt.Errorf("%s %s is not newer than %s", base, version, current)
+	}
+	return version, nil
+}
+
+// bumpImport moves a module to a new version in platosl.yaml and
+// module.cue, and tidies the requirements and re-export layers. The
+// returned function restores the files it changed.
+func bumpImport(p cuemod.Project, f *modfile.File, u cuemod.Update, base, target string) (func(), error) {
+	cfgPath := GetConfigFile()
+	cfgData, cfgErr := os.ReadFile(cfgPath)
+	modData, modErr := os.ReadFile(cuemod.Path(p.Root))
+	layers := make(map[string][]byte)
+	for _, dir := range p.Dirs {
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		path := filepath.Join(dir, cuemod.LayerFile)
+		data, _ := os.ReadFile(path)
+		layers[path] = data
+	}
+	restore := func() {
+		if cfgErr == nil {
+			os.WriteFile(cfgPath, cfgData, 0644)
+		}
+		if modErr == nil {
+			os.WriteFile(cuemod.Path(p.Root), modData, 0644)
+		}
+		for path, data := range layers {
+			if data == nil {
+				os.Remove(path)
+			} else {
+				os.WriteFile(path, data, 0644)
+			}
+		}
+	}
+	if cfgErr != nil {
+		return restore, fmt.Errorf("failed to read config file: %w", cfgErr)
+	}
+
+	upgraded, cfgChanges, err := config.SetImportVersion(cfgData, base, target)
+	if err != nil {
+		return restore, err
+	}
+	if len(cfgChanges) > 0 {
+		if err := os.WriteFile(cfgPath, upgraded, 0644); err != nil {
+			return restore, fmt.Errorf("failed to write config file: %w", err)
+		}
+	}
+
+	bumped := *f
+	bumped.Deps = make(map[string]*modfile.Dep, len(f.Deps))
+	newDep := &modfile.Dep{Version: target}
+	for mpath, dep := range f.Deps {
+		if depBase, _, _ := strings.Cut(mpath, "@"); depBase == base {
+			newDep.Default = dep.Default
+			continue
+		}
+		bumped.Deps[mpath] = dep
+	}
+	major, _, _ := strings.Cut(target, ".")
+	bumped.Deps[base+"@"+major] = newDep
+	if err := cuemod.Save(p.Root, &bumped); err != nil {
+		return restore, err
+	}
+
+	// The imports of platosl.yaml changed, so derive the project again
+	p, err = modProject()
+	if err != nil {
+		return restore, err
+	}
+	layerChanges, err := syncLayers(p, false)
+	if err != nil {
+		return restore, err
+	}
+	changes, err := tidyModule(p, &bumped, false)
+	if err != nil {
+		return restore, err
+	}
+
+	for _, change := range cfgChanges {
+		PrintInfo("  %s", change)
+	}
+	PrintInfo("  %s", cuemod.Change{Module: base + "@" + major, Old: u.Current, New: target})
+	for _, c := range layerChanges {
+		PrintInfo("  %s", layerSummary(p.Root, c))
+	}
+	for _, change := range changes {
+		PrintInfo("  %s", change)
+	}
+	PrintInfo("")
+	return restore, nil
+}
+
+// snapshotArtifacts reads the outputs of the enabled generators, files
+// and the files of output directories, keyed by path
+func snapshotArtifacts() map[string][]byte {
+	files := make(map[string][]byte)
+	cfg, err := config.Load(GetConfigFile())
+	if err != nil {
+		return files
+	}
+	for _, genCfg := range cfg.Generate {
+		if !genCfg.Enabled || genCfg.Output == "" {
+			continue
+		}
+		for _, output := range append([]string{genCfg.Output}, genCfg.ExtraOutputs()...) {
+			filepath.Walk(output, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return nil
+				}
+				if data, err := os.ReadFile(path); err == nil {
+					files[path] = data
+				}
+				return nil
+			})
+		}
+	}
+	return files
+}
+
+// printArtifactChanges summarizes how the generated artifacts changed:
+// files added (A), modified (M) and removed (D), with their line counts
+func printArtifactChanges(before, after map[string][]byte) {
+	var paths []string
+	for path := range after {
+		paths = append(paths, path)
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var lines []string
+	for _, path := range paths {
+		old, hadOld := before[path]
+		updated, hasNew := after[path]
+		added, removed := diff.Stat(old, updated)
+		switch {
+		case !hadOld:
+			lines = append(lines, fmt.Sprintf("  A %s (+%d)", path, added))
+		case !hasNew:
+			lines = append(lines, fmt.Sprintf("  D %s (-%d)", path, removed))
+		case added+removed > 0:
+			lines = append(lines, fmt.Sprintf("  M %s (+%d -%d)", path, added, removed))
+		}
+	}
+	if len(lines) == 0 {
+		PrintInfo("The generated artifacts are unchanged")
+		return
+	}
+	PrintInfo("\nGenerated artifacts changed:")
+	for _, line := range lines {
+		PrintInfo("%s", line)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeConfigTo     string
+	upgradeConfigDryRun bool
+)
+
+var upgradeConfigCmd = &cobra.Command{
+	Use:   "upgrade-config",
+	Short: "Upgrade platosl.yaml to the current config version",
+	Long: `Upgrade-config rewrites platosl.yaml in place to a newer config version
+and reports what changed. Comments and key order are kept.
+
+Versions:
+  v1  - one output per generator (output: path)
+  v2  - output lists per generator (outputs: [path, ...]); the first is the
+        primary output and the others get copies; the zod option of the
+        typescript generator becomes the zod generator
+
+The upgraded config must load after it is written, otherwise the original
+is restored, so a project is never left with a config platosl cannot read.
+
+Use --dry-run to print a diff of the changes without writing them.
+
+Examples:
+  platosl upgrade-config
+  platosl upgrade-config --dry-run
+  platosl upgrade-config --to v2 --config services/api/platosl.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runUpgradeConfig,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeConfigCmd)
+	upgradeConfigCmd.Flags().StringVar(&upgradeConfigTo, "to", config.CurrentVersion, "config version to upgrade to")
+	upgradeConfigCmd.Flags().BoolVar(&upgradeConfigDryRun, "dry-run", false, "show a diff of the changes without writing the config")
+}
+
+func runUpgradeConfig(cmd *cobra.Command, args []string) error {
+	path := GetConfigFile()
+	info, err := os.Stat(path)
+	if err != nil {
+		err = fmt.Errorf("config file not found: %s", path)
+		PrintError("%v", err)
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("failed to read config file: %w", err)
+		PrintError("%v", err)
+		return err
+	}
+
+	upgraded, changes, err := config.Upgrade(data, upgradeConfigTo)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	if len(changes) == 0 {
+		PrintSuccess("%s is already at version %s", path, upgradeConfigTo)
+		return nil
+	}
+
+	if upgradeConfigDryRun {
+		fmt.Print(diff.Unified(path, path+" (upgraded)", data, upgraded))
+		PrintInfo("\n%d change(s) would be made:", len(changes))
+		for _, change := range changes {
+			PrintInfo("  %s", change)
+		}
+		return nil
+	}
+
+	// Write the upgrade and check it loads, restoring the original if not
+	if err := os.WriteFile(path, upgraded, info.Mode().Perm()); err != nil {
+		err = fmt.Errorf("failed to write config file: %w", err)
+		PrintError("%v", err)
+		return err
+	}
+	if _, err := config.Load(path); err != nil {
+		if restoreErr := os.WriteFile(path, data, info.Mode().Perm()); restoreErr != nil {
+			err = fmt.Errorf("%w (restoring the original also failed: %v)", err, restoreErr)
+		}
+		err = fmt.Errorf("upgraded config does not load, left unchanged: %w", err)
+		PrintError("%v", err)
+		return err
+	}
+
+	PrintSuccess("Upgraded %s to version %s", path, upgradeConfigTo)
+	for _, change := range changes {
+		PrintInfo("  %s", change)
+	}
+	return nil
+}
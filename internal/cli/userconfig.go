@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/credentials"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the user config and directories",
+	Long: `Show the location of the user config file and the cache directory, and
+the settings read from the user config.
+
+The user config (config.yaml) holds defaults for every project:
+
+  registry: registry.example.com   # login/logout without an argument
+  color: auto                      # auto, always or never
+  author:                          # recorded in audit logs
+    name: Ada Lovelace
+    email: ada@example.com
+  maxWorkers: 4                    # default of --max-workers
+  memoryLimit: 2GiB                # default of --memory-limit
+  network:                         # merged under network in platosl.yaml
+    proxy: http://proxy.internal:3128
+    caFile: /etc/ssl/corp.pem
+    tokens:
+      registry.example.com: $REGISTRY_TOKEN
+  draft:                           # model of platosl draft
+    endpoint: https://api.openai.com/v1/chat/completions
+    apiKey: $OPENAI_API_KEY
+  cache:                           # imported modules, see platosl cache
+    backend: sqlite                # fs (default) or sqlite
+
+It is read from $XDG_CONFIG_HOME/platosl when XDG_CONFIG_HOME is set, and
+otherwise from the OS config directory: ~/.config/platosl on Linux,
+~/Library/Application Support/platosl on macOS and %AppData%\platosl on
+Windows. PLATOSL_USER_CONFIG points to another file. The cache lives in
+$XDG_CACHE_HOME/platosl or the OS cache directory.
+
+Flags and environment variables take precedence over the user config, and
+platosl.yaml takes precedence over its network settings.`,
+	Args: cobra.NoArgs,
+	RunE: runConfig,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfig(cmd *cobra.Command, args []string) error {
+	path, err := config.UserConfigPath()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+	cache, err := config.CacheDir()
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	status := ""
+	if !config.Exists(path) {
+		status = " (not found)"
+	}
+	fmt.Printf("User config: %s%s\n", path, status)
+	fmt.Printf("Cache:       %s\n", cache)
+	if imports, err := config.ImportCacheDir(userCfg.Cache); err == nil && imports != cache {
+		fmt.Printf("Imports:     %s\n", imports)
+	}
+
+	// Tokens are shown masked unless they reference an environment variable
+	shown := *userCfg
+	if len(shown.Network.Tokens) > 0 {
+		shown.Network.Tokens = make(map[string]string, len(userCfg.Network.Tokens))
+		for host, token := range userCfg.Network.Tokens {
+			if !strings.HasPrefix(token, "$") {
+				token = credentials.Mask(token)
+			}
+			shown.Network.Tokens[host] = token
+		}
+	}
+
+	data, err := yaml.Marshal(shown)
+	if err != nil {
+		return fmt.Errorf("failed to format user config: %w", err)
+	}
+	if string(data) != "{}\n" {
+		fmt.Printf("\n%s", data)
+	}
+	return nil
+}
@@ -1,29 +1,66 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"cuelang.org/go/cue"
+	cueErrors "cuelang.org/go/cue/errors"
 	"github.com/spf13/cobra"
 	"github.com/platoorg/plato-sl-cli/internal/config"
 	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	platoErrors "github.com/platoorg/plato-sl-cli/internal/errors"
+	"github.com/platoorg/plato-sl-cli/internal/lint"
+	"github.com/platoorg/plato-sl-cli/internal/severity"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
 )
 
 var (
-	validateStrict bool
+	validateStrict     bool
+	validateMaxErrors  int
+	validateErrorsJSON string
+	validateFormat     string
 )
 
 var validateCmd = &cobra.Command{
-	Use:   "validate [file or directory]",
-	Short: "Validate CUE schemas",
+	Use:     "validate [file or directory]",
+	Aliases: []string{"v"},
+	Short:   "Validate CUE schemas",
 	Long: `Validate CUE schemas for correctness and completeness.
 
 If a file or directory is specified, validates only that path.
-Otherwise, validates all schema paths from platosl.yaml.`,
+Otherwise, validates all schema paths from platosl.yaml.
+
+Errors are grouped by file. When there are more than --max-errors, only the
+first ones are printed, followed by a summary table of errors per file and
+per error type. Use --errors-json to write the full list to a file.
+
+With --format quickfix, every error is printed to stdout as one
+"file:line:col: message" line and nothing else is printed, for editors'
+quickfix lists (Vim :cexpr, Emacs compilation-mode). --max-errors does not
+apply to quickfix output.
+
+validation.severity in platosl.yaml reclassifies the findings of the
+built-in convention checks by code, rule name or category, optionally only
+in some files:
+
+  validation:
+    severity:
+      - error documentation                # lint rules are off unless raised
+      - warn PSL1003                       # currency-naming
+      - ignore PSL2002 in schemas/legacy/**
+
+Later entries win. Warnings are printed but only fail validation with
+validation.failOnWarning. CUE errors cannot be reclassified.
+
+Examples:
+  platosl validate
+  platosl validate schemas/ --max-errors 50
+  platosl validate --errors-json errors.json
+  vim -q <(platosl validate --format quickfix)`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runValidate,
 }
@@ -31,11 +68,21 @@ Otherwise, validates all schema paths from platosl.yaml.`,
 func init() {
 	rootCmd.AddCommand(validateCmd)
 	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "strict validation (requires all fields to be concrete)")
+	validateCmd.Flags().IntVar(&validateMaxErrors, "max-errors", 20, "maximum number of errors to print (0 prints all)")
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format (text, quickfix)")
+	validateCmd.Flags().StringVar(&validateErrorsJSON, "errors-json", "", "write the full list of errors to a JSON file")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
+	if validateFormat != "text" && validateFormat != "quickfix" {
+		PrintError("Unknown format %q (expected text or quickfix)", validateFormat)
+		return fmt.Errorf("unknown format: %s", validateFormat)
+	}
+
 	// Determine what to validate
 	var paths []string
+	var cfg *config.Config
+	var policy *severity.Policy
 	useConfig := false
 
 	if len(args) > 0 {
@@ -58,8 +105,10 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	} else {
 		// Load config and validate configured paths
 		useConfig = true
-		cfg, err := config.Load(GetConfigFile())
+		var err error
+		cfg, err = config.Load(GetConfigFile())
 		if err != nil {
+			PrintError("%v", err)
 			return err
 		}
 
@@ -70,6 +119,12 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		}
 		validateStrict = strict
 
+		policy, err = severityPolicy(cfg)
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+
 		// Collect all schema paths (keep relative for CUE)
 		for _, schemaPath := range cfg.Schemas {
 			// Validate path exists
@@ -87,8 +142,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		PrintVerbose("Validating %d schema path(s) from config", len(paths))
 	}
 
-	// Create loader and validator
-	loader := platoCue.NewLoader()
+	// Create validator
 	validator := platoCue.NewValidator(validateStrict)
 
 	// Track validation results
@@ -128,15 +182,37 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Validate each path
-	for _, path := range expandedPaths {
+	// Validate each path in parallel. Each worker has its own loader, since
+	// CUE values must not be shared between goroutines.
+	type pathResult struct {
+		errors    []*platoErrors.Error
+		warnings  []*platoErrors.Error
+		validated bool
+	}
+	results := make([]pathResult, len(expandedPaths))
+	// Definitions published by dependencies are in scope for each worker
+	newLoader := func() (*platoCue.Loader, error) {
+		loader := platoCue.NewLoader()
+		if !useConfig {
+			return loader, nil
+		}
+		scoped, _, _, err := dependencyLoader(loader, cfg)
+		return scoped, err
+	}
+	if _, err := newLoader(); err != nil {
+		PrintError("Failed to load dependencies: %v", err)
+		return err
+	}
+
+	err := workers.Run(len(expandedPaths), newLoader, func(loader *platoCue.Loader, i int) error {
+		path := expandedPaths[i]
 		info, err := os.Stat(path)
 		if err != nil {
-			allErrors = append(allErrors, platoErrors.Newf(
+			results[i].errors = append(results[i].errors, platoErrors.Newf(
 				platoErrors.ErrorTypeFileSystem,
 				"cannot access %s: %v", path, err,
 			))
-			continue
+			return nil
 		}
 
 		var val cue.Value
@@ -149,37 +225,118 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		}
 
 		if err != nil {
-			allErrors = append(allErrors, platoErrors.Wrapf(
+			results[i].errors = append(results[i].errors, platoErrors.Wrapf(
 				platoErrors.ErrorTypeValidation,
 				err,
 				"failed to load %s", path,
 			))
-			continue
+			return nil
 		}
 
 		// Validate
 		result := validator.Validate(val)
-		validatedFiles++
+		results[i].validated = true
+
+		// Findings of the convention checks are reported at the level
+		// validation.severity sets for their code
+		report := func(e *platoErrors.Error, def severity.Level) {
+			switch policy.Level(findingNames(e.Code), e.File, def) {
+			case severity.Error:
+				results[i].errors = append(results[i].errors, e)
+			case severity.Warn:
+				results[i].warnings = append(results[i].warnings, e)
+			}
+		}
 
 		if !result.Valid {
 			for _, verr := range result.Errors {
-				allErrors = append(allErrors, platoErrors.New(
+				// Errors without a position still belong to the loaded path
+				file := verr.File
+				if file == "" {
+					file = path
+				}
+				e := platoErrors.New(
 					platoErrors.ErrorTypeValidation,
 					verr.Message,
-				).WithLocation(verr.File, verr.Line, verr.Column).WithSuggestion(verr.Suggestion))
+				).WithLocation(file, verr.Line, verr.Column).WithSuggestion(verr.Suggestion)
+				if verr.Code == "" {
+					results[i].errors = append(results[i].errors, e)
+					continue
+				}
+				report(e.WithCode(verr.Code), severity.Error)
 			}
 		}
+
+		// Lint rules are only checked where validation.severity raises them
+		if result.Valid && lintRaised(policy) {
+			lintResult, err := lint.Run(val, lint.DefaultOptions)
+			if err != nil {
+				results[i].errors = append(results[i].errors, platoErrors.Wrapf(
+					platoErrors.ErrorTypeValidation,
+					err,
+					"failed to lint %s", path,
+				))
+				return nil
+			}
+			for _, f := range lintResult.Findings {
+				file := f.File
+				if file == "" {
+					file = path
+				}
+				report(platoErrors.New(
+					platoErrors.ErrorTypeValidation,
+					f.Message,
+				).WithLocation(file, f.Line, 0).WithSuggestion(f.Suggestion).WithCode(f.Code), severity.Ignore)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		PrintError("Failed to load dependencies: %v", err)
+		return err
+	}
+
+	var allWarnings []*platoErrors.Error
+	for _, result := range results {
+		allErrors = append(allErrors, result.errors...)
+		allWarnings = append(allWarnings, result.warnings...)
+		if result.validated {
+			validatedFiles++
+		}
 	}
 
 	// Report results
-	if len(allErrors) > 0 {
-		PrintError("Validation failed\n")
+	if validateFormat == "quickfix" {
+		if validateErrorsJSON != "" {
+			if err := platoErrors.WriteJSON(validateErrorsJSON, allErrors); err != nil {
+				PrintError("%v", err)
+			}
+		}
 		for _, err := range allErrors {
-			fmt.Fprintln(os.Stderr, err.Format())
-			fmt.Fprintln(os.Stderr)
+			for _, line := range quickfixLines(err) {
+				fmt.Println(line)
+			}
+		}
+		platoErrors.SortByFile(allWarnings)
+		for _, w := range allWarnings {
+			fmt.Println(quickfixLine(w.File, w.Line, w.Column, "warning: "+w.Message+" ["+w.Code+"]"))
+		}
+		if len(allErrors) > 0 {
+			return fmt.Errorf("found %d error(s)", len(allErrors))
 		}
+		return failOnWarnings(cfg, allWarnings)
+	}
+
+	if len(allErrors) > 0 {
+		reportValidationWarnings(allWarnings)
+		reportValidationErrors(allErrors)
 		return fmt.Errorf("found %d error(s)", len(allErrors))
 	}
+	reportValidationWarnings(allWarnings)
+	if err := failOnWarnings(cfg, allWarnings); err != nil {
+		PrintError("%v", err)
+		return err
+	}
 
 	// Success
 	if useConfig {
@@ -191,6 +348,123 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// reportValidationWarnings prints the findings validation.severity reports
+// as warnings
+func reportValidationWarnings(warnings []*platoErrors.Error) {
+	platoErrors.SortByFile(warnings)
+	for _, w := range warnings {
+		PrintWarning("%s:%d: %s [%s]", w.File, w.Line, w.Message, w.Code)
+		if w.Suggestion != "" {
+			fmt.Fprintf(os.Stderr, "  Suggestion: %s\n", w.Suggestion)
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// failOnWarnings fails validation with warnings when validation.failOnWarning
+// is set
+func failOnWarnings(cfg *config.Config, warnings []*platoErrors.Error) error {
+	if cfg == nil || !cfg.Validation.FailOnWarning || len(warnings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("found %d warning(s) (validation.failOnWarning is set)", len(warnings))
+}
+
+// reportValidationErrors prints errors grouped by file, up to --max-errors,
+// with a summary table when some are left out
+func reportValidationErrors(allErrors []*platoErrors.Error) {
+	PrintError("Validation failed\n")
+
+	if validateErrorsJSON != "" {
+		if err := platoErrors.WriteJSON(validateErrorsJSON, allErrors); err != nil {
+			PrintError("%v", err)
+		}
+	}
+
+	platoErrors.SortByFile(allErrors)
+	shown := allErrors
+	if validateMaxErrors > 0 && len(shown) > validateMaxErrors {
+		shown = shown[:validateMaxErrors]
+	}
+	for _, err := range shown {
+		fmt.Fprintln(os.Stderr, err.Format())
+		fmt.Fprintln(os.Stderr)
+	}
+
+	if len(shown) < len(allErrors) {
+		summary := platoErrors.Summarize(allErrors)
+		fmt.Fprintf(os.Stderr, "... and %d more error(s)\n\n", len(allErrors)-len(shown))
+		printErrorGroups("File", summary.Files)
+		fmt.Fprintln(os.Stderr)
+		printErrorGroups("Error type", summary.Categories)
+		fmt.Fprintln(os.Stderr)
+		if validateErrorsJSON == "" {
+			fmt.Fprintln(os.Stderr, "Use --max-errors 0 to print all errors, or --errors-json <path> to save them")
+		}
+	}
+
+	if validateErrorsJSON != "" {
+		PrintInfo("Wrote %d error(s) to %s", len(allErrors), validateErrorsJSON)
+	}
+}
+
+// quickfixLines formats an error as "file:line:col: message" lines. Load
+// errors are expanded into one line per underlying CUE error, which carry
+// their own positions. Errors without a position point at the start of
+// their file.
+func quickfixLines(e *platoErrors.Error) []string {
+	var cueErr cueErrors.Error
+	if e.Cause != nil && errors.As(e.Cause, &cueErr) {
+		var lines []string
+		for _, ce := range cueErrors.Errors(cueErr) {
+			pos := platoCue.ErrorPosition(ce)
+			file := pos.Filename()
+			if file == "" {
+				file = e.File
+			}
+			lines = append(lines, quickfixLine(file, pos.Line(), pos.Column(), ce.Error()))
+		}
+		return lines
+	}
+
+	msg := e.Message
+	if e.Code != "" {
+		msg += " [" + e.Code + "]"
+	}
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return []string{quickfixLine(e.File, e.Line, e.Column, msg)}
+}
+
+func quickfixLine(file string, line, column int, msg string) string {
+	if line <= 0 {
+		line, column = 1, 1
+	}
+	if column <= 0 {
+		column = 1
+	}
+	msg = strings.Join(strings.Fields(msg), " ")
+	return fmt.Sprintf("%s:%d:%d: %s", file, line, column, msg)
+}
+
+// printErrorGroups prints a two-column table of error counts
+func printErrorGroups(title string, groups []platoErrors.Group) {
+	width := len(title)
+	for _, g := range groups {
+		if len(g.Name) > width {
+			width = len(g.Name)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, title, "Errors")
+	fmt.Fprintf(os.Stderr, "  %s  %s\n", strings.Repeat("-", width), strings.Repeat("-", 6))
+	for _, g := range groups {
+		fmt.Fprintf(os.Stderr, "  %-*s  %6d\n", width, g.Name, g.Count)
+	}
+}
+
 // findCuePackages finds all directories containing CUE files recursively
 func findCuePackages(rootPath string) ([]string, error) {
 	var packages []string
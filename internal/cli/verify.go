@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyManifest string
+	verifyVersion  string
+	verifyDir      string
+	verifyMap      []string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify artifacts against published metadata",
+}
+
+var verifyArtifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "Check vendored generated files against a build manifest",
+	Long: `Check vendored copies of generated files against the build manifest
+published with a schema release (see 'platosl manifest'), proving that they
+match the schema version they claim to target.
+
+The manifest can be a local file or an http(s) URL; remote fetches use the
+network settings and tokens of platosl.yaml (if present) and 'platosl login'.
+
+Each artifact is looked up by file name in --dir, unless mapped explicitly
+with --map name=path. Files of multi-file generators (java, php, bigquery,
+jsonschema --split) are looked up by their path inside the output
+directory, under --dir or the mapped directory.
+
+Examples:
+  platosl verify artifacts --manifest https://schemas.example.com/orders/v1.4.0/manifest.json \
+    --version v1.4.0 --dir src/generated
+  platosl verify artifacts --manifest manifest.json --map go=internal/types/orders.go`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyArtifacts,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.AddCommand(verifyArtifactsCmd)
+	verifyArtifactsCmd.Flags().StringVar(&verifyManifest, "manifest", "", "manifest file or URL (required)")
+	verifyArtifactsCmd.Flags().StringVar(&verifyVersion, "version", "", "expected release version")
+	verifyArtifactsCmd.Flags().StringVar(&verifyDir, "dir", ".", "directory containing the vendored files")
+	verifyArtifactsCmd.Flags().StringArrayVar(&verifyMap, "map", nil, "local path for an artifact (name=path, repeatable)")
+	verifyArtifactsCmd.MarkFlagRequired("manifest")
+}
+
+func runVerifyArtifacts(cmd *cobra.Command, args []string) error {
+	mapped := make(map[string]string)
+	for _, entry := range verifyMap {
+		name, local, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || local == "" {
+			err := fmt.Errorf("invalid --map %q (expected name=path)", entry)
+			PrintError("%v", err)
+			return err
+		}
+		mapped[name] = local
+	}
+
+	// Consumer repos usually have no platosl.yaml; fall back to defaults
+	cfg := &config.Config{}
+	if config.Exists(GetConfigFile()) {
+		loaded, err := config.Load(GetConfigFile())
+		if err != nil {
+			PrintError("%v", err)
+			return err
+		}
+		cfg = loaded
+	}
+
+	client, err := newHTTPClient(cfg, "")
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	m, err := manifest.Load(client, verifyManifest)
+	if err != nil {
+		PrintError("%v", err)
+		return err
+	}
+
+	if verifyVersion != "" && m.Version != verifyVersion {
+		err := fmt.Errorf("manifest is for version %q, expected %q", m.Version, verifyVersion)
+		PrintError("%v", err)
+		return err
+	}
+
+	results := m.Verify(func(a manifest.Artifact) string {
+		if local, ok := mapped[a.Name]; ok {
+			if a.Dir != "" {
+				return filepath.Join(local, a.File())
+			}
+			return local
+		}
+		return filepath.Join(verifyDir, a.File())
+	})
+
+	PrintInfo("Manifest: %s %s (schema %s)", m.Project, m.Version, m.SchemaHash)
+
+	failed := 0
+	for _, r := range results {
+		switch r.Status {
+		case manifest.StatusOK:
+			PrintSuccess("%s: %s matches", r.Artifact.Name, r.Local)
+		case manifest.StatusMissing:
+			failed++
+			PrintError("%s: %s not found", r.Artifact.Name, r.Local)
+		case manifest.StatusMismatch:
+			failed++
+			PrintError("%s: %s does not match (expected %s, got %s)", r.Artifact.Name, r.Local, r.Artifact.Hash, r.Hash)
+		}
+	}
+
+	if failed > 0 {
+		PrintError("%d of %d artifact(s) failed verification", failed, len(results))
+		return fmt.Errorf("%d artifact(s) failed verification", failed)
+	}
+
+	PrintSuccess("All %d artifact(s) match %s %s", len(results), m.Project, m.Version)
+	return nil
+}
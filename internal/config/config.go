@@ -1,28 +1,180 @@
 package config
 
+import "gopkg.in/yaml.v3"
+
 // Config represents the platosl.yaml configuration
 type Config struct {
 	Version    string              `yaml:"version"`
 	Name       string              `yaml:"name"`
-	Imports    []string            `yaml:"imports,omitempty"`
+	Imports    []Import            `yaml:"imports,omitempty"`
 	Schemas    []string            `yaml:"schemas"`
+	Publish    []string            `yaml:"publish,omitempty"`
+	DependsOn  []string            `yaml:"dependsOn,omitempty"`
 	Validation ValidationConfig    `yaml:"validation"`
 	Generate   map[string]GenConfig `yaml:"generate"`
+	Network    NetworkConfig       `yaml:"network,omitempty"`
+	Audit      AuditConfig         `yaml:"audit,omitempty"`
+	I18n       I18nConfig          `yaml:"i18n,omitempty"`
+	Metadata   MetadataConfig      `yaml:"metadata,omitempty"`
+	Draft      DraftConfig         `yaml:"draft,omitempty"`
+}
+
+// Import is a module the schemas import: its path, with a full version
+// (pinned), a major version or none, or a local path. With As, the
+// re-export layer makes its package available to every schema file as a
+// hidden field, e.g. _addr for as: addr. Update is the newest kind of
+// version 'platosl upgrade' moves the import to: patch, minor (the
+// default), major, or none to keep its version. An import with neither As
+// nor Update is written as a plain string.
+type Import struct {
+	Pkg    string `yaml:"pkg"`
+	As     string `yaml:"as,omitempty"`
+	Update string `yaml:"update,omitempty"`
+}
+
+// UpdatePolicies are the values of update, from the most to the least
+// conservative
+var UpdatePolicies = []string{"none", "patch", "minor", "major"}
+
+// UpdatePolicy returns the update policy of an import, minor by default
+func (i Import) UpdatePolicy() string {
+	if i.Update == "" {
+		return "minor"
+	}
+	return i.Update
+}
+
+// UnmarshalYAML reads an import given as a string or as pkg, as and update
+func (i *Import) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*i = Import{Pkg: node.Value}
+		return nil
+	}
+	type plain Import
+	return node.Decode((*plain)(i))
+}
+
+// MarshalYAML writes an import without an alias or policy as a string
+func (i Import) MarshalYAML() (interface{}, error) {
+	if i.As == "" && i.Update == "" {
+		return i.Pkg, nil
+	}
+	type plain Import
+	return plain(i), nil
+}
+
+// ImportPaths returns the paths of the imports
+func (c *Config) ImportPaths() []string {
+	paths := make([]string, 0, len(c.Imports))
+	for _, imp := range c.Imports {
+		paths = append(paths, imp.Pkg)
+	}
+	return paths
 }
 
 // ValidationConfig holds validation options
 type ValidationConfig struct {
 	Strict        bool `yaml:"strict"`
 	FailOnWarning bool `yaml:"failOnWarning"`
+
+	// Severity reclassifies findings by code, e.g.
+	// "ignore PSL2003 in schemas/legacy/**"
+	Severity []string `yaml:"severity,omitempty"`
+}
+
+// NetworkConfig holds options shared by all remote operations
+type NetworkConfig struct {
+	// Timeout is the per-request timeout (e.g. "30s")
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// Retries is the number of retries for failed or throttled requests
+	Retries *int `yaml:"retries,omitempty"`
+
+	// RateLimit caps outgoing requests per second (0 means unlimited)
+	RateLimit float64 `yaml:"rateLimit,omitempty"`
+
+	// Proxy overrides the HTTP(S)_PROXY environment variables
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// CAFile is a PEM bundle trusted in addition to the system roots
+	CAFile string `yaml:"caFile,omitempty"`
+
+	// Tokens maps hosts to auth tokens; values may reference env vars ($VAR)
+	Tokens map[string]string `yaml:"tokens,omitempty"`
+}
+
+// AuditConfig controls the audit log of generation and publish operations
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Path is the local JSON Lines log file (default .platosl/audit.log)
+	Path string `yaml:"path,omitempty"`
+
+	// URL receives each record as a JSON POST, in addition to the local log
+	URL string `yaml:"url,omitempty"`
+}
+
+// I18nConfig holds localization settings for @i18n fields
+type I18nConfig struct {
+	// SourceLocale is the locale translatable content is authored in
+	SourceLocale string `yaml:"sourceLocale,omitempty"`
+
+	// Locales lists the locales localized data must cover
+	Locales []string `yaml:"locales,omitempty"`
+}
+
+// MetadataConfig describes the schema package. Generators embed it in the
+// headers of generated files and in packaging outputs, and manifests and
+// registry uploads carry it.
+type MetadataConfig struct {
+	// License is an SPDX license expression, e.g. MIT or Apache-2.0
+	License string   `yaml:"license,omitempty" json:"license,omitempty"`
+	Authors []string `yaml:"authors,omitempty" json:"authors,omitempty"`
+
+	// Homepage is the URL of the project or its documentation
+	Homepage string `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+
+	// Version is the version of packaging outputs such as package.json
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+// IsZero reports whether no metadata is set
+func (m MetadataConfig) IsZero() bool {
+	return m.License == "" && len(m.Authors) == 0 && m.Homepage == "" && m.Version == ""
+}
+
+// DraftConfig configures the model platosl draft asks for schema drafts:
+// an OpenAI-compatible chat completions endpoint, or a command reading the
+// prompt on stdin and writing the draft to stdout
+type DraftConfig struct {
+	Endpoint string   `yaml:"endpoint,omitempty"`
+	Model    string   `yaml:"model,omitempty"`
+	Command  []string `yaml:"command,omitempty"`
+
+	// APIKey is sent as a bearer token; it is expanded with environment
+	// variables so the key can stay out of the file ($OPENAI_API_KEY)
+	APIKey string `yaml:"apiKey,omitempty"`
 }
 
 // GenConfig holds generator-specific configuration
 type GenConfig struct {
-	Enabled bool                   `yaml:"enabled"`
-	Output  string                 `yaml:"output"`
+	Enabled bool   `yaml:"enabled"`
+	Output  string `yaml:"output,omitempty"`
+
+	// Outputs lists every path the output is written to (version v2). The
+	// first is the primary output and the others get copies.
+	Outputs []string               `yaml:"outputs,omitempty"`
 	Options map[string]interface{} `yaml:"options,omitempty"`
 }
 
+// ExtraOutputs returns the outputs besides the primary one
+func (g GenConfig) ExtraOutputs() []string {
+	if len(g.Outputs) < 2 {
+		return nil
+	}
+	return g.Outputs[1:]
+}
+
 // TypeScriptOptions holds TypeScript-specific options
 type TypeScriptOptions struct {
 	Zod bool `yaml:"zod"`
@@ -12,9 +12,9 @@ func DefaultWithGenerators(name string, generators []string) *Config {
 	}
 
 	cfg := &Config{
-		Version: "v1",
+		Version: CurrentVersion,
 		Name:    name,
-		Imports: []string{},
+		Imports: []Import{},
 		Schemas: []string{"schemas/"},
 		Validation: ValidationConfig{
 			Strict:        true,
@@ -57,6 +57,11 @@ func DefaultWithGenerators(name string, generators []string) *Config {
 					"module": "MyApp.Types",
 				},
 			}
+		case "graphql":
+			cfg.Generate["graphql"] = GenConfig{
+				Enabled: true,
+				Output:  "generated/schema.graphql",
+			}
 		}
 	}
 
@@ -71,8 +76,14 @@ func UpdateGenerators(cfg *Config, generators []string) *Config {
 		selectedMap[gen] = true
 	}
 
+	// Generators outside the defaults below are enabled or disabled too
+	for gen, existingCfg := range cfg.Generate {
+		existingCfg.Enabled = selectedMap[gen]
+		cfg.Generate[gen] = existingCfg
+	}
+
 	// Update existing generators and disable those not selected
-	allGenerators := []string{"typescript", "zod", "jsonschema", "go", "elixir"}
+	allGenerators := []string{"typescript", "zod", "jsonschema", "go", "elixir", "graphql"}
 	for _, gen := range allGenerators {
 		if existingCfg, exists := cfg.Generate[gen]; exists {
 			// Generator exists in config - update enabled status
@@ -112,6 +123,11 @@ func UpdateGenerators(cfg *Config, generators []string) *Config {
 						"module": "MyApp.Types",
 					},
 				}
+			case "graphql":
+				cfg.Generate["graphql"] = GenConfig{
+					Enabled: true,
+					Output:  "generated/schema.graphql",
+				}
 			}
 		}
 	}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Ecosystem is a language toolchain found in a project directory, with the
+// generators and output paths idiomatic for it
+type Ecosystem struct {
+	// Name describes the ecosystem and its marker file, e.g. "Go (go.mod)"
+	Name string
+
+	// Generate holds the generators to enable, by name
+	Generate map[string]GenConfig
+}
+
+// Generators returns the names of the generators of an ecosystem
+func (e Ecosystem) Generators() []string {
+	var names []string
+	for _, name := range []string{"typescript", "zod", "go", "jsonschema", "elixir", "graphql"} {
+		if _, ok := e.Generate[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+var (
+	// mixApp matches the application name in mix.exs, e.g. app: :my_app
+	mixApp = regexp.MustCompile(`app:\s*:([a-z_][a-z0-9_]*)`)
+
+	// pyprojectName matches the project name in pyproject.toml
+	pyprojectName = regexp.MustCompile(`(?m)^name\s*=\s*["']([^"']+)["']`)
+)
+
+// DetectEcosystems inspects the marker files of a directory (package.json,
+// go.mod, mix.exs, pyproject.toml) and returns the ecosystems found
+func DetectEcosystems(dir string) []Ecosystem {
+	var found []Ecosystem
+
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		out := "generated"
+		if isDir(filepath.Join(dir, "src")) {
+			out = "src/generated"
+		}
+		gens := map[string]GenConfig{
+			"typescript": {Enabled: true, Output: out + "/types.ts"},
+		}
+		// Only projects that use zod get the schemas
+		if strings.Contains(string(data), `"zod"`) {
+			gens["zod"] = GenConfig{Enabled: true, Output: out + "/schemas.ts"}
+		}
+		found = append(found, Ecosystem{Name: "Node.js (package.json)", Generate: gens})
+	}
+
+	if isFile(filepath.Join(dir, "go.mod")) {
+		found = append(found, Ecosystem{Name: "Go (go.mod)", Generate: map[string]GenConfig{
+			"go": {Enabled: true, Output: "internal/types/types.go", Options: map[string]interface{}{"package": "types"}},
+		}})
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "mix.exs")); err == nil {
+		app := snakeCase(filepath.Base(dir))
+		if m := mixApp.FindSubmatch(data); m != nil {
+			app = string(m[1])
+		}
+		found = append(found, Ecosystem{Name: "Elixir (mix.exs)", Generate: map[string]GenConfig{
+			"elixir": {
+				Enabled: true,
+				Output:  "lib/" + app + "/types.ex",
+				Options: map[string]interface{}{"module": camelCase(app) + ".Types"},
+			},
+		}})
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml")); err == nil {
+		// Python has no type generator; JSON Schema is read by pydantic,
+		// jsonschema and datamodel-codegen
+		out := "generated/schema.json"
+		if m := pyprojectName.FindSubmatch(data); m != nil {
+			pkg := snakeCase(string(m[1]))
+			switch {
+			case isDir(filepath.Join(dir, "src", pkg)):
+				out = "src/" + pkg + "/schema.json"
+			case isDir(filepath.Join(dir, pkg)):
+				out = pkg + "/schema.json"
+			}
+		}
+		found = append(found, Ecosystem{Name: "Python (pyproject.toml)", Generate: map[string]GenConfig{
+			"jsonschema": {Enabled: true, Output: out},
+		}})
+	}
+
+	return found
+}
+
+// snakeCase converts a project name to an identifier, e.g. my-app to my_app
+func snakeCase(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteByte('_')
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "_")
+	switch {
+	case s == "":
+		return "app"
+	case unicode.IsDigit(rune(s[0])):
+		return "app_" + s
+	}
+	return s
+}
+
+// camelCase converts a snake_case name to a module name, e.g. my_app to MyApp
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetImportVersion rewrites the imports of the module base in platosl.yaml
+// data for a new version, editing the YAML document in place like Upgrade.
+// A pinned import gets the new version; an import of a major version gets
+// the new major version when it changes; an import without a version is
+// kept. It returns the new data and a description of each change.
+func SetImportVersion(data []byte, base, version string) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("config file is not a mapping")
+	}
+	imports := mappingValue(doc.Content[0], "imports")
+	if imports == nil || imports.Kind != yaml.SequenceNode {
+		return data, nil, nil
+	}
+
+	var changes []string
+	for _, item := range imports.Content {
+		node := item
+		if item.Kind == yaml.MappingNode {
+			node = mappingValue(item, "pkg")
+		}
+		if node == nil || node.Kind != yaml.ScalarNode {
+			continue
+		}
+		pkgBase, old, hasVersion := strings.Cut(node.Value, "@")
+		if pkgBase != base || !hasVersion {
+			continue
+		}
+		next := version
+		if !strings.Contains(old, ".") {
+			next, _, _ = strings.Cut(version, ".")
+		}
+		if next == old {
+			continue
+		}
+		node.Value = base + "@" + next
+		changes = append(changes, fmt.Sprintf("imports: %s@%s → %s", base, old, node.Value))
+	}
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indentOf(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return buf.Bytes(), changes, nil
+}
@@ -3,10 +3,19 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/platoorg/plato-sl-cli/internal/deprecation"
+	"github.com/platoorg/plato-sl-cli/internal/severity"
 	"gopkg.in/yaml.v3"
 )
 
+// identPattern matches the names import aliases may have: CUE identifiers
+// that are not definitions or hidden
+var identPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
 // Load reads and parses a platosl.yaml configuration file
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -21,6 +30,9 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	for _, d := range deprecation.ConfigKeys(data) {
+		deprecation.Warn(d)
+	}
 
 	// Apply defaults
 	if cfg.Version == "" {
@@ -33,11 +45,66 @@ func Load(path string) (*Config, error) {
 		cfg.Generate = make(map[string]GenConfig)
 	}
 
+	if !slices.Contains(Versions, cfg.Version) {
+		return nil, fmt.Errorf("unsupported config version %q (this platosl supports %s)", cfg.Version, strings.Join(Versions, ", "))
+	}
+	for name, gen := range cfg.Generate {
+		if len(gen.Outputs) == 0 {
+			continue
+		}
+		if cfg.Version == "v1" {
+			return nil, fmt.Errorf("generate.%s.outputs requires version v2\n\nRun 'platosl upgrade-config' to upgrade platosl.yaml", name)
+		}
+		if gen.Output != "" {
+			return nil, fmt.Errorf("generate.%s sets both output and outputs", name)
+		}
+		gen.Output = gen.Outputs[0]
+		cfg.Generate[name] = gen
+	}
+	if _, err := severity.Parse("", cfg.Validation.Severity); err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string)
+	for _, imp := range cfg.Imports {
+		if imp.Pkg == "" {
+			return nil, fmt.Errorf("import without pkg in platosl.yaml")
+		}
+		if imp.Update != "" && !slices.Contains(UpdatePolicies, imp.Update) {
+			return nil, fmt.Errorf("unknown update policy %q of import %s (expected %s)", imp.Update, imp.Pkg, strings.Join(UpdatePolicies, ", "))
+		}
+		if imp.As == "" {
+			continue
+		}
+		if !identPattern.MatchString(imp.As) {
+			return nil, fmt.Errorf("import alias %q of %s is not an identifier", imp.As, imp.Pkg)
+		}
+		if other, ok := aliases[imp.As]; ok {
+			return nil, fmt.Errorf("import alias %q is used for both %s and %s", imp.As, other, imp.Pkg)
+		}
+		aliases[imp.As] = imp.Pkg
+	}
+
 	return &cfg, nil
 }
 
-// Save writes a configuration to a file
+// Save writes a configuration to a file. From version v2 on, outputs are
+// written as lists.
 func Save(path string, cfg *Config) error {
+	if cfg.Version != "v1" {
+		listed := *cfg
+		listed.Generate = make(map[string]GenConfig, len(cfg.Generate))
+		for name, gen := range cfg.Generate {
+			if len(gen.Outputs) == 0 && gen.Output != "" {
+				gen.Outputs = []string{gen.Output}
+			} else if len(gen.Outputs) > 0 {
+				gen.Outputs = append([]string{gen.Output}, gen.Outputs[1:]...)
+			}
+			gen.Output = ""
+			listed.Generate[name] = gen
+		}
+		cfg = &listed
+	}
+
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config version written by init and upgrade-config
+const CurrentVersion = "v2"
+
+// Versions lists the supported config versions, oldest first. Version v2
+// lists the outputs of each generator (outputs instead of output) and
+// drops the zod option of the typescript generator for the zod generator.
+var Versions = []string{"v1", "v2"}
+
+// migration rewrites a config from one version to the next. It edits the
+// YAML document in place, so comments and key order are kept, and
+// describes each change it makes.
+type migration struct {
+	from, to string
+	apply    func(root *yaml.Node) []string
+}
+
+// migrations upgrade a config one version at a time, in order
+var migrations = []migration{
+	{from: "v1", to: "v2", apply: func(root *yaml.Node) []string {
+		return append(listOutputs(root), moveZodOption(root)...)
+	}},
+}
+
+// Upgrade rewrites platosl.yaml data to a target version. It returns the
+// new data and a description of each change; no changes means the config
+// is already at the target version.
+func Upgrade(data []byte, target string) ([]byte, []string, error) {
+	if !slices.Contains(Versions, target) {
+		return nil, nil, fmt.Errorf("unknown config version %q (expected one of %s)", target, strings.Join(Versions, ", "))
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("config file is not a mapping")
+	}
+	root := doc.Content[0]
+
+	version := "v1"
+	if v := mappingValue(root, "version"); v != nil && v.Value != "" {
+		version = v.Value
+	}
+	from := slices.Index(Versions, version)
+	if from < 0 {
+		return nil, nil, fmt.Errorf("unsupported config version %q (this platosl supports %s)", version, strings.Join(Versions, ", "))
+	}
+	if from > slices.Index(Versions, target) {
+		return nil, nil, fmt.Errorf("config is at version %s, which is newer than %s; downgrades are not supported", version, target)
+	}
+	if version == target {
+		return data, nil, nil
+	}
+
+	changes := []string{fmt.Sprintf("version: %s → %s", version, target)}
+	for _, m := range migrations {
+		if m.from != version || version == target {
+			continue
+		}
+		changes = append(changes, m.apply(root)...)
+		version = m.to
+	}
+	setVersion(root, target)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indentOf(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// listOutputs turns the output of each generator into an outputs list (v1
+// to v2)
+func listOutputs(root *yaml.Node) []string {
+	generate := mappingValue(root, "generate")
+	if generate == nil || generate.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var changes []string
+	for i := 0; i+1 < len(generate.Content); i += 2 {
+		name, gen := generate.Content[i].Value, generate.Content[i+1]
+		if gen.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(gen.Content); j += 2 {
+			key, value := gen.Content[j], gen.Content[j+1]
+			if key.Value != "output" || value.Kind != yaml.ScalarNode {
+				continue
+			}
+			if value.Value == "" {
+				gen.Content = slices.Delete(gen.Content, j, j+2)
+				changes = append(changes, fmt.Sprintf("generate.%s.output: removed empty output", name))
+				break
+			}
+			key.Value = "outputs"
+			gen.Content[j+1] = &yaml.Node{
+				Kind:        yaml.SequenceNode,
+				Tag:         "!!seq",
+				Style:       yaml.FlowStyle,
+				Content:     []*yaml.Node{value},
+				LineComment: value.LineComment,
+			}
+			value.LineComment = ""
+			changes = append(changes, fmt.Sprintf("generate.%s.output → outputs: [%s]", name, value.Value))
+			break
+		}
+	}
+	return changes
+}
+
+// moveZodOption removes the zod option of the typescript generator, which
+// has no effect, and enables the zod generator in its place when the option
+// was set and the zod generator is not configured (v1 to v2). The schemas
+// are written next to the first TypeScript output.
+func moveZodOption(root *yaml.Node) []string {
+	generate := mappingValue(root, "generate")
+	if generate == nil || generate.Kind != yaml.MappingNode {
+		return nil
+	}
+	ts := mappingValue(generate, "typescript")
+	if ts == nil || ts.Kind != yaml.MappingNode {
+		return nil
+	}
+	options := mappingValue(ts, "options")
+	if options == nil || options.Kind != yaml.MappingNode {
+		return nil
+	}
+	zod := mappingValue(options, "zod")
+	if zod == nil {
+		return nil
+	}
+
+	removeKey(options, "zod")
+	if len(options.Content) == 0 {
+		removeKey(ts, "options")
+	}
+	changes := []string{"generate.typescript.options.zod: removed"}
+
+	var enabled bool
+	if err := zod.Decode(&enabled); err != nil || !enabled || mappingValue(generate, "zod") != nil {
+		return changes
+	}
+
+	output := "generated/schemas.ts"
+	if outputs := mappingValue(ts, "outputs"); outputs != nil && outputs.Kind == yaml.SequenceNode && len(outputs.Content) > 0 {
+		output = path.Join(path.Dir(outputs.Content[0].Value), "schemas.ts")
+	}
+	generate.Content = append(generate.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "zod"},
+		&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "enabled"},
+			{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "outputs"},
+			{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: output},
+			}},
+		}},
+	)
+	return append(changes, fmt.Sprintf("generate.zod: enabled with outputs: [%s]", output))
+}
+
+// removeKey removes a key and its value from a mapping node
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setVersion sets the version of a config, adding it as the first key when
+// missing. A comment at the top of the file stays there.
+func setVersion(root *yaml.Node, version string) {
+	if v := mappingValue(root, "version"); v != nil {
+		v.Kind, v.Tag, v.Style, v.Value = yaml.ScalarNode, "!!str", 0, version
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	if len(root.Content) > 0 {
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, {Kind: yaml.ScalarNode, Tag: "!!str", Value: version}}, root.Content...)
+}
+
+// indentOf returns the indentation of YAML data: the smallest indentation
+// of a nested line, or 4 as written by Save
+func indentOf(data []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		n := len(line) - len(trimmed)
+		if n == 0 || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent == 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent < 2 {
+		return 4
+	}
+	return indent
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/platoorg/plato-sl-cli/internal/deprecation"
+)
+
+func TestUpgrade(t *testing.T) {
+	v1 := `# Project config
+name: shop
+schemas:
+  - schemas/
+generate:
+  typescript:
+    enabled: true
+    output: web/types.ts # the app imports this
+    options:
+      zod: true
+  go:
+    enabled: true
+    output: ""
+`
+	data, changes, err := Upgrade([]byte(v1), "v2")
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"# Project config\nversion: v2\n",
+		"outputs: [web/types.ts] # the app imports this",
+		"zod:\n    enabled: true\n    outputs: [web/schemas.ts]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("upgraded config lacks %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"options", "output:"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("upgraded config still has %q:\n%s", unwanted, out)
+		}
+	}
+	if len(changes) != 5 {
+		t.Errorf("changes = %q, want the version, two outputs and the zod option", changes)
+	}
+
+	if deprecated := deprecation.ConfigKeys(data); len(deprecated) != 0 {
+		t.Errorf("upgraded config has deprecated keys: %v", deprecated)
+	}
+
+	again, changes, err := Upgrade(data, "v2")
+	if err != nil || changes != nil || string(again) != out {
+		t.Errorf("second Upgrade = %q, %v, want no changes", changes, err)
+	}
+	if _, _, err := Upgrade([]byte("version: v3\n"), "v2"); err == nil {
+		t.Errorf("Upgrade of an unknown version did not fail")
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfig holds per-user defaults, read from config.yaml in the user
+// config directory. Project settings in platosl.yaml take precedence.
+type UserConfig struct {
+	// Registry is the registry used by login, logout and whoami when none
+	// is given
+	Registry string `yaml:"registry,omitempty"`
+
+	// Color is auto (default), always or never
+	Color string `yaml:"color,omitempty"`
+
+	// Author identifies the user in audit records
+	Author AuthorConfig `yaml:"author,omitempty"`
+
+	// MaxWorkers and MemoryLimit are the defaults of --max-workers and
+	// --memory-limit
+	MaxWorkers  int    `yaml:"maxWorkers,omitempty"`
+	MemoryLimit string `yaml:"memoryLimit,omitempty"`
+
+	// Network holds defaults for the network settings of every project
+	Network NetworkConfig `yaml:"network,omitempty"`
+
+	// Draft configures the model of platosl draft, unless the project does
+	Draft DraftConfig `yaml:"draft,omitempty"`
+
+	// Cache configures the storage of imported modules
+	Cache CacheConfig `yaml:"cache,omitempty"`
+}
+
+// CacheConfig selects where the modules imported by schemas are cached
+type CacheConfig struct {
+	// Backend is fs (default), a file per module in the cache directory,
+	// or sqlite, a single database that long-running serve and watch
+	// processes share safely with other invocations
+	Backend string `yaml:"backend,omitempty"`
+
+	// Dir overrides the cache directory
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// AuthorConfig identifies the user
+type AuthorConfig struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// String returns the author as "Name <email>", or whichever part is set
+func (a AuthorConfig) String() string {
+	switch {
+	case a.Name != "" && a.Email != "":
+		return a.Name + " <" + a.Email + ">"
+	case a.Name != "":
+		return a.Name
+	}
+	return a.Email
+}
+
+// UserConfigDir returns the directory of the user config:
+// $XDG_CONFIG_HOME/platosl when set, otherwise platosl in the OS config
+// directory (~/.config on Linux, ~/Library/Application Support on macOS,
+// %AppData% on Windows)
+func UserConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "platosl"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "platosl"), nil
+}
+
+// CacheDir returns the cache directory: $XDG_CACHE_HOME/platosl when set,
+// otherwise platosl in the OS cache directory (~/.cache on Linux,
+// ~/Library/Caches on macOS, %LocalAppData% on Windows)
+func CacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "platosl"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "platosl"), nil
+}
+
+// ImportCacheDir returns the directory of the import cache: the configured
+// one, or the cache directory
+func ImportCacheDir(cfg CacheConfig) (string, error) {
+	if cfg.Dir != "" {
+		return os.ExpandEnv(cfg.Dir), nil
+	}
+	return CacheDir()
+}
+
+// UserConfigPath returns the path of the user config file.
+// PLATOSL_USER_CONFIG overrides it.
+func UserConfigPath() (string, error) {
+	if path := os.Getenv("PLATOSL_USER_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// LoadUser reads the user config. A missing file is an empty config.
+func LoadUser() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg UserConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read user config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse user config %s: %w", path, err)
+	}
+
+	switch cfg.Color {
+	case "", "auto", "always", "never":
+	default:
+		return nil, fmt.Errorf("invalid color %q in %s (expected auto, always or never)", cfg.Color, path)
+	}
+	switch cfg.Cache.Backend {
+	case "", "fs", "sqlite":
+	default:
+		return nil, fmt.Errorf("invalid cache backend %q in %s (expected fs or sqlite)", cfg.Cache.Backend, path)
+	}
+	return &cfg, nil
+}
+
+// MergeNetwork returns the network settings of a project, with the user
+// defaults for the settings the project leaves out. Project tokens take
+// precedence over user tokens for the same host.
+func MergeNetwork(project, user NetworkConfig) NetworkConfig {
+	merged := project
+	if merged.Timeout == "" {
+		merged.Timeout = user.Timeout
+	}
+	if merged.Retries == nil {
+		merged.Retries = user.Retries
+	}
+	if merged.RateLimit == 0 {
+		merged.RateLimit = user.RateLimit
+	}
+	if merged.Proxy == "" {
+		merged.Proxy = user.Proxy
+	}
+	if merged.CAFile == "" {
+		merged.CAFile = user.CAFile
+	}
+	if len(user.Tokens) > 0 {
+		merged.Tokens = make(map[string]string, len(project.Tokens)+len(user.Tokens))
+		for host, token := range user.Tokens {
+			merged.Tokens[host] = token
+		}
+		for host, token := range project.Tokens {
+			merged.Tokens[host] = token
+		}
+	}
+	return merged
+}
+
+// MergeDraft returns the draft settings of a project, falling back to the
+// user's when the project configures no endpoint or command. The API key is
+// personal and always taken from the user config when set.
+func MergeDraft(project, user DraftConfig) DraftConfig {
+	merged := project
+	if merged.Endpoint == "" && len(merged.Command) == 0 {
+		merged.Endpoint, merged.Command = user.Endpoint, user.Command
+		if user.Model != "" {
+			merged.Model = user.Model
+		}
+	}
+	if user.APIKey != "" {
+		merged.APIKey = user.APIKey
+	}
+	return merged
+}
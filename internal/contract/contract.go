@@ -0,0 +1,189 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/endpoint"
+	"github.com/platoorg/plato-sl-cli/internal/fuzz"
+)
+
+// maxBodySize bounds response bodies read for validation
+const maxBodySize = 10 << 20
+
+// maxErrors bounds the validation errors reported per endpoint
+const maxErrors = 10
+
+// Result outcomes
+const (
+	Pass = "pass"
+	Fail = "fail"
+	Skip = "skip"
+)
+
+// Doer sends HTTP requests, e.g. an httpclient.Client
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Options configures a contract test run
+type Options struct {
+	// BaseURL is prepended to endpoint paths, e.g. https://staging.example.com/api
+	BaseURL string
+
+	// Methods are the methods to test; other endpoints are skipped. Only GET
+	// is tested by default, since other methods change data.
+	Methods []string
+
+	// Params are the values of path parameters, by name
+	Params map[string]string
+
+	// Headers are added to every request
+	Headers map[string]string
+
+	// Seed seeds the request bodies generated for request definitions
+	Seed int64
+}
+
+// Result is the outcome of calling one endpoint
+type Result struct {
+	Endpoint   string   `json:"endpoint"`
+	URL        string   `json:"url,omitempty"`
+	Response   string   `json:"response"`
+	Outcome    string   `json:"outcome"`
+	StatusCode int      `json:"statusCode,omitempty"`
+	DurationMS int64    `json:"durationMs"`
+	Failures   []string `json:"failures,omitempty"`
+	SkipReason string   `json:"skipReason,omitempty"`
+}
+
+// Run calls every endpoint with a tested method and checks the status code,
+// content type and body of each response against its definition.
+// Endpoints are called one at a time, in order.
+func Run(schemas cue.Value, endpoints []endpoint.Endpoint, client Doer, opts Options) []Result {
+	methods := opts.Methods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	tested := make(map[string]bool)
+	for _, m := range methods {
+		tested[strings.ToUpper(m)] = true
+	}
+
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	results := make([]Result, 0, len(endpoints))
+	for _, e := range endpoints {
+		result := Result{Endpoint: e.String(), Response: e.Response}
+		path, missing := e.Expand(opts.Params)
+		switch {
+		case !tested[e.Method]:
+			result.Outcome = Skip
+			result.SkipReason = fmt.Sprintf("%s is not tested (see --method)", e.Method)
+		case len(missing) > 0:
+			result.Outcome = Skip
+			result.SkipReason = fmt.Sprintf("no value for path parameter(s) %s (see --param)", strings.Join(missing, ", "))
+		default:
+			result.URL = strings.TrimSuffix(opts.BaseURL, "/") + path
+			check(schemas, e, client, opts.Headers, rnd, &result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// check calls an endpoint and records the failures of its response
+func check(schemas cue.Value, e endpoint.Endpoint, client Doer, headers map[string]string, rnd *rand.Rand, result *Result) {
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+	defer func() {
+		result.Outcome = Pass
+		if len(result.Failures) > 0 {
+			result.Outcome = Fail
+		}
+	}()
+
+	var body io.Reader
+	if e.Request != "" {
+		def, err := platoCue.LookupDefinition(schemas, e.Request)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		data, ok, err := fuzz.ValidInstance(def, rnd, 20)
+		if err != nil || !ok {
+			fail("could not generate a valid %s request body", e.Request)
+			return
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(e.Method, result.URL, body)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.DurationMS = time.Since(start).Milliseconds()
+		fail("%v", err)
+		return
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	result.DurationMS = time.Since(start).Milliseconds()
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		fail("failed to read the response: %v", err)
+		return
+	}
+
+	if resp.StatusCode != e.Status {
+		fail("expected status %d, got %d", e.Status, resp.StatusCode)
+		return
+	}
+	if !e.HasBody() {
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		fail("expected a JSON content type, got %q", resp.Header.Get("Content-Type"))
+		return
+	}
+	if !json.Valid(data) {
+		fail("response body is not valid JSON")
+		return
+	}
+
+	def, err := platoCue.LookupDefinition(schemas, e.Response)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+	validation := platoCue.ValidateData(def, def.Context().CompileBytes(data))
+	for i, ve := range validation.Errors {
+		if i == maxErrors {
+			fail("... and %d more error(s)", len(validation.Errors)-maxErrors)
+			break
+		}
+		fail("%s", ve.Message)
+	}
+}
@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Summary is the JSON report of a run
+type Summary struct {
+	BaseURL string   `json:"baseUrl"`
+	Total   int      `json:"total"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Results []Result `json:"results"`
+}
+
+// Summarize counts the outcomes of a run
+func Summarize(baseURL string, results []Result) Summary {
+	s := Summary{BaseURL: baseURL, Total: len(results), Results: results}
+	for _, r := range results {
+		switch r.Outcome {
+		case Pass:
+			s.Passed++
+		case Fail:
+			s.Failed++
+		default:
+			s.Skipped++
+		}
+	}
+	return s
+}
+
+// WriteTAP writes results in the Test Anything Protocol (version 13), with
+// skipped endpoints marked # SKIP and failures as YAML diagnostics
+func WriteTAP(w io.Writer, baseURL string, results []Result) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+
+	for i, r := range results {
+		switch r.Outcome {
+		case Skip:
+			fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", i+1, r.Endpoint, r.SkipReason)
+			continue
+		case Pass:
+			fmt.Fprintf(&b, "ok %d - %s (%dms)\n", i+1, r.Endpoint, r.DurationMS)
+			continue
+		}
+
+		fmt.Fprintf(&b, "not ok %d - %s\n", i+1, r.Endpoint)
+		b.WriteString("  ---\n")
+		fmt.Fprintf(&b, "  url: %s\n", yamlString(r.URL))
+		fmt.Fprintf(&b, "  response: %s\n", yamlString(r.Response))
+		if r.StatusCode != 0 {
+			fmt.Fprintf(&b, "  status: %d\n", r.StatusCode)
+		}
+		b.WriteString("  failures:\n")
+		for _, f := range r.Failures {
+			fmt.Fprintf(&b, "    - %s\n", yamlString(f))
+		}
+		b.WriteString("  ...\n")
+	}
+
+	s := Summarize(baseURL, results)
+	fmt.Fprintf(&b, "# pass %d\n", s.Passed)
+	fmt.Fprintf(&b, "# fail %d\n", s.Failed)
+	fmt.Fprintf(&b, "# skip %d\n", s.Skipped)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the summary and results as indented JSON
+func WriteJSON(w io.Writer, baseURL string, results []Result) error {
+	data, err := json.MarshalIndent(Summarize(baseURL, results), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// yamlString quotes a string for YAML; JSON strings are valid YAML scalars
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
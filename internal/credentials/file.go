@@ -0,0 +1,115 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// FileStore keeps tokens in a JSON file readable only by the current user
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates a file store at path, or at the default location
+// (<user config dir>/platosl/credentials.json) when path is empty
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		dir, err := config.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "credentials.json")
+	}
+	return &FileStore{path: path}, nil
+}
+
+// Name returns the backend name
+func (s *FileStore) Name() string {
+	return "file (" + s.path + ")"
+}
+
+// Get returns the token stored for a registry
+func (s *FileStore) Get(registry string) (string, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[registry]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
+
+// Set stores a token for a registry
+func (s *FileStore) Set(registry, token string) error {
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[registry] = token
+	return s.write(tokens)
+}
+
+// Delete removes the token stored for a registry
+func (s *FileStore) Delete(registry string) error {
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[registry]; !ok {
+		return ErrNotFound
+	}
+	delete(tokens, registry)
+	return s.write(tokens)
+}
+
+// List returns the registries with stored tokens
+func (s *FileStore) List() ([]string, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(tokens), nil
+}
+
+// read loads the credentials file, treating a missing file as empty
+func (s *FileStore) read() (map[string]string, error) {
+	tokens := make(map[string]string)
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+// write saves the credentials file with owner-only permissions
+func (s *FileStore) write(tokens map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+
+	// WriteFile keeps the mode of an existing file; tighten it explicitly
+	return os.Chmod(s.path, 0600)
+}
@@ -0,0 +1,171 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// keychainService is the service name tokens are stored under
+const keychainService = "platosl"
+
+// keychainStore keeps tokens in the OS keychain via the platform CLI
+// (security on macOS, secret-tool on Linux). Keychains cannot be enumerated
+// portably, so the registry names (not tokens) are tracked in an index file.
+type keychainStore struct {
+	index string
+}
+
+// newKeychainStore returns a keychain store, or nil if no keychain CLI is available
+func newKeychainStore() *keychainStore {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux":
+		tool = "secret-tool"
+	default:
+		return nil
+	}
+
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil
+	}
+
+	dir, err := config.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+
+	return &keychainStore{index: filepath.Join(dir, "logins.json")}
+}
+
+// Name returns the backend name
+func (s *keychainStore) Name() string {
+	return "keychain"
+}
+
+// Get returns the token stored for a registry
+func (s *keychainStore) Get(registry string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", registry, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "registry", registry)
+	}
+
+	out, err := cmd.Output()
+	token := strings.TrimSpace(string(out))
+	if err != nil || token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
+
+// Set stores a token for a registry
+func (s *keychainStore) Set(registry, token string) error {
+	cmd := storeCommand(runtime.GOOS, registry, token)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store token in keychain: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+
+	return s.updateIndex(registry, true)
+}
+
+// storeCommand returns the command storing a token for a registry. The
+// token is passed on stdin, never as an argument, which other processes
+// can read.
+func storeCommand(goos, registry, token string) *exec.Cmd {
+	if goos == "darwin" {
+		// -w without a value, as the last option, prompts for the
+		// password twice
+		cmd := exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", registry, "-w")
+		cmd.Stdin = strings.NewReader(token + "\n" + token + "\n")
+		return cmd
+	}
+	cmd := exec.Command("secret-tool", "store", "--label", "platosl: "+registry, "service", keychainService, "registry", registry)
+	cmd.Stdin = strings.NewReader(token)
+	return cmd
+}
+
+// Delete removes the token stored for a registry
+func (s *keychainStore) Delete(registry string) error {
+	if _, err := s.Get(registry); err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", registry)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keychainService, "registry", registry)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove token from keychain: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+
+	return s.updateIndex(registry, false)
+}
+
+// List returns the registries with stored tokens
+func (s *keychainStore) List() ([]string, error) {
+	return s.readIndex()
+}
+
+// readIndex loads the registry index
+func (s *keychainStore) readIndex() ([]string, error) {
+	var registries []string
+
+	data, err := os.ReadFile(s.index)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return registries, nil
+		}
+		return nil, fmt.Errorf("failed to read login index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &registries); err != nil {
+		return nil, fmt.Errorf("failed to parse login index %s: %w", s.index, err)
+	}
+	return registries, nil
+}
+
+// updateIndex adds or removes a registry from the index
+func (s *keychainStore) updateIndex(registry string, add bool) error {
+	registries, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var updated []string
+	for _, r := range registries {
+		if r == registry || seen[r] {
+			continue
+		}
+		seen[r] = true
+		updated = append(updated, r)
+	}
+	if add {
+		updated = append(updated, registry)
+	}
+	sort.Strings(updated)
+
+	if err := os.MkdirAll(filepath.Dir(s.index), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.index, data, 0600)
+}
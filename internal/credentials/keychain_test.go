@@ -0,0 +1,32 @@
+package credentials
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStoreCommandKeepsTokenOutOfArgs(t *testing.T) {
+	const token = "secret-token"
+	for _, goos := range []string{"darwin", "linux"} {
+		cmd := storeCommand(goos, "registry.example.com", token)
+		for _, arg := range cmd.Args {
+			if strings.Contains(arg, token) {
+				t.Errorf("%s: token passed as argument %q", goos, arg)
+			}
+		}
+		stdin, err := io.ReadAll(cmd.Stdin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(stdin), token) {
+			t.Errorf("%s: token not passed on stdin", goos)
+		}
+	}
+
+	// security reads the password only when -w is the last option
+	if args := storeCommand("darwin", "registry.example.com", token).Args; args[len(args)-1] != "-w" || slices.Index(args, "-w") != len(args)-1 {
+		t.Errorf("security arguments %q, want -w last", args)
+	}
+}
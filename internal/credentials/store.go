@@ -0,0 +1,139 @@
+package credentials
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotFound is returned when no credential is stored for a registry
+var ErrNotFound = errors.New("no credentials stored")
+
+// Store persists registry auth tokens
+type Store interface {
+	// Name identifies the backend (e.g. "keychain", "file")
+	Name() string
+
+	// Get returns the token stored for a registry
+	Get(registry string) (string, error)
+
+	// Set stores a token for a registry
+	Set(registry, token string) error
+
+	// Delete removes the token stored for a registry
+	Delete(registry string) error
+
+	// List returns the registries with stored tokens
+	List() ([]string, error)
+}
+
+// Default returns the OS keychain store when available, otherwise the
+// permission-restricted credentials file in the user config directory. A
+// keychain CLI can be installed without a keychain to talk to, e.g.
+// secret-tool without a D-Bus session, so the file takes over when the
+// keychain fails too.
+func Default() (Store, error) {
+	file, err := NewFileStore("")
+	if err != nil {
+		return nil, err
+	}
+	if ks := newKeychainStore(); ks != nil {
+		return &fallbackStore{primary: ks, fallback: file}, nil
+	}
+	return file, nil
+}
+
+// fallbackStore stores tokens in a primary store until it fails, and in
+// the fallback store from then on. Tokens are looked up in both, as earlier
+// runs may have fallen back.
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+	failed   bool
+}
+
+// Name returns the name of the store tokens are stored in
+func (s *fallbackStore) Name() string {
+	if s.failed {
+		return s.fallback.Name()
+	}
+	return s.primary.Name()
+}
+
+// Get returns the token stored for a registry
+func (s *fallbackStore) Get(registry string) (string, error) {
+	if token, err := s.primary.Get(registry); err == nil {
+		return token, nil
+	}
+	return s.fallback.Get(registry)
+}
+
+// Set stores a token for a registry
+func (s *fallbackStore) Set(registry, token string) error {
+	if !s.failed {
+		if err := s.primary.Set(registry, token); err == nil {
+			return nil
+		}
+		s.failed = true
+	}
+	return s.fallback.Set(registry, token)
+}
+
+// Delete removes the token stored for a registry from both stores
+func (s *fallbackStore) Delete(registry string) error {
+	primaryErr := s.primary.Delete(registry)
+	fallbackErr := s.fallback.Delete(registry)
+	switch {
+	case primaryErr == nil || fallbackErr == nil:
+		return nil
+	case errors.Is(fallbackErr, ErrNotFound):
+		return primaryErr
+	default:
+		return fallbackErr
+	}
+}
+
+// List returns the registries with tokens in either store
+func (s *fallbackStore) List() ([]string, error) {
+	registries := make(map[string]string)
+	primary, primaryErr := s.primary.List()
+	fallback, fallbackErr := s.fallback.List()
+	if primaryErr != nil && fallbackErr != nil {
+		return nil, primaryErr
+	}
+	for _, r := range append(primary, fallback...) {
+		registries[r] = r
+	}
+	return sortedKeys(registries), nil
+}
+
+// Token looks up a registry token in the default store, returning an empty
+// string when none is stored or the store is unavailable.
+func Token(registry string) string {
+	store, err := Default()
+	if err != nil {
+		return ""
+	}
+	token, err := store.Get(registry)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// Mask returns a token with all but its first and last four characters hidden
+func Mask(token string) string {
+	if len(token) <= 8 {
+		return "********"
+	}
+	return token[:4] + "…" + token[len(token)-4:]
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package credentials
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// brokenStore is a keychain whose CLI is installed but cannot reach a
+// keychain: lookups find nothing and writes fail
+type brokenStore struct{}
+
+func (brokenStore) Name() string               { return "keychain" }
+func (brokenStore) Get(string) (string, error) { return "", ErrNotFound }
+func (brokenStore) Set(string, string) error {
+	return errors.New("cannot autolaunch D-Bus without X11 $DISPLAY")
+}
+func (brokenStore) Delete(string) error     { return ErrNotFound }
+func (brokenStore) List() ([]string, error) { return nil, nil }
+
+func TestFallbackStoreUsesFileWhenKeychainFails(t *testing.T) {
+	file, err := NewFileStore(filepath.Join(t.TempDir(), "credentials.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &fallbackStore{primary: brokenStore{}, fallback: file}
+
+	if err := store.Set("registry.example.com", "secret-token"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if store.Name() != file.Name() {
+		t.Errorf("Name() = %q, want %q", store.Name(), file.Name())
+	}
+
+	// A later run starts with the keychain again and must find the token
+	store = &fallbackStore{primary: brokenStore{}, fallback: file}
+	if token, err := store.Get("registry.example.com"); err != nil || token != "secret-token" {
+		t.Errorf("Get = %q, %v, want the stored token", token, err)
+	}
+	if registries, err := store.List(); err != nil || !slices.Equal(registries, []string{"registry.example.com"}) {
+		t.Errorf("List = %v, %v, want the registry", registries, err)
+	}
+	if err := store.Delete("registry.example.com"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if err := store.Delete("registry.example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
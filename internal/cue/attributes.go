@@ -0,0 +1,116 @@
+package cue
+
+import (
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// Attr is a parsed CUE attribute such as @pii(email) or @acl(read=admin)
+type Attr struct {
+	Name   string
+	Args   []string
+	Params map[string]string
+}
+
+// Arg returns the i-th positional argument, or an empty string
+func (a Attr) Arg(i int) string {
+	if i < len(a.Args) {
+		return a.Args[i]
+	}
+	return ""
+}
+
+// Param returns a key=value argument, or an empty string
+func (a Attr) Param(key string) string {
+	return a.Params[key]
+}
+
+// GetAttr looks up a field or declaration attribute by name
+func GetAttr(val cue.Value, name string) (Attr, bool) {
+	for _, attr := range val.Attributes(cue.ValueAttr) {
+		if attr.Name() != name {
+			continue
+		}
+
+		result := Attr{
+			Name:   name,
+			Params: make(map[string]string),
+		}
+
+		for i := 0; i < attr.NumArgs(); i++ {
+			key, value := attr.Arg(i)
+			if isParam(attr.RawArg(i), key) {
+				result.Params[strings.TrimSpace(key)] = value
+			} else if key != "" {
+				result.Args = append(result.Args, key)
+			}
+		}
+
+		return result, true
+	}
+
+	return Attr{}, false
+}
+
+// isParam reports whether a raw attribute argument is key=value, as
+// opposed to a quoted argument that contains "=", e.g. "must be >= 3"
+func isParam(raw, key string) bool {
+	raw = strings.TrimSpace(raw)
+	key = strings.TrimSpace(key)
+	if key == "" || !strings.HasPrefix(raw, key) {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(raw[len(key):]), "=")
+}
+
+// HasAttr reports whether a value carries the named attribute
+func HasAttr(val cue.Value, name string) bool {
+	_, ok := GetAttr(val, name)
+	return ok
+}
+
+// DocComment returns the doc comment attached to a value, trimmed
+func DocComment(val cue.Value) string {
+	var parts []string
+	for _, doc := range val.Doc() {
+		if text := strings.TrimSpace(doc.Text()); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ErrorMessages are the validation messages of a field from its @errmsg
+// attribute: @errmsg("Please enter a valid email") sets the message of every
+// constraint, and @errmsg(pattern="Use lowercase letters", minLength="Too
+// short") sets messages per JSON Schema keyword: pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems and
+// maxItems.
+type ErrorMessages struct {
+	Default  string
+	Keywords map[string]string
+}
+
+// ErrorMessagesOf returns the @errmsg messages of a field
+func ErrorMessagesOf(val cue.Value) ErrorMessages {
+	attr, ok := GetAttr(val, "errmsg")
+	if !ok {
+		return ErrorMessages{}
+	}
+	return ErrorMessages{Default: attr.Arg(0), Keywords: attr.Params}
+}
+
+// IsZero reports whether there are no messages
+func (m ErrorMessages) IsZero() bool {
+	return m.Default == "" && len(m.Keywords) == 0
+}
+
+// For returns the message of a keyword, falling back to the default
+// message; it is empty when neither is set
+func (m ErrorMessages) For(keyword string) string {
+	if msg := m.Keywords[keyword]; msg != "" {
+		return msg
+	}
+	return m.Default
+}
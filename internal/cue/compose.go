@@ -0,0 +1,261 @@
+package cue
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+)
+
+// maxComposeDepth limits how deep nested structs are expanded in a composition
+const maxComposeDepth = 8
+
+// Composition describes a fully unified definition and where each part came from
+type Composition struct {
+	Definition string          `json:"definition" yaml:"definition"`
+	Sources    []SourceRef     `json:"sources" yaml:"sources"`
+	Fields     []ComposedField `json:"fields" yaml:"fields"`
+}
+
+// ComposedField holds a unified field and the conjuncts that contributed to it
+type ComposedField struct {
+	Path      string     `json:"path" yaml:"path"`
+	Type      string     `json:"type" yaml:"type"`
+	Optional  bool       `json:"optional" yaml:"optional"`
+	Reference string     `json:"reference,omitempty" yaml:"reference,omitempty"`
+	Conjuncts []Conjunct `json:"conjuncts" yaml:"conjuncts"`
+}
+
+// Conjunct is a single constraint contributing to a field
+type Conjunct struct {
+	Expr   string    `json:"expr" yaml:"expr"`
+	Source SourceRef `json:"source" yaml:"source"`
+}
+
+// SourceRef points at the file (and import package, if any) a value came from
+type SourceRef struct {
+	File    string `json:"file" yaml:"file"`
+	Line    int    `json:"line" yaml:"line"`
+	Package string `json:"package,omitempty" yaml:"package,omitempty"`
+}
+
+// String formats the source reference as file:line [package]
+func (s SourceRef) String() string {
+	if s.File == "" {
+		return "<unknown>"
+	}
+	ref := fmt.Sprintf("%s:%d", s.File, s.Line)
+	if s.Package != "" {
+		ref += fmt.Sprintf(" [%s]", s.Package)
+	}
+	return ref
+}
+
+// LookupDefinition finds a definition by name, with or without the leading #
+func LookupDefinition(val cue.Value, name string) (cue.Value, error) {
+	if !strings.HasPrefix(name, "#") {
+		name = "#" + name
+	}
+
+	def := val.LookupPath(cue.ParsePath(name))
+	if !def.Exists() {
+		return cue.Value{}, fmt.Errorf("definition %s not found", name)
+	}
+
+	return def, nil
+}
+
+// Compose builds a composition report for the named definition
+func Compose(val cue.Value, name string) (*Composition, error) {
+	def, err := LookupDefinition(val, name)
+	if err != nil {
+		return nil, err
+	}
+
+	comp := &Composition{
+		Definition: def.Path().String(),
+		Sources:    []SourceRef{},
+		Fields:     []ComposedField{},
+	}
+
+	for _, c := range conjuncts(def) {
+		comp.Sources = append(comp.Sources, c.Source)
+	}
+
+	if err := composeFields(def, "", 0, &comp.Fields); err != nil {
+		return nil, err
+	}
+
+	return comp, nil
+}
+
+// composeFields walks the fields of a struct value and records their conjuncts
+func composeFields(val cue.Value, prefix string, depth int, out *[]ComposedField) error {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return fmt.Errorf("failed to iterate fields: %w", err)
+	}
+
+	for iter.Next() {
+		label := strings.TrimRight(iter.Selector().String(), "?!")
+		fieldVal := iter.Value()
+
+		fieldPath := label
+		if prefix != "" {
+			fieldPath = prefix + "." + label
+		}
+
+		field := ComposedField{
+			Path:      fieldPath,
+			Type:      typeName(fieldVal),
+			Optional:  iter.IsOptional(),
+			Conjuncts: conjuncts(fieldVal),
+		}
+
+		if _, ref := fieldVal.ReferencePath(); len(ref.Selectors()) > 0 {
+			field.Reference = ref.String()
+		}
+
+		*out = append(*out, field)
+
+		// Expand inline structs; referenced definitions are reported on their own
+		if field.Reference == "" && depth < maxComposeDepth && fieldVal.IncompleteKind() == cue.StructKind {
+			if err := composeFields(fieldVal, fieldPath, depth+1, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// typeName names the type of a field: the definition it refers to, the
+// branches of a disjunction, e.g. #Address | null, or its kind
+func typeName(val cue.Value) string {
+	if ref := DefinitionRef(val); ref != "" {
+		return ref
+	}
+
+	op, args := val.Expr()
+	if op == cue.OrOp {
+		var names []string
+		seen := make(map[string]bool, len(args))
+		for _, arg := range args {
+			name := typeName(arg)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, " | ")
+	}
+
+	switch val.IncompleteKind() {
+	case cue.NullKind:
+		return "null"
+	case cue.TopKind:
+		return "_"
+	}
+	return inferType(val)
+}
+
+// conjuncts splits a value into the expressions it was unified from
+func conjuncts(val cue.Value) []Conjunct {
+	op, args := val.Expr()
+	if op != cue.AndOp {
+		args = []cue.Value{val}
+	}
+
+	var result []Conjunct
+	for _, arg := range args {
+		result = append(result, Conjunct{
+			Expr:   exprString(arg),
+			Source: sourceRef(arg.Pos()),
+		})
+	}
+	return result
+}
+
+// exprString renders the source expression of a value
+func exprString(val cue.Value) string {
+	var node ast.Node = val.Source()
+	if field, ok := node.(*ast.Field); ok {
+		node = field.Value
+	}
+	if node == nil {
+		return fmt.Sprint(val)
+	}
+
+	data, err := format.Node(node)
+	if err != nil {
+		return fmt.Sprint(val)
+	}
+	return strings.Join(strings.Fields(string(data)), " ")
+}
+
+// sourceRef converts a token position into a source reference
+func sourceRef(pos token.Pos) SourceRef {
+	if !pos.IsValid() {
+		return SourceRef{}
+	}
+
+	file := pos.Filename()
+	ref := SourceRef{
+		File:    file,
+		Line:    pos.Line(),
+		Package: importPackage(file),
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			ref.File = rel
+		}
+	}
+
+	return ref
+}
+
+// importPackage derives the import path for files vendored under cue.mod
+func importPackage(file string) string {
+	slashed := filepath.ToSlash(file)
+	for _, dir := range []string{"/cue.mod/pkg/", "/cue.mod/gen/", "/cue.mod/usr/"} {
+		if idx := strings.Index(slashed, dir); idx >= 0 {
+			return path.Dir(slashed[idx+len(dir):])
+		}
+	}
+	return ""
+}
+
+// FormatComposition formats a composition report as a string
+func FormatComposition(comp *Composition) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", comp.Definition)
+	for _, src := range comp.Sources {
+		fmt.Fprintf(&b, "  defined in %s\n", src)
+	}
+	b.WriteString("\n")
+
+	for _, field := range comp.Fields {
+		optional := ""
+		if field.Optional {
+			optional = " (optional)"
+		}
+		typ := field.Type
+		if field.Reference != "" {
+			typ = field.Reference
+		}
+		fmt.Fprintf(&b, "  %s: %s%s\n", field.Path, typ, optional)
+		for _, c := range field.Conjuncts {
+			fmt.Fprintf(&b, "      %-40s %s\n", c.Expr, c.Source)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package cue
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestComposeFieldTypes(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Address: {city: string}
+#Status: "active" | "inactive"
+#User: {
+	address: #Address
+	next?:   #Address | null
+	status:  #Status
+	value:   string | int
+	kind:    "a" | "b"
+	nothing: null
+	tags:    [...string]
+	meta: {source: string}
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	comp, err := Compose(val, "User")
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	types := map[string]string{}
+	for _, field := range comp.Fields {
+		types[field.Path] = field.Type
+	}
+	want := map[string]string{
+		"address":     "#Address",
+		"next":        "#Address | null",
+		"status":      "#Status",
+		"value":       "string | int",
+		"kind":        "string",
+		"nothing":     "null",
+		"tags":        "list",
+		"meta":        "struct",
+		"meta.source": "string",
+	}
+	for path, typ := range want {
+		if types[path] != typ {
+			t.Errorf("%s: type %q, want %q", path, types[path], typ)
+		}
+	}
+	if comp.Definition != "#User" || len(comp.Fields) != len(want) {
+		t.Errorf("composition of %s has %d fields, want %d", comp.Definition, len(comp.Fields), len(want))
+	}
+}
@@ -0,0 +1,115 @@
+package cue
+
+import (
+	"cuelang.org/go/cue"
+)
+
+// maxConstraintDepth bounds the walk through nested conjunctions
+const maxConstraintDepth = 32
+
+// Constraints are the validation rules of a string, number or list value
+type Constraints struct {
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+
+	// MinLength and MaxLength count runes of strings and items of lists
+	MinLength *int
+	MaxLength *int
+
+	// Patterns are regular expressions a string must match
+	Patterns []string
+}
+
+// IsZero reports whether there are no constraints
+func (c Constraints) IsZero() bool {
+	return c.Minimum == nil && c.Maximum == nil && c.ExclusiveMinimum == nil && c.ExclusiveMaximum == nil &&
+		c.MinLength == nil && c.MaxLength == nil && len(c.Patterns) == 0
+}
+
+// ConstraintsOf collects bounds, patterns and length limits from the
+// conjuncts of a value, e.g. string & =~"^[a-z]+$" & strings.MaxRunes(20).
+// A string that must not be empty (!="") has a minimum length of 1.
+// Disjunctions are not descended into.
+func ConstraintsOf(val cue.Value) Constraints {
+	var c Constraints
+	var visit func(v cue.Value, depth int)
+	visit = func(v cue.Value, depth int) {
+		if depth > maxConstraintDepth {
+			return
+		}
+		op, args := v.Expr()
+		switch op {
+		case cue.AndOp:
+			for _, arg := range args {
+				visit(arg, depth+1)
+			}
+		case cue.GreaterThanEqualOp:
+			c.Minimum = tighter(c.Minimum, args[0], true)
+		case cue.GreaterThanOp:
+			c.ExclusiveMinimum = tighter(c.ExclusiveMinimum, args[0], true)
+		case cue.LessThanEqualOp:
+			c.Maximum = tighter(c.Maximum, args[0], false)
+		case cue.LessThanOp:
+			c.ExclusiveMaximum = tighter(c.ExclusiveMaximum, args[0], false)
+		case cue.RegexMatchOp:
+			if s, err := args[0].String(); err == nil {
+				c.Patterns = append(c.Patterns, s)
+			}
+		case cue.NotEqualOp:
+			if s, err := args[0].String(); err == nil && s == "" {
+				c.MinLength = longer(c.MinLength, 1)
+			}
+		case cue.CallOp:
+			c.call(args)
+		}
+	}
+	visit(val, 0)
+	return c
+}
+
+// call handles the length builtins of the strings and list packages
+func (c *Constraints) call(args []cue.Value) {
+	if len(args) != 2 {
+		return
+	}
+	name := ""
+	if _, sel := args[0].Expr(); len(sel) == 2 {
+		name, _ = sel[1].String()
+	}
+	limit, err := args[1].Int64()
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "MinRunes", "MinItems":
+		c.MinLength = longer(c.MinLength, int(limit))
+	case "MaxRunes", "MaxItems":
+		n := int(limit)
+		if c.MaxLength == nil || n < *c.MaxLength {
+			c.MaxLength = &n
+		}
+	}
+}
+
+// tighter keeps the stricter of two numeric bounds
+func tighter(current *float64, bound cue.Value, lower bool) *float64 {
+	next, err := bound.Float64()
+	if err != nil {
+		return current
+	}
+	if current == nil || (lower && next > *current) || (!lower && next < *current) {
+		return &next
+	}
+	return current
+}
+
+// longer keeps the larger of two minimum lengths
+func longer(current *int, n int) *int {
+	if current == nil || n > *current {
+		return &n
+	}
+	return current
+}
@@ -0,0 +1,102 @@
+package cue
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"gopkg.in/yaml.v3"
+)
+
+// DataExtensions lists the file extensions recognised as data files
+var DataExtensions = []string{".json", ".yaml", ".yml", ".cue"}
+
+// IsDataFile reports whether path has a recognised data file extension
+func IsDataFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range DataExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadDataFile loads a JSON, YAML or CUE data file as a CUE value
+func (l *Loader) LoadDataFile(path string) (cue.Value, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".cue":
+		return l.LoadFile(path)
+	case ".json", ".yaml", ".yml":
+		data, err := ReadDataFile(path)
+		if err != nil {
+			return cue.Value{}, err
+		}
+		val := l.ctx.Encode(data)
+		if err := val.Err(); err != nil {
+			return cue.Value{}, fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		return val, nil
+	default:
+		return cue.Value{}, fmt.Errorf("unsupported data file: %s (use .json, .yaml or .cue)", path)
+	}
+}
+
+// ReadDataFile decodes a JSON or YAML file into plain Go values
+func ReadDataFile(path string) (interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var data interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		// Decode numbers precisely so integers stay integers for CUE
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		data = normalizeNumbers(data)
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data file: %s (use .json or .yaml)", path)
+	}
+
+	return data, nil
+}
+
+// normalizeNumbers converts json.Number values into int64 or float64
+func normalizeNumbers(val interface{}) interface{} {
+	switch v := val.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	}
+	return val
+}
+
+// ValidateData unifies data with a definition and validates the result
+// strictly, so missing required fields and constraint violations are reported.
+func ValidateData(def, data cue.Value) *ValidationResult {
+	return NewValidator(true).Validate(def.Unify(data))
+}
@@ -0,0 +1,53 @@
+package cue
+
+import (
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// Docs holds the human-readable documentation of a definition or field,
+// declared with @title("Email address") and @description("Where receipts
+// are sent"). Without @description, the doc comment is the description.
+//
+// Generators take titles and descriptions from here rather than from doc
+// comments, which may hold notes not meant for the generated code.
+type Docs struct {
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// DocsOf reads the @title and @description attributes and doc comment of a
+// value
+func DocsOf(val cue.Value) Docs {
+	return Docs{Title: Title(val), Description: Description(val)}
+}
+
+// IsZero reports whether neither a title nor a description is declared
+func (d Docs) IsZero() bool {
+	return d.Title == "" && d.Description == ""
+}
+
+// Title returns the @title of a value, if any
+func Title(val cue.Value) string {
+	if attr, ok := GetAttr(val, "title"); ok {
+		return attrText(attr)
+	}
+	return ""
+}
+
+// Description returns the @description of a value, or its doc comment
+func Description(val cue.Value) string {
+	if attr, ok := GetAttr(val, "description"); ok {
+		if text := attrText(attr); text != "" {
+			return text
+		}
+	}
+	return DocComment(val)
+}
+
+// attrText returns the text of a free-form attribute. Unquoted text is
+// split at commas into several arguments, which are joined back.
+func attrText(attr Attr) string {
+	return strings.TrimSpace(strings.Join(attr.Args, ", "))
+}
@@ -9,16 +9,34 @@ import (
 
 // SchemaInfo holds information about a CUE schema
 type SchemaInfo struct {
-	Fields      []FieldInfo
-	Definitions []string
+	Fields      []FieldInfo `json:"fields" yaml:"fields"`
+	Definitions []string    `json:"definitions" yaml:"definitions"`
 }
 
 // FieldInfo holds information about a field
 type FieldInfo struct {
-	Name     string
-	Type     string
-	Optional bool
-	Path     string
+	Name     string `json:"name" yaml:"name"`
+	Type     string `json:"type" yaml:"type"`
+	Optional bool   `json:"optional" yaml:"optional"`
+	Path     string `json:"path" yaml:"path"`
+
+	// Docs holds the field's title and description, if any
+	Docs `yaml:",inline"`
+
+	// Measure holds the field's @unit / @currency annotations, if any
+	Measure `yaml:",inline"`
+}
+
+// DefinitionInfo holds information about a single definition and its fields
+type DefinitionInfo struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Kind is struct, union for disjunctions of structs, or the type of
+	// other definitions, e.g. string for #Status: "a" | "b"; only structs
+	// have fields
+	Kind   string `json:"kind" yaml:"kind"`
+	Docs   `yaml:",inline"`
+	Fields []FieldInfo `json:"fields" yaml:"fields"`
 }
 
 // Introspect extracts schema information from a CUE value
@@ -49,6 +67,8 @@ func Introspect(val cue.Value) (*SchemaInfo, error) {
 			Type:     inferType(value),
 			Optional: iter.IsOptional(),
 			Path:     iter.Selector().String(),
+			Docs:     DocsOf(value),
+			Measure:  MeasureOf(value),
 		}
 
 		info.Fields = append(info.Fields, fieldInfo)
@@ -57,6 +77,59 @@ func Introspect(val cue.Value) (*SchemaInfo, error) {
 	return info, nil
 }
 
+// IntrospectDefinitions extracts the fields of every top-level definition.
+// Definitions of other than structs are listed with their kind only.
+func IntrospectDefinitions(val cue.Value) ([]DefinitionInfo, error) {
+	var defs []DefinitionInfo
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate fields: %w", err)
+	}
+
+	for iter.Next() {
+		label := iter.Selector().String()
+		if !strings.HasPrefix(label, "#") {
+			continue
+		}
+
+		def := DefinitionInfo{
+			Name:   label,
+			Kind:   inferType(iter.Value()),
+			Docs:   DocsOf(iter.Value()),
+			Fields: []FieldInfo{},
+		}
+		if _, ok := UnionOf(iter.Value()); ok {
+			def.Kind = "union"
+		}
+		if def.Kind != "struct" {
+			defs = append(defs, def)
+			continue
+		}
+
+		fields, err := iter.Value().Fields(cue.Optional(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate fields of %s: %w", label, err)
+		}
+
+		for fields.Next() {
+			name := strings.TrimRight(fields.Selector().String(), "?!")
+			def.Fields = append(def.Fields, FieldInfo{
+				Name:     name,
+				Type:     inferType(fields.Value()),
+				Optional: fields.IsOptional(),
+				Path:     label + "." + name,
+				Docs:     DocsOf(fields.Value()),
+				Measure:  MeasureOf(fields.Value()),
+			})
+		}
+
+		defs = append(defs, def)
+	}
+
+	return defs, nil
+}
+
 // inferType infers the CUE type as a string
 func inferType(val cue.Value) string {
 	kind := val.IncompleteKind()
@@ -100,7 +173,11 @@ func FormatSchemaInfo(info *SchemaInfo) string {
 			if field.Optional {
 				optional = " (optional)"
 			}
-			fmt.Fprintf(&b, "  %s: %s%s\n", field.Name, field.Type, optional)
+			measure := ""
+			if !field.Measure.IsZero() {
+				measure = " [" + field.Measure.String() + "]"
+			}
+			fmt.Fprintf(&b, "  %s: %s%s%s\n", field.Name, field.Type, optional, measure)
 		}
 	}
 
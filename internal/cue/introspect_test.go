@@ -0,0 +1,52 @@
+package cue
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestIntrospectDefinitionsNonStruct(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Status: "active" | "inactive"
+#Id: string
+#Circle: {kind: "circle", r: number}
+#Square: {kind: "square", side: number}
+#Shape: #Circle | #Square
+#User: {
+	id:      #Id
+	status?: #Status
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	defs, err := IntrospectDefinitions(val)
+	if err != nil {
+		t.Fatalf("IntrospectDefinitions: %v", err)
+	}
+
+	kinds := map[string]string{}
+	fields := map[string]int{}
+	for _, def := range defs {
+		kinds[def.Name] = def.Kind
+		fields[def.Name] = len(def.Fields)
+	}
+	want := map[string]string{
+		"#Status": "string",
+		"#Id":     "string",
+		"#Circle": "struct",
+		"#Square": "struct",
+		"#Shape":  "union",
+		"#User":   "struct",
+	}
+	for name, kind := range want {
+		if kinds[name] != kind {
+			t.Errorf("%s: kind %q, want %q", name, kinds[name], kind)
+		}
+	}
+	if fields["#Status"] != 0 || fields["#User"] != 2 {
+		t.Errorf("fields: %v, want none for #Status and 2 for #User", fields)
+	}
+}
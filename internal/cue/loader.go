@@ -9,11 +9,29 @@ import (
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/load"
+	"cuelang.org/go/mod/modconfig"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
 )
 
+// registry fetches the modules imported by schemas; nil leaves it to CUE,
+// which caches them in CUE_CACHE_DIR
+var registry modconfig.Registry
+
+// SetRegistry sets the registry imported modules are fetched through
+func SetRegistry(r modconfig.Registry) {
+	registry = r
+}
+
+// Registry returns the registry imported modules are fetched through, or
+// nil for CUE's own
+func Registry() modconfig.Registry {
+	return registry
+}
+
 // Loader handles loading CUE files and directories
 type Loader struct {
-	ctx *cue.Context
+	ctx   *cue.Context
+	scope *cue.Value
 }
 
 // NewLoader creates a new CUE loader
@@ -23,6 +41,21 @@ func NewLoader() *Loader {
 	}
 }
 
+// WithScope returns a loader sharing the context of l that resolves
+// references not defined in the loaded files in scope, e.g. the definitions
+// published by other projects of the repository
+func (l *Loader) WithScope(scope cue.Value) *Loader {
+	return &Loader{ctx: l.ctx, scope: &scope}
+}
+
+// compile compiles the contents of a file, with the scope if set
+func (l *Loader) compile(data []byte, path string) cue.Value {
+	if l.scope != nil {
+		return l.ctx.CompileBytes(data, cue.Filename(path), cue.Scope(*l.scope))
+	}
+	return l.ctx.CompileBytes(data, cue.Filename(path))
+}
+
 // LoadFile loads a single CUE file
 func (l *Loader) LoadFile(path string) (cue.Value, error) {
 	data, err := os.ReadFile(path)
@@ -30,7 +63,7 @@ func (l *Loader) LoadFile(path string) (cue.Value, error) {
 		return cue.Value{}, fmt.Errorf("failed to read file %s: %w", path, err)
 	}
 
-	val := l.ctx.CompileBytes(data, cue.Filename(path))
+	val := l.compile(data, path)
 	if err := val.Err(); err != nil {
 		return cue.Value{}, fmt.Errorf("failed to compile %s: %w", path, err)
 	}
@@ -56,7 +89,8 @@ func (l *Loader) LoadDir(dir string) (cue.Value, error) {
 	moduleRoot := findModuleRoot(dir)
 	hasModule := moduleRoot != "" && dirExists(filepath.Join(moduleRoot, "cue.mod"))
 
-	if hasModule {
+	// Module instances cannot be built with a scope
+	if hasModule && l.scope == nil {
 		// Use load.Instances for module-based loading
 		loadPath := dir
 		if !filepath.IsAbs(dir) && !strings.HasPrefix(dir, "./") && !strings.HasPrefix(dir, "../") {
@@ -65,6 +99,12 @@ func (l *Loader) LoadDir(dir string) (cue.Value, error) {
 
 		cfg := &load.Config{
 			ModuleRoot: moduleRoot,
+			Registry:   registry,
+		}
+		// Absolute directories are not package paths; load them as the
+		// current directory instead
+		if filepath.IsAbs(dir) {
+			cfg.Dir, loadPath = dir, "."
 		}
 		buildInstances := load.Instances([]string{loadPath}, cfg)
 		if len(buildInstances) > 0 && buildInstances[0].Err == nil {
@@ -82,19 +122,31 @@ func (l *Loader) LoadDir(dir string) (cue.Value, error) {
 		return cue.Value{}, fmt.Errorf("failed to read directory %s: %w", dir, err)
 	}
 
-	var values []cue.Value
+	var files []string
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".cue") {
 			continue
 		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
 
-		filePath := filepath.Join(dir, entry.Name())
-		data, err := os.ReadFile(filePath)
+	// Read files in parallel; compiling shares the context, so it stays serial
+	contents := make([][]byte, len(files))
+	err = workers.Each(len(files), func(i int) error {
+		data, err := os.ReadFile(files[i])
 		if err != nil {
-			return cue.Value{}, fmt.Errorf("failed to read file %s: %w", filePath, err)
+			return fmt.Errorf("failed to read file %s: %w", files[i], err)
 		}
+		contents[i] = data
+		return nil
+	})
+	if err != nil {
+		return cue.Value{}, err
+	}
 
-		val := l.ctx.CompileBytes(data, cue.Filename(filePath))
+	var values []cue.Value
+	for i, filePath := range files {
+		val := l.compile(contents[i], filePath)
 		if err := val.Err(); err != nil {
 			return cue.Value{}, fmt.Errorf("failed to compile %s: %w", filePath, err)
 		}
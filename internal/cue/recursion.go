@@ -0,0 +1,189 @@
+package cue
+
+import (
+	"sort"
+
+	"cuelang.org/go/cue"
+)
+
+// DefinitionRef returns the top-level definition a value refers to, e.g.
+// #Node, or "" when it refers to none. References inside their own
+// definition evaluate to bottom, so generators look for the reference
+// before the kind of a value.
+func DefinitionRef(val cue.Value) string {
+	_, path := val.ReferencePath()
+	if sels := path.Selectors(); len(sels) == 1 && sels[0].IsDefinition() {
+		return sels[0].String()
+	}
+	return ""
+}
+
+// RefGraph records which definitions of the schemas refer to which, to
+// tell recursive definitions, e.g. #Node: {children: [...#Node]} or
+// #Comment and #Reply referring to each other, from the others
+type RefGraph struct {
+	refs  map[string][]string
+	cycle map[string]int // the cycle of each recursive definition
+}
+
+// NewRefGraph reads the references between definitions, through fields,
+// list items, map values and the branches of disjunctions
+func NewRefGraph(defs map[string]cue.Value) *RefGraph {
+	g := &RefGraph{refs: make(map[string][]string), cycle: make(map[string]int)}
+	for name, val := range defs {
+		found := make(map[string]bool)
+		collectRefs(val, defs, found, 0)
+		for ref := range found {
+			g.refs[name] = append(g.refs[name], ref)
+		}
+		sort.Strings(g.refs[name])
+	}
+	g.findCycles(defs)
+	return g
+}
+
+// collectRefs adds the definitions a value refers to, without following
+// the references
+func collectRefs(val cue.Value, defs map[string]cue.Value, found map[string]bool, depth int) {
+	if depth > 32 {
+		return
+	}
+	if ref := DefinitionRef(val); ref != "" {
+		if _, ok := defs[ref]; ok {
+			found[ref] = true
+			return
+		}
+	}
+	if op, args := val.Expr(); op == cue.OrOp || op == cue.AndOp {
+		for _, arg := range args {
+			collectRefs(arg, defs, found, depth+1)
+		}
+		return
+	}
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			collectRefs(elem, defs, found, depth+1)
+		}
+	case cue.StructKind:
+		iter, err := val.Fields(cue.Optional(true))
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			if !iter.Selector().IsDefinition() {
+				collectRefs(iter.Value(), defs, found, depth+1)
+			}
+		}
+		if pattern := val.LookupPath(cue.MakePath(cue.AnyString)); pattern.Exists() {
+			collectRefs(pattern, defs, found, depth+1)
+		}
+	}
+}
+
+// findCycles finds the strongly connected components of the graph
+// (Tarjan's algorithm); those with more than one definition, or a single
+// one referring to itself, are cycles
+func (g *RefGraph) findCycles(defs map[string]cue.Value) {
+	var names []string
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	next, cycles := 0, 0
+
+	var visit func(name string)
+	visit = func(name string) {
+		index[name], low[name] = next, next
+		next++
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, ref := range g.refs[name] {
+			if _, seen := index[ref]; !seen {
+				visit(ref)
+				low[name] = min(low[name], low[ref])
+			} else if onStack[ref] {
+				low[name] = min(low[name], index[ref])
+			}
+		}
+		if low[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		if len(component) > 1 || g.refersTo(name, name) {
+			cycles++
+			for _, member := range component {
+				g.cycle[member] = cycles
+			}
+		}
+	}
+	for _, name := range names {
+		if _, seen := index[name]; !seen {
+			visit(name)
+		}
+	}
+}
+
+// Refs returns the definitions a definition refers to, in order
+func (g *RefGraph) Refs(name string) []string {
+	return g.refs[name]
+}
+
+// refersTo reports whether a definition refers to another directly
+func (g *RefGraph) refersTo(name, ref string) bool {
+	for _, r := range g.refs[name] {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// Recursive reports whether a definition refers back to itself, directly
+// or through others
+func (g *RefGraph) Recursive(name string) bool {
+	return g.cycle[name] > 0
+}
+
+// SameCycle reports whether two definitions refer to each other, directly
+// or through others, or are the same recursive definition
+func (g *RefGraph) SameCycle(a, b string) bool {
+	return g.cycle[a] > 0 && g.cycle[a] == g.cycle[b]
+}
+
+// Order orders definitions so each follows the definitions it refers to,
+// as far as cycles allow, and otherwise keeps their order
+func (g *RefGraph) Order(names []string) []string {
+	var order []string
+	state := make(map[string]int) // 1 visiting, 2 done
+	var visit func(name string)
+	visit = func(name string) {
+		if state[name] != 0 {
+			return
+		}
+		state[name] = 1
+		for _, ref := range g.refs[name] {
+			visit(ref)
+		}
+		state[name] = 2
+		order = append(order, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return order
+}
@@ -0,0 +1,163 @@
+package cue
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SelectPath selects values from decoded data using a simple path expression
+// such as "items[*].name" or "catalog.items[0]". A [*] segment fans out over
+// every list element (or struct value in key order), so the result may hold
+// any number of values. A missing field is an error.
+func SelectPath(data interface{}, path string) ([]interface{}, error) {
+	return selectPath(data, path, true)
+}
+
+// SelectPresent selects values like SelectPath, skipping values that lack a
+// field of the path, e.g. the list elements without an optional field
+func SelectPresent(data interface{}, path string) ([]interface{}, error) {
+	return selectPath(data, path, false)
+}
+
+// SelectsMany reports whether a path has a [*] segment, so it selects any
+// number of values rather than exactly one
+func SelectsMany(path string) bool {
+	segments, err := parseSelectPath(path)
+	if err != nil {
+		return false
+	}
+	for _, seg := range segments {
+		if seg.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+func selectPath(data interface{}, path string, strict bool) ([]interface{}, error) {
+	segments, err := parseSelectPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	for _, seg := range segments {
+		var next []interface{}
+		for _, item := range current {
+			selected, err := seg.apply(item, strict)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			next = append(next, selected...)
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// selectSegment is a single step of a select path
+type selectSegment struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// apply applies the segment to a decoded value; a missing field is an
+// error when strict, and selects nothing otherwise
+func (s selectSegment) apply(val interface{}, strict bool) ([]interface{}, error) {
+	switch {
+	case s.wildcard:
+		switch v := val.(type) {
+		case []interface{}:
+			return v, nil
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			result := make([]interface{}, 0, len(keys))
+			for _, k := range keys {
+				result = append(result, v[k])
+			}
+			return result, nil
+		default:
+			return nil, fmt.Errorf("[*] applied to non-collection value")
+		}
+
+	case s.isIndex:
+		list, ok := val.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("[%d] applied to non-list value", s.index)
+		}
+		if s.index < 0 || s.index >= len(list) {
+			return nil, fmt.Errorf("index %d out of range (length %d)", s.index, len(list))
+		}
+		return []interface{}{list[s.index]}, nil
+
+	default:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q applied to non-struct value", s.field)
+		}
+		field, ok := obj[s.field]
+		if !ok && strict {
+			return nil, fmt.Errorf("path not found: %s", s.field)
+		}
+		if !ok {
+			return nil, nil
+		}
+		return []interface{}{field}, nil
+	}
+}
+
+// parseSelectPath parses a path expression into segments
+func parseSelectPath(path string) ([]selectSegment, error) {
+	var segments []selectSegment
+
+	path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+	if path == "" {
+		return segments, nil
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		if idx := strings.Index(part, "["); idx >= 0 {
+			name = part[:idx]
+			part = part[idx:]
+		} else {
+			part = ""
+		}
+
+		if name != "" {
+			segments = append(segments, selectSegment{field: name})
+		}
+
+		for part != "" {
+			end := strings.Index(part, "]")
+			if !strings.HasPrefix(part, "[") || end < 0 {
+				return nil, fmt.Errorf("invalid path segment %q", part)
+			}
+
+			inner := part[1:end]
+			part = part[end+1:]
+
+			if inner == "*" {
+				segments = append(segments, selectSegment{wildcard: true})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q", inner)
+			}
+			segments = append(segments, selectSegment{index: index, isIndex: true})
+		}
+	}
+
+	return segments, nil
+}
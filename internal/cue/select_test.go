@@ -0,0 +1,54 @@
+package cue
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectPath(t *testing.T) {
+	data := map[string]interface{}{
+		"catalog": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "note": "x"},
+				map[string]interface{}{"name": "b"},
+			},
+		},
+	}
+
+	values, err := SelectPath(data, "catalog.items[*].name")
+	if err != nil {
+		t.Fatalf("SelectPath: %v", err)
+	}
+	if len(values) != 2 || values[0] != "a" || values[1] != "b" {
+		t.Errorf("SelectPath = %v, want [a b]", values)
+	}
+
+	for _, path := range []string{"does.not.exist", "catalog.missing", "catalog.items[*].note"} {
+		if _, err := SelectPath(data, path); err == nil || !strings.Contains(err.Error(), "path not found") {
+			t.Errorf("SelectPath(%q) error = %v, want path not found", path, err)
+		}
+	}
+
+	values, err = SelectPresent(data, "catalog.items[*].note")
+	if err != nil {
+		t.Fatalf("SelectPresent: %v", err)
+	}
+	if len(values) != 1 || values[0] != "x" {
+		t.Errorf("SelectPresent = %v, want [x]", values)
+	}
+}
+
+func TestSelectsMany(t *testing.T) {
+	tests := map[string]bool{
+		"":                 false,
+		"catalog.items[0]": false,
+		"catalog.items[*]": true,
+		"items[*].name":    true,
+		"catalog.items[0":  false,
+	}
+	for path, want := range tests {
+		if got := SelectsMany(path); got != want {
+			t.Errorf("SelectsMany(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
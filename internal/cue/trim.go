@@ -0,0 +1,74 @@
+package cue
+
+import (
+	"fmt"
+	"os"
+
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/tools/trim"
+)
+
+// TrimResult holds the original and trimmed source of a single file
+type TrimResult struct {
+	File     string
+	Original []byte
+	Trimmed  []byte
+
+	// formatted is the original formatted like the trimmed source
+	formatted []byte
+}
+
+// Changed reports whether trimming removed anything from the file.
+// Formatting alone, which writing the trimmed source also applies, is not
+// a change.
+func (r TrimResult) Changed() bool {
+	return string(r.formatted) != string(r.Trimmed)
+}
+
+// Trim removes fields implied by definitions and defaults from the CUE package
+// in dir. Files are not modified; the trimmed source is returned instead.
+func (l *Loader) Trim(dir string) ([]TrimResult, error) {
+	buildInstances := load.Instances([]string{"."}, &load.Config{Dir: dir})
+	if len(buildInstances) == 0 {
+		return nil, fmt.Errorf("no CUE package found in %s", dir)
+	}
+
+	inst := buildInstances[0]
+	if inst.Err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", dir, inst.Err)
+	}
+
+	val := l.ctx.BuildInstance(inst)
+	if err := val.Err(); err != nil {
+		return nil, fmt.Errorf("failed to build %s: %w", dir, err)
+	}
+
+	// Read and format originals before trim mutates the ASTs
+	var results []TrimResult
+	for _, f := range inst.Files {
+		original, err := os.ReadFile(f.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", f.Filename, err)
+		}
+		formatted, err := format.Node(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", f.Filename, err)
+		}
+		results = append(results, TrimResult{File: f.Filename, Original: original, formatted: formatted})
+	}
+
+	if err := trim.Files(inst.Files, val, &trim.Config{}); err != nil {
+		return nil, fmt.Errorf("failed to trim %s: %w", dir, err)
+	}
+
+	for i, f := range inst.Files {
+		trimmed, err := format.Node(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", f.Filename, err)
+		}
+		results[i].Trimmed = trimmed
+	}
+
+	return results, nil
+}
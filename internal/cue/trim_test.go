@@ -0,0 +1,33 @@
+package cue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrimIgnoresFormatting(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Badly aligned, but nothing to trim
+		"formatted.cue": "package schemas\n\n#User: {\n\tname: string\n\trole:     *\"member\" | \"admin\"\n}\n",
+		// The role is implied by the definition's default
+		"data.cue": "package schemas\n\nuser: #User & {\n\tname: \"Ada\"\n\trole: \"member\"\n}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := NewLoader().Trim(dir)
+	if err != nil {
+		t.Fatalf("Trim: %v", err)
+	}
+	for _, r := range results {
+		want := filepath.Base(r.File) == "data.cue"
+		if r.Changed() != want {
+			t.Errorf("%s: Changed() = %v, want %v\n%s", filepath.Base(r.File), r.Changed(), want, r.Trimmed)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package cue
+
+import (
+	"cuelang.org/go/cue"
+)
+
+// Union is a disjunction of structs, e.g. #OrderCreated | #OrderCancelled
+type Union struct {
+	// Members are the branches: references to definitions or inline structs
+	Members []cue.Value
+
+	// Discriminator is the field every member requires with a string
+	// literal of its own, e.g. kind: "order.created"; empty when the
+	// members share no such field
+	Discriminator string
+}
+
+// UnionOf returns the union of a disjunction whose branches are all
+// structs. Disjunctions with a default, e.g. *#A | #B, are not unions.
+func UnionOf(val cue.Value) (Union, bool) {
+	op, args := val.Expr()
+	if op != cue.OrOp || len(args) < 2 {
+		return Union{}, false
+	}
+	for _, arg := range args {
+		if arg.IncompleteKind() != cue.StructKind {
+			return Union{}, false
+		}
+	}
+	return Union{Members: args, Discriminator: discriminator(args)}, true
+}
+
+// IsUnion reports whether a value is a union of structs, see UnionOf, or
+// refers to a definition that is one
+func IsUnion(val cue.Value) bool {
+	if _, ok := UnionOf(val); ok {
+		return true
+	}
+	if DefinitionRef(val) == "" {
+		return false
+	}
+	_, ok := UnionOf(cue.Dereference(val))
+	return ok
+}
+
+// discriminator returns the first field of the first member that every
+// member requires with a distinct string literal
+func discriminator(members []cue.Value) string {
+	iter, err := members[0].Fields()
+	if err != nil {
+		return ""
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		seen := make(map[string]bool, len(members))
+		for _, m := range members {
+			tag, ok := literalField(m, name)
+			if !ok || seen[tag] {
+				seen = nil
+				break
+			}
+			seen[tag] = true
+		}
+		if seen != nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// literalField returns the string literal of a required field of a struct
+func literalField(val cue.Value, name string) (string, bool) {
+	field := val.LookupPath(cue.MakePath(cue.Str(name)))
+	if !field.Exists() || !field.IsConcrete() || field.Kind() != cue.StringKind {
+		return "", false
+	}
+	s, err := field.String()
+	return s, err == nil
+}
+
+// StripNull removes a null branch from a disjunction, e.g. #Node in
+// #Node | null, reporting whether there was one
+func StripNull(val cue.Value) (cue.Value, bool) {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return val, false
+	}
+
+	var rest []cue.Value
+	nullable := false
+	for _, arg := range args {
+		if arg.IncompleteKind() == cue.NullKind {
+			nullable = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	if !nullable || len(rest) != 1 {
+		return val, false
+	}
+	return rest[0], true
+}
+
+// Alternatives returns the branches of a disjunction of different kinds or
+// definitions, without null branches, and whether there was a null branch.
+// Branches of one kind, e.g. string | *"draft", are checked as that kind.
+// Literals of different kinds, e.g. "auto" | 0, are branches too, so
+// generators look for enums first.
+func Alternatives(val cue.Value) ([]cue.Value, bool) {
+	return alternatives(val, func(arg cue.Value) bool {
+		_, path := arg.ReferencePath()
+		return len(path.Selectors()) > 0
+	}, cue.Value.IncompleteKind)
+}
+
+// KindAlternatives returns the branches of a disjunction like Alternatives,
+// for targets that type a value by its kind: numbers are one kind and only
+// struct definitions are branches of their own, so #Role | *"member" and
+// float | *1 are typed as that kind
+func KindAlternatives(val cue.Value) ([]cue.Value, bool) {
+	return alternatives(val, func(arg cue.Value) bool {
+		_, path := arg.ReferencePath()
+		return len(path.Selectors()) > 0 && arg.IncompleteKind() == cue.StructKind
+	}, func(arg cue.Value) cue.Kind {
+		kind := arg.IncompleteKind()
+		if kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0 {
+			return cue.NumberKind
+		}
+		return kind
+	})
+}
+
+// alternatives returns the branches of a disjunction without null
+// branches when a branch is distinct or the kinds of the branches differ
+func alternatives(val cue.Value, distinct func(cue.Value) bool, kindOf func(cue.Value) cue.Kind) ([]cue.Value, bool) {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil, false
+	}
+	var rest []cue.Value
+	nullable := false
+	differ := false
+	for _, arg := range args {
+		if arg.IncompleteKind() == cue.NullKind {
+			nullable = true
+			continue
+		}
+		if distinct(arg) || len(rest) > 0 && kindOf(arg) != kindOf(rest[0]) {
+			differ = true
+		}
+		rest = append(rest, arg)
+	}
+	if len(rest) < 2 || !differ {
+		return nil, false
+	}
+	return rest, nullable
+}
@@ -0,0 +1,146 @@
+package cue
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// Measure holds the unit-of-measure and currency annotations of a field,
+// declared with @unit("ms") and @currency("ISO4217")
+type Measure struct {
+	Unit     string `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Currency string `json:"currency,omitempty" yaml:"currency,omitempty"`
+}
+
+// MeasureOf reads the @unit and @currency attributes of a value
+func MeasureOf(val cue.Value) Measure {
+	var m Measure
+	if attr, ok := GetAttr(val, "unit"); ok {
+		m.Unit = attr.Arg(0)
+	}
+	if attr, ok := GetAttr(val, "currency"); ok {
+		m.Currency = attr.Arg(0)
+	}
+	return m
+}
+
+// IsZero reports whether no unit or currency is declared
+func (m Measure) IsZero() bool {
+	return m.Unit == "" && m.Currency == ""
+}
+
+// String formats the measure for generated comments, e.g. "unit: ms"
+func (m Measure) String() string {
+	var parts []string
+	if m.Unit != "" {
+		parts = append(parts, "unit: "+m.Unit)
+	}
+	if m.Currency != "" {
+		parts = append(parts, "currency: "+m.Currency)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Codes of the @unit and @currency conventions
+const (
+	MeasureNotNumeric  = "PSL1001"
+	CurrencyNotInteger = "PSL1002"
+	CurrencyNaming     = "PSL1003"
+)
+
+// MeasureCategory groups the codes of the @unit and @currency conventions
+const MeasureCategory = "measures"
+
+// MeasureRules names the codes of the @unit and @currency conventions
+var MeasureRules = map[string]string{
+	MeasureNotNumeric:  "measure-not-numeric",
+	CurrencyNotInteger: "currency-not-integer",
+	CurrencyNaming:     "currency-naming",
+}
+
+// LintMeasures checks the @unit and @currency conventions of all definitions:
+//
+//   - @unit and @currency only apply to numeric fields
+//   - currency amounts are integer minor units, so @currency fields must be
+//     integers named *_cents (or *Cents)
+func LintMeasures(val cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			lintMeasureFields(iter.Value(), iter.Selector().String(), 0, &errs)
+		}
+	}
+
+	return errs
+}
+
+// lintMeasureFields checks the fields of a struct value recursively
+func lintMeasureFields(val cue.Value, prefix string, depth int, errs *[]ValidationError) {
+	if depth > 8 {
+		return
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		path := prefix + "." + name
+		field := iter.Value()
+		kind := field.IncompleteKind()
+
+		if kind == cue.StructKind {
+			lintMeasureFields(field, path, depth+1, errs)
+			continue
+		}
+
+		m := MeasureOf(field)
+		if m.IsZero() {
+			continue
+		}
+
+		report := func(code, msg, suggestion string) {
+			pos := field.Pos()
+			*errs = append(*errs, ValidationError{
+				Code:       code,
+				File:       pos.Filename(),
+				Line:       pos.Line(),
+				Column:     pos.Column(),
+				Path:       path,
+				Message:    fmt.Sprintf("%s: %s", path, msg),
+				Suggestion: suggestion,
+			})
+		}
+
+		if kind&cue.NumberKind == 0 || kind&^cue.NumberKind != 0 {
+			report(MeasureNotNumeric, "@unit and @currency require a numeric field", "Remove the attribute or change the field type to int or number")
+			continue
+		}
+
+		if m.Currency == "" {
+			continue
+		}
+
+		if kind != cue.IntKind {
+			report(CurrencyNotInteger, "currency amounts must be integers in minor units", fmt.Sprintf("Use an int field such as %s_cents: int @currency(%q)", strings.TrimSuffix(name, "_cents"), m.Currency))
+			continue
+		}
+
+		if !strings.HasSuffix(name, "_cents") && !strings.HasSuffix(name, "Cents") {
+			report(CurrencyNaming, "integer currency fields must end in _cents", fmt.Sprintf("Rename the field to %s_cents", name))
+		}
+	}
+}
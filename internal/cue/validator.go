@@ -6,6 +6,7 @@ import (
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 )
 
 // ValidationResult holds the result of a validation
@@ -16,6 +17,9 @@ type ValidationResult struct {
 
 // ValidationError represents a single validation error with context
 type ValidationError struct {
+	// Code identifies the convention checks, e.g. PSL1001; CUE errors
+	// have none
+	Code       string
 	File       string
 	Line       int
 	Column     int
@@ -55,6 +59,12 @@ func (v *Validator) Validate(val cue.Value) *ValidationResult {
 		return result
 	}
 
+	// Check attribute conventions
+	if lintErrors := LintMeasures(val); len(lintErrors) > 0 {
+		result.Valid = false
+		result.Errors = lintErrors
+	}
+
 	return result
 }
 
@@ -64,7 +74,7 @@ func (v *Validator) parseErrors(err error) []ValidationError {
 
 	// Use CUE's error formatting
 	for _, e := range errors.Errors(err) {
-		pos := e.Position()
+		pos := ErrorPosition(e)
 
 		validationErrors = append(validationErrors, ValidationError{
 			File:       pos.Filename(),
@@ -79,6 +89,21 @@ func (v *Validator) parseErrors(err error) []ValidationError {
 	return validationErrors
 }
 
+// ErrorPosition returns where a CUE error occurred. Conflicts between values
+// often have no position of their own, so fall back to the first of the
+// values involved.
+func ErrorPosition(err errors.Error) token.Pos {
+	if pos := err.Position(); pos.IsValid() {
+		return pos
+	}
+	for _, pos := range err.InputPositions() {
+		if pos.IsValid() {
+			return pos
+		}
+	}
+	return token.NoPos
+}
+
 // extractPath extracts the field path from an error
 func extractPath(err errors.Error) string {
 	// Try to extract path from error message
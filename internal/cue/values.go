@@ -0,0 +1,75 @@
+package cue
+
+import (
+	"cuelang.org/go/cue"
+)
+
+// HasFields reports whether a struct declares regular fields
+func HasFields(val cue.Value) bool {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return false
+	}
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMap reports whether a struct value is a pattern-only map, e.g.
+// {[string]: int}
+func IsMap(val cue.Value) bool {
+	return val.LookupPath(cue.MakePath(cue.AnyString)).Exists() && !HasFields(val)
+}
+
+// TypedConjunct returns the conjunct of a conjunction, nested ones
+// included, that has a kind of its own, e.g. [...string] in [...string] &
+// list.MinItems(1) & list.MaxItems(5). Builtin validators such as
+// list.MinItems can make the kind of the whole conjunction unknown.
+func TypedConjunct(val cue.Value) (cue.Value, bool) {
+	op, args := val.Expr()
+	if op != cue.AndOp {
+		return cue.Value{}, false
+	}
+	for _, arg := range args {
+		argOp, _ := arg.Expr()
+		if argOp == cue.AndOp {
+			if t, ok := TypedConjunct(arg); ok {
+				return t, true
+			}
+		} else if argOp == cue.NoOp && arg.IncompleteKind() != cue.BottomKind {
+			return arg, true
+		}
+	}
+	return cue.Value{}, false
+}
+
+// TypedKind returns the kind of a value and the value that has it: the
+// typed conjunct, see TypedConjunct, when builtin validators make the kind
+// of the whole conjunction unknown, and the value itself otherwise
+func TypedKind(val cue.Value) (cue.Value, cue.Kind) {
+	kind := val.IncompleteKind()
+	if kind == cue.BottomKind {
+		if t, ok := TypedConjunct(val); ok {
+			return t, t.IncompleteKind()
+		}
+	}
+	return val, kind
+}
+
+// ScalarDefault renders the default of a string, number or boolean field
+// as JSON, e.g. "member" for *"member" | "admin" and 1.5 for float | *1.5.
+// Concrete fields and open lists, which default to [], have none.
+func ScalarDefault(val cue.Value) (string, bool) {
+	def, ok := val.Default()
+	if !ok || val.IsConcrete() || !def.IsConcrete() || def.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) == 0 {
+		return "", false
+	}
+	data, err := def.MarshalJSON()
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,92 @@
+package cue
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestValueHelpers(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+import "list"
+
+#Address: {city: string}
+tags:    [...string] & list.MinItems(1) & list.MaxItems(5)
+next:    #Address | null
+meta:    {[string]: int}
+value:   string | int
+ratio:   float | *1
+mixed:   #Address | string
+address: #Address
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+	field := func(name string) cue.Value { return val.LookupPath(cue.ParsePath(name)) }
+
+	if typed, ok := TypedConjunct(field("tags")); !ok || typed.IncompleteKind() != cue.ListKind {
+		t.Errorf("TypedConjunct(tags) = %v, %v, want the list", typed, ok)
+	}
+	if _, kind := TypedKind(field("tags")); kind != cue.ListKind {
+		t.Errorf("TypedKind(tags) = %v, want list", kind)
+	}
+	if _, kind := TypedKind(field("ratio")); kind != cue.NumberKind {
+		t.Errorf("TypedKind(ratio) = %v, want the kind of the value", kind)
+	}
+	if rest, ok := StripNull(field("next")); !ok || DefinitionRef(rest) != "#Address" {
+		t.Errorf("StripNull(next) = %v, %v, want #Address", rest, ok)
+	}
+	if !IsMap(field("meta")) || IsMap(field("address")) || !HasFields(field("address")) {
+		t.Errorf("IsMap/HasFields: a pattern-only struct is a map, a struct with fields is not")
+	}
+
+	tests := []struct {
+		name        string
+		alts, kinds int
+	}{
+		{"value", 2, 2},
+		{"ratio", 2, 0},
+		{"mixed", 2, 2},
+		{"next", 0, 0},
+	}
+	for _, tt := range tests {
+		alts, _ := Alternatives(field(tt.name))
+		kinds, _ := KindAlternatives(field(tt.name))
+		if len(alts) != tt.alts || len(kinds) != tt.kinds {
+			t.Errorf("%s: %d alternatives and %d kind alternatives, want %d and %d",
+				tt.name, len(alts), len(kinds), tt.alts, tt.kinds)
+		}
+	}
+}
+
+func TestScalarDefault(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+flag:  bool | *false
+score: float | *1.5
+count: *1 | int
+role:  *"member" | "admin"
+name:  string
+tags:  [...string]
+fixed: "x"
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"flag":  "false",
+		"score": "1.5",
+		"count": "1",
+		"role":  `"member"`,
+		"name":  "",
+		"tags":  "",
+		"fixed": "",
+	}
+	for name, want := range tests {
+		got, ok := ScalarDefault(val.LookupPath(cue.ParsePath(name)))
+		if got != want || ok != (want != "") {
+			t.Errorf("ScalarDefault(%s) = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+}
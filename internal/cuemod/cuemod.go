@@ -0,0 +1,570 @@
+// Package cuemod creates and maintains the cue.mod/module.cue of a project:
+// its module path, language version and the modules it requires, kept in
+// sync with the imports of platosl.yaml and of the schemas.
+package cuemod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/module"
+)
+
+// ErrNoModule is returned for a project without cue.mod/module.cue
+var ErrNoModule = errors.New("no CUE module found (cue.mod/module.cue); run 'platosl mod init <module>' to create one")
+
+// ErrExists is returned by Init when the project already is a module
+var ErrExists = errors.New("cue.mod/module.cue already exists")
+
+// Path returns the path of the module file of a project
+func Path(root string) string {
+	return filepath.Join(root, "cue.mod", "module.cue")
+}
+
+// Init creates cue.mod/module.cue declaring a module at the language
+// version of this CLI. A module path without a major version gets @v0.
+func Init(root, mpath string) (*modfile.File, error) {
+	if _, err := os.Stat(Path(root)); err == nil {
+		return nil, ErrExists
+	}
+	if !strings.Contains(mpath, "@") {
+		mpath += "@v0"
+	}
+	if err := module.CheckPath(mpath); err != nil {
+		return nil, fmt.Errorf("invalid module path: %w", err)
+	}
+	f := &modfile.File{
+		Module:   mpath,
+		Language: &modfile.Language{Version: cue.LanguageVersion()},
+	}
+	if err := Save(root, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Load reads and parses cue.mod/module.cue
+func Load(root string) (*modfile.File, error) {
+	path := Path(root)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoModule
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	f, err := modfile.Parse(data, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Save writes cue.mod/module.cue in canonical form
+func Save(root string, f *modfile.File) error {
+	data, err := modfile.Format(f)
+	if err != nil {
+		return fmt.Errorf("failed to format module file: %w", err)
+	}
+	path := Path(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cue.mod: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Project is what the requirements of a module are derived from
+type Project struct {
+	// Root is the directory holding cue.mod
+	Root string
+
+	// Dirs are the schema directories, whose .cue files are scanned for
+	// imports
+	Dirs []string
+
+	// Imports are the imports of platosl.yaml: module paths with a full
+	// version (pinned), a major version or none, and local paths, which
+	// are skipped
+	Imports []string
+
+	// Aliases are the imports of platosl.yaml with an alias, which the
+	// re-export layers of the schema directories bind
+	Aliases []Alias
+
+	// Policies are the update policies of the imports of platosl.yaml
+	// (none, patch, minor or major), keyed by module path without major
+	// version; imports without one follow minor
+	Policies map[string]string
+}
+
+// Change is a requirement added, removed or moved to another version
+type Change struct {
+	Module string `json:"module"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("add %s %s", c.Module, c.New)
+	case c.New == "":
+		return fmt.Sprintf("remove %s %s", c.Module, c.Old)
+	case compareVersions(c.New, c.Old) > 0:
+		return fmt.Sprintf("upgrade %s %s => %s", c.Module, c.Old, c.New)
+	default:
+		return fmt.Sprintf("downgrade %s %s => %s", c.Module, c.Old, c.New)
+	}
+}
+
+// Problem is a difference between module.cue and the project
+type Problem struct {
+	Module  string `json:"module,omitempty"`
+	Message string `json:"message"`
+}
+
+// Tidy returns the module file with the requirements of the project: the
+// modules of platosl.yaml and of the schema imports, and the modules they
+// require in turn, each at the highest version any of them requires.
+// Versions already required are kept unless platosl.yaml pins another;
+// new modules get their latest release from the registry. f is not
+// modified.
+func Tidy(ctx context.Context, reg modconfig.Registry, p Project, f *modfile.File) (*modfile.File, []Change, error) {
+	t := &tidier{ctx: ctx, reg: reg, project: p, file: f, resolve: true}
+	build, err := t.buildList()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tidied := *f
+	if tidied.Language == nil {
+		tidied.Language = &modfile.Language{Version: cue.LanguageVersion()}
+	}
+	tidied.Deps = make(map[string]*modfile.Dep)
+	for mpath, version := range build {
+		dep := &modfile.Dep{Version: version}
+		if old, ok := f.Deps[mpath]; ok {
+			dep.Default = old.Default
+		}
+		tidied.Deps[mpath] = dep
+	}
+	return &tidied, diff(f.Deps, build), nil
+}
+
+// Verify reports the differences between module.cue and the project: the
+// checks of Tidy without looking up modules module.cue does not mention,
+// plus the module path and language version
+func Verify(ctx context.Context, reg modconfig.Registry, p Project, f *modfile.File) ([]Problem, error) {
+	t := &tidier{ctx: ctx, reg: reg, project: p, file: f}
+	var problems []Problem
+	if err := module.CheckPath(f.QualifiedModule()); err != nil {
+		problems = append(problems, Problem{Message: fmt.Sprintf("invalid module path %q: %v", f.Module, err)})
+	}
+	switch {
+	case f.Language == nil || f.Language.Version == "":
+		problems = append(problems, Problem{Message: "no language version"})
+	case compareVersions(f.Language.Version, cue.LanguageVersion()) > 0:
+		problems = append(problems, Problem{Message: fmt.Sprintf("language version %s is newer than %s, the version this CLI supports", f.Language.Version, cue.LanguageVersion())})
+	}
+
+	build, err := t.buildList()
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, t.problems...)
+	layers, err := Layers(p.Dirs, p.Aliases)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range layers {
+		rel, err := filepath.Rel(p.Root, c.Path)
+		if err != nil {
+			rel = c.Path
+		}
+		if c.Content == nil {
+			problems = append(problems, Problem{Message: fmt.Sprintf("%s re-exports imports platosl.yaml no longer aliases", rel)})
+		} else {
+			problems = append(problems, Problem{Message: fmt.Sprintf("%s does not match the import aliases of platosl.yaml", rel)})
+		}
+	}
+	for _, c := range diff(f.Deps, build) {
+		switch {
+		case c.Old == "":
+			problems = append(problems, Problem{Module: c.Module, Message: fmt.Sprintf("%s %s is needed but not required", c.Module, c.New)})
+		case c.New == "":
+			problems = append(problems, Problem{Module: c.Module, Message: fmt.Sprintf("%s %s is required but not needed", c.Module, c.Old)})
+		default:
+			problems = append(problems, Problem{Module: c.Module, Message: fmt.Sprintf("%s is required at %s, but %s is needed", c.Module, c.Old, c.New)})
+		}
+	}
+	return problems, nil
+}
+
+// tidier derives the build list of a project
+type tidier struct {
+	ctx     context.Context
+	reg     modconfig.Registry
+	project Project
+	file    *modfile.File
+
+	// resolve allows looking up modules module.cue does not mention in
+	// the registry; without it they are problems
+	resolve  bool
+	problems []Problem
+}
+
+// wanted is a module the project imports directly
+type wanted struct {
+	base   string
+	major  string // empty when any major version will do
+	pinned string // version pinned in platosl.yaml
+	source string
+}
+
+// buildList returns the selected version of every module required,
+// directly or not, keyed by module path with its major version
+func (t *tidier) buildList() (map[string]string, error) {
+	direct, err := t.direct()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]string)
+	var queue []module.Version
+	require := func(mv module.Version) {
+		if old, ok := selected[mv.Path()]; ok && compareVersions(old, mv.Version()) >= 0 {
+			return
+		}
+		selected[mv.Path()] = mv.Version()
+		queue = append(queue, mv)
+	}
+	for _, w := range direct {
+		version, err := t.version(w)
+		if err != nil {
+			return nil, err
+		}
+		if version == "" {
+			continue
+		}
+		mv, err := module.NewVersion(w.base, version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", w.source, err)
+		}
+		require(mv)
+	}
+
+	// Minimal version selection over the requirements of the selected
+	// modules
+	for len(queue) > 0 {
+		mv := queue[0]
+		queue = queue[1:]
+		if selected[mv.Path()] != mv.Version() {
+			continue
+		}
+		reqs, err := t.reg.Requirements(t.ctx, mv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the requirements of %s: %w", mv, err)
+		}
+		for _, req := range reqs {
+			require(req)
+		}
+	}
+	return selected, nil
+}
+
+// direct collects the modules imported by platosl.yaml and the schemas
+func (t *tidier) direct() ([]*wanted, error) {
+	var modules []*wanted
+	find := func(base, major string) *wanted {
+		for _, w := range modules {
+			if w.base == base && (w.major == major || w.major == "" || major == "") {
+				if w.major == "" {
+					w.major = major
+				}
+				return w
+			}
+		}
+		return nil
+	}
+
+	for _, imp := range t.project.Imports {
+		if isLocal(imp) {
+			continue
+		}
+		base, version, _ := strings.Cut(imp, "@")
+		w := &wanted{base: base, source: "platosl.yaml import " + imp}
+		switch {
+		case version == "":
+		case majorPattern.MatchString(version):
+			w.major = version
+		default:
+			if _, err := module.NewVersion(base, version); err != nil {
+				return nil, fmt.Errorf("invalid import %q in platosl.yaml: %w", imp, err)
+			}
+			w.major, w.pinned = majorOf(version), version
+		}
+		if existing := find(w.base, w.major); existing != nil {
+			if w.pinned != "" {
+				existing.pinned = w.pinned
+			}
+			continue
+		}
+		modules = append(modules, w)
+	}
+
+	imports, err := t.imports()
+	if err != nil {
+		return nil, err
+	}
+	own := t.ownModule()
+	for _, imp := range imports {
+		ip := ast.ParseImportPath(imp.path)
+		if isStandard(ip.Path) || within(ip.Path, own) {
+			continue
+		}
+		base, major := t.moduleOf(ip, modules)
+		if base == "" {
+			if !t.resolve {
+				t.problems = append(t.problems, Problem{Message: fmt.Sprintf("no required module provides package %s, imported by %s", imp.path, imp.file)})
+				continue
+			}
+			if base, major, err = t.lookup(ip); err != nil {
+				return nil, fmt.Errorf("%s, imported by %s: %w", imp.path, imp.file, err)
+			}
+		}
+		if find(base, major) == nil {
+			modules = append(modules, &wanted{base: base, major: major, source: imp.file})
+		}
+	}
+	return modules, nil
+}
+
+// version returns the version a directly imported module should have: the
+// pinned one, the one already required, or the latest release
+func (t *tidier) version(w *wanted) (string, error) {
+	if w.pinned != "" {
+		return w.pinned, nil
+	}
+	for mpath, dep := range t.file.Deps {
+		base, major, _ := ast.SplitPackageVersion(mpath)
+		if base == w.base && (w.major == "" || w.major == major) {
+			return dep.Version, nil
+		}
+	}
+	if !t.resolve {
+		// Reported as missing by the diff of the build list
+		major := w.major
+		if major == "" {
+			major = "v0"
+		}
+		t.problems = append(t.problems, Problem{Module: w.base + "@" + major, Message: fmt.Sprintf("%s is imported by %s but not required", w.base, w.source)})
+		return "", nil
+	}
+	return t.latest(w.base, w.major)
+}
+
+// moduleOf returns the module of an import among the modules already known:
+// the longest base path containing the import, of its major version if it
+// has one
+func (t *tidier) moduleOf(ip ast.ImportPath, modules []*wanted) (string, string) {
+	var candidates []struct{ base, major string }
+	for mpath := range t.file.Deps {
+		base, major, _ := ast.SplitPackageVersion(mpath)
+		candidates = append(candidates, struct{ base, major string }{base, major})
+	}
+	for _, w := range modules {
+		candidates = append(candidates, struct{ base, major string }{w.base, w.major})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return len(candidates[i].base) > len(candidates[j].base) })
+	for _, c := range candidates {
+		if within(ip.Path, c.base) && (ip.Version == "" || c.major == "" || ip.Version == c.major) {
+			if ip.Version != "" {
+				return c.base, ip.Version
+			}
+			return c.base, c.major
+		}
+	}
+	return "", ""
+}
+
+// lookup finds the module of an import in the registry, trying the import
+// path and then its parents
+func (t *tidier) lookup(ip ast.ImportPath) (string, string, error) {
+	for base := ip.Path; base != ""; base = parentPath(base) {
+		query := base
+		if ip.Version != "" {
+			query += "@" + ip.Version
+		}
+		versions, err := t.reg.ModuleVersions(t.ctx, query)
+		if err != nil {
+			return "", "", err
+		}
+		if len(versions) > 0 {
+			return base, majorOf(latestOf(versions)), nil
+		}
+	}
+	return "", "", fmt.Errorf("no module in the registry provides the package")
+}
+
+// latest returns the latest release of a module, of a major version if
+// given
+func (t *tidier) latest(base, major string) (string, error) {
+	query := base
+	if major != "" {
+		query += "@" + major
+	}
+	versions, err := t.reg.ModuleVersions(t.ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the versions of %s: %w", query, err)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no versions of %s found in the registry", query)
+	}
+	return latestOf(versions), nil
+}
+
+// importSpec is an import of a schema file
+type importSpec struct {
+	path string
+	file string
+}
+
+// imports returns the imports of the .cue files under the schema
+// directories, skipping cue.mod and hidden directories, in a stable order
+func (t *tidier) imports() ([]importSpec, error) {
+	var specs []importSpec
+	seen := make(map[string]bool)
+	for _, dir := range t.project.Dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != dir && (strings.HasPrefix(info.Name(), ".") || info.Name() == "cue.mod") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".cue") {
+				return nil
+			}
+			f, err := parser.ParseFile(path, nil, parser.ImportsOnly)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			rel, err := filepath.Rel(t.project.Root, path)
+			if err != nil {
+				rel = path
+			}
+			for _, spec := range f.Imports {
+				importPath := strings.Trim(spec.Path.Value, `"`)
+				if !seen[importPath] {
+					seen[importPath] = true
+					specs = append(specs, importSpec{path: importPath, file: filepath.ToSlash(rel)})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].path < specs[j].path })
+	return specs, nil
+}
+
+// ownModule returns the module path of the project without its major
+// version
+func (t *tidier) ownModule() string {
+	base, _, _ := ast.SplitPackageVersion(t.file.QualifiedModule())
+	return base
+}
+
+// diff lists the changes from the requirements of a module file to a
+// build list, sorted by module
+func diff(deps map[string]*modfile.Dep, build map[string]string) []Change {
+	var changes []Change
+	for mpath, dep := range deps {
+		if version, ok := build[mpath]; !ok {
+			changes = append(changes, Change{Module: mpath, Old: dep.Version})
+		} else if version != dep.Version {
+			changes = append(changes, Change{Module: mpath, Old: dep.Version, New: version})
+		}
+	}
+	for mpath, version := range build {
+		if _, ok := deps[mpath]; !ok {
+			changes = append(changes, Change{Module: mpath, New: version})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Module < changes[j].Module })
+	return changes
+}
+
+// majorPattern matches a major version, e.g. v1
+var majorPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// majorOf returns the major version of a version, e.g. v1 for v1.2.3
+func majorOf(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// latestOf returns the latest release among versions in semver order,
+// or the latest pre-release when there is no release
+func latestOf(versions []string) string {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !strings.Contains(versions[i], "-") {
+			return versions[i]
+		}
+	}
+	return versions[len(versions)-1]
+}
+
+// compareVersions compares two semantic versions
+func compareVersions(a, b string) int {
+	va, errA := module.NewVersion("example.com/m", a)
+	vb, errB := module.NewVersion("example.com/m", b)
+	if errA != nil || errB != nil || va.Path() != vb.Path() {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+// isLocal reports whether an import of platosl.yaml is a local path
+func isLocal(imp string) bool {
+	return strings.HasPrefix(imp, ".") || filepath.IsAbs(imp)
+}
+
+// isStandard reports whether an import path is of the standard library,
+// whose first element has no dot
+func isStandard(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// within reports whether an import path is base or below it
+func within(importPath, base string) bool {
+	return base != "" && (importPath == base || strings.HasPrefix(importPath, base+"/"))
+}
+
+// parentPath drops the last element of a path
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package cuemod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+)
+
+// LayerFile is the re-export layer written into each schema directory
+const LayerFile = "platosl_imports.cue"
+
+// layerHeader starts the re-export layer, marking it as generated
+const layerHeader = "// Generated by PlatoSL\n// DO NOT EDIT - This file is auto-generated from the imports of platosl.yaml\n\n"
+
+// Alias is an import of platosl.yaml with an alias
+type Alias struct {
+	// Name is the alias, e.g. addr; the layer declares _addr
+	Name string
+
+	// Path is the import as platosl.yaml gives it, e.g.
+	// example.com/base/address@v1.2.0
+	Path string
+}
+
+// Layer returns the re-export layer of a schema package: it imports every
+// aliased package and binds it to a hidden field, so the files of the
+// package refer to example.com/base/address@v1 as _addr.#Address without
+// importing it. Swapping or upgrading the package then only changes the
+// layer. Pinned versions are cut to their major version, the only version
+// an import path takes.
+func Layer(pkg string, aliases []Alias) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString(layerHeader)
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", pkg)
+	for _, a := range aliases {
+		if isLocal(a.Path) {
+			return nil, fmt.Errorf("local import %s cannot have an alias", a.Path)
+		}
+		importPath := a.Path
+		if base, version, ok := strings.Cut(a.Path, "@"); ok {
+			importPath = base + "@" + majorOf(version)
+		}
+		fmt.Fprintf(&b, "\t%s %s\n", a.Name, strconv.Quote(importPath))
+	}
+	b.WriteString(")\n")
+	for _, a := range aliases {
+		fmt.Fprintf(&b, "\n// _%s re-exports %s\n_%s: %s\n", a.Name, a.Path, a.Name, a.Name)
+	}
+
+	out, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", LayerFile, err)
+	}
+	return out, nil
+}
+
+// LayerChange is a re-export layer written or removed
+type LayerChange struct {
+	Path    string
+	Content []byte // nil when the layer is removed
+}
+
+// Layers returns the changes that bring the re-export layers of the schema
+// directories in line with the aliases: each directory holding a CUE
+// package gets a layer, and without aliases existing layers are removed.
+// Dirs may also be schema files, whose directory is used.
+func Layers(dirs []string, aliases []Alias) ([]LayerChange, error) {
+	var changes []LayerChange
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		path := filepath.Join(dir, LayerFile)
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if current != nil && !isLayer(current) {
+			return nil, fmt.Errorf("%s exists and was not generated by PlatoSL", path)
+		}
+
+		if len(aliases) == 0 {
+			if current != nil {
+				changes = append(changes, LayerChange{Path: path})
+			}
+			continue
+		}
+		pkg := packageOf(dir)
+		if pkg == "" {
+			continue
+		}
+		want, err := Layer(pkg, aliases)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(current, want) {
+			changes = append(changes, LayerChange{Path: path, Content: want})
+		}
+	}
+	return changes, nil
+}
+
+// WriteLayers applies layer changes
+func WriteLayers(changes []LayerChange) error {
+	for _, c := range changes {
+		if c.Content == nil {
+			if err := os.Remove(c.Path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", c.Path, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(c.Path, c.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+// isLayer reports whether a file starts with the header of the layer
+func isLayer(data []byte) bool {
+	line, _ := bufio.NewReader(bytes.NewReader(data)).ReadString('\n')
+	return strings.Contains(line, "Generated by PlatoSL")
+}
+
+// packageOf returns the package of the CUE files in a directory other
+// than the layer, if any
+func packageOf(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.cue"))
+	for _, file := range files {
+		if filepath.Base(file) == LayerFile {
+			continue
+		}
+		f, err := parser.ParseFile(file, nil, parser.PackageClauseOnly)
+		if err == nil && f.PackageName() != "" {
+			return f.PackageName()
+		}
+	}
+	return ""
+}
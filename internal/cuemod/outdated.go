@@ -0,0 +1,147 @@
+package cuemod
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+)
+
+// Update is a newer version of a module the project imports directly
+type Update struct {
+	// Module is the module path with its major version, e.g.
+	// example.com/geo@v1
+	Module  string `json:"module"`
+	Current string `json:"current"`
+
+	// Wanted is the newest version the update policy of the import
+	// allows, empty when it allows none; Latest the newest release
+	Wanted string `json:"wanted,omitempty"`
+	Latest string `json:"latest"`
+
+	// Change is the kind of version change to Latest: patch, minor or
+	// major. Breaking is set for major changes and for minor changes of
+	// v0 modules, which semver allows to break.
+	Change   string `json:"change"`
+	Breaking bool   `json:"breaking"`
+
+	// Policy is the update policy of the import
+	Policy string `json:"policy"`
+
+	// Pinned is set when platosl.yaml pins the current version
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// Base returns the module path of an update without its major version
+func (u Update) Base() string {
+	base, _, _ := ast.SplitPackageVersion(u.Module)
+	return base
+}
+
+// Outdated returns the modules the project imports directly that have a
+// newer release in the registry than the version pinned in platosl.yaml or
+// required by module.cue, sorted by module. Pre-releases are only offered
+// for modules at a pre-release.
+func Outdated(ctx context.Context, reg modconfig.Registry, p Project, f *modfile.File) ([]Update, error) {
+	t := &tidier{ctx: ctx, reg: reg, project: p, file: f}
+	direct, err := t.direct()
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []Update
+	for _, w := range direct {
+		current, err := t.version(w)
+		if err != nil {
+			return nil, err
+		}
+		if current == "" {
+			continue
+		}
+		versions, err := reg.ModuleVersions(ctx, w.base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the versions of %s: %w", w.base, err)
+		}
+
+		policy := p.Policies[w.base]
+		if policy == "" {
+			policy = "minor"
+		}
+		u := Update{Module: w.base + "@" + majorOf(current), Current: current, Policy: policy, Pinned: w.pinned != ""}
+		for _, v := range versions {
+			if compareVersions(v, current) <= 0 || (isPrerelease(v) && !isPrerelease(current)) {
+				continue
+			}
+			if compareVersions(v, u.Latest) > 0 || u.Latest == "" {
+				u.Latest = v
+			}
+			if Allows(policy, current, v) && (u.Wanted == "" || compareVersions(v, u.Wanted) > 0) {
+				u.Wanted = v
+			}
+		}
+		if u.Latest == "" {
+			continue
+		}
+		u.Change, u.Breaking = Classify(current, u.Latest)
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Module < updates[j].Module })
+	return updates, nil
+}
+
+// Classify returns the kind of change from one version to another, patch,
+// minor or major, and whether it may break: major changes, and minor
+// changes below v1
+func Classify(from, to string) (string, bool) {
+	fromParts, toParts := versionParts(from), versionParts(to)
+	switch {
+	case fromParts[0] != toParts[0]:
+		return "major", true
+	case fromParts[1] != toParts[1]:
+		return "minor", fromParts[0] == "v0"
+	default:
+		return "patch", false
+	}
+}
+
+// Allows reports whether an update policy allows moving from one version
+// to another: none allows nothing, patch the same minor version, minor the
+// same major version and major any version
+func Allows(policy, from, to string) bool {
+	change, _ := Classify(from, to)
+	switch policy {
+	case "none":
+		return false
+	case "patch":
+		return change == "patch"
+	case "major":
+		return true
+	default:
+		return change != "major"
+	}
+}
+
+// versionParts splits a version into its major, minor and patch parts,
+// dropping any pre-release or build suffix
+func versionParts(version string) [3]string {
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "+")
+	var parts [3]string
+	copy(parts[:], strings.SplitN(version, ".", 3))
+	return parts
+}
+
+// isPrerelease reports whether a version is a pre-release, e.g. v1.0.0-rc.1
+func isPrerelease(version string) bool {
+	version, _, _ = strings.Cut(version, "+")
+	return strings.Contains(version, "-")
+}
+
+// Newer reports whether a version is newer than another in semver order
+func Newer(version, than string) bool {
+	return compareVersions(version, than) > 0
+}
@@ -0,0 +1,162 @@
+package deprecation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is the kind of thing that is deprecated
+type Kind int
+
+const (
+	// Flag is a command-line flag
+	Flag Kind = iota
+	// ConfigKey is a key in platosl.yaml
+	ConfigKey
+)
+
+// Deprecation describes a deprecated flag or config key
+type Deprecation struct {
+	Kind Kind
+
+	// Command is the command path of a flag, e.g. "platosl entry new"
+	Command string
+
+	// Name is the flag name without dashes, or the dotted path of a config
+	// key where * matches any key, e.g. generate.*.options.zod
+	Name string
+
+	// Value limits a config key deprecation to one value of the key
+	Value string
+
+	// Replacement tells the user what to do instead
+	Replacement string
+
+	// Since is the release that deprecated it, RemovedIn the release that
+	// will remove it
+	Since     string
+	RemovedIn string
+}
+
+// Table lists everything deprecated. Add an entry when a flag or config key
+// is replaced, and remove it with the flag or key in the RemovedIn release.
+var Table = []Deprecation{
+	{
+		Kind:        Flag,
+		Command:     "platosl entry new",
+		Name:        "out",
+		Replacement: "use --output (-o) instead",
+		Since:       "0.1.0",
+		RemovedIn:   "0.2.0",
+	},
+	{
+		Kind:        ConfigKey,
+		Name:        "version",
+		Value:       "v1",
+		Replacement: "run 'platosl upgrade-config' to upgrade to v2",
+		Since:       "0.1.0",
+		RemovedIn:   "1.0.0",
+	},
+	{
+		Kind:        ConfigKey,
+		Name:        "generate.typescript.options.zod",
+		Replacement: "enable the zod generator under generate.zod instead (the option has no effect)",
+		Since:       "0.1.0",
+		RemovedIn:   "0.2.0",
+	},
+}
+
+// String names the deprecated flag or config key
+func (d Deprecation) String() string {
+	switch {
+	case d.Kind == Flag:
+		return fmt.Sprintf("flag --%s of '%s'", d.Name, d.Command)
+	case d.Value != "":
+		return fmt.Sprintf("config %s %s", d.Name, d.Value)
+	}
+	return "config key " + d.Name
+}
+
+// Message returns the warning for a deprecation
+func (d Deprecation) Message() string {
+	return fmt.Sprintf("%s is deprecated since %s and will be removed in %s; %s", d, d.Since, d.RemovedIn, d.Replacement)
+}
+
+// Flags returns the deprecated flags of a command
+func Flags(command string) []Deprecation {
+	var found []Deprecation
+	for _, d := range Table {
+		if d.Kind == Flag && d.Command == command {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// ConfigKeys returns the deprecated keys set in config data. Data that does
+// not parse has none; loading it reports the error.
+func ConfigKeys(data []byte) []Deprecation {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+
+	var found []Deprecation
+	for _, d := range Table {
+		if d.Kind != ConfigKey {
+			continue
+		}
+		for _, value := range lookup(root.Content[0], strings.Split(d.Name, ".")) {
+			if d.Value == "" || (value.Kind == yaml.ScalarNode && value.Value == d.Value) {
+				found = append(found, d)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// lookup returns the values at a key path in a YAML mapping
+func lookup(node *yaml.Node, path []string) []*yaml.Node {
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if path[0] == "*" || node.Content[i].Value == path[0] {
+			values = append(values, lookup(node.Content[i+1], path[1:])...)
+		}
+	}
+	return values
+}
+
+// Output receives the warnings
+var Output io.Writer = os.Stderr
+
+var (
+	mu     sync.Mutex
+	warned = make(map[string]bool)
+)
+
+// Warn prints the warning of a deprecation, once per run.
+// PLATOSL_NO_DEPRECATION_WARNINGS=1 silences the warnings.
+func Warn(d Deprecation) {
+	if os.Getenv("PLATOSL_NO_DEPRECATION_WARNINGS") == "1" {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if warned[d.String()] {
+		return
+	}
+	warned[d.String()] = true
+	fmt.Fprintf(Output, "warning: %s\n", d.Message())
+}
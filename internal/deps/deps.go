@@ -0,0 +1,294 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// ErrNoModule is returned for a project without cue.mod/module.cue; only
+// CUE modules can have dependencies
+var ErrNoModule = errors.New("no CUE module found (cue.mod/module.cue); run 'platosl mod init <module>' to create one")
+
+// Module is a schema module the project depends on
+type Module struct {
+	// Path is the module path with its major version, e.g. example.com/base@v1
+	Path    string `json:"path"`
+	Version string `json:"version"`
+
+	// Source is the registry repository the module is fetched from, Dir
+	// where it is extracted in the CUE module cache
+	Source string `json:"source,omitempty"`
+	Dir    string `json:"dir"`
+
+	// License is the SPDX identifier from the module's package metadata
+	// (custom: platosl: license in its module.cue) or its LICENSE file
+	License string `json:"license,omitempty"`
+
+	// Direct is true when the project's own packages import the module
+	Direct bool `json:"direct"`
+
+	// UsedBy lists the local definitions that reference the module, e.g.
+	// "#Order (schemas/order.cue)"; ImportedBy the modules importing it
+	UsedBy     []string `json:"usedBy,omitempty"`
+	ImportedBy []string `json:"importedBy,omitempty"`
+}
+
+// Graph holds the resolved dependencies of a project
+type Graph struct {
+	// Module is the project's module path
+	Module  string    `json:"module"`
+	Modules []*Module `json:"dependencies"`
+}
+
+// Resolve loads the schema packages under dirs and resolves their imports,
+// direct and transitive, through the CUE module cache (fetching modules
+// from the registry when missing). Root is the directory holding cue.mod.
+func Resolve(root string, dirs []string) (*Graph, error) {
+	modPath := filepath.Join(root, "cue.mod", "module.cue")
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoModule
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", modPath, err)
+	}
+	mf, err := modfile.Parse(data, modPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", modPath, err)
+	}
+
+	var args []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("schema directory %s is outside the CUE module at %s", dir, root)
+		}
+		args = append(args, "./"+filepath.ToSlash(filepath.Join(rel, "...")))
+	}
+
+	insts := load.Instances(args, &load.Config{ModuleRoot: root, Dir: root, Registry: platoCue.Registry()})
+	for _, inst := range insts {
+		if inst.Err != nil {
+			return nil, fmt.Errorf("failed to resolve dependencies: %w", inst.Err)
+		}
+	}
+
+	g := &Graph{Module: mf.QualifiedModule()}
+	modules := make(map[string]*Module)
+	resolver, _ := modconfig.NewResolver(nil)
+
+	module := func(inst *build.Instance) *Module {
+		if m, ok := modules[inst.Module]; ok {
+			return m
+		}
+		m := &Module{
+			Path:    inst.Module,
+			Version: versionOf(mf, inst),
+			Dir:     moduleDir(inst),
+			License: licenseOf(inst),
+		}
+		if resolver != nil {
+			base, _, _ := strings.Cut(m.Path, "@")
+			if loc, ok := resolver.ResolveToLocation(base, m.Version); ok {
+				m.Source = loc.Host + "/" + loc.Repository
+			}
+		}
+		modules[inst.Module] = m
+		g.Modules = append(g.Modules, m)
+		return m
+	}
+
+	visited := make(map[*build.Instance]bool)
+	var walk func(inst *build.Instance)
+	walk = func(inst *build.Instance) {
+		if visited[inst] {
+			return
+		}
+		visited[inst] = true
+
+		local := inst.Module == g.Module
+		for _, imp := range inst.Imports {
+			if imp.Module != "" && imp.Module != g.Module {
+				m := module(imp)
+				if local {
+					m.Direct = true
+				} else if imp.Module != inst.Module {
+					m.ImportedBy = appendUnique(m.ImportedBy, inst.Module)
+				}
+			}
+			walk(imp)
+		}
+		if local {
+			for _, file := range inst.Files {
+				recordUses(root, inst, file, modules)
+			}
+		}
+	}
+	for _, inst := range insts {
+		walk(inst)
+	}
+
+	sort.Slice(g.Modules, func(i, j int) bool { return g.Modules[i].Path < g.Modules[j].Path })
+	return g, nil
+}
+
+// versionOf returns the selected version of an imported module: from the
+// project's module.cue, or the version suffix of its cache directory
+func versionOf(mf *modfile.File, inst *build.Instance) string {
+	if dep, ok := mf.Deps[inst.Module]; ok {
+		return dep.Version
+	}
+	if _, version, ok := strings.Cut(filepath.Base(moduleDir(inst)), "@"); ok {
+		return version
+	}
+	return ""
+}
+
+// recordUses adds each top-level definition or field of a local file that
+// references an imported module to that module's UsedBy
+func recordUses(root string, inst *build.Instance, file *ast.File, modules map[string]*Module) {
+	// Local import names in this file
+	names := make(map[string]*Module)
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		imp := inst.LookupImport(importPath)
+		if imp == nil || modules[imp.Module] == nil {
+			continue
+		}
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else {
+			name = importName(importPath)
+		}
+		names[name] = modules[imp.Module]
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	filename := file.Filename
+	if rel, err := filepath.Rel(root, filename); err == nil {
+		filename = filepath.ToSlash(rel)
+	}
+	for _, decl := range file.Decls {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		label, _, err := ast.LabelName(field.Label)
+		if err != nil {
+			continue
+		}
+		ast.Walk(field.Value, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := sel.X.(*ast.Ident); ok {
+				if m := names[id.Name]; m != nil {
+					m.UsedBy = appendUnique(m.UsedBy, fmt.Sprintf("%s (%s)", label, filename))
+				}
+			}
+			return true
+		}, nil)
+	}
+}
+
+// importName returns the default name of an import: its package qualifier,
+// or the last path element without the major version
+func importName(importPath string) string {
+	if _, pkg, ok := strings.Cut(importPath, ":"); ok {
+		return pkg
+	}
+	name := path.Base(importPath)
+	name, _, _ = strings.Cut(name, "@")
+	return name
+}
+
+// Find returns the module with a path, with or without its major version,
+// or the module providing an import path
+func (g *Graph) Find(name string) *Module {
+	for _, m := range g.Modules {
+		base, _, _ := strings.Cut(m.Path, "@")
+		if name == m.Path || name == base {
+			return m
+		}
+	}
+	name, _, _ = strings.Cut(name, "@")
+	for _, m := range g.Modules {
+		base, _, _ := strings.Cut(m.Path, "@")
+		if strings.HasPrefix(name, base+"/") {
+			return m
+		}
+	}
+	return nil
+}
+
+// Why returns the shortest import chains from the project to a module, each
+// starting with a local definition (or the project's module when no
+// definition references the module directly) and ending with the module
+func (g *Graph) Why(target *Module) [][]string {
+	byPath := make(map[string]*Module)
+	for _, m := range g.Modules {
+		byPath[m.Path] = m
+	}
+
+	var chains [][]string
+	seen := map[string]bool{target.Path: true}
+	queue := [][]string{{target.Path}}
+	for len(queue) > 0 {
+		chain := queue[0]
+		queue = queue[1:]
+		m := byPath[chain[0]]
+		if m.Direct {
+			users := m.UsedBy
+			if len(users) == 0 {
+				users = []string{g.Module}
+			}
+			for _, user := range users {
+				chains = append(chains, append([]string{user}, chain...))
+			}
+		}
+		for _, importer := range m.ImportedBy {
+			if !seen[importer] {
+				seen[importer] = true
+				queue = append(queue, append([]string{importer}, chain...))
+			}
+		}
+	}
+	return chains
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// moduleDir returns the root directory of an imported module: the package
+// directory without the package's path inside the module
+func moduleDir(inst *build.Instance) string {
+	modBase, _, _ := strings.Cut(inst.Module, "@")
+	pkgPath, _, _ := strings.Cut(inst.ImportPath, ":")
+	pkgPath, _, _ = strings.Cut(pkgPath, "@")
+	sub := strings.TrimPrefix(strings.TrimPrefix(pkgPath, modBase), "/")
+	if sub == "" {
+		return inst.Dir
+	}
+	return strings.TrimSuffix(inst.Dir, string(filepath.Separator)+filepath.FromSlash(sub))
+}
@@ -0,0 +1,71 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue/build"
+)
+
+// licenseFiles are the names checked for a license text, in order
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+// licenseMarkers identify common licenses by phrases of their text; more
+// specific entries come first
+var licenseMarkers = []struct {
+	id      string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "2.0"}},
+	{"AGPL-3.0", []string{"GNU AFFERO GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+	{"ISC", []string{"Permission to use, copy, modify, and/or distribute this software"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"This is free and unencumbered software"}},
+}
+
+// licenseOf returns the license of an imported module. The package metadata
+// wins:
+//
+//	custom: platosl: license: "Apache-2.0"
+//
+// in the module's cue.mod/module.cue; otherwise the license is detected from
+// a LICENSE file. A license file that is not recognised reports "unknown".
+func licenseOf(inst *build.Instance) string {
+	if mf := inst.ModuleFile; mf != nil {
+		if license, ok := mf.Custom["platosl"]["license"].(string); ok && license != "" {
+			return license
+		}
+	}
+	dir := moduleDir(inst)
+	for _, name := range licenseFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return detectLicense(string(data))
+		}
+	}
+	return ""
+}
+
+// detectLicense identifies a license text
+func detectLicense(text string) string {
+	for _, marker := range licenseMarkers {
+		found := true
+		for _, phrase := range marker.phrases {
+			if !strings.Contains(text, phrase) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return marker.id
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,157 @@
+package devtest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Scenario is a canonical schema exercising one feature
+type Scenario struct {
+	Name    string `json:"name"`
+	Feature string `json:"feature"`
+
+	// Source is the CUE of the scenario, without a package clause
+	Source string `json:"-"`
+
+	// Expect lists names the output must contain
+	Expect []string `json:"-"`
+}
+
+// Statuses of a scenario run
+const (
+	// Pass means the output has every expected name and is the same on
+	// every run
+	Pass = "pass"
+
+	// Fail means the output misses expected names, is empty, or differs
+	// between runs
+	Fail = "fail"
+
+	// Error means the generator returned an error
+	Error = "error"
+
+	// Crash means the generator panicked or overflowed its stack
+	Crash = "crash"
+
+	// Timeout means the generator did not finish in time
+	Timeout = "timeout"
+)
+
+// Result is the outcome of running a scenario through a generator
+type Result struct {
+	Scenario string `json:"scenario"`
+	Feature  string `json:"feature"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Output   string `json:"output,omitempty"`
+}
+
+// Find returns the scenario of a name
+func Find(name string) (Scenario, bool) {
+	for _, s := range Scenarios {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// Run runs a scenario through a generator. Panics are reported as crashes,
+// but a stack overflow ends the process, so callers run scenarios in child
+// processes to survive generators that recurse without end.
+func Run(gen generator.Generator, s Scenario, genCfg config.GenConfig) Result {
+	result := Result{Scenario: s.Name, Feature: s.Feature}
+
+	val := cuecontext.New().CompileString("package devtest\n\n"+s.Source, cue.Filename(s.Name+".cue"))
+	if err := val.Err(); err != nil {
+		result.Status = Error
+		result.Detail = fmt.Sprintf("scenario does not compile: %v", err)
+		return result
+	}
+	cfg := &config.Config{Name: "devtest"}
+
+	output, err := generate(gen, val, cfg, genCfg)
+	result.Output = string(output)
+	if err != nil {
+		result.Status = Error
+		if p, ok := err.(panicError); ok {
+			result.Status = Crash
+			err = p
+		}
+		result.Detail = err.Error()
+		return result
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		result.Status = Fail
+		result.Detail = "empty output"
+		return result
+	}
+	if missing := Missing(output, s.Expect); len(missing) > 0 {
+		result.Status = Fail
+		result.Detail = "missing " + strings.Join(missing, ", ")
+		return result
+	}
+
+	again, err := generate(gen, val, cfg, genCfg)
+	if err != nil || !bytes.Equal(output, again) {
+		result.Status = Fail
+		result.Detail = "output differs between runs"
+		return result
+	}
+
+	result.Status = Pass
+	return result
+}
+
+// panicError is a recovered panic of a generator
+type panicError struct {
+	value interface{}
+}
+
+func (p panicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+func generate(gen generator.Generator, val cue.Value, cfg *config.Config, genCfg config.GenConfig) (output []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError{value: r}
+		}
+	}()
+	ctx := generator.NewContext(val, cfg, genCfg)
+	if err := gen.Validate(ctx); err != nil {
+		return nil, err
+	}
+	return gen.Generate(ctx)
+}
+
+// Missing returns the names an output does not contain, ignoring case and
+// punctuation
+func Missing(output []byte, names []string) []string {
+	text := normalize(string(output))
+	var missing []string
+	for _, name := range names {
+		if !strings.Contains(text, normalize(name)) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// normalize lowercases letters and digits and drops everything else
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
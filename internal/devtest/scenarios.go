@@ -0,0 +1,245 @@
+package devtest
+
+// Scenarios are the canonical schemas every generator should handle, one
+// feature each. Expect lists the names a generator must carry into its
+// output; they are matched ignoring case and punctuation, so orderItem,
+// OrderItem and order_item all match order-item.
+var Scenarios = []Scenario{
+	{
+		Name:    "scalars",
+		Feature: "string, int, float, bool and bytes fields",
+		Source: `#Scalars: {
+	title:   string
+	count:   int
+	ratio:   float
+	enabled: bool
+	payload: bytes
+}`,
+		Expect: []string{"Scalars", "title", "count", "ratio", "enabled", "payload"},
+	},
+	{
+		Name:    "optional",
+		Feature: "optional fields",
+		Source: `#Profile: {
+	handle:    string
+	nickname?: string
+	age?:      int
+}`,
+		Expect: []string{"Profile", "handle", "nickname", "age"},
+	},
+	{
+		Name:    "nested",
+		Feature: "inline nested structs",
+		Source: `#Shipment: {
+	tracking: string
+	destination: {
+		street:   string
+		postcode: string
+	}
+}`,
+		Expect: []string{"Shipment", "destination", "street", "postcode"},
+	},
+	{
+		Name:    "references",
+		Feature: "fields referencing other definitions",
+		Source: `#Customer: {
+	fullName: string
+}
+
+#Invoice: {
+	number:   string
+	customer: #Customer
+}`,
+		Expect: []string{"Customer", "Invoice", "fullName", "customer"},
+	},
+	{
+		Name:    "lists",
+		Feature: "lists, of scalars, definitions and lists",
+		Source: `#Line: {
+	sku: string
+}
+
+#Basket: {
+	tags: [...string]
+	lines: [...#Line]
+	matrix: [...[...int]]
+}`,
+		Expect: []string{"Basket", "Line", "tags", "lines", "matrix"},
+	},
+	{
+		Name:    "maps",
+		Feature: "pattern constraints as maps",
+		Source: `#Inventory: {
+	warehouse: string
+	stock: [string]: int
+	labels: [string]: string
+}`,
+		Expect: []string{"Inventory", "warehouse", "stock", "labels"},
+	},
+	{
+		Name:    "enums",
+		Feature: "disjunctions of string literals",
+		Source: `#Ticket: {
+	subject: string
+	status:  "open" | "pending" | "closed"
+}`,
+		Expect: []string{"Ticket", "status", "open", "pending", "closed"},
+	},
+	{
+		Name:    "unions",
+		Feature: "disjunctions of struct definitions",
+		Source: `#Cat: {
+	meows: bool
+}
+
+#Dog: {
+	barks: bool
+}
+
+#Adoption: {
+	animal: #Cat | #Dog
+}`,
+		Expect: []string{"Adoption", "animal", "Cat", "Dog", "meows", "barks"},
+	},
+	{
+		Name:    "mixed-unions",
+		Feature: "disjunctions of different kinds",
+		Source: `#Setting: {
+	key:   string
+	value: string | int | bool
+}`,
+		Expect: []string{"Setting", "key", "value"},
+	},
+	{
+		Name:    "nullable",
+		Feature: "fields that may be null",
+		Source: `#Draft: {
+	headline:    string
+	publishedAt: string | null
+}`,
+		Expect: []string{"Draft", "headline", "publishedAt"},
+	},
+	{
+		Name:    "defaults",
+		Feature: "default values",
+		Source: `#Preferences: {
+	theme:   *"light" | "dark"
+	retries: int | *3
+}`,
+		Expect: []string{"Preferences", "theme", "retries", "light"},
+	},
+	{
+		Name:    "constraints",
+		Feature: "bounds, patterns and length constraints",
+		Source: `import "strings"
+
+#Account: {
+	username: string & =~"^[a-z]+$" & strings.MinRunes(3)
+	age:      int & >=0 & <=150
+}`,
+		Expect: []string{"Account", "username", "age", "150"},
+	},
+	{
+		Name:    "recursion",
+		Feature: "self-referencing definitions",
+		Source: `#TreeNode: {
+	label: string
+	children: [...#TreeNode]
+	parent?: #TreeNode
+}`,
+		Expect: []string{"TreeNode", "label", "children", "parent"},
+	},
+	{
+		Name:    "mutual-recursion",
+		Feature: "definitions referencing each other",
+		Source: `#Author: {
+	penName: string
+	books: [...#Book]
+}
+
+#Book: {
+	isbn: string
+	author?: #Author
+}`,
+		Expect: []string{"Author", "Book", "penName", "books", "isbn", "author"},
+	},
+	{
+		Name:    "embedding",
+		Feature: "definitions embedded in others",
+		Source: `#Timestamps: {
+	createdAt: string
+	updatedAt: string
+}
+
+#Post: {
+	#Timestamps
+	body: string
+}`,
+		Expect: []string{"Post", "createdAt", "updatedAt", "body"},
+	},
+	{
+		Name:    "aliases",
+		Feature: "definitions of scalar types",
+		Source: `#Email: string & =~"^[^@]+@[^@]+$"
+
+#Subscriber: {
+	email: #Email
+	since: int
+}`,
+		Expect: []string{"Subscriber", "email", "since"},
+	},
+	{
+		Name:    "unicode-names",
+		Feature: "non-ASCII field names",
+		Source: `#Produkt: {
+	"größe":  int
+	"名前":     string
+	"prix-€": float
+}`,
+		Expect: []string{"Produkt", "größe", "名前"},
+	},
+	{
+		Name:    "quoted-names",
+		Feature: "field names that are not identifiers",
+		Source: `#Signup: {
+	"first-name": string
+	"2fa":        bool
+	"zip code":   string
+}`,
+		Expect: []string{"Signup", "first-name", "2fa", "zip code"},
+	},
+	{
+		Name:    "reserved-words",
+		Feature: "field names that are keywords in target languages",
+		Source: `#Keywords: {
+	"type":    string
+	"class":   string
+	"default": string
+	"import":  string
+	"package": string
+}`,
+		Expect: []string{"Keywords", "type", "class", "default", "import", "package"},
+	},
+	{
+		Name:    "docs",
+		Feature: "doc comments",
+		Source: `// A carefully documented widget
+#Widget: {
+	// Stock keeping unit of the widget
+	sku: string
+}`,
+		Expect: []string{"Widget", "sku", "carefully documented", "stock keeping unit"},
+	},
+	{
+		Name:    "open-structs",
+		Feature: "open structs and empty definitions",
+		Source: `#Metadata: {...}
+
+#Envelope: {
+	id:   string
+	meta: #Metadata
+	extra: {...}
+}`,
+		Expect: []string{"Envelope", "id", "meta", "extra"},
+	},
+}
@@ -0,0 +1,197 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// opKind identifies a line-level edit operation
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is a single line-level edit
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a unified diff between a and b, or an empty string if they are equal
+func Unified(fromName, toName string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+
+	ops := lineOps(splitLines(string(a)), splitLines(string(b)))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n", fromName)
+	fmt.Fprintf(&out, "+++ %s\n", toName)
+
+	for _, h := range hunks(ops) {
+		out.WriteString(h)
+	}
+
+	return out.String()
+}
+
+// Stat returns the number of lines added and removed from a to b
+func Stat(a, b []byte) (added, removed int) {
+	if string(a) == string(b) {
+		return 0, 0
+	}
+	for _, o := range lineOps(splitLines(string(a)), splitLines(string(b))) {
+		switch o.kind {
+		case opInsert:
+			added++
+		case opDelete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// splitLines splits text into lines, dropping the trailing empty line
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOps computes the edit script between two line slices using an LCS table.
+// Common prefixes and suffixes are stripped first to keep the table small.
+func lineOps(a, b []string) []op {
+	var prefix []op
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, op{opEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	var suffix []op
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]op{{opEqual, a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := prefix
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{opInsert, b[j]})
+	}
+
+	return append(ops, suffix...)
+}
+
+// hunks groups an edit script into unified diff hunks
+func hunks(ops []op) []string {
+	var result []string
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		first := start
+		for first < len(ops) && ops[first].kind == opEqual {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*contextLines of each other
+		last := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != opEqual {
+				last = k
+			} else if k-last > 2*contextLines {
+				break
+			}
+		}
+
+		from := first - contextLines
+		if from < start {
+			from = start
+		}
+		to := last + contextLines + 1
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		// Line numbers at the start of the hunk
+		aLine, bLine := 1, 1
+		for _, o := range ops[:from] {
+			if o.kind != opInsert {
+				aLine++
+			}
+			if o.kind != opDelete {
+				bLine++
+			}
+		}
+
+		var body strings.Builder
+		aCount, bCount := 0, 0
+		for _, o := range ops[from:to] {
+			switch o.kind {
+			case opEqual:
+				body.WriteString(" " + o.line + "\n")
+				aCount++
+				bCount++
+			case opDelete:
+				body.WriteString("-" + o.line + "\n")
+				aCount++
+			case opInsert:
+				body.WriteString("+" + o.line + "\n")
+				bCount++
+			}
+		}
+
+		result = append(result, fmt.Sprintf("@@ -%d,%d +%d,%d @@\n%s", aLine, aCount, bLine, bCount, body.String()))
+		start = to
+	}
+
+	return result
+}
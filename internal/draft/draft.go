@@ -0,0 +1,183 @@
+package draft
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// Model completes a prompt into a schema draft
+type Model interface {
+	Complete(prompt Prompt) (string, error)
+}
+
+// Prompt is what the model is asked
+type Prompt struct {
+	System string
+	User   string
+}
+
+// Text is the prompt as one text, for models taking a single input
+func (p Prompt) Text() string {
+	return p.System + "\n\n" + p.User + "\n"
+}
+
+// Request describes the schema to draft
+type Request struct {
+	// Description is what the schema models, in the user's words
+	Description string
+
+	// Package is the CUE package of the target directory
+	Package string
+
+	// Definitions are the existing definitions the draft may reference
+	Definitions []string
+}
+
+// system instructs the model on the conventions of PlatoSL schemas
+const system = `You write data schemas in CUE for PlatoSL, which generates TypeScript, Go,
+JSON Schema and other code from them. Follow these conventions:
+
+- Model each entity as a closed definition named in PascalCase, e.g. #Invoice.
+- Name fields in camelCase and document every definition and field with a
+  // comment above it.
+- Mark optional fields with ?, and give defaults with *, e.g. status: "draft" | *"open".
+- Use enums of string literals ("a" | "b") for fixed sets of values.
+- Constrain values: bounds on numbers (int & >=0), strings.MaxRunes or
+  patterns (=~) on strings, [...#Item] for lists.
+- Reuse existing definitions by reference instead of redefining them.
+- Only import CUE standard library packages such as "strings" or "time".
+
+Answer with a single CUE file in a cue code block and nothing else.`
+
+// NewPrompt returns the prompt asking for a draft
+func NewPrompt(req Request) Prompt {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Draft a schema for: %s\n", req.Description)
+	if req.Package != "" {
+		fmt.Fprintf(&b, "\nThe file belongs to package %s.\n", req.Package)
+	}
+	if len(req.Definitions) > 0 {
+		fmt.Fprintf(&b, "\nExisting definitions in the package, which must not be redefined:\n%s\n", strings.Join(req.Definitions, ", "))
+	}
+	return Prompt{System: system, User: strings.TrimRight(b.String(), "\n")}
+}
+
+// HTTP is a model behind an OpenAI-compatible chat completions endpoint,
+// e.g. https://api.openai.com/v1/chat/completions or a local server
+type HTTP struct {
+	Client   *httpclient.Client
+	Endpoint string
+	Model    string
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Complete implements Model
+func (h *HTTP) Complete(prompt Prompt) (string, error) {
+	req := chatRequest{
+		Model: h.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
+		Temperature: 0.2,
+	}
+	var resp chatResponse
+	if err := h.Client.DoJSON(http.MethodPost, h.Endpoint, req, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("%s returned no completion", h.Endpoint)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Command is a model behind a command, which reads the prompt on stdin and
+// writes the draft to stdout, e.g. a wrapper around a local model
+type Command struct {
+	Args []string
+}
+
+// Complete implements Model
+func (c *Command) Complete(prompt Prompt) (string, error) {
+	if len(c.Args) == 0 {
+		return "", fmt.Errorf("no draft command configured")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(c.Args[0], c.Args[1:]...)
+	cmd.Stdin = strings.NewReader(prompt.Text())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", c.Args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", c.Args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+var codeBlock = regexp.MustCompile("(?s)```[a-zA-Z]*[ \t]*\n(.*?)```")
+
+// Normalize turns a completion into a formatted CUE file of a package: the
+// code is taken from the first code block when there is one, must parse,
+// and gets the package clause of the target directory
+func Normalize(completion, pkg string) ([]byte, error) {
+	src := completion
+	if m := codeBlock.FindStringSubmatch(completion); m != nil {
+		src = m[1]
+	}
+	if strings.TrimSpace(src) == "" {
+		return nil, fmt.Errorf("the model returned no schema")
+	}
+
+	file, err := parser.ParseFile("draft.cue", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("the draft is not valid CUE: %w", err)
+	}
+
+	if pkg != "" {
+		var clause *ast.Package
+		for _, decl := range file.Decls {
+			if p, ok := decl.(*ast.Package); ok {
+				clause = p
+				break
+			}
+		}
+		if clause != nil {
+			clause.Name = ast.NewIdent(pkg)
+		} else {
+			file.Decls = append([]ast.Decl{&ast.Package{Name: ast.NewIdent(pkg)}}, file.Decls...)
+		}
+	}
+
+	out, err := format.Node(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the draft: %w", err)
+	}
+	return out, nil
+}
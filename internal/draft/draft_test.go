@@ -0,0 +1,32 @@
+package draft
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	completion := "Here is the schema:\n\n```cue\npackage other\n\n#LineItem: {sku: string, qty: int & >0}\n```\n\nLet me know."
+	out, err := Normalize(completion, "schemas")
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	src := string(out)
+	if !strings.HasPrefix(src, "package schemas\n") || strings.Contains(src, "package other") {
+		t.Errorf("package clause not replaced:\n%s", src)
+	}
+	if !strings.Contains(src, "#LineItem: {") || strings.Contains(src, "Let me know") {
+		t.Errorf("code block not extracted:\n%s", src)
+	}
+
+	out, err = Normalize("#Tag: string", "schemas")
+	if err != nil || !strings.HasPrefix(string(out), "package schemas\n") {
+		t.Errorf("Normalize without package clause = %q, %v", out, err)
+	}
+
+	for _, bad := range []string{"", "```cue\n```", "#Broken: {"} {
+		if _, err := Normalize(bad, "schemas"); err == nil {
+			t.Errorf("Normalize(%q) did not fail", bad)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// Finding kinds
+const (
+	UnknownField    = "unknown-field"    // in data, not in the schema
+	AbsentField     = "absent-field"     // in the schema, never in data
+	MissingRequired = "missing-required" // required, but missing from some data
+	TypeMismatch    = "type-mismatch"    // a value of a kind the schema rejects
+	UnknownValue    = "unknown-value"    // a string outside a string enum
+)
+
+// Finding is a difference between the schema and the sampled data
+type Finding struct {
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Of      int    `json:"of"`
+	Message string `json:"message"`
+
+	// Suggestion is a CUE field declaration that would fit the data, to
+	// add to the struct at Parent
+	Suggestion string `json:"suggestion,omitempty"`
+	Parent     string `json:"parent,omitempty"`
+}
+
+// Report is the result of comparing a sample of payloads with a definition
+type Report struct {
+	Definition string    `json:"definition"`
+	Records    int       `json:"records"`
+	Total      int       `json:"total"`
+	Malformed  int       `json:"malformed"`
+	Findings   []Finding `json:"findings"`
+}
+
+// Analyzer compares payloads with a definition, one at a time
+type Analyzer struct {
+	def        cue.Value
+	definition string
+	records    int
+
+	structs  map[string]*structInfo   // schema structs by walk key
+	objects  map[string]int           // objects seen at a path
+	values   map[string]int           // values seen at a path, including null
+	fields   map[string]*fieldStats   // declared fields by path
+	unknown  map[string]*unknownField // undeclared fields by path
+	mismatch map[string]*mismatch     // values of the wrong kind by path
+	enums    map[string]*enumStats    // values outside string enums by path
+}
+
+// structInfo is the fields of a schema struct, cached per walk key
+type structInfo struct {
+	fields  []fieldInfo
+	index   map[string]int
+	pattern cue.Value
+	open    bool
+}
+
+type fieldInfo struct {
+	label    string
+	val      cue.Value
+	optional bool
+}
+
+// unknownField is an undeclared field and the shape of its values
+type unknownField struct {
+	parent string
+	label  string
+	shape  shape
+}
+
+type fieldStats struct {
+	parent   string
+	label    string
+	val      cue.Value
+	optional bool
+	present  int
+}
+
+// mismatch counts values of kinds the schema rejects; parent and label
+// are empty for values that are not fields, e.g. list elements
+type mismatch struct {
+	expected string
+	parent   string
+	label    string
+	got      map[string]int
+}
+
+type enumStats struct {
+	parent  string
+	label   string
+	members []string
+	unseen  map[string]int
+}
+
+// NewAnalyzer creates an analyzer for a definition; definition names it in
+// the report
+func NewAnalyzer(def cue.Value, definition string) *Analyzer {
+	return &Analyzer{
+		def:        def,
+		definition: definition,
+		structs:    make(map[string]*structInfo),
+		objects:    make(map[string]int),
+		values:     make(map[string]int),
+		fields:     make(map[string]*fieldStats),
+		unknown:    make(map[string]*unknownField),
+		mismatch:   make(map[string]*mismatch),
+		enums:      make(map[string]*enumStats),
+	}
+}
+
+// Add analyzes a payload decoded with json.Decoder.UseNumber
+func (a *Analyzer) Add(record interface{}) {
+	a.records++
+	a.walk(a.def, "", "", "", "", record)
+}
+
+// walk compares a data value with the schema at a path. key identifies the
+// schema value, which differs from the path when a disjunction branch was
+// chosen. parent and label are set when the value is a field.
+func (a *Analyzer) walk(schema cue.Value, key, path, parent, label string, data interface{}) {
+	schema, key = a.branch(schema, key, data)
+
+	kind := kindOf(data)
+	a.values[path]++
+	if schema.IncompleteKind()&kind == 0 {
+		m := a.mismatch[path]
+		if m == nil {
+			m = &mismatch{expected: kindNames(schema.IncompleteKind()), parent: parent, label: label, got: make(map[string]int)}
+			a.mismatch[path] = m
+		}
+		m.got[kindNames(kind)]++
+		return
+	}
+
+	switch d := data.(type) {
+	case map[string]interface{}:
+		a.walkStruct(schema, key, path, d)
+	case []interface{}:
+		elem := schema.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return
+		}
+		for _, item := range d {
+			a.walk(elem, key+"[]", path+"[]", "", "", item)
+		}
+	case string:
+		members := stringEnum(schema)
+		if members == nil || contains(members, d) {
+			return
+		}
+		e := a.enums[path]
+		if e == nil {
+			e = &enumStats{parent: parent, label: label, members: members, unseen: make(map[string]int)}
+			a.enums[path] = e
+		}
+		e.unseen[d]++
+	}
+}
+
+// walkStruct compares the fields of an object with a schema struct
+func (a *Analyzer) walkStruct(schema cue.Value, key, path string, data map[string]interface{}) {
+	info := a.structInfo(schema, key)
+	a.objects[path]++
+
+	for _, f := range info.fields {
+		fieldPath := joinPath(path, f.label)
+		stats := a.fields[fieldPath]
+		if stats == nil {
+			stats = &fieldStats{parent: path, label: f.label, val: f.val, optional: f.optional}
+			a.fields[fieldPath] = stats
+		}
+		if value, ok := data[f.label]; ok {
+			stats.present++
+			a.walk(f.val, key+"."+f.label, fieldPath, path, f.label, value)
+		}
+	}
+
+	labels := make([]string, 0, len(data))
+	for label := range data {
+		if _, ok := info.index[label]; !ok {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fieldPath := joinPath(path, label)
+		switch {
+		case info.pattern.Exists():
+			a.walk(info.pattern, key+".*", joinPath(path, "*"), "", "", data[label])
+		case info.open:
+		default:
+			u := a.unknown[fieldPath]
+			if u == nil {
+				u = &unknownField{parent: path, label: label}
+				a.unknown[fieldPath] = u
+			}
+			u.shape.add(data[label])
+		}
+	}
+}
+
+// structInfo returns the cached fields of a schema struct
+func (a *Analyzer) structInfo(schema cue.Value, key string) *structInfo {
+	if info, ok := a.structs[key]; ok {
+		return info
+	}
+
+	info := &structInfo{index: make(map[string]int)}
+	if iter, err := schema.Fields(cue.Optional(true)); err == nil {
+		for iter.Next() {
+			if iter.Selector().IsDefinition() {
+				continue
+			}
+			label := iter.Selector().Unquoted()
+			info.index[label] = len(info.fields)
+			info.fields = append(info.fields, fieldInfo{label: label, val: iter.Value(), optional: iter.IsOptional()})
+		}
+	}
+	info.pattern = schema.LookupPath(cue.MakePath(cue.AnyString))
+	// A label no schema declares tells open structs from closed ones
+	info.open = schema.Allows(cue.Str("\x00platosl-drift"))
+
+	a.structs[key] = info
+	return info
+}
+
+// branch picks the disjunction branch a value most likely belongs to: the
+// first branch of its kind, or among struct branches the one declaring most
+// of its fields
+func (a *Analyzer) branch(schema cue.Value, key string, data interface{}) (cue.Value, string) {
+	op, args := schema.Expr()
+	if op != cue.OrOp || len(args) < 2 || stringEnum(schema) != nil {
+		return schema, key
+	}
+
+	kind := kindOf(data)
+	best, bestScore := -1, -1
+	for i, arg := range args {
+		if arg.IncompleteKind()&kind == 0 {
+			continue
+		}
+		score := 0
+		if obj, ok := data.(map[string]interface{}); ok {
+			info := a.structInfo(arg, fmt.Sprintf("%s|%d", key, i))
+			for label := range obj {
+				if _, ok := info.index[label]; ok {
+					score++
+				}
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return schema, key
+	}
+	return args[best], fmt.Sprintf("%s|%d", key, best)
+}
+
+// Report lists the findings, sorted by path
+func (a *Analyzer) Report() *Report {
+	r := &Report{Definition: a.definition, Records: a.records, Findings: []Finding{}}
+
+	for path, u := range a.unknown {
+		of := a.objects[u.parent]
+		r.Findings = append(r.Findings, Finding{
+			Path:       path,
+			Kind:       UnknownField,
+			Count:      u.shape.count,
+			Of:         of,
+			Message:    fmt.Sprintf("not in schema, present in %s as %s", ratio(u.shape.count, of), u.shape.describe()),
+			Suggestion: u.shape.field(u.label, u.shape.count < of, ""),
+			Parent:     u.parent,
+		})
+	}
+
+	for path, f := range a.fields {
+		of := a.objects[f.parent]
+		switch {
+		case of == 0:
+		case f.present == 0:
+			r.Findings = append(r.Findings, Finding{
+				Path:    path,
+				Kind:    AbsentField,
+				Count:   of,
+				Of:      of,
+				Message: fmt.Sprintf("in schema, never present in %d object(s)", of),
+			})
+		case f.present < of && !f.optional:
+			finding := Finding{
+				Path:    path,
+				Kind:    MissingRequired,
+				Count:   of - f.present,
+				Of:      of,
+				Message: fmt.Sprintf("required, but missing in %s", ratio(of-f.present, of)),
+			}
+			// Scalar types print compactly; structs and lists are left to the
+			// schema, and mismatched types to the type-mismatch suggestion
+			if kind := f.val.IncompleteKind(); kind&(cue.StructKind|cue.ListKind) == 0 && a.mismatch[path] == nil {
+				finding.Suggestion = fmt.Sprintf("%s?: %v", quoteLabel(f.label), f.val)
+				finding.Parent = f.parent
+			}
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+
+	for path, m := range a.mismatch {
+		// A field that is also sometimes missing gets one optional suggestion
+		optional := ""
+		if f, ok := a.fields[path]; ok && !f.optional && f.present < a.objects[f.parent] {
+			optional = "?"
+		}
+		count := 0
+		var got []string
+		for kind, n := range m.got {
+			count += n
+			got = append(got, kind)
+		}
+		sort.Strings(got)
+		of := a.values[path]
+		finding := Finding{
+			Path:    path,
+			Kind:    TypeMismatch,
+			Count:   count,
+			Of:      of,
+			Message: fmt.Sprintf("schema expects %s, data has %s in %s", m.expected, strings.Join(got, ", "), ratio(count, of)),
+		}
+		if m.label != "" {
+			finding.Suggestion = quoteLabel(m.label) + optional + ": " + strings.Join(append(got, m.expected), " | ")
+			finding.Parent = m.parent
+		}
+		r.Findings = append(r.Findings, finding)
+	}
+
+	for path, e := range a.enums {
+		count := 0
+		values := make([]string, 0, len(e.unseen))
+		for v, n := range e.unseen {
+			count += n
+			values = append(values, v)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if e.unseen[values[i]] != e.unseen[values[j]] {
+				return e.unseen[values[i]] > e.unseen[values[j]]
+			}
+			return values[i] < values[j]
+		})
+
+		described := make([]string, len(values))
+		for i, v := range values {
+			described[i] = fmt.Sprintf("%s (%d)", quote(v), e.unseen[v])
+		}
+		finding := Finding{
+			Path:    path,
+			Kind:    UnknownValue,
+			Count:   count,
+			Of:      a.values[path],
+			Message: fmt.Sprintf("values not in schema: %s", strings.Join(described, ", ")),
+		}
+		if e.label != "" {
+			var members []string
+			for _, m := range append(e.members, values...) {
+				members = append(members, quote(m))
+			}
+			finding.Suggestion = quoteLabel(e.label) + ": " + strings.Join(members, " | ")
+			finding.Parent = e.parent
+		}
+		r.Findings = append(r.Findings, finding)
+	}
+
+	sort.Slice(r.Findings, func(i, j int) bool {
+		if r.Findings[i].Path != r.Findings[j].Path {
+			return r.Findings[i].Path < r.Findings[j].Path
+		}
+		return r.Findings[i].Kind < r.Findings[j].Kind
+	})
+	return r
+}
+
+// kindOf returns the CUE kind of a decoded JSON value
+func kindOf(data interface{}) cue.Kind {
+	switch d := data.(type) {
+	case nil:
+		return cue.NullKind
+	case bool:
+		return cue.BoolKind
+	case string:
+		return cue.StringKind
+	case json.Number:
+		if strings.ContainsAny(d.String(), ".eE") {
+			return cue.FloatKind
+		}
+		return cue.IntKind
+	case []interface{}:
+		return cue.ListKind
+	case map[string]interface{}:
+		return cue.StructKind
+	default:
+		return cue.BottomKind
+	}
+}
+
+// kindNames describes the kinds a schema allows, e.g. "int | string"
+func kindNames(kind cue.Kind) string {
+	if kind&cue.NumberKind == cue.NumberKind {
+		kind = kind &^ cue.NumberKind
+		if kind == 0 {
+			return "number"
+		}
+		return kindNames(kind) + " | number"
+	}
+	var names []string
+	for _, k := range []struct {
+		kind cue.Kind
+		name string
+	}{
+		{cue.NullKind, "null"}, {cue.BoolKind, "bool"}, {cue.IntKind, "int"}, {cue.FloatKind, "float"},
+		{cue.StringKind, "string"}, {cue.BytesKind, "bytes"}, {cue.ListKind, "list"}, {cue.StructKind, "struct"},
+	} {
+		if kind&k.kind != 0 {
+			names = append(names, k.name)
+		}
+	}
+	if len(names) == 0 {
+		return "_|_"
+	}
+	return strings.Join(names, " | ")
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath appends a field label to a path
+func joinPath(path, label string) string {
+	if path == "" {
+		return label
+	}
+	return path + "." + label
+}
+
+// ratio renders a count out of a total, e.g. "12 of 400 (3%)"
+func ratio(count, of int) string {
+	if of == 0 {
+		return fmt.Sprintf("%d", count)
+	}
+	return fmt.Sprintf("%d of %d (%d%%)", count, of, count*100/of)
+}
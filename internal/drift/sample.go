@@ -0,0 +1,99 @@
+package drift
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// maxLineSize bounds the length of a single NDJSON line
+const maxLineSize = 16 << 20
+
+// Sampler keeps a uniform random sample of NDJSON lines (reservoir sampling),
+// so large logs are analyzed in bounded memory
+type Sampler struct {
+	size  int
+	rnd   *rand.Rand
+	lines [][]byte
+	total int
+}
+
+// NewSampler creates a sampler keeping up to size lines; 0 keeps every line
+func NewSampler(size int, seed int64) *Sampler {
+	return &Sampler{size: size, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Read adds the non-empty lines of r to the sample
+func (s *Sampler) Read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		s.total++
+
+		switch {
+		case s.size <= 0 || len(s.lines) < s.size:
+			s.lines = append(s.lines, append([]byte(nil), line...))
+		default:
+			if i := s.rnd.Intn(s.total); i < s.size {
+				s.lines[i] = append([]byte(nil), line...)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	return nil
+}
+
+// Analyze decodes the sampled lines and compares them with the analyzer's
+// definition. When path is set, the payload is the value at that dot-separated
+// path of each line, e.g. "body" for {"ts": ..., "body": {...}}. Lines that
+// are not JSON or lack the path are counted as malformed.
+func (s *Sampler) Analyze(a *Analyzer, path string) *Report {
+	malformed := 0
+	for _, line := range s.lines {
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var record interface{}
+		if err := dec.Decode(&record); err != nil {
+			malformed++
+			continue
+		}
+		payload, ok := extract(record, path)
+		if !ok {
+			malformed++
+			continue
+		}
+		a.Add(payload)
+	}
+
+	r := a.Report()
+	r.Total = s.total
+	r.Malformed = malformed
+	return r
+}
+
+// extract returns the value at a dot-separated path of a record
+func extract(record interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return record, true
+	}
+	for _, label := range strings.Split(path, ".") {
+		obj, ok := record.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if record, ok = obj[label]; !ok {
+			return nil, false
+		}
+	}
+	return record, true
+}
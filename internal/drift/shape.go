@@ -0,0 +1,151 @@
+package drift
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// identifier matches labels that need no quotes in CUE
+var identifier = regexp.MustCompile(`^[a-zA-Z$][a-zA-Z0-9_$]*$`)
+
+// keywords are identifiers that are quoted as labels to stay unambiguous
+var keywords = map[string]bool{
+	"null": true, "true": true, "false": true, "if": true, "for": true, "in": true,
+	"let": true, "import": true, "package": true, "div": true, "mod": true, "quo": true, "rem": true,
+}
+
+// shape is the type inferred from the values of an undeclared field
+type shape struct {
+	count   int
+	kinds   map[string]int
+	objects int
+	fields  map[string]*shape
+	elem    *shape
+}
+
+// add records a value
+func (s *shape) add(v interface{}) {
+	s.count++
+	if s.kinds == nil {
+		s.kinds = make(map[string]int)
+	}
+	s.kinds[kindNames(kindOf(v))]++
+
+	switch d := v.(type) {
+	case map[string]interface{}:
+		s.objects++
+		if s.fields == nil {
+			s.fields = make(map[string]*shape)
+		}
+		for label, value := range d {
+			child := s.fields[label]
+			if child == nil {
+				child = &shape{}
+				s.fields[label] = child
+			}
+			child.add(value)
+		}
+	case []interface{}:
+		if s.elem == nil {
+			s.elem = &shape{}
+		}
+		for _, item := range d {
+			s.elem.add(item)
+		}
+	}
+}
+
+// describe lists the kinds seen, e.g. "string" or "null | int"
+func (s *shape) describe() string {
+	return strings.Join(s.kindList(), " | ")
+}
+
+// kindList returns the CUE types of the kinds seen, null first and ints and
+// floats merged into number
+func (s *shape) kindList() []string {
+	kinds := make(map[string]bool)
+	for k := range s.kinds {
+		kinds[k] = true
+	}
+	if kinds["int"] && kinds["float"] {
+		delete(kinds, "int")
+		delete(kinds, "float")
+		kinds["number"] = true
+	}
+
+	var list []string
+	for _, k := range []string{"null", "bool", "int", "float", "number", "string", "list", "struct"} {
+		if kinds[k] {
+			list = append(list, k)
+		}
+	}
+	return list
+}
+
+// field renders a CUE field declaration for the shape
+func (s *shape) field(label string, optional bool, indent string) string {
+	mark := ""
+	if optional {
+		mark = "?"
+	}
+	return quoteLabel(label) + mark + ": " + s.cueType(indent)
+}
+
+// cueType renders the shape as a CUE type; nested structs are indented
+// below indent
+func (s *shape) cueType(indent string) string {
+	var types []string
+	for _, k := range s.kindList() {
+		switch k {
+		case "list":
+			if s.elem == nil || s.elem.count == 0 {
+				types = append(types, "[...]")
+			} else {
+				types = append(types, "[..."+s.elem.cueType(indent)+"]")
+			}
+		case "struct":
+			types = append(types, s.structType(indent))
+		default:
+			types = append(types, k)
+		}
+	}
+	return strings.Join(types, " | ")
+}
+
+// structType renders the fields seen in objects; fields missing from some
+// objects are optional
+func (s *shape) structType(indent string) string {
+	if len(s.fields) == 0 {
+		return "{...}"
+	}
+	labels := make([]string, 0, len(s.fields))
+	for label := range s.fields {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, label := range labels {
+		child := s.fields[label]
+		b.WriteString(indent + "\t" + child.field(label, child.count < s.objects, indent+"\t") + "\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// quoteLabel quotes a label unless it is an identifier
+func quoteLabel(label string) string {
+	if identifier.MatchString(label) && !keywords[label] {
+		return label
+	}
+	return quote(label)
+}
+
+// quote renders a CUE string literal
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
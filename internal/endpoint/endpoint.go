@@ -0,0 +1,252 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Methods are the HTTP methods @http accepts
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+var (
+	// ErrNotFound reports a path no endpoint matches
+	ErrNotFound = errors.New("no endpoint matches the path")
+
+	// ErrMethodNotAllowed reports a path that only matches other methods
+	ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+// Endpoint is an HTTP endpoint declared with
+// @http(<method>, "<path>", request=#Def, response=#Def, status=<code>) on a
+// definition. The definition is the response body unless response is set,
+// and then the request body unless request is set.
+type Endpoint struct {
+	Method string
+	Path   string
+
+	// Definition is the annotated definition, e.g. "#User"
+	Definition string
+
+	// Request is the definition request bodies must match, if any
+	Request string
+
+	// Response is the definition of the response body
+	Response string
+
+	// Status is the status code of a successful response
+	Status int
+
+	segments []string
+}
+
+// String returns the method and path, e.g. "GET /users/:id"
+func (e Endpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+// HasBody reports whether successful responses carry a body
+func (e Endpoint) HasBody() bool {
+	return e.Status != 204 && e.Status != 304
+}
+
+// Extract collects the endpoints of all definitions annotated with @http,
+// sorted by path and method
+func Extract(schemas cue.Value) ([]Endpoint, error) {
+	iter, err := schemas.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+	seen := make(map[string]string)
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		attr, ok := platoCue.GetAttr(iter.Value(), "http")
+		if !ok {
+			continue
+		}
+		def := iter.Selector().String()
+
+		e, err := parse(schemas, def, attr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", def, err)
+		}
+
+		key := e.Method + " " + e.pattern()
+		if other, dup := seen[key]; dup {
+			return nil, fmt.Errorf("%s is declared by both %s and %s", e, other, def)
+		}
+		seen[key] = def
+
+		endpoints = append(endpoints, e)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints, nil
+}
+
+// parse reads and checks one @http attribute
+func parse(schemas cue.Value, def string, attr platoCue.Attr) (Endpoint, error) {
+	e := Endpoint{
+		Method:     strings.ToUpper(attr.Arg(0)),
+		Path:       attr.Arg(1),
+		Definition: def,
+		Request:    attr.Param("request"),
+		Response:   attr.Param("response"),
+		Status:     200,
+	}
+
+	valid := false
+	for _, m := range Methods {
+		valid = valid || e.Method == m
+	}
+	if !valid {
+		return e, fmt.Errorf("invalid @http method %q (expected one of %s)", attr.Arg(0), strings.Join(Methods, ", "))
+	}
+	if !strings.HasPrefix(e.Path, "/") {
+		return e, fmt.Errorf("invalid @http path %q (must start with /)", e.Path)
+	}
+	e.segments = splitPath(e.Path)
+	for _, seg := range e.segments {
+		if name, ok := param(seg); ok && name == "" {
+			return e, fmt.Errorf("invalid @http path %q (unnamed parameter)", e.Path)
+		}
+	}
+
+	if status := attr.Param("status"); status != "" {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 100 || code > 599 {
+			return e, fmt.Errorf("invalid @http status %q", status)
+		}
+		e.Status = code
+	}
+
+	if e.Response == "" {
+		e.Response = def
+	} else if e.Request == "" {
+		e.Request = def
+	}
+	for _, ref := range []string{e.Request, e.Response} {
+		if ref == "" {
+			continue
+		}
+		if _, err := platoCue.LookupDefinition(schemas, ref); err != nil {
+			return e, fmt.Errorf("@http: %w", err)
+		}
+	}
+
+	return e, nil
+}
+
+// pattern returns the path with unnamed parameters, so /users/:id and
+// /users/{userId} compare equal
+func (e Endpoint) pattern() string {
+	segments := make([]string, len(e.segments))
+	for i, seg := range e.segments {
+		if _, ok := param(seg); ok {
+			seg = ":"
+		}
+		segments[i] = seg
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// Match reports whether a request path matches the endpoint's path,
+// returning the values of its parameters
+func (e Endpoint) Match(path string) (map[string]string, bool) {
+	segments := splitPath(path)
+	if len(segments) != len(e.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, seg := range e.segments {
+		if name, ok := param(seg); ok {
+			params[name] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Expand substitutes the values of path parameters, returning the names of
+// parameters without a value
+func (e Endpoint) Expand(params map[string]string) (string, []string) {
+	var missing []string
+	segments := make([]string, len(e.segments))
+	for i, seg := range e.segments {
+		name, ok := param(seg)
+		if !ok {
+			segments[i] = seg
+			continue
+		}
+		value, set := params[name]
+		if !set {
+			missing = append(missing, name)
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return "/" + strings.Join(segments, "/"), missing
+}
+
+// Find returns the endpoint matching a request and the values of its path
+// parameters. Static segments win over parameters, so /users/me is matched
+// before /users/:id. It returns ErrMethodNotAllowed when only other methods
+// match the path, and ErrNotFound when no endpoint does.
+func Find(endpoints []Endpoint, method, path string) (Endpoint, map[string]string, error) {
+	var best Endpoint
+	var bestParams map[string]string
+	err := ErrNotFound
+	for _, candidate := range endpoints {
+		params, ok := candidate.Match(path)
+		if !ok {
+			continue
+		}
+		if candidate.Method != method {
+			if err == ErrNotFound {
+				err = ErrMethodNotAllowed
+			}
+			continue
+		}
+		if err != nil || len(params) < len(bestParams) {
+			best, bestParams, err = candidate, params, nil
+		}
+	}
+	return best, bestParams, err
+}
+
+// splitPath splits a path into segments, ignoring leading and trailing slashes
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// param returns the name of a :name or {name} path parameter
+func param(seg string) (string, bool) {
+	if strings.HasPrefix(seg, ":") {
+		return seg[1:], true
+	}
+	if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		return seg[1 : len(seg)-1], true
+	}
+	return "", false
+}
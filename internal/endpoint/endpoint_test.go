@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestExtractRequestDefinition(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#User: {
+	@http(GET, "/users/:id")
+	id:    int
+	email: string
+}
+#CreateUser: {
+	@http(POST, "/users", response=#User, status=201)
+	email: string
+}
+#Login: {
+	@http(POST, "/login", request=#Credentials, response=#Session)
+}
+#Credentials: {user: string}
+#Session: {token: string}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints, err := Extract(val)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := map[string][2]string{
+		"GET /users/:id": {"", "#User"},
+		"POST /users":    {"#CreateUser", "#User"},
+		"POST /login":    {"#Credentials", "#Session"},
+	}
+	for _, e := range endpoints {
+		w, ok := want[e.String()]
+		if !ok {
+			t.Errorf("unexpected endpoint %s", e)
+			continue
+		}
+		if e.Request != w[0] || e.Response != w[1] {
+			t.Errorf("%s: request %q, response %q, want %q and %q", e, e.Request, e.Response, w[0], w[1])
+		}
+	}
+	if len(endpoints) != len(want) {
+		t.Errorf("got %d endpoints, want %d", len(endpoints), len(want))
+	}
+}
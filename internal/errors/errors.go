@@ -14,6 +14,9 @@ type Error struct {
 	Column     int
 	Suggestion string
 	Cause      error
+
+	// Code identifies findings of a convention check, e.g. PSL2003
+	Code string
 }
 
 // ErrorType represents the type of error
@@ -69,6 +72,12 @@ func (e *Error) WithLocation(file string, line, column int) *Error {
 	return e
 }
 
+// WithCode sets the code of the check that found the error
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
 // WithSuggestion adds a suggestion
 func (e *Error) WithSuggestion(suggestion string) *Error {
 	e.Suggestion = suggestion
@@ -98,6 +107,9 @@ func (e *Error) Error() string {
 
 	// Message
 	b.WriteString(e.Message)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
 
 	// Cause
 	if e.Cause != nil {
@@ -125,6 +137,9 @@ func (e *Error) Format() string {
 	} else {
 		fmt.Fprintf(&b, "✗ %s", e.Message)
 	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " [%s]", e.Code)
+	}
 
 	// Cause details
 	if e.Cause != nil {
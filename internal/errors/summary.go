@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Group is a count of errors sharing a file or category
+type Group struct {
+	Name  string
+	Count int
+}
+
+// Summary counts errors by file and by category
+type Summary struct {
+	Total      int
+	Files      []Group
+	Categories []Group
+}
+
+// Category classifies an error for grouping, based on its message
+func Category(e *Error) string {
+	text := strings.ToLower(e.Message)
+	if e.Cause != nil {
+		text += " " + strings.ToLower(e.Cause.Error())
+	}
+
+	switch {
+	case strings.Contains(text, "reference") && strings.Contains(text, "not found"):
+		return "unresolved reference"
+	case strings.Contains(text, "conflicting values") || strings.Contains(text, "mismatched types"):
+		return "conflicting values"
+	case strings.Contains(text, "incomplete value") || strings.Contains(text, "non-concrete"):
+		return "incomplete value"
+	case strings.Contains(text, "not allowed"):
+		return "field not allowed"
+	case strings.Contains(text, "invalid value") || strings.Contains(text, "out of bound"):
+		return "constraint violation"
+	case strings.Contains(text, "cycle"):
+		return "structural cycle"
+	case strings.Contains(text, "expected") || strings.Contains(text, "syntax"):
+		return "syntax error"
+	case e.Type != "":
+		return string(e.Type)
+	default:
+		return "other"
+	}
+}
+
+// Summarize counts errors by file and category, most frequent first
+func Summarize(errs []*Error) *Summary {
+	files := make(map[string]int)
+	categories := make(map[string]int)
+	for _, e := range errs {
+		file := e.File
+		if file == "" {
+			file = "(no file)"
+		}
+		files[file]++
+		categories[Category(e)]++
+	}
+
+	return &Summary{
+		Total:      len(errs),
+		Files:      sortedGroups(files),
+		Categories: sortedGroups(categories),
+	}
+}
+
+// SortByFile orders errors by file, with the files that have the most
+// errors first, then by position within each file
+func SortByFile(errs []*Error) {
+	counts := make(map[string]int)
+	for _, e := range errs {
+		counts[e.File]++
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i], errs[j]
+		if a.File != b.File {
+			if counts[a.File] != counts[b.File] {
+				return counts[a.File] > counts[b.File]
+			}
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// WriteJSON writes the full list of errors to path as a JSON array
+func WriteJSON(path string, errs []*Error) error {
+	type jsonError struct {
+		Type       ErrorType `json:"type"`
+		Code       string    `json:"code,omitempty"`
+		Category   string    `json:"category"`
+		File       string    `json:"file,omitempty"`
+		Line       int       `json:"line,omitempty"`
+		Column     int       `json:"column,omitempty"`
+		Message    string    `json:"message"`
+		Cause      string    `json:"cause,omitempty"`
+		Suggestion string    `json:"suggestion,omitempty"`
+	}
+
+	out := make([]jsonError, 0, len(errs))
+	for _, e := range errs {
+		je := jsonError{
+			Type:       e.Type,
+			Code:       e.Code,
+			Category:   Category(e),
+			File:       e.File,
+			Line:       e.Line,
+			Column:     e.Column,
+			Message:    e.Message,
+			Suggestion: e.Suggestion,
+		}
+		if e.Cause != nil {
+			je.Cause = e.Cause.Error()
+		}
+		out = append(out, je)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode errors: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func sortedGroups(counts map[string]int) []Group {
+	groups := make([]Group, 0, len(counts))
+	for name, count := range counts {
+		groups = append(groups, Group{Name: name, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
@@ -0,0 +1,195 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// ExpectFile is the file describing an example's expected outcome
+const ExpectFile = "expect.cue"
+
+// expectSchema constrains expect.cue files
+const expectSchema = `
+definition:   =~"^#"
+valid:        bool
+errors?:      [...string]
+description?: string
+`
+
+// Expect is the expected outcome of validating an example's data file
+type Expect struct {
+	// Definition is the definition the data file is checked against
+	Definition string `json:"definition"`
+
+	// Valid is whether the data file should pass validation
+	Valid bool `json:"valid"`
+
+	// Errors are substrings that must each match at least one validation
+	// error, by message or field path
+	Errors []string `json:"errors,omitempty"`
+
+	// Description is shown next to the example's name
+	Description string `json:"description,omitempty"`
+}
+
+// Example is a subdirectory of the examples directory
+type Example struct {
+	Name     string
+	Dir      string
+	DataFile string
+	Expect   Expect
+
+	// Err is set when the example itself is malformed
+	Err error
+}
+
+// Result is the outcome of running an example
+type Result struct {
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	Definition  string   `json:"definition,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Passed      bool     `json:"passed"`
+	Failures    []string `json:"failures,omitempty"`
+	Errors      []string `json:"errors,omitempty"` // actual validation errors
+}
+
+// Discover finds the examples in dir: every subdirectory with an expect.cue
+// and exactly one data file. Subdirectories without an expect.cue are
+// skipped; malformed examples are returned with Err set so they fail.
+func Discover(dir string) ([]*Example, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read examples directory: %w", err)
+	}
+
+	var examples []*Example
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		exDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(exDir, ExpectFile)); err != nil {
+			continue
+		}
+
+		ex := &Example{Name: entry.Name(), Dir: exDir}
+		examples = append(examples, ex)
+
+		expect, err := loadExpect(filepath.Join(exDir, ExpectFile))
+		if err != nil {
+			ex.Err = err
+			continue
+		}
+		ex.Expect = *expect
+
+		files, err := os.ReadDir(exDir)
+		if err != nil {
+			ex.Err = err
+			continue
+		}
+		var data []string
+		for _, f := range files {
+			if !f.IsDir() && f.Name() != ExpectFile && platoCue.IsDataFile(f.Name()) {
+				data = append(data, f.Name())
+			}
+		}
+		switch len(data) {
+		case 0:
+			ex.Err = fmt.Errorf("no data file next to %s", ExpectFile)
+		case 1:
+			ex.DataFile = filepath.Join(exDir, data[0])
+		default:
+			ex.Err = fmt.Errorf("expected one data file, found %s", strings.Join(data, ", "))
+		}
+	}
+
+	sort.Slice(examples, func(i, j int) bool { return examples[i].Name < examples[j].Name })
+	return examples, nil
+}
+
+// loadExpect reads and checks an expect.cue file
+func loadExpect(path string) (*Expect, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ExpectFile, err)
+	}
+
+	ctx := cuecontext.New()
+	val := ctx.CompileString(expectSchema).Unify(ctx.CompileBytes(data, cue.Filename(path)))
+	if err := val.Validate(cue.Concrete(true)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ExpectFile, err)
+	}
+
+	var expect Expect
+	if err := val.Decode(&expect); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ExpectFile, err)
+	}
+	return &expect, nil
+}
+
+// Run validates an example's data file against the schemas and compares the
+// outcome with its expectations. schemas must come from loader's context.
+func Run(loader *platoCue.Loader, schemas cue.Value, ex *Example) Result {
+	result := Result{
+		Name:        ex.Name,
+		Path:        ex.Dir,
+		Definition:  ex.Expect.Definition,
+		Description: ex.Expect.Description,
+	}
+	fail := func(format string, args ...interface{}) Result {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		return result
+	}
+
+	if ex.Err != nil {
+		return fail("%v", ex.Err)
+	}
+
+	def, err := platoCue.LookupDefinition(schemas, ex.Expect.Definition)
+	if err != nil {
+		return fail("%v", err)
+	}
+	data, err := loader.LoadDataFile(ex.DataFile)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	validation := platoCue.ValidateData(def, data)
+	for _, e := range validation.Errors {
+		result.Errors = append(result.Errors, e.Message)
+	}
+
+	switch {
+	case ex.Expect.Valid && !validation.Valid:
+		fail("expected %s to be valid, got %d error(s)", filepath.Base(ex.DataFile), len(validation.Errors))
+	case !ex.Expect.Valid && validation.Valid:
+		fail("expected %s to be invalid, but it is valid", filepath.Base(ex.DataFile))
+	}
+
+	for _, want := range ex.Expect.Errors {
+		if !matchesAny(want, validation.Errors) {
+			fail("expected an error matching %q", want)
+		}
+	}
+
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// matchesAny reports whether want is a substring of any error's message or path
+func matchesAny(want string, errs []platoCue.ValidationError) bool {
+	for _, e := range errs {
+		if strings.Contains(e.Message, want) || strings.Contains(e.Path, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Summary is the JSON report of a run
+type Summary struct {
+	Total   int      `json:"total"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Results []Result `json:"results"`
+}
+
+// Summarize counts passed and failed results
+func Summarize(results []Result) Summary {
+	s := Summary{Total: len(results), Results: results}
+	for _, r := range results {
+		if r.Passed {
+			s.Passed++
+		} else {
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// WriteTAP writes results in the Test Anything Protocol (version 13), with
+// failures and actual errors as YAML diagnostics
+func WriteTAP(w io.Writer, results []Result) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+
+	for i, r := range results {
+		status := "ok"
+		if !r.Passed {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s", status, i+1, r.Name)
+		if r.Description != "" {
+			fmt.Fprintf(&b, ": %s", r.Description)
+		}
+		b.WriteString("\n")
+
+		if r.Passed {
+			continue
+		}
+		b.WriteString("  ---\n")
+		fmt.Fprintf(&b, "  path: %s\n", yamlString(r.Path))
+		if r.Definition != "" {
+			fmt.Fprintf(&b, "  definition: %s\n", yamlString(r.Definition))
+		}
+		writeYAMLList(&b, "failures", r.Failures)
+		writeYAMLList(&b, "errors", r.Errors)
+		b.WriteString("  ...\n")
+	}
+
+	s := Summarize(results)
+	fmt.Fprintf(&b, "# pass %d\n", s.Passed)
+	fmt.Fprintf(&b, "# fail %d\n", s.Failed)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the summary and results as indented JSON
+func WriteJSON(w io.Writer, results []Result) error {
+	data, err := json.MarshalIndent(Summarize(results), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeYAMLList(b *strings.Builder, key string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "  %s:\n", key)
+	for _, item := range items {
+		fmt.Fprintf(b, "    - %s\n", yamlString(item))
+	}
+}
+
+// yamlString quotes a string for YAML; JSON strings are valid YAML scalars
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,165 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Options configures a fuzzing run
+type Options struct {
+	// Definition is the definition to fuzz, e.g. "#Order"
+	Definition string
+
+	// Targets are the generated validators to compare against CUE
+	Targets []string
+
+	// Iterations is the number of instances to generate
+	Iterations int
+
+	// Seed seeds the instance generator, for reproducible runs
+	Seed int64
+
+	// WorkDir receives the generated harnesses, one subdirectory per target
+	WorkDir string
+}
+
+// Disagreement is an instance on which a target and CUE differ
+type Disagreement struct {
+	Instance string
+	Valid    bool   // CUE's verdict
+	Reason   string // CUE's error for invalid instances
+	Verdicts map[string]Verdict
+}
+
+// TargetStats summarises a target's agreement with CUE
+type TargetStats struct {
+	Name         string
+	Agreed       int
+	FalseAccepts int // invalid per CUE, accepted by the target
+	FalseRejects int // valid per CUE, rejected by the target
+}
+
+// Result is the outcome of a fuzzing run
+type Result struct {
+	Instances     int
+	Valid         int
+	Targets       []TargetStats
+	Disagreements []Disagreement
+}
+
+// Run generates random valid and near-invalid instances of a definition and
+// runs them through the generated validators of each target. CUE is the
+// oracle: every instance it accepts should be accepted by each target, and
+// every instance it rejects should be rejected.
+func Run(schemas cue.Value, cfg *config.Config, opts Options) (*Result, error) {
+	if strings.Contains(strings.TrimPrefix(opts.Definition, "#"), ".") {
+		return nil, fmt.Errorf("only top-level definitions can be fuzzed: %s", opts.Definition)
+	}
+	def, err := platoCue.LookupDefinition(schemas, opts.Definition)
+	if err != nil {
+		return nil, err
+	}
+	typeName := typeNameOf(opts.Definition)
+
+	targets := make([]Target, 0, len(opts.Targets))
+	for _, name := range opts.Targets {
+		target, err := NewTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := target.Prepare(filepath.Join(opts.WorkDir, name), schemas, cfg, typeName); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		targets = append(targets, target)
+	}
+
+	// Generate instances: alternate valid and mutated ones
+	gen := &instanceGenerator{rnd: rand.New(rand.NewSource(opts.Seed))}
+	instances := make([][]byte, 0, opts.Iterations)
+	expected := make([]*platoCue.ValidationResult, 0, opts.Iterations)
+	for i := 0; i < opts.Iterations; i++ {
+		instance := gen.valid(def, "", 0)
+		if i%2 == 1 {
+			instance = gen.mutate(instance)
+		}
+		data, err := encode(instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode instance: %w", err)
+		}
+		instances = append(instances, data)
+		expected = append(expected, platoCue.ValidateData(def, def.Context().CompileBytes(data)))
+	}
+
+	result := &Result{Instances: len(instances)}
+	for _, e := range expected {
+		if e.Valid {
+			result.Valid++
+		}
+	}
+
+	disagreements := make(map[int]*Disagreement)
+	for _, target := range targets {
+		verdicts, err := target.Run(filepath.Join(opts.WorkDir, target.Name()), instances)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", target.Name(), err)
+		}
+
+		stats := TargetStats{Name: target.Name()}
+		for i, verdict := range verdicts {
+			switch {
+			case verdict.Valid == expected[i].Valid:
+				stats.Agreed++
+				continue
+			case verdict.Valid:
+				stats.FalseAccepts++
+			default:
+				stats.FalseRejects++
+			}
+
+			d := disagreements[i]
+			if d == nil {
+				d = &Disagreement{
+					Instance: string(instances[i]),
+					Valid:    expected[i].Valid,
+					Verdicts: make(map[string]Verdict),
+				}
+				if len(expected[i].Errors) > 0 {
+					d.Reason = expected[i].Errors[0].Message
+				}
+				disagreements[i] = d
+			}
+			d.Verdicts[target.Name()] = verdict
+		}
+		result.Targets = append(result.Targets, stats)
+	}
+
+	for i := range instances {
+		if d, ok := disagreements[i]; ok {
+			result.Disagreements = append(result.Disagreements, *d)
+		}
+	}
+	return result, nil
+}
+
+// typeNameOf returns the name the generators give a definition's type
+func typeNameOf(definition string) string {
+	name := strings.TrimPrefix(definition, "#")
+	if len(name) > 0 {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
+
+// unquote decodes a JSON string literal
+func unquote(s string) (string, error) {
+	var out string
+	err := json.Unmarshal([]byte(s), &out)
+	return out, err
+}
@@ -0,0 +1,451 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"cuelang.org/go/cue"
+)
+
+// maxDepth bounds recursion into nested structs and lists
+const maxDepth = 6
+
+// stringPool holds candidate strings tried against string constraints. The
+// first candidate satisfying the constraint is used for valid instances.
+var stringPool = []string{
+	"hello", "a", "x-1", "hello-world", "Hello World", "abc123", "ABC",
+	"user@example.com", "https://example.com/path", "2024-01-31",
+	"2024-01-31T12:00:00Z", "550e8400-e29b-41d4-a716-446655440000",
+	"über", "日本語", " padded ", "with\nnewline", "0", "",
+}
+
+// instanceGenerator produces random instances of CUE definitions
+type instanceGenerator struct {
+	rnd     *rand.Rand
+	profile *Profile // value distributions to follow, if set
+}
+
+// Instance returns a random instance of val, for fixtures and mock data.
+// Like the valid instances of a fuzzing run it is valid in most cases but not
+// always, so check it with CUE before relying on it.
+func Instance(val cue.Value, rnd *rand.Rand) interface{} {
+	g := &instanceGenerator{rnd: rnd}
+	return g.valid(val, "", 0)
+}
+
+// ValidInstance returns the JSON of a random instance of val that CUE
+// accepts, trying up to attempts instances. ok is false if none was valid,
+// in which case the last one is returned.
+func ValidInstance(val cue.Value, rnd *rand.Rand, attempts int) ([]byte, bool, error) {
+	return validInstance(val, &instanceGenerator{rnd: rnd}, attempts)
+}
+
+func validInstance(val cue.Value, g *instanceGenerator, attempts int) (data []byte, ok bool, err error) {
+	for i := 0; i < attempts; i++ {
+		data, err = encode(g.valid(val, "", 0))
+		if err != nil {
+			return nil, false, err
+		}
+		if accepts(val, json.RawMessage(data)) {
+			return data, true, nil
+		}
+	}
+	return data, false, nil
+}
+
+// Encode marshals an instance as JSON; see encode
+func Encode(v interface{}) ([]byte, error) {
+	return encode(v)
+}
+
+// valid returns an instance of val that is valid in most cases. Constraints
+// are satisfied by filtering candidates through CUE, so validity is not
+// guaranteed; callers decide validity with the CUE oracle. path locates
+// val in the instance, for the statistics of a profile.
+func (g *instanceGenerator) valid(val cue.Value, path string, depth int) interface{} {
+	// Follow the data: draw a kind as often as it occurs at the path, and a
+	// scalar from its distribution
+	kind := cue.BottomKind
+	if f := g.profile.field(path); f != nil {
+		kind = f.drawKind(g.rnd)
+		if v, ok := g.learned(val, f, kind); ok {
+			return v
+		}
+	}
+
+	if op, args := val.Expr(); op == cue.OrOp && len(args) > 1 {
+		if kind != cue.BottomKind {
+			if matching := branchesOf(args, kind); len(matching) > 0 {
+				return g.valid(matching[g.rnd.Intn(len(matching))], path, depth)
+			}
+		}
+		// Prefer the default now and then, like real data
+		if def, ok := val.Default(); ok && g.rnd.Intn(3) == 0 {
+			if v, ok := concrete(def); ok {
+				return v
+			}
+		}
+		return g.valid(args[g.rnd.Intn(len(args))], path, depth)
+	}
+
+	if v, ok := concrete(val); ok {
+		return v
+	}
+
+	switch kind := val.IncompleteKind(); {
+	case kind&cue.StructKind != 0 && depth < maxDepth:
+		return g.validStruct(val, path, depth)
+	case kind&cue.ListKind != 0 && depth < maxDepth:
+		return g.validList(val, path, depth)
+	case kind&cue.StringKind != 0:
+		return g.pick(val, g.stringCandidates())
+	case kind&cue.IntKind != 0:
+		return g.pick(val, g.intCandidates())
+	case kind&cue.FloatKind != 0:
+		return g.pick(val, g.floatCandidates())
+	case kind&cue.BoolKind != 0:
+		return g.rnd.Intn(2) == 0
+	case kind&cue.NullKind != 0:
+		return nil
+	default:
+		return g.anyScalar()
+	}
+}
+
+// validStruct generates required fields and about half of the optional
+// ones, or as many as are present in the data of a profile
+func (g *instanceGenerator) validStruct(val cue.Value, path string, depth int) interface{} {
+	obj := make(map[string]interface{})
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return obj
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		if iter.IsOptional() && !g.include(fieldPath(path, label), path) {
+			continue
+		}
+		obj[label] = g.valid(iter.Value(), fieldPath(path, label), depth+1)
+	}
+	return obj
+}
+
+// validList generates up to three elements, or as many as the lists in the
+// data of a profile, retrying list length constraints
+func (g *instanceGenerator) validList(val cue.Value, path string, depth int) interface{} {
+	elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+
+	var best []interface{}
+	for attempt := 0; attempt < 4; attempt++ {
+		items := []interface{}{}
+		if elem.Exists() {
+			for n := g.length(path); n > 0; n-- {
+				items = append(items, g.valid(elem, path+"[]", depth+1))
+			}
+		}
+		best = items
+		if accepts(val, items) {
+			break
+		}
+	}
+	return best
+}
+
+// pick returns the first candidate accepted by val, or a random candidate
+func (g *instanceGenerator) pick(val cue.Value, candidates []interface{}) interface{} {
+	for _, c := range candidates {
+		if accepts(val, c) {
+			return c
+		}
+	}
+	return candidates[g.rnd.Intn(len(candidates))]
+}
+
+// stringCandidates returns the string pool in random order, after a few
+// random identifiers of varying length
+func (g *instanceGenerator) stringCandidates() []interface{} {
+	var candidates []interface{}
+	for _, n := range []int{1 + g.rnd.Intn(8), 8 + g.rnd.Intn(24), 64 + g.rnd.Intn(200)} {
+		candidates = append(candidates, g.word(n))
+	}
+	for _, i := range g.rnd.Perm(len(stringPool)) {
+		candidates = append(candidates, stringPool[i])
+	}
+	return candidates
+}
+
+// intCandidates returns small, boundary and random integers
+func (g *instanceGenerator) intCandidates() []interface{} {
+	candidates := []interface{}{
+		int64(g.rnd.Intn(100)), int64(g.rnd.Intn(100000)), int64(-g.rnd.Intn(1000)),
+	}
+	for _, n := range []int64{0, 1, -1, 255, 256, 65535, math.MaxInt32, math.MinInt32, math.MaxInt64} {
+		candidates = append(candidates, n)
+	}
+	return candidates
+}
+
+// floatCandidates returns floats that always carry a fractional part, so
+// their JSON encoding cannot be mistaken for an integer
+func (g *instanceGenerator) floatCandidates() []interface{} {
+	return []interface{}{
+		float64(g.rnd.Intn(1000)) + 0.5, g.rnd.Float64(), -g.rnd.Float64() * 1000,
+		0.5, -0.5, 1e-9, 1.5e12,
+	}
+}
+
+// anyScalar returns a value of a random kind for unconstrained fields
+func (g *instanceGenerator) anyScalar() interface{} {
+	switch g.rnd.Intn(4) {
+	case 0:
+		return g.word(6)
+	case 1:
+		return int64(g.rnd.Intn(1000))
+	case 2:
+		return g.rnd.Intn(2) == 0
+	default:
+		return nil
+	}
+}
+
+// word returns a random lowercase identifier of length n
+func (g *instanceGenerator) word(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.rnd.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// mutate returns a near-invalid copy of a valid instance: one random field is
+// dropped, retyped, pushed past a boundary, or an unknown field is added.
+func (g *instanceGenerator) mutate(instance interface{}) interface{} {
+	copied := deepCopy(instance)
+
+	// Collect mutable locations
+	var targets []location
+	collect(copied, nil, &targets)
+	if len(targets) == 0 {
+		return copied
+	}
+	loc := targets[g.rnd.Intn(len(targets))]
+
+	switch g.rnd.Intn(4) {
+	case 0:
+		if obj, ok := loc.value.(map[string]interface{}); ok {
+			obj["unexpected_"+g.word(4)] = g.anyScalar()
+			return copied
+		}
+		fallthrough
+	case 1:
+		if loc.parent != nil && loc.key != "" {
+			delete(loc.parent, loc.key)
+			return copied
+		}
+		fallthrough
+	case 2:
+		return loc.set(copied, g.retype(loc.value))
+	default:
+		return loc.set(copied, g.nudge(loc.value))
+	}
+}
+
+// retype replaces a value with one of a different JSON type
+func (g *instanceGenerator) retype(v interface{}) interface{} {
+	switch v.(type) {
+	case string:
+		return int64(g.rnd.Intn(100))
+	case int64, float64:
+		return strconv.Itoa(g.rnd.Intn(100))
+	case bool:
+		return "true"
+	case nil:
+		return false
+	case []interface{}:
+		return map[string]interface{}{}
+	default:
+		return []interface{}{}
+	}
+}
+
+// nudge moves a value just past a likely constraint boundary
+func (g *instanceGenerator) nudge(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		options := []string{"", x + " ", x + "\u0000", x + g.word(300), "UPPER" + x}
+		return options[g.rnd.Intn(len(options))]
+	case int64:
+		options := []interface{}{x + 1, x - 1, -x, float64(x) + 0.5, x * 1000}
+		return options[g.rnd.Intn(len(options))]
+	case float64:
+		options := []interface{}{x + 1, -x, 0.0, x * 1e6, math.MaxFloat64}
+		return options[g.rnd.Intn(len(options))]
+	case bool:
+		return !x
+	case []interface{}:
+		if len(x) > 0 {
+			return append(x, x[0])
+		}
+		return append(x, g.anyScalar())
+	default:
+		return nil
+	}
+}
+
+// location addresses a value inside an instance for mutation
+type location struct {
+	path   []interface{} // string keys and int indexes from the root
+	parent map[string]interface{}
+	key    string
+	value  interface{}
+}
+
+// set replaces the value at the location and returns the (possibly new) root
+func (l location) set(root, v interface{}) interface{} {
+	if len(l.path) == 0 {
+		return v
+	}
+	container := root
+	for _, step := range l.path[:len(l.path)-1] {
+		container = child(container, step)
+	}
+	switch last := l.path[len(l.path)-1].(type) {
+	case string:
+		container.(map[string]interface{})[last] = v
+	case int:
+		container.([]interface{})[last] = v
+	}
+	return root
+}
+
+// collect lists every location in an instance, root first
+func collect(v interface{}, path []interface{}, out *[]location) {
+	*out = append(*out, location{path: path, value: v})
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := append(append([]interface{}{}, path...), k)
+			n := len(*out)
+			collect(x[k], childPath, out)
+			(*out)[n].parent = x
+			(*out)[n].key = k
+		}
+	case []interface{}:
+		for i, item := range x {
+			collect(item, append(append([]interface{}{}, path...), i), out)
+		}
+	}
+}
+
+// child steps into a map key or list index
+func child(v interface{}, step interface{}) interface{} {
+	switch s := step.(type) {
+	case string:
+		return v.(map[string]interface{})[s]
+	case int:
+		return v.([]interface{})[s]
+	}
+	return nil
+}
+
+// deepCopy copies maps and lists of an instance
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, item := range x {
+			l[i] = deepCopy(item)
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// concrete decodes a concrete scalar value
+func concrete(val cue.Value) (interface{}, bool) {
+	if !val.IsConcrete() {
+		return nil, false
+	}
+	switch val.Kind() {
+	case cue.StringKind:
+		s, err := val.String()
+		return s, err == nil
+	case cue.IntKind:
+		i, err := val.Int64()
+		return i, err == nil
+	case cue.FloatKind:
+		f, err := val.Float64()
+		return f, err == nil
+	case cue.BoolKind:
+		b, err := val.Bool()
+		return b, err == nil
+	case cue.NullKind:
+		return nil, true
+	}
+	return nil, false
+}
+
+// accepts reports whether val accepts a generated value
+func accepts(val cue.Value, v interface{}) bool {
+	data, err := encode(v)
+	if err != nil {
+		return false
+	}
+	return val.Unify(val.Context().CompileBytes(data)).Validate() == nil
+}
+
+// encode marshals an instance as JSON. Floats always keep a decimal point so
+// they are not read back as integers.
+func encode(v interface{}) ([]byte, error) {
+	return json.Marshal(jsonValue(v))
+}
+
+// jsonValue converts floats to json.Number with an explicit fraction
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case float64:
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return x
+		}
+		s := strconv.FormatFloat(x, 'g', -1, 64)
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			s += ".0"
+		}
+		return json.Number(s)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			m[k] = jsonValue(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, item := range x {
+			l[i] = jsonValue(item)
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,343 @@
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// minFrequency is how often a string must occur in a corpus before it is
+// reproduced in generated data. Rarer strings, such as names and emails,
+// only shape the length of generated strings, so fixtures do not leak
+// individual records.
+const minFrequency = 5
+
+// maxDistinct bounds the distinct strings counted per field
+const maxDistinct = 1000
+
+// maxDecimals bounds the precision of generated floats
+const maxDecimals = 6
+
+// maxLearnedItems bounds the length of lists generated from a profile
+const maxLearnedItems = 50
+
+// learnedAttempts bounds the values drawn from a distribution until one is
+// accepted by the schema, before falling back to the usual candidates
+const learnedAttempts = 5
+
+// Profile holds value distributions learned from sample data: the kinds of
+// values at each path, how often optional fields are present, string lengths
+// and frequent strings, numeric ranges and list lengths. Instances generated
+// with a profile statistically resemble the sample, and still have to pass
+// the schema, so constraints win over the data.
+type Profile struct {
+	records int
+	fields  map[string]*fieldStats
+}
+
+// fieldStats are the values seen at one path, e.g. items[].sku
+type fieldStats struct {
+	count   int // values of any kind
+	nulls   int
+	trues   int
+	falses  int
+	objects int
+	strings stringStats
+	numbers numberStats
+	lists   numberStats // lengths
+}
+
+// stringStats counts string values and their lengths
+type stringStats struct {
+	count    int
+	values   map[string]int
+	lengths  numberStats
+	frequent []string // values seen at least minFrequency times, sorted; nil when stale
+}
+
+// numberStats tracks the range, mean and variance of numbers (Welford)
+type numberStats struct {
+	count    int
+	integers int
+	decimals int
+	min, max float64
+	mean, m2 float64
+}
+
+// NewProfile creates an empty profile
+func NewProfile() *Profile {
+	return &Profile{fields: make(map[string]*fieldStats)}
+}
+
+// Records returns the number of records added
+func (p *Profile) Records() int {
+	return p.records
+}
+
+// Add learns from a record decoded from JSON
+func (p *Profile) Add(record interface{}) {
+	p.records++
+	p.observe("", record)
+}
+
+// AddFile learns from the records of a corpus file: a JSON array of records,
+// or one or more JSON values separated by whitespace, e.g. NDJSON
+func (p *Profile) AddFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []interface{}
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		for _, r := range records {
+			p.Add(r)
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for n := 1; ; n++ {
+		var record interface{}
+		if err := dec.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("record %d: invalid JSON: %w", n, err)
+		}
+		p.Add(record)
+	}
+}
+
+// observe counts a value and the values nested in it
+func (p *Profile) observe(path string, v interface{}) {
+	f, ok := p.fields[path]
+	if !ok {
+		f = &fieldStats{}
+		p.fields[path] = f
+	}
+	f.count++
+
+	switch x := v.(type) {
+	case nil:
+		f.nulls++
+	case bool:
+		if x {
+			f.trues++
+		} else {
+			f.falses++
+		}
+	case float64:
+		f.numbers.add(x)
+	case string:
+		f.strings.add(x)
+	case []interface{}:
+		f.lists.add(float64(len(x)))
+		for _, item := range x {
+			p.observe(path+"[]", item)
+		}
+	case map[string]interface{}:
+		f.objects++
+		for k, item := range x {
+			p.observe(fieldPath(path, k), item)
+		}
+	}
+}
+
+// field returns the statistics of a path, nil without a profile or data
+func (p *Profile) field(path string) *fieldStats {
+	if p == nil {
+		return nil
+	}
+	return p.fields[path]
+}
+
+// fieldPath returns the path of a field of a struct at path
+func fieldPath(path, label string) string {
+	if path == "" {
+		return label
+	}
+	return path + "." + label
+}
+
+// Instance returns a random instance of val shaped after the profile; see
+// Instance. A nil profile generates the same instances as Instance.
+func (p *Profile) Instance(val cue.Value, rnd *rand.Rand) interface{} {
+	g := &instanceGenerator{rnd: rnd, profile: p}
+	return g.valid(val, "", 0)
+}
+
+// ValidInstance returns the JSON of a random instance of val shaped after the
+// profile that CUE accepts; see ValidInstance
+func (p *Profile) ValidInstance(val cue.Value, rnd *rand.Rand, attempts int) ([]byte, bool, error) {
+	return validInstance(val, &instanceGenerator{rnd: rnd, profile: p}, attempts)
+}
+
+func (s *stringStats) add(v string) {
+	s.count++
+	s.lengths.add(float64(len([]rune(v))))
+	if s.values == nil {
+		s.values = make(map[string]int)
+	}
+	if _, ok := s.values[v]; ok || len(s.values) < maxDistinct {
+		s.values[v]++
+		s.frequent = nil
+	}
+}
+
+// draw returns a frequent value with its observed probability, or else a
+// random word of an observed length
+func (s *stringStats) draw(g *instanceGenerator) string {
+	if s.frequent == nil {
+		s.frequent = []string{}
+		for v, n := range s.values {
+			if n >= minFrequency {
+				s.frequent = append(s.frequent, v)
+			}
+		}
+		sort.Strings(s.frequent)
+	}
+
+	r := g.rnd.Intn(s.count)
+	for _, v := range s.frequent {
+		if r < s.values[v] {
+			return v
+		}
+		r -= s.values[v]
+	}
+	return g.word(int(math.Round(s.lengths.draw(g.rnd))))
+}
+
+func (s *numberStats) add(x float64) {
+	if s.count == 0 || x < s.min {
+		s.min = x
+	}
+	if s.count == 0 || x > s.max {
+		s.max = x
+	}
+	s.count++
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+
+	if x == math.Trunc(x) {
+		s.integers++
+	} else if _, frac, ok := strings.Cut(strconv.FormatFloat(x, 'f', -1, 64), "."); ok {
+		s.decimals = min(max(s.decimals, len(frac)), maxDecimals)
+	}
+}
+
+// draw returns a number from a normal distribution with the observed mean
+// and deviation, clamped to the observed range
+func (s *numberStats) draw(rnd *rand.Rand) float64 {
+	x := s.mean
+	if s.count > 1 {
+		x += rnd.NormFloat64() * math.Sqrt(s.m2/float64(s.count-1))
+	}
+	return math.Max(s.min, math.Min(s.max, x))
+}
+
+// drawKind draws the kind of a value with its observed probability
+func (f *fieldStats) drawKind(rnd *rand.Rand) cue.Kind {
+	weights := []struct {
+		kind cue.Kind
+		n    int
+	}{
+		{cue.NullKind, f.nulls},
+		{cue.BoolKind, f.trues + f.falses},
+		{cue.NumberKind, f.numbers.count},
+		{cue.StringKind, f.strings.count},
+		{cue.ListKind, f.lists.count},
+		{cue.StructKind, f.objects},
+	}
+	r := rnd.Intn(f.count)
+	for _, w := range weights {
+		if r < w.n {
+			return w.kind
+		}
+		r -= w.n
+	}
+	return cue.BottomKind
+}
+
+// learned draws a scalar of the given kind from the statistics of a field,
+// reporting false when none of the draws is accepted by val. Lists and
+// structs are generated field by field instead.
+func (g *instanceGenerator) learned(val cue.Value, f *fieldStats, kind cue.Kind) (interface{}, bool) {
+	for attempt := 0; attempt < learnedAttempts; attempt++ {
+		var candidates []interface{}
+		switch kind {
+		case cue.NullKind:
+			candidates = []interface{}{nil}
+		case cue.BoolKind:
+			candidates = []interface{}{g.rnd.Intn(f.trues+f.falses) < f.trues}
+		case cue.NumberKind:
+			x := f.numbers.draw(g.rnd)
+			if f.numbers.integers == f.numbers.count {
+				candidates = []interface{}{int64(math.Round(x)), math.Round(x)}
+			} else {
+				scale := math.Pow(10, float64(f.numbers.decimals))
+				candidates = []interface{}{math.Round(x*scale) / scale}
+			}
+		case cue.StringKind:
+			candidates = []interface{}{f.strings.draw(g)}
+		default:
+			return nil, false
+		}
+		for _, c := range candidates {
+			if accepts(val, c) {
+				return c, true
+			}
+		}
+		if kind == cue.NullKind {
+			break
+		}
+	}
+	return nil, false
+}
+
+// include decides whether to generate an optional field: as often as it is
+// present in the data, or half of the time without data about its struct
+func (g *instanceGenerator) include(path, parent string) bool {
+	p := g.profile.field(parent)
+	if p == nil || p.objects == 0 {
+		return g.rnd.Intn(2) == 0
+	}
+	present := 0
+	if f := g.profile.field(path); f != nil {
+		present = f.count
+	}
+	return g.rnd.Intn(p.objects) < present
+}
+
+// length returns the number of elements of a generated list: as long as the
+// lists in the data, or up to three without data
+func (g *instanceGenerator) length(path string) int {
+	f := g.profile.field(path)
+	if f == nil || f.lists.count == 0 {
+		return g.rnd.Intn(4)
+	}
+	return min(int(math.Round(f.lists.draw(g.rnd))), maxLearnedItems)
+}
+
+// branchesOf returns the branches of a disjunction that allow a kind
+func branchesOf(args []cue.Value, kind cue.Kind) []cue.Value {
+	var matching []cue.Value
+	for _, arg := range args {
+		if arg.IncompleteKind()&kind != 0 {
+			matching = append(matching, arg)
+		}
+	}
+	return matching
+}
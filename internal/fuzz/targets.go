@@ -0,0 +1,243 @@
+package fuzz
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Verdict is a target's opinion on the validity of one instance
+type Verdict struct {
+	Valid  bool
+	Reason string
+}
+
+// Target runs instances through the validators generated for one language
+type Target interface {
+	// Name returns the target name as given on the command line
+	Name() string
+
+	// Prepare generates the validator and harness into dir
+	Prepare(dir string, schemas cue.Value, cfg *config.Config, typeName string) error
+
+	// Run validates instances, one JSON document per element
+	Run(dir string, instances [][]byte) ([]Verdict, error)
+}
+
+// Targets lists the supported target names
+var Targets = []string{"go", "zod"}
+
+// NewTarget returns the target with the given name
+func NewTarget(name string) (Target, error) {
+	switch name {
+	case "go":
+		return &goTarget{}, nil
+	case "zod":
+		return &zodTarget{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported target %q (supported: %s)", name, strings.Join(Targets, ", "))
+	}
+}
+
+// goTarget decodes instances into the structs of the go generator with
+// unknown fields disallowed
+type goTarget struct{}
+
+func (t *goTarget) Name() string { return "go" }
+
+func (t *goTarget) Prepare(dir string, schemas cue.Value, cfg *config.Config, typeName string) error {
+	code, err := generate("go", schemas, cfg, map[string]interface{}{"package": "main"})
+	if err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"go.mod":   "module fuzzharness\n\ngo 1.21\n",
+		"types.go": string(code),
+		"main.go":  fmt.Sprintf(goHarness, typeName),
+	}
+	if err := writeFiles(dir, files); err != nil {
+		return err
+	}
+
+	// Build once up front so compile errors are reported as such
+	cmd := exec.Command("go", "build", "-o", "harness", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("generated Go code does not compile: %w\n%s", err, out)
+	}
+	return nil
+}
+
+func (t *goTarget) Run(dir string, instances [][]byte) ([]Verdict, error) {
+	return runHarness(exec.Command(filepath.Join(dir, "harness")), instances)
+}
+
+const goHarness = `package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	for scanner.Scan() {
+		var v %s
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&v); err != nil {
+			msg, _ := json.Marshal(err.Error())
+			fmt.Fprintf(out, "0 %%s\n", msg)
+			continue
+		}
+		fmt.Fprintln(out, "1")
+	}
+}
+`
+
+// zodTarget parses instances with the schemas of the zod generator in node.
+// zod is resolved from the project's node_modules.
+type zodTarget struct{}
+
+func (t *zodTarget) Name() string { return "zod" }
+
+func (t *zodTarget) Prepare(dir string, schemas cue.Value, cfg *config.Config, typeName string) error {
+	if _, err := exec.LookPath("node"); err != nil {
+		return fmt.Errorf("node is required for the zod target: %w", err)
+	}
+
+	code, err := generate("zod", schemas, cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	return writeFiles(dir, map[string]string{
+		"schemas.mjs": stripTypeExports(string(code)),
+		"harness.mjs": fmt.Sprintf(zodHarness, typeName),
+	})
+}
+
+func (t *zodTarget) Run(dir string, instances [][]byte) ([]Verdict, error) {
+	cmd := exec.Command("node", "harness.mjs")
+	cmd.Dir = dir
+	return runHarness(cmd, instances)
+}
+
+const zodHarness = `import { createInterface } from 'node:readline';
+import { %[1]sSchema } from './schemas.mjs';
+
+const lines = createInterface({ input: process.stdin, crlfDelay: Infinity });
+for await (const line of lines) {
+  const result = %[1]sSchema.safeParse(JSON.parse(line));
+  if (result.success) {
+    console.log('1');
+  } else {
+    const issue = result.error.issues[0];
+    console.log('0 ' + JSON.stringify(issue.path.join('.') + ': ' + issue.message));
+  }
+}
+`
+
+// stripTypeExports drops TypeScript-only "export type" lines so the zod
+// module runs in node without a TypeScript toolchain
+func stripTypeExports(code string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(code, "\n") {
+		if strings.HasPrefix(line, "export type ") {
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// generate runs a registered generator over the schemas
+func generate(name string, schemas cue.Value, cfg *config.Config, opts map[string]interface{}) ([]byte, error) {
+	gen, err := generator.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	genCfg := cfg.Generate[name]
+	ctx := generator.NewContext(schemas, cfg, genCfg)
+	for k, v := range opts {
+		ctx.Options[k] = v
+	}
+
+	if err := gen.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("%s generator validation failed: %w", name, err)
+	}
+	code, err := gen.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s generation failed: %w", name, err)
+	}
+	return code, nil
+}
+
+// writeFiles writes harness files into dir
+func writeFiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// runHarness feeds instances as JSON lines to a harness process and parses
+// one verdict line ("1" or "0 <json reason>") per instance
+func runHarness(cmd *exec.Cmd, instances [][]byte) ([]Verdict, error) {
+	var input bytes.Buffer
+	for _, inst := range instances {
+		input.Write(inst)
+		input.WriteByte('\n')
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdin = &input
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("harness failed: %w\n%s", err, stderr.String())
+	}
+
+	var verdicts []Verdict
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "1" {
+			verdicts = append(verdicts, Verdict{Valid: true})
+			continue
+		}
+		v := Verdict{Reason: strings.TrimPrefix(line, "0 ")}
+		if reason, err := unquote(v.Reason); err == nil {
+			v.Reason = reason
+		}
+		verdicts = append(verdicts, v)
+	}
+
+	if len(verdicts) != len(instances) {
+		return nil, fmt.Errorf("harness returned %d verdicts for %d instances", len(verdicts), len(instances))
+	}
+	return verdicts, nil
+}
@@ -0,0 +1,549 @@
+package absinthe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Absinthe GraphQL type notation from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Absinthe generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "elixir-absinthe"
+}
+
+var (
+	// moduleName matches Elixir module names such as MyAppWeb.Schema.Types
+	moduleName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*(\.[A-Z][A-Za-z0-9_]*)*$`)
+
+	// identifier matches names that are atoms and GraphQL names as they are
+	identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// object is an object and, for inputs, an input_object: a struct
+// definition, or a nested struct of one, which gets a type of its own
+type object struct {
+	ident  string // snake_case identifier, e.g. order_item
+	doc    string
+	fields []field
+}
+
+// field is a field of an object
+type field struct {
+	ident    string // snake_case identifier
+	name     string // GraphQL name when the identifier does not camelize to it
+	doc      string
+	typ      string // type of the object field, e.g. non_null(:string)
+	input    string // type of the input field
+	def      string // default value of the input field
+	required bool
+}
+
+// enum is an enum type of string literals
+type enum struct {
+	ident  string
+	doc    string
+	values []string
+}
+
+// renderer collects the types of definitions. Definitions other than
+// structs and string enums, such as constrained strings, are inlined where
+// they are used.
+type renderer struct {
+	objects   map[string]string // object identifiers by CUE definition name
+	enums     map[string]string // enum identifiers by CUE definition name
+	used      map[string]bool
+	types     []*object
+	enumTypes []*enum
+	json      bool // whether the :json scalar is used
+}
+
+// Generate generates a module of Absinthe object, input_object and enum
+// types, one per definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	module := ctx.GetStringOption("module", "MyAppWeb.Schema.Types")
+	inputs := ctx.GetBoolOption("inputs", true)
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{objects: make(map[string]string), enums: make(map[string]string), used: make(map[string]bool)}
+	for _, name := range cueNames {
+		switch val := defs[name]; {
+		case isObject(val):
+			r.objects[name] = generator.UniqueName(r.used, toSnakeCase(name), "")
+		case len(stringEnum(val)) > 0:
+			r.enums[name] = generator.UniqueName(r.used, toSnakeCase(name), "")
+		}
+	}
+	for _, name := range cueNames {
+		val := defs[name]
+		if ident, ok := r.enums[name]; ok {
+			r.enumTypes = append(r.enumTypes, &enum{ident: ident, doc: platoCue.Description(val), values: stringEnum(val)})
+		}
+	}
+	for _, name := range cueNames {
+		if ident, ok := r.objects[name]; ok {
+			r.object(ident, platoCue.Description(defs[name]), defs[name])
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "defmodule %s do\n", module)
+	buf.WriteString("  @moduledoc \"\"\"\n  GraphQL types of the CUE definitions. Import them into a schema with\n")
+	fmt.Fprintf(&buf, "  `import_types %s`.\n  \"\"\"\n\n", module)
+	buf.WriteString("  use Absinthe.Schema.Notation\n")
+
+	if r.json {
+		buf.WriteString("\n")
+		writeJSONScalar(&buf)
+	}
+	for _, e := range r.enumTypes {
+		buf.WriteString("\n")
+		writeEnum(&buf, e)
+	}
+	for _, o := range r.types {
+		buf.WriteString("\n")
+		writeObject(&buf, o, false)
+	}
+	if inputs {
+		for _, o := range r.types {
+			buf.WriteString("\n")
+			writeObject(&buf, o, true)
+		}
+	}
+	buf.WriteString("end\n")
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if module := ctx.GetStringOption("module", "MyAppWeb.Schema.Types"); !moduleName.MatchString(module) {
+		return fmt.Errorf("invalid module name %q (expected an Elixir module name such as MyAppWeb.Schema.Types)", module)
+	}
+	return nil
+}
+
+// isObject reports whether a definition is a struct with fields, which
+// becomes an object
+func isObject(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// object adds the object of a struct, followed by the objects of its
+// nested structs. Fields are non-null unless they are optional or may be
+// null; input fields with a default are nullable and get the default.
+func (r *renderer) object(ident, doc string, val cue.Value) {
+	o := &object{ident: ident, doc: doc}
+	r.types = append(r.types, o)
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		f := field{ident: fieldIdent(label), doc: platoCue.Description(fieldVal)}
+		if camelize(f.ident) != label {
+			f.name = label
+		}
+		typ, input, nullable := r.fieldType(ident, label, fieldVal)
+		f.typ, f.input = typ, input
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			f.def = elixirValue(def)
+		}
+		f.required = !iter.IsOptional() && !nullable
+		o.fields = append(o.fields, f)
+	}
+}
+
+// fieldType maps a value to the identifiers of its object and input types,
+// reporting whether it may be null
+func (r *renderer) fieldType(parent, label string, val cue.Value) (string, string, bool) {
+	if ident, ok := r.reference(val, r.objects); ok {
+		return ":" + ident, ":" + ident + "_input", false
+	}
+	if ident, ok := r.reference(val, r.enums); ok {
+		return ":" + ident, ":" + ident, false
+	}
+	if values := stringEnum(val); len(values) > 0 {
+		if ident, ok := r.enumReference(val, values); ok {
+			return ":" + ident, ":" + ident, false
+		}
+		ident := generator.UniqueName(r.used, parent+"_"+toSnakeCase(label), "")
+		r.enumTypes = append(r.enumTypes, &enum{ident: ident, values: values})
+		return ":" + ident, ":" + ident, false
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		r.json = true
+		return ":json", ":json", nullable
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if ident, ok := r.reference(val, r.objects); ok {
+		return ":" + ident, ":" + ident + "_input", nullable
+	}
+	if ident, ok := r.reference(val, r.enums); ok {
+		return ":" + ident, ":" + ident, nullable
+	}
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		// Definitions other than structs and enums are inlined
+		typ, input, null := r.fieldType(parent, label, cue.Dereference(val))
+		return typ, input, nullable || null
+	}
+
+	typ, input := r.base(parent, label, val)
+	return typ, input, nullable
+}
+
+// reference returns the identifier of a reference to a definition of types
+func (r *renderer) reference(val cue.Value, types map[string]string) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	ident, ok := types[path.String()]
+	return ident, ok
+}
+
+// enumReference returns the enum of a disjunction that refers to an enum
+// definition holding all its values, e.g. #Role | *"member"
+func (r *renderer) enumReference(val cue.Value, values []string) (string, bool) {
+	_, args := val.Expr()
+	for _, arg := range args {
+		ident, ok := r.reference(arg, r.enums)
+		if !ok {
+			continue
+		}
+		members := stringEnum(cue.Dereference(arg))
+		all := true
+		for _, v := range values {
+			if !slices.Contains(members, v) {
+				all = false
+			}
+		}
+		if all {
+			return ident, true
+		}
+	}
+	return "", false
+}
+
+// base maps a value by its kind
+func (r *renderer) base(parent, label string, val cue.Value) (string, string) {
+	val, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		return ":string", ":string"
+	case kind == cue.IntKind:
+		return ":integer", ":integer"
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return ":float", ":float"
+	case kind == cue.BoolKind:
+		return ":boolean", ":boolean"
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			r.json = true
+			return "list_of(:json)", "list_of(:json)"
+		}
+		typ, input, nullable := r.fieldType(parent, label, elem)
+		if !nullable {
+			typ, input = nonNull(typ), nonNull(input)
+		}
+		return "list_of(" + typ + ")", "list_of(" + input + ")"
+	case kind == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val):
+		ident := generator.UniqueName(r.used, parent+"_"+toSnakeCase(label), "")
+		r.object(ident, platoCue.Description(val), val)
+		return ":" + ident, ":" + ident + "_input"
+	}
+	r.json = true
+	return ":json", ":json"
+}
+
+// writeObject writes the object or input_object of an object
+func writeObject(buf *bytes.Buffer, o *object, input bool) {
+	macro, ident := "object", o.ident
+	if input {
+		macro, ident = "input_object", o.ident+"_input"
+	}
+	if o.doc != "" {
+		fmt.Fprintf(buf, "  @desc %s\n", elixirString(o.doc))
+	}
+	fmt.Fprintf(buf, "  %s :%s do\n", macro, ident)
+	for _, f := range o.fields {
+		if f.doc != "" {
+			fmt.Fprintf(buf, "    @desc %s\n", elixirString(f.doc))
+		}
+		typ := f.typ
+		if input {
+			typ = f.input
+		}
+		args := []string{":" + f.ident}
+		switch {
+		case input && f.def != "":
+			args = append(args, typ, "default_value: "+f.def)
+		case f.required:
+			args = append(args, nonNull(typ))
+		default:
+			args = append(args, typ)
+		}
+		if f.name != "" {
+			args = append(args, "name: "+elixirString(f.name))
+		}
+		fmt.Fprintf(buf, "    field %s\n", strings.Join(args, ", "))
+	}
+	buf.WriteString("  end\n")
+}
+
+// writeEnum writes an enum. Values keep the CUE strings as their internal
+// values, e.g. value :in_stock, as: "in-stock".
+func writeEnum(buf *bytes.Buffer, e *enum) {
+	if e.doc != "" {
+		fmt.Fprintf(buf, "  @desc %s\n", elixirString(e.doc))
+	}
+	fmt.Fprintf(buf, "  enum :%s do\n", e.ident)
+	used := make(map[string]bool)
+	for _, v := range e.values {
+		fmt.Fprintf(buf, "    value :%s, as: %s\n", generator.UniqueName(used, valueIdent(v), ""), elixirString(v))
+	}
+	buf.WriteString("  end\n")
+}
+
+// writeJSONScalar writes the scalar of values GraphQL has no type for, such
+// as maps and disjunctions of different kinds. Input is a JSON string.
+func writeJSONScalar(buf *bytes.Buffer) {
+	buf.WriteString(`  @desc "Arbitrary JSON value"
+  scalar :json, name: "JSON" do
+    serialize &Function.identity/1
+
+    parse fn
+      %Absinthe.Blueprint.Input.String{value: value} -> Jason.decode(value)
+      %Absinthe.Blueprint.Input.Null{} -> {:ok, nil}
+      _ -> :error
+    end
+  end
+`)
+}
+
+// nonNull wraps a type in non_null
+func nonNull(typ string) string {
+	return "non_null(" + typ + ")"
+}
+
+// stringEnum returns the distinct strings of a disjunction of string
+// literals, following references to enum definitions
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if _, path := arg.ReferencePath(); len(path.Selectors()) > 0 {
+			arg = cue.Dereference(arg)
+		}
+		var argValues []string
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if argValues = stringEnum(arg); argValues == nil {
+				return nil
+			}
+		} else {
+			if !arg.IsConcrete() || arg.Kind() != cue.StringKind {
+				return nil
+			}
+			s, _ := arg.String()
+			argValues = []string{s}
+		}
+		for _, v := range argValues {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// elixirString renders a double-quoted string literal
+func elixirString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\n"), "#{", `\#{`)
+}
+
+// elixirValue renders a JSON default as an Elixir term. Strings stay
+// strings, which is what enum values are internally.
+func elixirValue(data string) string {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "nil"
+	}
+
+	var render func(v interface{}) string
+	render = func(v interface{}) string {
+		switch v := v.(type) {
+		case nil:
+			return "nil"
+		case bool:
+			return strconv.FormatBool(v)
+		case json.Number:
+			return v.String()
+		case string:
+			return elixirString(v)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = render(item)
+			}
+			return "[" + strings.Join(items, ", ") + "]"
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, len(keys))
+			for i, key := range keys {
+				pairs[i] = elixirString(key) + " => " + render(v[key])
+			}
+			return "%{" + strings.Join(pairs, ", ") + "}"
+		}
+		return "nil"
+	}
+	return render(v)
+}
+
+// fieldIdent returns the identifier of a field: its name in snake_case,
+// which Absinthe camelizes back to the GraphQL name, e.g. firstName
+func fieldIdent(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return toSnakeCase(label)
+}
+
+// camelize returns the GraphQL name Absinthe derives from an identifier,
+// e.g. first_name to firstName
+func camelize(ident string) string {
+	lead := len(ident) - len(strings.TrimLeft(ident, "_"))
+	parts := strings.Split(ident[lead:], "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return ident[:lead] + strings.Join(parts, "")
+}
+
+// valueIdent returns the identifier of an enum value, e.g. in_stock for
+// "in-stock", which Absinthe exposes as IN_STOCK. GraphQL reserves true,
+// false and null.
+func valueIdent(value string) string {
+	if value == "" {
+		return "empty"
+	}
+	ident := toSnakeCase(value)
+	switch ident {
+	case "true", "false", "null":
+		return ident + "_value"
+	}
+	return ident
+}
+
+// toSnakeCase converts a definition or field name to an identifier, e.g.
+// #OrderItem to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLower(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "t" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("absinthe", "elixir-absinthe")
+}
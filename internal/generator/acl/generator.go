@@ -0,0 +1,217 @@
+package acl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/acl"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator exports @acl field access rules for authorization layers
+type Generator struct{}
+
+// NewGenerator creates a new access control generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "acl"
+}
+
+// Generate exports the access rules in the configured format
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	rules, err := acl.Extract(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract access rules: %w", err)
+	}
+
+	switch format := ctx.GetStringOption("format", "opa"); format {
+	case "opa":
+		return generateOPAData(rules)
+	case "rego":
+		return generateRego(ctx.GetStringOption("package", "platosl.acl"), rules)
+	case "casbin":
+		return generateCasbin(rules), nil
+	case "typescript", "ts":
+		return generateTypeScript(rules)
+	case "go":
+		return generateGo(ctx.GetStringOption("package", "acl"), rules), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (supported: opa, rego, casbin, typescript, go)", format)
+	}
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// generateOPAData renders the rules as an OPA data document
+func generateOPAData(rules []acl.Rule) ([]byte, error) {
+	doc := map[string]interface{}{
+		"acl": acl.Tree(rules),
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// generateRego renders a Rego module with the rules embedded and helper
+// rules for field-level checks
+func generateRego(pkg string, rules []acl.Rule) ([]byte, error) {
+	data, err := json.MarshalIndent(acl.Tree(rules), "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "# DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import rego.v1\n\n")
+	fmt.Fprintf(&buf, "fields := %s\n\n", data)
+	buf.WriteString("# can_read(resource, field, roles) holds if any of roles may read the field\n")
+	buf.WriteString("can_read(resource, field, roles) if {\n")
+	buf.WriteString("\tsome role in roles\n")
+	buf.WriteString("\trole in fields[resource][field].read\n")
+	buf.WriteString("}\n\n")
+	buf.WriteString("# can_write(resource, field, roles) holds if any of roles may write the field\n")
+	buf.WriteString("can_write(resource, field, roles) if {\n")
+	buf.WriteString("\tsome role in roles\n")
+	buf.WriteString("\trole in fields[resource][field].write\n")
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// generateCasbin renders the rules as Casbin policy lines (sub, obj, act),
+// with objects named <Resource>.<field>
+func generateCasbin(rules []acl.Rule) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n")
+
+	for _, r := range rules {
+		obj := r.Resource + "." + r.Field
+		for _, role := range r.Read {
+			fmt.Fprintf(&buf, "p, %s, %s, read\n", role, obj)
+		}
+		for _, role := range r.Write {
+			fmt.Fprintf(&buf, "p, %s, %s, write\n", role, obj)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// generateTypeScript renders the rules as a constant map
+func generateTypeScript(rules []acl.Rule) ([]byte, error) {
+	data, err := json.MarshalIndent(acl.Tree(rules), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "export const acl = %s as const;\n\n", data)
+	buf.WriteString("export type AclResource = keyof typeof acl;\n")
+	buf.WriteString("export type AclOperation = \"read\" | \"write\";\n\n")
+	buf.WriteString("/** Reports whether any of the roles may perform the operation on a field. */\n")
+	buf.WriteString("export function canAccess<R extends AclResource>(\n")
+	buf.WriteString("  resource: R,\n")
+	buf.WriteString("  field: keyof (typeof acl)[R],\n")
+	buf.WriteString("  operation: AclOperation,\n")
+	buf.WriteString("  roles: readonly string[],\n")
+	buf.WriteString("): boolean {\n")
+	buf.WriteString("  const rule = acl[resource][field] as { read: readonly string[]; write: readonly string[] };\n")
+	buf.WriteString("  return roles.some((role) => rule[operation].includes(role));\n")
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// generateGo renders the rules as a constant-like map with a lookup helper
+func generateGo(pkg string, rules []acl.Rule) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	buf.WriteString("// FieldACL lists the roles allowed to read and write a field.\n")
+	buf.WriteString("type FieldACL struct {\n")
+	buf.WriteString("\tRead  []string\n")
+	buf.WriteString("\tWrite []string\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// ACL maps resources and fields to their access rules.\n")
+	buf.WriteString("var ACL = map[string]map[string]FieldACL{\n")
+
+	tree := acl.Tree(rules)
+	resources := make([]string, 0, len(tree))
+	for resource := range tree {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	for _, resource := range resources {
+		fmt.Fprintf(&buf, "\t%s: {\n", strconv.Quote(resource))
+		fields := make([]string, 0, len(tree[resource]))
+		for field := range tree[resource] {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			rule := tree[resource][field]
+			fmt.Fprintf(&buf, "\t\t%s: {Read: %s, Write: %s},\n", strconv.Quote(field), goStrings(rule["read"]), goStrings(rule["write"]))
+		}
+		buf.WriteString("\t},\n")
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// CanAccess reports whether any of roles may perform op (\"read\" or \"write\") on a field.\n")
+	buf.WriteString("func CanAccess(resource, field, op string, roles []string) bool {\n")
+	buf.WriteString("\trule, ok := ACL[resource][field]\n")
+	buf.WriteString("\tif !ok {\n")
+	buf.WriteString("\t\treturn false\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\tallowed := rule.Read\n")
+	buf.WriteString("\tif op == \"write\" {\n")
+	buf.WriteString("\t\tallowed = rule.Write\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\tfor _, role := range roles {\n")
+	buf.WriteString("\t\tfor _, a := range allowed {\n")
+	buf.WriteString("\t\t\tif role == a {\n")
+	buf.WriteString("\t\t\t\treturn true\n")
+	buf.WriteString("\t\t\t}\n")
+	buf.WriteString("\t\t}\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn false\n")
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// goStrings renders a string slice literal
+func goStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
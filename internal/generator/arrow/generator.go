@@ -0,0 +1,337 @@
+package arrow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Apache Arrow schemas, as a pyarrow module, or Parquet
+// message types from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Arrow generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "arrow"
+}
+
+// dataType is an Arrow data type. Scalars are named after their pyarrow
+// factory, e.g. int64 or timestamp.
+type dataType struct {
+	name         string
+	elem         *dataType // list item or map value
+	elemNullable bool
+	fields       []column // struct fields
+	precision    int      // decimal
+	scale        int      // decimal
+}
+
+// column is a field of a schema or struct
+type column struct {
+	name     string
+	docs     platoCue.Docs
+	typ      *dataType
+	nullable bool
+}
+
+// schema is the Arrow schema of a struct definition
+type schema struct {
+	def     string
+	ident   string // UPPER_SNAKE for pyarrow, snake_case for Parquet
+	docs    platoCue.Docs
+	columns []column
+}
+
+// scalars are the types @arrow may set, besides decimal
+var scalars = map[string]bool{
+	"string": true, "large_string": true, "binary": true, "bool": true,
+	"int8": true, "int16": true, "int32": true, "int64": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "timestamp": true, "date": true, "time": true,
+}
+
+// builder maps definitions to Arrow types. Arrow has no named types, so
+// definitions are inlined where they are referenced; a reference back to a
+// definition being inlined is a JSON-encoded string.
+type builder struct {
+	defs     map[string]cue.Value
+	visiting map[string]bool
+}
+
+// Generate generates a schema per struct definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var names []string
+	for name, val := range defs {
+		if isRecord(val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	parquet := ctx.GetBoolOption("parquet", false)
+	used := make(map[string]bool)
+	var schemas []*schema
+	for _, name := range names {
+		b := &builder{defs: defs, visiting: map[string]bool{name: true}}
+		columns, err := b.columns(defs[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		ident := generator.UniqueName(used, toSnakeCase(name), "_")
+		if !parquet {
+			ident = strings.ToUpper(ident) + "_SCHEMA"
+		}
+		schemas = append(schemas, &schema{
+			def:     strings.TrimPrefix(name, "#"),
+			ident:   ident,
+			docs:    platoCue.DocsOf(defs[name]),
+			columns: columns,
+		})
+	}
+
+	if parquet {
+		return writeParquet(schemas), nil
+	}
+	return writePyArrow(schemas), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// columns maps the fields of a struct to columns. Optional fields and
+// fields that may be null are nullable.
+func (b *builder) columns(val cue.Value) ([]column, error) {
+	var columns []column
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		typ, nullable, err := b.typeOf(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		columns = append(columns, column{
+			name:     name,
+			docs:     platoCue.DocsOf(iter.Value()),
+			typ:      typ,
+			nullable: nullable || iter.IsOptional(),
+		})
+	}
+	return columns, nil
+}
+
+// typeOf maps a value to an Arrow type, reporting whether it may be null
+func (b *builder) typeOf(val cue.Value) (*dataType, bool, error) {
+	if attr, ok := platoCue.GetAttr(val, "arrow"); ok {
+		_, nullable := platoCue.StripNull(val)
+		typ, err := attrType(attr)
+		return typ, nullable, err
+	}
+
+	if alts, nullable := platoCue.KindAlternatives(val); alts != nil {
+		return &dataType{name: "json"}, nullable, nil
+	}
+	val, nullable := platoCue.StripNull(val)
+
+	if name, ok := reference(val); ok {
+		if def, ok := b.defs[name]; ok && isRecord(def) {
+			if b.visiting[name] {
+				return &dataType{name: "json"}, nullable, nil
+			}
+			b.visiting[name] = true
+			defer delete(b.visiting, name)
+			fields, err := b.columns(def)
+			if err != nil {
+				return nil, false, err
+			}
+			return &dataType{name: "struct", fields: fields}, nullable, nil
+		}
+	}
+
+	if stringEnum(val) {
+		return &dataType{name: "dictionary"}, nullable, nil
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		return &dataType{name: "string"}, nullable, nil
+	case kind == cue.BytesKind:
+		return &dataType{name: "binary"}, nullable, nil
+	case kind == cue.IntKind:
+		return &dataType{name: "int64"}, nullable, nil
+	case kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0:
+		return &dataType{name: "float64"}, nullable, nil
+	case kind == cue.BoolKind:
+		return &dataType{name: "bool"}, nullable, nil
+	case kind == cue.ListKind:
+		elem := typed.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return &dataType{name: "list", elem: &dataType{name: "json"}, elemNullable: true}, nullable, nil
+		}
+		item, itemNullable, err := b.typeOf(elem)
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "list", elem: item, elemNullable: itemNullable}, nullable, nil
+	case kind == cue.StructKind && platoCue.IsMap(typed):
+		value, valueNullable, err := b.typeOf(typed.LookupPath(cue.MakePath(cue.AnyString)))
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "map", elem: value, elemNullable: valueNullable}, nullable, nil
+	case kind == cue.StructKind && platoCue.HasFields(typed):
+		fields, err := b.columns(typed)
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "struct", fields: fields}, nullable, nil
+	}
+	return &dataType{name: "json"}, nullable, nil
+}
+
+// attrType reads the type of an @arrow attribute, e.g. @arrow(timestamp)
+// or @arrow(decimal, precision=12, scale=2)
+func attrType(attr platoCue.Attr) (*dataType, error) {
+	name := strings.ToLower(attr.Arg(0))
+	if name == "decimal" {
+		precision, err := strconv.Atoi(attr.Param("precision"))
+		if err != nil || precision < 1 || precision > 38 {
+			return nil, fmt.Errorf("@arrow(decimal) needs a precision from 1 to 38")
+		}
+		scale := 0
+		if s := attr.Param("scale"); s != "" {
+			if scale, err = strconv.Atoi(s); err != nil || scale < 0 || scale > precision {
+				return nil, fmt.Errorf("@arrow(decimal) scale must be from 0 to the precision")
+			}
+		}
+		return &dataType{name: "decimal", precision: precision, scale: scale}, nil
+	}
+	if !scalars[name] {
+		return nil, fmt.Errorf("unknown Arrow type %q in @arrow", attr.Arg(0))
+	}
+	return &dataType{name: name}, nil
+}
+
+// stringEnum reports whether a value is a disjunction of string literals,
+// following references to enum definitions
+func stringEnum(val cue.Value) bool {
+	if _, ok := reference(val); ok {
+		val = cue.Dereference(val)
+	}
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return false
+	}
+	for _, arg := range args {
+		if _, ok := reference(arg); ok {
+			arg = cue.Dereference(arg)
+		}
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if !stringEnum(arg) {
+				return false
+			}
+		} else if !arg.IsConcrete() || arg.Kind() != cue.StringKind {
+			return false
+		}
+	}
+	return true
+}
+
+// isRecord reports whether a definition is a struct with fields, which
+// becomes a schema or a struct type
+func isRecord(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+// toSnakeCase converts a definition name to a schema name, e.g.
+// #OrderItem to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "s" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
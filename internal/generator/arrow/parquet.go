@@ -0,0 +1,101 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// parquetTypes maps scalar data types to a Parquet primitive type and
+// logical type annotation
+var parquetTypes = map[string][2]string{
+	"string":       {"binary", "STRING"},
+	"large_string": {"binary", "STRING"},
+	"dictionary":   {"binary", "STRING"},
+	"json":         {"binary", "JSON"},
+	"binary":       {"binary", ""},
+	"bool":         {"boolean", ""},
+	"int8":         {"int32", "INTEGER(8,true)"},
+	"int16":        {"int32", "INTEGER(16,true)"},
+	"int32":        {"int32", ""},
+	"int64":        {"int64", ""},
+	"uint8":        {"int32", "INTEGER(8,false)"},
+	"uint16":       {"int32", "INTEGER(16,false)"},
+	"uint32":       {"int32", "INTEGER(32,false)"},
+	"uint64":       {"int64", "INTEGER(64,false)"},
+	"float32":      {"float", ""},
+	"float64":      {"double", ""},
+	"timestamp":    {"int64", "TIMESTAMP(MICROS,true)"},
+	"date":         {"int32", "DATE"},
+	"time":         {"int64", "TIME(MICROS,true)"},
+}
+
+// writeParquet renders the schemas as Parquet message types, in the text
+// form parquet-mr's MessageTypeParser and parquet-cpp read. The format has
+// no comments, so there is no generated header.
+func writeParquet(schemas []*schema) []byte {
+	var buf bytes.Buffer
+	for i, s := range schemas {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "message %s {\n", s.ident)
+		for _, c := range s.columns {
+			writeParquetField(&buf, repetition(c.nullable), parquetName(c.name), c.typ, "  ")
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes()
+}
+
+// writeParquetField writes a field, with lists and maps in the three-level
+// structure of the Parquet spec
+func writeParquetField(buf *bytes.Buffer, rep, name string, t *dataType, indent string) {
+	switch t.name {
+	case "struct":
+		fmt.Fprintf(buf, "%s%s group %s {\n", indent, rep, name)
+		for _, f := range t.fields {
+			writeParquetField(buf, repetition(f.nullable), parquetName(f.name), f.typ, indent+"  ")
+		}
+		fmt.Fprintf(buf, "%s}\n", indent)
+	case "list":
+		fmt.Fprintf(buf, "%s%s group %s (LIST) {\n", indent, rep, name)
+		fmt.Fprintf(buf, "%s  repeated group list {\n", indent)
+		writeParquetField(buf, repetition(t.elemNullable), "element", t.elem, indent+"    ")
+		fmt.Fprintf(buf, "%s  }\n%s}\n", indent, indent)
+	case "map":
+		fmt.Fprintf(buf, "%s%s group %s (MAP) {\n", indent, rep, name)
+		fmt.Fprintf(buf, "%s  repeated group key_value {\n", indent)
+		fmt.Fprintf(buf, "%s    required binary key (STRING);\n", indent)
+		writeParquetField(buf, repetition(t.elemNullable), "value", t.elem, indent+"    ")
+		fmt.Fprintf(buf, "%s  }\n%s}\n", indent, indent)
+	case "decimal":
+		fmt.Fprintf(buf, "%s%s binary %s (DECIMAL(%d,%d));\n", indent, rep, name, t.precision, t.scale)
+	default:
+		typ := parquetTypes[t.name]
+		if typ[1] != "" {
+			fmt.Fprintf(buf, "%s%s %s %s (%s);\n", indent, rep, typ[0], name, typ[1])
+		} else {
+			fmt.Fprintf(buf, "%s%s %s %s;\n", indent, rep, typ[0], name)
+		}
+	}
+}
+
+// repetition returns the Parquet repetition of a field
+func repetition(nullable bool) string {
+	if nullable {
+		return "optional"
+	}
+	return "required"
+}
+
+// parquetName replaces the characters that end a name in the message type
+// syntax
+func parquetName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" \t\n\r;{}()=,", r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,114 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// writePyArrow renders the schemas as a Python module of pyarrow schemas,
+// with a SCHEMAS dict by definition name
+func writePyArrow(schemas []*schema) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("import pyarrow as pa\n")
+
+	for _, s := range schemas {
+		buf.WriteString("\n")
+		if s.docs.Description != "" {
+			for _, line := range strings.Split(s.docs.Description, "\n") {
+				buf.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+		fmt.Fprintf(&buf, "%s = pa.schema(\n    [\n", s.ident)
+		for _, c := range s.columns {
+			fmt.Fprintf(&buf, "        %s,\n", pyField(c, "        "))
+		}
+		buf.WriteString("    ],\n")
+		if meta := pyMetadata(s.docs); meta != "" {
+			fmt.Fprintf(&buf, "    metadata=%s,\n", meta)
+		}
+		buf.WriteString(")\n")
+	}
+
+	buf.WriteString("\nSCHEMAS = {\n")
+	for _, s := range schemas {
+		fmt.Fprintf(&buf, "    %s: %s,\n", strconv.Quote(s.def), s.ident)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// pyField renders a pa.field call; nested struct fields go on lines of
+// their own, indented from indent
+func pyField(c column, indent string) string {
+	args := []string{strconv.Quote(c.name), pyType(c.typ, indent)}
+	if !c.nullable {
+		args = append(args, "nullable=False")
+	}
+	if meta := pyMetadata(c.docs); meta != "" {
+		args = append(args, "metadata="+meta)
+	}
+	return "pa.field(" + strings.Join(args, ", ") + ")"
+}
+
+// pyType renders the pyarrow expression of a data type
+func pyType(t *dataType, indent string) string {
+	switch t.name {
+	case "struct":
+		var b strings.Builder
+		b.WriteString("pa.struct([\n")
+		for _, f := range t.fields {
+			fmt.Fprintf(&b, "%s    %s,\n", indent, pyField(f, indent+"    "))
+		}
+		b.WriteString(indent + "])")
+		return b.String()
+	case "list":
+		return "pa.list_(" + pyItem("item", t, indent) + ")"
+	case "map":
+		return "pa.map_(pa.string(), " + pyItem("value", t, indent) + ")"
+	case "dictionary":
+		return "pa.dictionary(pa.int32(), pa.string())"
+	case "json":
+		return "pa.string()"
+	case "bool":
+		return "pa.bool_()"
+	case "timestamp":
+		return `pa.timestamp("us", tz="UTC")`
+	case "date":
+		return "pa.date32()"
+	case "time":
+		return `pa.time64("us")`
+	case "decimal":
+		return fmt.Sprintf("pa.decimal128(%d, %d)", t.precision, t.scale)
+	}
+	return "pa." + t.name + "()"
+}
+
+// pyItem renders the item type of a list or map, as a field when items may
+// not be null
+func pyItem(name string, t *dataType, indent string) string {
+	if t.elemNullable {
+		return pyType(t.elem, indent)
+	}
+	return fmt.Sprintf("pa.field(%q, %s, nullable=False)", name, pyType(t.elem, indent))
+}
+
+// pyMetadata renders the title and description as a metadata dict
+func pyMetadata(docs platoCue.Docs) string {
+	var entries []string
+	if docs.Title != "" {
+		entries = append(entries, `"title": `+strconv.Quote(docs.Title))
+	}
+	if docs.Description != "" {
+		entries = append(entries, `"description": `+strconv.Quote(docs.Description))
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
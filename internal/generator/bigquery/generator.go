@@ -0,0 +1,491 @@
+package bigquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates BigQuery JSON table schemas from CUE, one file per
+// table
+type Generator struct{}
+
+// NewGenerator creates a new BigQuery generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "bigquery"
+}
+
+// maxDescription is the longest column description BigQuery accepts
+const maxDescription = 1024
+
+// types are the column types @bigquery may set
+var types = map[string]bool{
+	"STRING": true, "BYTES": true, "INTEGER": true, "INT64": true, "FLOAT": true,
+	"FLOAT64": true, "NUMERIC": true, "BIGNUMERIC": true, "BOOLEAN": true,
+	"BOOL": true, "TIMESTAMP": true, "DATE": true, "TIME": true, "DATETIME": true,
+	"GEOGRAPHY": true, "JSON": true, "RECORD": true, "STRUCT": true,
+}
+
+// Field is a column of a table schema, as bq and the BigQuery API take it
+type Field struct {
+	Name                   string  `json:"name"`
+	Type                   string  `json:"type"`
+	Mode                   string  `json:"mode"`
+	Description            string  `json:"description,omitempty"`
+	MaxLength              string  `json:"maxLength,omitempty"`
+	DefaultValueExpression string  `json:"defaultValueExpression,omitempty"`
+	Fields                 []Field `json:"fields,omitempty"`
+}
+
+// builder maps definitions to columns. Definitions are inlined where they
+// are referenced; a reference back to a definition being inlined is a JSON
+// column, as BigQuery has no recursive records.
+type builder struct {
+	defs     map[string]cue.Value
+	visiting map[string]bool
+}
+
+// Generate generates all files joined into one stream
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	files, err := g.GenerateFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return generator.JoinFiles(files), nil
+}
+
+// GenerateFiles generates a <table>.json schema per table definition: the
+// definitions in the tables option, or by default the struct definitions
+// no other definition uses
+func (g *Generator) GenerateFiles(ctx *generator.Context) ([]generator.File, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var names []string
+	for name, val := range defs {
+		if isRecord(val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tables, err := tableNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if tables == nil {
+		referenced := make(map[string]bool)
+		for _, name := range names {
+			references(defs[name], referenced)
+			delete(referenced, name)
+		}
+		tables = make(map[string]bool)
+		for _, name := range names {
+			if !referenced[name] {
+				tables[name] = true
+			}
+		}
+	}
+
+	var files []generator.File
+	used := make(map[string]bool)
+	for _, name := range names {
+		if !tables[name] {
+			continue
+		}
+		b := &builder{defs: defs, visiting: map[string]bool{name: true}}
+		fields, err := b.fields(defs[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(fields); err != nil {
+			return nil, fmt.Errorf("failed to encode schema of %s: %w", name, err)
+		}
+		files = append(files, generator.File{
+			Path:    generator.UniqueName(used, toSnakeCase(name), "_") + ".json",
+			Content: buf.Bytes(),
+		})
+	}
+	return files, nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// tableNames reads the tables option: definition names, with or without
+// the #, as a list or a comma-separated string. It returns nil when the
+// option is not set.
+func tableNames(ctx *generator.Context, names []string) (map[string]bool, error) {
+	raw, ok := ctx.GetOption("tables")
+	if !ok {
+		return nil, nil
+	}
+	var list []string
+	switch v := raw.(type) {
+	case string:
+		list = strings.Split(v, ",")
+	case []string:
+		list = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+
+	tables := make(map[string]bool)
+	for _, table := range list {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			continue
+		}
+		found := false
+		for _, name := range names {
+			if name == table || name == "#"+table {
+				tables[name], found = true, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table %q is not a struct definition", table)
+		}
+	}
+	return tables, nil
+}
+
+// fields maps the fields of a struct to columns
+func (b *builder) fields(val cue.Value) ([]Field, error) {
+	var fields []Field
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		value := iter.Value()
+		field, err := b.column(columnName(used, iter.Selector().Unquoted()), value)
+		if err != nil {
+			return nil, err
+		}
+		if field.Mode == "" {
+			field.Mode = "REQUIRED"
+			if _, nullable := platoCue.StripNull(value); nullable || iter.IsOptional() {
+				field.Mode = "NULLABLE"
+			}
+			if def, ok := platoCue.ScalarDefault(value); ok {
+				field.Mode = "NULLABLE"
+				field.DefaultValueExpression = sqlLiteral(def, field.Type)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// column maps a field to a column. Lists are REPEATED columns of their
+// items; the mode of other columns is left to the caller.
+func (b *builder) column(name string, val cue.Value) (Field, error) {
+	field := Field{Name: name, Description: description(val)}
+
+	if attr, ok := platoCue.GetAttr(val, "bigquery"); ok {
+		typ := strings.ToUpper(attr.Arg(0))
+		if !types[typ] {
+			return Field{}, fmt.Errorf("%s: unknown BigQuery type %q in @bigquery", name, attr.Arg(0))
+		}
+		field.Type = typ
+		if kind := val.IncompleteKind(); kind == cue.ListKind {
+			field.Mode = "REPEATED"
+		}
+		return field, nil
+	}
+
+	if alts, _ := platoCue.KindAlternatives(val); alts != nil {
+		field.Type = "JSON"
+		return field, nil
+	}
+	val, _ = platoCue.StripNull(val)
+
+	if name, ok := reference(val); ok {
+		if def, ok := b.defs[name]; ok && isRecord(def) {
+			if b.visiting[name] {
+				field.Type = "JSON"
+				return field, nil
+			}
+			b.visiting[name] = true
+			defer delete(b.visiting, name)
+			if field.Description == "" {
+				field.Description = description(def)
+			}
+			return b.record(field, def)
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		field.Type = "STRING"
+		if max := platoCue.ConstraintsOf(val).MaxLength; max != nil {
+			field.MaxLength = fmt.Sprint(*max)
+		}
+	case kind == cue.BytesKind:
+		field.Type = "BYTES"
+	case kind == cue.IntKind:
+		field.Type = "INTEGER"
+	case kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0:
+		field.Type = "FLOAT"
+	case kind == cue.BoolKind:
+		field.Type = "BOOLEAN"
+	case kind == cue.ListKind:
+		elem := typed.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			field.Type, field.Mode = "JSON", "REPEATED"
+			return field, nil
+		}
+		item, err := b.column(name, elem)
+		if err != nil {
+			return Field{}, err
+		}
+		// BigQuery has no arrays of arrays; nested lists are JSON
+		if item.Mode == "REPEATED" {
+			item = Field{Name: name, Type: "JSON"}
+		}
+		item.Mode = "REPEATED"
+		item.MaxLength, item.DefaultValueExpression = "", ""
+		if field.Description != "" {
+			item.Description = field.Description
+		}
+		return item, nil
+	case kind == cue.StructKind && platoCue.HasFields(typed) && !platoCue.IsMap(typed):
+		return b.record(field, typed)
+	default:
+		field.Type = "JSON"
+	}
+	return field, nil
+}
+
+// record maps a struct to a RECORD column
+func (b *builder) record(field Field, val cue.Value) (Field, error) {
+	fields, err := b.fields(val)
+	if err != nil {
+		return Field{}, err
+	}
+	field.Type, field.Fields = "RECORD", fields
+	return field, nil
+}
+
+// description returns the doc comment of a value, cut to the length
+// BigQuery accepts
+func description(val cue.Value) string {
+	doc := platoCue.Description(val)
+	if runes := []rune(doc); len(runes) > maxDescription {
+		doc = string(runes[:maxDescription-1]) + "…"
+	}
+	return doc
+}
+
+// sqlLiteral renders a JSON default as a GoogleSQL literal of a column type
+func sqlLiteral(data, typ string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		quoted, _ := json.Marshal(v)
+		if typ == "JSON" {
+			return "JSON " + sqlString(string(quoted))
+		}
+		return sqlString(v)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case float64:
+		return data
+	case nil:
+		return "NULL"
+	}
+	if typ == "JSON" {
+		return "JSON " + sqlString(data)
+	}
+	return ""
+}
+
+// sqlString renders a GoogleSQL string literal
+func sqlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// columnName returns a column name: letters, digits and underscores, not
+// starting with a digit, and unique within its record
+func columnName(used map[string]bool, key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// isRecord reports whether a definition is a struct with fields, which
+// becomes a RECORD column or a table
+func isRecord(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// references adds the definitions a value refers to in its fields, list
+// items, map values and disjunctions
+func references(val cue.Value, refs map[string]bool) {
+	if name, ok := reference(val); ok {
+		refs[name] = true
+		return
+	}
+	if op, args := val.Expr(); op == cue.OrOp || op == cue.AndOp {
+		for _, arg := range args {
+			references(arg, refs)
+		}
+		return
+	}
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			references(elem, refs)
+		}
+	case cue.StructKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			references(elem, refs)
+		}
+		iter, err := val.Fields(cue.Optional(true))
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			references(iter.Value(), refs)
+		}
+	}
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+// toSnakeCase converts a definition name to a table name, e.g. #OrderItem
+// to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "t" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("bq", "bigquery")
+}
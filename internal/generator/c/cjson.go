@@ -0,0 +1,300 @@
+package c
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// writeStrdup renders the strdup the helpers copy strings with, as C99 has
+// none
+func writeStrdup(buf *bytes.Buffer, prefix string) {
+	fmt.Fprintf(buf, "\nstatic char *%splatosl_strdup(const char *s)\n{\n", prefix)
+	buf.WriteString("    size_t n = strlen(s) + 1;\n")
+	buf.WriteString("    char *copy = malloc(n);\n")
+	buf.WriteString("    if (copy != NULL) {\n        memcpy(copy, s, n);\n    }\n")
+	buf.WriteString("    return copy;\n}\n")
+}
+
+// writeEnumHelpers renders the conversions of an enum from and to its
+// strings
+func writeEnumHelpers(buf *bytes.Buffer, d *typeDecl) {
+	fmt.Fprintf(buf, "const char *%s_to_string(%s value)\n{\n", d.base, d.name)
+	buf.WriteString("    switch (value) {\n")
+	for _, v := range d.enum {
+		fmt.Fprintf(buf, "    case %s:\n        return %s;\n", v.name, quote(v.value))
+	}
+	buf.WriteString("    }\n    return NULL;\n}\n\n")
+
+	fmt.Fprintf(buf, "int %s_from_string(const char *s, %s *out)\n{\n", d.base, d.name)
+	buf.WriteString("    if (s == NULL) {\n        return -1;\n    }\n")
+	for _, v := range d.enum {
+		fmt.Fprintf(buf, "    if (strcmp(s, %s) == 0) {\n        *out = %s;\n        return 0;\n    }\n", quote(v.value), v.name)
+	}
+	buf.WriteString("    return -1;\n}\n")
+}
+
+// implWriter renders the cJSON helpers of a struct
+type implWriter struct {
+	buf    *bytes.Buffer
+	prefix string
+}
+
+func (w *implWriter) line(indent int, format string, args ...interface{}) {
+	w.buf.WriteString(strings.Repeat("    ", indent))
+	fmt.Fprintf(w.buf, format, args...)
+	w.buf.WriteString("\n")
+}
+
+// toJSON renders <type>_to_json, which builds a cJSON object from a struct.
+// Absent optional fields are left out and absent nullable fields are null.
+func (w *implWriter) toJSON(d *typeDecl) {
+	w.line(0, "cJSON *%s_to_json(const %s *v)", d.base, d.name)
+	w.line(0, "{")
+	w.line(1, "cJSON *json = cJSON_CreateObject();")
+	w.line(1, "if (json == NULL) {")
+	w.line(2, "return NULL;")
+	w.line(1, "}")
+	for _, f := range d.fields {
+		key := quote(f.key)
+		src := "v->" + f.name
+
+		// The condition under which the field has a value
+		var present string
+		switch {
+		case f.hasPresence():
+			present = "v->has_" + f.name
+		case f.typ.kind == kindString || f.typ.kind == kindJSON || f.typ.pointer:
+			present = src + " != NULL"
+		}
+
+		indent := 1
+		if present != "" {
+			w.line(1, "if (%s) {", present)
+			indent = 2
+		}
+		switch f.typ.kind {
+		case kindList:
+			w.line(indent, "{")
+			w.line(indent+1, "cJSON *items = cJSON_AddArrayToObject(json, %s);", key)
+			w.line(indent+1, "for (size_t i = 0; i < %s_count; i++) {", src)
+			w.line(indent+2, "cJSON_AddItemToArray(items, %s);", item(f.typ.elem, src+"[i]"))
+			w.line(indent+1, "}")
+			w.line(indent, "}")
+		case kindMap:
+			w.line(indent, "{")
+			w.line(indent+1, "cJSON *items = cJSON_AddObjectToObject(json, %s);", key)
+			w.line(indent+1, "for (size_t i = 0; i < %s_count; i++) {", src)
+			w.line(indent+2, "cJSON_AddItemToObject(items, %s_keys[i], %s);", src, item(f.typ.elem, src+"_values[i]"))
+			w.line(indent+1, "}")
+			w.line(indent, "}")
+		default:
+			w.line(indent, "cJSON_AddItemToObject(json, %s, %s);", key, item(f.typ, src))
+		}
+		if present != "" {
+			if f.nullable && !f.optional {
+				w.line(1, "} else {")
+				w.line(2, "cJSON_AddNullToObject(json, %s);", key)
+			}
+			w.line(1, "}")
+		}
+	}
+	w.line(1, "return json;")
+	w.line(0, "}")
+}
+
+// item renders the cJSON item of a value
+func item(t *ctype, src string) string {
+	switch t.kind {
+	case kindString:
+		return "cJSON_CreateString(" + src + ")"
+	case kindInt, kindFloat:
+		return "cJSON_CreateNumber((double)" + src + ")"
+	case kindBool:
+		return "cJSON_CreateBool(" + src + ")"
+	case kindEnum:
+		return "cJSON_CreateString(" + t.ref.base + "_to_string(" + src + "))"
+	case kindStruct:
+		if t.pointer {
+			return t.ref.base + "_to_json(" + src + ")"
+		}
+		return t.ref.base + "_to_json(&" + src + ")"
+	default:
+		return "cJSON_Parse(" + src + ")"
+	}
+}
+
+// fromJSON renders <type>_from_json, which reads a struct from a cJSON
+// object. It returns 0, or -1 when a required field is missing or a value
+// has the wrong type, leaving the struct empty. Counts are set once their
+// arrays are allocated, so _free never walks a missing array.
+func (w *implWriter) fromJSON(d *typeDecl) {
+	w.line(0, "int %s_from_json(const cJSON *json, %s *out)", d.base, d.name)
+	w.line(0, "{")
+	w.line(1, "const cJSON *item;")
+	w.line(0, "")
+	w.line(1, "memset(out, 0, sizeof(*out));")
+	w.line(1, "if (!cJSON_IsObject(json)) {")
+	w.line(2, "return -1;")
+	w.line(1, "}")
+	for _, f := range d.fields {
+		dst := "out->" + f.name
+		w.line(1, "item = cJSON_GetObjectItemCaseSensitive(json, %s);", quote(f.key))
+		w.line(1, "if (item != NULL && !cJSON_IsNull(item)) {")
+		switch f.typ.kind {
+		case kindList:
+			w.line(2, "const cJSON *elem;")
+			w.line(2, "size_t i = 0, n;")
+			w.line(2, "if (!cJSON_IsArray(item)) {")
+			w.line(3, "goto fail;")
+			w.line(2, "}")
+			w.line(2, "n = (size_t)cJSON_GetArraySize(item);")
+			w.line(2, "%s = calloc(n > 0 ? n : 1, sizeof(*%s));", dst, dst)
+			w.line(2, "if (%s == NULL) {", dst)
+			w.line(3, "goto fail;")
+			w.line(2, "}")
+			w.line(2, "%s_count = n;", dst)
+			w.line(2, "cJSON_ArrayForEach(elem, item) {")
+			w.read(3, f.typ.elem, "elem", dst+"[i]")
+			w.line(3, "i++;")
+			w.line(2, "}")
+		case kindMap:
+			w.line(2, "const cJSON *elem;")
+			w.line(2, "size_t i = 0, n;")
+			w.line(2, "if (!cJSON_IsObject(item)) {")
+			w.line(3, "goto fail;")
+			w.line(2, "}")
+			w.line(2, "n = (size_t)cJSON_GetArraySize(item);")
+			w.line(2, "%s_keys = calloc(n > 0 ? n : 1, sizeof(*%s_keys));", dst, dst)
+			w.line(2, "%s_values = calloc(n > 0 ? n : 1, sizeof(*%s_values));", dst, dst)
+			w.line(2, "if (%s_keys == NULL || %s_values == NULL) {", dst, dst)
+			w.line(3, "goto fail;")
+			w.line(2, "}")
+			w.line(2, "%s_count = n;", dst)
+			w.line(2, "cJSON_ArrayForEach(elem, item) {")
+			w.line(3, "%s_keys[i] = %splatosl_strdup(elem->string);", dst, w.prefix)
+			w.line(3, "if (%s_keys[i] == NULL) {", dst)
+			w.line(4, "goto fail;")
+			w.line(3, "}")
+			w.read(3, f.typ.elem, "elem", dst+"_values[i]")
+			w.line(3, "i++;")
+			w.line(2, "}")
+		default:
+			w.read(2, f.typ, "item", dst)
+		}
+		if f.hasPresence() {
+			w.line(2, "out->has_%s = true;", f.name)
+		}
+
+		// Missing fields are an error unless optional, and null ones
+		// unless nullable
+		switch {
+		case !f.optional && !f.nullable:
+			w.line(1, "} else {")
+			w.line(2, "goto fail;")
+		case !f.optional:
+			w.line(1, "} else if (item == NULL) {")
+			w.line(2, "goto fail;")
+		case !f.nullable:
+			w.line(1, "} else if (item != NULL) {")
+			w.line(2, "goto fail;")
+		}
+		w.line(1, "}")
+	}
+	w.line(1, "return 0;")
+	w.line(0, "")
+	w.line(0, "fail:")
+	w.line(1, "%s_free(out);", d.base)
+	w.line(1, "return -1;")
+	w.line(0, "}")
+}
+
+// read renders the reading of a cJSON item into dst
+func (w *implWriter) read(indent int, t *ctype, src, dst string) {
+	fail := func(cond string) {
+		w.line(indent, "if (%s) {", cond)
+		w.line(indent+1, "goto fail;")
+		w.line(indent, "}")
+	}
+	switch t.kind {
+	case kindString:
+		fail("!cJSON_IsString(" + src + ")")
+		w.line(indent, "%s = %splatosl_strdup(%s->valuestring);", dst, w.prefix, src)
+		fail(dst + " == NULL")
+	case kindInt:
+		fail(fmt.Sprintf("!cJSON_IsNumber(%s) || %s->valuedouble < %s || %s->valuedouble > %s", src, src, t.min, src, t.max))
+		w.line(indent, "%s = (%s)%s->valuedouble;", dst, t.decl, src)
+	case kindFloat:
+		fail("!cJSON_IsNumber(" + src + ")")
+		w.line(indent, "%s = %s->valuedouble;", dst, src)
+	case kindBool:
+		fail("!cJSON_IsBool(" + src + ")")
+		w.line(indent, "%s = cJSON_IsTrue(%s);", dst, src)
+	case kindEnum:
+		fail(fmt.Sprintf("!cJSON_IsString(%s) || %s_from_string(%s->valuestring, &%s) != 0", src, t.ref.base, src, dst))
+	case kindStruct:
+		if t.pointer {
+			w.line(indent, "%s = calloc(1, sizeof(*%s));", dst, dst)
+			fail(fmt.Sprintf("%s == NULL || %s_from_json(%s, %s) != 0", dst, t.ref.base, src, dst))
+		} else {
+			fail(fmt.Sprintf("%s_from_json(%s, &%s) != 0", t.ref.base, src, dst))
+		}
+	default:
+		w.line(indent, "%s = cJSON_PrintUnformatted(%s);", dst, src)
+		fail(dst + " == NULL")
+	}
+}
+
+// free renders <type>_free, which frees the memory a struct owns, but not
+// the struct itself
+func (w *implWriter) free(d *typeDecl) {
+	w.line(0, "void %s_free(%s *v)", d.base, d.name)
+	w.line(0, "{")
+	w.line(1, "if (v == NULL) {")
+	w.line(2, "return;")
+	w.line(1, "}")
+	for _, f := range d.fields {
+		src := "v->" + f.name
+		switch f.typ.kind {
+		case kindList:
+			if elem := release(f.typ.elem, src+"[i]"); elem != nil {
+				w.line(1, "for (size_t i = 0; i < %s_count; i++) {", src)
+				for _, stmt := range elem {
+					w.line(2, "%s", stmt)
+				}
+				w.line(1, "}")
+			}
+			w.line(1, "free(%s);", src)
+		case kindMap:
+			w.line(1, "for (size_t i = 0; i < %s_count; i++) {", src)
+			w.line(2, "free(%s_keys[i]);", src)
+			for _, stmt := range release(f.typ.elem, src+"_values[i]") {
+				w.line(2, "%s", stmt)
+			}
+			w.line(1, "}")
+			w.line(1, "free(%s_keys);", src)
+			w.line(1, "free(%s_values);", src)
+		default:
+			for _, stmt := range release(f.typ, src) {
+				w.line(1, "%s", stmt)
+			}
+		}
+	}
+	w.line(1, "memset(v, 0, sizeof(*v));")
+	w.line(0, "}")
+}
+
+// release renders the statements freeing a value, or none for values
+// owning no memory
+func release(t *ctype, src string) []string {
+	switch t.kind {
+	case kindString, kindJSON:
+		return []string{"free(" + src + ");"}
+	case kindStruct:
+		if t.pointer {
+			return []string{t.ref.base + "_free(" + src + ");", "free(" + src + ");"}
+		}
+		return []string{t.ref.base + "_free(&" + src + ");"}
+	}
+	return nil
+}
@@ -0,0 +1,785 @@
+package c
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates a C header with structs and enums from CUE, and
+// optionally cJSON serialization helpers
+type Generator struct{}
+
+// NewGenerator creates a new C header generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "c"
+}
+
+// kind is how a value is stored and serialized
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindEnum
+	kindStruct
+	kindList
+	kindMap
+	kindJSON // any other value, kept as its JSON text
+)
+
+// ctype is the C type of a field, list element or map value
+type ctype struct {
+	decl    string // type in declarations, e.g. int32_t or shop_order_t
+	kind    kind
+	ref     *typeDecl // enum or struct, for the names of its helpers
+	elem    *ctype    // element of a list, value of a map
+	pointer bool      // struct held by pointer: optional, nullable or recursive
+	min     string    // bounds of an integer type, checked when reading JSON
+	max     string
+}
+
+// typeDecl is a struct, enum or scalar typedef declared in the header
+type typeDecl struct {
+	base   string // prefix and snake_case name, e.g. shop_order
+	name   string // type name, e.g. shop_order_t
+	doc    string
+	enum   []enumValue
+	alias  *ctype // scalar typedef
+	fields []*field
+}
+
+// enumValue is an enumerator with the string it serializes to
+type enumValue struct {
+	name  string
+	value string
+}
+
+// field is a struct member
+type field struct {
+	name     string // snake_case C name
+	key      string // JSON key
+	doc      string
+	typ      *ctype
+	optional bool
+	nullable bool
+}
+
+// builder converts definitions into type declarations
+type builder struct {
+	prefix  string
+	defs    map[string]*typeDecl // CUE definition name -> declaration
+	enums   []*typeDecl
+	aliases []*typeDecl
+	structs []*typeDecl
+	names   map[string]bool
+}
+
+// Generate generates the header
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	b := &builder{
+		prefix: ctx.GetStringOption("prefix", ""),
+		defs:   make(map[string]*typeDecl),
+		names:  make(map[string]bool),
+	}
+	for name := range standardTypes {
+		b.names[name] = true
+	}
+
+	// Declare the definitions first, so references resolve in any order
+	var names []string
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		val := defs[name]
+		d := &typeDecl{doc: platoCue.Description(val)}
+		switch {
+		case len(stringEnum(val)) > 0:
+			b.enums = append(b.enums, d)
+		case val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !isMixed(val):
+			b.structs = append(b.structs, d)
+		case scalarKind(val):
+			b.aliases = append(b.aliases, d)
+		default:
+			// Lists, maps and unions are written where they are used
+			continue
+		}
+		d.base = b.uniqueName(b.prefix + toSnakeCase(name))
+		d.name = d.base + "_t"
+		if values := stringEnum(val); len(values) > 0 {
+			d.enum = enumValues(d.base, values)
+		} else if scalarKind(val) {
+			d.alias = b.scalar(val)
+		}
+		b.defs[name] = d
+	}
+
+	for _, name := range names {
+		d, ok := b.defs[name]
+		if !ok || d.enum != nil || d.alias != nil {
+			continue
+		}
+		if err := b.fields(d, defs[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	guard := includeGuard(ctx)
+	cjson := ctx.GetBoolOption("cjson", false)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "#ifndef %s\n#define %s\n\n", guard, guard)
+	buf.WriteString("#include <stdbool.h>\n#include <stddef.h>\n#include <stdint.h>\n")
+	if cjson {
+		fmt.Fprintf(&buf, "\n#include %s\n", includePath(ctx.GetStringOption("cjsonInclude", "cJSON.h")))
+	}
+	buf.WriteString("\n#ifdef __cplusplus\nextern \"C\" {\n#endif\n")
+
+	for _, d := range b.enums {
+		buf.WriteString("\n")
+		writeEnum(&buf, d)
+	}
+	for _, d := range b.aliases {
+		buf.WriteString("\n")
+		writeDoc(&buf, d.doc, "")
+		fmt.Fprintf(&buf, "typedef %s%s;\n", declPrefix(d.alias.decl), d.name)
+	}
+
+	structs := b.order()
+	if len(structs) > 0 {
+		buf.WriteString("\n")
+		for _, d := range structs {
+			fmt.Fprintf(&buf, "typedef struct %s %s;\n", d.base, d.name)
+		}
+	}
+	for _, d := range structs {
+		buf.WriteString("\n")
+		writeStruct(&buf, d)
+	}
+
+	if cjson {
+		buf.WriteString("\n")
+		for _, d := range b.enums {
+			fmt.Fprintf(&buf, "const char *%s_to_string(%s value);\n", d.base, d.name)
+			fmt.Fprintf(&buf, "int %s_from_string(const char *s, %s *out);\n", d.base, d.name)
+		}
+		for _, d := range structs {
+			fmt.Fprintf(&buf, "cJSON *%s_to_json(const %s *v);\n", d.base, d.name)
+			fmt.Fprintf(&buf, "int %s_from_json(const cJSON *json, %s *out);\n", d.base, d.name)
+			fmt.Fprintf(&buf, "void %s_free(%s *v);\n", d.base, d.name)
+		}
+	}
+
+	buf.WriteString("\n#ifdef __cplusplus\n}\n#endif\n\n")
+	fmt.Fprintf(&buf, "#endif /* %s */\n", guard)
+
+	if cjson {
+		impl := strings.TrimSuffix(guard, "_H") + "_IMPLEMENTATION"
+		done := strings.TrimSuffix(guard, "_H") + "_IMPLEMENTED"
+		fmt.Fprintf(&buf, "\n#if defined(%s) && !defined(%s)\n#define %s\n\n", impl, done, done)
+		buf.WriteString("#include <stdlib.h>\n#include <string.h>\n")
+		writeStrdup(&buf, b.prefix)
+		for _, d := range b.enums {
+			buf.WriteString("\n")
+			writeEnumHelpers(&buf, d)
+		}
+		for _, d := range structs {
+			w := &implWriter{buf: &buf, prefix: b.prefix}
+			buf.WriteString("\n")
+			w.toJSON(d)
+			buf.WriteString("\n")
+			w.fromJSON(d)
+			buf.WriteString("\n")
+			w.free(d)
+		}
+		fmt.Fprintf(&buf, "\n#endif /* %s */\n", impl)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if prefix := ctx.GetStringOption("prefix", ""); prefix != "" && !isIdentifier(prefix) {
+		return fmt.Errorf("invalid prefix %q: must be a C identifier, e.g. shop_", prefix)
+	}
+	if guard := ctx.GetStringOption("guard", ""); guard != "" && !isIdentifier(guard) {
+		return fmt.Errorf("invalid include guard %q", guard)
+	}
+	return nil
+}
+
+// fields adds the members of a struct, declaring structs and enums for
+// inline structs and enums, named after the struct and field
+func (b *builder) fields(d *typeDecl, val cue.Value) error {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+		name := identifier(toSnakeCase(label))
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s_%d", name, i)
+		}
+		used[unique] = true
+
+		typ, err := b.typeOf(d.base+"_"+toSnakeCase(label), fieldVal)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", label, err)
+		}
+		f := &field{
+			name:     unique,
+			key:      label,
+			doc:      platoCue.Description(iter.Value()),
+			typ:      typ,
+			optional: iter.IsOptional(),
+			nullable: nullable,
+		}
+		if typ.kind == kindStruct && (f.optional || f.nullable) {
+			typ.pointer = true
+		}
+		d.fields = append(d.fields, f)
+	}
+	return nil
+}
+
+// typeOf maps a value to a C type; base names structs and enums declared
+// for it
+func (b *builder) typeOf(base string, val cue.Value) (*ctype, error) {
+	if ref, ok := reference(val); ok {
+		if d, ok := b.defs[ref]; ok {
+			switch {
+			case d.enum != nil:
+				return &ctype{decl: d.name, kind: kindEnum, ref: d}, nil
+			case d.alias != nil:
+				t := *d.alias
+				t.decl = d.name
+				return &t, nil
+			default:
+				return &ctype{decl: d.name, kind: kindStruct, ref: d}, nil
+			}
+		}
+	}
+	if isMixed(val) {
+		return &ctype{decl: "char *", kind: kindJSON}, nil
+	}
+	if values := stringEnum(val); len(values) > 0 {
+		d := &typeDecl{base: b.uniqueName(base)}
+		d.name = d.base + "_t"
+		d.enum = enumValues(d.base, values)
+		b.enums = append(b.enums, d)
+		return &ctype{decl: d.name, kind: kindEnum, ref: d}, nil
+	}
+
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return &ctype{decl: "char *", kind: kindJSON}, nil
+		}
+		elem, _ = platoCue.StripNull(elem)
+		et, err := b.element(base+"_item", elem)
+		if err != nil {
+			return nil, err
+		}
+		return &ctype{decl: et.decl, kind: kindList, elem: et}, nil
+	case cue.StructKind:
+		if platoCue.IsMap(val) {
+			value, _ := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			vt, err := b.element(base+"_value", value)
+			if err != nil {
+				return nil, err
+			}
+			return &ctype{decl: vt.decl, kind: kindMap, elem: vt}, nil
+		}
+		if val.IncompleteKind() != cue.StructKind || !platoCue.HasFields(val) {
+			return &ctype{decl: "char *", kind: kindJSON}, nil
+		}
+		d := &typeDecl{base: b.uniqueName(base)}
+		d.name = d.base + "_t"
+		b.structs = append(b.structs, d)
+		if err := b.fields(d, val); err != nil {
+			return nil, err
+		}
+		return &ctype{decl: d.name, kind: kindStruct, ref: d}, nil
+	}
+	return b.scalar(val), nil
+}
+
+// element maps a list element or map value; nested lists and maps are kept
+// as JSON text
+func (b *builder) element(base string, val cue.Value) (*ctype, error) {
+	t, err := b.typeOf(base, val)
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == kindList || t.kind == kindMap {
+		return &ctype{decl: "char *", kind: kindJSON}, nil
+	}
+	return t, nil
+}
+
+// scalar maps a string, number or bool. Integers get the smallest type
+// holding their bounds, e.g. uint8_t for int & >=0 & <=255, and int64_t
+// without bounds.
+func (b *builder) scalar(val cue.Value) *ctype {
+	switch val.IncompleteKind() {
+	case cue.StringKind, cue.BytesKind:
+		return &ctype{decl: "char *", kind: kindString}
+	case cue.IntKind:
+		return intType(val)
+	case cue.FloatKind, cue.NumberKind:
+		return &ctype{decl: "double", kind: kindFloat}
+	case cue.BoolKind:
+		return &ctype{decl: "bool", kind: kindBool}
+	}
+	return &ctype{decl: "char *", kind: kindJSON}
+}
+
+// intTypes are the integer types, smallest first
+var intTypes = []struct {
+	decl     string
+	min, max float64
+	cmin     string
+	cmax     string
+}{
+	{"uint8_t", 0, 1<<8 - 1, "0", "UINT8_MAX"},
+	{"int8_t", -1 << 7, 1<<7 - 1, "INT8_MIN", "INT8_MAX"},
+	{"uint16_t", 0, 1<<16 - 1, "0", "UINT16_MAX"},
+	{"int16_t", -1 << 15, 1<<15 - 1, "INT16_MIN", "INT16_MAX"},
+	{"uint32_t", 0, 1<<32 - 1, "0", "UINT32_MAX"},
+	{"int32_t", -1 << 31, 1<<31 - 1, "INT32_MIN", "INT32_MAX"},
+	{"uint64_t", 0, 1<<64 - 1, "0", "UINT64_MAX"},
+}
+
+// intType picks the integer type of an int from its bounds
+func intType(val cue.Value) *ctype {
+	c := platoCue.ConstraintsOf(val)
+	lo, hi := c.Minimum, c.Maximum
+	if lo == nil {
+		if c.ExclusiveMinimum != nil {
+			v := *c.ExclusiveMinimum + 1
+			lo = &v
+		}
+	}
+	if hi == nil {
+		if c.ExclusiveMaximum != nil {
+			v := *c.ExclusiveMaximum - 1
+			hi = &v
+		}
+	}
+	if lo != nil && hi != nil {
+		for _, t := range intTypes {
+			if *lo >= t.min && *hi <= t.max {
+				return &ctype{decl: t.decl, kind: kindInt, min: t.cmin, max: t.cmax}
+			}
+		}
+	}
+	return &ctype{decl: "int64_t", kind: kindInt, min: "INT64_MIN", max: "INT64_MAX"}
+}
+
+// order returns the structs with the structs they embed by value first.
+// A struct embedding itself, directly or through others, holds the
+// embedded struct by pointer instead.
+func (b *builder) order() []*typeDecl {
+	var ordered []*typeDecl
+	done := make(map[*typeDecl]bool)
+	visiting := make(map[*typeDecl]bool)
+	var visit func(d *typeDecl)
+	visit = func(d *typeDecl) {
+		if done[d] {
+			return
+		}
+		visiting[d] = true
+		for _, f := range d.fields {
+			if f.typ.kind != kindStruct || f.typ.pointer {
+				continue
+			}
+			if visiting[f.typ.ref] {
+				f.typ.pointer = true
+				continue
+			}
+			visit(f.typ.ref)
+		}
+		visiting[d] = false
+		done[d] = true
+		ordered = append(ordered, d)
+	}
+	for _, d := range b.structs {
+		visit(d)
+	}
+	return ordered
+}
+
+// standardTypes are the typedefs of the standard headers the header
+// includes, less their _t, which definitions must not shadow
+var standardTypes = map[string]bool{
+	"size": true, "ptrdiff": true, "wchar": true, "max_align": true,
+	"int8": true, "int16": true, "int32": true, "int64": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"intptr": true, "uintptr": true, "intmax": true, "uintmax": true,
+}
+
+// uniqueName reserves a name, adding a number if it is taken
+func (b *builder) uniqueName(name string) string {
+	return generator.UniqueName(b.names, name, "_")
+}
+
+// hasPresence reports whether a field has a has_ flag: optional and
+// nullable fields that cannot be NULL
+func (f *field) hasPresence() bool {
+	switch f.typ.kind {
+	case kindString, kindJSON, kindStruct:
+		return false
+	}
+	return f.optional || f.nullable
+}
+
+// writeEnum renders an enum with an enumerator per value
+func writeEnum(buf *bytes.Buffer, d *typeDecl) {
+	writeDoc(buf, d.doc, "")
+	buf.WriteString("typedef enum {\n")
+	for _, v := range d.enum {
+		fmt.Fprintf(buf, "    %s, /* %s */\n", v.name, commentText(quote(v.value)))
+	}
+	fmt.Fprintf(buf, "} %s;\n", d.name)
+}
+
+// writeStruct renders a struct. Lists are a pointer and a count, maps
+// parallel arrays of keys and values, and optional and nullable scalars
+// have a has_ flag.
+func writeStruct(buf *bytes.Buffer, d *typeDecl) {
+	writeDoc(buf, d.doc, "")
+	fmt.Fprintf(buf, "struct %s {\n", d.base)
+	for _, f := range d.fields {
+		writeDoc(buf, f.doc, "    ")
+		if f.hasPresence() {
+			fmt.Fprintf(buf, "    bool has_%s;\n", f.name)
+		}
+		switch f.typ.kind {
+		case kindList:
+			fmt.Fprintf(buf, "    %s*%s;%s\n", declPrefix(f.typ.elem.decl), f.name, memberNote(f))
+			fmt.Fprintf(buf, "    size_t %s_count;\n", f.name)
+		case kindMap:
+			fmt.Fprintf(buf, "    char **%s_keys;%s\n", f.name, memberNote(f))
+			fmt.Fprintf(buf, "    %s*%s_values;\n", declPrefix(f.typ.elem.decl), f.name)
+			fmt.Fprintf(buf, "    size_t %s_count;\n", f.name)
+		case kindStruct:
+			if f.typ.pointer {
+				fmt.Fprintf(buf, "    %s *%s;%s\n", f.typ.decl, f.name, memberNote(f))
+			} else {
+				fmt.Fprintf(buf, "    %s %s;%s\n", f.typ.decl, f.name, memberNote(f))
+			}
+		default:
+			fmt.Fprintf(buf, "    %s%s;%s\n", declPrefix(f.typ.decl), f.name, memberNote(f))
+		}
+	}
+	fmt.Fprintf(buf, "};\n")
+}
+
+// memberNote renders the comment after a member: its JSON key when the
+// name differs, and whether it is JSON text
+func memberNote(f *field) string {
+	var notes []string
+	if f.key != f.name {
+		notes = append(notes, fmt.Sprintf("key %q", f.key))
+	}
+	if f.typ.kind == kindJSON {
+		notes = append(notes, "JSON text")
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " /* " + commentText(strings.Join(notes, ", ")) + " */"
+}
+
+// writeDoc renders a doc comment
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, commentText(lines[0]))
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s * %s\n", indent, commentText(line))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// declPrefix writes a type before a name: pointer types are followed
+// directly by the name, others by a space
+func declPrefix(decl string) string {
+	if strings.HasSuffix(decl, "*") {
+		return decl
+	}
+	return decl + " "
+}
+
+// enumValues names the enumerators of values after the enum, e.g.
+// SHOP_STATUS_OPEN
+func enumValues(base string, values []string) []enumValue {
+	var result []enumValue
+	used := make(map[string]bool)
+	for _, v := range values {
+		name := strings.ToUpper(toSnakeCase(v))
+		if v == "" {
+			name = "EMPTY"
+		}
+		name = strings.ToUpper(base) + "_" + name
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s_%d", name, i)
+		}
+		used[unique] = true
+		result = append(result, enumValue{name: unique, value: v})
+	}
+	return result
+}
+
+// includeGuard returns the guard option, or a guard named after the
+// output file, e.g. SCHEMAS_H for schemas.h
+func includeGuard(ctx *generator.Context) string {
+	if guard := ctx.GetStringOption("guard", ""); guard != "" {
+		return guard
+	}
+	name := "schemas.h"
+	if output := ctx.GeneratorConfig.Output; output != "" {
+		name = filepath.Base(output)
+	}
+	guard := strings.ToUpper(toSnakeCase(strings.ReplaceAll(name, ".", "_")))
+	if !strings.HasSuffix(guard, "_H") {
+		guard += "_H"
+	}
+	return guard
+}
+
+// includePath quotes the cJSON include, unless it is already in quotes or
+// angle brackets
+func includePath(path string) string {
+	if strings.HasPrefix(path, "<") || strings.HasPrefix(path, `"`) {
+		return path
+	}
+	return `"` + path + `"`
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+// isMixed reports whether a value is a disjunction of different kinds or
+// of several structs, which C has no type for
+func isMixed(val cue.Value) bool {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return false
+	}
+	var rest []cue.Value
+	for _, arg := range args {
+		if arg.IncompleteKind() != cue.NullKind {
+			rest = append(rest, arg)
+		}
+	}
+	structs := 0
+	for _, arg := range rest {
+		if kindOf(arg) != kindOf(rest[0]) {
+			return true
+		}
+		if arg.IncompleteKind() == cue.StructKind {
+			structs++
+		}
+	}
+	return structs > 1
+}
+
+// kindOf returns the kind of a value, with ints and floats as numbers
+func kindOf(val cue.Value) cue.Kind {
+	kind := val.IncompleteKind()
+	if kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0 {
+		return cue.NumberKind
+	}
+	return kind
+}
+
+// scalarKind reports whether a value is a string, number or bool
+func scalarKind(val cue.Value) bool {
+	switch val.IncompleteKind() {
+	case cue.StringKind, cue.BytesKind, cue.IntKind, cue.FloatKind, cue.NumberKind, cue.BoolKind:
+		return !isMixed(val)
+	}
+	return false
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// keywords are C keywords and the macros of stdbool.h and stddef.h
+var keywords = map[string]bool{
+	"auto": true, "break": true, "case": true, "char": true, "const": true, "continue": true,
+	"default": true, "do": true, "double": true, "else": true, "enum": true, "extern": true,
+	"float": true, "for": true, "goto": true, "if": true, "inline": true, "int": true,
+	"long": true, "register": true, "restrict": true, "return": true, "short": true,
+	"signed": true, "sizeof": true, "static": true, "struct": true, "switch": true,
+	"typedef": true, "union": true, "unsigned": true, "void": true, "volatile": true,
+	"while": true, "bool": true, "true": true, "false": true, "NULL": true,
+}
+
+// identifier escapes C keywords with a trailing underscore
+func identifier(name string) string {
+	if keywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// isIdentifier reports whether s is a C identifier
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// toSnakeCase converts a definition name, label or enum value to a C
+// identifier. Letters are transliterated to ASCII, and other runes escaped,
+// as not every compiler takes UTF-8 identifiers.
+func toSnakeCase(name string) string {
+	name = generator.UnicodeName(generator.UnicodeTransliterate, strings.TrimPrefix(name, "#"), generator.EscapeRune)
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "v" + s
+	}
+	return s
+}
+
+// quote renders a C string literal, escaping non-ASCII bytes so the
+// header is valid in any source character set
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			// Octal escapes end after three digits, unlike hex ones
+			fmt.Fprintf(&b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// commentText keeps text from closing a block comment
+func commentText(s string) string {
+	return strings.ReplaceAll(s, "*/", "* /")
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("cheader", "c")
+}
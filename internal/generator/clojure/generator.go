@@ -0,0 +1,301 @@
+package clojure
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates clojure.spec definitions or malli schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Clojure generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "clojure"
+}
+
+// namespaceName matches namespaces such as my-app.schemas
+var namespaceName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*)*$`)
+
+// keywordName matches field names that can be written as keywords, e.g.
+// :email or :created-at
+var keywordName = regexp.MustCompile(`^[A-Za-z*+!_?<>=-][A-Za-z0-9*+!_?<>=.'-]*$`)
+
+// definition is a CUE definition with its Clojure names
+type definition struct {
+	Name string // keyword name in the generated namespace, e.g. user
+	Var  string // malli var name, e.g. User
+	Val  cue.Value
+}
+
+// Generate generates a Clojure namespace
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	ns, style, err := options(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	// Every definition gets a keyword, so references to scalar and list
+	// definitions keep their names too
+	names := make(map[string]bool)
+	vars := make(map[string]bool)
+	keys := make(map[string]string)
+	var ordered []definition
+	for _, name := range cueNames {
+		d := definition{
+			Name: generator.UniqueName(names, toKebabCase(name), "-"),
+			Var:  generator.UniqueName(vars, toPascalCase(name), ""),
+			Val:  defs[name],
+		}
+		keys[name] = d.Name
+		ordered = append(ordered, d)
+	}
+
+	if style == "malli" {
+		return generateMalli(ns, keys, ordered), nil
+	}
+	return generateSpec(ns, keys, names, ordered), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, _, err := options(ctx)
+	return err
+}
+
+// options returns the namespace (platosl.types by default) and the style,
+// spec or malli
+func options(ctx *generator.Context) (string, string, error) {
+	ns := ctx.GetStringOption("namespace", "platosl.types")
+	if !namespaceName.MatchString(ns) {
+		return "", "", fmt.Errorf("invalid Clojure namespace %q", ns)
+	}
+	style := ctx.GetStringOption("style", "spec")
+	if style != "spec" && style != "malli" {
+		return "", "", fmt.Errorf("unknown style %q (expected spec or malli)", style)
+	}
+	return ns, style, nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// reference returns the keyword name of the definition a value refers to
+func reference(keys map[string]string, val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		name, ok := keys[path.String()]
+		return name, ok
+	}
+	return "", false
+}
+
+// kindName names the kind of a value, for s/or tags
+func kindName(val cue.Value) string {
+	switch kind := val.IncompleteKind(); {
+	case kind == cue.StringKind:
+		return "string"
+	case kind == cue.IntKind:
+		return "int"
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return "number"
+	case kind == cue.BoolKind:
+		return "boolean"
+	case kind == cue.ListKind:
+		return "list"
+	case kind == cue.StructKind:
+		return "map"
+	default:
+		return "any"
+	}
+}
+
+// stringEnum returns the distinct members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		if !seen[s] {
+			seen[s] = true
+			members = append(members, s)
+		}
+	}
+	return members
+}
+
+// words splits a label or value into words at separators and case changes
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			result = append(result, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// toKebabCase converts a definition name or label to a keyword name, e.g.
+// #OrderItem to order-item
+func toKebabCase(name string) string {
+	parts := words(strings.TrimPrefix(name, "#"))
+	for i, w := range parts {
+		parts[i] = strings.ToLower(w)
+	}
+	s := strings.Join(parts, "-")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "t-" + s
+	}
+	return s
+}
+
+// toPascalCase converts a definition name to a var name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(strings.TrimPrefix(name, "#")) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+// quote renders a Clojure string literal
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// regex renders a regex literal. The reader passes backslash escapes through
+// to the pattern unchanged, so only bare quotes need escaping.
+func regex(pattern string) string {
+	var b strings.Builder
+	b.WriteString(`#"`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// number renders a bound, without a fraction when it is integral
+func number(f float64) string {
+	if f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// comment renders a doc comment as ;; lines
+func comment(indent, doc string) string {
+	if doc == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(doc, "\n") {
+		b.WriteString(strings.TrimRight(indent+";; "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("clj", "clojure")
+}
@@ -0,0 +1,264 @@
+package clojure
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// item renders an element of a vector starting at the given column
+type item func(column int) string
+
+// generateMalli renders a namespace with a registry of malli schemas, one
+// per definition, and a var with the compiled schema of each definition.
+// Definitions refer to each other with :ref, e.g. [:ref ::address].
+func generateMalli(ns string, keys map[string]string, defs []definition) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(";; Generated by PlatoSL\n")
+	buf.WriteString(";; DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "(ns %s\n", ns)
+	buf.WriteString("  \"Malli schemas generated from CUE schemas. Maps have keyword keys,\n")
+	buf.WriteString("  e.g. JSON read with (json/read-str s :key-fn keyword).\"\n")
+	buf.WriteString("  (:require [malli.core :as m]\n")
+	buf.WriteString("            [malli.registry :as mr]))\n\n")
+
+	buf.WriteString("(def registry\n")
+	buf.WriteString("  \"Schemas of the definitions, by qualified keyword\"\n")
+	buf.WriteString("  (mr/composite-registry\n")
+	buf.WriteString("   (m/default-schemas)\n")
+	buf.WriteString("   {")
+	for i, d := range defs {
+		if i > 0 {
+			buf.WriteString("\n\n    ")
+		}
+		key := "::" + d.Name
+		inline := schema(keys, d.Val, 4+len(key)+1)
+		if !strings.Contains(inline, "\n") {
+			buf.WriteString(key + " " + inline)
+		} else {
+			buf.WriteString(key + "\n    " + schema(keys, d.Val, 4))
+		}
+	}
+	buf.WriteString("}))\n")
+
+	for _, d := range defs {
+		fmt.Fprintf(&buf, "\n(def %s\n", d.Var)
+		if doc := platoCue.Description(d.Val); doc != "" {
+			fmt.Fprintf(&buf, "  %s\n", quote(doc))
+		}
+		fmt.Fprintf(&buf, "  (m/schema ::%s {:registry registry}))\n", d.Name)
+	}
+
+	return buf.Bytes()
+}
+
+// schema renders the malli schema of a value starting at the given column
+func schema(keys map[string]string, val cue.Value, column int) string {
+	// :ref resolves lazily, so definitions can refer to themselves
+	if key, ok := reference(keys, val); ok {
+		return "[:ref ::" + key + "]"
+	}
+	if members := stringEnum(val); len(members) > 0 {
+		quoted := make([]string, len(members))
+		for i, m := range members {
+			quoted[i] = quote(m)
+		}
+		return "[:enum " + strings.Join(quoted, " ") + "]"
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]item, len(alts))
+		for i, alt := range alts {
+			alt := alt
+			items[i] = func(c int) string { return schema(keys, alt, c) }
+		}
+		return maybe(column, nullable, func(c int) string { return vector(c, ":or", items...) })
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if val.IncompleteKind() == cue.NullKind {
+		return ":nil"
+	}
+	c := platoCue.ConstraintsOf(val)
+
+	var render item
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		var parts []string
+		if props := lengthProps(c); props != "" {
+			parts = append(parts, "[:string "+props+"]")
+		}
+		for _, p := range c.Patterns {
+			parts = append(parts, "[:re "+regex(p)+"]")
+		}
+		render = constant(":string")
+		switch {
+		case len(parts) == 1:
+			render = constant(parts[0])
+		case len(parts) > 1:
+			render = constant("[:and " + strings.Join(parts, " ") + "]")
+		}
+	case kind == cue.IntKind:
+		var props []string
+		if c.Minimum != nil {
+			props = append(props, ":min "+number(*c.Minimum))
+		}
+		if c.Maximum != nil {
+			props = append(props, ":max "+number(*c.Maximum))
+		}
+		base := ":int"
+		if len(props) > 0 {
+			base = "[:int {" + strings.Join(props, " ") + "}]"
+		}
+		render = constant(allOf(base, exclusive(c)))
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		var preds []string
+		if c.Minimum != nil {
+			preds = append(preds, "[:>= "+number(*c.Minimum)+"]")
+		}
+		if c.Maximum != nil {
+			preds = append(preds, "[:<= "+number(*c.Maximum)+"]")
+		}
+		render = constant(allOf("number?", append(preds, exclusive(c)...)))
+	case kind == cue.BoolKind:
+		render = constant(":boolean")
+	case kind == cue.ListKind:
+		elem := constant(":any")
+		if v := val.LookupPath(cue.MakePath(cue.AnyIndex)); v.Exists() {
+			elem = func(col int) string { return schema(keys, v, col) }
+		}
+		head := ":sequential"
+		if props := lengthProps(c); props != "" {
+			head += " " + props
+		}
+		render = func(col int) string { return vector(col, head, elem) }
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			value := val.LookupPath(cue.MakePath(cue.AnyString))
+			render = func(col int) string {
+				return vector(col, ":map-of :keyword", func(c int) string { return schema(keys, value, c) })
+			}
+		case !platoCue.HasFields(val):
+			render = constant(":map")
+		default:
+			render = func(col int) string { return mapSchema(keys, val, col) }
+		}
+	default:
+		render = constant(":any")
+	}
+
+	return maybe(column, nullable, render)
+}
+
+// mapSchema renders a struct as a :map schema with one field per line
+func mapSchema(keys map[string]string, val cue.Value, column int) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return ":map"
+	}
+
+	indent := strings.Repeat(" ", column+1)
+	var b strings.Builder
+	b.WriteString("[:map")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		head := "[:" + label
+		if !keywordName.MatchString(label) {
+			head = "[(keyword " + quote(label) + ")"
+		}
+		var props []string
+		if iter.IsOptional() {
+			props = append(props, ":optional true")
+		}
+		if doc := platoCue.Description(fieldVal); doc != "" {
+			props = append(props, ":description "+quote(doc))
+		}
+		if len(props) > 0 {
+			head += " {" + strings.Join(props, " ") + "}"
+		}
+
+		b.WriteString("\n" + indent)
+		field := head + " " + schema(keys, fieldVal, column+1+utf8.RuneCountInString(head)+1) + "]"
+		if strings.Contains(field, "\n") {
+			field = head + "\n" + indent + " " + schema(keys, fieldVal, column+2) + "]"
+		}
+		b.WriteString(field)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// vector renders [head item...], each item rendered at the column it starts at
+func vector(column int, head string, items ...item) string {
+	s := "[" + head
+	for _, it := range items {
+		s += " " + it(end(column, s)+1)
+	}
+	return s + "]"
+}
+
+// end returns the column after a rendered string that started at column
+func end(column int, s string) int {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return utf8.RuneCountInString(s[i+1:])
+	}
+	return column + utf8.RuneCountInString(s)
+}
+
+// maybe renders a schema, wrapped in :maybe when the value may be null
+func maybe(column int, nullable bool, render item) string {
+	if nullable {
+		return vector(column, ":maybe", render)
+	}
+	return render(column)
+}
+
+// constant renders a fixed single-line schema
+func constant(s string) item {
+	return func(int) string { return s }
+}
+
+// allOf combines a base schema with extra checks in :and
+func allOf(base string, preds []string) string {
+	if len(preds) == 0 {
+		return base
+	}
+	return "[:and " + base + " " + strings.Join(preds, " ") + "]"
+}
+
+// exclusive returns :> and :< schemas for exclusive bounds
+func exclusive(c platoCue.Constraints) []string {
+	var preds []string
+	if c.ExclusiveMinimum != nil {
+		preds = append(preds, "[:> "+number(*c.ExclusiveMinimum)+"]")
+	}
+	if c.ExclusiveMaximum != nil {
+		preds = append(preds, "[:< "+number(*c.ExclusiveMaximum)+"]")
+	}
+	return preds
+}
+
+// lengthProps renders :min and :max properties for lengths, e.g. {:min 1}
+func lengthProps(c platoCue.Constraints) string {
+	var props []string
+	if c.MinLength != nil {
+		props = append(props, fmt.Sprintf(":min %d", *c.MinLength))
+	}
+	if c.MaxLength != nil {
+		props = append(props, fmt.Sprintf(":max %d", *c.MaxLength))
+	}
+	if len(props) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(props, " ") + "}"
+}
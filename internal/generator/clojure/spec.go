@@ -0,0 +1,236 @@
+package clojure
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// specWriter renders definitions as clojure.spec forms
+type specWriter struct {
+	ns    string
+	keys  map[string]string // CUE definition name -> keyword name
+	names map[string]bool   // keyword names in use
+	queue []nestedSpec      // nested structs still to define
+	buf   bytes.Buffer
+}
+
+// nestedSpec is a struct nested in a field, named after its parents, e.g.
+// article-author
+type nestedSpec struct {
+	Name string
+	Val  cue.Value
+}
+
+// generateSpec renders a namespace of specs. Structs become s/keys specs
+// with unqualified keys, so payloads are checked with the keyword keys a
+// JSON reader produces, e.g. (json/read-str s :key-fn keyword).
+func generateSpec(ns string, keys map[string]string, names map[string]bool, defs []definition) []byte {
+	w := &specWriter{ns: ns, keys: keys, names: names}
+
+	w.buf.WriteString(";; Generated by PlatoSL\n")
+	w.buf.WriteString(";; DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&w.buf, "(ns %s\n", ns)
+	w.buf.WriteString("  \"Specs generated from CUE schemas. Maps are checked with unqualified\n")
+	w.buf.WriteString("  keyword keys, e.g. JSON read with (json/read-str s :key-fn keyword).\"\n")
+	w.buf.WriteString("  (:require [clojure.spec.alpha :as s]))\n")
+
+	for _, d := range defs {
+		w.buf.WriteString("\n")
+		w.define(d.Name, platoCue.Description(d.Val), d.Val)
+		for len(w.queue) > 0 {
+			n := w.queue[0]
+			w.queue = w.queue[1:]
+			w.buf.WriteString("\n")
+			w.define(n.Name, "", n.Val)
+		}
+	}
+
+	return w.buf.Bytes()
+}
+
+// define renders the specs of a definition or nested struct
+func (w *specWriter) define(name, doc string, val cue.Value) {
+	w.buf.WriteString(comment("", doc))
+
+	if _, ok := reference(w.keys, val); ok || val.IncompleteKind() != cue.StructKind || platoCue.IsMap(val) || !platoCue.HasFields(val) {
+		expr, nullable := w.expr(name, val)
+		fmt.Fprintf(&w.buf, "(s/def ::%s %s)\n", name, nilable(expr, nullable))
+		return
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		fmt.Fprintf(&w.buf, "(s/def ::%s map?)\n", name)
+		return
+	}
+
+	fieldNS := w.ns + "." + name
+	var req, opt []string
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		if !keywordName.MatchString(label) {
+			fmt.Fprintf(&w.buf, ";; %s is not a valid keyword and is not checked\n", quote(label))
+			continue
+		}
+
+		expr, nullable := w.expr(name+"-"+toKebabCase(label), iter.Value())
+		key := ":" + fieldNS + "/" + label
+		w.buf.WriteString(comment("", platoCue.Description(iter.Value())))
+		fmt.Fprintf(&w.buf, "(s/def %s %s)\n", key, nilable(expr, nullable))
+
+		if iter.IsOptional() {
+			opt = append(opt, key)
+		} else {
+			req = append(req, key)
+		}
+	}
+
+	fmt.Fprintf(&w.buf, "(s/def ::%s\n", name)
+	w.buf.WriteString("  (s/keys")
+	sep := " "
+	if len(req) > 0 {
+		w.buf.WriteString(sep + ":req-un " + keyVector(req, 19))
+		sep = "\n          "
+	}
+	if len(opt) > 0 {
+		w.buf.WriteString(sep + ":opt-un " + keyVector(opt, 19))
+	}
+	w.buf.WriteString("))\n")
+}
+
+// expr returns the spec of a value and whether it may be null; typeName
+// names nested structs defined for it
+func (w *specWriter) expr(typeName string, val cue.Value) (string, bool) {
+	if key, ok := reference(w.keys, val); ok {
+		return "::" + key, false
+	}
+	if members := stringEnum(val); len(members) > 0 {
+		quoted := make([]string, len(members))
+		for i, m := range members {
+			quoted[i] = quote(m)
+		}
+		return "#{" + strings.Join(quoted, " ") + "}", false
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		tags := make(map[string]bool)
+		parts := []string{"(s/or"}
+		for i, alt := range alts {
+			tag := kindName(alt)
+			if key, ok := reference(w.keys, alt); ok {
+				tag = key
+			}
+			expr, _ := w.expr(fmt.Sprintf("%s-%d", typeName, i+1), alt)
+			parts = append(parts, ":"+generator.UniqueName(tags, tag, "-"), expr)
+		}
+		return strings.Join(parts, " ") + ")", nullable
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if val.IncompleteKind() == cue.NullKind {
+		return "nil?", false
+	}
+	c := platoCue.ConstraintsOf(val)
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		preds := append([]string{"string?"}, between("(count %)", c.MinLength, c.MaxLength)...)
+		for _, p := range c.Patterns {
+			preds = append(preds, fmt.Sprintf("#(re-find %s %%)", regex(p)))
+		}
+		return and(preds), nullable
+	case kind == cue.IntKind:
+		return and(append([]string{"int?"}, bounds(c)...)), nullable
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return and(append([]string{"number?"}, bounds(c)...)), nullable
+	case kind == cue.BoolKind:
+		return "boolean?", nullable
+	case kind == cue.ListKind:
+		elem := "any?"
+		if v := val.LookupPath(cue.MakePath(cue.AnyIndex)); v.Exists() {
+			elem = nilable(w.expr(typeName+"-item", v))
+		}
+		spec := "(s/coll-of " + elem + " :kind sequential?"
+		if c.MinLength != nil {
+			spec += fmt.Sprintf(" :min-count %d", *c.MinLength)
+		}
+		if c.MaxLength != nil {
+			spec += fmt.Sprintf(" :max-count %d", *c.MaxLength)
+		}
+		return spec + ")", nullable
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			value := nilable(w.expr(typeName+"-value", val.LookupPath(cue.MakePath(cue.AnyString))))
+			return "(s/map-of keyword? " + value + ")", nullable
+		}
+		if !platoCue.HasFields(val) {
+			return "map?", nullable
+		}
+		name := generator.UniqueName(w.names, typeName, "-")
+		w.queue = append(w.queue, nestedSpec{Name: name, Val: val})
+		return "::" + name, nullable
+	default:
+		return "any?", nullable
+	}
+}
+
+// nilable wraps the spec of a nullable value in s/nilable
+func nilable(expr string, nullable bool) string {
+	if nullable {
+		return "(s/nilable " + expr + ")"
+	}
+	return expr
+}
+
+// and combines predicates with s/and
+func and(preds []string) string {
+	if len(preds) == 1 {
+		return preds[0]
+	}
+	return "(s/and " + strings.Join(preds, " ") + ")"
+}
+
+// bounds returns predicates for numeric bounds
+func bounds(c platoCue.Constraints) []string {
+	var preds []string
+	if c.Minimum != nil && c.Maximum != nil {
+		preds = append(preds, fmt.Sprintf("#(<= %s %% %s)", number(*c.Minimum), number(*c.Maximum)))
+	} else if c.Minimum != nil {
+		preds = append(preds, fmt.Sprintf("#(>= %% %s)", number(*c.Minimum)))
+	} else if c.Maximum != nil {
+		preds = append(preds, fmt.Sprintf("#(<= %% %s)", number(*c.Maximum)))
+	}
+	if c.ExclusiveMinimum != nil {
+		preds = append(preds, fmt.Sprintf("#(> %% %s)", number(*c.ExclusiveMinimum)))
+	}
+	if c.ExclusiveMaximum != nil {
+		preds = append(preds, fmt.Sprintf("#(< %% %s)", number(*c.ExclusiveMaximum)))
+	}
+	return preds
+}
+
+// between returns a predicate bounding an expression, e.g. a string's count
+func between(expr string, min, max *int) []string {
+	switch {
+	case min != nil && max != nil:
+		return []string{fmt.Sprintf("#(<= %d %s %d)", *min, expr, *max)}
+	case min != nil:
+		return []string{fmt.Sprintf("#(>= %s %d)", expr, *min)}
+	case max != nil:
+		return []string{fmt.Sprintf("#(<= %s %d)", expr, *max)}
+	}
+	return nil
+}
+
+// keyVector renders keys one per line, aligned at the given column
+func keyVector(keys []string, column int) string {
+	return "[" + strings.Join(keys, "\n"+strings.Repeat(" ", column)) + "]"
+}
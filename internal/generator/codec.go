@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codecs lists the binary codecs generators can emit alongside JSON
+var Codecs = []string{"msgpack", "cbor"}
+
+// CodecsOption returns the binary codecs selected with the "codecs" option,
+// given as a list or a comma-separated string, in the order of Codecs
+func (c *Context) CodecsOption() ([]string, error) {
+	var names []string
+	switch v := c.Options["codecs"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = strings.Split(v, ",")
+	case []string:
+		names = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid codecs option: %v is not a string", item)
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid codecs option: expected a list of %s", strings.Join(Codecs, ", "))
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, codec := range Codecs {
+			if name == codec {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown codec %q (available: %s)", name, strings.Join(Codecs, ", "))
+		}
+		selected[name] = true
+	}
+
+	var codecs []string
+	for _, codec := range Codecs {
+		if selected[codec] {
+			codecs = append(codecs, codec)
+		}
+	}
+	return codecs, nil
+}
@@ -0,0 +1,439 @@
+package csharp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates C# records with System.Text.Json attributes from CUE
+type Generator struct{}
+
+// NewGenerator creates a new C# generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "csharp"
+}
+
+// record is a C# record built from a struct
+type record struct {
+	Name       string
+	Doc        string
+	Properties []property
+}
+
+// property is a record property
+type property struct {
+	Name     string // PascalCase C# name
+	JSONName string // original label
+	Type     string
+	Required bool
+	Optional bool // omitted from JSON when null
+	Doc      string
+}
+
+// enum is a C# enum built from a disjunction of strings
+type enum struct {
+	Name    string
+	Doc     string
+	Members []enumMember
+}
+
+// enumMember is an enum member with the string it serializes to
+type enumMember struct {
+	Name  string
+	Value string
+}
+
+// builder converts definitions into records and enums
+type builder struct {
+	defTypes map[string]string // CUE definition name -> C# type name
+	types    []interface{}     // *record and *enum, in output order
+	names    map[string]bool   // type names in use
+}
+
+// Generate generates C# code
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	b := &builder{
+		defTypes: make(map[string]string),
+		names:    make(map[string]bool),
+	}
+
+	// Only struct and string enum definitions become types; references to
+	// other definitions, unions of structs included, use the referenced
+	// type directly
+	var names []string
+	for name, val := range defs {
+		if val.IncompleteKind() == cue.StructKind && !platoCue.IsMap(val) && !platoCue.IsUnion(val) || len(stringEnum(val)) > 0 {
+			b.defTypes[name] = toPascalCase(name)
+			b.names[b.defTypes[name]] = true
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		typeName, ok := b.defTypes[name]
+		if !ok {
+			continue
+		}
+		doc := platoCue.Description(defs[name])
+		if members := stringEnum(defs[name]); len(members) > 0 {
+			b.types = append(b.types, newEnum(typeName, doc, members))
+			continue
+		}
+		if _, err := b.record(typeName, doc, defs[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("#nullable enable\n\n")
+	buf.WriteString("using System;\n")
+	buf.WriteString("using System.Collections.Generic;\n")
+	buf.WriteString("using System.Text.Json;\n")
+	buf.WriteString("using System.Text.Json.Serialization;\n\n")
+	fmt.Fprintf(&buf, "namespace %s;\n", namespace(ctx))
+
+	for _, t := range b.types {
+		buf.WriteString("\n")
+		switch t := t.(type) {
+		case *record:
+			writeRecord(&buf, t)
+		case *enum:
+			writeEnum(&buf, t)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if ns := ctx.GetStringOption("namespace", ""); ns != "" && !validNamespace(ns) {
+		return fmt.Errorf("invalid namespace %q", ns)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// record builds a record and the nested types of its fields. Nested types
+// are named after the record and field, e.g. ArticleAuthor.
+func (b *builder) record(name, doc string, val cue.Value) (*record, error) {
+	rec := &record{Name: name, Doc: doc}
+	b.types = append(b.types, rec)
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+		optional := iter.IsOptional()
+
+		propName := toPascalCase(label)
+		if propName == name {
+			// Members cannot share the name of their enclosing type
+			propName += "Value"
+		}
+
+		typ, err := b.valueType(name+toPascalCase(label), fieldVal)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", label, err)
+		}
+		if nullable || optional {
+			typ += "?"
+		}
+
+		rec.Properties = append(rec.Properties, property{
+			Name:     propName,
+			JSONName: label,
+			Type:     typ,
+			Required: !optional,
+			Optional: optional,
+			Doc:      platoCue.Description(iter.Value()),
+		})
+	}
+
+	return rec, nil
+}
+
+// valueType maps a value to a C# type; typeName names nested types
+// created for it
+func (b *builder) valueType(typeName string, val cue.Value) (string, error) {
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if name, ok := b.defTypes[ref]; ok {
+			return name, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		name := b.uniqueName(typeName)
+		b.types = append(b.types, newEnum(name, "", members))
+		return name, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return "string", nil
+	case kind == cue.IntKind:
+		return "long", nil
+	case kind == cue.FloatKind, kind == cue.NumberKind:
+		return "double", nil
+	case kind == cue.BoolKind:
+		return "bool", nil
+	case kind == cue.BytesKind:
+		return "byte[]", nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return "List<JsonElement>", nil
+		}
+		elem, nullable := platoCue.StripNull(elem)
+		elemType, err := b.valueType(typeName+"Item", elem)
+		if err != nil {
+			return "", err
+		}
+		if nullable {
+			elemType += "?"
+		}
+		return "List<" + elemType + ">", nil
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			pattern, nullable := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			valueType, err := b.valueType(typeName+"Value", pattern)
+			if err != nil {
+				return "", err
+			}
+			if nullable {
+				valueType += "?"
+			}
+			return "Dictionary<string, " + valueType + ">", nil
+		}
+		if !platoCue.HasFields(val) {
+			return "JsonElement", nil
+		}
+		rec, err := b.record(b.uniqueName(typeName), "", val)
+		if err != nil {
+			return "", err
+		}
+		return rec.Name, nil
+	default:
+		return "JsonElement", nil
+	}
+}
+
+// uniqueName reserves a type name, adding a number if it is taken
+func (b *builder) uniqueName(name string) string {
+	return generator.UniqueName(b.names, name, "")
+}
+
+// newEnum builds an enum from its string values
+func newEnum(name, doc string, values []string) *enum {
+	e := &enum{Name: name, Doc: doc}
+	used := make(map[string]bool)
+	for _, v := range values {
+		member := toPascalCase(v)
+		if member == "" {
+			member = "Empty"
+		}
+		unique := member
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", member, i)
+		}
+		used[unique] = true
+		e.Members = append(e.Members, enumMember{Name: unique, Value: v})
+	}
+	return e
+}
+
+// writeRecord renders a record with one init-only property per field
+func writeRecord(buf *bytes.Buffer, rec *record) {
+	writeDoc(buf, rec.Doc, "")
+	fmt.Fprintf(buf, "public sealed record %s\n{\n", rec.Name)
+	for i, p := range rec.Properties {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeDoc(buf, p.Doc, "    ")
+		fmt.Fprintf(buf, "    [JsonPropertyName(%s)]\n", quote(p.JSONName))
+		if p.Optional {
+			buf.WriteString("    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]\n")
+		}
+		buf.WriteString("    public ")
+		if p.Required {
+			buf.WriteString("required ")
+		}
+		fmt.Fprintf(buf, "%s %s { get; init; }\n", p.Type, p.Name)
+	}
+	buf.WriteString("}\n")
+}
+
+// writeEnum renders an enum with a converter that reads and writes the
+// original strings
+func writeEnum(buf *bytes.Buffer, e *enum) {
+	writeDoc(buf, e.Doc, "")
+	fmt.Fprintf(buf, "[JsonConverter(typeof(%sJsonConverter))]\n", e.Name)
+	fmt.Fprintf(buf, "public enum %s\n{\n", e.Name)
+	for _, m := range e.Members {
+		fmt.Fprintf(buf, "    %s,\n", m.Name)
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(buf, "public sealed class %sJsonConverter : JsonConverter<%s>\n{\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "    public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>\n", e.Name)
+	buf.WriteString("        reader.GetString() switch\n        {\n")
+	for _, m := range e.Members {
+		fmt.Fprintf(buf, "            %s => %s.%s,\n", quote(m.Value), e.Name, m.Name)
+	}
+	fmt.Fprintf(buf, "            var value => throw new JsonException($\"Unknown %s value: {value}\"),\n", e.Name)
+	buf.WriteString("        };\n\n")
+	fmt.Fprintf(buf, "    public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options) =>\n", e.Name)
+	buf.WriteString("        writer.WriteStringValue(value switch\n        {\n")
+	for _, m := range e.Members {
+		fmt.Fprintf(buf, "            %s.%s => %s,\n", e.Name, m.Name, quote(m.Value))
+	}
+	fmt.Fprintf(buf, "            _ => throw new JsonException($\"Unknown %s value: {value}\"),\n", e.Name)
+	buf.WriteString("        });\n}\n")
+}
+
+// writeDoc renders a doc comment as an XML summary
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	fmt.Fprintf(buf, "%s/// <summary>\n", indent)
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(buf, "%s/// %s\n", indent, escapeXML(line))
+	}
+	fmt.Fprintf(buf, "%s/// </summary>\n", indent)
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// namespace returns the namespace from the "namespace" option or the
+// project name
+func namespace(ctx *generator.Context) string {
+	if ns := ctx.GetStringOption("namespace", ""); ns != "" {
+		return ns
+	}
+	if ctx.Config != nil && ctx.Config.Name != "" {
+		if name := toPascalCase(ctx.Config.Name); name != "" && !unicode.IsDigit([]rune(name)[0]) {
+			return name
+		}
+	}
+	return "PlatoSL"
+}
+
+// validNamespace reports whether a namespace is a dotted list of identifiers
+func validNamespace(ns string) bool {
+	for _, part := range strings.Split(ns, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			if !(r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// toPascalCase converts a definition name, label or enum value to a C#
+// identifier
+func toPascalCase(name string) string {
+	name = strings.TrimPrefix(name, "#")
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if s := b.String(); s != "" && unicode.IsDigit([]rune(s)[0]) {
+		return "_" + s
+	}
+	return b.String()
+}
+
+// quote renders a C# string literal
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("cs", "csharp")
+}
@@ -0,0 +1,613 @@
+package dart
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Dart classes with fromJson/toJson from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Dart generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "dart"
+}
+
+// dartType is a Dart type with what is needed to convert it from and to JSON
+type dartType struct {
+	Kind string // String, int, double, num, bool, Object, List, Map, class, enum
+	Name string // class or enum name
+	Elem *dartType
+	// ElemNullable marks list elements and map values that may be null
+	ElemNullable bool
+}
+
+// class is a Dart class built from a struct
+type class struct {
+	Name   string
+	Doc    string
+	Fields []field
+}
+
+// field is a final class field
+type field struct {
+	Name     string // camelCase Dart name
+	JSONName string // original label
+	Type     *dartType
+	Nullable bool
+	Optional bool // omitted from JSON when null
+	Doc      string
+}
+
+// enum is a Dart enum built from a disjunction of strings
+type enum struct {
+	Name   string
+	Doc    string
+	Values []enumValue
+}
+
+// enumValue is an enum value with the string it serializes to
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+// builder converts definitions into classes and enums
+type builder struct {
+	defTypes map[string]*dartType // CUE definition name -> Dart type
+	types    []interface{}        // *class and *enum, in output order
+	names    map[string]bool      // type names in use
+}
+
+// Generate generates Dart code
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	b := &builder{
+		defTypes: make(map[string]*dartType),
+		names:    make(map[string]bool),
+	}
+
+	// Only struct and string enum definitions become types; references to
+	// other definitions, unions of structs included, use the referenced
+	// type directly
+	var names []string
+	for name, val := range defs {
+		switch {
+		case len(stringEnum(val)) > 0:
+			b.defTypes[name] = &dartType{Kind: "enum", Name: toPascalCase(name)}
+		case val.IncompleteKind() == cue.StructKind && !platoCue.IsMap(val) && !platoCue.IsUnion(val):
+			b.defTypes[name] = &dartType{Kind: "class", Name: toPascalCase(name)}
+		default:
+			continue
+		}
+		b.names[b.defTypes[name].Name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		typ := b.defTypes[name]
+		doc := platoCue.Description(defs[name])
+		if typ.Kind == "enum" {
+			b.types = append(b.types, newEnum(typ.Name, doc, stringEnum(defs[name])))
+			continue
+		}
+		if err := b.class(typ.Name, doc, defs[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	serializable := ctx.GetBoolOption("jsonSerializable", false)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n")
+	if serializable {
+		output := ctx.GeneratorConfig.Output
+		if output == "" {
+			output = "types.dart"
+		}
+		part := strings.TrimSuffix(filepath.Base(output), ".dart") + ".g.dart"
+		buf.WriteString("\nimport 'package:json_annotation/json_annotation.dart';\n\n")
+		fmt.Fprintf(&buf, "part '%s';\n", part)
+	}
+
+	for _, t := range b.types {
+		buf.WriteString("\n")
+		switch t := t.(type) {
+		case *class:
+			writeClass(&buf, t, serializable)
+		case *enum:
+			writeEnum(&buf, t, serializable)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// class builds a class and the nested types of its fields. Nested types are
+// named after the class and field, e.g. ArticleAuthor.
+func (b *builder) class(name, doc string, val cue.Value) error {
+	c := &class{Name: name, Doc: doc}
+	b.types = append(b.types, c)
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+		optional := iter.IsOptional()
+
+		typ, err := b.valueType(name+toPascalCase(label), fieldVal)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", label, err)
+		}
+
+		c.Fields = append(c.Fields, field{
+			Name:     toFieldName(label),
+			JSONName: label,
+			Type:     typ,
+			Nullable: nullable || optional || typ.Kind == "Object",
+			Optional: optional,
+			Doc:      platoCue.Description(iter.Value()),
+		})
+	}
+
+	return nil
+}
+
+// valueType maps a value to a Dart type; typeName names nested types
+// created for it
+func (b *builder) valueType(typeName string, val cue.Value) (*dartType, error) {
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if typ, ok := b.defTypes[ref]; ok {
+			return typ, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		name := b.uniqueName(typeName)
+		b.types = append(b.types, newEnum(name, "", members))
+		return &dartType{Kind: "enum", Name: name}, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return &dartType{Kind: "String"}, nil
+	case kind == cue.IntKind:
+		return &dartType{Kind: "int"}, nil
+	case kind == cue.FloatKind:
+		return &dartType{Kind: "double"}, nil
+	case kind == cue.NumberKind:
+		return &dartType{Kind: "num"}, nil
+	case kind == cue.BoolKind:
+		return &dartType{Kind: "bool"}, nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return &dartType{Kind: "List", Elem: &dartType{Kind: "Object"}, ElemNullable: true}, nil
+		}
+		elem, nullable := platoCue.StripNull(elem)
+		elemType, err := b.valueType(typeName+"Item", elem)
+		if err != nil {
+			return nil, err
+		}
+		return &dartType{Kind: "List", Elem: elemType, ElemNullable: nullable || elemType.Kind == "Object"}, nil
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			pattern, nullable := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			valueType, err := b.valueType(typeName+"Value", pattern)
+			if err != nil {
+				return nil, err
+			}
+			return &dartType{Kind: "Map", Elem: valueType, ElemNullable: nullable || valueType.Kind == "Object"}, nil
+		}
+		if !platoCue.HasFields(val) {
+			return &dartType{Kind: "Map", Elem: &dartType{Kind: "Object"}, ElemNullable: true}, nil
+		}
+		name := b.uniqueName(typeName)
+		if err := b.class(name, "", val); err != nil {
+			return nil, err
+		}
+		return &dartType{Kind: "class", Name: name}, nil
+	default:
+		return &dartType{Kind: "Object"}, nil
+	}
+}
+
+// uniqueName reserves a type name, adding a number if it is taken
+func (b *builder) uniqueName(name string) string {
+	return generator.UniqueName(b.names, name, "")
+}
+
+// newEnum builds an enum from its string values
+func newEnum(name, doc string, values []string) *enum {
+	e := &enum{Name: name, Doc: doc}
+	used := map[string]bool{"values": true, "index": true, "name": true, "value": true}
+	for _, v := range values {
+		member := toFieldName(v)
+		unique := member
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", member, i)
+		}
+		used[unique] = true
+		e.Values = append(e.Values, enumValue{Name: unique, Value: v})
+	}
+	return e
+}
+
+// String renders the type as Dart source
+func (t *dartType) String() string {
+	switch t.Kind {
+	case "class", "enum":
+		return t.Name
+	case "Object":
+		return "Object"
+	case "List":
+		return "List<" + t.elem() + ">"
+	case "Map":
+		return "Map<String, " + t.elem() + ">"
+	default:
+		return t.Kind
+	}
+}
+
+func (t *dartType) elem() string {
+	if t.ElemNullable {
+		return t.Elem.String() + "?"
+	}
+	return t.Elem.String()
+}
+
+// fromJSON returns the expression converting a decoded JSON value to t
+func fromJSON(t *dartType, expr string, nullable bool) string {
+	q := ""
+	if nullable {
+		q = "?"
+	}
+	switch t.Kind {
+	case "String", "bool", "num":
+		return fmt.Sprintf("%s as %s%s", expr, t.Kind, q)
+	case "int":
+		return fmt.Sprintf("(%s as num%s)%s.toInt()", expr, q, q)
+	case "double":
+		return fmt.Sprintf("(%s as num%s)%s.toDouble()", expr, q, q)
+	case "Object":
+		return expr
+	case "List":
+		return fmt.Sprintf("(%s as List<dynamic>%s)%s.map((e) => %s).toList()", expr, q, q, fromJSON(t.Elem, "e", t.ElemNullable))
+	case "Map":
+		return fmt.Sprintf("(%s as Map<String, dynamic>%s)%s.map((k, e) => MapEntry(k, %s))", expr, q, q, fromJSON(t.Elem, "e", t.ElemNullable))
+	case "class":
+		conv := fmt.Sprintf("%s.fromJson(%s as Map<String, dynamic>)", t.Name, expr)
+		if nullable {
+			return fmt.Sprintf("%s == null ? null : %s", expr, conv)
+		}
+		return conv
+	case "enum":
+		conv := fmt.Sprintf("%s.fromJson(%s as String)", t.Name, expr)
+		if nullable {
+			return fmt.Sprintf("%s == null ? null : %s", expr, conv)
+		}
+		return conv
+	}
+	return expr
+}
+
+// toJSON returns the expression converting a value of t to JSON
+func toJSON(t *dartType, expr string, nullable bool) string {
+	q := ""
+	if nullable {
+		q = "?"
+	}
+	switch t.Kind {
+	case "class", "enum":
+		return fmt.Sprintf("%s%s.toJson()", expr, q)
+	case "List":
+		if !needsConversion(t.Elem) {
+			return expr
+		}
+		return fmt.Sprintf("%s%s.map((e) => %s).toList()", expr, q, toJSON(t.Elem, "e", t.ElemNullable))
+	case "Map":
+		if !needsConversion(t.Elem) {
+			return expr
+		}
+		return fmt.Sprintf("%s%s.map((k, e) => MapEntry(k, %s))", expr, q, toJSON(t.Elem, "e", t.ElemNullable))
+	}
+	return expr
+}
+
+// needsConversion reports whether values of t need converting for JSON
+func needsConversion(t *dartType) bool {
+	switch t.Kind {
+	case "class", "enum":
+		return true
+	case "List", "Map":
+		return needsConversion(t.Elem)
+	}
+	return false
+}
+
+// writeClass renders a class with a const constructor and JSON methods
+func writeClass(buf *bytes.Buffer, c *class, serializable bool) {
+	writeDoc(buf, c.Doc, "")
+	if serializable {
+		buf.WriteString("@JsonSerializable(explicitToJson: true)\n")
+	}
+	fmt.Fprintf(buf, "class %s {\n", c.Name)
+
+	for _, f := range c.Fields {
+		writeDoc(buf, f.Doc, "  ")
+		if serializable {
+			var args []string
+			if f.Name != f.JSONName {
+				args = append(args, fmt.Sprintf("name: %s", quote(f.JSONName)))
+			}
+			if f.Optional {
+				args = append(args, "includeIfNull: false")
+			}
+			if len(args) > 0 {
+				fmt.Fprintf(buf, "  @JsonKey(%s)\n", strings.Join(args, ", "))
+			}
+		}
+		fmt.Fprintf(buf, "  final %s %s;\n", fieldType(f), f.Name)
+	}
+	if len(c.Fields) > 0 {
+		buf.WriteString("\n")
+	}
+
+	// Constructor
+	if len(c.Fields) == 0 {
+		fmt.Fprintf(buf, "  const %s();\n\n", c.Name)
+	} else {
+		fmt.Fprintf(buf, "  const %s({\n", c.Name)
+		for _, f := range c.Fields {
+			if f.Optional {
+				fmt.Fprintf(buf, "    this.%s,\n", f.Name)
+			} else {
+				fmt.Fprintf(buf, "    required this.%s,\n", f.Name)
+			}
+		}
+		buf.WriteString("  });\n\n")
+	}
+
+	if serializable {
+		fmt.Fprintf(buf, "  factory %s.fromJson(Map<String, dynamic> json) => _$%sFromJson(json);\n\n", c.Name, c.Name)
+		fmt.Fprintf(buf, "  Map<String, dynamic> toJson() => _$%sToJson(this);\n", c.Name)
+		buf.WriteString("}\n")
+		return
+	}
+
+	// fromJson
+	if len(c.Fields) == 0 {
+		fmt.Fprintf(buf, "  factory %s.fromJson(Map<String, dynamic> json) => const %s();\n\n", c.Name, c.Name)
+	} else {
+		fmt.Fprintf(buf, "  factory %s.fromJson(Map<String, dynamic> json) => %s(\n", c.Name, c.Name)
+		for _, f := range c.Fields {
+			fmt.Fprintf(buf, "        %s: %s,\n", f.Name, fromJSON(f.Type, fmt.Sprintf("json[%s]", quote(f.JSONName)), f.Nullable))
+		}
+		buf.WriteString("      );\n\n")
+	}
+
+	// toJson; optional fields are left out when null
+	buf.WriteString("  Map<String, dynamic> toJson() => <String, dynamic>{\n")
+	for _, f := range c.Fields {
+		value := toJSON(f.Type, f.Name, f.Nullable)
+		if f.Optional {
+			fmt.Fprintf(buf, "        if (%s != null) %s: %s,\n", f.Name, quote(f.JSONName), strings.Replace(value, f.Name+"?.", f.Name+"!.", 1))
+		} else {
+			fmt.Fprintf(buf, "        %s: %s,\n", quote(f.JSONName), value)
+		}
+	}
+	buf.WriteString("      };\n")
+	buf.WriteString("}\n")
+}
+
+// fieldType renders the declared type of a field
+func fieldType(f field) string {
+	if f.Nullable {
+		return f.Type.String() + "?"
+	}
+	return f.Type.String()
+}
+
+// writeEnum renders an enhanced enum that keeps its original strings
+func writeEnum(buf *bytes.Buffer, e *enum, serializable bool) {
+	writeDoc(buf, e.Doc, "")
+	if serializable {
+		buf.WriteString("@JsonEnum(valueField: 'value')\n")
+	}
+	fmt.Fprintf(buf, "enum %s {\n", e.Name)
+	for i, v := range e.Values {
+		sep := ","
+		if i == len(e.Values)-1 {
+			sep = ";"
+		}
+		fmt.Fprintf(buf, "  %s(%s)%s\n", v.Name, quote(v.Value), sep)
+	}
+	buf.WriteString("\n")
+	fmt.Fprintf(buf, "  const %s(this.value);\n\n", e.Name)
+	if serializable {
+		buf.WriteString("  final String value;\n")
+		buf.WriteString("}\n")
+		return
+	}
+	buf.WriteString("  final String value;\n\n")
+	fmt.Fprintf(buf, "  static %s fromJson(String value) =>\n", e.Name)
+	fmt.Fprintf(buf, "      values.firstWhere((e) => e.value == value, orElse: () => throw ArgumentError.value(value, %s));\n\n", quote(e.Name))
+	buf.WriteString("  String toJson() => value;\n")
+	buf.WriteString("}\n")
+}
+
+// writeDoc renders a /// doc comment
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(buf, "%s/// %s\n", indent, line)
+	}
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// keywords are reserved words and built-in identifiers that cannot be
+// used as field names
+var keywords = map[string]bool{
+	"abstract": true, "as": true, "assert": true, "async": true, "await": true,
+	"base": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "covariant": true, "default": true, "deferred": true,
+	"do": true, "dynamic": true, "else": true, "enum": true, "export": true,
+	"extends": true, "extension": true, "external": true, "factory": true, "false": true,
+	"final": true, "finally": true, "for": true, "function": true, "get": true,
+	"hide": true, "if": true, "implements": true, "import": true, "in": true,
+	"interface": true, "is": true, "late": true, "library": true, "mixin": true,
+	"new": true, "null": true, "of": true, "on": true, "operator": true,
+	"part": true, "required": true, "rethrow": true, "return": true, "sealed": true,
+	"set": true, "show": true, "static": true, "super": true, "switch": true,
+	"sync": true, "this": true, "throw": true, "true": true, "try": true,
+	"type": true, "typedef": true, "var": true, "void": true, "when": true,
+	"while": true, "with": true, "yield": true, "json": true,
+}
+
+// words splits a label or value into words at separators and case changes
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			result = append(result, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// toPascalCase converts a definition name or label to a type name
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(strings.TrimPrefix(name, "#")) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+// toFieldName converts a label or enum value to a lowerCamelCase identifier
+func toFieldName(label string) string {
+	ws := words(label)
+	if len(ws) == 0 {
+		return "empty"
+	}
+	var b strings.Builder
+	for i, w := range ws {
+		r := []rune(w)
+		if i == 0 {
+			b.WriteString(strings.ToLower(w))
+			continue
+		}
+		b.WriteString(string(unicode.ToUpper(r[0])) + strings.ToLower(string(r[1:])))
+	}
+	name := b.String()
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "v" + name
+	}
+	if keywords[name] {
+		name += "Value"
+	}
+	return name
+}
+
+// quote renders a single-quoted Dart string literal
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,99 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// generateDefaults renders a definition with defaulted scalar fields
+func generateDefaults(t *testing.T, name string) string {
+	t.Helper()
+	return generateSource(t, name, `
+#User: {
+	name:   string
+	score:  float | *1.5
+	active: bool | *false
+	count:  *1 | int
+}
+`)
+}
+
+// generateSource renders CUE source with a registered generator
+func generateSource(t *testing.T, name, src string) string {
+	t.Helper()
+	val := cuecontext.New().CompileString(src)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+	gen, err := generator.Get(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := gen.Generate(generator.NewContext(val, &config.Config{}, config.GenConfig{}))
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return string(out)
+}
+
+// TestScalarDefaults checks that generators keep the defaults of scalar
+// fields, not only those of string enums
+func TestScalarDefaults(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      []string
+	}{
+		{"valibot", []string{"score: v.optional(v.number(), 1.5),", "active: v.optional(v.boolean(), false),", "count: v.optional(v.pipe(v.number(), v.integer()), 1),"}},
+		{"effect", []string{"active: Schema.optionalWith(Schema.Boolean, { default: () => false }),"}},
+		{"typebox", []string{"active: Type.Optional(Type.Boolean({ default: false })),"}},
+		{"elixir-ecto", []string{"field :active, :boolean, default: false"}},
+		{"elixir-absinthe", []string{"field :active, :boolean, default_value: false"}},
+		{"gleam", []string{`use active <- decode.optional_field("active", False, decode.bool)`}},
+		{"terraform", []string{"active = optional(bool, false)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			out := generateDefaults(t, tt.generator)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output lacks %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+// TestDefaultedFieldsAreOptional checks that validators accept payloads
+// without defaulted fields, as CUE does, and fill in the default
+func TestDefaultedFieldsAreOptional(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      []string
+		required  string
+	}{
+		{"joi", []string{"score: Joi.number().default(1.5),", "active: Joi.boolean().default(false),", "name: Joi.string().allow(\"\").required(),"}, ".required()"},
+		{"yup", []string{"score: yup.number().default(1.5),", "active: yup.boolean().default(false),"}, ".required()"},
+		{"mongoose", []string{"score: { type: Number, default: 1.5 },", "active: { type: Boolean, default: false },"}, "required: true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			out := generateDefaults(t, tt.generator)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output lacks %q:\n%s", want, out)
+				}
+			}
+			for _, line := range strings.Split(out, "\n") {
+				for _, field := range []string{"score:", "active:", "count:"} {
+					if strings.HasPrefix(strings.TrimSpace(line), field) && strings.Contains(line, tt.required) {
+						t.Errorf("defaulted field is required: %s", line)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,669 @@
+package ecto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Ecto embedded schemas with changesets from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Ecto generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "elixir-ecto"
+}
+
+var (
+	// moduleName matches Elixir module names such as MyApp.Schemas
+	moduleName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*(\.[A-Z][A-Za-z0-9_]*)*$`)
+
+	// atomName matches atoms that need no quotes
+	atomName = regexp.MustCompile(`^[a-z_][A-Za-z0-9_]*[?!]?$`)
+)
+
+// schema is a module with an embedded schema: a struct definition, or a
+// nested struct of one, which gets a module of its own
+type schema struct {
+	module string
+	doc    string
+	fields []field
+}
+
+// field is a field or embed of a schema
+type field struct {
+	name     string
+	doc      string
+	macro    string   // field, embeds_one or embeds_many
+	typ      string   // Ecto type, or the module of an embed
+	opts     []string // options of the macro, e.g. default: 0
+	spec     string   // typespec
+	nullable bool
+	required bool
+	checks   []string // changeset validations, e.g. validate_length(:name, min: 1)
+}
+
+// renderer collects the schemas of struct definitions. Definitions of
+// other types, such as enums or constrained strings, are inlined where
+// they are used.
+type renderer struct {
+	modules map[string]string // modules by CUE definition name
+	used    map[string]bool
+	schemas []*schema
+}
+
+// Generate generates a module with an embedded_schema and a changeset
+// function per struct definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	prefix := ctx.GetStringOption("module", "MyApp.Schemas")
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name, val := range defs {
+		if isSchema(val) {
+			cueNames = append(cueNames, name)
+		}
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{modules: make(map[string]string), used: make(map[string]bool)}
+	for _, name := range cueNames {
+		r.modules[name] = generator.UniqueName(r.used, prefix+"."+toPascalCase(name), "")
+	}
+	for _, name := range cueNames {
+		doc := platoCue.Description(defs[name])
+		if doc == "" {
+			doc = "Embedded schema of the CUE definition " + name + "."
+		}
+		r.schema(r.modules[name], doc, defs[name])
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n")
+	for _, s := range r.schemas {
+		buf.WriteString("\n")
+		writeSchema(&buf, s)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if module := ctx.GetStringOption("module", "MyApp.Schemas"); !moduleName.MatchString(module) {
+		return fmt.Errorf("invalid module prefix %q (expected an Elixir module name such as MyApp.Schemas)", module)
+	}
+	return nil
+}
+
+// isSchema reports whether a definition is a struct with fields, which
+// becomes a schema
+func isSchema(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// schema adds the schema of a struct, followed by the schemas of its
+// nested structs. Regular fields are required unless they may be null or
+// have a default.
+func (r *renderer) schema(module, doc string, val cue.Value) {
+	s := &schema{module: module, doc: doc}
+	r.schemas = append(r.schemas, s)
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		f := r.field(module, label, fieldVal)
+		f.doc = platoCue.Description(fieldVal)
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok && f.macro == "field" {
+			f.opts = append(f.opts, "default: "+elixirValue(def, f.typ))
+		} else if !iter.IsOptional() && !f.nullable {
+			f.required = true
+		}
+		s.fields = append(s.fields, f)
+	}
+}
+
+// field maps a value to a field of the schema of module
+func (r *renderer) field(module, label string, val cue.Value) field {
+	f := field{name: label, macro: "field"}
+	if embed, ok := r.reference(val); ok {
+		f.macro, f.typ, f.spec = "embeds_one", embed, embed+".t()"
+		return f
+	}
+	if values, args := enumLiterals(val); len(values) > 1 {
+		enum(&f, values, args)
+		return f
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		f.nullable = nullable
+		untyped(&f, alts)
+		return f
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if embed, ok := r.reference(val); ok {
+		f.macro, f.typ, f.spec, f.nullable = "embeds_one", embed, embed+".t()", nullable
+		return f
+	}
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		// Definitions other than structs are inlined
+		f = r.field(module, label, cue.Dereference(val))
+		f.nullable = f.nullable || nullable
+		return f
+	}
+	r.base(&f, module, val)
+	f.nullable = f.nullable || nullable
+	return f
+}
+
+// reference returns the module of a reference to a struct definition
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	module, ok := r.modules[path.String()]
+	return module, ok
+}
+
+// base maps a value by its kind, with its constraints as validations
+func (r *renderer) base(f *field, module string, val cue.Value) {
+	if val.IsConcrete() && val.Kind() != cue.NullKind && val.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) != 0 {
+		if lit, ok := literal(val); ok {
+			enum(f, []string{lit}, []cue.Value{val})
+			return
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+	atom := atomOf(f.name)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		f.typ, f.spec = ":string", "String.t()"
+		var opts []option
+		if c.MinLength != nil {
+			opts = append(opts, option{"min", strconv.Itoa(*c.MinLength), msgs.For("minLength")})
+		}
+		if c.MaxLength != nil {
+			opts = append(opts, option{"max", strconv.Itoa(*c.MaxLength), msgs.For("maxLength")})
+		}
+		f.checks = append(f.checks, validation("validate_length", atom, "", opts)...)
+		for _, pattern := range c.Patterns {
+			f.checks = append(f.checks, validation("validate_format", atom, regex(pattern),
+				[]option{{msg: msgs.For("pattern")}})...)
+		}
+	case kind == cue.IntKind:
+		f.typ, f.spec = ":integer", "integer()"
+		f.checks = append(f.checks, validation("validate_number", atom, "", bounds(c, msgs))...)
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		f.typ, f.spec = ":float", "float()"
+		if kind == cue.NumberKind {
+			f.spec = "number()"
+		}
+		f.checks = append(f.checks, validation("validate_number", atom, "", bounds(c, msgs))...)
+	case kind == cue.BoolKind:
+		f.typ, f.spec = ":boolean", "boolean()"
+	case kind == cue.ListKind:
+		elem := field{name: f.name, macro: "field", typ: ":any", spec: "any()"}
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.field(module, f.name, e)
+		}
+		switch {
+		case elem.macro == "embeds_one":
+			f.macro, f.typ, f.spec = "embeds_many", elem.typ, "["+elem.spec+"]"
+		case elem.typ == ":any":
+			untyped(f, nil)
+		default:
+			f.typ, f.spec = "{:array, "+elem.typ+"}", "["+elem.spec+"]"
+			for _, opt := range elem.opts {
+				if strings.HasPrefix(opt, "values: ") {
+					f.opts = append(f.opts, opt)
+				}
+			}
+		}
+		var opts []option
+		if c.MinLength != nil {
+			opts = append(opts, option{"min", strconv.Itoa(*c.MinLength), msgs.For("minItems")})
+		}
+		if c.MaxLength != nil {
+			opts = append(opts, option{"max", strconv.Itoa(*c.MaxLength), msgs.For("maxItems")})
+		}
+		f.checks = append(f.checks, validation("validate_length", atom, "", opts)...)
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			f.typ, f.spec = ":map", "map()"
+			elem := r.field(module, f.name, val.LookupPath(cue.MakePath(cue.AnyString)))
+			if elem.macro == "field" && elem.typ != ":any" && elem.typ != "Ecto.Enum" && !strings.HasPrefix(elem.typ, "{") {
+				f.typ, f.spec = "{:map, "+elem.typ+"}", "%{optional(String.t()) => "+elem.spec+"}"
+			}
+		case !platoCue.HasFields(val):
+			f.typ, f.spec = ":map", "map()"
+		default:
+			nested := generator.UniqueName(r.used, module+"."+toPascalCase(f.name), "")
+			f.macro, f.typ, f.spec = "embeds_one", nested, nested+".t()"
+			r.schema(nested, platoCue.Description(val), val)
+		}
+	default:
+		untyped(f, nil)
+	}
+}
+
+// enum maps literals to an Ecto.Enum when they are strings, otherwise to
+// their type with an inclusion validation
+func enum(f *field, values []string, args []cue.Value) {
+	strs := true
+	for _, arg := range args {
+		if arg.Kind() != cue.StringKind {
+			strs = false
+		}
+	}
+	if strs {
+		atoms := make([]string, len(args))
+		for i, arg := range args {
+			s, _ := arg.String()
+			atoms[i] = atomOf(s)
+		}
+		f.typ, f.spec = "Ecto.Enum", strings.Join(atoms, " | ")
+		f.opts = append(f.opts, "values: ["+strings.Join(atoms, ", ")+"]")
+		return
+	}
+
+	f.typ = ":any"
+	kinds := make(map[cue.Kind]bool)
+	for _, arg := range args {
+		kinds[arg.Kind()] = true
+	}
+	if len(kinds) == 1 {
+		switch {
+		case kinds[cue.IntKind]:
+			f.typ = ":integer"
+		case kinds[cue.FloatKind]:
+			f.typ = ":float"
+		case kinds[cue.BoolKind]:
+			f.typ = ":boolean"
+		}
+	}
+	if f.typ == ":any" {
+		f.opts = append(f.opts, "virtual: true")
+	}
+	f.spec = strings.Join(values, " | ")
+	f.checks = append(f.checks, fmt.Sprintf("validate_inclusion(%s, [%s])", atomOf(f.name), strings.Join(values, ", ")))
+}
+
+// untyped maps a value Ecto has no type for, such as a disjunction of
+// different kinds. Structs become maps; anything else is a virtual field,
+// which is cast but not stored with the embedding schema.
+func untyped(f *field, alts []cue.Value) {
+	structs := len(alts) > 0
+	for _, alt := range alts {
+		if alt.IncompleteKind() != cue.StructKind {
+			structs = false
+		}
+	}
+	if structs {
+		f.typ, f.spec = ":map", "map()"
+		return
+	}
+	f.typ, f.spec = ":any", "any()"
+	f.opts = append(f.opts, "virtual: true")
+}
+
+// option is an option of a validation, with its @errmsg message
+type option struct {
+	key, value, msg string
+}
+
+// validation renders the calls of a validation of a field, one per
+// distinct @errmsg message, e.g. validate_length(:name, min: 1, max: 20).
+// Arg is a positional argument after the field, such as a regex.
+func validation(fn, atom, arg string, opts []option) []string {
+	var msgs []string
+	groups := make(map[string][]string)
+	for _, opt := range opts {
+		if _, ok := groups[opt.msg]; !ok {
+			msgs = append(msgs, opt.msg)
+			groups[opt.msg] = nil
+		}
+		if opt.key != "" {
+			groups[opt.msg] = append(groups[opt.msg], opt.key+": "+opt.value)
+		}
+	}
+
+	var calls []string
+	for _, msg := range msgs {
+		args := []string{atom}
+		if arg != "" {
+			args = append(args, arg)
+		}
+		args = append(args, groups[msg]...)
+		if msg != "" {
+			args = append(args, "message: "+elixirString(msg))
+		}
+		calls = append(calls, fn+"("+strings.Join(args, ", ")+")")
+	}
+	return calls
+}
+
+// bounds returns the validate_number options of the bounds of a number
+func bounds(c platoCue.Constraints, msgs platoCue.ErrorMessages) []option {
+	var opts []option
+	if c.Minimum != nil {
+		opts = append(opts, option{"greater_than_or_equal_to", number(*c.Minimum), msgs.For("minimum")})
+	}
+	if c.ExclusiveMinimum != nil {
+		opts = append(opts, option{"greater_than", number(*c.ExclusiveMinimum), msgs.For("exclusiveMinimum")})
+	}
+	if c.Maximum != nil {
+		opts = append(opts, option{"less_than_or_equal_to", number(*c.Maximum), msgs.For("maximum")})
+	}
+	if c.ExclusiveMaximum != nil {
+		opts = append(opts, option{"less_than", number(*c.ExclusiveMaximum), msgs.For("exclusiveMaximum")})
+	}
+	return opts
+}
+
+// writeSchema writes the module of a schema: its typespec, embedded_schema
+// and changeset function
+func writeSchema(buf *bytes.Buffer, s *schema) {
+	fmt.Fprintf(buf, "defmodule %s do\n", s.module)
+	if s.doc == "" {
+		buf.WriteString("  @moduledoc false\n")
+	} else {
+		buf.WriteString("  @moduledoc \"\"\"\n")
+		for _, line := range strings.Split(heredoc(s.doc), "\n") {
+			buf.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+		}
+		buf.WriteString("  \"\"\"\n")
+	}
+	buf.WriteString("\n  use Ecto.Schema\n  import Ecto.Changeset\n\n")
+
+	// Typespec
+	var specs []string
+	for _, f := range s.fields {
+		spec := f.spec
+		if !f.required && f.macro != "embeds_many" {
+			spec += " | nil"
+		}
+		specs = append(specs, "          "+keyOf(f.name)+" "+spec)
+	}
+	if len(specs) == 0 {
+		buf.WriteString("  @type t :: %__MODULE__{}\n\n")
+	} else {
+		fmt.Fprintf(buf, "  @type t :: %%__MODULE__{\n%s\n        }\n\n", strings.Join(specs, ",\n"))
+	}
+
+	// Schema
+	buf.WriteString("  @primary_key false\n  embedded_schema do\n")
+	for _, f := range s.fields {
+		if f.doc != "" {
+			for _, line := range strings.Split(f.doc, "\n") {
+				buf.WriteString(strings.TrimRight("    # "+line, " ") + "\n")
+			}
+		}
+		args := append([]string{atomOf(f.name), f.typ}, f.opts...)
+		fmt.Fprintf(buf, "    %s %s\n", f.macro, strings.Join(args, ", "))
+	}
+	buf.WriteString("  end\n\n")
+
+	// Changeset
+	var cast, required, pipeline []string
+	for _, f := range s.fields {
+		if f.macro == "field" {
+			cast = append(cast, atomOf(f.name))
+			if f.required {
+				required = append(required, atomOf(f.name))
+			}
+			continue
+		}
+		if f.required {
+			pipeline = append(pipeline, fmt.Sprintf("cast_embed(%s, required: true)", atomOf(f.name)))
+		} else {
+			pipeline = append(pipeline, fmt.Sprintf("cast_embed(%s)", atomOf(f.name)))
+		}
+	}
+	if len(required) > 0 {
+		pipeline = append(pipeline, "validate_required(["+strings.Join(required, ", ")+"])")
+	}
+	for _, f := range s.fields {
+		pipeline = append(pipeline, f.checks...)
+	}
+
+	buf.WriteString("  @doc \"\"\"\n  Casts and validates params into a changeset.\n  \"\"\"\n")
+	buf.WriteString("  @spec changeset(t(), map()) :: Ecto.Changeset.t()\n")
+	buf.WriteString("  def changeset(schema \\\\ %__MODULE__{}, params) do\n")
+	buf.WriteString("    schema\n")
+	fmt.Fprintf(buf, "    |> cast(params, [%s])\n", strings.Join(cast, ", "))
+	for _, call := range pipeline {
+		fmt.Fprintf(buf, "    |> %s\n", call)
+	}
+	buf.WriteString("  end\nend\n")
+}
+
+// atomOf renders an atom, quoted when it is not a plain name
+func atomOf(name string) string {
+	if atomName.MatchString(name) {
+		return ":" + name
+	}
+	return ":" + elixirString(name)
+}
+
+// keyOf renders a keyword key, e.g. name: or "first-name":
+func keyOf(name string) string {
+	if atomName.MatchString(name) {
+		return name + ":"
+	}
+	return elixirString(name) + ":"
+}
+
+// regex renders a pattern as a ~r sigil
+func regex(pattern string) string {
+	return "~r/" + strings.ReplaceAll(jsgen.EscapeRegex(pattern), "#{", `\#{`) + "/"
+}
+
+// heredoc escapes text for a """ heredoc
+func heredoc(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"""`, `\"\"\"`)
+	return strings.ReplaceAll(s, "#{", `\#{`)
+}
+
+// elixirString renders a double-quoted string literal
+func elixirString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\n"), "#{", `\#{`)
+}
+
+// elixirValue renders a JSON default as an Elixir term. Strings of an
+// Ecto.Enum become atoms.
+func elixirValue(data, typ string) string {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "nil"
+	}
+	atoms := typ == "Ecto.Enum" || typ == "{:array, Ecto.Enum}"
+
+	var render func(v interface{}) string
+	render = func(v interface{}) string {
+		switch v := v.(type) {
+		case nil:
+			return "nil"
+		case bool:
+			return strconv.FormatBool(v)
+		case json.Number:
+			return v.String()
+		case string:
+			if atoms {
+				return atomOf(v)
+			}
+			return elixirString(v)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = render(item)
+			}
+			return "[" + strings.Join(items, ", ") + "]"
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, len(keys))
+			for i, key := range keys {
+				pairs[i] = elixirString(key) + " => " + render(v[key])
+			}
+			return "%{" + strings.Join(pairs, ", ") + "}"
+		}
+		return "nil"
+	}
+	return render(v)
+}
+
+// enumLiterals returns the literals of a disjunction of literals and their
+// values, following references to enum definitions, e.g. "admin" and
+// "member" for #Role | *"member"
+func enumLiterals(val cue.Value) ([]string, []cue.Value) {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil, nil
+	}
+
+	var lits []string
+	var vals []cue.Value
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if _, path := arg.ReferencePath(); len(path.Selectors()) > 0 {
+			arg = cue.Dereference(arg)
+		}
+		argLits, argVals := []string(nil), []cue.Value{arg}
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if argLits, argVals = enumLiterals(arg); argLits == nil {
+				return nil, nil
+			}
+		} else {
+			lit, ok := literal(arg)
+			if !ok || !arg.IsConcrete() || arg.Kind() == cue.NullKind {
+				return nil, nil
+			}
+			argLits = []string{lit}
+		}
+		for i, lit := range argLits {
+			if !seen[lit] {
+				seen[lit] = true
+				lits, vals = append(lits, lit), append(vals, argVals[i])
+			}
+		}
+	}
+	return lits, vals
+}
+
+// literal renders a concrete scalar as Elixir
+func literal(val cue.Value) (string, bool) {
+	switch val.Kind() {
+	case cue.StringKind:
+		s, err := val.String()
+		return elixirString(s), err == nil
+	case cue.IntKind, cue.FloatKind, cue.BoolKind:
+		data, err := val.MarshalJSON()
+		return string(data), err == nil
+	case cue.NullKind:
+		return "nil", true
+	}
+	return "", false
+}
+
+// number renders a bound, without a fraction when it is integral
+func number(f float64) string {
+	if f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// toPascalCase converts a definition or field name to a module name, e.g.
+// #order_item to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("ecto", "elixir-ecto")
+}
@@ -0,0 +1,424 @@
+package effect
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Effect schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Effect Schema generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "effect"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of definitions. References to definitions
+// declared further down, which only happens in cycles, are wrapped in
+// Schema.suspend.
+type renderer struct {
+	names    map[string]string // TypeScript names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+	brand    string            // brand of the scalar definition being rendered
+}
+
+// Generate generates a module with a schema and a type per definition.
+// Definitions of a constrained scalar, e.g. #UserId: string & =~"^u_",
+// become branded types unless the brands option is false.
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	pkg := ctx.GetStringOption("package", "effect")
+	brands := ctx.GetBoolOption("brands", true)
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	graph := platoCue.NewRefGraph(defs)
+	order := graph.Order(cueNames)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "import { Schema } from '%s';\n", pkg)
+
+	for _, name := range order {
+		val := defs[name]
+		tsName := r.names[name]
+
+		buf.WriteString("\n")
+		if brands && isScalar(val) {
+			r.brand = tsName
+		}
+		schema := r.schema(val, 0)
+		r.brand = ""
+		if graph.Recursive(name) {
+			// A schema referring to itself cannot be inferred by TypeScript,
+			// so its type and encoded type are written out and the schema
+			// annotated with them
+			typ, err := (&jsgen.Types{Ref: r.typeReference, Key: propertyName, Readonly: true}).Definition(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate type for %s: %w", name, err)
+			}
+			encoded, err := (&jsgen.Types{Ref: r.encodedReference(graph), Key: propertyName, Input: true, Readonly: true}).Definition(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate encoded type for %s: %w", name, err)
+			}
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export type %s = %s;\n", tsName, typ)
+			fmt.Fprintf(&buf, "export type %sEncoded = %s;\n", tsName, encoded)
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %s: Schema.Schema<%s, %sEncoded> = %s;\n", tsName, tsName, tsName, schema)
+		} else {
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %s = %s;\n", tsName, schema)
+			fmt.Fprintf(&buf, "export type %s = typeof %s.Type;\n", tsName, tsName)
+		}
+		r.declared[name] = true
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// isScalar reports whether a definition is a non-literal string or number,
+// which gets a brand
+func isScalar(val cue.Value) bool {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return false
+	}
+	if op, _ := val.Expr(); op == cue.OrOp || val.IsConcrete() {
+		return false
+	}
+	_, kind := platoCue.TypedKind(val)
+	return kind == cue.StringKind || kind == cue.IntKind || kind == cue.FloatKind || kind == cue.NumberKind
+}
+
+// schema renders the schema of a value at an indentation level
+func (r *renderer) schema(val cue.Value, indent int) string {
+	if name, ok := r.reference(val); ok {
+		return name
+	}
+	if values := jsgen.LiteralEnum(val); len(values) > 1 {
+		return "Schema.Literal(" + strings.Join(values, ", ") + ")"
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]string, len(alts))
+		for i, alt := range alts {
+			items[i] = r.schema(alt, indent)
+		}
+		return nullableIf(nullable, "Schema.Union("+strings.Join(items, ", ")+")")
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if name, ok := r.reference(val); ok {
+		return nullableIf(nullable, name)
+	}
+	return nullableIf(nullable, r.base(val, indent))
+}
+
+// reference renders a reference to a definition, suspended when the
+// definition is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	if !r.declared[path.String()] {
+		return "Schema.suspend((): Schema.Schema<" + name + ", " + name + "Encoded> => " + name + ")", true
+	}
+	return name, true
+}
+
+// typeReference renders a reference to a definition as its type name
+func (r *renderer) typeReference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	return name, ok
+}
+
+// encodedReference returns a renderer of references to definitions as
+// their encoded type: the one written out for recursive definitions and
+// the inferred one for the others
+func (r *renderer) encodedReference(graph *platoCue.RefGraph) func(cue.Value) (string, bool) {
+	return func(val cue.Value) (string, bool) {
+		_, path := val.ReferencePath()
+		name, ok := r.typeReference(val)
+		if !ok {
+			return "", false
+		}
+		if graph.Recursive(path.String()) {
+			return name + "Encoded", true
+		}
+		return "typeof " + name + ".Encoded", true
+	}
+}
+
+// base renders a value by its kind, with its constraints as filters
+func (r *renderer) base(val cue.Value, indent int) string {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok {
+			if val.Kind() == cue.NullKind {
+				return "Schema.Null"
+			}
+			return "Schema.Literal(" + lit + ")"
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		filters := lengthFilters(c, msgs, "minLength", "maxLength", "Schema.minLength", "Schema.maxLength")
+		for _, p := range c.Patterns {
+			filters = append(filters, filter("Schema.pattern", "/"+jsgen.EscapeRegex(p)+"/", msgs.For("pattern")))
+		}
+		return pipe("Schema.String", r.branded(filters))
+	case kind == cue.IntKind:
+		return pipe("Schema.Int", r.branded(rangeFilters(c, msgs)))
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return pipe("Schema.Number", r.branded(rangeFilters(c, msgs)))
+	case kind == cue.BoolKind:
+		return "Schema.Boolean"
+	case kind == cue.NullKind:
+		return "Schema.Null"
+	case kind == cue.ListKind:
+		elem := "Schema.Unknown"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.schema(e, indent)
+		}
+		return pipe("Schema.Array("+elem+")", lengthFilters(c, msgs, "minItems", "maxItems", "Schema.minItems", "Schema.maxItems"))
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			return "Schema.Record({ key: Schema.String, value: " + r.schema(val.LookupPath(cue.MakePath(cue.AnyString)), indent) + " })"
+		case !platoCue.HasFields(val):
+			return "Schema.Record({ key: Schema.String, value: Schema.Unknown })"
+		default:
+			return r.object(val, indent)
+		}
+	default:
+		return "Schema.Unknown"
+	}
+}
+
+// object renders a struct as Schema.Struct with one field per line.
+// Fields with a default are optional and take the default when decoding.
+func (r *renderer) object(val cue.Value, indent int) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "Schema.Record({ key: Schema.String, value: Schema.Unknown })"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("Schema.Struct({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		schema := r.schema(fieldVal, indent+1)
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			schema = "Schema.optionalWith(" + schema + ", { default: () => " + def + " })"
+		} else if iter.IsOptional() {
+			schema = "Schema.optional(" + schema + ")"
+		}
+
+		var doc bytes.Buffer
+		writeDoc(&doc, platoCue.Description(fieldVal), pad)
+		b.WriteString(doc.String())
+		fmt.Fprintf(&b, "%s%s: %s,\n", pad, propertyName(label), schema)
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+	return b.String()
+}
+
+// lengthFilters renders the length filters of strings and lists, with the
+// @errmsg messages of the given keywords
+func lengthFilters(c platoCue.Constraints, msgs platoCue.ErrorMessages, minKeyword, maxKeyword, minFilter, maxFilter string) []string {
+	var filters []string
+	if c.MinLength != nil {
+		filters = append(filters, filter(minFilter, strconv.Itoa(*c.MinLength), msgs.For(minKeyword)))
+	}
+	if c.MaxLength != nil {
+		filters = append(filters, filter(maxFilter, strconv.Itoa(*c.MaxLength), msgs.For(maxKeyword)))
+	}
+	return filters
+}
+
+// rangeFilters renders bounds as Schema.greaterThanOrEqualTo,
+// Schema.lessThanOrEqualTo, Schema.greaterThan and Schema.lessThan
+func rangeFilters(c platoCue.Constraints, msgs platoCue.ErrorMessages) []string {
+	var filters []string
+	if c.Minimum != nil {
+		filters = append(filters, filter("Schema.greaterThanOrEqualTo", jsgen.Number(*c.Minimum), msgs.For("minimum")))
+	}
+	if c.ExclusiveMinimum != nil {
+		filters = append(filters, filter("Schema.greaterThan", jsgen.Number(*c.ExclusiveMinimum), msgs.For("exclusiveMinimum")))
+	}
+	if c.Maximum != nil {
+		filters = append(filters, filter("Schema.lessThanOrEqualTo", jsgen.Number(*c.Maximum), msgs.For("maximum")))
+	}
+	if c.ExclusiveMaximum != nil {
+		filters = append(filters, filter("Schema.lessThan", jsgen.Number(*c.ExclusiveMaximum), msgs.For("exclusiveMaximum")))
+	}
+	return filters
+}
+
+// filter renders a filter with an optional message annotation
+func filter(fn, arg, msg string) string {
+	if msg != "" {
+		return fn + "(" + arg + ", { message: () => " + jsgen.String(msg) + " })"
+	}
+	return fn + "(" + arg + ")"
+}
+
+// branded adds the brand of the definition being rendered to the filters
+// of its scalar
+func (r *renderer) branded(filters []string) []string {
+	if r.brand == "" {
+		return filters
+	}
+	filters = append(filters, "Schema.brand("+jsgen.String(r.brand)+")")
+	r.brand = ""
+	return filters
+}
+
+// pipe adds filters to a schema
+func pipe(schema string, filters []string) string {
+	if len(filters) == 0 {
+		return schema
+	}
+	return schema + ".pipe(" + strings.Join(filters, ", ") + ")"
+}
+
+// nullableIf wraps a schema in Schema.NullOr
+func nullableIf(nullable bool, schema string) string {
+	if nullable {
+		return "Schema.NullOr(" + schema + ")"
+	}
+	return schema
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,62 @@
+package elixir
+
+import (
+	"bytes"
+)
+
+// codecFunctions are the module functions of each binary codec
+var codecFunctions = map[string]string{
+	"msgpack": `  @doc """
+  Encodes a value as MessagePack (Msgpax). Structs are encoded as maps.
+  """
+  @spec to_msgpack(term()) :: binary()
+  def to_msgpack(value), do: value |> to_plain() |> Msgpax.pack!(iodata: false)
+
+  @doc """
+  Decodes MessagePack into maps with string keys.
+  """
+  @spec from_msgpack(binary()) :: {:ok, term()} | {:error, term()}
+  def from_msgpack(data), do: Msgpax.unpack(data)
+`,
+	"cbor": `  @doc """
+  Encodes a value as CBOR. Structs are encoded as maps.
+  """
+  @spec to_cbor(term()) :: binary()
+  def to_cbor(value), do: value |> to_plain() |> CBOR.encode()
+
+  @doc """
+  Decodes CBOR into maps with string keys. Trailing bytes are an error.
+  """
+  @spec from_cbor(binary()) :: {:ok, term()} | {:error, term()}
+  def from_cbor(data) do
+    case CBOR.decode(data) do
+      {:ok, value, ""} -> {:ok, value}
+      {:ok, _value, rest} -> {:error, {:trailing_bytes, byte_size(rest)}}
+      {:error, reason} -> {:error, reason}
+    end
+  end
+`,
+}
+
+// toPlain converts structs to maps for the codecs, keeping the date and
+// time structs that CBOR encodes natively
+const toPlain = `  defp to_plain(%DateTime{} = value), do: value
+  defp to_plain(%_{} = struct), do: struct |> Map.from_struct() |> to_plain()
+  defp to_plain(map) when is_map(map), do: Map.new(map, fn {k, v} -> {k, to_plain(v)} end)
+  defp to_plain(list) when is_list(list), do: Enum.map(list, &to_plain/1)
+  defp to_plain(value), do: value
+`
+
+// writeCodecFunctions writes encode and decode functions for the selected
+// codecs
+func writeCodecFunctions(buf *bytes.Buffer, codecs []string) {
+	if len(codecs) == 0 {
+		return
+	}
+	for _, codec := range codecs {
+		buf.WriteString(codecFunctions[codec])
+		buf.WriteString("\n")
+	}
+	buf.WriteString(toPlain)
+	buf.WriteString("\n")
+}
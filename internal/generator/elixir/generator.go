@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
 )
 
@@ -36,6 +37,12 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	fmt.Fprintf(&buf, "  Type definitions generated from CUE schemas.\n")
 	fmt.Fprintf(&buf, "  \"\"\"\n\n")
 
+	// Binary codec helpers
+	codecs, err := ctx.CodecsOption()
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract definitions
 	defs, err := extractDefinitions(ctx.Value)
 	if err != nil {
@@ -63,6 +70,8 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 		buf.WriteString("\n")
 	}
 
+	writeCodecFunctions(&buf, codecs)
+
 	buf.WriteString("end\n")
 
 	return buf.Bytes(), nil
@@ -73,7 +82,8 @@ func (g *Generator) Validate(ctx *generator.Context) error {
 	if err := ctx.Value.Err(); err != nil {
 		return fmt.Errorf("invalid CUE value: %w", err)
 	}
-	return nil
+	_, err := ctx.CodecsOption()
+	return err
 }
 
 // extractDefinitions extracts all definitions from a CUE value
@@ -95,10 +105,19 @@ func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
 	return defs, nil
 }
 
-// generateTypespec generates an Elixir typespec
+// generateTypespec generates an Elixir typespec. The description of the
+// definition becomes its @typedoc and those of fields comments.
 func generateTypespec(name string, val cue.Value) (string, error) {
 	var buf bytes.Buffer
 
+	if doc := platoCue.Description(val); doc != "" {
+		buf.WriteString("  @typedoc \"\"\"\n")
+		for _, line := range strings.Split(heredoc(doc), "\n") {
+			buf.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+		}
+		buf.WriteString("  \"\"\"\n")
+	}
+
 	// Start type definition
 	fmt.Fprintf(&buf, "  @type %s() :: %%__MODULE__.%s{\n", toSnakeCase(name), name)
 
@@ -127,7 +146,14 @@ func generateTypespec(name string, val cue.Value) (string, error) {
 			elixirType = elixirType + " | nil"
 		}
 
-		fields = append(fields, fmt.Sprintf("    %s: %s", label, elixirType))
+		var field strings.Builder
+		if doc := platoCue.Description(fieldVal); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				field.WriteString(strings.TrimRight("    # "+line, " ") + "\n")
+			}
+		}
+		fmt.Fprintf(&field, "    %s: %s", label, elixirType)
+		fields = append(fields, field.String())
 	}
 
 	buf.WriteString(strings.Join(fields, ",\n"))
@@ -149,6 +175,13 @@ func generateTypespec(name string, val cue.Value) (string, error) {
 	return buf.String(), nil
 }
 
+// heredoc escapes text for a """ heredoc
+func heredoc(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"""`, `\"\"\"`)
+	return strings.ReplaceAll(s, "#{", `\#{`)
+}
+
 // mapToElixirType maps a CUE type to Elixir
 func mapToElixirType(val cue.Value) string {
 	kind := val.IncompleteKind()
@@ -223,4 +256,5 @@ func toSnakeCase(name string) string {
 func init() {
 	// Register the generator
 	generator.Register(NewGenerator())
+	generator.RegisterAlias("ex", "elixir")
 }
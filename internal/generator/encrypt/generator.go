@@ -0,0 +1,327 @@
+package encrypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// maxDepth limits how deep nested structs are searched for encrypted fields
+const maxDepth = 8
+
+// Generator generates envelope-encryption helpers for @encrypt fields
+type Generator struct{}
+
+// NewGenerator creates a new encryption hints generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "encrypt"
+}
+
+// Field is a field encrypted at rest under a KMS key alias
+type Field struct {
+	Path     string `json:"path"`
+	KeyAlias string `json:"keyAlias"`
+}
+
+// Generate generates Go helpers or a JSON field list
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	fields, err := extractFields(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract encrypted fields: %w", err)
+	}
+
+	switch format := ctx.GetStringOption("format", "go"); format {
+	case "go":
+		return generateGo(ctx.GetStringOption("package", "encryption"), fields), nil
+	case "json":
+		return json.MarshalIndent(fields, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported format %q (supported: go, json)", format)
+	}
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, err := extractFields(ctx.Value)
+	return err
+}
+
+// extractFields collects @encrypt fields per definition (without the #)
+func extractFields(val cue.Value) (map[string][]Field, error) {
+	result := make(map[string][]Field)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		name := strings.TrimPrefix(iter.Selector().String(), "#")
+
+		// Only structs have fields; enums and scalars have none, and the
+		// members of unions of structs are definitions of their own
+		if _, union := platoCue.UnionOf(iter.Value()); union || iter.Value().IncompleteKind() != cue.StructKind {
+			continue
+		}
+
+		var fields []Field
+		if err := walkFields(iter.Value(), "", 0, &fields); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(fields) > 0 {
+			result[name] = fields
+		}
+	}
+
+	return result, nil
+}
+
+// walkFields records fields marked with @encrypt(key-alias) in a struct
+func walkFields(val cue.Value, prefix string, depth int, out *[]Field) error {
+	if depth > maxDepth {
+		return nil
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		path := iter.Selector().Unquoted()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		field := iter.Value()
+
+		if attr, ok := platoCue.GetAttr(field, "encrypt"); ok {
+			alias := attr.Arg(0)
+			if alias == "" {
+				return fmt.Errorf("@encrypt on %s requires a KMS key alias, e.g. @encrypt(\"alias/pii\")", path)
+			}
+			*out = append(*out, Field{Path: path, KeyAlias: alias})
+			continue
+		}
+
+		if field.IncompleteKind() == cue.StructKind {
+			if err := walkFields(field, path, depth+1, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// generateGo generates envelope-encryption helpers over JSON documents
+func generateGo(pkg string, fields map[string][]Field) []byte {
+	var buf bytes.Buffer
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString(goImports)
+
+	buf.WriteString("// EncryptedFields maps each type to its encrypted field paths and KMS key aliases.\n")
+	buf.WriteString("var EncryptedFields = map[string]map[string]string{\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\t%s: {\n", strconv.Quote(name))
+		for _, f := range fields[name] {
+			fmt.Fprintf(&buf, "\t\t%s: %s,\n", strconv.Quote(f.Path), strconv.Quote(f.KeyAlias))
+		}
+		buf.WriteString("\t},\n")
+	}
+	buf.WriteString("}\n")
+
+	buf.WriteString(goRuntime)
+
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\n// Encrypt%s encrypts the encrypted fields of a %s document in place.\n", name, name)
+		fmt.Fprintf(&buf, "func Encrypt%s(ctx context.Context, keys KeyService, doc map[string]interface{}) error {\n", name)
+		fmt.Fprintf(&buf, "\treturn EncryptFields(ctx, keys, %s, doc)\n", strconv.Quote(name))
+		buf.WriteString("}\n")
+
+		fmt.Fprintf(&buf, "\n// Decrypt%s decrypts the encrypted fields of a %s document in place.\n", name, name)
+		fmt.Fprintf(&buf, "func Decrypt%s(ctx context.Context, keys KeyService, doc map[string]interface{}) error {\n", name)
+		fmt.Fprintf(&buf, "\treturn DecryptFields(ctx, keys, %s, doc)\n", strconv.Quote(name))
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes()
+}
+
+// goImports is the import block of the generated Go helpers
+const goImports = `import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+`
+
+// goRuntime is the envelope-encryption runtime shared by all types
+const goRuntime = `
+// KeyService issues and unwraps data keys under a KMS key alias
+// (e.g. AWS KMS GenerateDataKey / Decrypt).
+type KeyService interface {
+	GenerateDataKey(ctx context.Context, keyAlias string) (plaintext, wrapped []byte, err error)
+	DecryptDataKey(ctx context.Context, keyAlias string, wrapped []byte) ([]byte, error)
+}
+
+// envelope is the stored form of an encrypted value
+type envelope struct {
+	Key        []byte ` + "`json:\"k\"`" + `
+	Nonce      []byte ` + "`json:\"n\"`" + `
+	Ciphertext []byte ` + "`json:\"c\"`" + `
+}
+
+// EncryptFields replaces the encrypted fields of a document with envelopes:
+// each value is JSON-encoded, sealed with AES-256-GCM under a fresh data key,
+// and stored with the wrapped data key as a base64 string.
+func EncryptFields(ctx context.Context, keys KeyService, typeName string, doc map[string]interface{}) error {
+	for path, alias := range EncryptedFields[typeName] {
+		parent, key, ok := lookupParent(doc, path)
+		if !ok {
+			continue
+		}
+		value, ok := parent[key]
+		if !ok || value == nil {
+			continue
+		}
+
+		plaintext, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+
+		dataKey, wrapped, err := keys.GenerateDataKey(ctx, alias)
+		if err != nil {
+			return fmt.Errorf("%s.%s: failed to generate data key: %w", typeName, path, err)
+		}
+
+		gcm, err := newGCM(dataKey)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+
+		sealed, err := json.Marshal(envelope{
+			Key:        wrapped,
+			Nonce:      nonce,
+			Ciphertext: gcm.Seal(nil, nonce, plaintext, []byte(typeName+"."+path)),
+		})
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+		parent[key] = base64.StdEncoding.EncodeToString(sealed)
+	}
+	return nil
+}
+
+// DecryptFields restores the encrypted fields of a document from envelopes.
+func DecryptFields(ctx context.Context, keys KeyService, typeName string, doc map[string]interface{}) error {
+	for path, alias := range EncryptedFields[typeName] {
+		parent, key, ok := lookupParent(doc, path)
+		if !ok {
+			continue
+		}
+		encoded, ok := parent[key].(string)
+		if !ok {
+			continue
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("%s.%s: invalid envelope: %w", typeName, path, err)
+		}
+		var env envelope
+		if err := json.Unmarshal(raw, &env); err != nil {
+			return fmt.Errorf("%s.%s: invalid envelope: %w", typeName, path, err)
+		}
+
+		dataKey, err := keys.DecryptDataKey(ctx, alias, env.Key)
+		if err != nil {
+			return fmt.Errorf("%s.%s: failed to decrypt data key: %w", typeName, path, err)
+		}
+
+		gcm, err := newGCM(dataKey)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+		plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, []byte(typeName+"."+path))
+		if err != nil {
+			return fmt.Errorf("%s.%s: failed to decrypt: %w", typeName, path, err)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(plaintext, &value); err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, path, err)
+		}
+		parent[key] = value
+	}
+	return nil
+}
+
+// newGCM creates an AES-GCM cipher for a data key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// lookupParent returns the map holding the last segment of a dotted path
+func lookupParent(doc map[string]interface{}, path string) (map[string]interface{}, string, bool) {
+	segments := strings.Split(path, ".")
+	current := doc
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]interface{})
+		if !ok {
+			return nil, "", false
+		}
+		current = next
+	}
+	return current, segments[len(segments)-1], true
+}
+`
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,31 @@
+package encrypt
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestExtractFieldsSkipsNonStructDefinitions(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Status: "active" | "inactive"
+#A: {kind: "a"}
+#B: {kind: "b"}
+#AB: #A | #B
+#User: {
+	email:  string @encrypt("alias/pii")
+	status: #Status
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := extractFields(val)
+	if err != nil {
+		t.Fatalf("extractFields: %v", err)
+	}
+	if len(fields) != 1 || len(fields["User"]) != 1 || fields["User"][0].KeyAlias != "alias/pii" {
+		t.Errorf("fields = %v, want User.email only", fields)
+	}
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates typed publish/subscribe helpers for @event definitions
+type Generator struct{}
+
+// NewGenerator creates a new event envelope generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "events"
+}
+
+// event is a definition published as an event payload
+type event struct {
+	Name       string // payload type name, e.g. OrderCreated
+	Subject    string // NATS subject
+	Bus        string // EventBridge bus name
+	Source     string // envelope / EventBridge source
+	DetailType string // EventBridge detail type
+	Doc        string
+	Required   []string
+	Enums      map[string][]string
+}
+
+// Generate generates the helpers for the configured language and transport
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	events, err := extractEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events found: mark payload definitions with @event()")
+	}
+
+	transport := ctx.GetStringOption("transport", "nats")
+	if transport != "nats" && transport != "eventbridge" {
+		return nil, fmt.Errorf("unsupported transport %q (supported: nats, eventbridge)", transport)
+	}
+
+	switch lang := ctx.GetStringOption("language", "go"); lang {
+	case "go":
+		return generateGo(ctx.GetStringOption("package", "events"), transport, events), nil
+	case "typescript", "ts":
+		return generateTypeScript(ctx.GetStringOption("zodImport", "./schemas"), transport, events), nil
+	default:
+		return nil, fmt.Errorf("unsupported language %q (supported: go, typescript)", lang)
+	}
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, err := extractEvents(ctx)
+	return err
+}
+
+// extractEvents collects definitions marked with
+// @event(subject=..., bus=..., source=..., detailType=...). Unset names fall
+// back to the "bus" and "source" options, a subject derived from the
+// definition name (OrderCreated -> order.created) and the definition name as
+// the detail type.
+func extractEvents(ctx *generator.Context) ([]event, error) {
+	iter, err := ctx.Value.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	defaultSource := ctx.GetStringOption("source", "")
+	if defaultSource == "" && ctx.Config != nil {
+		defaultSource = ctx.Config.Name
+	}
+	defaultBus := ctx.GetStringOption("bus", "default")
+
+	var events []event
+	subjects := make(map[string]string)
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		attr, ok := platoCue.GetAttr(iter.Value(), "event")
+		if !ok {
+			continue
+		}
+
+		name := toTypeName(iter.Selector().String())
+		e := event{
+			Name:       name,
+			Subject:    orDefault(attr.Param("subject"), subjectOf(name)),
+			Bus:        orDefault(attr.Param("bus"), defaultBus),
+			Source:     orDefault(attr.Param("source"), defaultSource),
+			DetailType: orDefault(attr.Param("detailType"), name),
+			Doc:        platoCue.Description(iter.Value()),
+			Enums:      make(map[string][]string),
+		}
+
+		if other, dup := subjects[e.Subject]; dup {
+			return nil, fmt.Errorf("subject %q is used by both %s and %s", e.Subject, other, name)
+		}
+		subjects[e.Subject] = name
+
+		fields, err := iter.Value().Fields(cue.Optional(true))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for fields.Next() {
+			if fields.Selector().IsDefinition() {
+				continue
+			}
+			label := fields.Selector().Unquoted()
+			if !fields.IsOptional() {
+				e.Required = append(e.Required, label)
+			}
+			if members := stringEnum(fields.Value()); len(members) > 0 {
+				e.Enums[label] = members
+			}
+		}
+
+		events = append(events, e)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	return events, nil
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// subjectOf derives a subject from a type name, e.g. OrderCreated -> order.created
+func subjectOf(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// toTypeName converts a CUE definition name to the generated type name
+func toTypeName(name string) string {
+	name = strings.TrimPrefix(name, "#")
+	if len(name) > 0 {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
+
+// orDefault returns value, or fallback if value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,267 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generateGo renders Go publish/subscribe helpers. Payload types are expected
+// in the same package (e.g. from `platosl gen go --package <same>`).
+func generateGo(pkg, transport string, events []event) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	buf.WriteString("import (\n")
+	if transport == "eventbridge" {
+		buf.WriteString("\t\"context\"\n")
+	}
+	buf.WriteString("\t\"crypto/rand\"\n")
+	buf.WriteString("\t\"encoding/hex\"\n")
+	buf.WriteString("\t\"encoding/json\"\n")
+	buf.WriteString("\t\"fmt\"\n")
+	buf.WriteString("\t\"strings\"\n")
+	buf.WriteString("\t\"time\"\n")
+	if transport == "nats" {
+		buf.WriteString("\n\t\"github.com/nats-io/nats.go\"\n")
+	} else {
+		buf.WriteString("\n\t\"github.com/aws/aws-sdk-go-v2/aws\"\n")
+		buf.WriteString("\t\"github.com/aws/aws-sdk-go-v2/service/eventbridge\"\n")
+		buf.WriteString("\t\"github.com/aws/aws-sdk-go-v2/service/eventbridge/types\"\n")
+	}
+	buf.WriteString(")\n")
+
+	buf.WriteString(goRuntime)
+	if transport == "eventbridge" {
+		buf.WriteString(goEventBridgeRuntime)
+	}
+
+	for _, e := range events {
+		buf.WriteString("\n")
+		writeGoConstants(&buf, transport, e)
+		writeGoValidate(&buf, e)
+		writeGoDecode(&buf, e)
+		if transport == "nats" {
+			writeGoNATS(&buf, e)
+		} else {
+			writeGoEventBridge(&buf, e)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// writeGoConstants writes the routing names of an event
+func writeGoConstants(buf *bytes.Buffer, transport string, e event) {
+	if e.Doc != "" {
+		for _, line := range strings.Split(e.Doc, "\n") {
+			fmt.Fprintf(buf, "// %s\n", line)
+		}
+		buf.WriteString("//\n")
+	}
+	fmt.Fprintf(buf, "// Routing of %s events.\n", e.Name)
+	buf.WriteString("const (\n")
+	if transport == "nats" {
+		fmt.Fprintf(buf, "\t%sSubject = %s\n", e.Name, strconv.Quote(e.Subject))
+		fmt.Fprintf(buf, "\t%sSource  = %s\n", e.Name, strconv.Quote(e.Source))
+	} else {
+		fmt.Fprintf(buf, "\t%sBus        = %s\n", e.Name, strconv.Quote(e.Bus))
+		fmt.Fprintf(buf, "\t%sSource     = %s\n", e.Name, strconv.Quote(e.Source))
+		fmt.Fprintf(buf, "\t%sDetailType = %s\n", e.Name, strconv.Quote(e.DetailType))
+		pattern := fmt.Sprintf(`{"source":[%s],"detail-type":[%s]}`, strconv.Quote(e.Source), strconv.Quote(e.DetailType))
+		fmt.Fprintf(buf, "\n\t// %sPattern is the EventBridge rule pattern matching %s events.\n", e.Name, e.Name)
+		fmt.Fprintf(buf, "\t%sPattern = %s\n", e.Name, "`"+pattern+"`")
+	}
+	buf.WriteString(")\n\n")
+}
+
+// writeGoValidate writes the payload check run before publishing and after
+// receiving
+func writeGoValidate(buf *bytes.Buffer, e event) {
+	fmt.Fprintf(buf, "// validate%s checks required fields and enum values of %s payloads.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func validate%s(doc map[string]interface{}) error {\n", e.Name)
+	buf.WriteString("\tvar problems []string\n")
+	if len(e.Required) > 0 {
+		fmt.Fprintf(buf, "\tfor _, field := range %s {\n", goStrings(e.Required))
+		buf.WriteString("\t\tif doc[field] == nil {\n")
+		buf.WriteString("\t\t\tproblems = append(problems, field+\" is required\")\n")
+		buf.WriteString("\t\t}\n")
+		buf.WriteString("\t}\n")
+	}
+	for _, field := range sortedKeys(e.Enums) {
+		fmt.Fprintf(buf, "\tif v, ok := doc[%s].(string); ok && !oneOf(v, %s) {\n", strconv.Quote(field), goStrings(e.Enums[field]))
+		fmt.Fprintf(buf, "\t\tproblems = append(problems, fmt.Sprintf(\"%s: invalid value %%q\", v))\n", field)
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("\tif len(problems) > 0 {\n")
+	fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"invalid %s payload: %%s\", strings.Join(problems, \"; \"))\n", e.Name)
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n\n")
+}
+
+// writeGoDecode writes the envelope decoder of an event
+func writeGoDecode(buf *bytes.Buffer, e event) {
+	fmt.Fprintf(buf, "// Decode%s decodes an envelope and validates its %s payload.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func Decode%s(data []byte) (Envelope, %s, error) {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\tvar payload %s\n", e.Name)
+	fmt.Fprintf(buf, "\tenv, err := decodeEnvelope(data, validate%s, &payload)\n", e.Name)
+	buf.WriteString("\treturn env, payload, err\n")
+	buf.WriteString("}\n\n")
+}
+
+// writeGoNATS writes NATS publish and subscribe helpers
+func writeGoNATS(buf *bytes.Buffer, e event) {
+	fmt.Fprintf(buf, "// Publish%s validates payload and publishes it on %sSubject.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func Publish%s(nc *nats.Conn, payload %s) error {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\tdata, err := encodeEnvelope(%s, %sSource, payload, validate%s)\n", strconv.Quote(e.Name), e.Name, e.Name)
+	buf.WriteString("\tif err != nil {\n")
+	buf.WriteString("\t\treturn err\n")
+	buf.WriteString("\t}\n")
+	fmt.Fprintf(buf, "\treturn nc.Publish(%sSubject, data)\n", e.Name)
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(buf, "// Subscribe%s calls handler for every valid %s event. Messages that\n", e.Name, e.Name)
+	buf.WriteString("// fail to decode are passed to onError, which may be nil.\n")
+	fmt.Fprintf(buf, "func Subscribe%s(nc *nats.Conn, handler func(Envelope, %s), onError func(error)) (*nats.Subscription, error) {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\treturn nc.Subscribe(%sSubject, func(msg *nats.Msg) {\n", e.Name)
+	fmt.Fprintf(buf, "\t\tenv, payload, err := Decode%s(msg.Data)\n", e.Name)
+	buf.WriteString("\t\tif err != nil {\n")
+	buf.WriteString("\t\t\tif onError != nil {\n")
+	buf.WriteString("\t\t\t\tonError(err)\n")
+	buf.WriteString("\t\t\t}\n")
+	buf.WriteString("\t\t\treturn\n")
+	buf.WriteString("\t\t}\n")
+	buf.WriteString("\t\thandler(env, payload)\n")
+	buf.WriteString("\t})\n")
+	buf.WriteString("}\n")
+}
+
+// writeGoEventBridge writes EventBridge publish helpers; consumers (e.g.
+// Lambda targets) decode the event detail with Decode<Name>
+func writeGoEventBridge(buf *bytes.Buffer, e event) {
+	fmt.Fprintf(buf, "// Publish%s validates payload and puts it on %sBus.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func Publish%s(ctx context.Context, client EventBridgeAPI, payload %s) error {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\tdata, err := encodeEnvelope(%s, %sSource, payload, validate%s)\n", strconv.Quote(e.Name), e.Name, e.Name)
+	buf.WriteString("\tif err != nil {\n")
+	buf.WriteString("\t\treturn err\n")
+	buf.WriteString("\t}\n")
+	fmt.Fprintf(buf, "\treturn putEvent(ctx, client, %sBus, %sSource, %sDetailType, data)\n", e.Name, e.Name, e.Name)
+	buf.WriteString("}\n")
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// goStrings renders a string slice literal
+func goStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// goRuntime is the envelope encoding shared by all events
+const goRuntime = `
+// Envelope wraps every event payload.
+type Envelope struct {
+	ID     string          ` + "`json:\"id\"`" + `
+	Type   string          ` + "`json:\"type\"`" + `
+	Source string          ` + "`json:\"source,omitempty\"`" + `
+	Time   time.Time       ` + "`json:\"time\"`" + `
+	Data   json.RawMessage ` + "`json:\"data\"`" + `
+}
+
+// encodeEnvelope validates a payload and wraps it in an envelope
+func encodeEnvelope(typeName, source string, payload interface{}, validate func(map[string]interface{}) error) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", typeName, err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", typeName, err)
+	}
+	if err := validate(doc); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return json.Marshal(Envelope{
+		ID:     hex.EncodeToString(id),
+		Type:   typeName,
+		Source: source,
+		Time:   time.Now().UTC(),
+		Data:   data,
+	})
+}
+
+// decodeEnvelope decodes an envelope and validates its payload into out
+func decodeEnvelope(data []byte, validate func(map[string]interface{}) error, out interface{}) (Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return env, fmt.Errorf("invalid envelope: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(env.Data, &doc); err != nil {
+		return env, fmt.Errorf("invalid payload: %w", err)
+	}
+	if err := validate(doc); err != nil {
+		return env, err
+	}
+	return env, json.Unmarshal(env.Data, out)
+}
+
+// oneOf reports whether value is one of allowed
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+`
+
+// goEventBridgeRuntime is the EventBridge client plumbing
+const goEventBridgeRuntime = `
+// EventBridgeAPI is the subset of *eventbridge.Client used to publish events.
+type EventBridgeAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// putEvent puts a single entry and reports a failed entry as an error
+func putEvent(ctx context.Context, client EventBridgeAPI, bus, source, detailType string, detail []byte) error {
+	out, err := client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{{
+			EventBusName: aws.String(bus),
+			Source:       aws.String(source),
+			DetailType:   aws.String(detailType),
+			Detail:       aws.String(string(detail)),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", detailType, err)
+	}
+	if out.FailedEntryCount > 0 && len(out.Entries) > 0 {
+		return fmt.Errorf("%s: %s: %s", detailType, aws.ToString(out.Entries[0].ErrorCode), aws.ToString(out.Entries[0].ErrorMessage))
+	}
+	return nil
+}
+`
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// generateTypeScript renders TypeScript publish/subscribe helpers. Payloads
+// are validated with the schemas of the Zod generator output.
+func generateTypeScript(zodImport, transport string, events []event) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+
+	if transport == "nats" {
+		buf.WriteString("import { JSONCodec, type NatsConnection, type Subscription } from 'nats';\n")
+	} else {
+		buf.WriteString("import { PutEventsCommand, type EventBridgeClient } from '@aws-sdk/client-eventbridge';\n")
+	}
+
+	var imports []string
+	for _, e := range events {
+		imports = append(imports, e.Name+"Schema", "type "+e.Name)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&buf, "import { %s } from %s;\n\n", strings.Join(imports, ", "), tsQuote(zodImport))
+
+	buf.WriteString("/** Envelope wrapping every event payload. */\n")
+	buf.WriteString("export interface Envelope<T> {\n")
+	buf.WriteString("  id: string;\n")
+	buf.WriteString("  type: string;\n")
+	buf.WriteString("  source?: string;\n")
+	buf.WriteString("  time: string;\n")
+	buf.WriteString("  data: T;\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("function envelope<T>(type: string, source: string, data: T): Envelope<T> {\n")
+	buf.WriteString("  return { id: crypto.randomUUID(), type, source, time: new Date().toISOString(), data };\n")
+	buf.WriteString("}\n\n")
+
+	if transport == "nats" {
+		writeTSNATS(&buf, events)
+	} else {
+		writeTSEventBridge(&buf, events)
+	}
+
+	return buf.Bytes()
+}
+
+// writeTSNATS writes the subject table and NATS helpers
+func writeTSNATS(buf *bytes.Buffer, events []event) {
+	buf.WriteString("const codec = JSONCodec<Envelope<unknown>>();\n\n")
+
+	buf.WriteString("/** NATS subjects per event. */\n")
+	buf.WriteString("export const subjects = {\n")
+	for _, e := range events {
+		fmt.Fprintf(buf, "  %s: %s,\n", e.Name, tsQuote(e.Subject))
+	}
+	buf.WriteString("} as const;\n")
+
+	for _, e := range events {
+		buf.WriteString("\n")
+		writeTSDoc(buf, e)
+		fmt.Fprintf(buf, "export function publish%s(nc: NatsConnection, payload: %s): void {\n", e.Name, e.Name)
+		fmt.Fprintf(buf, "  const data = %sSchema.parse(payload);\n", e.Name)
+		fmt.Fprintf(buf, "  nc.publish(subjects.%s, codec.encode(envelope(%s, %s, data)));\n", e.Name, tsQuote(e.Name), tsQuote(e.Source))
+		buf.WriteString("}\n\n")
+
+		fmt.Fprintf(buf, "/** Decodes a %s envelope and validates its payload. */\n", e.Name)
+		fmt.Fprintf(buf, "export function decode%s(raw: Uint8Array): Envelope<%s> {\n", e.Name, e.Name)
+		buf.WriteString("  const env = codec.decode(raw);\n")
+		fmt.Fprintf(buf, "  return { ...env, data: %sSchema.parse(env.data) };\n", e.Name)
+		buf.WriteString("}\n\n")
+
+		fmt.Fprintf(buf, "/** Calls handler for every valid %s event; invalid messages go to onError. */\n", e.Name)
+		fmt.Fprintf(buf, "export function subscribe%s(\n", e.Name)
+		buf.WriteString("  nc: NatsConnection,\n")
+		fmt.Fprintf(buf, "  handler: (event: Envelope<%s>) => void | Promise<void>,\n", e.Name)
+		buf.WriteString("  onError: (err: unknown) => void = console.error,\n")
+		buf.WriteString("): Subscription {\n")
+		fmt.Fprintf(buf, "  return nc.subscribe(subjects.%s, {\n", e.Name)
+		buf.WriteString("    callback: (err, msg) => {\n")
+		buf.WriteString("      if (err) {\n")
+		buf.WriteString("        onError(err);\n")
+		buf.WriteString("        return;\n")
+		buf.WriteString("      }\n")
+		buf.WriteString("      try {\n")
+		fmt.Fprintf(buf, "        Promise.resolve(handler(decode%s(msg.data))).catch(onError);\n", e.Name)
+		buf.WriteString("      } catch (e) {\n")
+		buf.WriteString("        onError(e);\n")
+		buf.WriteString("      }\n")
+		buf.WriteString("    },\n")
+		buf.WriteString("  });\n")
+		buf.WriteString("}\n")
+	}
+}
+
+// writeTSEventBridge writes the routing table and EventBridge helpers
+func writeTSEventBridge(buf *bytes.Buffer, events []event) {
+	buf.WriteString("/** EventBridge routing per event; pattern matches the event in rules. */\n")
+	buf.WriteString("export const routes = {\n")
+	for _, e := range events {
+		fmt.Fprintf(buf, "  %s: {\n", e.Name)
+		fmt.Fprintf(buf, "    bus: %s,\n", tsQuote(e.Bus))
+		fmt.Fprintf(buf, "    source: %s,\n", tsQuote(e.Source))
+		fmt.Fprintf(buf, "    detailType: %s,\n", tsQuote(e.DetailType))
+		fmt.Fprintf(buf, "    pattern: { source: [%s], 'detail-type': [%s] },\n", tsQuote(e.Source), tsQuote(e.DetailType))
+		buf.WriteString("  },\n")
+	}
+	buf.WriteString("} as const;\n")
+
+	for _, e := range events {
+		buf.WriteString("\n")
+		writeTSDoc(buf, e)
+		fmt.Fprintf(buf, "export async function publish%s(client: EventBridgeClient, payload: %s): Promise<void> {\n", e.Name, e.Name)
+		fmt.Fprintf(buf, "  const data = %sSchema.parse(payload);\n", e.Name)
+		fmt.Fprintf(buf, "  const route = routes.%s;\n", e.Name)
+		buf.WriteString("  const out = await client.send(\n")
+		buf.WriteString("    new PutEventsCommand({\n")
+		buf.WriteString("      Entries: [\n")
+		buf.WriteString("        {\n")
+		buf.WriteString("          EventBusName: route.bus,\n")
+		buf.WriteString("          Source: route.source,\n")
+		buf.WriteString("          DetailType: route.detailType,\n")
+		fmt.Fprintf(buf, "          Detail: JSON.stringify(envelope(%s, route.source, data)),\n", tsQuote(e.Name))
+		buf.WriteString("        },\n")
+		buf.WriteString("      ],\n")
+		buf.WriteString("    }),\n")
+		buf.WriteString("  );\n")
+		buf.WriteString("  if (out.FailedEntryCount) {\n")
+		fmt.Fprintf(buf, "    throw new Error(`failed to publish %s: ${out.Entries?.[0]?.ErrorMessage ?? 'unknown error'}`);\n", e.Name)
+		buf.WriteString("  }\n")
+		buf.WriteString("}\n\n")
+
+		fmt.Fprintf(buf, "/** Validates the detail of a received %s event (e.g. in a Lambda target). */\n", e.Name)
+		fmt.Fprintf(buf, "export function decode%s(detail: unknown): Envelope<%s> {\n", e.Name, e.Name)
+		buf.WriteString("  const env = (typeof detail === 'string' ? JSON.parse(detail) : detail) as Envelope<unknown>;\n")
+		fmt.Fprintf(buf, "  return { ...env, data: %sSchema.parse(env.data) };\n", e.Name)
+		buf.WriteString("}\n")
+	}
+}
+
+// writeTSDoc writes the doc comment of a publish helper
+func writeTSDoc(buf *bytes.Buffer, e event) {
+	buf.WriteString("/**\n")
+	if e.Doc != "" {
+		for _, line := range strings.Split(e.Doc, "\n") {
+			fmt.Fprintf(buf, " * %s\n", line)
+		}
+		buf.WriteString(" *\n")
+	}
+	fmt.Fprintf(buf, " * Validates payload against %sSchema and publishes it.\n", e.Name)
+	buf.WriteString(" */\n")
+}
+
+// tsQuote renders a single-quoted TypeScript string literal
+func tsQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,466 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates typed feature flag / settings accessors from the
+// definition marked as the settings schema
+type Generator struct{}
+
+// NewGenerator creates a new feature flag generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "flags"
+}
+
+// flag is a single setting of the settings definition
+type flag struct {
+	Field   string
+	Key     string
+	Kind    string // bool, int, float, string, json
+	Enum    []string
+	Default interface{}
+	Doc     string
+}
+
+// Generate generates the accessor layer for the configured language
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	name, def, err := findSettingsDefinition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := extractFlags(def)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract settings from %s: %w", name, err)
+	}
+
+	typeName := strings.TrimPrefix(name, "#")
+
+	switch lang := ctx.GetStringOption("language", "typescript"); lang {
+	case "typescript", "ts":
+		return generateTypeScript(typeName, flags), nil
+	case "go":
+		return generateGo(ctx.GetStringOption("package", "flags"), typeName, flags), nil
+	default:
+		return nil, fmt.Errorf("unsupported language %q (supported: typescript, go)", lang)
+	}
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, _, err := findSettingsDefinition(ctx)
+	return err
+}
+
+// findSettingsDefinition returns the definition named by the "definition"
+// option, or the single definition marked with @flags()
+func findSettingsDefinition(ctx *generator.Context) (string, cue.Value, error) {
+	if name := ctx.GetStringOption("definition", ""); name != "" {
+		val, err := platoCue.LookupDefinition(ctx.Value, name)
+		if err != nil {
+			return "", cue.Value{}, err
+		}
+		return "#" + strings.TrimPrefix(name, "#"), val, nil
+	}
+
+	iter, err := ctx.Value.Fields(cue.Definitions(true))
+	if err != nil {
+		return "", cue.Value{}, err
+	}
+
+	var names []string
+	var found cue.Value
+	for iter.Next() {
+		if iter.Selector().IsDefinition() && platoCue.HasAttr(iter.Value(), "flags") {
+			names = append(names, iter.Selector().String())
+			found = iter.Value()
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return "", cue.Value{}, fmt.Errorf("no settings definition found: mark one with @flags() or set the 'definition' option")
+	case 1:
+		return names[0], found, nil
+	default:
+		return "", cue.Value{}, fmt.Errorf("multiple definitions marked with @flags() (%s): set the 'definition' option", strings.Join(names, ", "))
+	}
+}
+
+// extractFlags reads the settings of a definition. The flag key defaults to
+// the field name and can be overridden with @flag(key=...).
+func extractFlags(def cue.Value) ([]flag, error) {
+	iter, err := def.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []flag
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		val := iter.Value()
+
+		f := flag{
+			Field: label,
+			Key:   label,
+			Kind:  flagKind(val),
+			Doc:   platoCue.Description(val),
+		}
+		if attr, ok := platoCue.GetAttr(val, "flag"); ok && attr.Param("key") != "" {
+			f.Key = attr.Param("key")
+		}
+		if f.Kind == "string" {
+			f.Enum = stringEnum(val)
+		}
+
+		if d, ok := val.Default(); ok && d.IsConcrete() {
+			var v interface{}
+			if err := d.Decode(&v); err == nil {
+				f.Default = v
+			}
+		}
+		if f.Default == nil {
+			f.Default = zeroValue(f)
+		}
+
+		flags = append(flags, f)
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Field < flags[j].Field })
+	return flags, nil
+}
+
+// flagKind maps a CUE value to a flag kind
+func flagKind(val cue.Value) string {
+	switch val.IncompleteKind() {
+	case cue.BoolKind:
+		return "bool"
+	case cue.IntKind:
+		return "int"
+	case cue.FloatKind, cue.NumberKind:
+		return "float"
+	case cue.StringKind:
+		return "string"
+	default:
+		return "json"
+	}
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// zeroValue returns the fallback for a flag without a CUE default
+func zeroValue(f flag) interface{} {
+	switch f.Kind {
+	case "bool":
+		return false
+	case "int", "float":
+		return 0
+	case "string":
+		if len(f.Enum) > 0 {
+			return f.Enum[0]
+		}
+		return ""
+	default:
+		return nil
+	}
+}
+
+// generateTypeScript generates a typed client over flag payloads
+func generateTypeScript(typeName string, flags []flag) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+
+	// Settings interface
+	fmt.Fprintf(&buf, "export interface %s {\n", typeName)
+	for _, f := range flags {
+		if f.Doc != "" {
+			fmt.Fprintf(&buf, "  /** %s */\n", strings.ReplaceAll(f.Doc, "\n", " "))
+		}
+		fmt.Fprintf(&buf, "  %s: %s;\n", tsProperty(f.Field), tsType(f))
+	}
+	buf.WriteString("}\n\n")
+
+	// Flag keys
+	fmt.Fprintf(&buf, "export const %sKeys = {\n", lowerFirst(typeName))
+	for _, f := range flags {
+		fmt.Fprintf(&buf, "  %s: %s,\n", tsProperty(f.Field), jsonLiteral(f.Key))
+	}
+	buf.WriteString("} as const;\n\n")
+
+	// Defaults
+	fmt.Fprintf(&buf, "export const %sDefaults: %s = {\n", lowerFirst(typeName), typeName)
+	for _, f := range flags {
+		fmt.Fprintf(&buf, "  %s: %s,\n", tsProperty(f.Field), jsonLiteral(f.Default))
+	}
+	buf.WriteString("};\n\n")
+
+	// Per-flag type checks
+	fmt.Fprintf(&buf, "const %sChecks: { [K in keyof %s]: (v: unknown) => boolean } = {\n", lowerFirst(typeName), typeName)
+	for _, f := range flags {
+		fmt.Fprintf(&buf, "  %s: (v) => %s,\n", tsProperty(f.Field), tsCheck(f))
+	}
+	buf.WriteString("};\n\n")
+
+	// Generic payload decoder
+	fmt.Fprintf(&buf, "/** Builds typed settings from a key/value payload, falling back to defaults for missing or mistyped values. */\n")
+	fmt.Fprintf(&buf, "export function create%s(values: Record<string, unknown>): %s {\n", typeName, typeName)
+	fmt.Fprintf(&buf, "  const result = { ...%sDefaults } as Record<keyof %s, unknown>;\n", lowerFirst(typeName), typeName)
+	fmt.Fprintf(&buf, "  for (const field of Object.keys(%sKeys) as (keyof %s)[]) {\n", lowerFirst(typeName), typeName)
+	fmt.Fprintf(&buf, "    const value = values[%sKeys[field]];\n", lowerFirst(typeName))
+	fmt.Fprintf(&buf, "    if (value !== undefined && %sChecks[field](value)) {\n", lowerFirst(typeName))
+	buf.WriteString("      result[field] = value;\n")
+	buf.WriteString("    }\n")
+	buf.WriteString("  }\n")
+	fmt.Fprintf(&buf, "  return result as %s;\n", typeName)
+	buf.WriteString("}\n\n")
+
+	// Provider adapters
+	fmt.Fprintf(&buf, "/** Builds typed settings from a LaunchDarkly allFlags() / allFlagsState().toJSON() payload. */\n")
+	fmt.Fprintf(&buf, "export function from%sLaunchDarkly(flags: Record<string, unknown>): %s {\n", typeName, typeName)
+	fmt.Fprintf(&buf, "  return create%s(flags);\n", typeName)
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "/** Builds typed settings from a ConfigCat getAllValuesAsync() payload. */\n")
+	fmt.Fprintf(&buf, "export function from%sConfigCat(values: { settingKey: string; settingValue: unknown }[]): %s {\n", typeName, typeName)
+	buf.WriteString("  const payload: Record<string, unknown> = {};\n")
+	buf.WriteString("  for (const { settingKey, settingValue } of values) {\n")
+	buf.WriteString("    payload[settingKey] = settingValue;\n")
+	buf.WriteString("  }\n")
+	fmt.Fprintf(&buf, "  return create%s(payload);\n", typeName)
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// tsType maps a flag to a TypeScript type
+func tsType(f flag) string {
+	switch f.Kind {
+	case "bool":
+		return "boolean"
+	case "int", "float":
+		return "number"
+	case "string":
+		if len(f.Enum) > 0 {
+			var members []string
+			for _, m := range f.Enum {
+				members = append(members, jsonLiteral(m))
+			}
+			return strings.Join(members, " | ")
+		}
+		return "string"
+	default:
+		return "unknown"
+	}
+}
+
+// tsCheck returns a TypeScript expression checking v against a flag's type
+func tsCheck(f flag) string {
+	switch f.Kind {
+	case "bool":
+		return `typeof v === "boolean"`
+	case "int":
+		return "Number.isInteger(v)"
+	case "float":
+		return `typeof v === "number"`
+	case "string":
+		if len(f.Enum) > 0 {
+			return fmt.Sprintf("(%s as unknown[]).includes(v)", jsonLiteral(f.Enum))
+		}
+		return `typeof v === "string"`
+	default:
+		return "true"
+	}
+}
+
+// tsProperty quotes a property name when it is not a valid identifier
+func tsProperty(name string) string {
+	if isIdentifier(name) {
+		return name
+	}
+	return jsonLiteral(name)
+}
+
+// generateGo generates typed getters with defaults over a flag payload
+func generateGo(pkg, typeName string, flags []flag) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Generated by PlatoSL\n")
+	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	fmt.Fprintf(&buf, "// %s provides typed access to feature flag / settings values.\n", typeName)
+	fmt.Fprintf(&buf, "// Missing or mistyped values fall back to the schema defaults.\n")
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	buf.WriteString("\tvalues map[string]interface{}\n")
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "// New%s wraps a flag payload (e.g. decoded from JSON).\n", typeName)
+	fmt.Fprintf(&buf, "func New%s(values map[string]interface{}) *%s {\n", typeName, typeName)
+	fmt.Fprintf(&buf, "\treturn &%s{values: values}\n", typeName)
+	buf.WriteString("}\n")
+
+	for _, f := range flags {
+		method := toGoName(f.Field)
+		buf.WriteString("\n")
+		if f.Doc != "" {
+			for _, line := range strings.Split(f.Doc, "\n") {
+				fmt.Fprintf(&buf, "// %s\n", line)
+			}
+		} else {
+			fmt.Fprintf(&buf, "// %s returns the %q setting.\n", method, f.Key)
+		}
+
+		switch f.Kind {
+		case "bool":
+			fmt.Fprintf(&buf, "func (s *%s) %s() bool {\n", typeName, method)
+			fmt.Fprintf(&buf, "\tif v, ok := s.values[%q].(bool); ok {\n", f.Key)
+			buf.WriteString("\t\treturn v\n")
+			buf.WriteString("\t}\n")
+			fmt.Fprintf(&buf, "\treturn %v\n", f.Default)
+		case "int":
+			fmt.Fprintf(&buf, "func (s *%s) %s() int64 {\n", typeName, method)
+			fmt.Fprintf(&buf, "\tswitch v := s.values[%q].(type) {\n", f.Key)
+			buf.WriteString("\tcase int:\n\t\treturn int64(v)\n")
+			buf.WriteString("\tcase int64:\n\t\treturn v\n")
+			buf.WriteString("\tcase float64:\n\t\tif v == float64(int64(v)) {\n\t\t\treturn int64(v)\n\t\t}\n")
+			buf.WriteString("\t}\n")
+			fmt.Fprintf(&buf, "\treturn %s\n", goNumber(f.Default))
+		case "float":
+			fmt.Fprintf(&buf, "func (s *%s) %s() float64 {\n", typeName, method)
+			fmt.Fprintf(&buf, "\tswitch v := s.values[%q].(type) {\n", f.Key)
+			buf.WriteString("\tcase float64:\n\t\treturn v\n")
+			buf.WriteString("\tcase int:\n\t\treturn float64(v)\n")
+			buf.WriteString("\tcase int64:\n\t\treturn float64(v)\n")
+			buf.WriteString("\t}\n")
+			fmt.Fprintf(&buf, "\treturn %s\n", goNumber(f.Default))
+		case "string":
+			fmt.Fprintf(&buf, "func (s *%s) %s() string {\n", typeName, method)
+			if len(f.Enum) > 0 {
+				fmt.Fprintf(&buf, "\tswitch v, _ := s.values[%q].(string); v {\n", f.Key)
+				var members []string
+				for _, m := range f.Enum {
+					members = append(members, strconv.Quote(m))
+				}
+				fmt.Fprintf(&buf, "\tcase %s:\n", strings.Join(members, ", "))
+				buf.WriteString("\t\treturn v\n")
+				buf.WriteString("\t}\n")
+			} else {
+				fmt.Fprintf(&buf, "\tif v, ok := s.values[%q].(string); ok {\n", f.Key)
+				buf.WriteString("\t\treturn v\n")
+				buf.WriteString("\t}\n")
+			}
+			fmt.Fprintf(&buf, "\treturn %s\n", strconv.Quote(fmt.Sprint(f.Default)))
+		default:
+			fmt.Fprintf(&buf, "func (s *%s) %s() interface{} {\n", typeName, method)
+			fmt.Fprintf(&buf, "\tif v, ok := s.values[%q]; ok {\n", f.Key)
+			buf.WriteString("\t\treturn v\n")
+			buf.WriteString("\t}\n")
+			buf.WriteString("\treturn nil\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes()
+}
+
+// goNumber renders a default number as a Go literal
+func goNumber(v interface{}) string {
+	switch n := v.(type) {
+	case int:
+		return strconv.Itoa(n)
+	case int64:
+		return strconv.FormatInt(n, 10)
+	case float64:
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+// jsonLiteral renders a value as a JSON (and TypeScript) literal
+func jsonLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
+
+// toGoName converts a field name to an exported Go name
+func toGoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// lowerFirst lower-cases the first letter of a name
+func lowerFirst(name string) string {
+	if len(name) == 0 {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// isIdentifier reports whether name is a valid JavaScript identifier
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -1,6 +1,9 @@
 package generator
 
 import (
+	"bytes"
+	"fmt"
+
 	"cuelang.org/go/cue"
 	"github.com/platoorg/plato-sl-cli/internal/config"
 )
@@ -17,6 +20,47 @@ type Generator interface {
 	Validate(ctx *Context) error
 }
 
+// File is one file of a multi-file generator's output
+type File struct {
+	// Path is relative to the output directory, with forward slashes
+	Path string
+
+	// Content is the file content
+	Content []byte
+}
+
+// MultiFileGenerator is implemented by generators that write a directory of
+// files instead of a single file, e.g. one file per type. Their output
+// option names the directory; Generate returns all files joined with
+// JoinFiles, for commands that compare output.
+type MultiFileGenerator interface {
+	Generator
+
+	// GenerateFiles generates the files to write under the output directory
+	GenerateFiles(ctx *Context) ([]File, error)
+}
+
+// FileSplitter is implemented by multi-file generators that write a single
+// file unless an option asks for a directory, e.g. jsonschema with split
+type FileSplitter interface {
+	// SplitFiles reports whether the context asks for a directory of files
+	SplitFiles(ctx *Context) bool
+}
+
+// JoinFiles concatenates files into one stream, each preceded by a header
+// line naming its path
+func JoinFiles(files []File) []byte {
+	var buf bytes.Buffer
+	for i, f := range files {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "==> %s <==\n", f.Path)
+		buf.Write(f.Content)
+	}
+	return buf.Bytes()
+}
+
 // Context holds the context for code generation
 type Context struct {
 	// Value is the CUE value to generate code from
@@ -0,0 +1,120 @@
+package generator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/absinthe"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/acl"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/arrow"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/bigquery"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/c"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/clojure"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/ecto"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/effect"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/encrypt"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/events"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/flags"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/gleam"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/haskell"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/html"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/joi"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/mongoose"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/pyspark"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/terraform"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/trpc"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/typebox"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/valibot"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/yup"
+)
+
+// TestGenerators renders one schema with every generator and checks the
+// output refers to the nested definition
+func TestGenerators(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Address: {city: string, zip?: string}
+#User: {
+	name:     string
+	email:    string @acl(read="admin") @encrypt("alias/pii")
+	age:      int & >=0
+	status:   "active" | "inactive"
+	address:  #Address
+	tags:     [...string]
+	homepage: string @jsonld(schema:url)
+} @acl(read="user")
+#Settings: {
+	@flags()
+	newCheckout: bool | *false
+}
+#CreateUser: {
+	@rpc(mutation, output=#User)
+	name: string
+}
+#UserCreated: {
+	@event(subject="users.created")
+	userId: string
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		generator string
+		want      string
+	}{
+		{"acl", `"email"`},
+		{"arrow", `pa.field("address", pa.struct([`},
+		{"bigquery", `"name": "address"`},
+		{"c", "address_t address;"},
+		{"clojure", "(s/def :platosl.types.user/address ::address)"},
+		{"csharp", "public required Address Address { get; init; }"},
+		{"dart", "final Address address;"},
+		{"effect", "address: Address,"},
+		{"elixir-absinthe", "field :address, non_null(:address)"},
+		{"elixir-ecto", "embeds_one :address, MyApp.Schemas.Address"},
+		{"encrypt", `"alias/pii"`},
+		{"events", `UserCreatedSubject = "users.created"`},
+		{"flags", "newCheckout: false,"},
+		{"gleam", `use address <- decode.field("address", address_decoder())`},
+		{"graphql", "address: Address!"},
+		{"haskell", "userAddress :: Address"},
+		{"html", `<a href="Address.html">#Address</a>`},
+		{"java", "Address address"},
+		{"joi", "address: AddressSchema.required(),"},
+		{"jsonld", `"schema:url"`},
+		{"mongoose", "address: { type: AddressSchema, required: true },"},
+		{"php", "public readonly Address $address,"},
+		{"protobuf", "Address address = "},
+		{"pyspark", `StructField("address", StructType(`},
+		{"sql", `"address" JSONB NOT NULL`},
+		{"terraform", "address = object({"},
+		{"trpc", "createUser: { type: 'mutation', input: CreateUserSchema, output: UserSchema },"},
+		{"typebox", "address: Address,"},
+		{"valibot", "address: AddressSchema,"},
+		{"yup", "address: AddressSchema.required(),"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			gen, err := generator.Get(tt.generator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			genCfg := config.GenConfig{Options: map[string]interface{}{
+				"lockFile": filepath.Join(t.TempDir(), "proto.lock"),
+			}}
+			out, err := gen.Generate(generator.NewContext(val, &config.Config{}, genCfg))
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("output lacks %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
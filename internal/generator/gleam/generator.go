@@ -0,0 +1,683 @@
+package gleam
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Gleam custom types and JSON decoders from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Gleam generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "gleam"
+}
+
+// keywords are Gleam's reserved words, which labels cannot take
+var keywords = map[string]bool{
+	"as": true, "assert": true, "auto": true, "case": true, "const": true,
+	"delegate": true, "derive": true, "echo": true, "else": true, "fn": true,
+	"if": true, "implement": true, "import": true, "let": true, "macro": true,
+	"opaque": true, "panic": true, "pub": true, "test": true, "todo": true,
+	"type": true, "use": true,
+}
+
+// modules are the imported modules, which labels would shadow in decoders
+var modules = map[string]bool{
+	"decode": true, "dict": true, "dynamic": true, "int": true, "json": true,
+	"option": true,
+}
+
+// reserved are the imported type names definitions cannot take
+var reserved = map[string]bool{
+	"Int": true, "Float": true, "String": true, "Bool": true, "List": true,
+	"Option": true, "Dict": true, "Dynamic": true, "Decoder": true, "Nil": true,
+	"Result": true, "Some": true, "None": true, "True": true, "False": true,
+	"Ok": true, "Error": true,
+}
+
+// record is a record type: a struct definition, or a nested struct of one,
+// which gets a type of its own
+type record struct {
+	name   string
+	doc    string
+	fields []field
+}
+
+// field is a labelled field of a record
+type field struct {
+	key     string // JSON key
+	label   string
+	doc     string
+	typ     string // Gleam type, e.g. Option(Int)
+	decoder string // decoder of the value, e.g. decode.int
+	def     string // default when the key is missing
+	present bool   // whether the key is required
+}
+
+// enum is a custom type of string literals
+type enum struct {
+	name     string
+	doc      string
+	values   []string
+	variants []string
+}
+
+// renderer collects the types of definitions. Definitions other than
+// structs and string enums, such as constrained strings, are inlined where
+// they are used.
+type renderer struct {
+	records   map[string]string // record types by CUE definition name
+	enums     map[string]string // enum types by CUE definition name
+	used      map[string]bool   // type and constructor names
+	types     []*record
+	enumTypes []*enum
+	byName    map[string]*enum
+
+	// reaches holds the struct definitions each struct definition refers
+	// to, directly or through others; decoders of references that lead
+	// back to the definition being decoded are built lazily
+	reaches map[string]map[string]bool
+	current string
+
+	imports map[string]bool
+}
+
+// Generate generates a custom type and a decoder per definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{
+		records: make(map[string]string),
+		enums:   make(map[string]string),
+		used:    make(map[string]bool),
+		byName:  make(map[string]*enum),
+		reaches: make(map[string]map[string]bool),
+		imports: make(map[string]bool),
+	}
+	for name := range reserved {
+		r.used[name] = true
+	}
+	for _, name := range cueNames {
+		switch val := defs[name]; {
+		case isRecord(val):
+			r.records[name] = generator.UniqueName(r.used, toPascalCase(name), "")
+		case len(stringEnum(val)) > 0:
+			r.enums[name] = generator.UniqueName(r.used, toPascalCase(name), "")
+		}
+	}
+	for _, name := range cueNames {
+		if _, ok := r.records[name]; ok {
+			r.reaches[name] = make(map[string]bool)
+			r.references(defs[name], r.reaches[name])
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, targets := range r.reaches {
+			for target := range targets {
+				for next := range r.reaches[target] {
+					if !targets[next] {
+						targets[next], changed = true, true
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range cueNames {
+		if typ, ok := r.enums[name]; ok {
+			r.enum(typ, platoCue.Description(defs[name]), stringEnum(defs[name]))
+		}
+	}
+	for _, name := range cueNames {
+		if typ, ok := r.records[name]; ok {
+			r.current = name
+			r.record(typ, platoCue.Description(defs[name]), defs[name])
+		}
+	}
+
+	var body bytes.Buffer
+	for _, e := range r.enumTypes {
+		body.WriteString("\n")
+		writeEnum(&body, e)
+	}
+	for _, rec := range r.types {
+		body.WriteString("\n")
+		writeRecord(&body, rec)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	if r.imports["dict"] {
+		buf.WriteString("import gleam/dict.{type Dict}\n")
+	}
+	if r.imports["dynamic"] {
+		buf.WriteString("import gleam/dynamic.{type Dynamic}\n")
+	}
+	buf.WriteString("import gleam/dynamic/decode\n")
+	if r.imports["int"] {
+		buf.WriteString("import gleam/int\n")
+	}
+	if len(r.types) > 0 {
+		buf.WriteString("import gleam/json\n")
+	}
+	if r.imports["option"] {
+		buf.WriteString("import gleam/option.{type Option}\n")
+	}
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// isRecord reports whether a definition is a struct with fields, which
+// becomes a record
+func isRecord(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// references adds the struct definitions a value refers to in its fields,
+// list items, map values and disjunctions
+func (r *renderer) references(val cue.Value, refs map[string]bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		if _, ok := r.records[path.String()]; ok {
+			refs[path.String()] = true
+			return
+		}
+	}
+	if op, args := val.Expr(); op == cue.OrOp || op == cue.AndOp {
+		for _, arg := range args {
+			r.references(arg, refs)
+		}
+		return
+	}
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			r.references(elem, refs)
+		}
+	case cue.StructKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			r.references(elem, refs)
+		}
+		iter, err := val.Fields(cue.Optional(true))
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			if !iter.Selector().IsDefinition() {
+				r.references(iter.Value(), refs)
+			}
+		}
+	}
+}
+
+// enum adds the custom type of string literals. Constructors share the
+// module's namespace, so one that is taken is prefixed with the type name.
+func (r *renderer) enum(name, doc string, values []string) *enum {
+	e := &enum{name: name, doc: doc, values: values}
+	for _, v := range values {
+		variant := toPascalCase(v)
+		if r.used[variant] {
+			variant = name + variant
+		}
+		e.variants = append(e.variants, generator.UniqueName(r.used, variant, ""))
+	}
+	r.enumTypes = append(r.enumTypes, e)
+	r.byName[name] = e
+	return e
+}
+
+// record adds the record of a struct, followed by the records of its
+// nested structs
+func (r *renderer) record(name, doc string, val cue.Value) {
+	rec := &record{name: name, doc: doc}
+	r.types = append(r.types, rec)
+
+	labels := make(map[string]bool)
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		key := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		f := field{key: key, label: generator.UniqueName(labels, toLabel(key), ""), doc: platoCue.Description(fieldVal)}
+		typ, decoder, nullable := r.valueType(name, key, fieldVal)
+		switch {
+		case iter.IsOptional() || nullable:
+			r.imports["option"] = true
+			f.typ, f.decoder = "Option("+typ+")", "decode.optional("+decoder+")"
+			f.def = "option.None"
+		default:
+			f.typ, f.decoder, f.present = typ, decoder, true
+			if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+				if lit, ok := r.gleamValue(def, typ); ok {
+					f.def, f.present = lit, false
+				}
+			}
+		}
+		rec.fields = append(rec.fields, f)
+	}
+}
+
+// valueType maps a value to its Gleam type and decoder, reporting whether
+// it may be null
+func (r *renderer) valueType(parent, key string, val cue.Value) (string, string, bool) {
+	if typ, decoder, ok := r.reference(val); ok {
+		return typ, decoder, false
+	}
+	if values := stringEnum(val); len(values) > 0 {
+		if typ, ok := r.enumReference(val, values); ok {
+			return typ, decoderName(typ) + "()", false
+		}
+		e := r.enum(generator.UniqueName(r.used, parent+toPascalCase(key), ""), "", values)
+		return e.name, decoderName(e.name) + "()", false
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		r.imports["dynamic"] = true
+		return "Dynamic", "decode.dynamic", nullable
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if typ, decoder, ok := r.reference(val); ok {
+		return typ, decoder, nullable
+	}
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		// Definitions other than structs and enums are inlined
+		typ, decoder, null := r.valueType(parent, key, cue.Dereference(val))
+		return typ, decoder, nullable || null
+	}
+
+	typ, decoder := r.base(parent, key, val)
+	return typ, decoder, nullable
+}
+
+// reference returns the type and decoder of a reference to a struct or
+// enum definition. Decoders of records that lead back to the definition
+// being decoded are wrapped in decode.recursive, as building them eagerly
+// would not terminate.
+func (r *renderer) reference(val cue.Value) (string, string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", "", false
+	}
+	name := path.String()
+	if typ, ok := r.records[name]; ok {
+		if name == r.current || r.reaches[name][r.current] {
+			return typ, "decode.recursive(" + decoderName(typ) + ")", true
+		}
+		return typ, decoderName(typ) + "()", true
+	}
+	if typ, ok := r.enums[name]; ok {
+		return typ, decoderName(typ) + "()", true
+	}
+	return "", "", false
+}
+
+// enumReference returns the enum of a disjunction that refers to an enum
+// definition holding all its values, e.g. #Role | *"member"
+func (r *renderer) enumReference(val cue.Value, values []string) (string, bool) {
+	_, args := val.Expr()
+	for _, arg := range args {
+		_, path := arg.ReferencePath()
+		typ, ok := r.enums[path.String()]
+		if len(path.Selectors()) == 0 || !ok {
+			continue
+		}
+		members := stringEnum(cue.Dereference(arg))
+		all := true
+		for _, v := range values {
+			if !slices.Contains(members, v) {
+				all = false
+			}
+		}
+		if all {
+			return typ, true
+		}
+	}
+	return "", false
+}
+
+// base maps a value by its kind
+func (r *renderer) base(parent, key string, val cue.Value) (string, string) {
+	val, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		return "String", "decode.string"
+	case kind == cue.IntKind:
+		return "Int", "decode.int"
+	case kind == cue.FloatKind:
+		return "Float", "decode.float"
+	case kind == cue.NumberKind:
+		// JSON numbers without a fraction decode as Int on Erlang
+		r.imports["int"] = true
+		return "Float", "decode.one_of(decode.float, [decode.int |> decode.map(int.to_float)])"
+	case kind == cue.BoolKind:
+		return "Bool", "decode.bool"
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			r.imports["dynamic"] = true
+			return "List(Dynamic)", "decode.list(decode.dynamic)"
+		}
+		typ, decoder, nullable := r.valueType(parent, key, elem)
+		if nullable {
+			r.imports["option"] = true
+			typ, decoder = "Option("+typ+")", "decode.optional("+decoder+")"
+		}
+		return "List(" + typ + ")", "decode.list(" + decoder + ")"
+	case kind == cue.StructKind && platoCue.IsMap(val):
+		r.imports["dict"] = true
+		typ, decoder, nullable := r.valueType(parent, key, val.LookupPath(cue.MakePath(cue.AnyString)))
+		if nullable {
+			r.imports["option"] = true
+			typ, decoder = "Option("+typ+")", "decode.optional("+decoder+")"
+		}
+		return "Dict(String, " + typ + ")", "decode.dict(decode.string, " + decoder + ")"
+	case kind == cue.StructKind && platoCue.HasFields(val):
+		name := generator.UniqueName(r.used, parent+toPascalCase(key), "")
+		r.record(name, platoCue.Description(val), val)
+		return name, decoderName(name) + "()"
+	}
+	r.imports["dynamic"] = true
+	return "Dynamic", "decode.dynamic"
+}
+
+// writeEnum writes a custom type of string literals and its decoder
+func writeEnum(buf *bytes.Buffer, e *enum) {
+	writeDoc(buf, e.doc, "")
+	fmt.Fprintf(buf, "pub type %s {\n", e.name)
+	for _, v := range e.variants {
+		fmt.Fprintf(buf, "  %s\n", v)
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(buf, "pub fn %s() -> decode.Decoder(%s) {\n", decoderName(e.name), e.name)
+	buf.WriteString("  use value <- decode.then(decode.string)\n")
+	buf.WriteString("  case value {\n")
+	for i, v := range e.values {
+		fmt.Fprintf(buf, "    %s -> decode.success(%s)\n", gleamString(v), e.variants[i])
+	}
+	fmt.Fprintf(buf, "    _ -> decode.failure(%s, %s)\n", e.variants[0], gleamString(e.name))
+	buf.WriteString("  }\n}\n")
+}
+
+// writeRecord writes a record type, its decoder and a function decoding it
+// from a JSON string
+func writeRecord(buf *bytes.Buffer, rec *record) {
+	writeDoc(buf, rec.doc, "")
+	fmt.Fprintf(buf, "pub type %s {\n  %s(\n", rec.name, rec.name)
+	for _, f := range rec.fields {
+		writeDoc(buf, f.doc, "    ")
+		fmt.Fprintf(buf, "    %s: %s,\n", f.label, f.typ)
+	}
+	buf.WriteString("  )\n}\n\n")
+
+	decoder := decoderName(rec.name)
+	fmt.Fprintf(buf, "pub fn %s() -> decode.Decoder(%s) {\n", decoder, rec.name)
+	args := make([]string, len(rec.fields))
+	for i, f := range rec.fields {
+		if f.present {
+			fmt.Fprintf(buf, "  use %s <- decode.field(%s, %s)\n", f.label, gleamString(f.key), f.decoder)
+		} else {
+			fmt.Fprintf(buf, "  use %s <- decode.optional_field(%s, %s, %s)\n", f.label, gleamString(f.key), f.def, f.decoder)
+		}
+		args[i] = f.label + ": " + f.label
+	}
+	fmt.Fprintf(buf, "  decode.success(%s(%s))\n}\n\n", rec.name, strings.Join(args, ", "))
+
+	fn := toSnakeCase(rec.name) + "_from_json"
+	fmt.Fprintf(buf, "pub fn %s(json_string: String) -> Result(%s, json.DecodeError) {\n", fn, rec.name)
+	fmt.Fprintf(buf, "  json.parse(json_string, %s())\n}\n", decoder)
+}
+
+// writeDoc writes a doc comment; fields get a plain comment, as Gleam has
+// no doc comments for them
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	prefix := "///"
+	if indent != "" {
+		prefix = "//"
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		buf.WriteString(strings.TrimRight(indent+prefix+" "+line, " ") + "\n")
+	}
+}
+
+// gleamValue renders a JSON default as a Gleam constant of a type: a
+// scalar, or a variant of an enum. Other defaults are not rendered.
+func (r *renderer) gleamValue(data, typ string) (string, bool) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case bool:
+		if typ == "Bool" {
+			return map[bool]string{true: "True", false: "False"}[v], true
+		}
+	case json.Number:
+		switch typ {
+		case "Int":
+			return v.String(), true
+		case "Float":
+			s := v.String()
+			if !strings.ContainsAny(s, ".eE") {
+				s += ".0"
+			}
+			return s, true
+		}
+	case string:
+		if typ == "String" {
+			return gleamString(v), true
+		}
+		if e, ok := r.byName[typ]; ok {
+			if i := slices.Index(e.values, v); i >= 0 {
+				return e.variants[i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// gleamString renders a string literal
+func gleamString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// stringEnum returns the distinct strings of a disjunction of string
+// literals, following references to enum definitions
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if _, path := arg.ReferencePath(); len(path.Selectors()) > 0 {
+			arg = cue.Dereference(arg)
+		}
+		var argValues []string
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if argValues = stringEnum(arg); argValues == nil {
+				return nil
+			}
+		} else {
+			if !arg.IsConcrete() || arg.Kind() != cue.StringKind {
+				return nil
+			}
+			s, _ := arg.String()
+			argValues = []string{s}
+		}
+		for _, v := range argValues {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// decoderName returns the name of the decoder function of a type, e.g.
+// order_item_decoder
+func decoderName(typ string) string {
+	return toSnakeCase(typ) + "_decoder"
+}
+
+// toLabel converts a JSON key to a record label, e.g. firstName to
+// first_name; keywords and module names get a trailing underscore
+func toLabel(key string) string {
+	label := toSnakeCase(key)
+	if keywords[label] || modules[label] {
+		label += "_"
+	}
+	return label
+}
+
+// toPascalCase converts a definition name or enum value to a type or
+// constructor name, e.g. #order_item to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+// toSnakeCase converts a name to a label or function name, e.g. OrderItem
+// to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "f" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
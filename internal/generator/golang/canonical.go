@@ -0,0 +1,153 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// canonicalPackages are the imports the canonical JSON helpers need
+var canonicalPackages = []string{"bytes", "encoding/json", "fmt", "math", "sort", "strconv", "strings", "unicode/utf16"}
+
+// canonicalHelpers encode values as RFC 8785 canonical JSON. The output
+// matches the TypeScript generator's canonicalize, so payloads hash and
+// sign the same on both sides.
+const canonicalHelpers = `// canonicalJSON encodes v as canonical JSON (RFC 8785): object keys sorted
+// by UTF-16 code units, numbers in their shortest ECMAScript form and no
+// insignificant whitespace, so equal values encode to the same bytes in
+// every language. Integers beyond 2^53 lose precision, as in JavaScript.
+func canonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("canonical JSON: %w", err)
+		}
+		buf.WriteString(canonicalNumber(f))
+	case string:
+		writeCanonicalString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("canonical JSON: unexpected %T", value)
+	}
+	return nil
+}
+
+// canonicalNumber formats a number like ECMAScript's Number.prototype.toString
+func canonicalNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	return mantissa + "e" + exp[:1] + strings.TrimLeft(exp[1:], "0")
+}
+
+// writeCanonicalString escapes only quotes, backslashes and control characters
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString("\\\"")
+		case '\\':
+			buf.WriteString("\\\\")
+		case '\b':
+			buf.WriteString("\\b")
+		case '\f':
+			buf.WriteString("\\f")
+		case '\n':
+			buf.WriteString("\\n")
+		case '\r':
+			buf.WriteString("\\r")
+		case '\t':
+			buf.WriteString("\\t")
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, "\\u%04x", r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 orders strings by their UTF-16 code units
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+`
+
+// writeCanonicalHelpers renders the shared helpers and a CanonicalJSON
+// method per type
+func writeCanonicalHelpers(buf *bytes.Buffer, goNames []string) {
+	buf.WriteString(canonicalHelpers)
+
+	for _, name := range goNames {
+		fmt.Fprintf(buf, "\n// CanonicalJSON encodes the %s as canonical JSON for hashing and signing.\n", name)
+		fmt.Fprintf(buf, "func (v %s) CanonicalJSON() ([]byte, error) {\n", name)
+		buf.WriteString("\treturn canonicalJSON(v)\n")
+		buf.WriteString("}\n")
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
 )
 
@@ -33,6 +37,16 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	fmt.Fprintf(&buf, "// DO NOT EDIT - This file is auto-generated\n\n")
 	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
 
+	// Binary codecs get struct tags next to the JSON tag
+	codecs, err := ctx.CodecsOption()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ctx.UnicodeOption()
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract definitions
 	defs, err := extractDefinitions(ctx.Value)
 	if err != nil {
@@ -41,18 +55,57 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 
 	// Sort definitions for consistent output
 	var defNames []string
+	var listNames []string
 	for name := range defs {
 		defNames = append(defNames, name)
+		if defs[name].IncompleteKind() == cue.ListKind {
+			listNames = append(listNames, name)
+		}
 	}
 	sort.Strings(defNames)
+	sort.Strings(listNames)
+
+	// Streaming readers for list-typed definitions, canonical JSON helpers,
+	// lenient decoders and Validate methods bring their own imports
+	streaming := ctx.GetBoolOption("streaming", false) && len(listNames) > 0
+	canonical := ctx.GetBoolOption("canonical", false) && len(defNames) > 0
+	lenient := ctx.GetBoolOption("lenient", false) && len(defNames) > 0
+	var validators *validatorBuilder
+	if ctx.GetBoolOption("validate", false) && len(defNames) > 0 {
+		validators, err = newValidatorBuilder(defs, defNames, policy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Validate methods: %w", err)
+		}
+	}
+	var imports []string
+	if streaming {
+		imports = append(imports, streamPackages...)
+	}
+	if canonical {
+		imports = append(imports, canonicalPackages...)
+	}
+	if lenient {
+		imports = append(imports, lenientPackages...)
+	}
+	if validators != nil {
+		imports = append(imports, validators.imports()...)
+	}
+	writeImports(&buf, imports)
 
 	// Generate structs
 	for _, name := range defNames {
 		val := defs[name]
-		goName := toGoName(name)
+		goName := toGoName(name, policy)
+		writeDoc(&buf, platoCue.Description(val), "")
+
+		// List-typed definitions are slices of their element type
+		if val.IncompleteKind() == cue.ListKind {
+			fmt.Fprintf(&buf, "type %s %s\n\n", goName, mapToGoType(val, policy))
+			continue
+		}
 
 		// Generate struct
-		structCode, err := generateStruct(goName, val)
+		structCode, err := generateStruct(goName, val, codecs, policy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate struct for %s: %w", name, err)
 		}
@@ -60,15 +113,73 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 		buf.WriteString("\n")
 	}
 
+	goNames := make([]string, len(defNames))
+	for i, name := range defNames {
+		goNames[i] = toGoName(name, policy)
+	}
+
+	// Helper sections are separated by a blank line
+	sections := 0
+	if streaming {
+		writeStreamReaders(&buf, defs, listNames, policy)
+		sections++
+	}
+	if canonical {
+		if sections > 0 {
+			buf.WriteString("\n")
+		}
+		writeCanonicalHelpers(&buf, goNames)
+		sections++
+	}
+	if lenient {
+		if sections > 0 {
+			buf.WriteString("\n")
+		}
+		writeLenientDecoders(&buf, goNames)
+		sections++
+	}
+	if validators != nil {
+		if sections > 0 {
+			buf.WriteString("\n")
+		}
+		validators.write(&buf)
+	}
+
 	return buf.Bytes(), nil
 }
 
+// writeImports renders a sorted import block without duplicates
+func writeImports(buf *bytes.Buffer, packages []string) {
+	if len(packages) == 0 {
+		return
+	}
+	seen := make(map[string]bool)
+	var unique []string
+	for _, pkg := range packages {
+		if !seen[pkg] {
+			seen[pkg] = true
+			unique = append(unique, pkg)
+		}
+	}
+	sort.Strings(unique)
+
+	buf.WriteString("import (\n")
+	for _, pkg := range unique {
+		fmt.Fprintf(buf, "\t%q\n", pkg)
+	}
+	buf.WriteString(")\n\n")
+}
+
 // Validate validates the generator context
 func (g *Generator) Validate(ctx *generator.Context) error {
 	if err := ctx.Value.Err(); err != nil {
 		return fmt.Errorf("invalid CUE value: %w", err)
 	}
-	return nil
+	if _, err := ctx.CodecsOption(); err != nil {
+		return err
+	}
+	_, err := ctx.UnicodeOption()
+	return err
 }
 
 // extractDefinitions extracts all definitions from a CUE value
@@ -90,9 +201,14 @@ func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
 	return defs, nil
 }
 
-// generateStruct generates a Go struct
-func generateStruct(name string, val cue.Value) (string, error) {
+// generateStruct generates a Go struct; codecs adds a tag per binary codec
+// with the same name and omitempty as the JSON tag. Tags keep the field
+// names as they are, escaped under the escape unicode policy. Fields with a
+// default get a default tag, and the struct a New<Name> constructor setting
+// the defaults of its required fields, to decode JSON into.
+func generateStruct(name string, val cue.Value, codecs []string, policy string) (string, error) {
 	var buf bytes.Buffer
+	var defaults []string
 
 	fmt.Fprintf(&buf, "type %s struct {\n", name)
 
@@ -103,62 +219,134 @@ func generateStruct(name string, val cue.Value) (string, error) {
 	}
 
 	for iter.Next() {
-		label := iter.Selector().String()
-		fieldVal := iter.Value()
-		optional := iter.IsOptional()
-
 		// Skip definitions
-		if strings.HasPrefix(label, "#") {
+		if iter.Selector().IsDefinition() {
 			continue
 		}
 
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+		optional := iter.IsOptional()
+
 		// Map type
-		goType := mapToGoType(fieldVal)
+		goType := mapToGoType(fieldVal, policy)
 
 		// Optional fields are pointers
-		if optional {
+		if optional && !strings.HasPrefix(goType, "*") {
 			goType = "*" + goType
 		}
 
 		// Generate field with JSON tag
-		fieldName := toGoFieldName(label)
+		fieldName := toGoFieldName(label, policy)
 		jsonTag := label
 		if optional {
 			jsonTag += ",omitempty"
 		}
+		jsonTag = quoteName(jsonTag, policy)
+		writeDoc(&buf, platoCue.Description(fieldVal), "\t")
 
-		fmt.Fprintf(&buf, "\t%s %s `json:\"%s\"`\n", fieldName, goType, jsonTag)
+		// Unit / currency annotation
+		comment := ""
+		if measure := platoCue.MeasureOf(fieldVal); !measure.IsZero() {
+			comment = " // " + measure.String()
+		}
+
+		tags := "json:" + jsonTag
+		for _, codec := range codecs {
+			tags += " " + codec + ":" + jsonTag
+		}
+		if def, ok := fieldVal.Default(); ok {
+			if text, lit, ok := defaultValue(fieldVal, def, policy); ok {
+				tags += " default:" + quoteName(text, policy)
+				if !optional {
+					defaults = append(defaults, fmt.Sprintf("\t\t%s: %s,\n", fieldName, lit))
+				}
+			}
+		}
+
+		fmt.Fprintf(&buf, "\t%s %s `%s`%s\n", fieldName, goType, tags, comment)
 	}
 
 	buf.WriteString("}\n")
 
+	if len(defaults) > 0 {
+		fmt.Fprintf(&buf, "\n// New%s returns a %s with the defaults of its fields\n", name, name)
+		fmt.Fprintf(&buf, "func New%s() %s {\n", name, name)
+		fmt.Fprintf(&buf, "\treturn %s{\n", name)
+		buf.WriteString(strings.Join(defaults, ""))
+		buf.WriteString("\t}\n}\n")
+	}
+
 	return buf.String(), nil
 }
 
-// mapToGoType maps a CUE type to Go
-func mapToGoType(val cue.Value) string {
+// writeDoc writes a description as // comment lines
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		buf.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
+
+// defaultValue renders the default of a string, number or boolean field,
+// e.g. member for *"member" | "admin", as tag text and as a Go literal.
+// Open lists, which default to [], have none; nor do strings with
+// backquotes, which struct tags cannot hold.
+func defaultValue(val, def cue.Value, policy string) (string, string, bool) {
+	if val.IsConcrete() || !def.IsConcrete() {
+		return "", "", false
+	}
+	switch def.Kind() {
+	case cue.StringKind:
+		s, err := def.String()
+		if err != nil || strings.Contains(s, "`") {
+			return "", "", false
+		}
+		return s, quoteName(s, policy), true
+	case cue.IntKind, cue.FloatKind, cue.BoolKind:
+		data, err := def.MarshalJSON()
+		if err != nil {
+			return "", "", false
+		}
+		return string(data), string(data), true
+	}
+	return "", "", false
+}
+
+// mapToGoType maps a CUE type to Go. References to definitions are their
+// types, pointers when they may be null (next: #Node | null), which is how
+// recursive types end: Go types cannot contain themselves other than
+// through pointers, slices and maps.
+func mapToGoType(val cue.Value, policy string) string {
+	if ref := getDefinitionReference(val); ref != "" {
+		return toGoName(ref, policy)
+	}
+	if op, args := val.Expr(); op == cue.OrOp && len(args) == 2 {
+		for i, arg := range args {
+			if ref := getDefinitionReference(args[1-i]); arg.IncompleteKind() == cue.NullKind && ref != "" {
+				return "*" + toGoName(ref, policy)
+			}
+		}
+	}
 	kind := val.IncompleteKind()
 
 	switch {
 	case kind&cue.StringKind != 0:
 		return "string"
-	case kind&cue.IntKind != 0:
+	case kind&cue.NumberKind == cue.IntKind:
 		return "int"
-	case kind&cue.FloatKind != 0:
-		return "float64"
 	case kind&cue.NumberKind != 0:
+		// number and float, and int | float
 		return "float64"
 	case kind&cue.BoolKind != 0:
 		return "bool"
 	case kind&cue.ListKind != 0:
 		// Try to get element type
-		elemType := getListElementType(val)
+		elemType := getListElementType(val, policy)
 		return "[]" + elemType
 	case kind&cue.StructKind != 0:
-		// Check if it references a definition
-		if ref := getDefinitionReference(val); ref != "" {
-			return toGoName(ref)
-		}
 		return "interface{}"
 	default:
 		return "interface{}"
@@ -166,47 +354,66 @@ func mapToGoType(val cue.Value) string {
 }
 
 // getListElementType gets the element type of a list
-func getListElementType(val cue.Value) string {
+func getListElementType(val cue.Value, policy string) string {
 	// Try to get the first element or list constraint
 	iter, err := val.List()
 	if err == nil && iter.Next() {
-		return mapToGoType(iter.Value())
+		return mapToGoType(iter.Value(), policy)
+	}
+	if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+		return mapToGoType(elem, policy)
 	}
 	return "interface{}"
 }
 
 // getDefinitionReference checks if a value references a definition
 func getDefinitionReference(val cue.Value) string {
-	// This is a simplified implementation
-	return ""
+	return platoCue.DefinitionRef(val)
 }
 
 // toGoName converts a CUE definition name to Go type name
-func toGoName(name string) string {
+func toGoName(name, policy string) string {
 	// Remove leading # and ensure PascalCase
 	name = strings.TrimPrefix(name, "#")
+	return exported(generator.UnicodeName(policy, name, generator.EscapeRune))
+}
 
-	// Basic conversion to PascalCase
-	if len(name) > 0 {
-		name = strings.ToUpper(name[:1]) + name[1:]
+// toGoFieldName converts a field name to Go field name, joining the words
+// between underscores, dashes, spaces and other punctuation in PascalCase
+func toGoFieldName(name, policy string) string {
+	name = generator.UnicodeName(policy, name, generator.EscapeRune)
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		r, size := utf8.DecodeRuneInString(part)
+		parts[i] = string(unicode.ToUpper(r)) + part[size:]
 	}
+	return exported(strings.Join(parts, ""))
+}
 
-	return name
+// exported upper-cases the first letter of a name. Names starting with a
+// digit or a letter without case, such as CJK, get an X prefix, since Go
+// only exports names starting with an upper case letter.
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if upper := unicode.ToUpper(r); unicode.IsUpper(upper) {
+		return string(upper) + name[size:]
+	}
+	return "X" + name
 }
 
-// toGoFieldName converts a field name to Go field name
-func toGoFieldName(name string) string {
-	// Convert to PascalCase for exported fields
-	parts := strings.Split(name, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + part[1:]
-		}
+// quoteName quotes a field name as a Go string, escaping non-ASCII runes
+// under the escape unicode policy
+func quoteName(value, policy string) string {
+	if policy == generator.UnicodeEscape {
+		return strconv.QuoteToASCII(value)
 	}
-	return strings.Join(parts, "")
+	return strconv.Quote(value)
 }
 
 func init() {
 	// Register the generator
 	generator.Register(NewGenerator())
+	generator.RegisterAlias("golang", "go")
 }
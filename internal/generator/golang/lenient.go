@@ -0,0 +1,223 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// lenientPackages are the imports the lenient decoders need
+var lenientPackages = []string{"bytes", "encoding/json", "fmt", "reflect", "regexp", "sort", "strconv", "strings"}
+
+// lenientHelpers coerce loosely formed JSON to the shape of the generated
+// types before decoding it. The rules match the TypeScript generator's
+// lenient decoders.
+const lenientHelpers = `// lenientNumber matches numbers given as strings
+var lenientNumber = regexp.MustCompile(` + "`" + `^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$` + "`" + `)
+
+// decodeLenient decodes JSON into out after coercing common variations:
+// numbers and booleans given as strings, numbers and booleans where a string
+// is expected, and single values where a list is expected. Null, empty and
+// missing values are left at their zero value. Fields the type does not
+// declare are removed and returned as extras, keyed by their path (e.g.
+// "author.nickname" or "items[2].note").
+func decodeLenient(data []byte, out any) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	extras := make(map[string]any)
+	value, err := coerceLenient(raw, reflect.TypeOf(out).Elem(), "", extras)
+	if err != nil {
+		return nil, err
+	}
+	coerced, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(coerced, out); err != nil {
+		return nil, err
+	}
+	return extras, nil
+}
+
+func coerceLenient(value any, t reflect.Type, path string, extras map[string]any) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil || t.Kind() == reflect.Interface {
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case json.Number:
+			return string(v), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	case reflect.Bool:
+		s := ""
+		switch v := value.(type) {
+		case string:
+			s = strings.ToLower(strings.TrimSpace(v))
+		case json.Number:
+			s = string(v)
+		default:
+			return value, nil
+		}
+		switch s {
+		case "":
+			return nil, nil
+		case "true", "t", "1":
+			return true, nil
+		case "false", "f", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s: cannot use %q as a boolean", lenientPath(path), s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, ok := lenientNumberText(value)
+		if !ok {
+			return value, nil
+		}
+		if s == "" {
+			return nil, nil
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(n, 10)), nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || !lenientNumber.MatchString(s) || f != float64(int64(f)) {
+			return nil, fmt.Errorf("%s: cannot use %q as an integer", lenientPath(path), s)
+		}
+		return json.Number(strconv.FormatInt(int64(f), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		s, ok := lenientNumberText(value)
+		if !ok {
+			return value, nil
+		}
+		if s == "" {
+			return nil, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || !lenientNumber.MatchString(s) {
+			return nil, fmt.Errorf("%s: cannot use %q as a number", lenientPath(path), s)
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		for i, item := range items {
+			coerced, err := coerceLenient(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), extras)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = coerced
+		}
+		return items, nil
+	case reflect.Map:
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object", lenientPath(path))
+		}
+		for _, key := range lenientKeys(m) {
+			coerced, err := coerceLenient(m[key], t.Elem(), lenientJoin(path, key), extras)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = coerced
+		}
+		return m, nil
+	case reflect.Struct:
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object", lenientPath(path))
+		}
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = field.Type
+		}
+		for _, key := range lenientKeys(m) {
+			fieldType, ok := fields[key]
+			if !ok {
+				extras[lenientJoin(path, key)] = m[key]
+				delete(m, key)
+				continue
+			}
+			coerced, err := coerceLenient(m[key], fieldType, lenientJoin(path, key), extras)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = coerced
+		}
+		return m, nil
+	}
+	return value, nil
+}
+
+// lenientNumberText returns the text of a number or numeric string
+func lenientNumberText(value any) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return string(v), true
+	case string:
+		return strings.TrimSpace(v), true
+	}
+	return "", false
+}
+
+// lenientKeys returns the keys of an object in sorted order, so errors are
+// reported for the same field on every run
+func lenientKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lenientJoin(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func lenientPath(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
+`
+
+// writeLenientDecoders renders the shared helpers and a lenient decode
+// function per type
+func writeLenientDecoders(buf *bytes.Buffer, goNames []string) {
+	buf.WriteString(lenientHelpers)
+
+	for _, name := range goNames {
+		fmt.Fprintf(buf, "\n// Decode%sLenient decodes a %s from loosely formed JSON, returning the\n", name, name)
+		buf.WriteString("// fields it does not declare as extras. See decodeLenient for the rules.\n")
+		fmt.Fprintf(buf, "func Decode%sLenient(data []byte) (%s, map[string]any, error) {\n", name, name)
+		fmt.Fprintf(buf, "\tvar v %s\n", name)
+		buf.WriteString("\textras, err := decodeLenient(data, &v)\n")
+		buf.WriteString("\treturn v, extras, err\n")
+		buf.WriteString("}\n")
+	}
+}
@@ -0,0 +1,116 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+
+	"cuelang.org/go/cue"
+)
+
+// streamPackages are the imports the streaming readers need
+var streamPackages = []string{"bufio", "encoding/json", "fmt", "io"}
+
+// streamReader is the generic reader shared by all list-typed definitions
+const streamReader = `// StreamReader reads records one at a time from a JSON array or from
+// newline-delimited JSON (NDJSON), without loading the whole input.
+type StreamReader[T any] struct {
+	// Validate, when set, is called with each decoded record and its
+	// zero-based index; returning an error stops reading.
+	Validate func(index int, record *T) error
+
+	br    *bufio.Reader
+	dec   *json.Decoder
+	array bool
+	index int
+	err   error
+}
+
+// Next returns the next record, or io.EOF after the last one. Decoding and
+// validation errors name the record index and end the stream.
+func (r *StreamReader[T]) Next() (*T, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	record, err := r.next()
+	if err != nil {
+		r.err = err
+	}
+	return record, err
+}
+
+func (r *StreamReader[T]) next() (*T, error) {
+	if r.dec == nil {
+		if err := r.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.array && !r.dec.More() {
+		if _, err := r.dec.Token(); err != nil {
+			return nil, fmt.Errorf("record %d: %w", r.index, unexpectedEOF(err))
+		}
+		return nil, io.EOF
+	}
+
+	var record T
+	if err := r.dec.Decode(&record); err != nil {
+		if err == io.EOF && !r.array {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("record %d: %w", r.index, unexpectedEOF(err))
+	}
+
+	index := r.index
+	r.index++
+	if r.Validate != nil {
+		if err := r.Validate(index, &record); err != nil {
+			return nil, fmt.Errorf("record %d: %w", index, err)
+		}
+	}
+	return &record, nil
+}
+
+// start detects whether the input is a JSON array or NDJSON
+func (r *StreamReader[T]) start() error {
+	for {
+		c, err := r.br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		r.br.UnreadByte()
+		r.dec = json.NewDecoder(r.br)
+		if c == '[' {
+			r.array = true
+			_, err := r.dec.Token()
+			return unexpectedEOF(err)
+		}
+		return nil
+	}
+}
+
+// unexpectedEOF reports input that ends inside a JSON array
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+`
+
+// writeStreamReaders renders the generic stream reader and one constructor
+// per list-typed definition
+func writeStreamReaders(buf *bytes.Buffer, defs map[string]cue.Value, listNames []string, policy string) {
+	buf.WriteString(streamReader)
+
+	for _, name := range listNames {
+		goName := toGoName(name, policy)
+		elemType := getListElementType(defs[name], policy)
+		fmt.Fprintf(buf, "\n// New%sReader reads %s records from a JSON array or NDJSON stream.\n", goName, elemType)
+		fmt.Fprintf(buf, "func New%sReader(r io.Reader) *StreamReader[%s] {\n", goName, elemType)
+		fmt.Fprintf(buf, "\treturn &StreamReader[%s]{br: bufio.NewReader(r)}\n", elemType)
+		buf.WriteString("}\n")
+	}
+}
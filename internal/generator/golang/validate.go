@@ -0,0 +1,281 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// validationErrorType is the error every generated Validate method reports
+const validationErrorType = `// ValidationError reports a field that breaks a schema constraint
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+`
+
+// validatorBuilder renders a Validate method per definition from the
+// bounds, patterns and length limits of its fields, using the @errmsg
+// messages where they are set
+type validatorBuilder struct {
+	defs     map[string]cue.Value
+	policy   string
+	packages map[string]bool
+	patterns []patternVar
+	methods  bytes.Buffer
+}
+
+// patternVar is a compiled pattern at package level
+type patternVar struct {
+	name    string
+	pattern string
+}
+
+func newValidatorBuilder(defs map[string]cue.Value, defNames []string, policy string) (*validatorBuilder, error) {
+	b := &validatorBuilder{defs: defs, policy: policy, packages: map[string]bool{"errors": true}}
+	for _, name := range defNames {
+		if err := b.writeMethod(toGoName(name, policy), defs[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if len(b.patterns) > 0 {
+		b.packages["regexp"] = true
+	}
+	return b, nil
+}
+
+// imports returns the packages the Validate methods use
+func (b *validatorBuilder) imports() []string {
+	var packages []string
+	for pkg := range b.packages {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// write renders ValidationError, the compiled patterns and the methods
+func (b *validatorBuilder) write(buf *bytes.Buffer) {
+	buf.WriteString(validationErrorType)
+
+	if len(b.patterns) > 0 {
+		width := 0
+		for _, p := range b.patterns {
+			width = max(width, len(p.name))
+		}
+		buf.WriteString("\nvar (\n")
+		for _, p := range b.patterns {
+			fmt.Fprintf(buf, "\t%-*s = regexp.MustCompile(%s)\n", width, p.name, goRawString(p.pattern))
+		}
+		buf.WriteString(")\n")
+	}
+
+	buf.Write(b.methods.Bytes())
+}
+
+// writeMethod renders the Validate method of a struct or list definition
+func (b *validatorBuilder) writeMethod(goName string, val cue.Value) error {
+	b.methods.WriteString("\n// Validate reports the fields of v, and of the definitions it holds, that\n")
+	b.methods.WriteString("// break a bound, pattern or length limit of the schema\n")
+	fmt.Fprintf(&b.methods, "func (v %s) Validate() error {\n", goName)
+	b.methods.WriteString("\tvar errs []error\n")
+
+	if val.IncompleteKind() == cue.ListKind {
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() && b.isDefinition(elem) {
+			b.methods.WriteString("\tfor _, item := range v {\n")
+			b.methods.WriteString("\t\tif err := item.Validate(); err != nil {\n")
+			b.methods.WriteString("\t\t\terrs = append(errs, err)\n")
+			b.methods.WriteString("\t\t}\n")
+			b.methods.WriteString("\t}\n")
+		}
+		b.methods.WriteString("\treturn errors.Join(errs...)\n")
+		b.methods.WriteString("}\n")
+		return nil
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		checks := b.fieldChecks(goName, label, iter.Value(), iter.IsOptional())
+		if checks == "" {
+			continue
+		}
+		if iter.IsOptional() {
+			fmt.Fprintf(&b.methods, "\tif v.%s != nil {\n", toGoFieldName(label, b.policy))
+			b.methods.WriteString(indentLines(checks, "\t"))
+			b.methods.WriteString("\t}\n")
+		} else {
+			b.methods.WriteString(checks)
+		}
+	}
+
+	b.methods.WriteString("\treturn errors.Join(errs...)\n")
+	b.methods.WriteString("}\n")
+	return nil
+}
+
+// fieldChecks renders the checks of one field
+func (b *validatorBuilder) fieldChecks(goName, label string, val cue.Value, optional bool) string {
+	var buf bytes.Buffer
+	goType := mapToGoType(val, b.policy)
+	expr := "v." + toGoFieldName(label, b.policy)
+	value := expr
+	if optional {
+		value = "*" + expr
+	}
+
+	if b.isDefinition(val) {
+		fmt.Fprintf(&buf, "\tif err := %s.Validate(); err != nil {\n", expr)
+		buf.WriteString("\t\terrs = append(errs, err)\n")
+		buf.WriteString("\t}\n")
+		return buf.String()
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+	check := func(cond, keyword, fallback string) {
+		msg := msgs.For(keyword)
+		if msg == "" {
+			msg = fallback
+		}
+		fmt.Fprintf(&buf, "\tif %s {\n", cond)
+		fmt.Fprintf(&buf, "\t\terrs = append(errs, &ValidationError{Field: %s, Message: %s})\n", quoteName(label, b.policy), strconv.Quote(msg))
+		buf.WriteString("\t}\n")
+	}
+
+	switch {
+	case goType == "string":
+		for i, pattern := range c.Patterns {
+			name := b.patternName(goName, label, i)
+			b.patterns = append(b.patterns, patternVar{name: name, pattern: pattern})
+			check(fmt.Sprintf("!%s.MatchString(%s)", name, value), "pattern",
+				fmt.Sprintf("%s must match %s", label, pattern))
+		}
+		if c.MinLength != nil || c.MaxLength != nil {
+			b.packages["unicode/utf8"] = true
+			count := fmt.Sprintf("utf8.RuneCountInString(%s)", value)
+			if c.MinLength != nil {
+				check(fmt.Sprintf("%s < %d", count, *c.MinLength), "minLength",
+					fmt.Sprintf("%s must be at least %s", label, plural(*c.MinLength, "character")))
+			}
+			if c.MaxLength != nil {
+				check(fmt.Sprintf("%s > %d", count, *c.MaxLength), "maxLength",
+					fmt.Sprintf("%s must be at most %s", label, plural(*c.MaxLength, "character")))
+			}
+		}
+	case goType == "int" || goType == "float64":
+		bound := func(op string, limit *float64, keyword, fallback string) {
+			if limit == nil {
+				return
+			}
+			lhs := value
+			if goType == "int" && !isIntegral(*limit) {
+				lhs = "float64(" + value + ")"
+			}
+			check(fmt.Sprintf("%s %s %s", lhs, op, formatBound(*limit)), keyword,
+				fmt.Sprintf("%s must be %s %s", label, fallback, formatBound(*limit)))
+		}
+		bound("<", c.Minimum, "minimum", "at least")
+		bound(">", c.Maximum, "maximum", "at most")
+		bound("<=", c.ExclusiveMinimum, "exclusiveMinimum", "greater than")
+		bound(">=", c.ExclusiveMaximum, "exclusiveMaximum", "less than")
+	case strings.HasPrefix(goType, "[]"):
+		if c.MinLength != nil {
+			check(fmt.Sprintf("len(%s) < %d", value, *c.MinLength), "minItems",
+				fmt.Sprintf("%s must have at least %s", label, plural(*c.MinLength, "item")))
+		}
+		if c.MaxLength != nil {
+			check(fmt.Sprintf("len(%s) > %d", value, *c.MaxLength), "maxItems",
+				fmt.Sprintf("%s must have at most %s", label, plural(*c.MaxLength, "item")))
+		}
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() && b.isDefinition(elem) {
+			fmt.Fprintf(&buf, "\tfor _, item := range %s {\n", value)
+			buf.WriteString("\t\tif err := item.Validate(); err != nil {\n")
+			buf.WriteString("\t\t\terrs = append(errs, err)\n")
+			buf.WriteString("\t\t}\n")
+			buf.WriteString("\t}\n")
+		}
+	}
+	return buf.String()
+}
+
+// isDefinition reports whether a value is typed as a generated definition,
+// which has a Validate method of its own
+func (b *validatorBuilder) isDefinition(val cue.Value) bool {
+	ref := getDefinitionReference(val)
+	if ref == "" || mapToGoType(val, b.policy) != toGoName(ref, b.policy) {
+		return false
+	}
+	def, ok := b.defs[ref]
+	if !ok {
+		return false
+	}
+	kind := def.IncompleteKind()
+	return kind == cue.StructKind || kind == cue.ListKind
+}
+
+// patternName names the package-level variable of a field pattern, e.g.
+// userEmailPattern
+func (b *validatorBuilder) patternName(goName, label string, i int) string {
+	r, size := utf8.DecodeRuneInString(goName)
+	name := string(unicode.ToLower(r)) + goName[size:] + toGoFieldName(label, b.policy) + "Pattern"
+	if i > 0 {
+		name += strconv.Itoa(i + 1)
+	}
+	return name
+}
+
+// goRawString renders a raw string literal, falling back to a quoted one
+// when the text contains a backtick
+func goRawString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// formatBound renders a numeric bound as a Go constant
+func formatBound(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func isIntegral(f float64) bool {
+	return f == math.Trunc(f) && math.Abs(f) < 1<<53
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// indentLines indents every line of a block
+func indentLines(block, indent string) string {
+	lines := strings.SplitAfter(block, "\n")
+	var buf strings.Builder
+	for _, line := range lines {
+		if line != "" {
+			buf.WriteString(indent + line)
+		}
+	}
+	return buf.String()
+}
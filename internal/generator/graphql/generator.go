@@ -0,0 +1,426 @@
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// federationURL is the Apollo Federation spec linked from federated schemas
+const federationURL = "https://specs.apollo.dev/federation/v2.3"
+
+// jsonScalar matches uses of the JSON scalar in field types
+var jsonScalar = regexp.MustCompile(`[:\[] ?JSON\b`)
+
+// Generator generates GraphQL SDL from CUE
+type Generator struct{}
+
+// NewGenerator creates a new GraphQL generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "graphql"
+}
+
+// Generate generates GraphQL SDL
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// Header
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n\n")
+
+	federation := ctx.GetBoolOption("federation", false)
+	if federation {
+		fmt.Fprintf(&buf, "extend schema\n  @link(url: %q, import: [\"@key\", \"@shareable\", \"@external\"])\n\n", federationURL)
+	}
+
+	// Extract definitions
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	// Sort definitions for consistent output
+	var defNames []string
+	for name := range defs {
+		defNames = append(defNames, name)
+	}
+	sort.Strings(defNames)
+
+	keys := optionKeys(ctx)
+
+	// Generate object types
+	var body bytes.Buffer
+	for _, name := range defNames {
+		typeName := toGraphQLName(name)
+
+		// Unions of definitions become union types; unions with inline
+		// members have none, and fields of them are JSON
+		if platoCue.IsUnion(defs[name]) {
+			if members := unionMembers(defs[name]); members != nil {
+				body.WriteString(generateUnion(typeName, defs[name], members))
+				body.WriteString("\n")
+			}
+			continue
+		}
+
+		// String enums become enum types; other scalar definitions have
+		// no type of their own, and fields of them use the scalar
+		if defs[name].IncompleteKind() != cue.StructKind {
+			if values, ok := enumValues(defs[name]); ok {
+				body.WriteString(generateEnum(typeName, defs[name], values))
+				body.WriteString("\n")
+			}
+			continue
+		}
+
+		var directives []string
+		if federation {
+			directives = typeDirectives(defs[name], keys[typeName])
+		}
+
+		typeDef, err := generateType(typeName, defs[name], directives, federation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate type for %s: %w", name, err)
+		}
+		body.WriteString(typeDef)
+		body.WriteString("\n")
+	}
+
+	// Declare the scalar used for untyped structs
+	if jsonScalar.Match(body.Bytes()) {
+		buf.WriteString("scalar JSON\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		label := iter.Selector().String()
+		if strings.HasPrefix(label, "#") {
+			defs[label] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// optionKeys reads the "keys" option, mapping type names to @key field sets
+// (a string, or a list of strings for multiple keys)
+func optionKeys(ctx *generator.Context) map[string][]string {
+	keys := make(map[string][]string)
+
+	raw, ok := ctx.GetOption("keys")
+	if !ok {
+		return keys
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return keys
+	}
+
+	for name, v := range m {
+		name = toGraphQLName(name)
+		switch fields := v.(type) {
+		case string:
+			keys[name] = append(keys[name], fields)
+		case []interface{}:
+			for _, f := range fields {
+				if s, ok := f.(string); ok {
+					keys[name] = append(keys[name], s)
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// typeDirectives returns the federation directives of a type. @key field
+// sets come from @key("id") attributes (one directive per argument) and the
+// "keys" option; @shareable() marks the whole type as shareable.
+func typeDirectives(val cue.Value, optionKeys []string) []string {
+	var directives []string
+
+	fieldSets := append([]string{}, optionKeys...)
+	resolvable := true
+	if attr, ok := platoCue.GetAttr(val, "key"); ok {
+		fieldSets = append(fieldSets, attr.Args...)
+		if attr.Param("fields") != "" {
+			fieldSets = append(fieldSets, attr.Param("fields"))
+		}
+		if attr.Param("resolvable") == "false" {
+			resolvable = false
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, fields := range fieldSets {
+		if seen[fields] {
+			continue
+		}
+		seen[fields] = true
+		if resolvable {
+			directives = append(directives, fmt.Sprintf("@key(fields: %s)", strconv.Quote(fields)))
+		} else {
+			directives = append(directives, fmt.Sprintf("@key(fields: %s, resolvable: false)", strconv.Quote(fields)))
+		}
+	}
+
+	if platoCue.HasAttr(val, "shareable") {
+		directives = append(directives, "@shareable")
+	}
+
+	return directives
+}
+
+// generateType generates a GraphQL object type
+func generateType(name string, val cue.Value, directives []string, federation bool) (string, error) {
+	var buf bytes.Buffer
+
+	if doc := platoCue.Description(val); doc != "" {
+		fmt.Fprintf(&buf, "%s\n", blockString(doc, ""))
+	}
+
+	fmt.Fprintf(&buf, "type %s", name)
+	for _, d := range directives {
+		fmt.Fprintf(&buf, " %s", d)
+	}
+	buf.WriteString(" {\n")
+
+	// Iterate fields
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "", err
+	}
+
+	for iter.Next() {
+		label := iter.Selector().String()
+		fieldVal := iter.Value()
+		optional := iter.IsOptional()
+
+		// Skip definitions
+		if strings.HasPrefix(label, "#") {
+			continue
+		}
+
+		// Map type; required fields are non-null
+		gqlType := mapToGraphQLType(fieldVal)
+		if !optional {
+			gqlType += "!"
+		}
+
+		if doc := platoCue.Description(fieldVal); doc != "" {
+			fmt.Fprintf(&buf, "%s\n", blockString(doc, "  "))
+		}
+
+		fmt.Fprintf(&buf, "  %s: %s", iter.Selector().Unquoted(), gqlType)
+		if federation {
+			if platoCue.HasAttr(fieldVal, "shareable") {
+				buf.WriteString(" @shareable")
+			}
+			if platoCue.HasAttr(fieldVal, "external") {
+				buf.WriteString(" @external")
+			}
+		}
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
+
+// generateUnion generates a GraphQL union type
+func generateUnion(name string, val cue.Value, members []string) string {
+	var buf bytes.Buffer
+	if doc := platoCue.Description(val); doc != "" {
+		fmt.Fprintf(&buf, "%s\n", blockString(doc, ""))
+	}
+	fmt.Fprintf(&buf, "union %s = %s\n", name, strings.Join(members, " | "))
+	return buf.String()
+}
+
+// generateEnum generates a GraphQL enum type
+func generateEnum(name string, val cue.Value, values []string) string {
+	var buf bytes.Buffer
+	if doc := platoCue.Description(val); doc != "" {
+		fmt.Fprintf(&buf, "%s\n", blockString(doc, ""))
+	}
+	fmt.Fprintf(&buf, "enum %s {\n", name)
+	for _, v := range values {
+		fmt.Fprintf(&buf, "  %s\n", v)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// enumValues returns the GraphQL enum values of a disjunction of string
+// literals, upper-cased, e.g. ACTIVE for "active". Disjunctions of other
+// literals, or whose values clash once upper-cased, are not enums.
+func enumValues(val cue.Value) ([]string, bool) {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil, false
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil, false
+		}
+		v := enumValue(s)
+		if v == "" || seen[v] {
+			return nil, false
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	return values, true
+}
+
+// enumValue converts a string to an enum value name, e.g. IN_PROGRESS for
+// "in-progress", or "" when it has no letters or digits
+func enumValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(unicode.ToUpper(r))
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteRune('_')
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	switch {
+	case name == "":
+		return ""
+	case name == "TRUE" || name == "FALSE" || name == "NULL":
+		return name + "_"
+	case unicode.IsDigit(rune(name[0])):
+		return "_" + name
+	}
+	return name
+}
+
+// unionMembers returns the type names of the members of a union of
+// definitions, or nil when a member is an inline struct
+func unionMembers(val cue.Value) []string {
+	u, ok := platoCue.UnionOf(val)
+	if !ok {
+		return nil
+	}
+	var members []string
+	for _, m := range u.Members {
+		ref := platoCue.DefinitionRef(m)
+		if ref == "" {
+			return nil
+		}
+		members = append(members, toGraphQLName(ref))
+	}
+	return members
+}
+
+// mapToGraphQLType maps a CUE type to a GraphQL type
+func mapToGraphQLType(val cue.Value) string {
+	// References to string enums use the enum type
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if _, ok := enumValues(cue.Dereference(val)); ok {
+			return toGraphQLName(ref)
+		}
+	}
+
+	kind := val.IncompleteKind()
+
+	switch {
+	case kind&cue.StringKind != 0:
+		return "String"
+	case kind == cue.IntKind:
+		return "Int"
+	case kind&cue.NumberKind != 0:
+		return "Float"
+	case kind&cue.BoolKind != 0:
+		return "Boolean"
+	case kind&cue.ListKind != 0:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return "[String]"
+		}
+		return "[" + mapToGraphQLType(elem) + "!]"
+	case kind&cue.StructKind != 0:
+		// Check if it references a definition, which for a union needs a
+		// union type
+		if ref := platoCue.DefinitionRef(val); ref != "" {
+			if platoCue.IsUnion(val) && unionMembers(cue.Dereference(val)) == nil {
+				return "JSON"
+			}
+			return toGraphQLName(ref)
+		}
+		return "JSON"
+	default:
+		return "JSON"
+	}
+}
+
+// toGraphQLName converts a CUE definition name to a GraphQL type name
+func toGraphQLName(name string) string {
+	name = strings.TrimPrefix(name, "#")
+
+	if len(name) > 0 {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+
+	return name
+}
+
+// blockString renders a description as a GraphQL block string
+func blockString(text, indent string) string {
+	text = strings.ReplaceAll(text, `"""`, `\"""`)
+	if !strings.Contains(text, "\n") {
+		return fmt.Sprintf("%s\"\"\"%s\"\"\"", indent, text)
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return fmt.Sprintf("%s\"\"\"\n%s\n%s\"\"\"", indent, strings.Join(lines, "\n"), indent)
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("gql", "graphql")
+}
@@ -0,0 +1,606 @@
+package haskell
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Haskell data types with aeson instances from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Haskell generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "haskell"
+}
+
+// moduleName matches hierarchical module names such as Api.Types
+var moduleName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_']*(\.[A-Z][A-Za-z0-9_']*)*$`)
+
+// hsType is a Haskell type
+type hsType struct {
+	Kind string // Text, Int, Double, Bool, Value, Object, list, map, named
+	Name string // record, enum or alias name
+	Elem *hsType
+	// ElemNullable marks list elements and map values that may be null
+	ElemNullable bool
+}
+
+// record is a single-constructor record built from a struct
+type record struct {
+	Name   string
+	Doc    string
+	Fields []field
+}
+
+// field is a record field prefixed with the record name, e.g. personName
+type field struct {
+	Name     string
+	JSONName string
+	Type     *hsType
+	Maybe    bool
+	Doc      string
+}
+
+// enum is a sum type of nullary constructors built from a disjunction of
+// strings
+type enum struct {
+	Name   string
+	Doc    string
+	Values []enumValue
+}
+
+// enumValue is a constructor with the string it serializes to
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+// alias is a type synonym, e.g. for list-typed definitions
+type alias struct {
+	Name string
+	Doc  string
+	Type *hsType
+}
+
+// reserved are the imported and Prelude type names definitions cannot take
+var reserved = map[string]bool{
+	"Bool": true, "Double": true, "Either": true, "FromJSON": true, "Generic": true,
+	"Int": true, "Map": true, "Maybe": true, "Object": true, "Options": true,
+	"String": true, "Text": true, "ToJSON": true, "Value": true,
+}
+
+// builder converts definitions into records, enums and aliases
+type builder struct {
+	defTypes map[string]*hsType // CUE definition name -> Haskell type
+	types    []interface{}      // *record, *enum and *alias, in output order
+	names    map[string]bool    // type and constructor names in use
+	uses     map[string]bool    // imported names the output uses
+}
+
+// Generate generates a Haskell module
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	module, err := moduleOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	b := &builder{
+		defTypes: make(map[string]*hsType),
+		names:    make(map[string]bool),
+		uses:     make(map[string]bool),
+	}
+
+	// Struct, list and string enum definitions become types; references to
+	// other definitions use the referenced type directly
+	var names []string
+	for name, val := range defs {
+		kind := val.IncompleteKind()
+		if len(stringEnum(val)) == 0 && kind != cue.ListKind && (kind != cue.StructKind || platoCue.IsMap(val)) {
+			continue
+		}
+		typeName := toPascalCase(name)
+		if reserved[typeName] {
+			typeName += "Type"
+		}
+		b.defTypes[name] = &hsType{Kind: "named", Name: typeName}
+		b.names[typeName] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		typeName := b.defTypes[name].Name
+		val := defs[name]
+		doc := platoCue.Description(val)
+		switch {
+		case len(stringEnum(val)) > 0:
+			b.types = append(b.types, b.newEnum(typeName, doc, stringEnum(val)))
+		case val.IncompleteKind() == cue.ListKind:
+			typ, err := b.valueType(typeName+"Item", val)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			b.types = append(b.types, &alias{Name: typeName, Doc: doc, Type: typ})
+		default:
+			if err := b.record(typeName, doc, val); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("-- Generated by PlatoSL\n")
+	buf.WriteString("-- DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("{-# LANGUAGE DeriveGeneric #-}\n\n")
+	fmt.Fprintf(&buf, "module %s where\n\n", module)
+	b.writeImports(&buf)
+
+	for _, t := range b.types {
+		buf.WriteString("\n")
+		switch t := t.(type) {
+		case *record:
+			writeRecord(&buf, t)
+		case *enum:
+			writeEnum(&buf, t)
+		case *alias:
+			writeDoc(&buf, t.Doc)
+			fmt.Fprintf(&buf, "type %s = %s\n", t.Name, t.Type)
+		}
+	}
+
+	if b.uses["Options"] {
+		buf.WriteString(jsonOptions)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jsonOptions renames fields and constructors to their JSON names and drops
+// Nothing fields, for the instances derived via Generic
+const jsonOptions = `
+-- | aeson options mapping Haskell field and constructor names to the JSON
+-- names of the schema. Optional fields that are Nothing are left out.
+jsonOptions :: [(String, String)] -> Options
+jsonOptions names =
+  defaultOptions
+    { fieldLabelModifier = rename,
+      constructorTagModifier = rename,
+      omitNothingFields = True
+    }
+  where
+    rename name = fromMaybe name (lookup name names)
+`
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, err := moduleOption(ctx)
+	return err
+}
+
+// moduleOption returns the module name option, Types by default
+func moduleOption(ctx *generator.Context) (string, error) {
+	module := ctx.GetStringOption("module", "Types")
+	if !moduleName.MatchString(module) {
+		return "", fmt.Errorf("invalid Haskell module name %q", module)
+	}
+	return module, nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// record builds a record and the nested types of its fields. Nested types
+// are named after the record and field, e.g. ArticleAuthor. A struct without
+// fields becomes an alias of Object.
+func (b *builder) record(name, doc string, val cue.Value) error {
+	if !platoCue.HasFields(val) {
+		b.uses["Object"] = true
+		b.types = append(b.types, &alias{Name: name, Doc: doc, Type: &hsType{Kind: "Object"}})
+		return nil
+	}
+
+	r := &record{Name: name, Doc: doc}
+	b.types = append(b.types, r)
+	b.uses["Options"] = true
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+
+	prefix := lowerFirst(name)
+	used := make(map[string]bool)
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+
+		typ, err := b.valueType(name+toPascalCase(label), fieldVal)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", label, err)
+		}
+
+		fieldName := prefix + toPascalCase(label)
+		unique := fieldName
+		for i := 2; used[unique] || unique == prefix; i++ {
+			unique = fmt.Sprintf("%s%d", fieldName, i)
+		}
+		used[unique] = true
+
+		r.Fields = append(r.Fields, field{
+			Name:     unique,
+			JSONName: label,
+			Type:     typ,
+			Maybe:    nullable || iter.IsOptional(),
+			Doc:      platoCue.Description(iter.Value()),
+		})
+	}
+
+	return nil
+}
+
+// valueType maps a value to a Haskell type; typeName names nested types
+// created for it
+func (b *builder) valueType(typeName string, val cue.Value) (*hsType, error) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		if typ, ok := b.defTypes[path.String()]; ok {
+			return typ, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		name := b.uniqueName(typeName)
+		b.types = append(b.types, b.newEnum(name, "", members))
+		return &hsType{Kind: "named", Name: name}, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		b.uses["Text"] = true
+		return &hsType{Kind: "Text"}, nil
+	case kind == cue.IntKind:
+		return &hsType{Kind: "Int"}, nil
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return &hsType{Kind: "Double"}, nil
+	case kind == cue.BoolKind:
+		return &hsType{Kind: "Bool"}, nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			b.uses["Value"] = true
+			return &hsType{Kind: "list", Elem: &hsType{Kind: "Value"}}, nil
+		}
+		elem, nullable := platoCue.StripNull(elem)
+		elemType, err := b.valueType(typeName, elem)
+		if err != nil {
+			return nil, err
+		}
+		return &hsType{Kind: "list", Elem: elemType, ElemNullable: nullable}, nil
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			pattern, nullable := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			valueType, err := b.valueType(typeName+"Value", pattern)
+			if err != nil {
+				return nil, err
+			}
+			b.uses["Map"] = true
+			b.uses["Text"] = true
+			return &hsType{Kind: "map", Elem: valueType, ElemNullable: nullable}, nil
+		}
+		if !platoCue.HasFields(val) {
+			b.uses["Object"] = true
+			return &hsType{Kind: "Object"}, nil
+		}
+		name := b.uniqueName(typeName)
+		if err := b.record(name, "", val); err != nil {
+			return nil, err
+		}
+		return &hsType{Kind: "named", Name: name}, nil
+	default:
+		b.uses["Value"] = true
+		return &hsType{Kind: "Value"}, nil
+	}
+}
+
+// uniqueName reserves a type or constructor name, adding a number if it is
+// taken
+func (b *builder) uniqueName(name string) string {
+	return generator.UniqueName(b.names, name, "")
+}
+
+// newEnum builds an enum whose constructors are prefixed with its name,
+// e.g. StatusActive, since constructors share one namespace per module
+func (b *builder) newEnum(name, doc string, values []string) *enum {
+	b.uses["Options"] = true
+	e := &enum{Name: name, Doc: doc}
+	for _, v := range values {
+		suffix := toPascalCase(v)
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		e.Values = append(e.Values, enumValue{Name: b.uniqueName(name + suffix), Value: v})
+	}
+	return e
+}
+
+// writeImports renders the imports the module uses
+func (b *builder) writeImports(buf *bytes.Buffer) {
+	aeson := []string{"FromJSON (..)", "ToJSON (..)"}
+	if b.uses["Options"] {
+		aeson = append(aeson, "Options (..)", "defaultOptions", "genericParseJSON", "genericToEncoding", "genericToJSON")
+	}
+	if b.uses["Object"] {
+		aeson = append(aeson, "Object")
+	}
+	if b.uses["Value"] {
+		aeson = append(aeson, "Value")
+	}
+	sort.Strings(aeson)
+
+	fmt.Fprintf(buf, "import Data.Aeson (%s)\n", strings.Join(aeson, ", "))
+	if b.uses["Map"] {
+		buf.WriteString("import Data.Map.Strict (Map)\n")
+	}
+	if b.uses["Options"] {
+		buf.WriteString("import Data.Maybe (fromMaybe)\n")
+	}
+	if b.uses["Text"] {
+		buf.WriteString("import Data.Text (Text)\n")
+	}
+	buf.WriteString("import GHC.Generics (Generic)\n")
+}
+
+// String renders the type as Haskell source
+func (t *hsType) String() string {
+	switch t.Kind {
+	case "named":
+		return t.Name
+	case "list":
+		return "[" + t.elem() + "]"
+	case "map":
+		return "Map Text " + parens(t.elem())
+	default:
+		return t.Kind
+	}
+}
+
+func (t *hsType) elem() string {
+	if t.ElemNullable {
+		return "Maybe " + parens(t.Elem.String())
+	}
+	return t.Elem.String()
+}
+
+// parens wraps a type applied to arguments in parentheses
+func parens(s string) string {
+	if strings.Contains(s, " ") && !strings.HasPrefix(s, "[") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// writeRecord renders a record with instances derived via Generic
+func writeRecord(buf *bytes.Buffer, r *record) {
+	writeDoc(buf, r.Doc)
+	fmt.Fprintf(buf, "data %s = %s\n", r.Name, r.Name)
+	for i, f := range r.Fields {
+		sep := ","
+		if i == 0 {
+			sep = "{"
+		}
+		typ := f.Type.String()
+		if f.Maybe {
+			typ = "Maybe " + parens(typ)
+		}
+		fmt.Fprintf(buf, "  %s %s :: %s\n", sep, f.Name, typ)
+		if f.Doc != "" {
+			for _, line := range strings.Split(f.Doc, "\n") {
+				fmt.Fprintf(buf, "    -- ^ %s\n", line)
+			}
+		}
+	}
+	buf.WriteString("  }\n")
+	buf.WriteString("  deriving (Show, Eq, Generic)\n\n")
+
+	names := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		names[i] = fmt.Sprintf("(%s, %s)", quote(f.Name), quote(f.JSONName))
+	}
+	writeInstances(buf, r.Name, names)
+}
+
+// writeEnum renders a sum type of nullary constructors, encoded as the
+// original strings
+func writeEnum(buf *bytes.Buffer, e *enum) {
+	writeDoc(buf, e.Doc)
+	fmt.Fprintf(buf, "data %s\n", e.Name)
+	for i, v := range e.Values {
+		sep := "|"
+		if i == 0 {
+			sep = "="
+		}
+		fmt.Fprintf(buf, "  %s %s\n", sep, v.Name)
+	}
+	buf.WriteString("  deriving (Show, Eq, Ord, Enum, Bounded, Generic)\n\n")
+
+	names := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		names[i] = fmt.Sprintf("(%s, %s)", quote(v.Name), quote(v.Value))
+	}
+	writeInstances(buf, e.Name, names)
+}
+
+// writeInstances renders the FromJSON and ToJSON instances of a type and
+// the list of its JSON names
+func writeInstances(buf *bytes.Buffer, name string, names []string) {
+	namesVar := lowerFirst(name) + "JsonNames"
+	fmt.Fprintf(buf, "instance FromJSON %s where\n", name)
+	fmt.Fprintf(buf, "  parseJSON = genericParseJSON (jsonOptions %s)\n\n", namesVar)
+	fmt.Fprintf(buf, "instance ToJSON %s where\n", name)
+	fmt.Fprintf(buf, "  toJSON = genericToJSON (jsonOptions %s)\n", namesVar)
+	fmt.Fprintf(buf, "  toEncoding = genericToEncoding (jsonOptions %s)\n\n", namesVar)
+	fmt.Fprintf(buf, "%s :: [(String, String)]\n", namesVar)
+	if len(names) == 0 {
+		fmt.Fprintf(buf, "%s = []\n", namesVar)
+		return
+	}
+	fmt.Fprintf(buf, "%s =\n", namesVar)
+	for i, n := range names {
+		sep := ","
+		if i == 0 {
+			sep = "["
+		}
+		fmt.Fprintf(buf, "  %s %s\n", sep, n)
+	}
+	buf.WriteString("  ]\n")
+}
+
+// writeDoc renders a Haddock comment
+func writeDoc(buf *bytes.Buffer, doc string) {
+	if doc == "" {
+		return
+	}
+	for i, line := range strings.Split(doc, "\n") {
+		if i == 0 {
+			fmt.Fprintf(buf, "-- | %s\n", line)
+		} else {
+			fmt.Fprintf(buf, "-- %s\n", line)
+		}
+	}
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// words splits a label or value into words at separators and case changes
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			result = append(result, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// toPascalCase converts a definition name, label or enum value to a type
+// or constructor name
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(strings.TrimPrefix(name, "#")) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+// lowerFirst lowercases the first letter of a type name
+func lowerFirst(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// quote renders a Haskell string literal
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r < 0x20 || r > 0x7e:
+			// A numeric escape followed by a digit needs \& to end it
+			fmt.Fprintf(&b, `\%d\&`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("hs", "haskell")
+}
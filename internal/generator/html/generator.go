@@ -0,0 +1,607 @@
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// maxDepth bounds the nesting of inline structs listed on a page
+const maxDepth = 8
+
+// Generator generates a static HTML documentation site from CUE
+type Generator struct{}
+
+// NewGenerator creates a new HTML site generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "html"
+}
+
+// site is the data of the templates
+type site struct {
+	Title    string
+	Pages    []*page
+	License  string
+	Homepage string
+	Version  string
+}
+
+// pageData is the data of a page: the site and the definition of the page,
+// which the index has none of
+type pageData struct {
+	*site
+	Page *page
+}
+
+// page documents a definition
+type page struct {
+	Name        string
+	File        string
+	Title       string
+	Description string
+	Summary     string
+	Kind        string
+	Type        []part
+	Values      []value
+	Default     string
+	Constraints []constraint
+	Fields      []field
+	UsedBy      []use
+	Source      string
+}
+
+// field is a row of the field table of a struct definition; fields of
+// inline structs follow their parent with a dotted path
+type field struct {
+	Path        string
+	Anchor      string
+	Depth       int
+	Type        []part
+	Required    bool
+	Default     string
+	Constraints []constraint
+	Title       string
+	Description string
+}
+
+// part is a piece of a rendered type, linked when it names a definition
+type part struct {
+	Text string
+	Href string
+}
+
+// value is a value of an enum
+type value struct {
+	Text    string
+	Default bool
+}
+
+// constraint is a validation rule, with its expression set in code
+type constraint struct {
+	Text string
+	Code string
+}
+
+// use is a field or definition referring to a definition
+type use struct {
+	Name string
+	Href string
+}
+
+// searchEntry is a definition or field in the search index
+type searchEntry struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+// builder collects the pages and the references between them
+type builder struct {
+	defs  map[string]cue.Value
+	files map[string]string
+	uses  map[string][]use
+}
+
+// Generate generates the site as one stream of files
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	files, err := g.GenerateFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return generator.JoinFiles(files), nil
+}
+
+// GenerateFiles generates index.html, a page per definition, style.css and
+// search.js. The pages link to each other with relative URLs, so the site
+// can be served from any path or opened from disk.
+func (g *Generator) GenerateFiles(ctx *generator.Context) ([]generator.File, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+	var names []string
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &builder{defs: defs, files: pageFiles(names), uses: make(map[string][]use)}
+	s := &site{Title: ctx.GetStringOption("title", "")}
+	if ctx.Config != nil {
+		if s.Title == "" && ctx.Config.Name != "" {
+			s.Title = ctx.Config.Name + " schemas"
+		}
+		s.License = ctx.Config.Metadata.License
+		s.Homepage = ctx.Config.Metadata.Homepage
+		s.Version = ctx.Config.Metadata.Version
+	}
+	if s.Title == "" {
+		s.Title = "Schemas"
+	}
+
+	for _, name := range names {
+		s.Pages = append(s.Pages, b.page(name, defs[name]))
+	}
+	for _, p := range s.Pages {
+		p.UsedBy = b.uses[p.Name]
+		sort.Slice(p.UsedBy, func(i, j int) bool { return p.UsedBy[i].Name < p.UsedBy[j].Name })
+	}
+
+	var files []generator.File
+	var buf bytes.Buffer
+	buf.WriteString(marker)
+	if err := indexTemplate.Execute(&buf, pageData{site: s}); err != nil {
+		return nil, fmt.Errorf("failed to render index: %w", err)
+	}
+	files = append(files, generator.File{Path: "index.html", Content: buf.Bytes()})
+	for _, p := range s.Pages {
+		var buf bytes.Buffer
+		buf.WriteString(marker)
+		if err := pageTemplate.Execute(&buf, pageData{site: s, Page: p}); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", p.Name, err)
+		}
+		files = append(files, generator.File{Path: p.File, Content: buf.Bytes()})
+	}
+	files = append(files, generator.File{Path: "style.css", Content: []byte(styleSheet)})
+
+	index, err := searchIndex(s.Pages)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, generator.File{Path: "search.js", Content: index})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// page documents a definition: the fields of a struct, the values of an
+// enum or the type of other definitions
+func (b *builder) page(name string, val cue.Value) *page {
+	docs := platoCue.DocsOf(val)
+	p := &page{
+		Name:        name,
+		File:        b.files[name],
+		Title:       docs.Title,
+		Description: docs.Description,
+		Summary:     summary(docs),
+		Source:      source(name, val),
+	}
+
+	switch {
+	case isRecord(val):
+		p.Kind = "struct"
+		b.fields(p, "", val, 0)
+	case len(enumValues(val)) > 0:
+		p.Kind = "enum"
+		p.Values = enumValues(val)
+	default:
+		p.Kind = kindName(val)
+		p.Type = b.typeOf(val, 0)
+		p.Default = defaultValue(val)
+		p.Constraints = constraints(val)
+		b.references(val, use{Name: name, Href: p.File}, 0)
+	}
+	return p
+}
+
+// fields adds the fields of a struct to a page, followed by the fields of
+// inline structs and of lists of inline structs
+func (b *builder) fields(p *page, prefix string, val cue.Value, depth int) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		path := iter.Selector().Unquoted()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		fieldVal := iter.Value()
+		docs := platoCue.DocsOf(fieldVal)
+		f := field{
+			Path:        path,
+			Anchor:      anchor(path),
+			Depth:       depth,
+			Type:        b.typeOf(fieldVal, 0),
+			Required:    !iter.IsOptional(),
+			Default:     defaultValue(fieldVal),
+			Constraints: constraints(fieldVal),
+			Title:       docs.Title,
+			Description: docs.Description,
+		}
+		p.Fields = append(p.Fields, f)
+		b.references(fieldVal, use{Name: p.Name + "." + path, Href: p.File + "#" + f.Anchor}, 0)
+
+		if depth >= maxDepth {
+			continue
+		}
+		inner, _ := platoCue.StripNull(fieldVal)
+		if _, ok := reference(inner); ok {
+			continue
+		}
+		if isRecord(inner) {
+			b.fields(p, path, inner, depth+1)
+		} else if elem := inner.LookupPath(cue.MakePath(cue.AnyIndex)); inner.IncompleteKind() == cue.ListKind && elem.Exists() {
+			if _, ok := reference(elem); !ok && isRecord(elem) {
+				b.fields(p, path+"[]", elem, depth+1)
+			}
+		}
+	}
+}
+
+// typeOf renders the type of a value: definitions it refers to are links,
+// lists and maps name their element type, and disjunctions list their
+// branches
+func (b *builder) typeOf(val cue.Value, depth int) []part {
+	if depth > maxDepth {
+		return []part{{Text: "any"}}
+	}
+	if ref, ok := reference(val); ok {
+		return []part{{Text: ref, Href: b.files[ref]}}
+	}
+	if values := enumValues(val); len(values) > 0 {
+		texts := make([]string, len(values))
+		for i, v := range values {
+			texts[i] = v.Text
+		}
+		return []part{{Text: strings.Join(texts, " | ")}}
+	}
+	if alts, nullable := platoCue.KindAlternatives(val); alts != nil {
+		var parts []part
+		for i, alt := range alts {
+			if i > 0 {
+				parts = append(parts, part{Text: " | "})
+			}
+			parts = append(parts, b.typeOf(alt, depth+1)...)
+		}
+		if nullable {
+			parts = append(parts, part{Text: " | null"})
+		}
+		return parts
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	var parts []part
+	switch ref, isRef := reference(val); {
+	case isRef:
+		parts = []part{{Text: ref, Href: b.files[ref]}}
+	case val.IsConcrete() && val.IncompleteKind()&(cue.StructKind|cue.ListKind) == 0:
+		parts = []part{{Text: fmt.Sprint(val)}}
+	case val.IncompleteKind() == cue.ListKind:
+		parts = []part{{Text: "list"}}
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			parts = []part{{Text: "list of "}}
+			parts = append(parts, b.typeOf(elem, depth+1)...)
+		}
+	case val.IncompleteKind() == cue.StructKind && !isRecord(val):
+		parts = []part{{Text: "object"}}
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			parts = []part{{Text: "map of "}}
+			parts = append(parts, b.typeOf(elem, depth+1)...)
+		}
+	default:
+		parts = []part{{Text: kindName(val)}}
+	}
+	if nullable {
+		parts = append(parts, part{Text: " | null"})
+	}
+	return parts
+}
+
+// references records the definitions a value refers to as used by u
+func (b *builder) references(val cue.Value, u use, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	if ref, ok := reference(val); ok {
+		if _, known := b.defs[ref]; known {
+			for _, existing := range b.uses[ref] {
+				if existing == u {
+					return
+				}
+			}
+			b.uses[ref] = append(b.uses[ref], u)
+		}
+		return
+	}
+	if op, args := val.Expr(); op == cue.OrOp || op == cue.AndOp {
+		for _, arg := range args {
+			b.references(arg, u, depth+1)
+		}
+		return
+	}
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			b.references(elem, u, depth+1)
+		}
+	case cue.StructKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			b.references(elem, u, depth+1)
+		}
+	}
+}
+
+// kindName names the kind of a value, e.g. string or int
+func kindName(val cue.Value) string {
+	switch kind := val.IncompleteKind(); kind {
+	case cue.StringKind:
+		return "string"
+	case cue.IntKind:
+		return "int"
+	case cue.FloatKind:
+		return "float"
+	case cue.NumberKind:
+		return "number"
+	case cue.BoolKind:
+		return "bool"
+	case cue.BytesKind:
+		return "bytes"
+	case cue.NullKind:
+		return "null"
+	case cue.ListKind:
+		return "list"
+	case cue.StructKind:
+		return "object"
+	case cue.TopKind:
+		return "any"
+	default:
+		return kind.String()
+	}
+}
+
+// enumValues returns the values of a disjunction of literals, marking its
+// default
+func enumValues(val cue.Value) []value {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+	def := ""
+	if d, ok := val.Default(); ok && d.IsConcrete() {
+		def = fmt.Sprint(d)
+	}
+	var values []value
+	for _, arg := range args {
+		kind := arg.IncompleteKind()
+		if !arg.IsConcrete() || kind&(cue.StructKind|cue.ListKind|cue.NullKind) != 0 {
+			return nil
+		}
+		text := fmt.Sprint(arg)
+		values = append(values, value{Text: text, Default: text == def})
+	}
+	return values
+}
+
+// defaultValue renders the default of a scalar, if it has one
+func defaultValue(val cue.Value) string {
+	if val.IncompleteKind()&(cue.StructKind|cue.ListKind) != 0 {
+		return ""
+	}
+	d, ok := val.Default()
+	if !ok || !d.IsConcrete() {
+		return ""
+	}
+	return fmt.Sprint(d)
+}
+
+// constraints lists the bounds, length limits, patterns, unit and currency
+// of a value
+func constraints(val cue.Value) []constraint {
+	c := platoCue.ConstraintsOf(val)
+	var out []constraint
+	bound := func(op string, limit *float64) {
+		if limit != nil {
+			out = append(out, constraint{Code: op + " " + strconv.FormatFloat(*limit, 'g', -1, 64)})
+		}
+	}
+	bound(">=", c.Minimum)
+	bound(">", c.ExclusiveMinimum)
+	bound("<=", c.Maximum)
+	bound("<", c.ExclusiveMaximum)
+
+	unit := "characters"
+	if val.IncompleteKind() == cue.ListKind {
+		unit = "items"
+	}
+	switch {
+	case c.MinLength != nil && c.MaxLength != nil:
+		out = append(out, constraint{Text: fmt.Sprintf("%d to %d %s", *c.MinLength, *c.MaxLength, unit)})
+	case c.MinLength != nil:
+		out = append(out, constraint{Text: fmt.Sprintf("at least %d %s", *c.MinLength, unit)})
+	case c.MaxLength != nil:
+		out = append(out, constraint{Text: fmt.Sprintf("at most %d %s", *c.MaxLength, unit)})
+	}
+	for _, pattern := range c.Patterns {
+		out = append(out, constraint{Text: "matches ", Code: pattern})
+	}
+	if measure := platoCue.MeasureOf(val); !measure.IsZero() {
+		out = append(out, constraint{Text: measure.String()})
+	}
+	return out
+}
+
+// source renders the CUE of a definition, or nothing when it cannot be
+// formatted
+func source(name string, val cue.Value) string {
+	node := val.Source()
+	if node == nil {
+		node = val.Syntax(cue.Docs(true), cue.Optional(true), cue.Definitions(true))
+	}
+	data, err := format.Node(node)
+	if err != nil {
+		return ""
+	}
+	text := strings.TrimSpace(string(data))
+	if _, ok := node.(*ast.Field); ok {
+		return text
+	}
+	return name + ": " + text
+}
+
+// summary is the first line of the title or description, for listings
+func summary(docs platoCue.Docs) string {
+	text := docs.Title
+	if text == "" {
+		text = docs.Description
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+// searchIndex renders the definitions and fields as a script assigning
+// the search index, which pages load with a script tag so search works
+// when the site is opened from disk
+func searchIndex(pages []*page) ([]byte, error) {
+	var entries []searchEntry
+	for _, p := range pages {
+		entries = append(entries, searchEntry{Name: p.Name, Kind: p.Kind, Href: p.File, Text: p.Summary})
+		for _, f := range p.Fields {
+			entries = append(entries, searchEntry{
+				Name: p.Name + "." + f.Path,
+				Kind: "field",
+				Href: p.File + "#" + f.Anchor,
+				Text: summary(platoCue.Docs{Title: f.Title, Description: f.Description}),
+			})
+		}
+	}
+	if entries == nil {
+		entries = []searchEntry{}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search index: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("var searchIndex = ")
+	buf.Write(data)
+	buf.WriteString(";\n")
+	buf.WriteString(searchScript)
+	return buf.Bytes(), nil
+}
+
+// pageFiles names the page of each definition after it, e.g. Order.html
+// for #Order. Names that differ only in case get a suffix, for
+// case-insensitive file systems.
+func pageFiles(names []string) map[string]string {
+	files := make(map[string]string)
+	taken := make(map[string]bool)
+	for _, name := range names {
+		base := strings.TrimPrefix(name, "#")
+		file := base + ".html"
+		for i := 2; taken[strings.ToLower(file)] || strings.EqualFold(file, "index.html"); i++ {
+			file = fmt.Sprintf("%s-%d.html", base, i)
+		}
+		taken[strings.ToLower(file)] = true
+		files[name] = file
+	}
+	return files
+}
+
+// anchor is the element id of a field row
+func anchor(path string) string {
+	return "field-" + strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\n' {
+			return '-'
+		}
+		return r
+	}, path)
+}
+
+// isRecord reports whether a value is a struct with fields
+func isRecord(val cue.Value) bool {
+	if val.IncompleteKind() != cue.StructKind {
+		return false
+	}
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return false
+	}
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			return true
+		}
+	}
+	return false
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("site", "html")
+}
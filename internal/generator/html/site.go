@@ -0,0 +1,234 @@
+package html
+
+import (
+	"html/template"
+)
+
+// marker starts every page, where gen looks for it when removing the pages
+// of deleted definitions. html/template drops comments, so it is written
+// before the template output.
+const marker = "<!-- Generated by PlatoSL. DO NOT EDIT - This file is auto-generated -->\n"
+
+// layout is shared by the index and the definition pages: a header with
+// the site title and search box, and a footer with the package metadata
+const layout = `{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Page}}{{.Page.Name}} &middot; {{end}}{{.Title}}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<header>
+  <a class="site" href="index.html">{{.Title}}</a>
+  <div class="search">
+    <input id="search" type="search" placeholder="Search definitions and fields" autocomplete="off" aria-label="Search">
+    <ul id="results" hidden></ul>
+  </div>
+</header>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+<footer>
+  {{- if .Version}}<span>Version {{.Version}}</span>{{end}}
+  {{- if .License}}<span>License {{.License}}</span>{{end}}
+  {{- if .Homepage}}<span><a href="{{.Homepage}}">{{.Homepage}}</a></span>{{end}}
+  <span>Generated by PlatoSL</span>
+</footer>
+<script src="search.js"></script>
+</body>
+</html>
+{{end}}
+
+{{define "type"}}{{range .}}{{if .Href}}<a href="{{.Href}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}{{end}}
+
+{{define "constraints"}}{{range $i, $c := .}}{{if $i}}, {{end}}{{$c.Text}}{{if $c.Code}}<code>{{$c.Code}}</code>{{end}}{{end}}{{end}}
+`
+
+var indexTemplate = template.Must(template.Must(template.New("index").Parse(layout)).Parse(`{{template "header" .}}
+<h1>{{.Title}}</h1>
+<table>
+  <thead><tr><th>Definition</th><th>Kind</th><th>Description</th></tr></thead>
+  <tbody>
+  {{- range .Pages}}
+    <tr><td><a href="{{.File}}">{{.Name}}</a></td><td><span class="kind">{{.Kind}}</span></td><td>{{.Summary}}</td></tr>
+  {{- else}}
+    <tr><td colspan="3" class="muted">No definitions found.</td></tr>
+  {{- end}}
+  </tbody>
+</table>
+{{template "footer" .}}`))
+
+var pageTemplate = template.Must(template.Must(template.New("page").Parse(layout)).Parse(`{{template "header" .}}
+{{- with .Page}}
+<h1>{{.Name}} <span class="kind">{{.Kind}}</span></h1>
+{{- if .Title}}
+<p class="title">{{.Title}}</p>
+{{- end}}
+{{- if .Description}}
+<p class="description">{{.Description}}</p>
+{{- end}}
+
+{{- if .Fields}}
+<h2>Fields</h2>
+<table>
+  <thead><tr><th>Field</th><th>Type</th><th>Required</th><th>Default</th><th>Description</th></tr></thead>
+  <tbody>
+  {{- range .Fields}}
+    <tr id="{{.Anchor}}">
+      <td class="depth-{{.Depth}}"><a class="anchor" href="#{{.Anchor}}"><code>{{.Path}}</code></a></td>
+      <td>{{template "type" .Type}}{{if .Constraints}}<div class="muted">{{template "constraints" .Constraints}}</div>{{end}}</td>
+      <td>{{if .Required}}yes{{else}}<span class="muted">no</span>{{end}}</td>
+      <td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td>
+      <td>{{if .Title}}<strong>{{.Title}}</strong>{{if .Description}}<br>{{end}}{{end}}{{.Description}}</td>
+    </tr>
+  {{- end}}
+  </tbody>
+</table>
+{{- else if .Values}}
+<h2>Values</h2>
+<ul class="values">
+  {{- range .Values}}
+  <li><code>{{.Text}}</code>{{if .Default}} <span class="muted">(default)</span>{{end}}</li>
+  {{- end}}
+</ul>
+{{- else if .Type}}
+<h2>Type</h2>
+<p>{{template "type" .Type}}{{if .Default}} <span class="muted">(default <code>{{.Default}}</code>)</span>{{end}}</p>
+{{- if .Constraints}}
+<p class="muted">{{template "constraints" .Constraints}}</p>
+{{- end}}
+{{- end}}
+
+<h2>Used by</h2>
+{{- if .UsedBy}}
+<ul>
+  {{- range .UsedBy}}
+  <li><a href="{{.Href}}">{{.Name}}</a></li>
+  {{- end}}
+</ul>
+{{- else}}
+<p class="muted">No other definition refers to {{.Name}}.</p>
+{{- end}}
+
+{{- if .Source}}
+<details>
+  <summary>CUE source</summary>
+  <pre>{{.Source}}</pre>
+</details>
+{{- end}}
+{{- end}}
+{{template "footer" .}}`))
+
+// styleSheet is shared by all pages, in the palette of the validation
+// report
+const styleSheet = `/* Generated by PlatoSL */
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #f5f6f8; color: #1f2328; }
+header { background: #1f2937; color: #fff; padding: 16px 32px; display: flex; align-items: center; gap: 24px; flex-wrap: wrap; }
+header a.site { color: #fff; font-size: 18px; font-weight: 600; text-decoration: none; }
+.search { position: relative; flex: 1; max-width: 420px; }
+.search input { width: 100%; box-sizing: border-box; padding: 6px 10px; border-radius: 6px; border: 1px solid #374151; background: #111827; color: #fff; font-size: 14px; }
+#results { position: absolute; top: 100%; left: 0; right: 0; margin: 4px 0 0; padding: 0; list-style: none; background: #fff; border-radius: 6px; box-shadow: 0 4px 12px rgba(0,0,0,.15); max-height: 360px; overflow-y: auto; z-index: 10; }
+#results li { margin: 0; border-bottom: 1px solid #eef0f3; }
+#results a { display: block; padding: 8px 12px; color: #1f2328; text-decoration: none; font-size: 13px; }
+#results a:hover, #results a:focus { background: #f5f6f8; }
+#results .text { display: block; color: #57606a; font-size: 12px; }
+main { max-width: 1100px; margin: 0 auto; padding: 24px 32px 48px; }
+h1 { font-size: 24px; margin: 0 0 8px; }
+h2 { font-size: 16px; margin: 32px 0 12px; }
+a { color: #0969da; }
+.title { font-size: 16px; font-weight: 600; margin: 0 0 8px; }
+.description { white-space: pre-line; }
+.kind { display: inline-block; background: #eef0f3; color: #57606a; border-radius: 10px; padding: 0 8px; font-size: 12px; font-weight: normal; vertical-align: middle; }
+table { width: 100%; border-collapse: collapse; background: #fff; border-radius: 8px; overflow: hidden; box-shadow: 0 1px 2px rgba(0,0,0,.08); }
+th, td { text-align: left; padding: 10px 14px; border-bottom: 1px solid #eef0f3; font-size: 14px; vertical-align: top; }
+th { background: #fafbfc; font-weight: 600; }
+tr:target { background: #fff8c5; }
+td a.anchor { text-decoration: none; color: inherit; }
+td.depth-1 { padding-left: 34px; }
+td.depth-2 { padding-left: 54px; }
+td.depth-3 { padding-left: 74px; }
+td.depth-4, td.depth-5, td.depth-6, td.depth-7, td.depth-8 { padding-left: 94px; }
+code, pre { background: #eef0f3; border-radius: 3px; font-size: 12px; }
+code { padding: 1px 4px; }
+pre { padding: 12px 16px; overflow-x: auto; }
+ul { padding-left: 18px; }
+li { margin-bottom: 4px; }
+details { margin-top: 32px; }
+summary { cursor: pointer; font-weight: 600; }
+.muted { color: #57606a; }
+footer { max-width: 1100px; margin: 0 auto; padding: 0 32px 32px; color: #57606a; font-size: 12px; display: flex; gap: 16px; flex-wrap: wrap; }
+`
+
+// searchScript filters the search index as the user types. It is loaded
+// from search.js after the index, without modules or fetch, so it works
+// when pages are opened from disk.
+const searchScript = `
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  if (!input || !results) {
+    return;
+  }
+
+  function render(query) {
+    results.innerHTML = "";
+    var terms = query.toLowerCase().split(/\s+/).filter(Boolean);
+    if (terms.length === 0) {
+      results.hidden = true;
+      return;
+    }
+    var matches = searchIndex.filter(function (entry) {
+      var text = (entry.name + " " + (entry.text || "")).toLowerCase();
+      return terms.every(function (term) { return text.indexOf(term) !== -1; });
+    });
+    matches.sort(function (a, b) {
+      var an = a.name.toLowerCase().indexOf(terms[0]) !== -1 ? 0 : 1;
+      var bn = b.name.toLowerCase().indexOf(terms[0]) !== -1 ? 0 : 1;
+      return an - bn || a.name.length - b.name.length;
+    });
+    matches.slice(0, 50).forEach(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.href;
+      link.textContent = entry.name + " (" + entry.kind + ")";
+      if (entry.text) {
+        var text = document.createElement("span");
+        text.className = "text";
+        text.textContent = entry.text;
+        link.appendChild(text);
+      }
+      item.appendChild(link);
+      results.appendChild(item);
+    });
+    if (matches.length === 0) {
+      var none = document.createElement("li");
+      none.className = "muted";
+      none.style.padding = "8px 12px";
+      none.textContent = "No matches";
+      results.appendChild(none);
+    }
+    results.hidden = false;
+  }
+
+  input.addEventListener("input", function () { render(input.value); });
+  input.addEventListener("keydown", function (event) {
+    if (event.key === "Enter") {
+      var first = results.querySelector("a");
+      if (first) {
+        window.location.href = first.href;
+      }
+    } else if (event.key === "Escape") {
+      input.value = "";
+      render("");
+    }
+  });
+  document.addEventListener("click", function (event) {
+    if (!results.contains(event.target) && event.target !== input) {
+      results.hidden = true;
+    }
+  });
+})();
+`
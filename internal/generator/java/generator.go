@@ -0,0 +1,703 @@
+package java
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// recordTarget is the first Java release with records
+const recordTarget = 16
+
+// Generator generates Java records or classes with Jackson annotations from
+// CUE, one file per definition
+type Generator struct{}
+
+// NewGenerator creates a new Java generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "java"
+}
+
+// typeDecl is a record, class or enum
+type typeDecl struct {
+	Name   string
+	Doc    string
+	Enum   bool
+	Fields []field
+	Values []enumValue
+	Nested []*typeDecl
+	names  map[string]bool // nested type names in use
+	outer  map[string]bool // names of enclosing types
+}
+
+// field is a record component or class field
+type field struct {
+	Name     string // camelCase Java name
+	JSONName string // original label
+	Type     string
+	Required bool
+	Optional bool // omitted from JSON when null
+	Doc      string
+}
+
+// enumValue is an enum constant with the string it serializes to
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+// builder converts definitions into type declarations
+type builder struct {
+	defTypes map[string]string // CUE definition name -> Java type name
+}
+
+// Generate generates all files joined into one stream
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	files, err := g.GenerateFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return generator.JoinFiles(files), nil
+}
+
+// GenerateFiles generates one file per struct or enum definition, in the
+// directory of the package
+func (g *Generator) GenerateFiles(ctx *generator.Context) ([]generator.File, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	// Only struct and string enum definitions become types; references to
+	// other definitions, unions of structs included, use the referenced
+	// type directly
+	b := &builder{defTypes: make(map[string]string)}
+	var names []string
+	for name, val := range defs {
+		if val.IncompleteKind() == cue.StructKind && !platoCue.IsMap(val) && !platoCue.IsUnion(val) || len(stringEnum(val)) > 0 {
+			b.defTypes[name] = toPascalCase(name)
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Nested types must not shadow the definition types they sit next to
+	topNames := make(map[string]bool)
+	for _, typeName := range b.defTypes {
+		topNames[typeName] = true
+	}
+
+	pkg := packageName(ctx)
+	records := ctx.GetIntOption("target", 17) >= recordTarget
+	dir := strings.ReplaceAll(pkg, ".", "/")
+
+	var files []generator.File
+	for _, name := range names {
+		var decl *typeDecl
+		if members := stringEnum(defs[name]); len(members) > 0 {
+			decl = newEnum(b.defTypes[name], members)
+		} else {
+			decl, err = b.decl(b.defTypes[name], topNames, defs[name])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		decl.Doc = platoCue.Description(defs[name])
+
+		files = append(files, generator.File{
+			Path:    path.Join(dir, decl.Name+".java"),
+			Content: renderFile(pkg, decl, records),
+		})
+	}
+
+	return files, nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if pkg := ctx.GetStringOption("package", ""); pkg != "" && !validPackage(pkg) {
+		return fmt.Errorf("invalid package %q", pkg)
+	}
+	if target := ctx.GetIntOption("target", 17); target < 11 {
+		return fmt.Errorf("unsupported target Java %d (minimum 11)", target)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// decl builds a record or class and the nested types of its fields;
+// enclosing are the names of the types it is nested in
+func (b *builder) decl(name string, enclosing map[string]bool, val cue.Value) (*typeDecl, error) {
+	decl := &typeDecl{Name: name, names: map[string]bool{name: true}, outer: enclosing}
+	for n := range enclosing {
+		decl.names[n] = true
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+		optional := iter.IsOptional()
+
+		typ, err := b.valueType(decl, toPascalCase(label), fieldVal, nullable || optional)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", label, err)
+		}
+
+		decl.Fields = append(decl.Fields, field{
+			Name:     toFieldName(label),
+			JSONName: label,
+			Type:     typ,
+			Required: !optional,
+			Optional: optional,
+			Doc:      platoCue.Description(iter.Value()),
+		})
+	}
+
+	return decl, nil
+}
+
+// valueType maps a value to a Java type. Anonymous structs and string enums
+// become nested types of owner named after typeName. Nullable values and
+// type arguments use boxed types.
+func (b *builder) valueType(owner *typeDecl, typeName string, val cue.Value, boxed bool) (string, error) {
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if name, ok := b.defTypes[ref]; ok {
+			return name, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		e := newEnum(owner.nestedName(typeName), members)
+		owner.Nested = append(owner.Nested, e)
+		return e.Name, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return "String", nil
+	case kind == cue.IntKind:
+		return primitive("long", "Long", boxed), nil
+	case kind == cue.FloatKind, kind == cue.NumberKind:
+		return primitive("double", "Double", boxed), nil
+	case kind == cue.BoolKind:
+		return primitive("boolean", "Boolean", boxed), nil
+	case kind == cue.BytesKind:
+		return "byte[]", nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return "List<JsonNode>", nil
+		}
+		elem, _ = platoCue.StripNull(elem)
+		elemType, err := b.valueType(owner, typeName+"Item", elem, true)
+		if err != nil {
+			return "", err
+		}
+		return "List<" + elemType + ">", nil
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			pattern, _ := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			valueType, err := b.valueType(owner, typeName+"Value", pattern, true)
+			if err != nil {
+				return "", err
+			}
+			return "Map<String, " + valueType + ">", nil
+		}
+		if !platoCue.HasFields(val) {
+			return "JsonNode", nil
+		}
+		nested, err := b.decl(owner.nestedName(typeName), owner.enclosing(), val)
+		if err != nil {
+			return "", err
+		}
+		owner.Nested = append(owner.Nested, nested)
+		return nested.Name, nil
+	default:
+		return "JsonNode", nil
+	}
+}
+
+// nestedName reserves the name of a nested type. Nested types may not share
+// the name of an enclosing type or of each other.
+func (d *typeDecl) nestedName(name string) string {
+	unique := name
+	for i := 2; d.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	d.names[unique] = true
+	return unique
+}
+
+// enclosing returns the names nested types of d must avoid: d's own name
+// and the names of the types around it
+func (d *typeDecl) enclosing() map[string]bool {
+	names := map[string]bool{d.Name: true}
+	for n := range d.outer {
+		names[n] = true
+	}
+	return names
+}
+
+// newEnum builds an enum from its string values
+func newEnum(name string, values []string) *typeDecl {
+	e := &typeDecl{Name: name, Enum: true}
+	used := make(map[string]bool)
+	for _, v := range values {
+		constant := toUpperSnake(v)
+		if constant == "" {
+			constant = "EMPTY"
+		}
+		unique := constant
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s_%d", constant, i)
+		}
+		used[unique] = true
+		e.Values = append(e.Values, enumValue{Name: unique, Value: v})
+	}
+	return e
+}
+
+func primitive(name, boxedName string, boxed bool) string {
+	if boxed {
+		return boxedName
+	}
+	return name
+}
+
+// renderFile renders a top-level type with its package and imports
+func renderFile(pkg string, decl *typeDecl, records bool) []byte {
+	var body bytes.Buffer
+	writeDecl(&body, decl, records, "", false)
+
+	var imports []string
+	for _, imp := range []struct{ marker, path string }{
+		{"@JsonCreator", "com.fasterxml.jackson.annotation.JsonCreator"},
+		{"@JsonInclude", "com.fasterxml.jackson.annotation.JsonInclude"},
+		{"@JsonProperty", "com.fasterxml.jackson.annotation.JsonProperty"},
+		{"JsonNode", "com.fasterxml.jackson.databind.JsonNode"},
+		{"List<", "java.util.List"},
+		{"Map<", "java.util.Map"},
+		{"Objects.", "java.util.Objects"},
+	} {
+		if bytes.Contains(body.Bytes(), []byte(imp.marker)) {
+			imports = append(imports, imp.path)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", pkg)
+	if len(imports) > 0 {
+		for _, imp := range imports {
+			fmt.Fprintf(&buf, "import %s;\n", imp)
+		}
+		buf.WriteString("\n")
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
+
+// writeDecl renders a type and its nested types
+func writeDecl(buf *bytes.Buffer, decl *typeDecl, records bool, indent string, nested bool) {
+	switch {
+	case decl.Enum:
+		writeEnum(buf, decl, indent)
+	case records:
+		writeRecord(buf, decl, indent)
+	default:
+		writeClass(buf, decl, indent, nested)
+	}
+}
+
+// writeEnum renders an enum serialized as its original strings
+func writeEnum(buf *bytes.Buffer, decl *typeDecl, indent string) {
+	writeJavadoc(buf, decl.Doc, nil, indent)
+	fmt.Fprintf(buf, "%spublic enum %s {\n", indent, decl.Name)
+	for i, v := range decl.Values {
+		fmt.Fprintf(buf, "%s    @JsonProperty(%s)\n", indent, quote(v.Value))
+		fmt.Fprintf(buf, "%s    %s", indent, v.Name)
+		if i < len(decl.Values)-1 {
+			buf.WriteString(",\n")
+		} else {
+			buf.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// writeRecord renders a record; field docs become @param tags
+func writeRecord(buf *bytes.Buffer, decl *typeDecl, indent string) {
+	writeJavadoc(buf, decl.Doc, decl.Fields, indent)
+	fmt.Fprintf(buf, "%spublic record %s(", indent, decl.Name)
+	for i, f := range decl.Fields {
+		fmt.Fprintf(buf, "\n%s        %s%s %s", indent, annotations(f), f.Type, f.Name)
+		if i < len(decl.Fields)-1 {
+			buf.WriteString(",")
+		}
+	}
+	buf.WriteString(") {\n")
+	writeNested(buf, decl, true, indent)
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// writeClass renders an immutable class with a Jackson creator, getters,
+// equals, hashCode and toString
+func writeClass(buf *bytes.Buffer, decl *typeDecl, indent string, nested bool) {
+	in := indent + "    "
+	modifiers := "public final"
+	if nested {
+		modifiers = "public static final"
+	}
+
+	writeJavadoc(buf, decl.Doc, nil, indent)
+	fmt.Fprintf(buf, "%s%s class %s {\n", indent, modifiers, decl.Name)
+	for _, f := range decl.Fields {
+		fmt.Fprintf(buf, "%sprivate final %s %s;\n", in, f.Type, f.Name)
+	}
+	if len(decl.Fields) > 0 {
+		buf.WriteString("\n")
+	}
+
+	// Constructor
+	fmt.Fprintf(buf, "%s@JsonCreator\n", in)
+	fmt.Fprintf(buf, "%spublic %s(", in, decl.Name)
+	for i, f := range decl.Fields {
+		fmt.Fprintf(buf, "\n%s        %s %s %s", in, propertyAnnotation(f), f.Type, f.Name)
+		if i < len(decl.Fields)-1 {
+			buf.WriteString(",")
+		}
+	}
+	buf.WriteString(") {\n")
+	for _, f := range decl.Fields {
+		fmt.Fprintf(buf, "%s    this.%s = %s;\n", in, f.Name, f.Name)
+	}
+	fmt.Fprintf(buf, "%s}\n", in)
+
+	// Getters
+	for _, f := range decl.Fields {
+		buf.WriteString("\n")
+		writeJavadoc(buf, f.Doc, nil, in)
+		fmt.Fprintf(buf, "%s@JsonProperty(%s)\n", in, quote(f.JSONName))
+		if f.Optional {
+			fmt.Fprintf(buf, "%s@JsonInclude(JsonInclude.Include.NON_NULL)\n", in)
+		}
+		fmt.Fprintf(buf, "%spublic %s %s() {\n", in, f.Type, getterName(f))
+		fmt.Fprintf(buf, "%s    return %s;\n", in, f.Name)
+		fmt.Fprintf(buf, "%s}\n", in)
+	}
+
+	// equals, hashCode and toString
+	var compares, names, parts []string
+	for _, f := range decl.Fields {
+		names = append(names, f.Name)
+		parts = append(parts, fmt.Sprintf("%s=\" + %s", f.Name, f.Name))
+		switch {
+		case f.Type == "double":
+			compares = append(compares, fmt.Sprintf("Double.compare(%s, that.%s) == 0", f.Name, f.Name))
+		case isPrimitive(f.Type):
+			compares = append(compares, fmt.Sprintf("%s == that.%s", f.Name, f.Name))
+		default:
+			compares = append(compares, fmt.Sprintf("Objects.equals(%s, that.%s)", f.Name, f.Name))
+		}
+	}
+	buf.WriteString("\n")
+	fmt.Fprintf(buf, "%s@Override\n", in)
+	fmt.Fprintf(buf, "%spublic boolean equals(Object o) {\n", in)
+	fmt.Fprintf(buf, "%s    if (this == o) {\n%s        return true;\n%s    }\n", in, in, in)
+	fmt.Fprintf(buf, "%s    if (!(o instanceof %s)) {\n%s        return false;\n%s    }\n", in, decl.Name, in, in)
+	if len(compares) == 0 {
+		fmt.Fprintf(buf, "%s    return true;\n", in)
+	} else {
+		fmt.Fprintf(buf, "%s    %s that = (%s) o;\n", in, decl.Name, decl.Name)
+		fmt.Fprintf(buf, "%s    return %s;\n", in, strings.Join(compares, "\n"+in+"            && "))
+	}
+	fmt.Fprintf(buf, "%s}\n\n", in)
+
+	fmt.Fprintf(buf, "%s@Override\n", in)
+	fmt.Fprintf(buf, "%spublic int hashCode() {\n", in)
+	fmt.Fprintf(buf, "%s    return Objects.hash(%s);\n", in, strings.Join(names, ", "))
+	fmt.Fprintf(buf, "%s}\n\n", in)
+
+	fmt.Fprintf(buf, "%s@Override\n", in)
+	fmt.Fprintf(buf, "%spublic String toString() {\n", in)
+	if len(parts) == 0 {
+		fmt.Fprintf(buf, "%s    return \"%s[]\";\n", in, decl.Name)
+	} else {
+		fmt.Fprintf(buf, "%s    return \"%s[%s + \"]\";\n", in, decl.Name, strings.Join(parts, " + \", "))
+	}
+	fmt.Fprintf(buf, "%s}\n", in)
+
+	writeNested(buf, decl, false, indent)
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// writeNested renders the nested types of a record or class
+func writeNested(buf *bytes.Buffer, decl *typeDecl, records bool, indent string) {
+	for i, nested := range decl.Nested {
+		if i > 0 || !records {
+			buf.WriteString("\n")
+		}
+		writeDecl(buf, nested, records, indent+"    ", true)
+	}
+}
+
+// annotations renders the annotations of a record component
+func annotations(f field) string {
+	s := propertyAnnotation(f) + " "
+	if f.Optional {
+		s += "@JsonInclude(JsonInclude.Include.NON_NULL) "
+	}
+	return s
+}
+
+// propertyAnnotation renders @JsonProperty; creator properties of required
+// fields must be present in the JSON
+func propertyAnnotation(f field) string {
+	if f.Required {
+		return fmt.Sprintf("@JsonProperty(value = %s, required = true)", quote(f.JSONName))
+	}
+	return fmt.Sprintf("@JsonProperty(%s)", quote(f.JSONName))
+}
+
+// writeJavadoc renders a Javadoc comment, with @param tags for documented
+// record components
+func writeJavadoc(buf *bytes.Buffer, doc string, params []field, indent string) {
+	var tags []string
+	for _, p := range params {
+		if p.Doc != "" {
+			tags = append(tags, fmt.Sprintf("@param %s %s", p.Name, strings.ReplaceAll(p.Doc, "\n", " ")))
+		}
+	}
+	if doc == "" && len(tags) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	if doc != "" {
+		for _, line := range strings.Split(doc, "\n") {
+			fmt.Fprintf(buf, "%s * %s\n", indent, escapeJavadoc(line))
+		}
+		if len(tags) > 0 {
+			fmt.Fprintf(buf, "%s *\n", indent)
+		}
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(buf, "%s * %s\n", indent, escapeJavadoc(tag))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// packageName returns the Java package from the "package" option or the
+// project name
+func packageName(ctx *generator.Context) string {
+	if pkg := ctx.GetStringOption("package", ""); pkg != "" {
+		return pkg
+	}
+	name := "platosl"
+	if ctx.Config != nil && ctx.Config.Name != "" {
+		name = ctx.Config.Name
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) && b.Len() > 0 {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 || keywords[b.String()] {
+		return "platosl"
+	}
+	return b.String()
+}
+
+// validPackage reports whether a package name is a dotted list of
+// identifiers that are not keywords
+func validPackage(pkg string) bool {
+	for _, part := range strings.Split(pkg, ".") {
+		if part == "" || keywords[part] {
+			return false
+		}
+		for i, r := range part {
+			if !(r == '_' || r == '$' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// keywords are reserved words that cannot be identifiers
+var keywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "false": true, "final": true, "finally": true,
+	"float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true,
+	"native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true,
+	"strictfp": true, "super": true, "switch": true, "synchronized": true, "this": true,
+	"throw": true, "throws": true, "transient": true, "true": true, "try": true,
+	"void": true, "volatile": true, "while": true, "_": true,
+}
+
+func isPrimitive(typ string) bool {
+	switch typ {
+	case "long", "double", "boolean":
+		return true
+	}
+	return false
+}
+
+// getterName returns the JavaBean getter of a field
+func getterName(f field) string {
+	name := strings.TrimSuffix(f.Name, "_")
+	prefix := "get"
+	if f.Type == "boolean" {
+		prefix = "is"
+	}
+	return prefix + upperFirst(name)
+}
+
+// words splits a label or value into words at separators and case changes
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			result = append(result, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// toPascalCase converts a definition name or label to a type name
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(strings.TrimPrefix(name, "#")) {
+		b.WriteString(upperFirst(w))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "_" + s
+	}
+	return s
+}
+
+// toFieldName converts a label to a camelCase field name
+func toFieldName(label string) string {
+	name := toPascalCase(label)
+	if name == "" {
+		return "value"
+	}
+	if name[0] != '_' {
+		r := []rune(name)
+		name = string(unicode.ToLower(r[0])) + string(r[1:])
+	}
+	if keywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// toUpperSnake converts an enum value to a constant name
+func toUpperSnake(value string) string {
+	s := strings.ToUpper(strings.Join(words(value), "_"))
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "_" + s
+	}
+	return s
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	return string(unicode.ToUpper(r[0])) + string(r[1:])
+}
+
+// quote renders a Java string literal
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func escapeJavadoc(s string) string {
+	return strings.NewReplacer("*/", "*&#47;", "<", "&lt;", ">", "&gt;", "&", "&amp;").Replace(s)
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
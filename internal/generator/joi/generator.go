@@ -0,0 +1,402 @@
+package joi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Joi schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Joi generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "joi"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of definitions. References to definitions
+// declared further down, which only happens in cycles, are Joi.link
+// references to the id of the definition.
+type renderer struct {
+	names    map[string]string // JavaScript names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+}
+
+// Generate generates a module with a Joi schema per definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	module := ctx.GetStringOption("module", "commonjs")
+	if module != "commonjs" && module != "esm" {
+		return nil, fmt.Errorf("unknown module format %q (expected commonjs or esm)", module)
+	}
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	graph := platoCue.NewRefGraph(defs)
+	order := graph.Order(cueNames)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	if module == "esm" {
+		buf.WriteString("import Joi from 'joi';\n")
+	} else {
+		buf.WriteString("const Joi = require('joi');\n")
+	}
+
+	var exports []string
+	for _, name := range order {
+		val := defs[name]
+		jsName := r.names[name]
+
+		buf.WriteString("\n")
+		writeDoc(&buf, platoCue.Description(val), "")
+		schema := r.schema(val, 0)
+		if graph.Recursive(name) {
+			// Joi.link('#User') resolves to the enclosing schema with this id
+			schema += ".id(" + jsgen.String(jsName) + ")"
+		}
+		if module == "esm" {
+			buf.WriteString("export ")
+		}
+		fmt.Fprintf(&buf, "const %sSchema = %s;\n", jsName, schema)
+		exports = append(exports, jsName+"Schema")
+		r.declared[name] = true
+	}
+
+	if module == "commonjs" && len(exports) > 0 {
+		buf.WriteString("\nmodule.exports = {\n")
+		for _, name := range exports {
+			fmt.Fprintf(&buf, "  %s,\n", name)
+		}
+		buf.WriteString("};\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// schema renders the Joi schema of a value at an indentation level
+func (r *renderer) schema(val cue.Value, indent int) string {
+	if name, ok := r.reference(val); ok {
+		return name
+	}
+	if values := jsgen.LiteralEnum(val); len(values) > 1 {
+		_, args := val.Expr()
+		return valid(args, values)
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]string, len(alts))
+		for i, alt := range alts {
+			items[i] = r.schema(alt, indent)
+		}
+		return nullableIf(nullable, "Joi.alternatives().try("+strings.Join(items, ", ")+")")
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if name, ok := r.reference(val); ok {
+		return nullableIf(nullable, name)
+	}
+	return nullableIf(nullable, r.base(val, indent))
+}
+
+// reference renders a reference to a definition, as a link when the
+// definition is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	if !r.declared[path.String()] {
+		return "Joi.link(" + jsgen.String("#"+name) + ")", true
+	}
+	return name + "Schema", true
+}
+
+// base renders a value by its kind, with its constraints as rules
+func (r *renderer) base(val cue.Value, indent int) string {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok {
+			return valid([]cue.Value{val}, []string{lit})
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		rules := &ruleSet{msgs: msgs, typ: "string"}
+		// Joi rejects empty strings unless allowed; CUE accepts them
+		if c.MinLength == nil || *c.MinLength == 0 {
+			rules.add("allow", `""`, "", "")
+		}
+		rules.lengths(c, "minLength", "maxLength")
+		for _, p := range c.Patterns {
+			rules.add("pattern", "/"+jsgen.EscapeRegex(p)+"/", "pattern", "pattern.base")
+		}
+		return "Joi.string()" + rules.String()
+	case kind == cue.IntKind:
+		rules := &ruleSet{msgs: msgs, typ: "number"}
+		rules.add("integer", "", "", "")
+		rules.ranges(c)
+		return "Joi.number()" + rules.String()
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		rules := &ruleSet{msgs: msgs, typ: "number"}
+		rules.ranges(c)
+		return "Joi.number()" + rules.String()
+	case kind == cue.BoolKind:
+		return "Joi.boolean()"
+	case kind == cue.NullKind:
+		return "Joi.valid(null)"
+	case kind == cue.ListKind:
+		rules := &ruleSet{msgs: msgs, typ: "array"}
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			rules.add("items", r.schema(e, indent), "", "")
+		}
+		rules.lengths(c, "minItems", "maxItems")
+		return "Joi.array()" + rules.String()
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			return "Joi.object().pattern(Joi.string(), " + r.schema(val.LookupPath(cue.MakePath(cue.AnyString)), indent) + ")"
+		case !platoCue.HasFields(val):
+			return "Joi.object().unknown(true)"
+		default:
+			return r.object(val, indent)
+		}
+	default:
+		return "Joi.any()"
+	}
+}
+
+// object renders a struct as Joi.object with one key per line. Regular
+// fields are required, fields with a default take it when missing, and
+// open structs allow unknown keys.
+func (r *renderer) object(val cue.Value, indent int) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "Joi.object().unknown(true)"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("Joi.object({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		schema := r.schema(fieldVal, indent+1)
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			schema += ".default(" + def + ")"
+		} else if !iter.IsOptional() {
+			schema += ".required()"
+		}
+		if doc := platoCue.Description(fieldVal); doc != "" {
+			schema += ".description(" + jsgen.String(doc) + ")"
+		}
+
+		fmt.Fprintf(&b, "%s%s: %s,\n", pad, propertyName(label), schema)
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+	if val.Allows(cue.AnyString) {
+		b.WriteString(".unknown(true)")
+	}
+	return b.String()
+}
+
+// ruleSet collects the rules of a schema and the @errmsg messages of their
+// error codes, rendered as .messages({...})
+type ruleSet struct {
+	msgs     platoCue.ErrorMessages
+	typ      string // Joi type prefixing error codes, e.g. "string"
+	rules    []string
+	messages []string
+}
+
+// add appends a rule, with the message of keyword for the error code
+// typ.code when the field has one
+func (s *ruleSet) add(rule, arg, keyword, code string) {
+	s.rules = append(s.rules, "."+rule+"("+arg+")")
+	if keyword == "" {
+		return
+	}
+	if msg := s.msgs.For(keyword); msg != "" {
+		s.messages = append(s.messages, jsgen.String(s.typ+"."+code)+": "+jsgen.String(msg))
+	}
+}
+
+// lengths adds .min and .max for string lengths and list sizes
+func (s *ruleSet) lengths(c platoCue.Constraints, minKeyword, maxKeyword string) {
+	if c.MinLength != nil {
+		s.add("min", strconv.Itoa(*c.MinLength), minKeyword, "min")
+	}
+	if c.MaxLength != nil {
+		s.add("max", strconv.Itoa(*c.MaxLength), maxKeyword, "max")
+	}
+}
+
+// ranges adds bounds as .min, .max, .greater and .less
+func (s *ruleSet) ranges(c platoCue.Constraints) {
+	if c.Minimum != nil {
+		s.add("min", jsgen.Number(*c.Minimum), "minimum", "min")
+	}
+	if c.ExclusiveMinimum != nil {
+		s.add("greater", jsgen.Number(*c.ExclusiveMinimum), "exclusiveMinimum", "greater")
+	}
+	if c.Maximum != nil {
+		s.add("max", jsgen.Number(*c.Maximum), "maximum", "max")
+	}
+	if c.ExclusiveMaximum != nil {
+		s.add("less", jsgen.Number(*c.ExclusiveMaximum), "exclusiveMaximum", "less")
+	}
+}
+
+// String renders the rules and messages
+func (s *ruleSet) String() string {
+	out := strings.Join(s.rules, "")
+	if len(s.messages) > 0 {
+		out += ".messages({ " + strings.Join(s.messages, ", ") + " })"
+	}
+	return out
+}
+
+// valid renders a schema accepting only the given literals, as a string
+// schema when they are all strings
+func valid(args []cue.Value, values []string) string {
+	list := strings.Join(values, ", ")
+	for _, arg := range args {
+		if arg.Kind() != cue.StringKind {
+			return "Joi.valid(" + list + ")"
+		}
+	}
+	return "Joi.string().valid(" + list + ")"
+}
+
+// nullableIf allows null in a schema
+func nullableIf(nullable bool, schema string) string {
+	if nullable {
+		return schema + ".allow(null)"
+	}
+	return schema
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
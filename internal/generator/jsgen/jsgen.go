@@ -0,0 +1,86 @@
+// Package jsgen holds the helpers shared by the generators that write
+// JavaScript and TypeScript
+package jsgen
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// String renders a double-quoted string literal
+func String(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// Literal renders a concrete scalar as JavaScript
+func Literal(val cue.Value) (string, bool) {
+	switch val.Kind() {
+	case cue.StringKind:
+		s, err := val.String()
+		return String(s), err == nil
+	case cue.IntKind, cue.FloatKind, cue.BoolKind, cue.NullKind:
+		data, err := val.MarshalJSON()
+		return string(data), err == nil
+	}
+	return "", false
+}
+
+// Number renders a bound, without a fraction when it is integral
+func Number(f float64) string {
+	if f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// EscapeRegex escapes the slashes and line breaks of a pattern so it can be
+// written as a regular expression literal
+func EscapeRegex(pattern string) string {
+	var buf strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			buf.WriteRune('\\')
+		case r == '\n':
+			buf.WriteString(`\n`)
+			continue
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// LiteralEnum returns the distinct literals of a disjunction of string or
+// number literals, rendered as JavaScript
+func LiteralEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if !arg.IsConcrete() || arg.Kind()&(cue.StringKind|cue.NumberKind) == 0 {
+			return nil
+		}
+		lit, ok := Literal(arg)
+		if !ok {
+			return nil
+		}
+		if !seen[lit] {
+			seen[lit] = true
+			members = append(members, lit)
+		}
+	}
+	return members
+}
@@ -0,0 +1,54 @@
+package jsgen
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestEscapeRegex(t *testing.T) {
+	tests := map[string]string{
+		`^a/b$`:    `^a\/b$`,
+		`^a\/b$`:   `^a\/b$`,
+		"^a\nb$":   `^a\nb$`,
+		`^[a-z]+$`: `^[a-z]+$`,
+	}
+	for pattern, want := range tests {
+		if got := EscapeRegex(pattern); got != want {
+			t.Errorf("EscapeRegex(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+name:  "say \"hi\""
+count: 3
+ratio: 1.5
+flag:  true
+none:  null
+typed: string
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"name":  `"say \"hi\""`,
+		"count": "3",
+		"ratio": "1.5",
+		"flag":  "true",
+		"none":  "null",
+		"typed": "",
+	}
+	for name, want := range tests {
+		got, ok := Literal(val.LookupPath(cue.ParsePath(name)))
+		if got != want || ok != (want != "") {
+			t.Errorf("Literal(%s) = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+	if got := Number(2); got != "2" {
+		t.Errorf("Number(2) = %q, want 2", got)
+	}
+}
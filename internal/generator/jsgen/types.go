@@ -0,0 +1,153 @@
+package jsgen
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Types renders the TypeScript types of definitions whose schemas
+// TypeScript cannot infer, such as recursive ones
+type Types struct {
+	// Ref returns the type name of a reference to a definition
+	Ref func(val cue.Value) (string, bool)
+	// Key renders a property name
+	Key func(name string) string
+	// Input renders the type a schema accepts rather than the one it
+	// returns: fields with a default are optional
+	Input bool
+	// Readonly renders readonly fields and lists, as the types of schemas
+	// that decode to immutable values are
+	Readonly bool
+	// Defaults is set when a rendered object has a field with a default,
+	// which makes the input and output types differ
+	Defaults bool
+}
+
+// Definition renders the type of a definition: an object type for a
+// struct, and a union of the members for a union of structs
+func (t *Types) Definition(val cue.Value) (string, error) {
+	if u, ok := platoCue.UnionOf(val); ok {
+		members := make([]string, len(u.Members))
+		for i, m := range u.Members {
+			if ref, ok := t.Ref(m); ok {
+				members[i] = ref
+				continue
+			}
+			typ, err := t.object(m, "")
+			if err != nil {
+				return "", err
+			}
+			members[i] = typ
+		}
+		return strings.Join(members, " | "), nil
+	}
+	if val.IncompleteKind() != cue.StructKind || platoCue.IsMap(val) {
+		return t.Type(val, ""), nil
+	}
+	return t.object(val, "")
+}
+
+// object renders the fields of a struct as an object type, one per line
+func (t *Types) object(val cue.Value, indent string) (string, error) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	buf.WriteString("{\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		fieldVal := iter.Value()
+		key := t.Key(iter.Selector().Unquoted())
+		if _, ok := platoCue.ScalarDefault(fieldVal); ok {
+			t.Defaults = true
+			if t.Input {
+				key += "?"
+			}
+		} else if iter.IsOptional() {
+			key += "?"
+		}
+		if t.Readonly {
+			key = "readonly " + key
+		}
+		fmt.Fprintf(&buf, "%s  %s: %s;\n", indent, key, t.Type(fieldVal, indent+"  "))
+	}
+	buf.WriteString(indent + "}")
+	return buf.String(), nil
+}
+
+// Type renders the type of a value at an indentation
+func (t *Types) Type(val cue.Value, indent string) string {
+	if ref, ok := t.Ref(val); ok {
+		return ref
+	}
+	if values := LiteralEnum(val); len(values) > 1 {
+		return strings.Join(values, " | ")
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		types := make([]string, len(alts))
+		for i, alt := range alts {
+			types[i] = t.Type(alt, indent)
+		}
+		if nullable {
+			types = append(types, "null")
+		}
+		return strings.Join(types, " | ")
+	}
+	if rest, ok := platoCue.StripNull(val); ok {
+		return t.Type(rest, indent) + " | null"
+	}
+	if val.IsConcrete() {
+		if lit, ok := Literal(val); ok {
+			return lit
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch kind {
+	case cue.StringKind:
+		return "string"
+	case cue.IntKind, cue.FloatKind, cue.NumberKind:
+		return "number"
+	case cue.BoolKind:
+		return "boolean"
+	case cue.NullKind:
+		return "null"
+	case cue.ListKind:
+		elem := "unknown"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = t.Type(e, indent)
+		}
+		if t.Readonly {
+			return "ReadonlyArray<" + elem + ">"
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			value := t.Type(val.LookupPath(cue.MakePath(cue.AnyString)), indent)
+			if t.Readonly {
+				return "{ readonly [x: string]: " + value + " }"
+			}
+			return "Record<string, " + value + ">"
+		case !platoCue.HasFields(val):
+			return "Record<string, unknown>"
+		}
+		if typ, err := t.object(val, indent); err == nil {
+			return typ
+		}
+		return "Record<string, unknown>"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,480 @@
+package jsonld
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates a JSON-LD context from CUE
+type Generator struct{}
+
+// NewGenerator creates a new JSON-LD generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "jsonld"
+}
+
+// wellKnown are the prefixes declared when an IRI uses them and the
+// prefixes option does not
+var wellKnown = map[string]string{
+	"schema":  "https://schema.org/",
+	"xsd":     "http://www.w3.org/2001/XMLSchema#",
+	"rdf":     "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":    "http://www.w3.org/2000/01/rdf-schema#",
+	"owl":     "http://www.w3.org/2002/07/owl#",
+	"foaf":    "http://xmlns.com/foaf/0.1/",
+	"dcterms": "http://purl.org/dc/terms/",
+	"skos":    "http://www.w3.org/2004/02/skos/core#",
+}
+
+// schemes are IRI schemes that are not prefixes, besides those followed
+// by "//"
+var schemes = map[string]bool{"urn": true, "mailto": true, "tag": true, "did": true}
+
+// keywords are the JSON-LD keywords a field may alias with @jsonld
+var keywords = map[string]bool{"@id": true, "@type": true, "@language": true, "@value": true, "@index": true}
+
+// containers are the values of the container parameter
+var containers = map[string]bool{"@list": true, "@set": true, "@language": true, "@index": true}
+
+// term is the definition of a term in a context: a keyword alias, an IRI,
+// or an expanded definition with a type or container. A null term maps
+// the field to nothing, so processors drop it.
+type term struct {
+	alias     string
+	id        string
+	typ       string
+	container string
+	context   object // type-scoped context of a definition
+	null      bool
+}
+
+func (t *term) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.null:
+		return []byte("null"), nil
+	case t.alias != "":
+		return json.Marshal(t.alias)
+	case t.typ == "" && t.container == "" && t.context == nil:
+		return json.Marshal(t.id)
+	}
+	obj := object{{"@id", t.id}}
+	if t.typ != "" {
+		obj = append(obj, member{"@type", t.typ})
+	}
+	if t.container != "" {
+		obj = append(obj, member{"@container", t.container})
+	}
+	if t.context != nil {
+		obj = append(obj, member{"@context", t.context})
+	}
+	return json.Marshal(obj)
+}
+
+// key identifies the mapping of a term, to find conflicting ones
+func (t *term) key() string {
+	data, _ := t.MarshalJSON()
+	return string(data)
+}
+
+// object is a JSON object that keeps the order of its members
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// builder resolves IRIs against the prefixes and records those to declare:
+// the configured ones and the well-known ones used
+type builder struct {
+	vocab    string
+	prefixes map[string]string
+	used     map[string]bool
+}
+
+// definition holds the terms of a definition and of its inline structs
+type definition struct {
+	name  string
+	class *term
+	terms map[string]*term
+}
+
+// Generate generates the context document
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+	prefixes, err := prefixOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{
+		vocab:    ctx.GetStringOption("vocab", ""),
+		prefixes: prefixes,
+		used:     make(map[string]bool),
+	}
+	for prefix := range prefixes {
+		b.used[prefix] = true
+	}
+
+	// Unions of structs have no class of their own; their members do
+	var names []string
+	for name, val := range defs {
+		if val.IncompleteKind() == cue.StructKind && !platoCue.IsUnion(val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var definitions []*definition
+	for _, name := range names {
+		d, err := b.definition(name, defs[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		definitions = append(definitions, d)
+	}
+
+	context, err := b.context(ctx, definitions)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(object{{"@context", context}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// context assembles the context: keywords, prefixes, then the terms of
+// definitions and fields. A field mapped differently by two definitions
+// keeps the first mapping at the top and moves the others into the
+// type-scoped context of their definition.
+func (b *builder) context(ctx *generator.Context, definitions []*definition) (object, error) {
+	fields := make(map[string]*term)
+	owner := make(map[string]string)
+	scoped := false
+	for _, d := range definitions {
+		var names []string
+		for name := range d.terms {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			t := d.terms[name]
+			first, ok := fields[name]
+			if !ok {
+				fields[name], owner[name] = t, d.name
+				continue
+			}
+			if first.key() == t.key() {
+				continue
+			}
+			if d.class == nil {
+				return nil, fmt.Errorf("field %q is mapped differently in %s and %s; give %s a type with @jsonld in its body, so its mapping can be scoped to it",
+					name, owner[name], d.name, d.name)
+			}
+			d.class.context = append(d.class.context, member{name, t})
+			scoped = true
+		}
+	}
+
+	var context object
+	if scoped {
+		context = append(context, member{"@version", 1.1})
+	}
+	if base := ctx.GetStringOption("base", ""); base != "" {
+		context = append(context, member{"@base", base})
+	}
+	if b.vocab != "" {
+		context = append(context, member{"@vocab", b.vocab})
+	}
+
+	var prefixes []string
+	for prefix := range b.used {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		context = append(context, member{prefix, b.prefixes[prefix]})
+	}
+
+	taken := make(map[string]bool)
+	for _, d := range definitions {
+		if d.class != nil {
+			context = append(context, member{d.name, d.class})
+			taken[d.name] = true
+		}
+	}
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if taken[name] {
+			return nil, fmt.Errorf("field %q has the name of a definition", name)
+		}
+		context = append(context, member{name, fields[name]})
+	}
+	return context, nil
+}
+
+// definition collects the class of a definition, from @jsonld in its body,
+// and the terms of its fields
+func (b *builder) definition(name string, val cue.Value) (*definition, error) {
+	d := &definition{name: strings.TrimPrefix(name, "#"), terms: make(map[string]*term)}
+	if attr, ok := platoCue.GetAttr(val, "jsonld"); ok {
+		id, err := b.iri(attr.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		d.class = &term{id: id}
+	}
+	if err := b.fields(val, d.terms); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// fields adds the terms of the fields of a struct, and of inline structs
+// nested in it, to terms
+func (b *builder) fields(val cue.Value, terms map[string]*term) error {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		if attr, ok := platoCue.GetAttr(iter.Value(), "jsonld"); ok {
+			t, err := b.term(attr)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if prev, ok := terms[name]; ok && prev.key() != t.key() {
+				return fmt.Errorf("%s: mapped to both %s and %s", name, prev.key(), t.key())
+			}
+			terms[name] = t
+		}
+		if err := b.nested(iter.Value(), terms); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// nested adds the terms of an inline struct, or of the inline structs of a
+// list, map or disjunction; referenced definitions have terms of their own
+func (b *builder) nested(val cue.Value, terms map[string]*term) error {
+	if platoCue.DefinitionRef(val) != "" {
+		return nil
+	}
+	if op, args := val.Expr(); op == cue.OrOp {
+		for _, arg := range args {
+			if err := b.nested(arg, terms); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch val.IncompleteKind() {
+	case cue.StructKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			if err := b.nested(elem, terms); err != nil {
+				return err
+			}
+		}
+		return b.fields(val, terms)
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			return b.nested(elem, terms)
+		}
+	}
+	return nil
+}
+
+// term reads the term of a field from its @jsonld attribute, e.g.
+// @jsonld(schema:birthDate, type=xsd:date), @jsonld("@id") or @jsonld(-)
+func (b *builder) term(attr platoCue.Attr) (*term, error) {
+	arg := attr.Arg(0)
+	switch {
+	case arg == "-":
+		return &term{null: true}, nil
+	case keywords[arg]:
+		return &term{alias: arg}, nil
+	case strings.HasPrefix(arg, "@"):
+		return nil, fmt.Errorf("unsupported keyword %q in @jsonld", arg)
+	}
+
+	id, err := b.iri(arg)
+	if err != nil {
+		return nil, err
+	}
+	t := &term{id: id}
+	for key, value := range attr.Params {
+		switch key {
+		case "type":
+			switch value {
+			case "@id", "@vocab", "@json":
+				t.typ = value
+			default:
+				if t.typ, err = b.iri(value); err != nil {
+					return nil, err
+				}
+			}
+		case "container":
+			if !containers[value] {
+				return nil, fmt.Errorf("unsupported container %q in @jsonld (expected @list, @set, @language or @index)", value)
+			}
+			t.container = value
+		default:
+			return nil, fmt.Errorf("unknown parameter %q in @jsonld (expected type or container)", key)
+		}
+	}
+	return t, nil
+}
+
+// iri checks an IRI: an absolute IRI, a compact IRI whose prefix is
+// declared or well known, or a term of the vocabulary
+func (b *builder) iri(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("@jsonld needs an IRI")
+	}
+	prefix, suffix, ok := strings.Cut(s, ":")
+	if !ok {
+		if b.vocab == "" {
+			return "", fmt.Errorf("%q is relative to the vocabulary, but no vocab is set", s)
+		}
+		return s, nil
+	}
+	if strings.HasPrefix(suffix, "//") || schemes[prefix] {
+		return s, nil
+	}
+	if _, ok := b.prefixes[prefix]; !ok {
+		iri, known := wellKnown[prefix]
+		if !known {
+			return "", fmt.Errorf("unknown prefix %q in %q; declare it with --prefix or the prefixes option", prefix, s)
+		}
+		b.prefixes[prefix] = iri
+	}
+	b.used[prefix] = true
+	return s, nil
+}
+
+// prefixOption reads the prefixes option: a map from prefix to IRI, or a
+// list or comma-separated string of prefix=IRI
+func prefixOption(ctx *generator.Context) (map[string]string, error) {
+	prefixes := make(map[string]string)
+	raw, ok := ctx.GetOption("prefixes")
+	if !ok {
+		return prefixes, nil
+	}
+	var pairs []string
+	switch v := raw.(type) {
+	case map[string]string:
+		for prefix, iri := range v {
+			prefixes[prefix] = iri
+		}
+	case map[string]interface{}:
+		for prefix, iri := range v {
+			s, ok := iri.(string)
+			if !ok {
+				return nil, fmt.Errorf("prefix %q must map to an IRI string", prefix)
+			}
+			prefixes[prefix] = s
+		}
+	case string:
+		pairs = strings.Split(v, ",")
+	case []string:
+		pairs = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				pairs = append(pairs, s)
+			}
+		}
+	}
+	for _, pair := range pairs {
+		prefix, iri, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid prefix %q (expected prefix=IRI)", pair)
+		}
+		prefixes[prefix] = iri
+	}
+	for prefix, iri := range prefixes {
+		if prefix == "" || strings.ContainsAny(prefix, ":/#@") || iri == "" {
+			return nil, fmt.Errorf("invalid prefix %q=%q", prefix, iri)
+		}
+	}
+	return prefixes, nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
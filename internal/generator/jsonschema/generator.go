@@ -3,7 +3,12 @@ package jsonschema
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
 )
 
@@ -20,28 +25,38 @@ func (g *Generator) Name() string {
 	return "jsonschema"
 }
 
-// Generate generates JSON Schema
+// Generate generates JSON Schema. Definitions go into $defs, and
+// references to them become $ref; regular fields of the schemas become the
+// properties of the root schema. With the split option, it returns the
+// files of GenerateFiles joined.
 func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
-	// Use CUE's built-in JSON marshaling
-	data, err := ctx.Value.MarshalJSON()
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+	if g.SplitFiles(ctx) {
+		files, err := g.GenerateFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return generator.JoinFiles(files), nil
 	}
 
-	// Parse and wrap in JSON Schema format
-	var obj map[string]interface{}
-	if err := json.Unmarshal(data, &obj); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	d, err := draftOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{draft: d, defs: make(map[string]string), expanding: make(map[string]bool)}
+	defs, err := b.walkDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk definitions: %w", err)
 	}
 
-	// Create JSON Schema wrapper
 	schema := map[string]interface{}{
-		"$schema":     "https://json-schema.org/draft/2020-12/schema",
-		"$id":         fmt.Sprintf("https://platosl.org/schemas/%s", ctx.Config.Name),
-		"title":       ctx.Config.Name,
-		"type":        "object",
-		"properties":  obj,
-		"definitions": extractDefinitions(obj),
+		"$schema": d.uri,
+		"$id":     fmt.Sprintf("https://platosl.org/schemas/%s", ctx.Config.Name),
+		"title":   ctx.Config.Name,
+		"type":    "object",
+	}
+	b.addProperties(schema, ctx.Value, 0)
+	if len(defs) > 0 {
+		schema[d.defs] = defs
 	}
 
 	// Pretty-print JSON
@@ -53,30 +68,510 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	return output, nil
 }
 
+// SplitFiles reports whether the split option asks for a file per
+// definition
+func (g *Generator) SplitFiles(ctx *generator.Context) bool {
+	return ctx.GetBoolOption("split", false)
+}
+
+// GenerateFiles generates a <Name>.json schema per definition. References
+// to other definitions become $refs to their files, relative to the $id of
+// the schema, e.g. {"$ref": "Address.json"}. Regular fields are left out.
+func (g *Generator) GenerateFiles(ctx *generator.Context) ([]generator.File, error) {
+	d, err := draftOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{draft: d, files: true, defs: make(map[string]string), expanding: make(map[string]bool)}
+	defs, err := b.walkDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk definitions: %w", err)
+	}
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []generator.File
+	for _, name := range names {
+		schema := map[string]interface{}{
+			"$schema": d.uri,
+			"$id":     fmt.Sprintf("https://platosl.org/schemas/%s/%s", ctx.Config.Name, defFile(name)),
+			"title":   name,
+		}
+		for k, v := range defs[name].(map[string]interface{}) {
+			schema[k] = v
+		}
+		output, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format JSON of %s: %w", name, err)
+		}
+		files = append(files, generator.File{Path: name + ".json", Content: output})
+	}
+	return files, nil
+}
+
 // Validate validates the generator context
 func (g *Generator) Validate(ctx *generator.Context) error {
 	if err := ctx.Value.Err(); err != nil {
 		return fmt.Errorf("invalid CUE value: %w", err)
 	}
+	if _, err := draftOption(ctx); err != nil {
+		return err
+	}
+	if g.SplitFiles(ctx) && strings.HasSuffix(ctx.GeneratorConfig.Output, ".json") {
+		return fmt.Errorf("with split, the output is a directory, not %s (e.g. generated/schemas)", ctx.GeneratorConfig.Output)
+	}
 	return nil
 }
 
-// extractDefinitions extracts definitions from the object
-func extractDefinitions(obj map[string]interface{}) map[string]interface{} {
+// draft is a version of JSON Schema, with the keywords that differ between
+// versions
+type draft struct {
+	name string
+	uri  string
+
+	// defs is the keyword holding definitions: $defs from 2019-09 on
+	defs string
+
+	// prefixItems types the items of tuples; before 2020-12, items is an
+	// array for tuples and additionalItems types the rest
+	prefixItems bool
+
+	// refSiblings reports whether keywords next to a $ref apply; before
+	// 2019-09 they are ignored, so the $ref goes into allOf
+	refSiblings bool
+}
+
+// drafts are the supported versions, the default first
+var drafts = []draft{
+	{"2020-12", "https://json-schema.org/draft/2020-12/schema", "$defs", true, true},
+	{"2019-09", "https://json-schema.org/draft/2019-09/schema", "$defs", false, true},
+	{"draft-07", "http://json-schema.org/draft-07/schema#", "definitions", false, false},
+}
+
+// draftOption returns the draft selected with the "draft" option. The
+// draft- prefix and a leading zero are optional, e.g. 7 is draft-07.
+func draftOption(ctx *generator.Context) (draft, error) {
+	opt, ok := ctx.GetOption("draft")
+	if !ok {
+		return drafts[0], nil
+	}
+	name := strings.TrimPrefix(strings.ToLower(fmt.Sprint(opt)), "draft")
+	name = strings.TrimPrefix(name, "-")
+	if name == "7" {
+		name = "07"
+	}
+	for _, d := range drafts {
+		if strings.TrimPrefix(d.name, "draft-") == name {
+			return d, nil
+		}
+	}
+	return draft{}, fmt.Errorf("unknown JSON Schema draft %q (expected 2020-12, 2019-09 or draft-07)", fmt.Sprint(opt))
+}
+
+// builder renders CUE values as JSON Schema
+type builder struct {
+	draft draft
+
+	// files reports whether definitions are written to files of their own,
+	// which $refs point to instead of $defs
+	files bool
+
+	// defs names the definitions in $defs by their CUE path, e.g.
+	// #Address is Address
+	defs map[string]string
+
+	// expanding holds the references being inlined, to stop at cycles
+	// through values that are not in $defs
+	expanding map[string]bool
+}
+
+// walkDefinitions returns a schema for every CUE definition. All names are
+// collected first, so definitions can refer to ones declared after them.
+func (b *builder) walkDefinitions(val cue.Value) (map[string]interface{}, error) {
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]cue.Value)
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		name := strings.TrimPrefix(iter.Selector().String(), "#")
+		b.defs[iter.Selector().String()] = name
+		values[name] = iter.Value()
+	}
+
 	defs := make(map[string]interface{})
+	for name, v := range values {
+		defs[name] = b.valueSchema(v, 0)
+	}
+	return defs, nil
+}
+
+// defRef returns the $ref of a definition, a JSON pointer into $defs, or
+// definitions in draft-07, or the file of the definition with split
+func (b *builder) defRef(name string) string {
+	if b.files {
+		return defFile(name)
+	}
+	name = strings.ReplaceAll(name, "~", "~0")
+	name = strings.ReplaceAll(name, "/", "~1")
+	return (&url.URL{Fragment: "/" + b.draft.defs + "/" + name}).String()
+}
+
+// defFile returns the file name of a definition, escaped for use in a URI
+// reference
+func defFile(name string) string {
+	return url.PathEscape(name) + ".json"
+}
 
-	for key, val := range obj {
-		// CUE definitions start with #
-		if len(key) > 0 && key[0] == '#' {
-			defs[key[1:]] = val
-			delete(obj, key)
+// valueSchema builds the schema of a value: a $ref when it refers to a
+// definition, otherwise its type, the constraints of its conjuncts, the
+// values of an enum, a fixed value as const and its default. Fields
+// annotated with @unit / @currency carry the custom x-unit / x-currency
+// keywords.
+func (b *builder) valueSchema(val cue.Value, depth int) map[string]interface{} {
+	schema := make(map[string]interface{})
+
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		key := path.String()
+		if name, ok := b.defs[key]; ok {
+			b.annotate(schema, val)
+			if len(schema) > 0 && !b.draft.refSiblings {
+				schema["allOf"] = []interface{}{map[string]interface{}{"$ref": b.defRef(name)}}
+			} else {
+				schema["$ref"] = b.defRef(name)
+			}
+			return schema
+		}
+		// A value referring back to itself, other than through $defs,
+		// accepts anything below the cycle
+		if b.expanding[key] {
+			return schema
+		}
+		b.expanding[key] = true
+		defer delete(b.expanding, key)
+	}
+
+	// A value with a default is the disjunction without the default. Open
+	// lists default to [] in CUE, which is left out, as for other
+	// generators.
+	if d, ok := val.Default(); ok && d.IsConcrete() && isScalar(d) {
+		var v interface{}
+		if err := d.Decode(&v); err == nil {
+			schema["default"] = v
+		}
+	}
+	op, args := val.Expr()
+	if op == cue.NoOp && len(args) == 1 {
+		val = args[0]
+		op, args = val.Expr()
+	}
+
+	switch {
+	case op == cue.OrOp:
+		b.addDisjunction(schema, args, depth)
+	case isScalar(val) && val.IsConcrete():
+		var v interface{}
+		if err := val.Decode(&v); err == nil {
+			schema["type"] = typeName(val.IncompleteKind())
+			if v != nil {
+				schema["const"] = v
+			}
 		}
+	default:
+		b.addType(schema, val, op, args, depth)
 	}
+	addErrorMessage(schema, platoCue.ErrorMessagesOf(val))
+	b.annotate(schema, val)
+	return schema
+}
+
+// annotate adds the title, description and measure of a value
+func (b *builder) annotate(schema map[string]interface{}, val cue.Value) {
+	docs := platoCue.DocsOf(val)
+	if docs.Title != "" {
+		schema["title"] = docs.Title
+	}
+	if docs.Description != "" {
+		schema["description"] = docs.Description
+	}
+
+	measure := platoCue.MeasureOf(val)
+	if measure.Unit != "" {
+		schema["x-unit"] = measure.Unit
+	}
+	if measure.Currency != "" {
+		schema["x-currency"] = measure.Currency
+	}
+}
 
-	return defs
+// addDisjunction adds the branches of a disjunction: an enum when they are
+// all literals, e.g. "draft" | "published", and anyOf otherwise
+func (b *builder) addDisjunction(schema map[string]interface{}, args []cue.Value, depth int) {
+	var values []interface{}
+	kinds := make(map[string]bool)
+	for _, arg := range args {
+		if !isScalar(arg) || !arg.IsConcrete() {
+			values = nil
+			break
+		}
+		var v interface{}
+		if err := arg.Decode(&v); err != nil {
+			values = nil
+			break
+		}
+		values = append(values, v)
+		kinds[typeName(arg.IncompleteKind())] = true
+	}
+
+	if values != nil {
+		if len(kinds) == 1 {
+			for kind := range kinds {
+				schema["type"] = kind
+			}
+		}
+		schema["enum"] = values
+		return
+	}
+
+	var anyOf []interface{}
+	for _, arg := range args {
+		anyOf = append(anyOf, b.valueSchema(arg, depth+1))
+	}
+	schema["anyOf"] = anyOf
+}
+
+// addType adds the type of a value and the keywords of its constraints
+func (b *builder) addType(schema map[string]interface{}, val cue.Value, op cue.Op, args []cue.Value, depth int) {
+	kind := val.IncompleteKind()
+
+	// Lists and structs constrained by builtins, e.g. [...#Line] &
+	// list.MinItems(1), take their items and fields from the conjunct that
+	// is not a call
+	base := val
+	if kind == cue.BottomKind && op == cue.AndOp {
+		base = declared(args, 0)
+		kind = base.IncompleteKind()
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	switch kind {
+	case cue.StringKind:
+		schema["type"] = "string"
+		addPatterns(schema, c.Patterns)
+		// minLength and maxLength count code points, like CUE's
+		// strings.MinRunes and strings.MaxRunes
+		if c.MinLength != nil {
+			schema["minLength"] = *c.MinLength
+		}
+		if c.MaxLength != nil {
+			schema["maxLength"] = *c.MaxLength
+		}
+	case cue.BytesKind:
+		schema["type"] = "string"
+		schema["contentEncoding"] = "base64"
+	case cue.IntKind, cue.FloatKind, cue.NumberKind:
+		schema["type"] = typeName(kind)
+		addBounds(schema, c)
+	case cue.BoolKind:
+		schema["type"] = "boolean"
+	case cue.NullKind:
+		schema["type"] = "null"
+	case cue.ListKind:
+		schema["type"] = "array"
+		if depth < 8 {
+			b.addItems(schema, base, depth)
+		}
+		if c.MinLength != nil {
+			if n, ok := schema["minItems"].(int); !ok || *c.MinLength > n {
+				schema["minItems"] = *c.MinLength
+			}
+		}
+		if c.MaxLength != nil {
+			schema["maxItems"] = *c.MaxLength
+		}
+	case cue.StructKind:
+		schema["type"] = "object"
+		if depth < 8 {
+			b.addProperties(schema, base, depth)
+		}
+	}
+}
+
+// addItems adds the item schemas of a list. The fixed items of a tuple,
+// e.g. [string, int] or [string, ...int], go into prefixItems, or items in
+// drafts before 2020-12, and are required.
+func (b *builder) addItems(schema map[string]interface{}, val cue.Value, depth int) {
+	var rest interface{}
+	if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+		rest = b.valueSchema(elem, depth+1)
+	}
+
+	var prefix []interface{}
+	if iter, err := val.List(); err == nil {
+		for iter.Next() {
+			prefix = append(prefix, b.valueSchema(iter.Value(), depth+1))
+		}
+	}
+	if len(prefix) == 0 {
+		if rest != nil {
+			schema["items"] = rest
+		}
+		return
+	}
+
+	// A closed tuple allows no other items
+	if rest == nil {
+		rest = false
+	}
+	schema["minItems"] = len(prefix)
+	if b.draft.prefixItems {
+		schema["prefixItems"] = prefix
+		schema["items"] = rest
+	} else {
+		schema["items"] = prefix
+		schema["additionalItems"] = rest
+	}
+}
+
+// declared returns the conjunct among args, nested ones included, that is
+// neither a builtin call nor a conjunction itself
+func declared(args []cue.Value, depth int) cue.Value {
+	for _, arg := range args {
+		switch op, nested := arg.Expr(); {
+		case op == cue.AndOp && depth < 8:
+			if v := declared(nested, depth+1); v.Exists() {
+				return v
+			}
+		case op != cue.CallOp && op != cue.AndOp:
+			return arg
+		}
+	}
+	return cue.Value{}
+}
+
+// addBounds adds the numeric bounds of a value
+func addBounds(schema map[string]interface{}, c platoCue.Constraints) {
+	for keyword, limit := range map[string]*float64{
+		"minimum":          c.Minimum,
+		"maximum":          c.Maximum,
+		"exclusiveMinimum": c.ExclusiveMinimum,
+		"exclusiveMaximum": c.ExclusiveMaximum,
+	} {
+		if limit != nil {
+			schema[keyword] = *limit
+		}
+	}
+}
+
+// isScalar reports whether a value is a string, number, bool or null
+func isScalar(val cue.Value) bool {
+	kind := val.IncompleteKind()
+	return kind != cue.BottomKind && kind&(cue.StringKind|cue.NumberKind|cue.BoolKind|cue.NullKind) == kind
+}
+
+// typeName returns the JSON Schema type of a kind
+func typeName(kind cue.Kind) string {
+	switch kind {
+	case cue.StringKind:
+		return "string"
+	case cue.IntKind:
+		return "integer"
+	case cue.FloatKind, cue.NumberKind:
+		return "number"
+	case cue.BoolKind:
+		return "boolean"
+	case cue.NullKind:
+		return "null"
+	}
+	return ""
+}
+
+// addPatterns adds the =~ patterns of a value; further patterns go into
+// allOf, since a schema holds a single pattern keyword
+func addPatterns(schema map[string]interface{}, patterns []string) {
+	if len(patterns) == 0 {
+		return
+	}
+	schema["pattern"] = patterns[0]
+	if len(patterns) == 1 {
+		return
+	}
+	var allOf []interface{}
+	for _, pattern := range patterns[1:] {
+		allOf = append(allOf, map[string]interface{}{"pattern": pattern})
+	}
+	schema["allOf"] = allOf
+}
+
+// addErrorMessage adds the ajv-errors errorMessage keyword from @errmsg. A
+// single message replaces every error of the schema; per-keyword messages
+// become an object, with the default message under "_". Messages for
+// keywords the schema does not use are left out.
+func addErrorMessage(schema map[string]interface{}, msgs platoCue.ErrorMessages) {
+	if msgs.IsZero() {
+		return
+	}
+	if len(msgs.Keywords) == 0 {
+		schema["errorMessage"] = msgs.Default
+		return
+	}
+	errorMessage := make(map[string]interface{})
+	for keyword, msg := range msgs.Keywords {
+		if _, ok := schema[keyword]; ok {
+			errorMessage[keyword] = msg
+		}
+	}
+	if msgs.Default != "" {
+		errorMessage["_"] = msgs.Default
+	}
+	if len(errorMessage) > 0 {
+		schema["errorMessage"] = errorMessage
+	}
+}
+
+// addProperties adds properties and required fields of a struct value
+func (b *builder) addProperties(schema map[string]interface{}, val cue.Value, depth int) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return
+	}
+
+	properties := make(map[string]interface{})
+	var required []string
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		properties[name] = b.valueSchema(iter.Value(), depth+1)
+		if !iter.IsOptional() {
+			required = append(required, name)
+		}
+	}
+
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	// Pattern constraints, e.g. [string]: int, type the other properties
+	if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+		schema["additionalProperties"] = b.valueSchema(elem, depth+1)
+	}
 }
 
 func init() {
 	// Register the generator
 	generator.Register(NewGenerator())
+	generator.RegisterAlias("json", "jsonschema")
 }
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// headerMarker is the first line of the header comment of generated code
+const headerMarker = "Generated by PlatoSL"
+
+// StampMetadata adds the package metadata of a project to the header
+// comment of generated code, after its "Generated by PlatoSL" and
+// "DO NOT EDIT" lines, in the comment style of the file:
+//
+//	// Generated by PlatoSL
+//	// DO NOT EDIT - This file is auto-generated
+//	// SPDX-License-Identifier: MIT
+//	// Authors: Jane Doe <jane@example.com>
+//	// Homepage: https://example.com
+//
+// Output without such a header, e.g. JSON, is returned unchanged.
+func StampMetadata(output []byte, meta config.MetadataConfig) []byte {
+	var lines []string
+	if meta.License != "" {
+		lines = append(lines, "SPDX-License-Identifier: "+meta.License)
+	}
+	if len(meta.Authors) > 0 {
+		lines = append(lines, "Authors: "+strings.Join(meta.Authors, ", "))
+	}
+	if meta.Homepage != "" {
+		lines = append(lines, "Homepage: "+meta.Homepage)
+	}
+	if len(lines) == 0 {
+		return output
+	}
+
+	// The header is within the first lines, e.g. after <?php
+	text := string(output)
+	head := strings.SplitN(text, "\n", 6)
+	offset := 0
+	for i, line := range head[:len(head)-1] {
+		at := strings.Index(line, headerMarker)
+		if at < 0 || strings.TrimSpace(line[at+len(headerMarker):]) != "" {
+			offset += len(line) + 1
+			continue
+		}
+		prefix := line[:at]
+		if strings.TrimSpace(prefix) == "" {
+			return output
+		}
+
+		// Keep the DO NOT EDIT line with the marker
+		offset += len(line) + 1
+		if i+1 < len(head)-1 && strings.Contains(head[i+1], "DO NOT EDIT") {
+			offset += len(head[i+1]) + 1
+		}
+
+		var stamp strings.Builder
+		for _, l := range lines {
+			stamp.WriteString(prefix + l + "\n")
+		}
+		return []byte(text[:offset] + stamp.String() + text[offset:])
+	}
+	return output
+}
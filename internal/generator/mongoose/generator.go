@@ -0,0 +1,597 @@
+package mongoose
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Mongoose schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Mongoose generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "mongoose"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of struct definitions. Definitions of other
+// types, such as enums or constrained strings, are inlined where they are
+// used.
+type renderer struct {
+	names    map[string]string // model names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+	refs     map[string]bool   // definitions referenced by the current one
+	forward  bool              // whether the current field refers to a definition declared further down
+}
+
+// Generate generates a module with a Mongoose schema per struct definition
+// and a model per definition in the models option. Without the option,
+// definitions no other definition uses become models.
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	esm := ctx.GetStringOption("module", "commonjs") == "esm"
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	schemas := make(map[string]cue.Value)
+	for name, val := range defs {
+		if isSchema(val) {
+			cueNames = append(cueNames, name)
+			schemas[name] = val
+		}
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	order := platoCue.NewRefGraph(schemas).Order(cueNames)
+
+	// Schemas embedded in others are not models by default
+	referenced := make(map[string]bool)
+	for _, name := range cueNames {
+		r.refs = make(map[string]bool)
+		r.object(defs[name], 0, true, nil)
+		for ref := range r.refs {
+			if ref != name {
+				referenced[ref] = true
+			}
+		}
+	}
+	r.refs = nil
+
+	models, err := modelNames(ctx, r.names)
+	if err != nil {
+		return nil, err
+	}
+	if models == nil {
+		models = make(map[string]bool)
+		for _, name := range cueNames {
+			if !referenced[name] {
+				models[name] = true
+			}
+		}
+	}
+
+	export := func(name string) string {
+		if esm {
+			return "export const " + name
+		}
+		return "const " + name
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	if esm {
+		buf.WriteString("import mongoose, { Schema } from 'mongoose';\n")
+	} else {
+		buf.WriteString("const mongoose = require('mongoose');\n")
+		buf.WriteString("const { Schema } = mongoose;\n")
+	}
+
+	// Fields referring to definitions declared further down, which only
+	// happens in cycles, are added once every schema is declared
+	var deferred bytes.Buffer
+	var exports []string
+	for _, name := range order {
+		val := defs[name]
+		schemaName := r.names[name] + "Schema"
+
+		buf.WriteString("\n")
+		writeDoc(&buf, platoCue.Description(val), "")
+		var later []string
+		fmt.Fprintf(&buf, "%s = %s;\n", export(schemaName), r.object(val, 0, true, &later))
+		if len(later) > 0 {
+			fmt.Fprintf(&deferred, "%s.add({\n%s});\n", schemaName, strings.Join(later, ""))
+		}
+		exports = append(exports, schemaName)
+		r.declared[name] = true
+	}
+	if deferred.Len() > 0 {
+		buf.WriteString("\n")
+		buf.Write(deferred.Bytes())
+	}
+
+	var modelLines []string
+	for _, name := range order {
+		if models[name] {
+			model := r.names[name]
+			modelLines = append(modelLines, fmt.Sprintf("%s = mongoose.model(%s, %sSchema);\n", export(model), jsgen.String(model), model))
+			exports = append(exports, model)
+		}
+	}
+	if len(modelLines) > 0 {
+		buf.WriteString("\n" + strings.Join(modelLines, ""))
+	}
+
+	if !esm && len(exports) > 0 {
+		fmt.Fprintf(&buf, "\nmodule.exports = {\n  %s,\n};\n", strings.Join(exports, ",\n  "))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	switch module := ctx.GetStringOption("module", "commonjs"); module {
+	case "commonjs", "esm":
+	default:
+		return fmt.Errorf("unknown module format %q (expected commonjs or esm)", module)
+	}
+	return nil
+}
+
+// modelNames reads the models option: definition names, with or without
+// the #, as a list or a comma-separated string. It returns nil when the
+// option is not set.
+func modelNames(ctx *generator.Context, names map[string]string) (map[string]bool, error) {
+	raw, ok := ctx.GetOption("models")
+	if !ok {
+		return nil, nil
+	}
+	var list []string
+	switch v := raw.(type) {
+	case string:
+		list = strings.Split(v, ",")
+	case []string:
+		list = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+
+	models := make(map[string]bool)
+	for _, model := range list {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+		found := false
+		for name, modelName := range names {
+			if name == model || name == "#"+model || modelName == model {
+				models[name], found = true, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("model %q is not a struct definition", model)
+		}
+	}
+	return models, nil
+}
+
+// isSchema reports whether a definition is a struct with fields, which
+// becomes a schema
+func isSchema(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// object renders a struct as a Schema with one path per line. Regular
+// fields are required, unless they may be null or are lists, which
+// Mongoose defaults to []. The fields of a definition that refer to a
+// definition declared further down go to later instead.
+func (r *renderer) object(val cue.Value, indent int, top bool, later *[]string) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "Schema.Types.Mixed"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("new Schema({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		if top {
+			r.forward = false
+		}
+		typ, opts, nullable := r.path(fieldVal, indent+1)
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			if strings.HasPrefix(def, "[") || strings.HasPrefix(def, "{") {
+				// Objects and arrays would be shared between documents
+				def = "() => (" + def + ")"
+			}
+			opts = append(opts, "default: "+def)
+		} else if !iter.IsOptional() && !nullable && !strings.HasPrefix(typ, "[") {
+			opts = append([]string{"required: true"}, opts...)
+		}
+
+		var line bytes.Buffer
+		writeDoc(&line, platoCue.Description(fieldVal), pad)
+		fmt.Fprintf(&line, "%s%s: %s,\n", pad, propertyName(label), spec(typ, opts))
+		if top && r.forward && later != nil {
+			*later = append(*later, line.String())
+			continue
+		}
+		b.WriteString(line.String())
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "}")
+
+	var schemaOpts []string
+	if !top {
+		schemaOpts = append(schemaOpts, "_id: false")
+	}
+	if val.Allows(cue.AnyString) {
+		schemaOpts = append(schemaOpts, "strict: false")
+	}
+	if len(schemaOpts) > 0 {
+		b.WriteString(", { " + strings.Join(schemaOpts, ", ") + " }")
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// path renders the SchemaType of a value and its options, and reports
+// whether the value may be null
+func (r *renderer) path(val cue.Value, indent int) (string, []string, bool) {
+	if name, ok := r.reference(val); ok {
+		return name, nil, false
+	}
+	if values, args := enumLiterals(val); len(values) > 1 {
+		return enumType(args), []string{"enum: [" + strings.Join(values, ", ") + "]"}, false
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		return "Schema.Types.Mixed", nil, nullable
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if name, ok := r.reference(val); ok {
+		return name, nil, nullable
+	}
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		// Definitions other than structs are inlined
+		return r.path(cue.Dereference(val), indent)
+	}
+	typ, opts := r.base(val, indent)
+	return typ, opts, nullable
+}
+
+// enumLiterals returns the literals of a disjunction of literals and their
+// values, following references to enum definitions, e.g. "admin" and
+// "member" for #Role | *"member"
+func enumLiterals(val cue.Value) ([]string, []cue.Value) {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil, nil
+	}
+
+	var lits []string
+	var vals []cue.Value
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if _, path := arg.ReferencePath(); len(path.Selectors()) > 0 {
+			arg = cue.Dereference(arg)
+		}
+		argLits, argVals := []string(nil), []cue.Value{arg}
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if argLits, argVals = enumLiterals(arg); argLits == nil {
+				return nil, nil
+			}
+		} else {
+			lit, ok := jsgen.Literal(arg)
+			if !ok || !arg.IsConcrete() || arg.Kind() == cue.NullKind {
+				return nil, nil
+			}
+			argLits = []string{lit}
+		}
+		for i, lit := range argLits {
+			if !seen[lit] {
+				seen[lit] = true
+				lits, vals = append(lits, lit), append(vals, argVals[i])
+			}
+		}
+	}
+	return lits, vals
+}
+
+// reference renders a reference to a struct definition as its schema
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	if r.refs != nil {
+		r.refs[path.String()] = true
+	}
+	if !r.declared[path.String()] {
+		r.forward = true
+	}
+	return name + "Schema", true
+}
+
+// base renders a value by its kind, with its constraints as validators
+func (r *renderer) base(val cue.Value, indent int) (string, []string) {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok && val.Kind() != cue.NullKind {
+			return enumType([]cue.Value{val}), []string{"enum: [" + lit + "]"}
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		var opts []string
+		if c.MinLength != nil {
+			opts = append(opts, "minLength: "+withMessage(strconv.Itoa(*c.MinLength), msgs.For("minLength")))
+		}
+		if c.MaxLength != nil {
+			opts = append(opts, "maxLength: "+withMessage(strconv.Itoa(*c.MaxLength), msgs.For("maxLength")))
+		}
+		if len(c.Patterns) > 0 {
+			// Mongoose has one match per path; all patterns must match
+			pattern := c.Patterns[0]
+			if len(c.Patterns) > 1 {
+				pattern = "^"
+				for _, p := range c.Patterns {
+					pattern += `(?=[\s\S]*(?:` + p + "))"
+				}
+			}
+			opts = append(opts, "match: "+withMessage("/"+jsgen.EscapeRegex(pattern)+"/", msgs.For("pattern")))
+		}
+		return "String", opts
+	case kind == cue.IntKind:
+		opts, validators := bounds(c, msgs, true)
+		validators = append([]string{`{ validator: Number.isInteger, message: "{PATH} must be an integer" }`}, validators...)
+		return "Number", append(opts, validate(validators))
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		opts, validators := bounds(c, msgs, false)
+		if len(validators) > 0 {
+			opts = append(opts, validate(validators))
+		}
+		return "Number", opts
+	case kind == cue.BoolKind:
+		return "Boolean", nil
+	case kind == cue.ListKind:
+		elem := "Schema.Types.Mixed"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			typ, opts, _ := r.path(e, indent)
+			elem = spec(typ, opts)
+		}
+		var validators []string
+		if c.MinLength != nil {
+			validators = append(validators, validator(fmt.Sprintf("(v) => v.length >= %d", *c.MinLength),
+				fmt.Sprintf("{PATH} must have at least %d item(s)", *c.MinLength), msgs.For("minItems")))
+		}
+		if c.MaxLength != nil {
+			validators = append(validators, validator(fmt.Sprintf("(v) => v.length <= %d", *c.MaxLength),
+				fmt.Sprintf("{PATH} must have at most %d item(s)", *c.MaxLength), msgs.For("maxItems")))
+		}
+		var opts []string
+		if len(validators) > 0 {
+			opts = append(opts, validate(validators))
+		}
+		return "[" + elem + "]", opts
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			typ, opts, _ := r.path(val.LookupPath(cue.MakePath(cue.AnyString)), indent)
+			return "Map", []string{"of: " + spec(typ, opts)}
+		case !platoCue.HasFields(val):
+			return "Schema.Types.Mixed", nil
+		default:
+			return r.object(val, indent, false, nil), nil
+		}
+	default:
+		return "Schema.Types.Mixed", nil
+	}
+}
+
+// bounds renders the bounds of a number as min and max, and exclusive
+// bounds Mongoose has no option for as validators. Exclusive bounds of
+// integers become inclusive ones.
+func bounds(c platoCue.Constraints, msgs platoCue.ErrorMessages, integer bool) ([]string, []string) {
+	var opts, validators []string
+	if c.Minimum != nil {
+		opts = append(opts, "min: "+withMessage(jsgen.Number(*c.Minimum), msgs.For("minimum")))
+	}
+	if c.ExclusiveMinimum != nil {
+		if m := *c.ExclusiveMinimum; integer && m == math.Trunc(m) {
+			opts = append(opts, "min: "+withMessage(jsgen.Number(m+1), msgs.For("exclusiveMinimum")))
+		} else {
+			validators = append(validators, validator("(v) => v > "+jsgen.Number(m),
+				"{PATH} must be greater than "+jsgen.Number(m), msgs.For("exclusiveMinimum")))
+		}
+	}
+	if c.Maximum != nil {
+		opts = append(opts, "max: "+withMessage(jsgen.Number(*c.Maximum), msgs.For("maximum")))
+	}
+	if c.ExclusiveMaximum != nil {
+		if m := *c.ExclusiveMaximum; integer && m == math.Trunc(m) {
+			opts = append(opts, "max: "+withMessage(jsgen.Number(m-1), msgs.For("exclusiveMaximum")))
+		} else {
+			validators = append(validators, validator("(v) => v < "+jsgen.Number(m),
+				"{PATH} must be less than "+jsgen.Number(m), msgs.For("exclusiveMaximum")))
+		}
+	}
+	return opts, validators
+}
+
+// enumType returns the SchemaType of literals: String, Number or Boolean
+// when they share a kind, otherwise Mixed
+func enumType(args []cue.Value) string {
+	typ := ""
+	for _, arg := range args {
+		t := "Schema.Types.Mixed"
+		switch arg.Kind() {
+		case cue.StringKind:
+			t = "String"
+		case cue.IntKind, cue.FloatKind, cue.NumberKind:
+			t = "Number"
+		case cue.BoolKind:
+			t = "Boolean"
+		}
+		if typ != "" && t != typ {
+			return "Schema.Types.Mixed"
+		}
+		typ = t
+	}
+	return typ
+}
+
+// spec renders a SchemaType with its options, or the type alone
+func spec(typ string, opts []string) string {
+	if len(opts) == 0 {
+		return typ
+	}
+	return "{ type: " + typ + ", " + strings.Join(opts, ", ") + " }"
+}
+
+// withMessage renders an option value, with its @errmsg message when set
+func withMessage(value, msg string) string {
+	if msg == "" {
+		return value
+	}
+	return "[" + value + ", " + jsgen.String(msg) + "]"
+}
+
+// validator renders a custom validator, with an @errmsg message replacing
+// the default one
+func validator(fn, message, msg string) string {
+	if msg != "" {
+		message = msg
+	}
+	return "{ validator: " + fn + ", message: " + jsgen.String(message) + " }"
+}
+
+// validate renders the validate option of one or more validators
+func validate(validators []string) string {
+	if len(validators) == 1 {
+		return "validate: " + validators[0]
+	}
+	return "validate: [" + strings.Join(validators, ", ") + "]"
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,14 @@
+package generator
+
+import "fmt"
+
+// UniqueName reserves a name, adding a number after sep if it is taken,
+// e.g. Address2 or address_2
+func UniqueName(used map[string]bool, name, sep string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%s%d", name, sep, i)
+	}
+	used[unique] = true
+	return unique
+}
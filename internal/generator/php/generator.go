@@ -0,0 +1,692 @@
+package php
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates PHP 8.1 readonly classes and enums from CUE, one file
+// per type
+type Generator struct{}
+
+// NewGenerator creates a new PHP generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "php"
+}
+
+// phpType is a PHP type with what is needed to convert it from and to arrays
+type phpType struct {
+	Kind string // string, int, float, number, bool, mixed, list, map, class, enum
+	Name string // class or enum name
+	Elem *phpType
+	// ElemNullable marks list elements and map values that may be null
+	ElemNullable bool
+}
+
+// typeDecl is a class or a backed enum
+type typeDecl struct {
+	Name   string
+	Doc    string
+	Fields []field     // classes
+	Values []enumValue // enums
+	IsEnum bool
+}
+
+// field is a promoted constructor property
+type field struct {
+	Name     string // camelCase property name
+	JSONName string // original label
+	Type     *phpType
+	Nullable bool
+	Optional bool // defaults to null and is omitted from JSON when null
+	Doc      string
+}
+
+// enumValue is an enum case with the string it is backed by
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+// builder converts definitions into classes and enums
+type builder struct {
+	defTypes map[string]*phpType // CUE definition name -> PHP type
+	decls    []*typeDecl         // in output order
+	names    map[string]bool     // lower-cased type names in use
+}
+
+// Generate generates all files joined into one stream
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	files, err := g.GenerateFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return generator.JoinFiles(files), nil
+}
+
+// GenerateFiles generates one PSR-4 file per class or enum. The output
+// directory is the base directory of the namespace.
+func (g *Generator) GenerateFiles(ctx *generator.Context) ([]generator.File, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	b := &builder{
+		defTypes: make(map[string]*phpType),
+		names:    make(map[string]bool),
+	}
+
+	// Only struct and string enum definitions become types; references to
+	// other definitions, unions of structs included, use the referenced
+	// type directly
+	var names []string
+	for name, val := range defs {
+		switch {
+		case len(stringEnum(val)) > 0:
+			b.defTypes[name] = &phpType{Kind: "enum", Name: className(name)}
+		case val.IncompleteKind() == cue.StructKind && !platoCue.IsMap(val) && !platoCue.IsUnion(val):
+			b.defTypes[name] = &phpType{Kind: "class", Name: className(name)}
+		default:
+			continue
+		}
+		b.names[strings.ToLower(b.defTypes[name].Name)] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		typ := b.defTypes[name]
+		doc := platoCue.Description(defs[name])
+		if typ.Kind == "enum" {
+			b.decls = append(b.decls, newEnum(typ.Name, doc, stringEnum(defs[name])))
+			continue
+		}
+		if err := b.class(typ.Name, doc, defs[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	ns := namespace(ctx)
+	files := make([]generator.File, 0, len(b.decls))
+	for _, decl := range b.decls {
+		files = append(files, generator.File{
+			Path:    decl.Name + ".php",
+			Content: renderFile(ns, decl),
+		})
+	}
+	return files, nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if ns := ctx.GetStringOption("namespace", ""); ns != "" && !validNamespace(ns) {
+		return fmt.Errorf("invalid namespace %q", ns)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// class builds a class and the nested types of its fields. Nested types are
+// separate classes named after the class and field, e.g. ArticleAuthor.
+func (b *builder) class(name, doc string, val cue.Value) error {
+	decl := &typeDecl{Name: name, Doc: doc}
+	b.decls = append(b.decls, decl)
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+		optional := iter.IsOptional()
+
+		typ, err := b.valueType(name+toPascalCase(label), fieldVal)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", label, err)
+		}
+
+		propName := propertyName(label)
+		unique := propName
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", propName, i)
+		}
+		used[unique] = true
+
+		decl.Fields = append(decl.Fields, field{
+			Name:     unique,
+			JSONName: label,
+			Type:     typ,
+			Nullable: nullable || optional,
+			Optional: optional,
+			Doc:      platoCue.Description(iter.Value()),
+		})
+	}
+
+	return nil
+}
+
+// valueType maps a value to a PHP type; typeName names nested types
+// created for it
+func (b *builder) valueType(typeName string, val cue.Value) (*phpType, error) {
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if typ, ok := b.defTypes[ref]; ok {
+			return typ, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		name := b.uniqueName(typeName)
+		b.decls = append(b.decls, newEnum(name, "", members))
+		return &phpType{Kind: "enum", Name: name}, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return &phpType{Kind: "string"}, nil
+	case kind == cue.IntKind:
+		return &phpType{Kind: "int"}, nil
+	case kind == cue.FloatKind:
+		return &phpType{Kind: "float"}, nil
+	case kind == cue.NumberKind:
+		return &phpType{Kind: "number"}, nil
+	case kind == cue.BoolKind:
+		return &phpType{Kind: "bool"}, nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return &phpType{Kind: "list", Elem: &phpType{Kind: "mixed"}}, nil
+		}
+		elem, nullable := platoCue.StripNull(elem)
+		elemType, err := b.valueType(typeName+"Item", elem)
+		if err != nil {
+			return nil, err
+		}
+		return &phpType{Kind: "list", Elem: elemType, ElemNullable: nullable}, nil
+	case kind == cue.StructKind:
+		if platoCue.IsMap(val) {
+			pattern, nullable := platoCue.StripNull(val.LookupPath(cue.MakePath(cue.AnyString)))
+			valueType, err := b.valueType(typeName+"Value", pattern)
+			if err != nil {
+				return nil, err
+			}
+			return &phpType{Kind: "map", Elem: valueType, ElemNullable: nullable}, nil
+		}
+		if !platoCue.HasFields(val) {
+			return &phpType{Kind: "map", Elem: &phpType{Kind: "mixed"}}, nil
+		}
+		name := b.uniqueName(typeName)
+		if err := b.class(name, "", val); err != nil {
+			return nil, err
+		}
+		return &phpType{Kind: "class", Name: name}, nil
+	default:
+		return &phpType{Kind: "mixed"}, nil
+	}
+}
+
+// uniqueName reserves a type name, adding a number if it is taken. PHP
+// class names are case-insensitive.
+func (b *builder) uniqueName(name string) string {
+	unique := name
+	for i := 2; b.names[strings.ToLower(unique)]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	b.names[strings.ToLower(unique)] = true
+	return unique
+}
+
+// newEnum builds a backed enum from its string values
+func newEnum(name, doc string, values []string) *typeDecl {
+	decl := &typeDecl{Name: name, Doc: doc, IsEnum: true}
+	used := map[string]bool{"class": true}
+	for _, v := range values {
+		caseName := toPascalCase(v)
+		if caseName == "" {
+			caseName = "Empty"
+		} else if unicode.IsDigit([]rune(caseName)[0]) {
+			caseName = "V" + caseName
+		}
+		unique := caseName
+		for i := 2; used[strings.ToLower(unique)]; i++ {
+			unique = fmt.Sprintf("%s%d", caseName, i)
+		}
+		used[strings.ToLower(unique)] = true
+		decl.Values = append(decl.Values, enumValue{Name: unique, Value: v})
+	}
+	return decl
+}
+
+// declared renders the type used in a property declaration
+func (t *phpType) declared(nullable bool) string {
+	var typ string
+	switch t.Kind {
+	case "class", "enum":
+		typ = t.Name
+	case "list", "map":
+		typ = "array"
+	case "number":
+		if nullable {
+			return "int|float|null"
+		}
+		return "int|float"
+	case "mixed":
+		return "mixed"
+	default:
+		typ = t.Kind
+	}
+	if nullable {
+		return "?" + typ
+	}
+	return typ
+}
+
+// documented renders the type for PHPDoc, with array shapes spelled out
+func (t *phpType) documented(nullable bool) string {
+	var typ string
+	switch t.Kind {
+	case "list":
+		typ = "list<" + t.Elem.documented(t.ElemNullable) + ">"
+	case "map":
+		typ = "array<string, " + t.Elem.documented(t.ElemNullable) + ">"
+	default:
+		return t.declared(nullable)
+	}
+	if nullable {
+		return typ + "|null"
+	}
+	return typ
+}
+
+// needsConversion reports whether array values of t need converting to
+// objects
+func needsConversion(t *phpType) bool {
+	switch t.Kind {
+	case "class", "enum":
+		return true
+	case "list", "map":
+		return needsConversion(t.Elem)
+	}
+	return false
+}
+
+// fromArray returns the expression converting a decoded JSON value to t;
+// depth names the parameters of nested closures
+func fromArray(t *phpType, expr string, nullable bool, depth int) string {
+	var conv string
+	switch t.Kind {
+	case "class":
+		conv = fmt.Sprintf("%s::fromArray(%s)", t.Name, expr)
+	case "enum":
+		conv = fmt.Sprintf("%s::from(%s)", t.Name, expr)
+	case "list", "map":
+		if !needsConversion(t.Elem) {
+			return expr
+		}
+		param := "$item"
+		if depth > 0 {
+			param = fmt.Sprintf("$item%d", depth+1)
+		}
+		conv = fmt.Sprintf("array_map(static fn (%s) => %s, %s)",
+			param, fromArray(t.Elem, param, t.ElemNullable, depth+1), expr)
+	default:
+		return expr
+	}
+	if nullable {
+		return fmt.Sprintf("%s === null ? null : %s", expr, conv)
+	}
+	return conv
+}
+
+// renderFile renders a class or enum with the file header and namespace
+func renderFile(ns string, decl *typeDecl) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<?php\n\n")
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("declare(strict_types=1);\n\n")
+	fmt.Fprintf(&buf, "namespace %s;\n\n", ns)
+
+	if decl.IsEnum {
+		writeEnum(&buf, decl)
+	} else {
+		writeClass(&buf, decl)
+	}
+	return buf.Bytes()
+}
+
+// writeEnum renders a string-backed enum
+func writeEnum(buf *bytes.Buffer, decl *typeDecl) {
+	writeDoc(buf, decl.Doc, nil, "")
+	fmt.Fprintf(buf, "enum %s: string\n{\n", decl.Name)
+	for _, v := range decl.Values {
+		fmt.Fprintf(buf, "    case %s = %s;\n", v.Name, quote(v.Value))
+	}
+	buf.WriteString("}\n")
+}
+
+// writeClass renders a final class with readonly promoted properties, a
+// fromArray factory and jsonSerialize
+func writeClass(buf *bytes.Buffer, decl *typeDecl) {
+	writeDoc(buf, decl.Doc, nil, "")
+	fmt.Fprintf(buf, "final class %s implements \\JsonSerializable\n{\n", decl.Name)
+
+	// Required properties come first; optional ones default to null
+	params := make([]field, 0, len(decl.Fields))
+	for _, f := range decl.Fields {
+		if !f.Optional {
+			params = append(params, f)
+		}
+	}
+	for _, f := range decl.Fields {
+		if f.Optional {
+			params = append(params, f)
+		}
+	}
+
+	// Constructor
+	writeDoc(buf, "", params, "    ")
+	if len(params) == 0 {
+		buf.WriteString("    public function __construct()\n    {\n    }\n")
+	} else {
+		buf.WriteString("    public function __construct(\n")
+		for _, f := range params {
+			def := ""
+			if f.Optional {
+				def = " = null"
+			}
+			fmt.Fprintf(buf, "        public readonly %s $%s%s,\n", f.Type.declared(f.Nullable), f.Name, def)
+		}
+		buf.WriteString("    ) {\n    }\n")
+	}
+
+	// fromArray
+	buf.WriteString("\n    /**\n     * @param array<string, mixed> $data\n     */\n")
+	buf.WriteString("    public static function fromArray(array $data): self\n    {\n")
+	if len(params) == 0 {
+		buf.WriteString("        return new self();\n")
+	} else {
+		buf.WriteString("        return new self(\n")
+		for _, f := range params {
+			key := fmt.Sprintf("$data[%s]", quote(f.JSONName))
+			value := fromArray(f.Type, key, false, 0)
+			switch {
+			case f.Nullable && value == key:
+				value = key + " ?? null"
+			case f.Nullable:
+				value = fmt.Sprintf("isset(%s) ? %s : null", key, value)
+			}
+			fmt.Fprintf(buf, "            %s: %s,\n", f.Name, value)
+		}
+		buf.WriteString("        );\n")
+	}
+	buf.WriteString("    }\n")
+
+	// jsonSerialize; maps are cast to objects so empty maps encode as {},
+	// and optional properties are left out when null
+	if len(decl.Fields) == 0 {
+		buf.WriteString("\n    public function jsonSerialize(): object\n    {\n")
+		buf.WriteString("        return new \\stdClass();\n")
+		buf.WriteString("    }\n")
+		buf.WriteString("}\n")
+		return
+	}
+	buf.WriteString("\n    /**\n     * @return array<string, mixed>\n     */\n")
+	buf.WriteString("    public function jsonSerialize(): array\n    {\n")
+	hasOptional := len(params) > 0 && params[len(params)-1].Optional
+	if hasOptional {
+		buf.WriteString("        $data = [\n")
+	} else {
+		buf.WriteString("        return [\n")
+	}
+	for _, f := range decl.Fields {
+		value := "$this->" + f.Name
+		if f.Type.Kind == "map" {
+			if f.Nullable {
+				value = fmt.Sprintf("%s === null ? null : (object) %s", value, value)
+			} else {
+				value = "(object) " + value
+			}
+		}
+		fmt.Fprintf(buf, "            %s => %s,\n", quote(f.JSONName), value)
+	}
+	buf.WriteString("        ];\n")
+	if hasOptional {
+		for _, f := range decl.Fields {
+			if !f.Optional {
+				continue
+			}
+			fmt.Fprintf(buf, "        if ($this->%s === null) {\n", f.Name)
+			fmt.Fprintf(buf, "            unset($data[%s]);\n", quote(f.JSONName))
+			buf.WriteString("        }\n")
+		}
+		buf.WriteString("\n        return $data;\n")
+	}
+	buf.WriteString("    }\n")
+	buf.WriteString("}\n")
+}
+
+// writeDoc renders a PHPDoc block; params get @param tags when they are
+// documented or arrays
+func writeDoc(buf *bytes.Buffer, doc string, params []field, indent string) {
+	var lines []string
+	if doc != "" {
+		lines = append(lines, strings.Split(doc, "\n")...)
+	}
+	for _, f := range params {
+		if f.Doc == "" && f.Type.Kind != "list" && f.Type.Kind != "map" {
+			continue
+		}
+		line := fmt.Sprintf("@param %s $%s", f.Type.documented(f.Nullable), f.Name)
+		if f.Doc != "" {
+			line += " " + strings.ReplaceAll(f.Doc, "\n", " ")
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		if line == "" {
+			fmt.Fprintf(buf, "%s *\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// namespace returns the namespace from the "namespace" option or the
+// project name
+func namespace(ctx *generator.Context) string {
+	if ns := ctx.GetStringOption("namespace", ""); ns != "" {
+		return strings.Trim(ns, `\`)
+	}
+	if ctx.Config != nil && ctx.Config.Name != "" {
+		if name := toPascalCase(ctx.Config.Name); name != "" && !unicode.IsDigit([]rune(name)[0]) && !reserved[strings.ToLower(name)] {
+			return name
+		}
+	}
+	return "PlatoSL"
+}
+
+// validNamespace reports whether a namespace is a backslash-separated list
+// of identifiers that are not reserved words
+func validNamespace(ns string) bool {
+	for _, part := range strings.Split(strings.Trim(ns, `\`), `\`) {
+		if part == "" || reserved[strings.ToLower(part)] {
+			return false
+		}
+		for i, r := range part {
+			if !(r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reserved are keywords and type names that cannot name a class, enum or
+// namespace; PHP compares them case-insensitively
+var reserved = map[string]bool{
+	"abstract": true, "and": true, "array": true, "as": true, "bool": true,
+	"break": true, "callable": true, "case": true, "catch": true, "class": true,
+	"clone": true, "const": true, "continue": true, "declare": true, "default": true,
+	"do": true, "echo": true, "else": true, "elseif": true, "empty": true,
+	"enddeclare": true, "endfor": true, "endforeach": true, "endif": true, "endswitch": true,
+	"endwhile": true, "enum": true, "eval": true, "exit": true, "extends": true,
+	"false": true, "final": true, "finally": true, "float": true, "fn": true,
+	"for": true, "foreach": true, "function": true, "global": true, "goto": true,
+	"if": true, "implements": true, "include": true, "instanceof": true, "insteadof": true,
+	"int": true, "interface": true, "isset": true, "iterable": true, "list": true,
+	"match": true, "mixed": true, "namespace": true, "never": true, "new": true,
+	"null": true, "object": true, "or": true, "parent": true, "print": true,
+	"private": true, "protected": true, "public": true, "readonly": true, "require": true,
+	"resource": true, "return": true, "self": true, "static": true, "string": true,
+	"switch": true, "throw": true, "trait": true, "true": true, "try": true,
+	"unset": true, "use": true, "var": true, "void": true, "while": true,
+	"xor": true, "yield": true,
+}
+
+// className converts a definition name to a class name, suffixing reserved
+// words
+func className(name string) string {
+	s := toPascalCase(name)
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	if reserved[strings.ToLower(s)] {
+		s += "Type"
+	}
+	return s
+}
+
+// words splits a label or value into words at separators and case changes
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			result = append(result, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// toPascalCase converts a definition name, label or enum value to PascalCase
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(strings.TrimPrefix(name, "#")) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
+
+// propertyName converts a label to a camelCase property name
+func propertyName(label string) string {
+	ws := words(label)
+	if len(ws) == 0 {
+		return "empty"
+	}
+	var b strings.Builder
+	for i, w := range ws {
+		r := []rune(w)
+		if i == 0 {
+			b.WriteString(strings.ToLower(w))
+			continue
+		}
+		b.WriteString(string(unicode.ToUpper(r[0])) + strings.ToLower(string(r[1:])))
+	}
+	name := b.String()
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "v" + name
+	}
+	if name == "this" {
+		name = "thisValue"
+	}
+	return name
+}
+
+// quote renders a single-quoted PHP string literal
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(s) + "'"
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
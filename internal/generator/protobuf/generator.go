@@ -0,0 +1,547 @@
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates proto3 messages from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Protobuf generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "protobuf"
+}
+
+// message is a proto message with its nested types
+type message struct {
+	Name     string
+	Doc      string
+	Fields   []field
+	Reserved map[string]int
+	Messages []*message
+	Enums    []*enum
+}
+
+// field is a message field
+type field struct {
+	Name     string // snake_case proto name
+	JSONName string // original label when protoc would derive another JSON name
+	Type     string
+	Label    string // "optional", "repeated" or ""
+	Number   int
+	Doc      string
+}
+
+// enum is a proto enum built from a disjunction of strings
+type enum struct {
+	Name     string
+	Doc      string
+	Values   []enumValue
+	Reserved map[string]int
+}
+
+// enumValue is an enum member
+type enumValue struct {
+	Name   string
+	Number int
+}
+
+// builder converts definitions into messages while assigning numbers
+type builder struct {
+	lock     *Lock
+	imports  map[string]bool
+	defTypes map[string]string // CUE definition name -> message name
+}
+
+// Generate generates a .proto file and updates the numbering lock file.
+// With the frozenLock option, a lock file that would change is an error.
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	lockPath := ctx.GetStringOption("lockFile", DefaultLockFile)
+	lock, err := LoadLock(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		lock:     lock,
+		imports:  make(map[string]bool),
+		defTypes: make(map[string]string),
+	}
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	// Unions of structs have no message; fields of a union hold any value.
+	// String enums become top-level enums, and other scalars have no type
+	// of their own: fields of them use the scalar type.
+	var names []string
+	for name, val := range defs {
+		if platoCue.IsUnion(val) {
+			continue
+		}
+		if val.IncompleteKind() != cue.StructKind && len(stringEnum(val)) == 0 {
+			continue
+		}
+		names = append(names, name)
+		b.defTypes[name] = toMessageName(name)
+	}
+	sort.Strings(names)
+
+	messages := make(map[string]*message)
+	enums := make(map[string]*enum)
+	for _, name := range names {
+		if members := stringEnum(defs[name]); len(members) > 0 {
+			e := b.newEnum(b.defTypes[name], b.defTypes[name], members)
+			e.Doc = platoCue.Description(defs[name])
+			enums[name] = e
+			continue
+		}
+		msg, err := b.message(b.defTypes[name], b.defTypes[name], defs[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		msg.Doc = platoCue.Description(defs[name])
+		messages[name] = msg
+	}
+
+	if lock.Changed() {
+		if ctx.GetBoolOption("frozenLock", false) {
+			return nil, fmt.Errorf("field numbering in %s is out of date; run 'platosl gen protobuf' without --frozen-lock and commit the lock file", lockPath)
+		}
+		if err := lock.Save(lockPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n")
+	fmt.Fprintf(&buf, "// Field numbers are pinned in %s; commit it with the schema.\n\n", lockPath)
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", packageName(ctx))
+
+	if len(b.imports) > 0 {
+		var imports []string
+		for imp := range b.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		for _, imp := range imports {
+			fmt.Fprintf(&buf, "import %q;\n", imp)
+		}
+		buf.WriteString("\n")
+	}
+
+	if goPackage := ctx.GetStringOption("goPackage", ""); goPackage != "" {
+		fmt.Fprintf(&buf, "option go_package = %q;\n\n", goPackage)
+	}
+
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if e, ok := enums[name]; ok {
+			writeEnum(&buf, e, "")
+		} else {
+			writeMessage(&buf, messages[name], "")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, err := LoadLock(ctx.GetStringOption("lockFile", DefaultLockFile))
+	return err
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// message builds a message; lockKey is its dotted name in the lock file
+func (b *builder) message(name, lockKey string, val cue.Value) (*message, error) {
+	msg := &message{Name: name}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		label    string
+		val      cue.Value
+		optional bool
+	}
+	var fields []pending
+	var labels []string
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fields = append(fields, pending{label: label, val: iter.Value(), optional: iter.IsOptional()})
+		labels = append(labels, label)
+	}
+
+	numbers := b.lock.messageNumbers(lockKey, labels)
+	msg.Reserved = numbers.Reserved
+
+	for _, f := range fields {
+		typ, label, err := b.fieldType(msg, lockKey, f.label, f.val)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.label, err)
+		}
+		if label == "" && f.optional && !strings.HasPrefix(typ, "map<") {
+			label = "optional"
+		}
+
+		name := toSnakeCase(f.label)
+		pf := field{
+			Name:   name,
+			Type:   typ,
+			Label:  label,
+			Number: numbers.Numbers[f.label],
+			Doc:    platoCue.Description(f.val),
+		}
+		if lowerCamel(name) != f.label {
+			pf.JSONName = f.label
+		}
+		msg.Fields = append(msg.Fields, pf)
+	}
+
+	return msg, nil
+}
+
+// fieldType maps a field value to a proto type and label ("optional" for
+// nullable values, "repeated" for lists). Anonymous structs and string enums
+// become nested types of msg.
+func (b *builder) fieldType(msg *message, lockKey, label string, val cue.Value) (string, string, error) {
+	val, nullable := platoCue.StripNull(val)
+	optional := ""
+	if nullable {
+		optional = "optional"
+	}
+
+	if val.IncompleteKind() == cue.ListKind {
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			b.imports["google/protobuf/struct.proto"] = true
+			return "google.protobuf.ListValue", optional, nil
+		}
+		elem, _ = platoCue.StripNull(elem)
+		if elem.IncompleteKind() == cue.ListKind {
+			// Lists of lists have no proto equivalent
+			b.imports["google/protobuf/struct.proto"] = true
+			return "google.protobuf.ListValue", "repeated", nil
+		}
+		typ, err := b.valueType(msg, lockKey, toMessageName(label)+"Item", elem)
+		return typ, "repeated", err
+	}
+
+	typ, err := b.valueType(msg, lockKey, toMessageName(label), val)
+	if strings.HasPrefix(typ, "map<") {
+		optional = ""
+	}
+	return typ, optional, err
+}
+
+// valueType maps a non-list value to a proto type; typeName names nested
+// types created for it
+func (b *builder) valueType(msg *message, lockKey, typeName string, val cue.Value) (string, error) {
+	if ref := platoCue.DefinitionRef(val); ref != "" {
+		if name, ok := b.defTypes[ref]; ok {
+			return name, nil
+		}
+	}
+
+	if members := stringEnum(val); len(members) > 0 {
+		return b.enum(msg, lockKey, typeName, members), nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return "string", nil
+	case kind == cue.IntKind:
+		return "int64", nil
+	case kind == cue.FloatKind, kind == cue.NumberKind, kind == cue.IntKind|cue.FloatKind:
+		return "double", nil
+	case kind == cue.BoolKind:
+		return "bool", nil
+	case kind == cue.BytesKind:
+		return "bytes", nil
+	case kind == cue.StructKind && platoCue.IsUnion(val):
+		b.imports["google/protobuf/struct.proto"] = true
+		return "google.protobuf.Value", nil
+	case kind == cue.StructKind:
+		if pattern := val.LookupPath(cue.MakePath(cue.AnyString)); pattern.Exists() && !platoCue.HasFields(val) {
+			pattern, _ = platoCue.StripNull(pattern)
+			if pattern.IncompleteKind() == cue.ListKind || pattern.IncompleteKind() == cue.StructKind && platoCue.IsMap(pattern) {
+				b.imports["google/protobuf/struct.proto"] = true
+				return "map<string, google.protobuf.Value>", nil
+			}
+			valueType, err := b.valueType(msg, lockKey, typeName+"Value", pattern)
+			if err != nil {
+				return "", err
+			}
+			return "map<string, " + valueType + ">", nil
+		}
+
+		nestedKey := lockKey + "." + typeName
+		nested, err := b.message(typeName, nestedKey, val)
+		if err != nil {
+			return "", err
+		}
+		msg.Messages = append(msg.Messages, nested)
+		return typeName, nil
+	default:
+		b.imports["google/protobuf/struct.proto"] = true
+		return "google.protobuf.Value", nil
+	}
+}
+
+// enum adds a nested enum for a set of string values
+func (b *builder) enum(msg *message, lockKey, name string, members []string) string {
+	msg.Enums = append(msg.Enums, b.newEnum(name, lockKey+"."+name, members))
+	return name
+}
+
+// newEnum builds an enum for a set of string values; lockKey is its dotted
+// name in the lock file
+func (b *builder) newEnum(name, lockKey string, members []string) *enum {
+	numbers := b.lock.enumNumbers(lockKey, members)
+
+	prefix := toUpperSnake(name)
+	e := &enum{
+		Name:     name,
+		Values:   []enumValue{{Name: prefix + "_UNSPECIFIED", Number: 0}},
+		Reserved: numbers.Reserved,
+	}
+	for _, m := range members {
+		e.Values = append(e.Values, enumValue{
+			Name:   prefix + "_" + toUpperSnake(m),
+			Number: numbers.Numbers[m],
+		})
+	}
+	return e
+}
+
+// writeMessage renders a message and its nested types
+func writeMessage(buf *bytes.Buffer, msg *message, indent string) {
+	writeDoc(buf, msg.Doc, indent)
+	fmt.Fprintf(buf, "%smessage %s {\n", indent, msg.Name)
+	inner := indent + "  "
+
+	if len(msg.Reserved) > 0 {
+		writeReserved(buf, msg.Reserved, inner)
+		buf.WriteString("\n")
+	}
+
+	for _, f := range msg.Fields {
+		writeDoc(buf, f.Doc, inner)
+		buf.WriteString(inner)
+		if f.Label != "" {
+			buf.WriteString(f.Label + " ")
+		}
+		fmt.Fprintf(buf, "%s %s = %d", f.Type, f.Name, f.Number)
+		if f.JSONName != "" {
+			fmt.Fprintf(buf, " [json_name = %q]", f.JSONName)
+		}
+		buf.WriteString(";\n")
+	}
+
+	for _, e := range msg.Enums {
+		buf.WriteString("\n")
+		writeEnum(buf, e, inner)
+	}
+
+	for _, nested := range msg.Messages {
+		buf.WriteString("\n")
+		writeMessage(buf, nested, inner)
+	}
+
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// writeEnum renders an enum
+func writeEnum(buf *bytes.Buffer, e *enum, indent string) {
+	writeDoc(buf, e.Doc, indent)
+	fmt.Fprintf(buf, "%senum %s {\n", indent, e.Name)
+	if len(e.Reserved) > 0 {
+		writeReserved(buf, e.Reserved, indent+"  ")
+	}
+	for _, v := range e.Values {
+		fmt.Fprintf(buf, "%s  %s = %d;\n", indent, v.Name, v.Number)
+	}
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// writeReserved renders reserved numbers and names, sorted by number
+func writeReserved(buf *bytes.Buffer, reserved map[string]int, indent string) {
+	names := make([]string, 0, len(reserved))
+	for name := range reserved {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return reserved[names[i]] < reserved[names[j]] })
+
+	numbers := make([]string, 0, len(names))
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		numbers = append(numbers, fmt.Sprint(reserved[name]))
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+	fmt.Fprintf(buf, "%sreserved %s;\n", indent, strings.Join(numbers, ", "))
+	fmt.Fprintf(buf, "%sreserved %s;\n", indent, strings.Join(quoted, ", "))
+}
+
+// writeDoc renders a doc comment
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(buf, "%s// %s\n", indent, line)
+	}
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// packageName returns the proto package from the "package" option or the
+// project name
+func packageName(ctx *generator.Context) string {
+	if pkg := ctx.GetStringOption("package", ""); pkg != "" {
+		return pkg
+	}
+	name := "platosl"
+	if ctx.Config != nil && ctx.Config.Name != "" {
+		name = ctx.Config.Name
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// toMessageName converts a definition or field name to a message name
+func toMessageName(name string) string {
+	name = strings.TrimPrefix(name, "#")
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toSnakeCase converts a field label to a proto field name
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && runes[i-1] != '_') {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// toUpperSnake converts a name or enum value to UPPER_SNAKE_CASE
+func toUpperSnake(name string) string {
+	return strings.ToUpper(toSnakeCase(name))
+}
+
+// lowerCamel is the JSON name protoc derives from a snake_case field name
+func lowerCamel(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("proto", "protobuf")
+}
@@ -0,0 +1,158 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLockFile is the numbering lock used when no lockFile option is set.
+// It belongs next to platosl.yaml and should be committed.
+const DefaultLockFile = "platosl.proto.lock"
+
+// lockVersion is the format version written to new lock files
+const lockVersion = 1
+
+// Lock persists field and enum value numbers across runs. Numbers are never
+// reused: a name dropped from the schema keeps its number in Reserved, is
+// emitted as a reserved field, and gets the same number back if it returns.
+type Lock struct {
+	Version  int                    `json:"version"`
+	Messages map[string]*NumberLock `json:"messages"`
+	Enums    map[string]*NumberLock `json:"enums,omitempty"`
+
+	changed bool
+}
+
+// NumberLock holds the numbers of one message or enum
+type NumberLock struct {
+	Numbers  map[string]int `json:"numbers"`
+	Reserved map[string]int `json:"reserved,omitempty"`
+}
+
+// LoadLock reads a lock file; a missing file yields an empty lock
+func LoadLock(path string) (*Lock, error) {
+	lock := &Lock{
+		Version:  lockVersion,
+		Messages: make(map[string]*NumberLock),
+		Enums:    make(map[string]*NumberLock),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if lock.Version > lockVersion {
+		return nil, fmt.Errorf("lock file %s has version %d; upgrade platosl to use it", path, lock.Version)
+	}
+	if lock.Messages == nil {
+		lock.Messages = make(map[string]*NumberLock)
+	}
+	if lock.Enums == nil {
+		lock.Enums = make(map[string]*NumberLock)
+	}
+	return lock, nil
+}
+
+// Changed reports whether numbering differs from the loaded lock file
+func (l *Lock) Changed() bool {
+	return l.changed
+}
+
+// Save writes the lock file. Map keys are sorted by encoding/json, so the
+// file is stable across runs.
+func (l *Lock) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lock file: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lock file %s: %w", path, err)
+	}
+	return nil
+}
+
+// messageNumbers assigns field numbers for a message, in schema order
+func (l *Lock) messageNumbers(message string, fields []string) *NumberLock {
+	return l.assign(l.Messages, message, fields, 1)
+}
+
+// enumNumbers assigns enum value numbers; 0 is the UNSPECIFIED value
+func (l *Lock) enumNumbers(enum string, values []string) *NumberLock {
+	return l.assign(l.Enums, enum, values, 1)
+}
+
+// assign keeps existing numbers, restores reserved ones for returning names,
+// numbers new names after the highest number ever used, and reserves the
+// numbers of dropped names
+func (l *Lock) assign(locks map[string]*NumberLock, key string, names []string, first int) *NumberLock {
+	entry := locks[key]
+	if entry == nil {
+		entry = &NumberLock{Numbers: make(map[string]int)}
+		locks[key] = entry
+		l.changed = true
+	}
+	if entry.Numbers == nil {
+		entry.Numbers = make(map[string]int)
+	}
+
+	next := first
+	for _, n := range entry.Numbers {
+		if n >= next {
+			next = n + 1
+		}
+	}
+	for _, n := range entry.Reserved {
+		if n >= next {
+			next = n + 1
+		}
+	}
+
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+		if _, ok := entry.Numbers[name]; ok {
+			continue
+		}
+		if n, ok := entry.Reserved[name]; ok {
+			entry.Numbers[name] = n
+			delete(entry.Reserved, name)
+			l.changed = true
+			continue
+		}
+
+		// Skip the range reserved by the protobuf implementation
+		if next >= 19000 && next <= 19999 {
+			next = 20000
+		}
+		entry.Numbers[name] = next
+		next++
+		l.changed = true
+	}
+
+	for name, n := range entry.Numbers {
+		if present[name] {
+			continue
+		}
+		if entry.Reserved == nil {
+			entry.Reserved = make(map[string]int)
+		}
+		entry.Reserved[name] = n
+		delete(entry.Numbers, name)
+		l.changed = true
+	}
+
+	return entry
+}
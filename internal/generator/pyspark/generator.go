@@ -0,0 +1,406 @@
+package pyspark
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates PySpark StructType schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new PySpark generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "pyspark"
+}
+
+// dataType is a Spark SQL data type, named after its pyspark class without
+// the Type suffix, e.g. Long or Struct
+type dataType struct {
+	name         string
+	elem         *dataType // array element or map value
+	elemNullable bool
+	fields       []field // struct fields
+	precision    int     // decimal
+	scale        int     // decimal
+}
+
+// field is a StructField
+type field struct {
+	name     string
+	docs     platoCue.Docs
+	typ      *dataType
+	nullable bool
+}
+
+// schema is the StructType of a struct definition
+type schema struct {
+	def    string
+	ident  string
+	docs   platoCue.Docs
+	fields []field
+}
+
+// scalars maps the types @spark may set, besides decimal, to their class.
+// Both the pyspark names and the Spark SQL names are accepted.
+var scalars = map[string]string{
+	"string": "String", "binary": "Binary", "boolean": "Boolean",
+	"byte": "Byte", "tinyint": "Byte",
+	"short": "Short", "smallint": "Short",
+	"integer": "Integer", "int": "Integer",
+	"long": "Long", "bigint": "Long",
+	"float": "Float", "double": "Double",
+	"date": "Date", "timestamp": "Timestamp", "timestamp_ntz": "TimestampNTZ",
+}
+
+// builder maps definitions to Spark types. Spark has no named types, so
+// definitions are inlined where they are referenced; a reference back to a
+// definition being inlined is a JSON-encoded string.
+type builder struct {
+	defs     map[string]cue.Value
+	visiting map[string]bool
+}
+
+// Generate generates a StructType per struct definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var names []string
+	for name, val := range defs {
+		if isRecord(val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	used := make(map[string]bool)
+	var schemas []*schema
+	for _, name := range names {
+		b := &builder{defs: defs, visiting: map[string]bool{name: true}}
+		fields, err := b.fields(defs[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		schemas = append(schemas, &schema{
+			def:    strings.TrimPrefix(name, "#"),
+			ident:  strings.ToUpper(generator.UniqueName(used, toSnakeCase(name), "_")) + "_SCHEMA",
+			docs:   platoCue.DocsOf(defs[name]),
+			fields: fields,
+		})
+	}
+
+	return write(schemas), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// fields maps the fields of a struct to StructFields. Optional fields and
+// fields that may be null are nullable.
+func (b *builder) fields(val cue.Value) ([]field, error) {
+	var fields []field
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		typ, nullable, err := b.typeOf(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		fields = append(fields, field{
+			name:     name,
+			docs:     platoCue.DocsOf(iter.Value()),
+			typ:      typ,
+			nullable: nullable || iter.IsOptional(),
+		})
+	}
+	return fields, nil
+}
+
+// typeOf maps a value to a Spark type, reporting whether it may be null
+func (b *builder) typeOf(val cue.Value) (*dataType, bool, error) {
+	if attr, ok := platoCue.GetAttr(val, "spark"); ok {
+		_, nullable := platoCue.StripNull(val)
+		typ, err := attrType(attr)
+		return typ, nullable, err
+	}
+
+	if alts, nullable := platoCue.KindAlternatives(val); alts != nil {
+		return &dataType{name: "String"}, nullable, nil
+	}
+	val, nullable := platoCue.StripNull(val)
+
+	if name, ok := reference(val); ok {
+		if def, ok := b.defs[name]; ok && isRecord(def) {
+			if b.visiting[name] {
+				return &dataType{name: "String"}, nullable, nil
+			}
+			b.visiting[name] = true
+			defer delete(b.visiting, name)
+			fields, err := b.fields(def)
+			if err != nil {
+				return nil, false, err
+			}
+			return &dataType{name: "Struct", fields: fields}, nullable, nil
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		return &dataType{name: "String"}, nullable, nil
+	case kind == cue.BytesKind:
+		return &dataType{name: "Binary"}, nullable, nil
+	case kind == cue.IntKind:
+		return &dataType{name: "Long"}, nullable, nil
+	case kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0:
+		return &dataType{name: "Double"}, nullable, nil
+	case kind == cue.BoolKind:
+		return &dataType{name: "Boolean"}, nullable, nil
+	case kind == cue.ListKind:
+		elem := typed.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return &dataType{name: "Array", elem: &dataType{name: "String"}, elemNullable: true}, nullable, nil
+		}
+		item, itemNullable, err := b.typeOf(elem)
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "Array", elem: item, elemNullable: itemNullable}, nullable, nil
+	case kind == cue.StructKind && platoCue.IsMap(typed):
+		value, valueNullable, err := b.typeOf(typed.LookupPath(cue.MakePath(cue.AnyString)))
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "Map", elem: value, elemNullable: valueNullable}, nullable, nil
+	case kind == cue.StructKind && platoCue.HasFields(typed):
+		fields, err := b.fields(typed)
+		if err != nil {
+			return nil, false, err
+		}
+		return &dataType{name: "Struct", fields: fields}, nullable, nil
+	}
+	return &dataType{name: "String"}, nullable, nil
+}
+
+// attrType reads the type of a @spark attribute, e.g. @spark(timestamp)
+// or @spark(decimal, precision=12, scale=2)
+func attrType(attr platoCue.Attr) (*dataType, error) {
+	name := strings.ToLower(attr.Arg(0))
+	if name == "decimal" {
+		precision, err := strconv.Atoi(attr.Param("precision"))
+		if err != nil || precision < 1 || precision > 38 {
+			return nil, fmt.Errorf("@spark(decimal) needs a precision from 1 to 38")
+		}
+		scale := 0
+		if s := attr.Param("scale"); s != "" {
+			if scale, err = strconv.Atoi(s); err != nil || scale < 0 || scale > precision {
+				return nil, fmt.Errorf("@spark(decimal) scale must be from 0 to the precision")
+			}
+		}
+		return &dataType{name: "Decimal", precision: precision, scale: scale}, nil
+	}
+	class, ok := scalars[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown Spark type %q in @spark", attr.Arg(0))
+	}
+	return &dataType{name: class}, nil
+}
+
+// write renders the schemas as a Python module, with a SCHEMAS dict by
+// definition name
+func write(schemas []*schema) []byte {
+	var body bytes.Buffer
+	classes := map[string]bool{"StructType": true, "StructField": true}
+	for _, s := range schemas {
+		body.WriteString("\n")
+		if s.docs.Description != "" {
+			for _, line := range strings.Split(s.docs.Description, "\n") {
+				body.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+		fmt.Fprintf(&body, "%s = StructType(\n    [\n", s.ident)
+		for _, f := range s.fields {
+			fmt.Fprintf(&body, "        %s,\n", pyField(f, "        ", classes))
+		}
+		body.WriteString("    ]\n)\n")
+	}
+
+	var names []string
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("from pyspark.sql.types import (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "    %s,\n", name)
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	buf.WriteString("\nSCHEMAS = {\n")
+	for _, s := range schemas {
+		fmt.Fprintf(&buf, "    %s: %s,\n", strconv.Quote(s.def), s.ident)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// pyField renders a StructField call, recording the classes it uses;
+// nested struct fields go on lines of their own, indented from indent
+func pyField(f field, indent string, classes map[string]bool) string {
+	args := []string{strconv.Quote(f.name), pyType(f.typ, indent, classes), pyBool(f.nullable)}
+	if meta := pyMetadata(f.docs); meta != "" {
+		args = append(args, meta)
+	}
+	return "StructField(" + strings.Join(args, ", ") + ")"
+}
+
+// pyType renders the pyspark expression of a data type
+func pyType(t *dataType, indent string, classes map[string]bool) string {
+	class := t.name + "Type"
+	classes[class] = true
+	switch t.name {
+	case "Struct":
+		var b strings.Builder
+		b.WriteString("StructType(\n" + indent + "    [\n")
+		for _, f := range t.fields {
+			fmt.Fprintf(&b, "%s        %s,\n", indent, pyField(f, indent+"        ", classes))
+		}
+		b.WriteString(indent + "    ]\n" + indent + ")")
+		return b.String()
+	case "Array":
+		return fmt.Sprintf("ArrayType(%s, %s)", pyType(t.elem, indent, classes), pyBool(t.elemNullable))
+	case "Map":
+		classes["StringType"] = true
+		return fmt.Sprintf("MapType(StringType(), %s, %s)", pyType(t.elem, indent, classes), pyBool(t.elemNullable))
+	case "Decimal":
+		return fmt.Sprintf("DecimalType(%d, %d)", t.precision, t.scale)
+	}
+	return class + "()"
+}
+
+func pyBool(b bool) string {
+	if b {
+		return "True"
+	}
+	return "False"
+}
+
+// pyMetadata renders the title and description as a metadata dict. Spark
+// shows the comment key as the column comment, e.g. in DESCRIBE TABLE.
+func pyMetadata(docs platoCue.Docs) string {
+	var entries []string
+	if docs.Title != "" {
+		entries = append(entries, `"title": `+strconv.Quote(docs.Title))
+	}
+	if docs.Description != "" {
+		entries = append(entries, `"comment": `+strconv.Quote(docs.Description))
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// isRecord reports whether a definition is a struct with fields, which
+// becomes a schema or a struct type
+func isRecord(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+// toSnakeCase converts a definition name to a schema name, e.g.
+// #OrderItem to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "s" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("spark", "pyspark")
+}
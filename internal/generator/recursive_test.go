@@ -0,0 +1,76 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// recursiveSource has a self-referencing definition and a cycle of two,
+// one member of which has a field with a default
+const recursiveSource = `
+#Node: {
+	name:      string
+	children?: [...#Node]
+	next:      #Node | null
+}
+#Comment: {text: string, replies: [...#Reply]}
+#Reply: {text: string, parent: #Comment, pinned: *false | bool}
+`
+
+// TestRecursiveTypes checks that generators whose schema types are inferred
+// write out the type of recursive definitions, which TypeScript cannot
+// infer
+func TestRecursiveTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     []string
+		unwanted []string
+	}{
+		{
+			name: "valibot",
+			want: []string{
+				"export type Node = {\n  name: string;\n  children?: Node[];\n  next: Node | null;\n};",
+				"export const NodeSchema: v.GenericSchema<Node> = v.object({",
+				"export const CommentSchema: v.GenericSchema<Comment> = v.object({",
+				"export type Reply = {\n  text: string;\n  parent: Comment;\n  pinned: boolean;\n};",
+				"export const ReplySchema: v.GenericSchema<unknown, Reply> = v.object({",
+			},
+			unwanted: []string{"v.GenericSchema =", "export type Node = v.InferOutput"},
+		},
+		{
+			name: "effect",
+			want: []string{
+				"export type Node = {\n  readonly name: string;\n  readonly children?: ReadonlyArray<Node>;\n  readonly next: Node | null;\n};",
+				"export type NodeEncoded = {\n  readonly name: string;\n  readonly children?: ReadonlyArray<NodeEncoded>;\n  readonly next: NodeEncoded | null;\n};",
+				"export const Node: Schema.Schema<Node, NodeEncoded> = Schema.Struct({",
+				"Schema.suspend((): Schema.Schema<Node, NodeEncoded> => Node)",
+				"export type ReplyEncoded = {\n  readonly text: string;\n  readonly parent: CommentEncoded;\n  readonly pinned?: boolean;\n};",
+				"export const Reply: Schema.Schema<Reply, ReplyEncoded> = Schema.Struct({",
+			},
+			unwanted: []string{"Schema.Schema<any>", "export type Node = typeof Node.Type"},
+		},
+		{
+			name: "yup",
+			want: []string{
+				"export type Node = {\n  name: string;\n  children?: Node[];\n  next: Node | null;\n};",
+				"export const NodeSchema: yup.ObjectSchema<Node> = yup.object({",
+				"export type Reply = {\n  text: string;\n  parent: Comment;\n  pinned: boolean;\n};",
+				"export const ReplySchema: yup.ObjectSchema<Reply> = yup.object({",
+			},
+			unwanted: []string{"yup.Schema<any>", "export type Node = yup.InferType"},
+		},
+	}
+	for _, tt := range tests {
+		out := generateSource(t, tt.name, recursiveSource)
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, out)
+			}
+		}
+		for _, unwanted := range tt.unwanted {
+			if strings.Contains(out, unwanted) {
+				t.Errorf("%s: output contains %q:\n%s", tt.name, unwanted, out)
+			}
+		}
+	}
+}
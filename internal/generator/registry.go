@@ -2,6 +2,8 @@ package generator
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -9,6 +11,7 @@ import (
 type Registry struct {
 	mu         sync.RWMutex
 	generators map[string]Generator
+	aliases    map[string]string
 }
 
 var (
@@ -20,6 +23,7 @@ var (
 func NewRegistry() *Registry {
 	return &Registry{
 		generators: make(map[string]Generator),
+		aliases:    make(map[string]string),
 	}
 }
 
@@ -37,19 +41,113 @@ func (r *Registry) Register(gen Generator) error {
 	return nil
 }
 
-// Get retrieves a generator by name
+// RegisterAlias registers a short name for a generator, e.g. ts for
+// typescript
+func (r *Registry) RegisterAlias(alias, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.generators[alias]; exists {
+		return fmt.Errorf("alias %s is the name of a generator", alias)
+	}
+	if existing, exists := r.aliases[alias]; exists && existing != name {
+		return fmt.Errorf("alias %s already registered for %s", alias, existing)
+	}
+
+	r.aliases[alias] = name
+	return nil
+}
+
+// Resolve returns the name of the generator a name or alias stands for.
+// Unknown names are returned unchanged.
+func (r *Registry) Resolve(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if target, ok := r.aliases[name]; ok {
+		return target
+	}
+	return name
+}
+
+// Aliases returns the aliases of a generator, sorted
+func (r *Registry) Aliases(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var aliases []string
+	for alias, target := range r.aliases {
+		if target == name {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Get retrieves a generator by name or alias. The error for an unknown
+// name suggests the closest generator, e.g. "unknown generator 'typscript',
+// did you mean 'typescript'?"
 func (r *Registry) Get(name string) (Generator, error) {
+	name = r.Resolve(name)
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	gen, exists := r.generators[name]
 	if !exists {
-		return nil, fmt.Errorf("generator %s not found", name)
+		if suggestion := r.suggest(name); suggestion != "" {
+			return nil, fmt.Errorf("unknown generator '%s', did you mean '%s'?", name, suggestion)
+		}
+		return nil, fmt.Errorf("unknown generator '%s'", name)
 	}
 
 	return gen, nil
 }
 
+// suggest returns the generator whose name or alias is closest to name,
+// if any is close enough to be a typo. The caller holds the lock.
+func (r *Registry) suggest(name string) string {
+	best, bestDistance := "", 0
+	consider := func(candidate, target string) {
+		d := distance(strings.ToLower(name), candidate)
+		// Allow a typo per three characters, and prefixes of two or more
+		ok := d <= max(1, len(candidate)/3) || len(name) >= 2 && strings.HasPrefix(candidate, strings.ToLower(name))
+		if ok && (best == "" || d < bestDistance || d == bestDistance && target < best) {
+			best, bestDistance = target, d
+		}
+	}
+	for candidate := range r.generators {
+		consider(candidate, candidate)
+	}
+	for alias, target := range r.aliases {
+		consider(alias, target)
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between two strings
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
 // List returns all registered generator names
 func (r *Registry) List() []string {
 	r.mu.RLock()
@@ -67,6 +165,21 @@ func Register(gen Generator) error {
 	return DefaultRegistry.Register(gen)
 }
 
+// RegisterAlias is a convenience function that registers an alias in the default registry
+func RegisterAlias(alias, name string) error {
+	return DefaultRegistry.RegisterAlias(alias, name)
+}
+
+// Resolve is a convenience function that resolves an alias in the default registry
+func Resolve(name string) string {
+	return DefaultRegistry.Resolve(name)
+}
+
+// Aliases is a convenience function that lists the aliases of a generator in the default registry
+func Aliases(name string) []string {
+	return DefaultRegistry.Aliases(name)
+}
+
 // Get is a convenience function that retrieves a generator from the default registry
 func Get(name string) (Generator, error) {
 	return DefaultRegistry.Get(name)
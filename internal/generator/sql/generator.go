@@ -0,0 +1,371 @@
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Dialects lists the supported SQL dialects
+var Dialects = []string{"postgres", "mysql", "sqlite"}
+
+// dialect holds the column types and quoting of a SQL dialect
+type dialect struct {
+	types map[string]string
+	quote func(name string) string
+	// enum returns the column type for a string enum, or "" to use a
+	// string column with a CHECK constraint
+	enum func(values []string) string
+}
+
+var dialects = map[string]dialect{
+	"postgres": {
+		types: map[string]string{
+			"string": "TEXT",
+			"int":    "BIGINT",
+			"float":  "DOUBLE PRECISION",
+			"bool":   "BOOLEAN",
+			"bytes":  "BYTEA",
+			"json":   "JSONB",
+		},
+		quote: func(name string) string { return `"` + strings.ReplaceAll(name, `"`, `""`) + `"` },
+	},
+	"mysql": {
+		types: map[string]string{
+			"string": "VARCHAR(255)",
+			"int":    "BIGINT",
+			"float":  "DOUBLE",
+			"bool":   "BOOLEAN",
+			"bytes":  "BLOB",
+			"json":   "JSON",
+		},
+		quote: func(name string) string { return "`" + strings.ReplaceAll(name, "`", "``") + "`" },
+		enum: func(values []string) string {
+			quoted := make([]string, len(values))
+			for i, v := range values {
+				quoted[i] = quoteString(v)
+			}
+			return "ENUM(" + strings.Join(quoted, ", ") + ")"
+		},
+	},
+	"sqlite": {
+		types: map[string]string{
+			"string": "TEXT",
+			"int":    "INTEGER",
+			"float":  "REAL",
+			"bool":   "BOOLEAN",
+			"bytes":  "BLOB",
+			"json":   "TEXT",
+		},
+		quote: func(name string) string { return `"` + strings.ReplaceAll(name, `"`, `""`) + `"` },
+	},
+}
+
+// Generator generates SQL CREATE TABLE statements from CUE
+type Generator struct{}
+
+// NewGenerator creates a new SQL generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "sql"
+}
+
+// column is a table column
+type column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Default  string
+	Check    string
+	Doc      string
+}
+
+// Generate generates one CREATE TABLE statement per definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	dialectName := ctx.GetStringOption("dialect", "postgres")
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect %q (expected %s)", dialectName, strings.Join(Dialects, ", "))
+	}
+	overrides := typeOverrides(ctx)
+
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	// Unions of structs have no table; columns of a union are json. Enums
+	// and other scalars have no table either; columns of them take their
+	// type.
+	var names []string
+	for name, val := range defs {
+		if !platoCue.IsUnion(val) && val.IncompleteKind() == cue.StructKind {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("-- Generated by PlatoSL\n")
+	buf.WriteString("-- DO NOT EDIT - This file is auto-generated\n")
+	fmt.Fprintf(&buf, "-- Dialect: %s\n", dialectName)
+
+	for _, name := range names {
+		table := toSnakeCase(strings.TrimPrefix(name, "#"))
+		columns, err := tableColumns(d, defs[name], strings.TrimPrefix(name, "#"), overrides)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		buf.WriteString("\n")
+		writeComment(&buf, platoCue.Description(defs[name]), "")
+		fmt.Fprintf(&buf, "CREATE TABLE %s (\n", d.quote(table))
+		for i, col := range columns {
+			writeComment(&buf, col.Doc, "  ")
+			fmt.Fprintf(&buf, "  %s %s", d.quote(col.Name), col.Type)
+			if !col.Nullable {
+				buf.WriteString(" NOT NULL")
+			}
+			if col.Default != "" {
+				fmt.Fprintf(&buf, " DEFAULT %s", col.Default)
+			}
+			if col.Name == "id" {
+				buf.WriteString(" PRIMARY KEY")
+			}
+			if col.Check != "" {
+				fmt.Fprintf(&buf, " CHECK (%s)", col.Check)
+			}
+			if i < len(columns)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(");\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	if dialect := ctx.GetStringOption("dialect", "postgres"); dialects[dialect].types == nil {
+		return fmt.Errorf("unknown dialect %q (expected %s)", dialect, strings.Join(Dialects, ", "))
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// tableColumns maps the fields of a definition to columns. Optional fields
+// and fields that allow null are nullable.
+func tableColumns(d dialect, val cue.Value, table string, overrides map[string]string) ([]column, error) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []column
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal, nullable := platoCue.StripNull(iter.Value())
+
+		col := column{
+			Name:     toSnakeCase(label),
+			Nullable: nullable || iter.IsOptional(),
+			Doc:      platoCue.Description(iter.Value()),
+		}
+
+		kind, enum := typeKind(fieldVal)
+		switch {
+		case overrides[table+"."+label] != "":
+			col.Type = overrides[table+"."+label]
+		case overrides[kind] != "":
+			col.Type = overrides[kind]
+		case kind == "enum" && d.enum != nil:
+			col.Type = d.enum(enum)
+		case kind == "enum":
+			col.Type = d.types["string"]
+		default:
+			col.Type = d.types[kind]
+		}
+
+		// String enums without a native type are checked, unless overridden
+		if kind == "enum" && d.enum == nil && overrides[table+"."+label] == "" && overrides["enum"] == "" {
+			quoted := make([]string, len(enum))
+			for i, v := range enum {
+				quoted[i] = quoteString(v)
+			}
+			col.Check = fmt.Sprintf("%s IN (%s)", d.quote(col.Name), strings.Join(quoted, ", "))
+		}
+
+		if def, ok := fieldVal.Default(); ok && def.IsConcrete() {
+			col.Default = literal(def)
+		}
+
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// typeKind classifies a field value: string, int, float, bool, bytes,
+// enum (with its values) or json for lists, structs and anything else
+func typeKind(val cue.Value) (string, []string) {
+	// Fields of an enum definition are checked against its values too
+	if values := stringEnum(cue.Dereference(val)); len(values) > 1 {
+		return "enum", values
+	}
+
+	switch val.IncompleteKind() {
+	case cue.StringKind:
+		return "string", nil
+	case cue.IntKind:
+		return "int", nil
+	case cue.FloatKind, cue.NumberKind:
+		return "float", nil
+	case cue.BoolKind:
+		return "bool", nil
+	case cue.BytesKind:
+		return "bytes", nil
+	default:
+		return "json", nil
+	}
+}
+
+// typeOverrides reads the types option, which maps a type kind (string, int,
+// float, bool, bytes, enum, json) or a Table.field path to a column type
+func typeOverrides(ctx *generator.Context) map[string]string {
+	overrides := make(map[string]string)
+
+	raw, ok := ctx.GetOption("types")
+	if !ok {
+		return overrides
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return overrides
+	}
+
+	for key, v := range m {
+		if s, ok := v.(string); ok {
+			overrides[strings.TrimPrefix(key, "#")] = s
+		}
+	}
+	return overrides
+}
+
+// stringEnum returns the members of a disjunction of string literals
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		members = append(members, s)
+	}
+	return members
+}
+
+// literal formats a concrete scalar as a SQL literal
+func literal(val cue.Value) string {
+	switch val.Kind() {
+	case cue.StringKind:
+		s, _ := val.String()
+		return quoteString(s)
+	case cue.BoolKind:
+		b, _ := val.Bool()
+		if b {
+			return "TRUE"
+		}
+		return "FALSE"
+	case cue.IntKind, cue.FloatKind, cue.NumberKind:
+		f, err := val.Float64()
+		if err != nil {
+			return ""
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// quoteString quotes a SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// writeComment writes a doc comment as SQL line comments
+func writeComment(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(buf, "%s-- %s\n", indent, line)
+	}
+}
+
+// toSnakeCase converts camelCase and PascalCase names to snake_case
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		if r == '-' || r == ' ' {
+			b.WriteRune('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func init() {
+	generator.Register(NewGenerator())
+}
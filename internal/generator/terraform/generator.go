@@ -0,0 +1,601 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates Terraform variable declarations from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Terraform generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "terraform"
+}
+
+// identifier matches attribute names that need no quotes, and traversable
+// those that can be accessed with a dot, as a dash would read as a minus
+var (
+	identifier  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	traversable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// variable is a variable block of a struct definition
+type variable struct {
+	name        string
+	description string
+	typ         string
+	sensitive   bool
+	validations []validation
+}
+
+// validation is a validation block: a condition on the variable, and the
+// message shown when it does not hold
+type validation struct {
+	condition string
+	message   string
+}
+
+// attribute is an attribute of an object type
+type attribute struct {
+	doc   string
+	key   string
+	value string // may span lines, indented relative to the attribute
+}
+
+// renderer builds the variable of a definition. Terraform has no named
+// types, so referenced definitions are inlined; a definition that refers
+// back to itself is typed any from there on.
+type renderer struct {
+	defs      map[string]cue.Value
+	visiting  map[string]bool
+	validate  bool
+	sensitive bool
+	checks    []validation
+}
+
+// Generate generates a variable per struct definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var names []string
+	for name, val := range defs {
+		if isObject(val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	used := make(map[string]bool)
+	var vars []*variable
+	for _, name := range names {
+		r := &renderer{
+			defs:     defs,
+			visiting: map[string]bool{name: true},
+			validate: ctx.GetBoolOption("validations", true),
+		}
+		v := &variable{
+			name:        generator.UniqueName(used, toSnakeCase(name), "_"),
+			description: platoCue.Description(defs[name]),
+		}
+		v.typ = r.typeOf(defs[name])
+		if r.validate {
+			r.validations(defs[name], "var."+v.name, v.name, 0)
+		}
+		v.sensitive = r.sensitive
+		v.validations = r.checks
+		vars = append(vars, v)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by PlatoSL\n")
+	buf.WriteString("# DO NOT EDIT - This file is auto-generated\n")
+	for _, v := range vars {
+		buf.WriteString("\n")
+		writeVariable(&buf, v)
+	}
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// isObject reports whether a definition is a struct with fields, which
+// becomes a variable
+func isObject(val cue.Value) bool {
+	return val.IncompleteKind() == cue.StructKind && platoCue.HasFields(val) && !platoCue.IsMap(val)
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// typeOf maps a value to a Terraform type constraint. Null branches are
+// dropped, as every Terraform value may be null.
+func (r *renderer) typeOf(val cue.Value) string {
+	if platoCue.HasAttr(val, "sensitive") || platoCue.HasAttr(val, "pii") {
+		r.sensitive = true
+	}
+	if _, ok := platoCue.Alternatives(val); ok {
+		return "any"
+	}
+	val, _ = platoCue.StripNull(val)
+
+	if name, ok := reference(val); ok {
+		if def, ok := r.defs[name]; ok && isObject(def) {
+			if r.visiting[name] {
+				return "any"
+			}
+			r.visiting[name] = true
+			defer delete(r.visiting, name)
+			return r.object(def)
+		}
+	}
+
+	val, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind, kind == cue.BytesKind:
+		return "string"
+	case kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0:
+		return "number"
+	case kind == cue.BoolKind:
+		return "bool"
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return "list(any)"
+		}
+		return "list(" + r.typeOf(elem) + ")"
+	case kind == cue.StructKind && platoCue.IsMap(val):
+		return "map(" + r.typeOf(val.LookupPath(cue.MakePath(cue.AnyString))) + ")"
+	case kind == cue.StructKind && platoCue.HasFields(val):
+		return r.object(val)
+	}
+	return "any"
+}
+
+// object renders an object type. Optional fields and fields with a default
+// are optional attributes, taking the default when it is omitted.
+func (r *renderer) object(val cue.Value) string {
+	var attrs []attribute
+	iter, _ := val.Fields(cue.Optional(true))
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		field := iter.Value()
+		typ := r.typeOf(field)
+		if def, ok := platoCue.ScalarDefault(field); ok {
+			typ = "optional(" + typ + ", " + hclValue(def) + ")"
+		} else if iter.IsOptional() {
+			typ = "optional(" + typ + ")"
+		}
+		attrs = append(attrs, attribute{
+			doc:   platoCue.Description(field),
+			key:   attributeName(iter.Selector().Unquoted()),
+			value: typ,
+		})
+	}
+
+	var b strings.Builder
+	b.WriteString("object({\n")
+	writeAttributes(&b, attrs, "  ")
+	b.WriteString("})")
+	return b.String()
+}
+
+// validations adds the validation blocks of a value reached by expr: enums,
+// bounds, lengths, patterns and whole numbers. Checks of optional and
+// nullable values only apply when they are set; checks of list items and
+// map values apply to each of them.
+func (r *renderer) validations(val cue.Value, expr, path string, depth int) {
+	if _, ok := platoCue.Alternatives(val); ok {
+		return
+	}
+	val, _ = platoCue.StripNull(val)
+
+	if name, ok := reference(val); ok {
+		if def, ok := r.defs[name]; ok && isObject(def) {
+			if r.visiting[name] {
+				return
+			}
+			r.visiting[name] = true
+			defer delete(r.visiting, name)
+			val = def
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StructKind && platoCue.IsMap(typed):
+		item := loopVariable(depth)
+		r.nested(typed.LookupPath(cue.MakePath(cue.AnyString)), item, path+"[*]", depth, func(cond string) string {
+			return fmt.Sprintf("alltrue([for %s in values(%s) : %s])", item, expr, cond)
+		})
+	case kind == cue.StructKind:
+		iter, err := typed.Fields(cue.Optional(true))
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			if iter.Selector().IsDefinition() {
+				continue
+			}
+			key := iter.Selector().Unquoted()
+			field := iter.Value()
+			fieldExpr := expr + "." + key
+			if !traversable.MatchString(key) {
+				fieldExpr = expr + "[" + hclString(key) + "]"
+			}
+			_, nullable := platoCue.StripNull(field)
+			_, hasDefault := platoCue.ScalarDefault(field)
+			if (iter.IsOptional() && !hasDefault) || nullable {
+				r.nested(field, fieldExpr, path+"."+key, depth, func(cond string) string {
+					return fmt.Sprintf("%s == null ? true : %s", fieldExpr, cond)
+				})
+			} else {
+				r.validations(field, fieldExpr, path+"."+key, depth)
+			}
+		}
+	case kind == cue.ListKind:
+		elem := typed.LookupPath(cue.MakePath(cue.AnyIndex))
+		if elem.Exists() {
+			item := loopVariable(depth)
+			r.nested(elem, item, path+"[*]", depth, func(cond string) string {
+				return fmt.Sprintf("alltrue([for %s in %s : %s])", item, expr, cond)
+			})
+		}
+		r.scalar(val, expr, path, "Items")
+	default:
+		r.scalar(val, expr, path, "")
+	}
+}
+
+// nested adds the validations of a value, wrapping each condition
+func (r *renderer) nested(val cue.Value, expr, path string, depth int, wrap func(string) string) {
+	outer := r.checks
+	r.checks = nil
+	r.validations(val, expr, path, depth+1)
+	for i := range r.checks {
+		r.checks[i].condition = wrap(r.checks[i].condition)
+	}
+	r.checks = append(outer, r.checks...)
+}
+
+// scalar adds the validations of the constraints of a value. suffix names
+// the length keywords of lists, e.g. minItems.
+func (r *renderer) scalar(val cue.Value, expr, path, suffix string) {
+	msgs := platoCue.ErrorMessagesOf(val)
+	add := func(keyword, condition, message string) {
+		if custom := msgs.For(keyword); custom != "" {
+			message = custom
+		} else {
+			message = "Invalid " + path + ": " + message + "."
+		}
+		r.checks = append(r.checks, validation{condition: condition, message: message})
+	}
+
+	if values := enumValues(val); len(values) > 0 {
+		var quoted, items []string
+		for _, v := range values {
+			quoted = append(quoted, hclValue(v))
+			var s string
+			if json.Unmarshal([]byte(v), &s) != nil {
+				s = v
+			}
+			items = append(items, s)
+		}
+		add("enum", fmt.Sprintf("contains([%s], %s)", strings.Join(quoted, ", "), expr),
+			"must be one of "+strings.Join(items, ", "))
+		return
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	bound := func(keyword string, limit *float64, op, text string) {
+		if limit != nil {
+			n := strconv.FormatFloat(*limit, 'f', -1, 64)
+			add(keyword, fmt.Sprintf("%s %s %s", expr, op, n), text+" "+n)
+		}
+	}
+	bound("minimum", c.Minimum, ">=", "must be at least")
+	bound("maximum", c.Maximum, "<=", "must be at most")
+	bound("exclusiveMinimum", c.ExclusiveMinimum, ">", "must be greater than")
+	bound("exclusiveMaximum", c.ExclusiveMaximum, "<", "must be less than")
+
+	unit := "character"
+	if suffix != "" {
+		unit = "item"
+	}
+	if c.MinLength != nil {
+		add("min"+or(suffix, "Length"), fmt.Sprintf("length(%s) >= %d", expr, *c.MinLength),
+			"must have at least "+count(*c.MinLength, unit))
+	}
+	if c.MaxLength != nil {
+		add("max"+or(suffix, "Length"), fmt.Sprintf("length(%s) <= %d", expr, *c.MaxLength),
+			"must have at most "+count(*c.MaxLength, unit))
+	}
+	for _, pattern := range c.Patterns {
+		add("pattern", fmt.Sprintf("can(regex(%s, %s))", hclString(pattern), expr), "must match "+pattern)
+	}
+
+	if suffix == "" && val.IncompleteKind() == cue.IntKind {
+		add("type", fmt.Sprintf("floor(%s) == %s", expr, expr), "must be a whole number")
+	}
+}
+
+// writeVariable writes a variable block
+func writeVariable(buf *bytes.Buffer, v *variable) {
+	fmt.Fprintf(buf, "variable %s {\n", hclString(v.name))
+	var attrs []attribute
+	if v.description != "" {
+		attrs = append(attrs, attribute{key: "description", value: hclString(v.description)})
+	}
+	attrs = append(attrs, attribute{key: "type", value: v.typ})
+	if v.sensitive {
+		attrs = append(attrs, attribute{key: "sensitive", value: "true"})
+	}
+	var b strings.Builder
+	writeAttributes(&b, attrs, "  ")
+	buf.WriteString(b.String())
+
+	for _, check := range v.validations {
+		buf.WriteString("\n  validation {\n")
+		b.Reset()
+		writeAttributes(&b, []attribute{
+			{key: "condition", value: check.condition},
+			{key: "error_message", value: hclString(check.message)},
+		}, "    ")
+		buf.WriteString(b.String())
+		buf.WriteString("  }\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// writeAttributes writes attributes like terraform fmt: the equals signs of
+// consecutive single-line attributes line up, and a comment or the end of a
+// multi-line value starts a new group
+func writeAttributes(b *strings.Builder, attrs []attribute, indent string) {
+	for start := 0; start < len(attrs); {
+		end := start + 1
+		for end < len(attrs) && attrs[end].doc == "" && !strings.Contains(attrs[end-1].value, "\n") {
+			end++
+		}
+		width := 0
+		for _, a := range attrs[start:end] {
+			if len(a.key) > width {
+				width = len(a.key)
+			}
+		}
+		for _, a := range attrs[start:end] {
+			for _, line := range strings.Split(a.doc, "\n") {
+				if line != "" {
+					fmt.Fprintf(b, "%s# %s\n", indent, line)
+				}
+			}
+			value := strings.ReplaceAll(a.value, "\n", "\n"+indent)
+			fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a.key, value)
+		}
+		start = end
+	}
+}
+
+// loopVariable names the item of a for expression at a nesting depth
+func loopVariable(depth int) string {
+	if depth == 0 {
+		return "item"
+	}
+	return fmt.Sprintf("item%d", depth+1)
+}
+
+// attributeName returns an object attribute name, quoted unless it is an
+// identifier
+func attributeName(key string) string {
+	if identifier.MatchString(key) {
+		return key
+	}
+	return hclString(key)
+}
+
+// hclString renders a quoted string; template sequences are escaped so the
+// string is taken literally
+func hclString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	quoted := strings.TrimSuffix(buf.String(), "\n")
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// hclValue renders a JSON value as an HCL expression. HCL object
+// constructors accept JSON's "key": value syntax, so only strings need
+// their template sequences escaped.
+func hclValue(data string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return data
+	}
+	var b strings.Builder
+	writeValue(&b, v)
+	return b.String()
+}
+
+func writeValue(b *strings.Builder, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		b.WriteString(hclString(v))
+	case []interface{}:
+		b.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeValue(b, item)
+		}
+		b.WriteString("]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("{ ")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(b, "%s = ", attributeName(k))
+			writeValue(b, v[k])
+		}
+		b.WriteString(" }")
+	default:
+		data, _ := json.Marshal(v)
+		b.Write(data)
+	}
+}
+
+// count renders a number of things, e.g. 1 item or 3 items
+func count(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// or returns s, or def when s is empty
+func or(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// reference returns the definition a value refers to
+func reference(val cue.Value) (string, bool) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String(), true
+	}
+	return "", false
+}
+
+// enumValues returns the literals of a disjunction of strings, numbers or
+// booleans as JSON, following references to enum definitions
+func enumValues(val cue.Value) []string {
+	if _, ok := reference(val); ok {
+		val = cue.Dereference(val)
+	}
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		var argValues []string
+		if _, ok := reference(arg); ok {
+			arg = cue.Dereference(arg)
+		}
+		if op, _ := arg.Expr(); op == cue.OrOp {
+			if argValues = enumValues(arg); argValues == nil {
+				return nil
+			}
+		} else {
+			if !arg.IsConcrete() || arg.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) == 0 {
+				return nil
+			}
+			data, err := arg.MarshalJSON()
+			if err != nil {
+				return nil
+			}
+			argValues = []string{string(data)}
+		}
+		for _, v := range argValues {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// toSnakeCase converts a definition name to a variable name, e.g.
+// #OrderItem to order_item
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimPrefix(name, "#"))
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at an upper case letter after a lower case
+			// one, or at the last letter of an acronym, e.g. HTTPServer
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "v" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+	generator.RegisterAlias("tf", "terraform")
+}
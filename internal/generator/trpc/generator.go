@@ -0,0 +1,211 @@
+package trpc
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// Generator generates a typed tRPC router skeleton for @rpc definitions
+type Generator struct{}
+
+// NewGenerator creates a new tRPC generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "trpc"
+}
+
+// procedure is a tRPC procedure declared with @rpc on its input definition
+type procedure struct {
+	Name   string // procedure name, e.g. createOrder
+	Type   string // query, mutation or subscription
+	Input  string // input type name, e.g. CreateOrder
+	Output string // output type name, empty if unspecified
+}
+
+// Generate generates the router module
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	procs, err := extractProcedures(ctx.Value)
+	if err != nil {
+		return nil, err
+	}
+	if len(procs) == 0 {
+		return nil, fmt.Errorf("no procedures found: mark input definitions with @rpc(query|mutation|subscription)")
+	}
+
+	return generateRouter(ctx.GetStringOption("zodImport", "./schemas"), procs), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	_, err := extractProcedures(ctx.Value)
+	return err
+}
+
+// extractProcedures collects procedures from definitions marked with
+// @rpc(<type>, name=..., output=#Def). The definition itself is the input.
+func extractProcedures(val cue.Value) ([]procedure, error) {
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []procedure
+	seen := make(map[string]string)
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		attr, ok := platoCue.GetAttr(iter.Value(), "rpc")
+		if !ok {
+			continue
+		}
+		def := iter.Selector().String()
+
+		p := procedure{
+			Name:  attr.Param("name"),
+			Type:  attr.Arg(0),
+			Input: toTypescriptName(def),
+		}
+		if p.Name == "" {
+			p.Name = lowerFirst(p.Input)
+		}
+		if p.Type == "" {
+			p.Type = "query"
+		}
+		switch p.Type {
+		case "query", "mutation", "subscription":
+		default:
+			return nil, fmt.Errorf("%s: invalid @rpc type %q (expected query, mutation or subscription)", def, p.Type)
+		}
+
+		if output := attr.Param("output"); output != "" {
+			if _, err := platoCue.LookupDefinition(val, output); err != nil {
+				return nil, fmt.Errorf("%s: @rpc output: %w", def, err)
+			}
+			p.Output = toTypescriptName(output)
+		}
+
+		if other, dup := seen[p.Name]; dup {
+			return nil, fmt.Errorf("procedure %q is declared by both %s and %s", p.Name, other, def)
+		}
+		seen[p.Name] = def
+
+		procs = append(procs, p)
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].Name < procs[j].Name })
+	return procs, nil
+}
+
+// generateRouter renders the procedure table, handler interface and router
+func generateRouter(zodImport string, procs []procedure) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("import { initTRPC } from '@trpc/server';\n")
+	buf.WriteString("import type { z } from 'zod';\n")
+
+	// Schemas come from the Zod generator output
+	schemaSet := make(map[string]bool)
+	for _, p := range procs {
+		schemaSet[p.Input+"Schema"] = true
+		if p.Output != "" {
+			schemaSet[p.Output+"Schema"] = true
+		}
+	}
+	schemas := make([]string, 0, len(schemaSet))
+	for s := range schemaSet {
+		schemas = append(schemas, s)
+	}
+	sort.Strings(schemas)
+	fmt.Fprintf(&buf, "import { %s } from %s;\n\n", strings.Join(schemas, ", "), quote(zodImport))
+
+	// Procedure input/output schemas
+	buf.WriteString("/** Input and output schemas of each procedure. */\n")
+	buf.WriteString("export const procedures = {\n")
+	for _, p := range procs {
+		fmt.Fprintf(&buf, "  %s: { type: %s, input: %sSchema", p.Name, quote(p.Type), p.Input)
+		if p.Output != "" {
+			fmt.Fprintf(&buf, ", output: %sSchema", p.Output)
+		}
+		buf.WriteString(" },\n")
+	}
+	buf.WriteString("} as const;\n\n")
+
+	// Handler interface
+	buf.WriteString("/** Implementations of the procedures, called with validated input. */\n")
+	buf.WriteString("export interface Handlers<Ctx = object> {\n")
+	for _, p := range procs {
+		fmt.Fprintf(&buf, "  %s(input: z.infer<typeof %sSchema>, ctx: Ctx): Promise<%s>;\n", p.Name, p.Input, resultType(p))
+	}
+	buf.WriteString("}\n\n")
+
+	// Router skeleton
+	buf.WriteString("const t = initTRPC.create();\n\n")
+	buf.WriteString("/** Builds the router from procedure implementations. */\n")
+	buf.WriteString("export function createRouter(handlers: Handlers) {\n")
+	buf.WriteString("  return t.router({\n")
+	for _, p := range procs {
+		fmt.Fprintf(&buf, "    %s: t.procedure\n", p.Name)
+		fmt.Fprintf(&buf, "      .input(%sSchema)\n", p.Input)
+		if p.Output != "" {
+			fmt.Fprintf(&buf, "      .output(%sSchema)\n", p.Output)
+		}
+		fmt.Fprintf(&buf, "      .%s(({ input, ctx }) => handlers.%s(input, ctx)),\n", p.Type, p.Name)
+	}
+	buf.WriteString("  });\n")
+	buf.WriteString("}\n\n")
+	buf.WriteString("export type AppRouter = ReturnType<typeof createRouter>;\n")
+
+	return buf.Bytes()
+}
+
+// resultType returns the handler result type of a procedure
+func resultType(p procedure) string {
+	if p.Output == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("z.infer<typeof %sSchema>", p.Output)
+}
+
+// quote renders a single-quoted TypeScript string literal
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// toTypescriptName converts a CUE definition name to the name used by the
+// Zod generator
+func toTypescriptName(name string) string {
+	name = strings.TrimPrefix(name, "#")
+	if len(name) > 0 {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
+
+// lowerFirst lowercases the first letter of a name
+func lowerFirst(name string) string {
+	if len(name) > 0 {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	return name
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,415 @@
+package typebox
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates TypeBox schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new TypeBox generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "typebox"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of definitions. A definition referring to
+// itself is a Type.Recursive, and references to definitions declared
+// further down, which only happens in cycles, are Type.Ref by $id.
+type renderer struct {
+	names    map[string]string // TypeScript names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+	self     string            // definition rendered inside Type.Recursive
+	cloned   bool              // whether CloneType is used
+}
+
+// Generate generates a module with a TypeBox schema and a static type per
+// definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	graph := platoCue.NewRefGraph(defs)
+	order := graph.Order(cueNames)
+
+	var body bytes.Buffer
+	for _, name := range order {
+		val := defs[name]
+		tsName := r.names[name]
+
+		body.WriteString("\n")
+		writeDoc(&body, platoCue.Description(val), "")
+		var schema string
+		switch {
+		case slices.Contains(graph.Refs(name), name):
+			// Cyclic definitions have an $id for the Type.Ref of the others
+			r.self = name
+			schema = "Type.Recursive((Self) => " + r.schema(val, 0, nil) + ", { $id: " + jsgen.String(tsName) + " })"
+			r.self = ""
+		case graph.Recursive(name):
+			schema = r.schema(val, 0, []string{"$id: " + jsgen.String(tsName)})
+		default:
+			schema = r.schema(val, 0, nil)
+		}
+		fmt.Fprintf(&body, "export const %s = %s;\n", tsName, schema)
+		fmt.Fprintf(&body, "export type %s = Static<typeof %s>;\n", tsName, tsName)
+		r.declared[name] = true
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	if r.cloned {
+		buf.WriteString("import { CloneType, Type, type Static } from '@sinclair/typebox';\n")
+	} else {
+		buf.WriteString("import { Type, type Static } from '@sinclair/typebox';\n")
+	}
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// schema renders the TypeBox schema of a value at an indentation level,
+// with extra schema options such as a default or description
+func (r *renderer) schema(val cue.Value, indent int, extra []string) string {
+	if name, ok := r.reference(val); ok {
+		return r.withOptions(name, extra)
+	}
+	if values := jsgen.LiteralEnum(val); len(values) > 1 {
+		items := make([]string, len(values))
+		for i, v := range values {
+			items[i] = "Type.Literal(" + v + ")"
+		}
+		return "Type.Union([" + strings.Join(items, ", ") + "]" + trailing(extra) + ")"
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]string, len(alts))
+		for i, alt := range alts {
+			items[i] = r.schema(alt, indent, nil)
+		}
+		if nullable {
+			items = append(items, "Type.Null()")
+		}
+		return "Type.Union([" + strings.Join(items, ", ") + "]" + trailing(extra) + ")"
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if nullable {
+		inner := r.schema(val, indent, nil)
+		return "Type.Union([" + inner + ", Type.Null()]" + trailing(extra) + ")"
+	}
+	return r.base(val, indent, extra)
+}
+
+// reference renders a reference to a definition: Self inside its own
+// Type.Recursive, and a Type.Ref by $id when it is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	switch {
+	case path.String() == r.self:
+		return "Self", true
+	case !r.declared[path.String()]:
+		return "Type.Ref(" + jsgen.String(name) + ")", true
+	}
+	return name, true
+}
+
+// withOptions adds options to a referenced schema, which is shared, by
+// cloning it
+func (r *renderer) withOptions(schema string, extra []string) string {
+	if len(extra) == 0 {
+		return schema
+	}
+	r.cloned = true
+	return "CloneType(" + schema + trailing(extra) + ")"
+}
+
+// base renders a value by its kind, with its constraints as JSON Schema
+// options
+func (r *renderer) base(val cue.Value, indent int, extra []string) string {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok {
+			if val.Kind() == cue.NullKind {
+				return "Type.Null(" + options(extra) + ")"
+			}
+			return "Type.Literal(" + lit + trailing(extra) + ")"
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		opts := lengthOptions(c, "minLength", "maxLength")
+		if len(c.Patterns) == 1 {
+			opts = append(opts, "pattern: "+jsgen.String(c.Patterns[0]))
+		} else if len(c.Patterns) > 1 {
+			// JSON Schema has one pattern per schema; all must match
+			pattern := "^"
+			for _, p := range c.Patterns {
+				pattern += `(?=[\s\S]*(?:` + p + "))"
+			}
+			opts = append(opts, "pattern: "+jsgen.String(pattern))
+		}
+		opts = append(opts, errorMessages(msgs, "minLength", "maxLength", "pattern")...)
+		return "Type.String(" + options(append(opts, extra...)) + ")"
+	case kind == cue.IntKind:
+		opts := append(rangeOptions(c, msgs), extra...)
+		return "Type.Integer(" + options(opts) + ")"
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		opts := append(rangeOptions(c, msgs), extra...)
+		return "Type.Number(" + options(opts) + ")"
+	case kind == cue.BoolKind:
+		return "Type.Boolean(" + options(extra) + ")"
+	case kind == cue.NullKind:
+		return "Type.Null(" + options(extra) + ")"
+	case kind == cue.ListKind:
+		elem := "Type.Unknown()"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.schema(e, indent, nil)
+		}
+		opts := lengthOptions(c, "minItems", "maxItems")
+		opts = append(opts, errorMessages(msgs, "minItems", "maxItems")...)
+		return "Type.Array(" + elem + trailing(append(opts, extra...)) + ")"
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			elem := r.schema(val.LookupPath(cue.MakePath(cue.AnyString)), indent, nil)
+			return "Type.Record(Type.String(), " + elem + trailing(extra) + ")"
+		case !platoCue.HasFields(val):
+			return "Type.Record(Type.String(), Type.Unknown()" + trailing(extra) + ")"
+		default:
+			return r.object(val, indent, extra)
+		}
+	default:
+		return "Type.Unknown(" + options(extra) + ")"
+	}
+}
+
+// object renders a struct as Type.Object with one property per line.
+// Closed structs, such as definitions, reject additional properties.
+func (r *renderer) object(val cue.Value, indent int, extra []string) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "Type.Record(Type.String(), Type.Unknown()" + trailing(extra) + ")"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("Type.Object({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		// Field comments become descriptions, e.g. for Swagger
+		var opts []string
+		if doc := platoCue.Description(fieldVal); doc != "" {
+			opts = append(opts, "description: "+jsgen.String(doc))
+		}
+		def, hasDefault := platoCue.ScalarDefault(fieldVal)
+		if hasDefault {
+			opts = append(opts, "default: "+def)
+		}
+
+		schema := r.schema(fieldVal, indent+1, opts)
+		if hasDefault || iter.IsOptional() {
+			schema = "Type.Optional(" + schema + ")"
+		}
+		fmt.Fprintf(&b, "%s%s: %s,\n", pad, propertyName(label), schema)
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "}")
+
+	if !val.Allows(cue.AnyString) {
+		extra = append([]string{"additionalProperties: false"}, extra...)
+	}
+	b.WriteString(trailing(extra) + ")")
+	return b.String()
+}
+
+// options renders schema options as the only argument
+func options(opts []string) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(opts, ", ") + " }"
+}
+
+// trailing renders schema options as an argument after others
+func trailing(opts []string) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	return ", " + options(opts)
+}
+
+// lengthOptions renders the length keywords of strings and lists
+func lengthOptions(c platoCue.Constraints, minKeyword, maxKeyword string) []string {
+	var opts []string
+	if c.MinLength != nil {
+		opts = append(opts, minKeyword+": "+strconv.Itoa(*c.MinLength))
+	}
+	if c.MaxLength != nil {
+		opts = append(opts, maxKeyword+": "+strconv.Itoa(*c.MaxLength))
+	}
+	return opts
+}
+
+// rangeOptions renders the bounds keywords of numbers, with their messages
+func rangeOptions(c platoCue.Constraints, msgs platoCue.ErrorMessages) []string {
+	var opts []string
+	if c.Minimum != nil {
+		opts = append(opts, "minimum: "+jsgen.Number(*c.Minimum))
+	}
+	if c.ExclusiveMinimum != nil {
+		opts = append(opts, "exclusiveMinimum: "+jsgen.Number(*c.ExclusiveMinimum))
+	}
+	if c.Maximum != nil {
+		opts = append(opts, "maximum: "+jsgen.Number(*c.Maximum))
+	}
+	if c.ExclusiveMaximum != nil {
+		opts = append(opts, "exclusiveMaximum: "+jsgen.Number(*c.ExclusiveMaximum))
+	}
+	return append(opts, errorMessages(msgs, "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum")...)
+}
+
+// errorMessages renders the @errmsg messages of keywords as an
+// errorMessage option, as read by ajv-errors
+func errorMessages(msgs platoCue.ErrorMessages, keywords ...string) []string {
+	var entries []string
+	for _, keyword := range keywords {
+		if msg := msgs.For(keyword); msg != "" {
+			entries = append(entries, keyword+": "+jsgen.String(msg))
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return []string{"errorMessage: { " + strings.Join(entries, ", ") + " }"}
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,71 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// canonicalHelpers encode values as RFC 8785 canonical JSON. The output
+// matches the Go generator's CanonicalJSON, so payloads hash and sign the
+// same on both sides.
+const canonicalHelpers = `// canonicalize encodes a value as canonical JSON (RFC 8785): object keys
+// sorted by UTF-16 code units, numbers in their shortest form and no
+// insignificant whitespace, so equal values encode to the same string in
+// every language. Encode the result as UTF-8 before hashing or signing.
+export function canonicalize(value: unknown): string {
+  const json = canonicalValue(value);
+  if (json === undefined) {
+    throw new TypeError("value cannot be encoded as JSON");
+  }
+  return json;
+}
+
+function canonicalValue(value: unknown): string | undefined {
+  if (value !== null && typeof value === "object" && typeof (value as { toJSON?: unknown }).toJSON === "function") {
+    value = (value as { toJSON: () => unknown }).toJSON();
+  }
+  switch (typeof value) {
+    case "boolean":
+    case "string":
+      return JSON.stringify(value);
+    case "number":
+      if (!Number.isFinite(value)) {
+        throw new RangeError(` + "`cannot encode ${value} as canonical JSON`" + `);
+      }
+      return JSON.stringify(value);
+    case "bigint":
+      throw new TypeError("cannot encode a bigint as canonical JSON");
+    case "object":
+      break;
+    default:
+      return undefined;
+  }
+  if (value === null) {
+    return "null";
+  }
+  if (Array.isArray(value)) {
+    return "[" + value.map((item) => canonicalValue(item) ?? "null").join(",") + "]";
+  }
+  const record = value as Record<string, unknown>;
+  const entries: string[] = [];
+  for (const key of Object.keys(record).sort()) {
+    const item = canonicalValue(record[key]);
+    if (item !== undefined) {
+      entries.push(JSON.stringify(key) + ":" + item);
+    }
+  }
+  return "{" + entries.join(",") + "}";
+}
+`
+
+// writeCanonicalHelpers writes canonicalize and a typed wrapper per interface
+func writeCanonicalHelpers(buf *bytes.Buffer, names []string) {
+	buf.WriteString(canonicalHelpers)
+
+	for _, name := range names {
+		buf.WriteString("\n")
+		fmt.Fprintf(buf, "export function canonicalize%s(value: %s): string {\n", name, name)
+		buf.WriteString("  return canonicalize(value);\n")
+		buf.WriteString("}\n")
+	}
+}
@@ -0,0 +1,76 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// codecImports are the imports of each binary codec
+var codecImports = map[string]string{
+	"msgpack": "import { encode as encodeMsgpack, decode as decodeMsgpack } from \"@msgpack/msgpack\";\n",
+	"cbor":    "import { Encoder as CborEncoder } from \"cbor-x\";\n",
+}
+
+// codecSetup is emitted once after the interfaces when codecs are enabled
+const codecSetup = `// omitUndefined drops undefined properties, which JSON omits but binary
+// codecs would encode
+function omitUndefined(value: unknown): unknown {
+  if (Array.isArray(value)) {
+    return value.map(omitUndefined);
+  }
+  if (value === null || typeof value !== "object" || value instanceof Uint8Array || value instanceof Date) {
+    return value;
+  }
+  const result: Record<string, unknown> = {};
+  for (const [key, item] of Object.entries(value)) {
+    if (item !== undefined) {
+      result[key] = omitUndefined(item);
+    }
+  }
+  return result;
+}
+`
+
+// cborSetup configures cbor-x to write plain CBOR maps that other
+// implementations can read
+const cborSetup = `
+const cbor = new CborEncoder({ useRecords: false, mapsAsObjects: true });
+`
+
+// writeCodecImports writes the imports of the selected codecs
+func writeCodecImports(buf *bytes.Buffer, codecs []string) {
+	for _, codec := range codecs {
+		buf.WriteString(codecImports[codec])
+	}
+	if len(codecs) > 0 {
+		buf.WriteString("\n")
+	}
+}
+
+// writeCodecHelpers writes encode and decode functions per interface and
+// codec. Decoding does not validate; pair it with the zod schemas for
+// untrusted input.
+func writeCodecHelpers(buf *bytes.Buffer, names []string, codecs []string) {
+	buf.WriteString(codecSetup)
+	for _, codec := range codecs {
+		if codec == "cbor" {
+			buf.WriteString(cborSetup)
+		}
+	}
+
+	for _, name := range names {
+		for _, codec := range codecs {
+			suffix, encode, decode := "Msgpack", "encodeMsgpack", "decodeMsgpack"
+			if codec == "cbor" {
+				suffix, encode, decode = "Cbor", "cbor.encode", "cbor.decode"
+			}
+			buf.WriteString("\n")
+			fmt.Fprintf(buf, "export function encode%s%s(value: %s): Uint8Array {\n", name, suffix, name)
+			fmt.Fprintf(buf, "  return %s(omitUndefined(value));\n", encode)
+			buf.WriteString("}\n\n")
+			fmt.Fprintf(buf, "export function decode%s%s(data: Uint8Array): %s {\n", name, suffix, name)
+			fmt.Fprintf(buf, "  return %s(data) as %s;\n", decode, name)
+			buf.WriteString("}\n")
+		}
+	}
+}
@@ -5,14 +5,39 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
 )
 
 // Generator generates TypeScript types and Zod schemas from CUE
 type Generator struct{}
 
+// Styles of the enumStyle option, for disjunctions of strings
+const (
+	enumUnion  = "union" // "member" | "admin"
+	enumConst  = "const" // const object and keyof typeof type
+	enumNative = "enum"  // enum declaration
+)
+
+// renderer renders definitions as TypeScript types
+type renderer struct {
+	defs      map[string]cue.Value
+	policy    string
+	enumStyle string
+	readonly  bool            // fields are readonly
+	brands    bool            // definitions tagged @brand are branded
+	names     map[string]bool // type names in use
+
+	// hoisted holds the enums of fields, named after their interface and
+	// field, e.g. UserRole, declared before the interface
+	hoisted bytes.Buffer
+}
+
 // NewGenerator creates a new TypeScript generator
 func NewGenerator() *Generator {
 	return &Generator{}
@@ -31,6 +56,21 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	buf.WriteString("// Generated by PlatoSL\n")
 	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
 
+	// Binary codec helpers
+	codecs, err := ctx.CodecsOption()
+	if err != nil {
+		return nil, err
+	}
+	writeCodecImports(&buf, codecs)
+	policy, err := ctx.UnicodeOption()
+	if err != nil {
+		return nil, err
+	}
+	enumStyle, err := enumStyleOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract definitions
 	defs, err := extractDefinitions(ctx.Value)
 	if err != nil {
@@ -44,20 +84,75 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	}
 	sort.Strings(defNames)
 
+	tsNames := make([]string, len(defNames))
+	r := &renderer{
+		defs:      defs,
+		policy:    policy,
+		enumStyle: enumStyle,
+		readonly:  ctx.GetBoolOption("readonly", false),
+		brands:    ctx.GetBoolOption("brands", true),
+		names:     make(map[string]bool),
+	}
+	for i, name := range defNames {
+		tsNames[i] = toTypescriptName(name, policy)
+		r.names[tsNames[i]] = true
+	}
+
 	// Generate TypeScript interfaces
-	for _, name := range defNames {
+	for i, name := range defNames {
 		val := defs[name]
-		tsName := toTypescriptName(name)
 
-		// Generate interface
-		iface, err := generateInterface(tsName, val)
+		// Disjunctions of strings are enums
+		if values := stringEnum(val); len(values) > 0 {
+			r.writeEnum(&buf, tsNames[i], platoCue.Description(val), values)
+			buf.WriteString("\n")
+			continue
+		}
+
+		// Definitions of scalars and lists are type aliases
+		if val.IncompleteKind() != cue.StructKind {
+			r.writeAlias(&buf, tsNames[i], val)
+			buf.WriteString("\n")
+			continue
+		}
+
+		// Generate interface, or union type, after the enums of its fields
+		iface, err := r.generateInterface(tsNames[i], val)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate interface for %s: %w", name, err)
 		}
+		buf.Write(r.hoisted.Bytes())
+		r.hoisted.Reset()
 		buf.WriteString(iface)
 		buf.WriteString("\n")
 	}
 
+	if len(codecs) > 0 {
+		writeCodecHelpers(&buf, tsNames, codecs)
+	}
+	if ctx.GetBoolOption("canonical", false) && len(tsNames) > 0 {
+		if len(codecs) > 0 {
+			buf.WriteString("\n")
+		}
+		writeCanonicalHelpers(&buf, tsNames)
+	}
+	if ctx.GetBoolOption("lenient", false) && len(defNames) > 0 {
+		if len(codecs) > 0 || ctx.GetBoolOption("canonical", false) {
+			buf.WriteString("\n")
+		}
+		if err := writeLenientDecoders(&buf, defs, defNames, policy); err != nil {
+			return nil, fmt.Errorf("failed to generate lenient decoders: %w", err)
+		}
+	}
+	if ctx.GetBoolOption("typeGuards", false) && len(defNames) > 0 {
+		if len(codecs) > 0 || ctx.GetBoolOption("canonical", false) || ctx.GetBoolOption("lenient", false) {
+			buf.WriteString("\n")
+		}
+		if err := writeTypeGuards(&buf, defs, defNames, policy); err != nil {
+			return nil, fmt.Errorf("failed to generate type guards: %w", err)
+		}
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -66,7 +161,34 @@ func (g *Generator) Validate(ctx *generator.Context) error {
 	if err := ctx.Value.Err(); err != nil {
 		return fmt.Errorf("invalid CUE value: %w", err)
 	}
-	return nil
+	if _, err := ctx.CodecsOption(); err != nil {
+		return err
+	}
+	if _, err := enumStyleOption(ctx); err != nil {
+		return err
+	}
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return err
+	}
+	for name, val := range defs {
+		if platoCue.HasAttr(val, "brand") && (val.IncompleteKind() == cue.StructKind || len(stringEnum(val)) > 0) {
+			return fmt.Errorf("%s: @brand applies to definitions of strings, numbers and lists", name)
+		}
+	}
+	_, err = ctx.UnicodeOption()
+	return err
+}
+
+// enumStyleOption returns the enumStyle option: union (default), const or
+// enum
+func enumStyleOption(ctx *generator.Context) (string, error) {
+	switch style := ctx.GetStringOption("enumStyle", enumUnion); style {
+	case enumUnion, enumConst, enumNative:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown enumStyle %q (expected union, const or enum)", style)
+	}
 }
 
 // extractDefinitions extracts all definitions from a CUE value
@@ -88,49 +210,100 @@ func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
 	return defs, nil
 }
 
-// generateInterface generates a TypeScript interface
-func generateInterface(name string, val cue.Value) (string, error) {
+// generateInterface generates a TypeScript interface, or a union type for
+// a union of structs, e.g. export type OrderEvent = OrderCreated |
+// OrderCancelled. Literal fields such as kind: "order.created" have literal
+// types, so TypeScript narrows the union on them.
+func (r *renderer) generateInterface(name string, val cue.Value) (string, error) {
 	var buf bytes.Buffer
+	writeJSDoc(&buf, docLines(platoCue.Description(val)), "")
+
+	if u, ok := platoCue.UnionOf(val); ok {
+		members := make([]string, len(u.Members))
+		inline := false
+		for i, m := range u.Members {
+			if ref := reference(m, r.defs); ref != "" {
+				members[i] = toTypescriptName(ref, r.policy)
+				continue
+			}
+			var obj bytes.Buffer
+			obj.WriteString("{\n")
+			if err := r.writeFields(&obj, name, m, "    "); err != nil {
+				return "", err
+			}
+			obj.WriteString("  }")
+			members[i] = obj.String()
+			inline = true
+		}
+		if inline {
+			fmt.Fprintf(&buf, "export type %s =\n  | %s;\n", name, strings.Join(members, "\n  | "))
+		} else {
+			fmt.Fprintf(&buf, "export type %s = %s;\n", name, strings.Join(members, " | "))
+		}
+		return buf.String(), nil
+	}
 
 	fmt.Fprintf(&buf, "export interface %s {\n", name)
+	if err := r.writeFields(&buf, name, val, "  "); err != nil {
+		return "", err
+	}
+	buf.WriteString("}\n")
 
+	return buf.String(), nil
+}
+
+// writeFields writes the properties of an interface or object type, one
+// per line; owner names the enums of the fields
+func (r *renderer) writeFields(buf *bytes.Buffer, owner string, val cue.Value, indent string) error {
 	// Iterate fields
 	iter, err := val.Fields(cue.Optional(true))
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	for iter.Next() {
-		label := iter.Selector().String()
-		fieldVal := iter.Value()
-		optional := iter.IsOptional()
-
 		// Skip definitions
-		if strings.HasPrefix(label, "#") {
+		if iter.Selector().IsDefinition() {
 			continue
 		}
 
-		// Clean field name (remove CUE syntax markers like ! and ?)
-		cleanLabel := cleanFieldName(label)
+		fieldVal := iter.Value()
+		optional := iter.IsOptional()
+
+		// Field names are keys of the data and keep their spelling,
+		// quoted when they are not identifiers
+		label := iter.Selector().Unquoted()
+		cleanLabel := propertyKey(label, r.policy)
 
 		// Map type
-		tsType := mapToTypescriptType(fieldVal)
+		tsType := r.mapToTypescriptType(fieldVal, owner+pascalCase(label))
+
+		// Description, unit / currency annotation and default
+		doc := docLines(platoCue.Description(fieldVal))
+		if measure := platoCue.MeasureOf(fieldVal); !measure.IsZero() {
+			doc = append(doc, measure.String())
+		}
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			doc = append(doc, "@default "+def)
+		}
+		writeJSDoc(buf, doc, indent)
 
 		// Generate field
+		modifier := ""
+		if r.readonly {
+			modifier = "readonly "
+		}
 		if optional {
-			fmt.Fprintf(&buf, "  %s?: %s;\n", cleanLabel, tsType)
+			fmt.Fprintf(buf, "%s%s%s?: %s;\n", indent, modifier, cleanLabel, tsType)
 		} else {
-			fmt.Fprintf(&buf, "  %s: %s;\n", cleanLabel, tsType)
+			fmt.Fprintf(buf, "%s%s%s: %s;\n", indent, modifier, cleanLabel, tsType)
 		}
 	}
-
-	buf.WriteString("}\n")
-
-	return buf.String(), nil
+	return nil
 }
 
 // generateZodSchema generates a Zod schema
-func generateZodSchema(name string, val cue.Value) (string, error) {
+func generateZodSchema(name string, val cue.Value, policy string) (string, error) {
 	var buf bytes.Buffer
 
 	schemaName := name + "Schema"
@@ -156,7 +329,12 @@ func generateZodSchema(name string, val cue.Value) (string, error) {
 		cleanLabel := cleanFieldName(label)
 
 		// Map to Zod type
-		zodType := mapToZodType(fieldVal)
+		zodType := mapToZodType(fieldVal, policy)
+
+		// Unit / currency annotation
+		if measure := platoCue.MeasureOf(fieldVal); !measure.IsZero() {
+			fmt.Fprintf(&buf, "  // %s\n", measure)
+		}
 
 		// Add optional modifier
 		if optional {
@@ -171,8 +349,63 @@ func generateZodSchema(name string, val cue.Value) (string, error) {
 	return buf.String(), nil
 }
 
-// mapToTypescriptType maps a CUE type to TypeScript
-func mapToTypescriptType(val cue.Value) string {
+// writeJSDoc writes a JSDoc comment, on one line when it has one
+func writeJSDoc(buf *bytes.Buffer, lines []string, indent string) {
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(buf, "%s/**\n", indent)
+		for _, line := range lines {
+			fmt.Fprintf(buf, "%s\n", strings.TrimRight(indent+" * "+line, " "))
+		}
+		fmt.Fprintf(buf, "%s */\n", indent)
+	}
+}
+
+// docLines splits a description into JSDoc lines, escaping the end of the
+// comment
+func docLines(doc string) []string {
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(doc, "*/", "*\\/"), "\n")
+}
+
+// mapToTypescriptType maps a CUE type to TypeScript, literals to literal
+// types. References to definitions are their types, which interfaces may
+// be of themselves, e.g. children?: Node[] in Node, and a null branch adds
+// | null. Disjunctions of strings are enums in the enum style; name names
+// the enum when the style declares one.
+func (r *renderer) mapToTypescriptType(val cue.Value, name string) string {
+	if ref := reference(val, r.defs); ref != "" {
+		return toTypescriptName(ref, r.policy)
+	}
+	if rest, ok := stripNullBranch(val); ok {
+		return r.mapToTypescriptType(rest, name) + " | null"
+	}
+	if values := stringEnum(val); len(values) > 0 {
+		typ := r.literalUnion(values)
+		if r.enumStyle != enumUnion {
+			typ = r.uniqueName(generator.UnicodeName(r.policy, name, escapeRune))
+			r.writeEnum(&r.hoisted, typ, "", values)
+			r.hoisted.WriteString("\n")
+		}
+		if val.IncompleteKind()&cue.NullKind != 0 {
+			typ += " | null"
+		}
+		return typ
+	}
+	if val.IsConcrete() && val.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) != 0 {
+		if val.Kind() == cue.StringKind {
+			s, _ := val.String()
+			return quoteString(s, r.policy)
+		}
+		if data, err := val.MarshalJSON(); err == nil {
+			return string(data)
+		}
+	}
 	kind := val.IncompleteKind()
 
 	switch {
@@ -188,13 +421,12 @@ func mapToTypescriptType(val cue.Value) string {
 		return "boolean"
 	case kind&cue.ListKind != 0:
 		// Try to get element type
-		elemType := getListElementType(val)
+		elemType := r.getListElementType(val, name)
+		if strings.Contains(elemType, " | ") {
+			elemType = "(" + elemType + ")"
+		}
 		return elemType + "[]"
 	case kind&cue.StructKind != 0:
-		// Check if it references a definition
-		if ref := getDefinitionReference(val); ref != "" {
-			return toTypescriptName(ref)
-		}
 		return "object"
 	default:
 		return "unknown"
@@ -202,7 +434,7 @@ func mapToTypescriptType(val cue.Value) string {
 }
 
 // mapToZodType maps a CUE type to Zod
-func mapToZodType(val cue.Value) string {
+func mapToZodType(val cue.Value, policy string) string {
 	kind := val.IncompleteKind()
 
 	switch {
@@ -218,12 +450,12 @@ func mapToZodType(val cue.Value) string {
 	case kind&cue.BoolKind != 0:
 		return "z.boolean()"
 	case kind&cue.ListKind != 0:
-		elemType := getListElementZodType(val)
+		elemType := getListElementZodType(val, policy)
 		return fmt.Sprintf("z.array(%s)", elemType)
 	case kind&cue.StructKind != 0:
 		// Check if it references a definition
 		if ref := getDefinitionReference(val); ref != "" {
-			return toTypescriptName(ref) + "Schema"
+			return toTypescriptName(ref, policy) + "Schema"
 		}
 		return "z.object({})"
 	default:
@@ -232,21 +464,24 @@ func mapToZodType(val cue.Value) string {
 }
 
 // getListElementType gets the element type of a list
-func getListElementType(val cue.Value) string {
+func (r *renderer) getListElementType(val cue.Value, name string) string {
 	// Try to get the first element or list constraint
 	iter, err := val.List()
 	if err == nil && iter.Next() {
-		return mapToTypescriptType(iter.Value())
+		return r.mapToTypescriptType(iter.Value(), name)
+	}
+	if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+		return r.mapToTypescriptType(elem, name)
 	}
 	return "unknown"
 }
 
 // getListElementZodType gets the Zod element type of a list
-func getListElementZodType(val cue.Value) string {
+func getListElementZodType(val cue.Value, policy string) string {
 	// Try to get the first element or list constraint
 	iter, err := val.List()
 	if err == nil && iter.Next() {
-		return mapToZodType(iter.Value())
+		return mapToZodType(iter.Value(), policy)
 	}
 	return "z.unknown()"
 }
@@ -258,17 +493,175 @@ func getDefinitionReference(val cue.Value) string {
 	return ""
 }
 
-// toTypescriptName converts a CUE definition name to TypeScript
-func toTypescriptName(name string) string {
-	// Remove leading # and ensure PascalCase
-	name = strings.TrimPrefix(name, "#")
+// reference returns the definition of the schemas a value refers to, e.g.
+// #Node, or "" when it refers to none of them
+func reference(val cue.Value, defs map[string]cue.Value) string {
+	ref := platoCue.DefinitionRef(val)
+	if _, ok := defs[ref]; !ok {
+		return ""
+	}
+	return ref
+}
 
-	// Basic conversion
-	if len(name) > 0 {
-		name = strings.ToUpper(name[:1]) + name[1:]
+// stripNullBranch returns the other branch of a disjunction with null,
+// e.g. #Node in #Node | null
+func stripNullBranch(val cue.Value) (cue.Value, bool) {
+	op, args := val.Expr()
+	if op != cue.OrOp || len(args) != 2 {
+		return val, false
 	}
+	for i, arg := range args {
+		if arg.IncompleteKind() == cue.NullKind {
+			return args[1-i], true
+		}
+	}
+	return val, false
+}
 
-	return name
+// writeAlias declares a type alias for a definition of a scalar or list,
+// e.g. export type Tags = string[]. With brands, a definition tagged
+// @brand("UserId") gets a brand, so a plain string is not a UserId:
+//
+//	export type UserId = string & { readonly __brand: "UserId" };
+//
+// @brand without a name brands the definition with its own name.
+func (r *renderer) writeAlias(buf *bytes.Buffer, name string, val cue.Value) {
+	typ := r.mapToTypescriptType(val, name)
+	if attr, ok := platoCue.GetAttr(val, "brand"); ok && r.brands {
+		brand := attr.Arg(0)
+		if brand == "" {
+			brand = name
+		}
+		if strings.Contains(typ, " | ") {
+			typ = "(" + typ + ")"
+		}
+		typ += " & { readonly __brand: " + quoteString(brand, r.policy) + " }"
+	}
+	buf.Write(r.hoisted.Bytes())
+	r.hoisted.Reset()
+	writeJSDoc(buf, docLines(platoCue.Description(val)), "")
+	fmt.Fprintf(buf, "export type %s = %s;\n", name, typ)
+}
+
+// writeEnum declares an enum of strings in the enum style:
+//
+//	union: export type Role = "member" | "admin";
+//	const: export const Role = { Member: "member", ... } as const;
+//	       export type Role = (typeof Role)[keyof typeof Role];
+//	enum:  export enum Role { Member = "member", ... }
+func (r *renderer) writeEnum(buf *bytes.Buffer, name, doc string, values []string) {
+	lines := docLines(doc)
+	writeJSDoc(buf, lines, "")
+	switch r.enumStyle {
+	case enumConst:
+		fmt.Fprintf(buf, "export const %s = {\n", name)
+		for _, m := range r.enumMembers(values) {
+			fmt.Fprintf(buf, "  %s: %s,\n", m[0], m[1])
+		}
+		buf.WriteString("} as const;\n")
+		writeJSDoc(buf, lines, "")
+		fmt.Fprintf(buf, "export type %s = (typeof %s)[keyof typeof %s];\n", name, name, name)
+	case enumNative:
+		fmt.Fprintf(buf, "export enum %s {\n", name)
+		for _, m := range r.enumMembers(values) {
+			fmt.Fprintf(buf, "  %s = %s,\n", m[0], m[1])
+		}
+		buf.WriteString("}\n")
+	default:
+		fmt.Fprintf(buf, "export type %s = %s;\n", name, r.literalUnion(values))
+	}
+}
+
+// literalUnion renders strings as a union of literal types
+func (r *renderer) literalUnion(values []string) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = quoteString(v, r.policy)
+	}
+	return strings.Join(literals, " | ")
+}
+
+// enumMembers names the members of an enum after their values, e.g. Member
+// for "member" and OrderCreated for "order.created", with a number added
+// to names already taken. It returns the names and quoted values.
+func (r *renderer) enumMembers(values []string) [][2]string {
+	used := make(map[string]bool)
+	members := make([][2]string, len(values))
+	for i, v := range values {
+		member := pascalCase(v)
+		switch {
+		case member == "":
+			member = "Empty"
+		case unicode.IsDigit(rune(member[0])):
+			member = "_" + member
+		}
+		unique := member
+		for n := 2; used[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", member, n)
+		}
+		used[unique] = true
+		members[i] = [2]string{generator.UnicodeName(r.policy, unique, escapeRune), quoteString(v, r.policy)}
+	}
+	return members
+}
+
+// uniqueName reserves a type name, adding a number if it is taken
+func (r *renderer) uniqueName(name string) string {
+	return generator.UniqueName(r.names, name, "")
+}
+
+// stringEnum returns the members of a disjunction of string literals,
+// leaving out a null branch
+func stringEnum(val cue.Value) []string {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return nil
+	}
+
+	var members []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if arg.IncompleteKind() == cue.NullKind {
+			continue
+		}
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			return nil
+		}
+		if !seen[s] {
+			seen[s] = true
+			members = append(members, s)
+		}
+	}
+	return members
+}
+
+// pascalCase joins the words of a name in PascalCase, e.g. ShipTo for
+// ship-to; runes other than letters and digits separate words
+func pascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toTypescriptName converts a CUE definition name to TypeScript
+func toTypescriptName(name, policy string) string {
+	// Remove leading # and ensure PascalCase
+	name = strings.TrimPrefix(name, "#")
+	r, size := utf8.DecodeRuneInString(name)
+	name = string(unicode.ToUpper(r)) + name[size:]
+	return generator.UnicodeName(policy, name, escapeRune)
 }
 
 // cleanFieldName removes CUE syntax markers from field names
@@ -279,7 +672,60 @@ func cleanFieldName(name string) string {
 	return name
 }
 
+// propertyKey renders a property name, quoting it when it is not an
+// identifier. Under the escape unicode policy, non-ASCII runes are written
+// as escapes, which leaves the name the same at runtime.
+func propertyKey(name, policy string) string {
+	if isIdentifier(name) {
+		if policy == generator.UnicodeEscape {
+			return generator.UnicodeName(policy, name, escapeRune)
+		}
+		return name
+	}
+	return quoteString(name, policy)
+}
+
+// quoteString renders a double-quoted string literal, escaping non-ASCII
+// runes under the escape unicode policy
+func quoteString(s, policy string) string {
+	if policy != generator.UnicodeEscape {
+		return jsgen.String(s)
+	}
+	var b strings.Builder
+	for _, r := range jsgen.String(s) {
+		if r < utf8.RuneSelf {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(escapeRune(r))
+		}
+	}
+	return b.String()
+}
+
+// isIdentifier reports whether a name is a JavaScript identifier
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// escapeRune writes a rune as a JavaScript escape, valid in identifiers
+// and strings
+func escapeRune(r rune) string {
+	if r > 0xFFFF {
+		return fmt.Sprintf(`\u{%X}`, r)
+	}
+	return fmt.Sprintf(`\u%04X`, r)
+}
+
 func init() {
 	// Register the generator
 	generator.Register(NewGenerator())
+	generator.RegisterAlias("ts", "typescript")
 }
@@ -0,0 +1,252 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// guardRuntime is shared by the type guards. It has no is prefix, so it
+// cannot clash with the guard of a definition.
+const guardRuntime = `// guardRecord reports whether a value is a plain object
+function guardRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+`
+
+// guardBuilder renders CUE values as the conditions of type guards
+type guardBuilder struct {
+	defs   map[string]cue.Value
+	policy string
+}
+
+// writeTypeGuards writes an is<Name> function per interface, checking the
+// kinds, required fields, enums, bounds, lengths and patterns of the
+// definition at runtime without a validation library
+func writeTypeGuards(buf *bytes.Buffer, defs map[string]cue.Value, defNames []string, policy string) error {
+	b := &guardBuilder{defs: defs, policy: policy}
+
+	buf.WriteString(guardRuntime)
+	for _, name := range defNames {
+		conds, err := b.conditions(defs[name], "value", 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		tsName := toTypescriptName(name, policy)
+		buf.WriteString("\n")
+		fmt.Fprintf(buf, "export function is%s(value: unknown): value is %s {\n", tsName, tsName)
+		if len(conds) == 1 {
+			fmt.Fprintf(buf, "  return %s;\n", conds[0])
+		} else {
+			buf.WriteString("  return (\n")
+			fmt.Fprintf(buf, "    %s\n", strings.Join(conds, " &&\n    "))
+			buf.WriteString("  );\n")
+		}
+		buf.WriteString("}\n")
+	}
+	return nil
+}
+
+// condition renders the check of a value as a single expression
+func (b *guardBuilder) condition(val cue.Value, expr string, depth int) (string, error) {
+	conds, err := b.conditions(val, expr, depth)
+	if err != nil {
+		return "", err
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return "(" + strings.Join(conds, " && ") + ")", nil
+}
+
+// conditions renders the checks of a value, all of which must hold. expr
+// is the JavaScript expression of the value, and depth numbers the
+// parameters of the callbacks checking list items and map values.
+func (b *guardBuilder) conditions(val cue.Value, expr string, depth int) ([]string, error) {
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		if _, ok := b.defs[path.String()]; ok {
+			return []string{fmt.Sprintf("is%s(%s)", toTypescriptName(path.String(), b.policy), expr)}, nil
+		}
+	}
+
+	// A value with a default is the disjunction without it
+	op, args := val.Expr()
+	if op == cue.NoOp && len(args) == 1 {
+		val = args[0]
+		op, args = val.Expr()
+	}
+
+	// Each branch of a disjunction, including null and enum values
+	if op == cue.OrOp {
+		var branches []string
+		for _, arg := range args {
+			cond, err := b.condition(arg, expr, depth)
+			if err != nil {
+				return nil, err
+			}
+			if cond == "true" {
+				return []string{"true"}, nil
+			}
+			branches = append(branches, cond)
+		}
+		return []string{"(" + strings.Join(branches, " || ") + ")"}, nil
+	}
+
+	kind := val.IncompleteKind()
+	if val.IsConcrete() && kind&(cue.StringKind|cue.NumberKind|cue.BoolKind|cue.NullKind) == kind {
+		data, err := val.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("%s === %s", expr, data)}, nil
+	}
+
+	// Lists and structs constrained by builtins, e.g. [...#Line] &
+	// list.MinItems(1), take their items and fields from the conjunct
+	// that is not a call
+	base := val
+	if kind == cue.BottomKind && op == cue.AndOp {
+		base = declared(args, 0)
+		kind = base.IncompleteKind()
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	switch kind {
+	case cue.NullKind:
+		return []string{expr + " === null"}, nil
+	case cue.BoolKind:
+		return []string{fmt.Sprintf("typeof %s === \"boolean\"", expr)}, nil
+	case cue.StringKind:
+		conds := []string{fmt.Sprintf("typeof %s === \"string\"", expr)}
+		// CUE counts runes, which spreading a string yields, rather than
+		// UTF-16 code units
+		if c.MinLength != nil {
+			conds = append(conds, fmt.Sprintf("[...%s].length >= %d", expr, *c.MinLength))
+		}
+		if c.MaxLength != nil {
+			conds = append(conds, fmt.Sprintf("[...%s].length <= %d", expr, *c.MaxLength))
+		}
+		for _, pattern := range c.Patterns {
+			conds = append(conds, fmt.Sprintf("/%s/.test(%s)", jsgen.EscapeRegex(pattern), expr))
+		}
+		return conds, nil
+	case cue.IntKind, cue.FloatKind, cue.NumberKind:
+		conds := []string{fmt.Sprintf("typeof %s === \"number\"", expr)}
+		if kind == cue.IntKind {
+			conds = append(conds, fmt.Sprintf("Number.isInteger(%s)", expr))
+		}
+		for _, bound := range []struct {
+			limit *float64
+			op    string
+		}{{c.Minimum, ">="}, {c.ExclusiveMinimum, ">"}, {c.Maximum, "<="}, {c.ExclusiveMaximum, "<"}} {
+			if bound.limit != nil {
+				conds = append(conds, fmt.Sprintf("%s %s %v", expr, bound.op, *bound.limit))
+			}
+		}
+		return conds, nil
+	case cue.ListKind:
+		conds := []string{fmt.Sprintf("Array.isArray(%s)", expr)}
+		if c.MinLength != nil {
+			conds = append(conds, fmt.Sprintf("%s.length >= %d", expr, *c.MinLength))
+		}
+		if c.MaxLength != nil {
+			conds = append(conds, fmt.Sprintf("%s.length <= %d", expr, *c.MaxLength))
+		}
+		if elem := base.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			param := fmt.Sprintf("v%d", depth+1)
+			cond, err := b.condition(elem, param, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if cond != "true" {
+				conds = append(conds, fmt.Sprintf("%s.every((%s) => %s)", expr, param, cond))
+			}
+		}
+		return conds, nil
+	case cue.StructKind:
+		return b.structConditions(base, expr, depth)
+	}
+	// Top and mixed kinds accept anything
+	return []string{"true"}, nil
+}
+
+// declared returns the conjunct among args, nested ones included, that is
+// neither a builtin call nor a conjunction itself
+func declared(args []cue.Value, depth int) cue.Value {
+	for _, arg := range args {
+		switch op, nested := arg.Expr(); {
+		case op == cue.AndOp && depth < 8:
+			if v := declared(nested, depth+1); v.Exists() {
+				return v
+			}
+		case op != cue.CallOp && op != cue.AndOp:
+			return arg
+		}
+	}
+	return cue.Value{}
+}
+
+// structConditions renders the checks of a struct or map. Fields the
+// definition does not declare are allowed, as in TypeScript types.
+func (b *guardBuilder) structConditions(val cue.Value, expr string, depth int) ([]string, error) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, err
+	}
+
+	conds := []string{fmt.Sprintf("guardRecord(%s)", expr)}
+	fields := 0
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		fields++
+		label := iter.Selector().Unquoted()
+		access := expr + b.accessor(label)
+		fieldConds, err := b.conditions(iter.Value(), access, depth)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", label, err)
+		}
+		unchecked := len(fieldConds) == 1 && fieldConds[0] == "true"
+		switch {
+		case iter.IsOptional() && !unchecked:
+			cond := fieldConds[0]
+			if len(fieldConds) > 1 {
+				cond = "(" + strings.Join(fieldConds, " && ") + ")"
+			}
+			conds = append(conds, fmt.Sprintf("(%s === undefined || %s)", access, cond))
+		case iter.IsOptional():
+		case unchecked:
+			conds = append(conds, fmt.Sprintf("%s in %s", quoteString(label, b.policy), expr))
+		default:
+			conds = append(conds, fieldConds...)
+		}
+	}
+
+	if fields == 0 {
+		if pattern := val.LookupPath(cue.MakePath(cue.AnyString)); pattern.Exists() {
+			param := fmt.Sprintf("v%d", depth+1)
+			cond, err := b.condition(pattern, param, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if cond != "true" {
+				conds = append(conds, fmt.Sprintf("Object.values(%s).every((%s) => %s)", expr, param, cond))
+			}
+		}
+	}
+	return conds, nil
+}
+
+// accessor renders the access of a property, with a dot when the name is
+// an identifier
+func (b *guardBuilder) accessor(name string) string {
+	if isIdentifier(name) {
+		return "." + propertyKey(name, b.policy)
+	}
+	return "[" + quoteString(name, b.policy) + "]"
+}
@@ -0,0 +1,290 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// lenientRuntime coerces loosely formed JSON to the shape of the
+// interfaces. The rules match the Go generator's lenient decoders.
+const lenientRuntime = `// LenientType describes the expected shape of a value for the lenient decoders
+type LenientType =
+  | "string"
+  | "number"
+  | "integer"
+  | "boolean"
+  | "unknown"
+  | { ref: string }
+  | { list: LenientType }
+  | { map: LenientType }
+  | { fields: Record<string, LenientType>; optional: string[] };
+
+// LenientResult is a leniently decoded value with the fields its type does
+// not declare, keyed by their path (e.g. "author.nickname" or "items[2].note")
+export interface LenientResult<T> {
+  value: T;
+  extras: Record<string, unknown>;
+}
+
+// LenientDecodeError reports a value that cannot be coerced
+export class LenientDecodeError extends Error {
+  readonly path: string;
+
+  constructor(path: string, message: string) {
+    super(` + "`${path || \"value\"}: ${message}`" + `);
+    this.name = "LenientDecodeError";
+    this.path = path;
+  }
+}
+
+const lenientNumber = /^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$/;
+
+// decodeLenient coerces common variations: numbers and booleans given as
+// strings, numbers and booleans where a string is expected, and single
+// values where a list is expected. Null, empty and missing required fields
+// get their zero value, and missing optional fields stay missing. Fields the
+// type does not declare are removed and returned as extras. Fields are
+// visited in sorted order, so errors name the same field as in Go.
+function decodeLenient<T>(input: unknown, type: LenientType): LenientResult<T> {
+  const extras: Record<string, unknown> = {};
+  const value = coerceLenient(input, type, "", extras);
+  return { value: (value === undefined ? zeroValue(type) : value) as T, extras };
+}
+
+function coerceLenient(value: unknown, type: LenientType, path: string, extras: Record<string, unknown>): unknown {
+  if (typeof type === "object" && "ref" in type) {
+    return coerceLenient(value, lenientTypes[type.ref], path, extras);
+  }
+  if (type === "unknown") {
+    return value;
+  }
+  if (value === null || value === undefined) {
+    return undefined;
+  }
+
+  if (type === "string") {
+    return typeof value === "number" || typeof value === "boolean" ? String(value) : value;
+  }
+  if (type === "boolean") {
+    if (typeof value !== "string" && typeof value !== "number") {
+      return value;
+    }
+    const s = String(value).trim().toLowerCase();
+    if (s === "") {
+      return undefined;
+    }
+    if (s === "true" || s === "t" || s === "1") {
+      return true;
+    }
+    if (s === "false" || s === "f" || s === "0") {
+      return false;
+    }
+    throw new LenientDecodeError(path, ` + "`cannot use ${JSON.stringify(value)} as a boolean`" + `);
+  }
+  if (type === "number" || type === "integer") {
+    if (typeof value !== "string") {
+      return value;
+    }
+    const s = value.trim();
+    if (s === "") {
+      return undefined;
+    }
+    const n = Number(s);
+    if (!lenientNumber.test(s) || !Number.isFinite(n) || (type === "integer" && !Number.isInteger(n))) {
+      throw new LenientDecodeError(path, ` + "`cannot use ${JSON.stringify(value)} as ${type === \"integer\" ? \"an integer\" : \"a number\"}`" + `);
+    }
+    return n;
+  }
+  if ("list" in type) {
+    const items = Array.isArray(value) ? value : [value];
+    return items.map((item, i) => {
+      const coerced = coerceLenient(item, type.list, ` + "`${path}[${i}]`" + `, extras);
+      return coerced === undefined ? zeroValue(type.list) : coerced;
+    });
+  }
+
+  if (typeof value !== "object" || Array.isArray(value)) {
+    throw new LenientDecodeError(path, "expected an object");
+  }
+  const record = value as Record<string, unknown>;
+  const result: Record<string, unknown> = {};
+  if ("map" in type) {
+    for (const key of Object.keys(record).sort()) {
+      const coerced = coerceLenient(record[key], type.map, lenientJoin(path, key), extras);
+      result[key] = coerced === undefined ? zeroValue(type.map) : coerced;
+    }
+    return result;
+  }
+  for (const key of Object.keys(record).sort()) {
+    if (!Object.prototype.hasOwnProperty.call(type.fields, key)) {
+      extras[lenientJoin(path, key)] = record[key];
+      continue;
+    }
+    const coerced = coerceLenient(record[key], type.fields[key], lenientJoin(path, key), extras);
+    if (coerced !== undefined) {
+      result[key] = coerced;
+    }
+  }
+  for (const key of Object.keys(type.fields)) {
+    if (result[key] === undefined && !type.optional.includes(key)) {
+      result[key] = zeroValue(type.fields[key]);
+    }
+  }
+  return result;
+}
+
+// zeroValue is the value a missing required field gets
+function zeroValue(type: LenientType): unknown {
+  switch (type) {
+    case "string":
+      return "";
+    case "number":
+    case "integer":
+      return 0;
+    case "boolean":
+      return false;
+    case "unknown":
+      return null;
+  }
+  if ("ref" in type) {
+    return zeroValue(lenientTypes[type.ref]);
+  }
+  if ("list" in type) {
+    return [];
+  }
+  if ("map" in type) {
+    return {};
+  }
+  return coerceLenient({}, type, "", {});
+}
+
+function lenientJoin(path: string, key: string): string {
+  return path === "" ? key : ` + "`${path}.${key}`" + `;
+}
+`
+
+// lenientBuilder describes definitions as LenientType literals
+type lenientBuilder struct {
+	defs   map[string]cue.Value
+	policy string
+}
+
+// writeLenientDecoders writes the lenient runtime, the shape of each
+// interface and a decode<Name>Lenient function per interface
+func writeLenientDecoders(buf *bytes.Buffer, defs map[string]cue.Value, defNames []string, policy string) error {
+	b := &lenientBuilder{defs: defs, policy: policy}
+
+	buf.WriteString(lenientRuntime)
+	buf.WriteString("\nconst lenientTypes: Record<string, LenientType> = {\n")
+	for _, name := range defNames {
+		typ, err := b.typeOf(defs[name], "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintf(buf, "  %s: %s,\n", propertyKey(b.refName(name), policy), typ)
+	}
+	buf.WriteString("};\n")
+
+	for _, name := range defNames {
+		tsName := toTypescriptName(name, policy)
+		buf.WriteString("\n")
+		fmt.Fprintf(buf, "export function decode%sLenient(input: unknown): LenientResult<%s> {\n", tsName, tsName)
+		fmt.Fprintf(buf, "  return decodeLenient<%s>(input, { ref: %s });\n", tsName, quoteString(b.refName(name), policy))
+		buf.WriteString("}\n")
+	}
+	return nil
+}
+
+// refName names a definition in lenientTypes. It is the interface name
+// before the unicode policy, which quoteString and propertyKey escape.
+func (b *lenientBuilder) refName(name string) string {
+	return toTypescriptName(name, generator.UnicodeKeep)
+}
+
+// typeOf renders the LenientType literal of a value
+func (b *lenientBuilder) typeOf(val cue.Value, indent string) (string, error) {
+	// Null branches are dropped first, so #Node | null refers to #Node
+	// rather than expanding it, which would never end for recursive types
+	val, _ = platoCue.StripNull(val)
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		if _, ok := b.defs[path.String()]; ok {
+			return fmt.Sprintf("{ ref: %s }", quoteString(b.refName(path.String()), b.policy)), nil
+		}
+	}
+
+	// Unions of structs are passed through as they are
+	if _, ok := platoCue.UnionOf(val); ok {
+		return `"unknown"`, nil
+	}
+
+	kind := val.IncompleteKind()
+	switch {
+	case kind == cue.StringKind:
+		return `"string"`, nil
+	case kind == cue.IntKind:
+		return `"integer"`, nil
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return `"number"`, nil
+	case kind == cue.BoolKind:
+		return `"boolean"`, nil
+	case kind == cue.ListKind:
+		elem := val.LookupPath(cue.MakePath(cue.AnyIndex))
+		if !elem.Exists() {
+			return `{ list: "unknown" }`, nil
+		}
+		typ, err := b.typeOf(elem, indent)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("{ list: %s }", typ), nil
+	case kind == cue.StructKind:
+		return b.structOf(val, indent)
+	default:
+		return `"unknown"`, nil
+	}
+}
+
+// structOf renders the LenientType literal of a struct or map
+func (b *lenientBuilder) structOf(val cue.Value, indent string) (string, error) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "", err
+	}
+
+	var fields bytes.Buffer
+	var optional []string
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		typ, err := b.typeOf(iter.Value(), indent+"    ")
+		if err != nil {
+			return "", fmt.Errorf("field %s: %w", label, err)
+		}
+		fmt.Fprintf(&fields, "%s    %s: %s,\n", indent, propertyKey(label, b.policy), typ)
+		if iter.IsOptional() {
+			optional = append(optional, quoteString(label, b.policy))
+		}
+	}
+
+	if fields.Len() == 0 {
+		pattern := val.LookupPath(cue.MakePath(cue.AnyString))
+		if !pattern.Exists() {
+			return `{ map: "unknown" }`, nil
+		}
+		typ, err := b.typeOf(pattern, indent)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("{ map: %s }", typ), nil
+	}
+
+	return fmt.Sprintf("{\n%s  fields: {\n%s%s  },\n%s  optional: [%s],\n%s}",
+		indent, fields.String(), indent, indent, strings.Join(optional, ", "), indent), nil
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Unicode policies say how generators write non-ASCII names in identifiers,
+// such as type and struct field names. Data keys, such as JSON tags and
+// object property names, always keep the exact name of the field, or the
+// generated code would not read the data.
+const (
+	// UnicodeKeep writes names as they are
+	UnicodeKeep = "keep"
+
+	// UnicodeTransliterate spells names in ASCII: accents are dropped and
+	// ligatures spelled out (größe becomes grosse); runes without an ASCII
+	// spelling, such as CJK, are escaped
+	UnicodeTransliterate = "transliterate"
+
+	// UnicodeEscape writes every non-ASCII rune as an escape, in the
+	// escape syntax of the target language where it has one
+	UnicodeEscape = "escape"
+)
+
+// UnicodePolicies lists the policies of the "unicode" option
+var UnicodePolicies = []string{UnicodeKeep, UnicodeTransliterate, UnicodeEscape}
+
+// UnicodeOption returns the policy selected with the "unicode" option, keep
+// by default
+func (c *Context) UnicodeOption() (string, error) {
+	policy := strings.ToLower(c.GetStringOption("unicode", UnicodeKeep))
+	for _, p := range UnicodePolicies {
+		if policy == p {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown unicode policy %q (available: %s)", policy, strings.Join(UnicodePolicies, ", "))
+}
+
+// UnicodeName applies a policy to a name before a generator turns it into
+// an identifier. Runes left outside ASCII by transliterate and escape are
+// written with escape.
+func UnicodeName(policy, name string, escape func(rune) string) string {
+	if policy == UnicodeKeep || isASCII(name) {
+		return name
+	}
+	if policy == UnicodeTransliterate {
+		name = Transliterate(name)
+	}
+	var b strings.Builder
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(escape(r))
+		}
+	}
+	return b.String()
+}
+
+// EscapeRune writes a rune as U and its code point in hex, e.g. U00E9, for
+// languages whose identifiers have no escape syntax
+func EscapeRune(r rune) string {
+	return fmt.Sprintf("U%04X", r)
+}
+
+// ligatures are letters that do not decompose into an ASCII letter and marks
+var ligatures = map[rune]string{
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "Th", 'ł': "l", 'Ł': "L", 'ı': "i", 'ħ': "h", 'Ħ': "H",
+}
+
+// Transliterate spells the letters of a string in ASCII where they have an
+// ASCII spelling: café becomes cafe and straße strasse. Other runes are kept.
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks, the accents of decomposed letters
+		case ligatures[r] != "":
+			b.WriteString(ligatures[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// isASCII reports whether a string is all ASCII
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
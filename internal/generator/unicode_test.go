@@ -0,0 +1,32 @@
+package generator
+
+import "testing"
+
+func TestUnicodeName(t *testing.T) {
+	tests := []struct {
+		policy, name, want string
+	}{
+		{UnicodeKeep, "größe", "größe"},
+		{UnicodeTransliterate, "größe", "grosse"},
+		{UnicodeTransliterate, "café", "cafe"},
+		{UnicodeTransliterate, "名前", "U540DU524D"},
+		{UnicodeEscape, "café", "cafU00E9"},
+		{UnicodeEscape, "name", "name"},
+	}
+	for _, tt := range tests {
+		if got := UnicodeName(tt.policy, tt.name, EscapeRune); got != tt.want {
+			t.Errorf("UnicodeName(%s, %q) = %q, want %q", tt.policy, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnicodeOption(t *testing.T) {
+	ctx := &Context{Options: map[string]interface{}{"unicode": "Transliterate"}}
+	if policy, err := ctx.UnicodeOption(); err != nil || policy != UnicodeTransliterate {
+		t.Errorf("UnicodeOption() = %q, %v, want transliterate", policy, err)
+	}
+	ctx.Options["unicode"] = "ascii"
+	if _, err := ctx.UnicodeOption(); err == nil {
+		t.Errorf("UnicodeOption() accepted an unknown policy")
+	}
+}
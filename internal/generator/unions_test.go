@@ -0,0 +1,135 @@
+package generator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/csharp"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/dart"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/graphql"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/java"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/jsonld"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/php"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/protobuf"
+	_ "github.com/platoorg/plato-sl-cli/internal/generator/sql"
+)
+
+// TestUnionDefinitions checks that generators accept a definition that is
+// a union of other definitions, and fields of it
+func TestUnionDefinitions(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Circle: {kind: "circle", r: number}
+#Square: {kind: "square", side: number}
+#Shape: #Circle | #Square
+#Drawing: {
+	shape: #Shape
+	alt?:  #Circle | #Square
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		generator string
+		want      string
+	}{
+		{"csharp", "public required JsonElement Shape"},
+		{"dart", "final Map<String, Object?> shape;"},
+		{"graphql", "union Shape = Circle | Square"},
+		{"java", "JsonNode shape"},
+		{"jsonld", "@context"},
+		{"php", "public readonly array $shape"},
+		{"protobuf", "google.protobuf.Value shape = 1;"},
+		{"sql", `"shape" JSONB NOT NULL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			gen, err := generator.Get(tt.generator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			genCfg := config.GenConfig{Options: map[string]interface{}{
+				"lockFile": filepath.Join(t.TempDir(), "proto.lock"),
+			}}
+			out, err := gen.Generate(generator.NewContext(val, &config.Config{}, genCfg))
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("output lacks %q:\n%s", tt.want, out)
+			}
+			if strings.Contains(string(out), "##") {
+				t.Errorf("output has a doubled definition prefix:\n%s", out)
+			}
+		})
+	}
+}
+
+// TestEnumDefinitions checks that generators accept definitions of enums
+// and scalars, and fields of them
+func TestEnumDefinitions(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#Status: "active" | "in-progress"
+#Score:  int & >=0
+#User: {
+	status:  #Status
+	history: [...#Status]
+	score:   #Score
+	ratio:   number
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		generator string
+		want      []string
+	}{
+		{"graphql", []string{
+			"enum Status {\n  ACTIVE\n  IN_PROGRESS\n}",
+			"status: Status!",
+			"history: [Status!]!",
+			"score: Int!",
+			"ratio: Float!",
+		}},
+		{"protobuf", []string{
+			"enum Status {\n  STATUS_UNSPECIFIED = 0;\n  STATUS_ACTIVE = 1;\n  STATUS_IN_PROGRESS = 2;\n}",
+			"Status status = 1;",
+			"repeated Status history = 2;",
+			"int64 score = 3;",
+			"double ratio = 4;",
+		}},
+		{"sql", []string{
+			`"status" TEXT NOT NULL CHECK ("status" IN ('active', 'in-progress'))`,
+			`"history" JSONB NOT NULL`,
+			`"score" BIGINT NOT NULL`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			gen, err := generator.Get(tt.generator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			genCfg := config.GenConfig{Options: map[string]interface{}{
+				"lockFile": filepath.Join(t.TempDir(), "proto.lock"),
+			}}
+			out, err := gen.Generate(generator.NewContext(val, &config.Config{}, genCfg))
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output lacks %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package valibot
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Valibot schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Valibot generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "valibot"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of definitions. References to definitions
+// declared further down, which only happens in cycles, are wrapped in
+// v.lazy.
+type renderer struct {
+	names    map[string]string // TypeScript names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+}
+
+// Generate generates a module with a Valibot schema and an inferred type
+// per definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	graph := platoCue.NewRefGraph(defs)
+	order := graph.Order(cueNames)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("import * as v from 'valibot';\n")
+
+	for _, name := range order {
+		val := defs[name]
+		tsName := r.names[name]
+
+		buf.WriteString("\n")
+		schema := r.schema(val, 0)
+		if graph.Recursive(name) {
+			// A schema referring to itself cannot be inferred by TypeScript,
+			// so its type is written out and the schema annotated with it
+			types := &jsgen.Types{Ref: r.typeReference, Key: propertyName}
+			typ, err := types.Definition(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate type for %s: %w", name, err)
+			}
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export type %s = %s;\n", tsName, typ)
+			annotation := "v.GenericSchema<" + tsName + ">"
+			if types.Defaults {
+				annotation = "v.GenericSchema<unknown, " + tsName + ">"
+			}
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %sSchema: %s = %s;\n", tsName, annotation, schema)
+		} else {
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %sSchema = %s;\n", tsName, schema)
+			fmt.Fprintf(&buf, "export type %s = v.InferOutput<typeof %sSchema>;\n", tsName, tsName)
+		}
+		r.declared[name] = true
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// schema renders the Valibot schema of a value at an indentation level
+func (r *renderer) schema(val cue.Value, indent int) string {
+	if name, ok := r.reference(val); ok {
+		return name
+	}
+	if values := jsgen.LiteralEnum(val); len(values) > 1 {
+		return "v.picklist([" + strings.Join(values, ", ") + "])"
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]string, len(alts))
+		for i, alt := range alts {
+			items[i] = r.schema(alt, indent)
+		}
+		return nullableIf(nullable, "v.union(["+strings.Join(items, ", ")+"])")
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if name, ok := r.reference(val); ok {
+		return nullableIf(nullable, name)
+	}
+	return nullableIf(nullable, r.base(val, indent))
+}
+
+// reference renders a reference to a definition, lazily when the
+// definition is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	if !r.declared[path.String()] {
+		return "v.lazy(() => " + name + "Schema)", true
+	}
+	return name + "Schema", true
+}
+
+// typeReference renders a reference to a definition as its type name
+func (r *renderer) typeReference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	return name, ok
+}
+
+// base renders a value by its kind, with its constraints as actions
+func (r *renderer) base(val cue.Value, indent int) string {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok {
+			if val.Kind() == cue.NullKind {
+				return "v.null()"
+			}
+			return "v.literal(" + lit + ")"
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		actions := lengthActions(c, msgs, "minLength", "maxLength")
+		for _, p := range c.Patterns {
+			actions = append(actions, action("v.regex", "/"+jsgen.EscapeRegex(p)+"/", msgs.For("pattern")))
+		}
+		return pipe("v.string()", actions)
+	case kind == cue.IntKind:
+		return pipe("v.number()", append([]string{"v.integer()"}, rangeActions(c, msgs)...))
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return pipe("v.number()", rangeActions(c, msgs))
+	case kind == cue.BoolKind:
+		return "v.boolean()"
+	case kind == cue.NullKind:
+		return "v.null()"
+	case kind == cue.ListKind:
+		elem := "v.unknown()"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.schema(e, indent)
+		}
+		return pipe("v.array("+elem+")", lengthActions(c, msgs, "minItems", "maxItems"))
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			return "v.record(v.string(), " + r.schema(val.LookupPath(cue.MakePath(cue.AnyString)), indent) + ")"
+		case !platoCue.HasFields(val):
+			return "v.looseObject({})"
+		default:
+			return r.object(val, indent)
+		}
+	default:
+		return "v.unknown()"
+	}
+}
+
+// object renders a struct as v.object with one field per line. Fields with
+// a default are optional and take the default when missing.
+func (r *renderer) object(val cue.Value, indent int) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "v.looseObject({})"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("v.object({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		schema := r.schema(fieldVal, indent+1)
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			schema = "v.optional(" + schema + ", " + def + ")"
+		} else if iter.IsOptional() {
+			schema = "v.optional(" + schema + ")"
+		}
+
+		var doc bytes.Buffer
+		writeDoc(&doc, platoCue.Description(fieldVal), pad)
+		b.WriteString(doc.String())
+		fmt.Fprintf(&b, "%s%s: %s,\n", pad, propertyName(label), schema)
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+	return b.String()
+}
+
+// lengthActions renders v.minLength and v.maxLength for strings and lists,
+// with the @errmsg messages of the given keywords
+func lengthActions(c platoCue.Constraints, msgs platoCue.ErrorMessages, minKeyword, maxKeyword string) []string {
+	var actions []string
+	if c.MinLength != nil {
+		actions = append(actions, action("v.minLength", strconv.Itoa(*c.MinLength), msgs.For(minKeyword)))
+	}
+	if c.MaxLength != nil {
+		actions = append(actions, action("v.maxLength", strconv.Itoa(*c.MaxLength), msgs.For(maxKeyword)))
+	}
+	return actions
+}
+
+// rangeActions renders bounds as v.minValue, v.maxValue, v.gtValue and
+// v.ltValue
+func rangeActions(c platoCue.Constraints, msgs platoCue.ErrorMessages) []string {
+	var actions []string
+	if c.Minimum != nil {
+		actions = append(actions, action("v.minValue", jsgen.Number(*c.Minimum), msgs.For("minimum")))
+	}
+	if c.ExclusiveMinimum != nil {
+		actions = append(actions, action("v.gtValue", jsgen.Number(*c.ExclusiveMinimum), msgs.For("exclusiveMinimum")))
+	}
+	if c.Maximum != nil {
+		actions = append(actions, action("v.maxValue", jsgen.Number(*c.Maximum), msgs.For("maximum")))
+	}
+	if c.ExclusiveMaximum != nil {
+		actions = append(actions, action("v.ltValue", jsgen.Number(*c.ExclusiveMaximum), msgs.For("exclusiveMaximum")))
+	}
+	return actions
+}
+
+// action renders a validation action with an optional message
+func action(fn, arg, msg string) string {
+	if msg != "" {
+		return fn + "(" + arg + ", " + jsgen.String(msg) + ")"
+	}
+	return fn + "(" + arg + ")"
+}
+
+// pipe combines a schema with its actions in v.pipe
+func pipe(schema string, actions []string) string {
+	if len(actions) == 0 {
+		return schema
+	}
+	return "v.pipe(" + schema + ", " + strings.Join(actions, ", ") + ")"
+}
+
+// nullableIf wraps a schema in v.nullable
+func nullableIf(nullable bool, schema string) string {
+	if nullable {
+		return "v.nullable(" + schema + ")"
+	}
+	return schema
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -0,0 +1,424 @@
+package yup
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
+)
+
+// Generator generates Yup schemas from CUE
+type Generator struct{}
+
+// NewGenerator creates a new Yup generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Name returns the generator name
+func (g *Generator) Name() string {
+	return "yup"
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// renderer renders the schemas of definitions. References to definitions
+// declared further down, which only happens in cycles, are wrapped in
+// yup.lazy.
+type renderer struct {
+	names    map[string]string // TypeScript names by CUE definition name
+	declared map[string]bool   // definitions declared above the current one
+}
+
+// Generate generates a module with a Yup schema and an inferred type per
+// definition
+func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
+	defs, err := extractDefinitions(ctx.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract definitions: %w", err)
+	}
+
+	var cueNames []string
+	for name := range defs {
+		cueNames = append(cueNames, name)
+	}
+	sort.Strings(cueNames)
+
+	r := &renderer{names: make(map[string]string), declared: make(map[string]bool)}
+	used := make(map[string]bool)
+	for _, name := range cueNames {
+		r.names[name] = generator.UniqueName(used, toPascalCase(name), "")
+	}
+
+	// Declare definitions after the ones they refer to
+	graph := platoCue.NewRefGraph(defs)
+	order := graph.Order(cueNames)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by PlatoSL\n")
+	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
+	buf.WriteString("import * as yup from 'yup';\n")
+
+	for _, name := range order {
+		val := defs[name]
+		tsName := r.names[name]
+
+		buf.WriteString("\n")
+		schema := r.schema(val, 0)
+		if graph.Recursive(name) {
+			// A schema referring to itself cannot be inferred by TypeScript,
+			// so its type is written out and the schema checked against it
+			typ, err := (&jsgen.Types{Ref: r.typeReference, Key: propertyName}).Definition(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate type for %s: %w", name, err)
+			}
+			annotation := "yup.Schema<" + tsName + ">"
+			if strings.HasPrefix(schema, "yup.object({") {
+				annotation = "yup.ObjectSchema<" + tsName + ">"
+			}
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export type %s = %s;\n", tsName, typ)
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %sSchema: %s = %s;\n", tsName, annotation, schema)
+		} else {
+			writeDoc(&buf, platoCue.Description(val), "")
+			fmt.Fprintf(&buf, "export const %sSchema = %s;\n", tsName, schema)
+			fmt.Fprintf(&buf, "export type %s = yup.InferType<typeof %sSchema>;\n", tsName, tsName)
+		}
+		r.declared[name] = true
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate validates the generator context
+func (g *Generator) Validate(ctx *generator.Context) error {
+	if err := ctx.Value.Err(); err != nil {
+		return fmt.Errorf("invalid CUE value: %w", err)
+	}
+	return nil
+}
+
+// extractDefinitions extracts all definitions from a CUE value
+func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
+	defs := make(map[string]cue.Value)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = iter.Value()
+		}
+	}
+
+	return defs, nil
+}
+
+// schema renders the Yup schema of a value at an indentation level
+func (r *renderer) schema(val cue.Value, indent int) string {
+	if name, ok := r.reference(val); ok {
+		return name
+	}
+	if values := jsgen.LiteralEnum(val); len(values) > 1 {
+		_, args := val.Expr()
+		return oneOf(args[0].Kind(), args, values)
+	}
+	if alts, nullable := platoCue.Alternatives(val); alts != nil {
+		items := make([]string, len(alts))
+		for i, alt := range alts {
+			items[i] = r.schema(alt, indent)
+		}
+		// Yup has no unions; accept values valid against any branch
+		union := "yup.mixed().test(\"union\", \"${path} does not match any of the allowed types\", " +
+			"(value) => value === undefined || [" + strings.Join(items, ", ") + "].some((s) => s.isValidSync(value)))"
+		return nullableIf(nullable, union)
+	}
+
+	val, nullable := platoCue.StripNull(val)
+	if name, ok := r.reference(val); ok {
+		return nullableIf(nullable, name)
+	}
+	return nullableIf(nullable, r.base(val, indent))
+}
+
+// reference renders a reference to a definition, lazily when the
+// definition is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	if !ok {
+		return "", false
+	}
+	if !r.declared[path.String()] {
+		return lazyReference + name + "Schema)", true
+	}
+	return name + "Schema", true
+}
+
+// typeReference renders a reference to a definition as its type name
+func (r *renderer) typeReference(val cue.Value) (string, bool) {
+	_, path := val.ReferencePath()
+	if len(path.Selectors()) == 0 {
+		return "", false
+	}
+	name, ok := r.names[path.String()]
+	return name, ok
+}
+
+// base renders a value by its kind, with its constraints as tests
+func (r *renderer) base(val cue.Value, indent int) string {
+	if val.IsConcrete() {
+		if lit, ok := jsgen.Literal(val); ok {
+			return oneOf(val.Kind(), []cue.Value{val}, []string{lit})
+		}
+	}
+
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind == cue.StringKind:
+		tests := lengthTests(c, msgs, "minLength", "maxLength")
+		for _, p := range c.Patterns {
+			tests = append(tests, test("matches", "/"+jsgen.EscapeRegex(p)+"/", msgs.For("pattern")))
+		}
+		return "yup.string()" + strings.Join(tests, "")
+	case kind == cue.IntKind:
+		return "yup.number().integer()" + strings.Join(rangeTests(c, msgs), "")
+	case kind == cue.FloatKind || kind == cue.NumberKind:
+		return "yup.number()" + strings.Join(rangeTests(c, msgs), "")
+	case kind == cue.BoolKind:
+		return "yup.boolean()"
+	case kind == cue.NullKind:
+		return "yup.mixed().nullable().oneOf([null])"
+	case kind == cue.ListKind:
+		elem := "yup.mixed()"
+		if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.schema(e, indent)
+		}
+		return "yup.array(" + elem + ")" + strings.Join(lengthTests(c, msgs, "minItems", "maxItems"), "")
+	case kind == cue.StructKind:
+		switch {
+		case platoCue.IsMap(val):
+			// Yup has no records; check every key against the value schema
+			elem := r.schema(val.LookupPath(cue.MakePath(cue.AnyString)), indent)
+			return "yup.lazy((value) => yup.object(Object.fromEntries(Object.keys(value ?? {}).map((key) => [key, " + elem + "]))))"
+		case !platoCue.HasFields(val):
+			return "yup.object()"
+		default:
+			return r.object(val, indent)
+		}
+	default:
+		return "yup.mixed()"
+	}
+}
+
+// object renders a struct as yup.object with one field per line. Regular
+// fields are required, and fields with a default take it when missing.
+func (r *renderer) object(val cue.Value, indent int) string {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "yup.object()"
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("yup.object({\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		fieldVal := iter.Value()
+
+		schema := r.schema(fieldVal, indent+1)
+		schema = modify(schema, presence(fieldVal, schema, iter.IsOptional()))
+
+		var doc bytes.Buffer
+		writeDoc(&doc, platoCue.Description(fieldVal), pad)
+		b.WriteString(doc.String())
+		fmt.Fprintf(&b, "%s%s: %s,\n", pad, propertyName(label), schema)
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+	return b.String()
+}
+
+// presence renders the modifiers of a field: its default, .required() for
+// regular fields (.defined() when they may be null, as required rejects
+// null), and .default(undefined) for optional objects, which Yup would
+// otherwise fill in
+func presence(val cue.Value, schema string, optional bool) string {
+	if def, ok := platoCue.ScalarDefault(val); ok {
+		return ".default(" + def + ")"
+	}
+	nullable := strings.HasSuffix(schema, ".nullable()") || strings.HasSuffix(schema, ".nullable())")
+	switch {
+	case optional && (strings.HasPrefix(schema, "yup.object(") || !strings.HasPrefix(schema, "yup.") ||
+		strings.HasPrefix(schema, lazyReference)):
+		return ".default(undefined)"
+	case optional:
+		return ""
+	case nullable:
+		return ".defined()"
+	default:
+		return ".required()"
+	}
+}
+
+// oneOf renders a schema accepting only the given literals, typed by their
+// kind
+func oneOf(kind cue.Kind, args []cue.Value, values []string) string {
+	for _, arg := range args {
+		if arg.Kind() != kind {
+			kind = cue.TopKind
+		}
+	}
+	list := "[" + strings.Join(values, ", ") + "]"
+	switch kind {
+	case cue.StringKind:
+		return "yup.string().oneOf(" + list + ")"
+	case cue.IntKind, cue.FloatKind, cue.NumberKind:
+		return "yup.number().oneOf(" + list + ")"
+	case cue.BoolKind:
+		return "yup.boolean().oneOf(" + list + ")"
+	case cue.NullKind:
+		return "yup.mixed().nullable().oneOf(" + list + ")"
+	default:
+		return "yup.mixed().oneOf(" + list + ")"
+	}
+}
+
+// lengthTests renders .min and .max for strings and lists, with the
+// @errmsg messages of the given keywords
+func lengthTests(c platoCue.Constraints, msgs platoCue.ErrorMessages, minKeyword, maxKeyword string) []string {
+	var tests []string
+	if c.MinLength != nil {
+		tests = append(tests, test("min", strconv.Itoa(*c.MinLength), msgs.For(minKeyword)))
+	}
+	if c.MaxLength != nil {
+		tests = append(tests, test("max", strconv.Itoa(*c.MaxLength), msgs.For(maxKeyword)))
+	}
+	return tests
+}
+
+// rangeTests renders bounds as .min, .max, .moreThan and .lessThan
+func rangeTests(c platoCue.Constraints, msgs platoCue.ErrorMessages) []string {
+	var tests []string
+	if c.Minimum != nil {
+		tests = append(tests, test("min", jsgen.Number(*c.Minimum), msgs.For("minimum")))
+	}
+	if c.ExclusiveMinimum != nil {
+		tests = append(tests, test("moreThan", jsgen.Number(*c.ExclusiveMinimum), msgs.For("exclusiveMinimum")))
+	}
+	if c.Maximum != nil {
+		tests = append(tests, test("max", jsgen.Number(*c.Maximum), msgs.For("maximum")))
+	}
+	if c.ExclusiveMaximum != nil {
+		tests = append(tests, test("lessThan", jsgen.Number(*c.ExclusiveMaximum), msgs.For("exclusiveMaximum")))
+	}
+	return tests
+}
+
+// test renders a chained test method with an optional message
+func test(method, arg, msg string) string {
+	if msg != "" {
+		return "." + method + "(" + arg + ", " + jsgen.String(msg) + ")"
+	}
+	return "." + method + "(" + arg + ")"
+}
+
+// nullableIf allows null in a schema
+func nullableIf(nullable bool, schema string) string {
+	if nullable {
+		return modify(schema, ".nullable()")
+	}
+	return schema
+}
+
+// lazyReference starts a reference to a definition declared further down
+const lazyReference = "yup.lazy(() => "
+
+// modify chains a modifier to a schema. Lazy references are modified
+// inside, and lazy maps, which have no modifiers, are left as they are.
+func modify(schema, modifier string) string {
+	switch {
+	case modifier == "":
+		return schema
+	case strings.HasPrefix(schema, lazyReference):
+		return strings.TrimSuffix(schema, ")") + modifier + ")"
+	case strings.HasPrefix(schema, "yup.lazy("):
+		return schema
+	}
+	return schema + modifier
+}
+
+// propertyName renders an object key, quoted when it is not an identifier
+func propertyName(label string) string {
+	if identifier.MatchString(label) {
+		return label
+	}
+	return jsgen.String(label)
+}
+
+// writeDoc writes a doc comment as JSDoc
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// toPascalCase converts a definition name to a type name, e.g. #order_item
+// to OrderItem
+func toPascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range strings.TrimPrefix(name, "#") {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "T" + s
+	}
+	return s
+}
+
+func init() {
+	// Register the generator
+	generator.Register(NewGenerator())
+}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
 	"github.com/platoorg/plato-sl-cli/internal/generator"
+	"github.com/platoorg/plato-sl-cli/internal/generator/jsgen"
 )
 
 // Generator generates Zod schemas from CUE
@@ -32,6 +37,11 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	buf.WriteString("// DO NOT EDIT - This file is auto-generated\n\n")
 	buf.WriteString("import { z } from 'zod';\n\n")
 
+	policy, err := ctx.UnicodeOption()
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract definitions
 	defs, err := extractDefinitions(ctx.Value)
 	if err != nil {
@@ -45,24 +55,31 @@ func (g *Generator) Generate(ctx *generator.Context) ([]byte, error) {
 	}
 	sort.Strings(defNames)
 
-	// Generate Zod schemas
-	for _, name := range defNames {
+	// Generate Zod schemas, each after the definitions it refers to
+	r := &renderer{defs: defs, graph: platoCue.NewRefGraph(defs), policy: policy, declared: make(map[string]bool)}
+	for _, name := range r.graph.Order(defNames) {
 		val := defs[name]
-		tsName := toTypescriptName(name)
+		tsName := toTypescriptName(name, policy)
 
 		// Generate Zod schema
-		schema, err := generateZodSchema(tsName, val)
+		schema, err := r.generateZodSchema(name, tsName, val)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate Zod schema for %s: %w", name, err)
 		}
 		buf.WriteString(schema)
 		buf.WriteString("\n")
+		r.declared[name] = true
 	}
 
-	// Generate TypeScript types from Zod schemas
+	// Generate TypeScript types from Zod schemas; recursive definitions
+	// declare theirs above the schema
 	buf.WriteString("// TypeScript types inferred from Zod schemas\n")
 	for _, name := range defNames {
-		tsName := toTypescriptName(name)
+		if r.graph.Recursive(name) {
+			continue
+		}
+		tsName := toTypescriptName(name, policy)
+		writeDoc(&buf, platoCue.Description(defs[name]), "")
 		schemaName := tsName + "Schema"
 		buf.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %s>;\n", tsName, schemaName))
 	}
@@ -75,7 +92,8 @@ func (g *Generator) Validate(ctx *generator.Context) error {
 	if err := ctx.Value.Err(); err != nil {
 		return fmt.Errorf("invalid CUE value: %w", err)
 	}
-	return nil
+	_, err := ctx.UnicodeOption()
+	return err
 }
 
 // extractDefinitions extracts all definitions from a CUE value
@@ -97,115 +115,506 @@ func extractDefinitions(val cue.Value) (map[string]cue.Value, error) {
 	return defs, nil
 }
 
-// generateZodSchema generates a Zod schema
-func generateZodSchema(name string, val cue.Value) (string, error) {
+// renderer renders the Zod schemas of definitions. References to
+// definitions declared further down, which only happens in cycles, are
+// wrapped in z.lazy.
+type renderer struct {
+	defs     map[string]cue.Value
+	graph    *platoCue.RefGraph
+	policy   string
+	declared map[string]bool // definitions declared above the current one
+	defaults bool            // the object type being rendered has fields with a default
+}
+
+// generateZodSchema generates a Zod schema: z.object for a struct, the
+// schema of its type for other definitions, e.g. z.enum for enums, and for
+// a union of structs z.discriminatedUnion on the field the members tell
+// themselves apart by, or z.union when they share none. TypeScript cannot
+// infer the type of a recursive schema, so its type is declared first and
+// the schema typed z.ZodType<T>. Fields with a default are optional in
+// the input of the schema but not in T, so such a schema takes any input.
+func (r *renderer) generateZodSchema(cueName, name string, val cue.Value) (string, error) {
 	var buf bytes.Buffer
 
 	schemaName := name + "Schema"
+	if r.graph.Recursive(cueName) {
+		writeDoc(&buf, platoCue.Description(val), "")
+		r.defaults = false
+		typ, err := r.definitionType(val)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&buf, "export type %s = %s;\n", name, typ)
+		if r.defaults {
+			schemaName += ": z.ZodType<" + name + ", z.ZodTypeDef, unknown>"
+		} else {
+			schemaName += ": z.ZodType<" + name + ">"
+		}
+	}
+	writeDoc(&buf, platoCue.Description(val), "")
+	if u, ok := platoCue.UnionOf(val); ok {
+		if u.Discriminator != "" {
+			fmt.Fprintf(&buf, "export const %s = z.discriminatedUnion(%s, [\n", schemaName, jsgen.String(u.Discriminator))
+		} else {
+			fmt.Fprintf(&buf, "export const %s = z.union([\n", schemaName)
+		}
+		for _, m := range u.Members {
+			if ref, ok := r.reference(m); ok {
+				fmt.Fprintf(&buf, "  %s,\n", ref)
+				continue
+			}
+			buf.WriteString("  z.object({\n")
+			if err := r.writeObjectFields(&buf, m, "    "); err != nil {
+				return "", err
+			}
+			buf.WriteString("  }),\n")
+		}
+		buf.WriteString("]);\n")
+		return buf.String(), nil
+	}
+
+	// Definitions of enums, scalars and lists, e.g. #Status: "a" | "b" as
+	// z.enum(["a", "b"])
+	if val.IncompleteKind() != cue.StructKind {
+		fmt.Fprintf(&buf, "export const %s = %s;\n", schemaName, r.mapToZodType(val))
+		return buf.String(), nil
+	}
+
 	fmt.Fprintf(&buf, "export const %s = z.object({\n", schemaName)
+	if err := r.writeObjectFields(&buf, val, "  "); err != nil {
+		return "", err
+	}
+	buf.WriteString("});\n")
+
+	return buf.String(), nil
+}
+
+// writeObjectFields writes the fields of a z.object, one per line
+func (r *renderer) writeObjectFields(buf *bytes.Buffer, val cue.Value, indent string) error {
+	policy := r.policy
 
 	// Iterate fields
 	iter, err := val.Fields(cue.Optional(true))
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	for iter.Next() {
-		label := iter.Selector().String()
-		fieldVal := iter.Value()
-		optional := iter.IsOptional()
-
 		// Skip definitions
-		if strings.HasPrefix(label, "#") {
+		if iter.Selector().IsDefinition() {
 			continue
 		}
 
-		// Clean field name (remove CUE syntax markers like ! and ?)
-		cleanLabel := cleanFieldName(label)
+		fieldVal := iter.Value()
+		optional := iter.IsOptional()
+
+		// Field names are keys of the data and keep their spelling,
+		// quoted when they are not identifiers
+		cleanLabel := propertyKey(iter.Selector().Unquoted(), policy)
 
 		// Map to Zod type
-		zodType := mapToZodType(fieldVal)
+		zodType := r.mapToZodType(fieldVal)
 
-		// Add optional modifier
-		if optional {
+		// Fields with a default take it when missing, optional or not
+		if def, ok := platoCue.ScalarDefault(fieldVal); ok {
+			zodType = zodType + ".default(" + def + ")"
+		} else if optional {
 			zodType = zodType + ".optional()"
 		}
 
-		fmt.Fprintf(&buf, "  %s: %s,\n", cleanLabel, zodType)
-	}
-
-	buf.WriteString("});\n")
+		// Description, which the inferred type keeps, and unit /
+		// currency annotation
+		writeDoc(buf, platoCue.Description(fieldVal), indent)
+		if measure := platoCue.MeasureOf(fieldVal); !measure.IsZero() {
+			fmt.Fprintf(buf, "%s// %s\n", indent, measure)
+		}
 
-	return buf.String(), nil
+		fmt.Fprintf(buf, "%s%s: %s,\n", indent, cleanLabel, zodType)
+	}
+	return nil
 }
 
-// mapToZodType maps a CUE type to Zod
-func mapToZodType(val cue.Value) string {
-	kind := val.IncompleteKind()
+// mapToZodType maps a CUE type to Zod, with its constraints as checks:
+// patterns as .regex(), bounds as .min(), .max(), .gt() and .lt(), list
+// lengths as .min() and .max(), literals as z.literal(), and disjunctions
+// of literals as z.enum(), or a union of z.literal() when not all of them
+// are strings. References to definitions are their schemas, and a null
+// branch makes a value .nullable().
+func (r *renderer) mapToZodType(val cue.Value) string {
+	if ref, ok := r.reference(val); ok {
+		return ref
+	}
+	if rest, ok := platoCue.StripNull(val); ok {
+		return r.mapToZodType(rest) + ".nullable()"
+	}
+	if enum := literalEnum(val); len(enum.literals) > 1 {
+		return enum.zod()
+	}
+	if val.IsConcrete() && val.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) != 0 {
+		if data, err := val.MarshalJSON(); err == nil {
+			return "z.literal(" + string(data) + ")"
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
 
 	switch {
 	case kind&cue.StringKind != 0:
-		// Check for string constraints (regex, etc.)
-		// TODO: Add regex and other string constraints
-		return "z.string()"
-	case kind&cue.IntKind != 0:
-		return "z.number().int()"
-	case kind&cue.FloatKind != 0:
-		return "z.number()"
+		return "z.string()" + regexRefinements(val) + lengthRefinements(val)
+	case kind&cue.NumberKind == cue.IntKind:
+		return "z.number().int()" + rangeChecks(val)
 	case kind&cue.NumberKind != 0:
-		return "z.number()"
+		return "z.number()" + rangeChecks(val)
 	case kind&cue.BoolKind != 0:
 		return "z.boolean()"
 	case kind&cue.ListKind != 0:
-		elemType := getListElementZodType(val)
-		return fmt.Sprintf("z.array(%s)", elemType)
+		elemType := r.getListElementZodType(typed)
+		return fmt.Sprintf("z.array(%s)", elemType) + itemChecks(val)
 	case kind&cue.StructKind != 0:
-		// Check if it references a definition
-		if ref := getDefinitionReference(val); ref != "" {
-			return toTypescriptName(ref) + "Schema"
-		}
 		return "z.object({})"
 	default:
 		return "z.unknown()"
 	}
 }
 
+// definitionType renders the TypeScript type of a definition, the type
+// z.infer gives its schema: an object type for a struct, and a union of
+// the members for a union of structs
+func (r *renderer) definitionType(val cue.Value) (string, error) {
+	if u, ok := platoCue.UnionOf(val); ok {
+		members := make([]string, len(u.Members))
+		for i, m := range u.Members {
+			if ref, ok := r.typeReference(m); ok {
+				members[i] = ref
+				continue
+			}
+			typ, err := r.objectType(m, "")
+			if err != nil {
+				return "", err
+			}
+			members[i] = typ
+		}
+		return strings.Join(members, " | "), nil
+	}
+	if val.IncompleteKind() != cue.StructKind {
+		return r.mapToType(val), nil
+	}
+	return r.objectType(val, "")
+}
+
+// objectType renders the fields of a struct as an object type, one per
+// line. Fields with a default are always set.
+func (r *renderer) objectType(val cue.Value, indent string) (string, error) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	buf.WriteString("{\n")
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		fieldVal := iter.Value()
+		key := propertyKey(iter.Selector().Unquoted(), r.policy)
+		if _, ok := platoCue.ScalarDefault(fieldVal); ok {
+			r.defaults = true
+		} else if iter.IsOptional() {
+			key += "?"
+		}
+		fmt.Fprintf(&buf, "%s  %s: %s;\n", indent, key, r.mapToType(fieldVal))
+	}
+	buf.WriteString(indent + "}")
+	return buf.String(), nil
+}
+
+// mapToType maps a CUE type to the TypeScript type of its schema from
+// mapToZodType
+func (r *renderer) mapToType(val cue.Value) string {
+	if ref, ok := r.typeReference(val); ok {
+		return ref
+	}
+	if rest, ok := platoCue.StripNull(val); ok {
+		return r.mapToType(rest) + " | null"
+	}
+	if enum := literalEnum(val); len(enum.literals) > 1 {
+		return strings.Join(enum.literals, " | ")
+	}
+	if val.IsConcrete() && val.Kind()&(cue.StringKind|cue.NumberKind|cue.BoolKind) != 0 {
+		if data, err := val.MarshalJSON(); err == nil {
+			return string(data)
+		}
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+
+	switch {
+	case kind&cue.StringKind != 0:
+		return "string"
+	case kind&cue.NumberKind != 0:
+		return "number"
+	case kind&cue.BoolKind != 0:
+		return "boolean"
+	case kind&cue.ListKind != 0:
+		elem := "unknown"
+		if iter, err := typed.List(); err == nil && iter.Next() {
+			elem = r.mapToType(iter.Value())
+		} else if e := typed.LookupPath(cue.MakePath(cue.AnyIndex)); e.Exists() {
+			elem = r.mapToType(e)
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case kind&cue.StructKind != 0:
+		return "{}"
+	default:
+		return "unknown"
+	}
+}
+
+// typeReference renders a reference to a definition as its type name
+func (r *renderer) typeReference(val cue.Value) (string, bool) {
+	ref := platoCue.DefinitionRef(val)
+	if _, ok := r.defs[ref]; !ok {
+		return "", false
+	}
+	return toTypescriptName(ref, r.policy), true
+}
+
+// regexRefinements renders a .regex() call per =~ pattern, passing the
+// @errmsg message when one is set
+func regexRefinements(val cue.Value) string {
+	msgs := platoCue.ErrorMessagesOf(val)
+	var buf strings.Builder
+	for _, pattern := range platoCue.ConstraintsOf(val).Patterns {
+		fmt.Fprintf(&buf, ".regex(/%s/", jsgen.EscapeRegex(pattern))
+		if msg := msgs.For("pattern"); msg != "" {
+			fmt.Fprintf(&buf, ", %s", jsgen.String(msg))
+		}
+		buf.WriteString(")")
+	}
+	return buf.String()
+}
+
+// lengthRefinements renders the length limits of a string. CUE counts
+// runes, while .min() and .max() count UTF-16 code units, which differ for
+// emoji and other characters outside the Basic Multilingual Plane, so the
+// limits are refinements counting code points.
+func lengthRefinements(val cue.Value) string {
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+	var buf strings.Builder
+	refine := func(cond, keyword, fallback string) {
+		msg := msgs.For(keyword)
+		if msg == "" {
+			msg = fallback
+		}
+		fmt.Fprintf(&buf, ".refine((s) => %s, { message: %s })", cond, jsgen.String(msg))
+	}
+	if c.MinLength != nil {
+		refine(fmt.Sprintf("[...s].length >= %d", *c.MinLength), "minLength",
+			fmt.Sprintf("String must contain at least %d character(s)", *c.MinLength))
+	}
+	if c.MaxLength != nil {
+		refine(fmt.Sprintf("[...s].length <= %d", *c.MaxLength), "maxLength",
+			fmt.Sprintf("String must contain at most %d character(s)", *c.MaxLength))
+	}
+	return buf.String()
+}
+
+// rangeChecks renders the bounds of a number as .min(), .max(), .gt() and
+// .lt(), passing the @errmsg messages
+func rangeChecks(val cue.Value) string {
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+	var buf strings.Builder
+	for _, check := range []struct {
+		method, keyword string
+		limit           *float64
+	}{
+		{"min", "minimum", c.Minimum},
+		{"gt", "exclusiveMinimum", c.ExclusiveMinimum},
+		{"max", "maximum", c.Maximum},
+		{"lt", "exclusiveMaximum", c.ExclusiveMaximum},
+	} {
+		if check.limit != nil {
+			buf.WriteString(call(check.method, jsgen.Number(*check.limit), msgs.For(check.keyword)))
+		}
+	}
+	return buf.String()
+}
+
+// itemChecks renders the length limits of a list as .min() and .max(),
+// which count items as CUE does
+func itemChecks(val cue.Value) string {
+	c := platoCue.ConstraintsOf(val)
+	msgs := platoCue.ErrorMessagesOf(val)
+	var buf strings.Builder
+	if c.MinLength != nil {
+		buf.WriteString(call("min", strconv.Itoa(*c.MinLength), msgs.For("minItems")))
+	}
+	if c.MaxLength != nil {
+		buf.WriteString(call("max", strconv.Itoa(*c.MaxLength), msgs.For("maxItems")))
+	}
+	return buf.String()
+}
+
+// call renders a check method with an optional message
+func call(method, arg, msg string) string {
+	if msg != "" {
+		return "." + method + "(" + arg + ", " + jsgen.String(msg) + ")"
+	}
+	return "." + method + "(" + arg + ")"
+}
+
+// enum holds the literals of a disjunction, rendered as TypeScript, and
+// whether they are all strings
+type enum struct {
+	literals []string
+	strings  bool
+}
+
+// zod renders an enum as z.enum() for strings, or a union of z.literal()
+func (e enum) zod() string {
+	if e.strings {
+		return "z.enum([" + strings.Join(e.literals, ", ") + "])"
+	}
+	items := make([]string, len(e.literals))
+	for i, lit := range e.literals {
+		items[i] = "z.literal(" + lit + ")"
+	}
+	return "z.union([" + strings.Join(items, ", ") + "])"
+}
+
+// literalEnum returns the distinct literals of a disjunction of string or
+// number literals, e.g. "draft" | "published"
+func literalEnum(val cue.Value) enum {
+	op, args := val.Expr()
+	if op != cue.OrOp {
+		return enum{}
+	}
+
+	e := enum{strings: true}
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if !arg.IsConcrete() || arg.Kind()&(cue.StringKind|cue.NumberKind) == 0 {
+			return enum{}
+		}
+		data, err := arg.MarshalJSON()
+		if err != nil {
+			return enum{}
+		}
+		if arg.Kind() != cue.StringKind {
+			e.strings = false
+		}
+		if lit := string(data); !seen[lit] {
+			seen[lit] = true
+			e.literals = append(e.literals, lit)
+		}
+	}
+	return e
+}
+
+// writeDoc writes a description as a JSDoc comment
+func writeDoc(buf *bytes.Buffer, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s", strings.TrimRight(indent+" * "+line, " ")+"\n")
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
 // getListElementZodType gets the Zod element type of a list
-func getListElementZodType(val cue.Value) string {
+func (r *renderer) getListElementZodType(val cue.Value) string {
 	// Try to get the first element or list constraint
 	iter, err := val.List()
 	if err == nil && iter.Next() {
-		return mapToZodType(iter.Value())
+		return r.mapToZodType(iter.Value())
+	}
+	if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+		return r.mapToZodType(elem)
 	}
 	return "z.unknown()"
 }
 
-// getDefinitionReference checks if a value references a definition
-func getDefinitionReference(val cue.Value) string {
-	// This is a simplified implementation
-	// In a real implementation, you'd walk the value to find definition references
-	return ""
+// reference renders a reference to a definition as its schema, lazily
+// when the definition is declared further down
+func (r *renderer) reference(val cue.Value) (string, bool) {
+	ref := platoCue.DefinitionRef(val)
+	if _, ok := r.defs[ref]; !ok {
+		return "", false
+	}
+	schema := toTypescriptName(ref, r.policy) + "Schema"
+	if !r.declared[ref] {
+		return "z.lazy(() => " + schema + ")", true
+	}
+	return schema, true
 }
 
 // toTypescriptName converts a CUE definition name to TypeScript
-func toTypescriptName(name string) string {
+func toTypescriptName(name, policy string) string {
 	// Remove leading # and ensure PascalCase
 	name = strings.TrimPrefix(name, "#")
+	r, size := utf8.DecodeRuneInString(name)
+	name = string(unicode.ToUpper(r)) + name[size:]
+	return generator.UnicodeName(policy, name, escapeRune)
+}
 
-	// Basic conversion
-	if len(name) > 0 {
-		name = strings.ToUpper(name[:1]) + name[1:]
+// propertyKey renders a property name, quoting it when it is not an
+// identifier. Under the escape unicode policy, non-ASCII runes are written
+// as escapes, which leaves the name the same at runtime.
+func propertyKey(name, policy string) string {
+	if isIdentifier(name) {
+		if policy == generator.UnicodeEscape {
+			return generator.UnicodeName(policy, name, escapeRune)
+		}
+		return name
+	}
+	if policy != generator.UnicodeEscape {
+		return jsgen.String(name)
+	}
+	var b strings.Builder
+	for _, r := range jsgen.String(name) {
+		if r < utf8.RuneSelf {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(escapeRune(r))
+		}
 	}
+	return b.String()
+}
 
-	return name
+// isIdentifier reports whether a name is a JavaScript identifier
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)):
+		default:
+			return false
+		}
+	}
+	return name != ""
 }
 
-// cleanFieldName removes CUE syntax markers from field names
-func cleanFieldName(name string) string {
-	// Remove trailing ! (required marker) and ? (optional marker)
-	name = strings.TrimSuffix(name, "!")
-	name = strings.TrimSuffix(name, "?")
-	return name
+// escapeRune writes a rune as a JavaScript escape, valid in identifiers
+// and strings
+func escapeRune(r rune) string {
+	if r > 0xFFFF {
+		return fmt.Sprintf(`\u{%X}`, r)
+	}
+	return fmt.Sprintf(`\u%04X`, r)
 }
 
 func init() {
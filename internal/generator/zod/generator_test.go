@@ -0,0 +1,71 @@
+package zod
+
+import (
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// generate renders CUE source with the Zod generator
+func generate(t *testing.T, src string) string {
+	t.Helper()
+	val := cuecontext.New().CompileString(src)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := NewGenerator().Generate(generator.NewContext(val, &config.Config{}, config.GenConfig{}))
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return string(out)
+}
+
+func TestEnumDefinition(t *testing.T) {
+	out := generate(t, `
+#Status: "active" | "inactive"
+#Score: int & >=0
+#User: {status: #Status}
+`)
+	for _, want := range []string{
+		`export const StatusSchema = z.enum(["active", "inactive"]);`,
+		`export const ScoreSchema = z.number().int().min(0);`,
+		`status: StatusSchema,`,
+		`export type Status = z.infer<typeof StatusSchema>;`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRecursiveDefinition(t *testing.T) {
+	out := generate(t, `
+#Node: {
+	name:      string
+	children?: [...#Node]
+	next:      #Node | null
+}
+#Comment: {text: string, replies: [...#Reply]}
+#Reply: {text: string, parent: #Comment, pinned: *false | bool}
+`)
+	for _, want := range []string{
+		"export type Node = {\n  name: string;\n  children?: Node[];\n  next: Node | null;\n};",
+		`export const NodeSchema: z.ZodType<Node> = z.object({`,
+		`children: z.array(z.lazy(() => NodeSchema)).optional(),`,
+		`export const CommentSchema: z.ZodType<Comment> = z.object({`,
+		"export type Reply = {\n  text: string;\n  parent: Comment;\n  pinned: boolean;\n};",
+		`export const ReplySchema: z.ZodType<Reply, z.ZodTypeDef, unknown> = z.object({`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"z.ZodTypeAny", "export type Node = z.infer"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q:\n%s", unwanted, out)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultRetries    = 3
+	defaultBackoffMin = 500 * time.Millisecond
+	defaultBackoffMax = 30 * time.Second
+)
+
+// TokenFunc returns the auth token for a host, or an empty string
+type TokenFunc func(host string) string
+
+// Options configures a Client
+type Options struct {
+	Timeout    time.Duration
+	Retries    int
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+	RateLimit  float64
+	Proxy      string
+	CAFile     string
+	UserAgent  string
+	Token      TokenFunc
+}
+
+// Client is an HTTP client with retries, backoff, rate limiting and auth
+type Client struct {
+	http *http.Client
+	opts Options
+
+	mu       sync.Mutex
+	lastSent time.Time
+}
+
+// OptionsFromConfig builds client options from the network config section
+func OptionsFromConfig(cfg config.NetworkConfig) (Options, error) {
+	opts := Options{
+		Timeout:    defaultTimeout,
+		Retries:    defaultRetries,
+		BackoffMin: defaultBackoffMin,
+		BackoffMax: defaultBackoffMax,
+		RateLimit:  cfg.RateLimit,
+		Proxy:      cfg.Proxy,
+		CAFile:     cfg.CAFile,
+		Token:      TokenChain(EnvTokens(), ConfigTokens(cfg.Tokens)),
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid network.timeout %q: %w", cfg.Timeout, err)
+		}
+		opts.Timeout = timeout
+	}
+
+	if cfg.Retries != nil {
+		opts.Retries = *cfg.Retries
+	}
+
+	return opts, nil
+}
+
+// New creates a client from options
+func New(opts Options) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", opts.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.BackoffMin == 0 {
+		opts.BackoffMin = defaultBackoffMin
+	}
+	if opts.BackoffMax == 0 {
+		opts.BackoffMax = defaultBackoffMax
+	}
+
+	return &Client{
+		http: &http.Client{Timeout: opts.Timeout, Transport: transport},
+		opts: opts,
+	}, nil
+}
+
+// Do sends a request, retrying network errors, 429 and 5xx responses with
+// exponential backoff. The request body must be replayable (GetBody set),
+// which is the case for requests created with bytes or strings readers.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.opts.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+	}
+	if c.opts.Token != nil && req.Header.Get("Authorization") == "" {
+		if token := c.opts.Token(req.URL.Hostname()); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.Retries; attempt++ {
+		if attempt > 0 {
+			if req.Body != nil {
+				if req.GetBody == nil {
+					return nil, fmt.Errorf("%s %s: request body cannot be retried: %w", req.Method, req.URL, lastErr)
+				}
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		c.throttle()
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			c.sleep(attempt, "")
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%s", resp.Status)
+			if attempt < c.opts.Retries {
+				retryAfter := resp.Header.Get("Retry-After")
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				c.sleep(attempt, retryAfter)
+				continue
+			}
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("%s %s: giving up after %d attempt(s): %w", req.Method, req.URL, c.opts.Retries+1, lastErr)
+}
+
+// DoJSON sends body as JSON and decodes a JSON response into out (if non-nil).
+// Non-2xx responses are returned as errors including the response body.
+func (c *Client) DoJSON(method, rawURL string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, rawURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &StatusError{Method: method, URL: rawURL, Status: resp.Status, Code: resp.StatusCode, Body: string(bytes.TrimSpace(msg))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", rawURL, err)
+	}
+	return nil
+}
+
+// StatusError is returned by DoJSON for non-2xx responses
+type StatusError struct {
+	Method string
+	URL    string
+	Status string
+	Code   int
+	Body   string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("%s %s: %s: %s", e.Method, e.URL, e.Status, e.Body)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+}
+
+// throttle blocks until the rate limit allows another request
+func (c *Client) throttle() {
+	if c.opts.RateLimit <= 0 {
+		return
+	}
+
+	interval := time.Duration(float64(time.Second) / c.opts.RateLimit)
+
+	c.mu.Lock()
+	wait := time.Until(c.lastSent.Add(interval))
+	if wait < 0 {
+		wait = 0
+	}
+	c.lastSent = time.Now().Add(wait)
+	c.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// sleep waits before the next attempt, honouring Retry-After when present
+// up to the maximum backoff, so a server cannot stall the client
+func (c *Client) sleep(attempt int, retryAfter string) {
+	if attempt >= c.opts.Retries {
+		return
+	}
+
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+		wait := time.Duration(secs) * time.Second
+		if wait > c.opts.BackoffMax || wait < 0 {
+			wait = c.opts.BackoffMax
+		}
+		time.Sleep(wait)
+		return
+	}
+
+	backoff := c.opts.BackoffMin << attempt
+	if backoff > c.opts.BackoffMax || backoff <= 0 {
+		backoff = c.opts.BackoffMax
+	}
+
+	// Full jitter keeps concurrent clients from retrying in lockstep
+	time.Sleep(time.Duration(rand.Int63n(int64(backoff) + 1)))
+}
@@ -0,0 +1,64 @@
+package httpclient
+
+import (
+	"os"
+	"strings"
+)
+
+// TokenChain returns a TokenFunc that tries each source in order
+func TokenChain(sources ...TokenFunc) TokenFunc {
+	return func(host string) string {
+		for _, source := range sources {
+			if source == nil {
+				continue
+			}
+			if token := source(host); token != "" {
+				return token
+			}
+		}
+		return ""
+	}
+}
+
+// EnvTokens resolves tokens from PLATOSL_TOKEN_<HOST> (host upper-cased with
+// non-alphanumerics replaced by underscores). PLATOSL_TOKEN is the token of
+// the registry: it is only the fallback for the given registry hosts, so it
+// is not sent to other servers.
+func EnvTokens(registries ...string) TokenFunc {
+	return func(host string) string {
+		if token := os.Getenv(HostEnvVar(host)); token != "" {
+			return token
+		}
+		for _, registry := range registries {
+			if strings.EqualFold(host, registry) {
+				return os.Getenv("PLATOSL_TOKEN")
+			}
+		}
+		return ""
+	}
+}
+
+// HostEnvVar returns the per-host token environment variable name
+func HostEnvVar(host string) string {
+	var b strings.Builder
+	b.WriteString("PLATOSL_TOKEN_")
+	for _, r := range strings.ToUpper(host) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// ConfigTokens resolves tokens from the network.tokens config map. Values
+// are expanded with environment variables so secrets can stay out of the file.
+func ConfigTokens(tokens map[string]string) TokenFunc {
+	return func(host string) string {
+		if token, ok := tokens[host]; ok {
+			return os.ExpandEnv(token)
+		}
+		return ""
+	}
+}
@@ -0,0 +1,54 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvTokens(t *testing.T) {
+	t.Setenv("PLATOSL_TOKEN", "registry-token")
+	t.Setenv("PLATOSL_TOKEN_CATALOG_EXAMPLE_COM", "catalog-token")
+
+	tokens := EnvTokens("registry.example.com")
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"registry.example.com", "registry-token"},
+		{"REGISTRY.example.com", "registry-token"},
+		{"catalog.example.com", "catalog-token"},
+		{"draft.example.com", ""},
+		{"api.github.com", ""},
+	}
+	for _, tt := range tests {
+		if got := tokens(tt.host); got != tt.want {
+			t.Errorf("EnvTokens(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+
+	if got := EnvTokens()("registry.example.com"); got != "" {
+		t.Errorf("EnvTokens() without registries = %q, want no token", got)
+	}
+}
+
+func TestRegistryTokenNotSentToOtherHosts(t *testing.T) {
+	t.Setenv("PLATOSL_TOKEN", "secret-registry-token")
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	client, err := New(Options{Token: TokenChain(EnvTokens("registry.example.com"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DoJSON(http.MethodGet, server.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "" {
+		t.Errorf("Authorization = %q, want none", auth)
+	}
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+// CatalogEntry is a message in a JSON message catalog
+type CatalogEntry struct {
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context,omitempty"`
+}
+
+// JSONCatalog renders a message catalog skeleton keyed by message ID
+func JSONCatalog(messages []Message) ([]byte, error) {
+	catalog := make(map[string]CatalogEntry, len(messages))
+	for _, m := range messages {
+		catalog[m.ID()] = CatalogEntry{
+			Description: m.Description,
+			Context:     m.Context,
+		}
+	}
+	return json.MarshalIndent(catalog, "", "  ")
+}
+
+// xliff is an XLIFF 2.0 document
+type xliff struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr,omitempty"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID    string      `xml:"id,attr"`
+	Units []xliffUnit `xml:"unit"`
+}
+
+type xliffUnit struct {
+	ID      string       `xml:"id,attr"`
+	Notes   *xliffNotes  `xml:"notes,omitempty"`
+	Segment xliffSegment `xml:"segment"`
+}
+
+type xliffNotes struct {
+	Notes []xliffNote `xml:"note"`
+}
+
+type xliffNote struct {
+	Category string `xml:"category,attr"`
+	Text     string `xml:",chardata"`
+}
+
+type xliffSegment struct {
+	Source string  `xml:"source"`
+	Target *string `xml:"target,omitempty"`
+}
+
+// XLIFFCatalog renders an XLIFF 2.0 skeleton with one <file> per definition.
+// A target element is included when a target locale is given.
+func XLIFFCatalog(messages []Message, sourceLocale, targetLocale string) ([]byte, error) {
+	if sourceLocale == "" {
+		return nil, fmt.Errorf("XLIFF requires a source locale")
+	}
+
+	doc := xliff{
+		Version: "2.0",
+		SrcLang: sourceLocale,
+		TrgLang: targetLocale,
+	}
+
+	files := make(map[string]int)
+	for _, m := range messages {
+		idx, ok := files[m.Definition]
+		if !ok {
+			idx = len(doc.Files)
+			files[m.Definition] = idx
+			doc.Files = append(doc.Files, xliffFile{ID: m.Definition})
+		}
+
+		unit := xliffUnit{ID: m.ID()}
+		if m.Description != "" || m.Context != "" {
+			unit.Notes = &xliffNotes{}
+			if m.Description != "" {
+				unit.Notes.Notes = append(unit.Notes.Notes, xliffNote{Category: "description", Text: m.Description})
+			}
+			if m.Context != "" {
+				unit.Notes.Notes = append(unit.Notes.Notes, xliffNote{Category: "context", Text: m.Context})
+			}
+		}
+		if targetLocale != "" {
+			empty := ""
+			unit.Segment.Target = &empty
+		}
+
+		doc.Files[idx].Units = append(doc.Files[idx].Units, unit)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
+)
+
+// Problem is a localization gap found by Check
+type Problem struct {
+	Locale  string
+	File    string
+	Message string
+}
+
+// String formats the problem as "locale/file: message"
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", filepath.Join(p.Locale, p.File), p.Message)
+}
+
+// Check verifies that localized data files cover all required locales.
+//
+// Data is laid out as <dir>/<locale>/<file>. Every file present for any
+// locale must exist for all locales, and every required translatable field
+// must be present and non-empty in each of them.
+func Check(messages []Message, dir string, locales []string) ([]Problem, error) {
+	if len(locales) == 0 {
+		return nil, fmt.Errorf("no locales given")
+	}
+
+	// Collect data files per locale, relative to the locale directory
+	files := make(map[string]map[string]bool)
+	all := make(map[string]bool)
+	for _, locale := range locales {
+		files[locale] = make(map[string]bool)
+		root := filepath.Join(dir, locale)
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == root {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() || !platoCue.IsDataFile(path) {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			files[locale][rel] = true
+			all[rel] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	var names []string
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Check files in parallel, keeping problems in name order
+	found := make([][]Problem, len(names))
+	workers.Each(len(names), func(i int) error {
+		name := names[i]
+		for _, locale := range locales {
+			if !files[locale][name] {
+				found[i] = append(found[i], Problem{Locale: locale, File: name, Message: "missing localized file"})
+				continue
+			}
+
+			data, err := platoCue.ReadDataFile(filepath.Join(dir, locale, name))
+			if err != nil {
+				found[i] = append(found[i], Problem{Locale: locale, File: name, Message: err.Error()})
+				continue
+			}
+
+			for _, m := range messages {
+				if msg := checkMessage(data, m); msg != "" {
+					found[i] = append(found[i], Problem{Locale: locale, File: name, Message: msg})
+				}
+			}
+		}
+		return nil
+	})
+
+	var problems []Problem
+	for _, p := range found {
+		problems = append(problems, p...)
+	}
+	return problems, nil
+}
+
+// checkMessage checks a single translatable field in decoded data
+func checkMessage(data interface{}, m Message) string {
+	values, err := platoCue.SelectPresent(data, m.Path)
+	if err != nil {
+		return err.Error()
+	}
+
+	if !m.Optional {
+		// Compare with the number of parents, so a list element missing the
+		// field is reported too
+		expected := 1
+		if idx := strings.LastIndex(m.Path, "."); idx > 0 {
+			parents, err := platoCue.SelectPresent(data, m.Path[:idx])
+			if err != nil {
+				return err.Error()
+			}
+			expected = len(parents)
+		}
+		if len(values) < expected {
+			return fmt.Sprintf("missing translation for %s", m.Path)
+		}
+	}
+
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Sprintf("%s must be a string", m.Path)
+		}
+		if strings.TrimSpace(s) == "" {
+			return fmt.Sprintf("empty translation for %s", m.Path)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// maxDepth limits how deep nested structs are searched for translatable fields
+const maxDepth = 8
+
+// Message is a translatable field of a definition
+type Message struct {
+	// Definition is the definition name without the leading #
+	Definition string `json:"definition"`
+
+	// Path is the field path within the definition, e.g. "variants[*].name"
+	Path string `json:"path"`
+
+	// Optional reports whether the field (or one of its parents) is optional
+	Optional bool `json:"optional"`
+
+	// Description is the field's doc comment, passed on to translators
+	Description string `json:"description,omitempty"`
+
+	// Context is the @i18n argument (e.g. @i18n("button label")), if any
+	Context string `json:"context,omitempty"`
+}
+
+// ID returns the message identifier, e.g. "Product.title"
+func (m Message) ID() string {
+	return m.Definition + "." + m.Path
+}
+
+// Extract collects all string fields marked with @i18n from the definitions
+// of a CUE value, sorted by message ID.
+func Extract(val cue.Value) ([]Message, error) {
+	var messages []Message
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate definitions: %w", err)
+	}
+
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			continue
+		}
+		def := strings.TrimPrefix(iter.Selector().String(), "#")
+		if err := extractFields(iter.Value(), def, "", false, 0, &messages); err != nil {
+			return nil, fmt.Errorf("%s: %w", def, err)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID() < messages[j].ID() })
+	return messages, nil
+}
+
+// ExtractDefinition collects the translatable fields of a single definition
+func ExtractDefinition(val cue.Value, name string) ([]Message, error) {
+	def, err := platoCue.LookupDefinition(val, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	if err := extractFields(def, strings.TrimPrefix(name, "#"), "", false, 0, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// extractFields walks a struct value and records fields marked with @i18n
+func extractFields(val cue.Value, def, prefix string, optional bool, depth int, out *[]Message) error {
+	if depth > maxDepth {
+		return nil
+	}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		path := iter.Selector().Unquoted()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		field := iter.Value()
+		fieldOptional := optional || iter.IsOptional()
+
+		if attr, ok := platoCue.GetAttr(field, "i18n"); ok {
+			if field.IncompleteKind() != cue.StringKind {
+				return fmt.Errorf("@i18n on non-string field %s", path)
+			}
+			*out = append(*out, Message{
+				Definition:  def,
+				Path:        path,
+				Optional:    fieldOptional,
+				Description: platoCue.Description(field),
+				Context:     attr.Arg(0),
+			})
+			continue
+		}
+
+		switch field.IncompleteKind() {
+		case cue.StructKind:
+			if err := extractFields(field, def, path, fieldOptional, depth+1, out); err != nil {
+				return err
+			}
+		case cue.ListKind:
+			elem := field.LookupPath(cue.MakePath(cue.AnyIndex))
+			if elem.Exists() && elem.IncompleteKind() == cue.StructKind {
+				if err := extractFields(elem, def, path+"[*]", fieldOptional, depth+1, out); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package importcache
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/modregistry"
+	"cuelang.org/go/mod/module"
+)
+
+// Registry resolves the modules imported by schemas from the CUE registry
+// (CUE_REGISTRY, registry.cue.works by default), keeping their module files
+// and zips in a Store. It implements modconfig.Registry for load.Config.
+//
+// The CUE loader reads modules from the OS file system, so zips are
+// extracted to a directory per module version on first use; the store
+// remains the record of what is cached and when it was last used.
+//
+// The store and the registry client are set up on first use, so commands
+// that load no modules neither open the cache nor read registry logins.
+type Registry struct {
+	backend    string
+	dir        string
+	extractDir string
+
+	// Offline is called when the registry cannot be reached but the cache
+	// can answer instead; unless it returns an error, the cached answer is
+	// used. Without it, failures are errors.
+	Offline func(err error) error
+
+	once   sync.Once
+	store  Store
+	client *modregistry.Client
+	err    error
+}
+
+// NewRegistry returns a registry caching in the store of a backend in dir
+// (see Open), with modules extracted under dir/extract
+func NewRegistry(backend, dir string) *Registry {
+	return &Registry{backend: backend, dir: dir, extractDir: filepath.Join(dir, "extract")}
+}
+
+func (r *Registry) init() error {
+	r.once.Do(func() {
+		if r.store, r.err = Open(r.backend, r.dir); r.err != nil {
+			r.err = fmt.Errorf("failed to open import cache: %w", r.err)
+			return
+		}
+		resolver, err := modconfig.NewResolver(nil)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.client = modregistry.NewClientWithResolver(resolver)
+	})
+	return r.err
+}
+
+// Requirements implements modconfig.Registry
+func (r *Registry) Requirements(ctx context.Context, mv module.Version) ([]module.Version, error) {
+	if err := r.init(); err != nil {
+		return nil, err
+	}
+	data, err := r.fetch(mv.String()+"/module.cue", func() ([]byte, error) {
+		m, err := r.client.GetModule(ctx, mv)
+		if err != nil {
+			return nil, err
+		}
+		return m.ModuleFile(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	mf, err := modfile.Parse(data, mv.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse module file from %v: %w", mv, err)
+	}
+	return mf.DepVersions(), nil
+}
+
+// Fetch implements modconfig.Registry
+func (r *Registry) Fetch(ctx context.Context, mv module.Version) (module.SourceLoc, error) {
+	if err := r.init(); err != nil {
+		return module.SourceLoc{}, err
+	}
+	data, err := r.fetch(mv.String()+"/module.zip", func() ([]byte, error) {
+		m, err := r.client.GetModule(ctx, mv)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := m.GetZip(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	})
+	if err != nil {
+		return module.SourceLoc{}, err
+	}
+	return r.sourceLoc(mv, data)
+}
+
+// FetchFromCache implements modconfig.CachedRegistry
+func (r *Registry) FetchFromCache(mv module.Version) (module.SourceLoc, error) {
+	if err := r.init(); err != nil {
+		return module.SourceLoc{}, err
+	}
+	data, err := r.store.Get(mv.String() + "/module.zip")
+	if errors.Is(err, ErrNotFound) {
+		return module.SourceLoc{}, modregistry.ErrNotFound
+	}
+	if err != nil {
+		return module.SourceLoc{}, err
+	}
+	return r.sourceLoc(mv, data)
+}
+
+// ModuleVersions implements modconfig.Registry. Versions are not cached,
+// so new releases are seen as soon as they are published; when the
+// registry cannot be reached, Offline may allow the cached versions.
+func (r *Registry) ModuleVersions(ctx context.Context, mpath string) ([]string, error) {
+	if err := r.init(); err != nil {
+		return nil, err
+	}
+	versions, err := r.client.ModuleVersions(ctx, mpath)
+	if err == nil || r.Offline == nil {
+		return versions, err
+	}
+	cached, cacheErr := r.cachedVersions(mpath)
+	if cacheErr != nil || len(cached) == 0 {
+		return nil, err
+	}
+	if err := r.Offline(fmt.Errorf("cannot list versions of %s, using the %d cached: %w", mpath, len(cached), err)); err != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+// cachedVersions returns the versions of a module in the cache, in semver
+// order. A major version suffix in mpath selects that major version only.
+func (r *Registry) cachedVersions(mpath string) ([]string, error) {
+	base, _, _ := ast.SplitPackageVersion(mpath)
+	entries, err := r.store.List(base + "@")
+	if err != nil {
+		return nil, err
+	}
+	var mvs []module.Version
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		key, ok := strings.CutSuffix(e.Key, "/module.cue")
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		version := strings.TrimPrefix(key, base+"@")
+		if mv, err := module.NewVersion(mpath, version); err == nil {
+			mvs = append(mvs, mv)
+		}
+	}
+	module.Sort(mvs)
+	versions := make([]string, len(mvs))
+	for i, mv := range mvs {
+		versions[i] = mv.Version()
+	}
+	return versions, nil
+}
+
+// Store returns the store, opening it if needed
+func (r *Registry) Store() (Store, error) {
+	if err := r.init(); err != nil {
+		return nil, err
+	}
+	return r.store, nil
+}
+
+// Remove removes a module version, e.g. example.com/schemas@v1.2.0, from
+// the cache
+func (r *Registry) Remove(mv string) error {
+	if err := r.init(); err != nil {
+		return err
+	}
+	for _, key := range []string{mv + "/module.cue", mv + "/module.zip"} {
+		if err := r.store.Delete(key); err != nil {
+			return err
+		}
+	}
+	if err := checkKey(mv); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(r.extractDir, filepath.FromSlash(mv)))
+}
+
+// Close closes the store if it was opened
+func (r *Registry) Close() error {
+	if r.store == nil {
+		return nil
+	}
+	return r.store.Close()
+}
+
+// fetch returns a cached entry, downloading and storing it on a miss
+func (r *Registry) fetch(key string, download func() ([]byte, error)) ([]byte, error) {
+	data, err := r.store.Get(key)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if data, err = download(); err != nil {
+		return nil, err
+	}
+	if err := r.store.Put(key, data); err != nil {
+		return nil, fmt.Errorf("failed to cache %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// sourceLoc serves the files of a module version from its extracted
+// directory, extracting its zip when the directory does not exist yet
+func (r *Registry) sourceLoc(mv module.Version, data []byte) (module.SourceLoc, error) {
+	dir := filepath.Join(r.extractDir, filepath.FromSlash(mv.String()))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := extract(dir, data); err != nil {
+			return module.SourceLoc{}, fmt.Errorf("failed to extract %v: %w", mv, err)
+		}
+	}
+	return module.SourceLoc{FS: module.OSDirFS(dir), Dir: "."}, nil
+}
+
+// extract unpacks a module zip to dir. Files are unpacked to a temporary
+// directory that is renamed into place, so concurrent loads never see a
+// partial module.
+func extract(dir string, data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid zip in the import cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if err := checkKey(f.Name); err != nil {
+			return fmt.Errorf("invalid file name %q in zip", f.Name)
+		}
+		path := filepath.Join(tmp, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := extractFile(path, f); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		// Another process extracted the module first
+		if _, statErr := os.Stat(dir); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func extractFile(path string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,107 @@
+package importcache
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// schema creates the entries table; used is in Unix nanoseconds
+const schema = `CREATE TABLE IF NOT EXISTS entries (
+	key  TEXT PRIMARY KEY,
+	data BLOB NOT NULL,
+	size INTEGER NOT NULL,
+	used INTEGER NOT NULL
+)`
+
+// SQLite stores entries in a single SQLite database. The database is in
+// WAL mode with a busy timeout, so a long-running serve or watch process
+// and other invocations can use the cache at the same time, and listing
+// entries does not read their data.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens or creates a database store
+func OpenSQLite(path string) (*SQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	dsn := (&url.URL{
+		Scheme:   "file",
+		Path:     filepath.ToSlash(path),
+		RawQuery: "_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)",
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open cache database %s: %w", path, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Get implements Store
+func (s *SQLite) Get(key string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM entries WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, _ = s.db.Exec(`UPDATE entries SET used = ? WHERE key = ?`, time.Now().UnixNano(), key)
+	return data, nil
+}
+
+// Put implements Store
+func (s *SQLite) Put(key string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`INSERT INTO entries (key, data, size, used) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET data = excluded.data, size = excluded.size, used = excluded.used`,
+		key, data, len(data), time.Now().UnixNano())
+	return err
+}
+
+// Delete implements Store
+func (s *SQLite) Delete(key string) error {
+	_, err := s.db.Exec(`DELETE FROM entries WHERE key = ?`, key)
+	return err
+}
+
+// List implements Store
+func (s *SQLite) List(prefix string) ([]Entry, error) {
+	rows, err := s.db.Query(`SELECT key, size, used FROM entries WHERE substr(key, 1, length(?)) = ? ORDER BY key`, prefix, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var used int64
+		if err := rows.Scan(&e.Key, &e.Size, &used); err != nil {
+			return nil, err
+		}
+		e.Used = time.Unix(0, used)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Close implements Store
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
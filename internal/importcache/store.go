@@ -0,0 +1,67 @@
+package importcache
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for keys that are not in the cache
+var ErrNotFound = errors.New("not in cache")
+
+// Backends are the storage backends of the cache
+var Backends = []string{"fs", "sqlite"}
+
+// Store holds the cache entries. Keys are slash-separated paths, e.g.
+// example.com/schemas@v1.2.0/module.zip. Implementations are safe for
+// concurrent use, also by several processes sharing the cache.
+type Store interface {
+	// Get returns the data of a key, or ErrNotFound, and marks it used
+	Get(key string) ([]byte, error)
+
+	// Put stores the data of a key, replacing what was there
+	Put(key string, data []byte) error
+
+	// Delete removes a key; deleting a missing key is not an error
+	Delete(key string) error
+
+	// List returns the entries whose key starts with prefix, by key
+	List(prefix string) ([]Entry, error)
+
+	// Close releases the store
+	Close() error
+}
+
+// Entry describes a cache entry
+type Entry struct {
+	Key  string    `json:"key"`
+	Size int64     `json:"size"`
+	Used time.Time `json:"used"` // when it was last stored or read
+}
+
+// Open opens the store of a backend in dir: a directory tree for fs, and
+// a modules.db database for sqlite
+func Open(backend, dir string) (Store, error) {
+	switch backend {
+	case "", "fs":
+		return OpenFS(filepath.Join(dir, "modules"))
+	case "sqlite":
+		return OpenSQLite(filepath.Join(dir, "modules.db"))
+	}
+	return nil, fmt.Errorf("unknown cache backend %q (expected %s)", backend, strings.Join(Backends, " or "))
+}
+
+// checkKey rejects keys that would escape a directory tree
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid cache key %q", key)
+	}
+	for _, elem := range strings.Split(key, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("invalid cache key %q", key)
+		}
+	}
+	return nil
+}
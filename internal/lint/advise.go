@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Category groups rules into an aspect of schema quality, weighted by how
+// much it counts towards the overall score
+type Category struct {
+	Name   string
+	Weight int
+	Rules  []string
+}
+
+// Categories weigh to 100 in total
+var Categories = []Category{
+	{"documentation", 30, []string{UndocumentedDefinition, UndocumentedField}},
+	{"constraints", 25, []string{UnconstrainedString, UnconstrainedNumber}},
+	{"naming", 15, []string{DefinitionNaming, FieldNaming, NonASCIIName, NonASCIIEnumValue}},
+	{"unused definitions", 15, []string{UnusedDefinition}},
+	{"definition size", 15, []string{OversizedDefinition}},
+}
+
+// CategoryOf returns the name of the category of a rule
+func CategoryOf(code string) string {
+	for _, cat := range Categories {
+		for _, c := range cat.Rules {
+			if c == code {
+				return cat.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Report is the quality score of a project and what to do to improve it
+type Report struct {
+	Score       int             `json:"score"`
+	Categories  []CategoryScore `json:"categories"`
+	Suggestions []Suggestion    `json:"suggestions"`
+}
+
+// CategoryScore is the score of a category, 0 to 100
+type CategoryScore struct {
+	Name     string `json:"name"`
+	Weight   int    `json:"weight"`
+	Score    int    `json:"score"`
+	Findings int    `json:"findings"`
+	Checked  int    `json:"checked"`
+}
+
+// Suggestion is an action fixing all findings of a rule, with the points it
+// adds to the overall score
+type Suggestion struct {
+	Rule     Rule      `json:"rule"`
+	Action   string    `json:"action"`
+	Points   float64   `json:"points"`
+	Count    int       `json:"count"`
+	URL      string    `json:"url"`
+	Findings []Finding `json:"findings"`
+}
+
+// actions describe what fixing the findings of a rule means
+var actions = map[string]string{
+	UndocumentedDefinition: "Document %d of %d definitions",
+	UndocumentedField:      "Document %d of %d fields",
+	UnconstrainedString:    "Constrain %d of %d string fields with a length, pattern or enum",
+	UnconstrainedNumber:    "Bound %d of %d number fields",
+	DefinitionNaming:       "Rename %d of %d definitions to PascalCase",
+	FieldNaming:            "Rename %d of %d fields to the project's naming style",
+	UnusedDefinition:       "Remove or use %d of %d enum and scalar definitions",
+	OversizedDefinition:    "Split %d of %d oversized definitions",
+	NonASCIIName:           "Rename %d of %d definitions and fields with non-ASCII names",
+	NonASCIIEnumValue:      "Replace %d of %d non-ASCII enum values",
+}
+
+// Advise scores the findings of a lint run. A category scores the share of
+// checked places without findings; a project without anything to check in a
+// category gets the full score. Suggestions come highest gain first.
+func Advise(result *Result) *Report {
+	report := &Report{}
+	total := 0.0
+	for _, cat := range Categories {
+		cs := CategoryScore{Name: cat.Name, Weight: cat.Weight}
+		for _, code := range cat.Rules {
+			cs.Checked += result.Checked[code]
+		}
+		counts := make(map[string][]Finding)
+		for _, f := range result.Findings {
+			for _, code := range cat.Rules {
+				if f.Code == code {
+					counts[code] = append(counts[code], f)
+				}
+			}
+		}
+
+		ratio := 1.0
+		for _, code := range cat.Rules {
+			findings := counts[code]
+			if len(findings) == 0 {
+				continue
+			}
+			cs.Findings += len(findings)
+			share := float64(len(findings)) / float64(cs.Checked)
+			ratio -= share
+			rule, _ := Lookup(code)
+			report.Suggestions = append(report.Suggestions, Suggestion{
+				Rule:     rule,
+				Action:   fmt.Sprintf(actions[code], len(findings), result.Checked[code]),
+				Points:   math.Round(share*float64(cat.Weight)*10) / 10,
+				Count:    len(findings),
+				URL:      rule.URL(),
+				Findings: findings,
+			})
+		}
+		if ratio < 0 {
+			ratio = 0
+		}
+		cs.Score = int(math.Round(ratio * 100))
+		total += ratio * float64(cat.Weight)
+		report.Categories = append(report.Categories, cs)
+	}
+	report.Score = int(math.Round(total))
+
+	sort.SliceStable(report.Suggestions, func(i, j int) bool {
+		return report.Suggestions[i].Points > report.Suggestions[j].Points
+	})
+	return report
+}
@@ -0,0 +1,484 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/generator"
+)
+
+// DocsURL documents the lint rules, one section per rule
+const DocsURL = "https://github.com/platoorg/plato-sl-cli/blob/main/CLI.md"
+
+// Rule codes
+const (
+	UndocumentedDefinition = "PSL2001"
+	UndocumentedField      = "PSL2002"
+	UnconstrainedString    = "PSL2003"
+	UnconstrainedNumber    = "PSL2004"
+	DefinitionNaming       = "PSL2005"
+	FieldNaming            = "PSL2006"
+	UnusedDefinition       = "PSL2007"
+	OversizedDefinition    = "PSL2008"
+	NonASCIIName           = "PSL2009"
+	NonASCIIEnumValue      = "PSL2010"
+)
+
+// Rule is a schema convention checked by lint
+type Rule struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Fix     string `json:"fix"`
+}
+
+// Rules are the lint rules, by code
+var Rules = []Rule{
+	{UndocumentedDefinition, "undocumented-definition", "definitions have a doc comment", "Describe what the definition represents in a // comment above it, or with @description"},
+	{UndocumentedField, "undocumented-field", "fields have a doc comment", "Describe the field in a // comment above it, or with @description"},
+	{UnconstrainedString, "unconstrained-string", "string fields have a length, pattern or enum constraint", "Add a length limit (strings.MaxRunes), a pattern (=~) or an enum"},
+	{UnconstrainedNumber, "unconstrained-number", "number fields have bounds", "Add bounds, e.g. int & >=0 & <=100"},
+	{DefinitionNaming, "definition-naming", "definitions are named in PascalCase", "Rename the definition"},
+	{FieldNaming, "field-naming", "fields follow the project's naming style (camelCase or snake_case)", "Rename the field"},
+	{UnusedDefinition, "unused-definition", "enum and scalar definitions are used", "Remove the definition, or use it where the type applies"},
+	{OversizedDefinition, "oversized-definition", "definitions stay within a field count and nesting depth", "Split it into smaller definitions and compose them"},
+	{NonASCIIName, "non-ascii-name", "definition and field names are ASCII", "Rename it, or pick how generators write it with the unicode option (keep, transliterate, escape)"},
+	{NonASCIIEnumValue, "non-ascii-enum-value", "enum values are ASCII", "Use an ASCII value and keep the label for display elsewhere"},
+}
+
+// Lookup returns the rule with a code
+func Lookup(code string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.Code == code {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// URL links to the documentation of the rule
+func (r Rule) URL() string {
+	return DocsURL + "#" + strings.ToLower(r.Code) + "-" + r.Name
+}
+
+// Finding is a place that breaks a rule, with a fix when there is a
+// specific one, such as the name to rename to
+type Finding struct {
+	Code       string `json:"code"`
+	Rule       string `json:"rule"`
+	Path       string `json:"path"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Location formats where a finding is, e.g. schemas/user.cue:12
+func (f Finding) Location() string {
+	if f.File == "" {
+		return f.Path
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// Options tunes the rules
+type Options struct {
+	// MaxFields is the number of fields, nested ones included, above which
+	// a definition is oversized; MaxDepth the nesting depth of structs
+	MaxFields int
+	MaxDepth  int
+}
+
+// DefaultOptions are the limits used when none are set
+var DefaultOptions = Options{MaxFields: 40, MaxDepth: 4}
+
+// Result holds the findings of a lint run and how many places each rule
+// checked, which relates the findings to the size of the project
+type Result struct {
+	Findings []Finding      `json:"findings"`
+	Checked  map[string]int `json:"checked"`
+}
+
+// Drop removes the findings drop reports, and the places they are at from
+// the checked places, as if their rules did not apply there
+func (r *Result) Drop(drop func(Finding) bool) {
+	kept := r.Findings[:0]
+	for _, f := range r.Findings {
+		if drop(f) {
+			r.Checked[f.Code]--
+			continue
+		}
+		kept = append(kept, f)
+	}
+	r.Findings = kept
+}
+
+var (
+	pascalCase = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCase  = regexp.MustCompile(`^[a-z][a-z0-9]*([A-Z][a-z0-9]*)+$`)
+	snakeCase  = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)+$`)
+	oneWord    = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+)
+
+// linter collects findings over the definitions of a value
+type linter struct {
+	opts   Options
+	wd     string
+	result *Result
+	fields []namedField
+	used   map[string]bool
+}
+
+// namedField is a field name, checked against the dominant style once all
+// fields are known
+type namedField struct {
+	name, path string
+	val        cue.Value
+}
+
+// Run checks the definitions of a value against all rules
+func Run(val cue.Value, opts Options) (*Result, error) {
+	if opts.MaxFields <= 0 {
+		opts.MaxFields = DefaultOptions.MaxFields
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultOptions.MaxDepth
+	}
+	wd, _ := os.Getwd()
+	l := &linter{opts: opts, wd: wd, result: &Result{Checked: make(map[string]int)}, used: make(map[string]bool)}
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, err
+	}
+	type def struct {
+		name string
+		val  cue.Value
+	}
+	var defs []def
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs = append(defs, def{iter.Selector().String(), iter.Value()})
+		} else {
+			l.references(iter.Value(), 0)
+		}
+	}
+
+	for _, d := range defs {
+		l.definition(d.name, d.val)
+	}
+	for _, d := range defs {
+		if isStruct(d.val) {
+			continue
+		}
+		l.result.Checked[UnusedDefinition]++
+		if !l.used[d.name] {
+			l.report(UnusedDefinition, d.name, d.val,
+				fmt.Sprintf("%s is not used by any definition or field", d.name), "")
+		}
+	}
+	l.fieldNames()
+
+	sort.SliceStable(l.result.Findings, func(i, j int) bool {
+		a, b := l.result.Findings[i], l.result.Findings[j]
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return l.result, nil
+}
+
+// definition checks a definition and, for structs, its fields
+func (l *linter) definition(name string, val cue.Value) {
+	l.result.Checked[UndocumentedDefinition]++
+	if platoCue.Description(val) == "" {
+		l.report(UndocumentedDefinition, name, val, fmt.Sprintf("%s has no doc comment", name), "")
+	}
+
+	l.result.Checked[DefinitionNaming]++
+	if bare := strings.TrimPrefix(name, "#"); isASCII(bare) && !pascalCase.MatchString(bare) {
+		l.report(DefinitionNaming, name, val, fmt.Sprintf("%s is not PascalCase", name),
+			fmt.Sprintf("Rename it to #%s", toPascalCase(bare)))
+	}
+	l.asciiName(name, name, val)
+
+	if !isStruct(val) {
+		l.references(val, 0)
+		l.scalar(name, val)
+		return
+	}
+
+	fields, depth := l.fieldsOf(name, val, 1)
+	l.result.Checked[OversizedDefinition]++
+	switch {
+	case fields > l.opts.MaxFields:
+		l.report(OversizedDefinition, name, val,
+			fmt.Sprintf("%s has %d fields (limit %d)", name, fields, l.opts.MaxFields), "")
+	case depth > l.opts.MaxDepth:
+		l.report(OversizedDefinition, name, val,
+			fmt.Sprintf("%s nests structs %d levels deep (limit %d)", name, depth, l.opts.MaxDepth), "")
+	}
+}
+
+// fieldsOf checks the fields of a struct, returning how many fields it has
+// including nested ones, and how deep its structs nest. References to
+// definitions are not descended into.
+func (l *linter) fieldsOf(prefix string, val cue.Value, depth int) (int, int) {
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return 0, depth
+	}
+	count, deepest := 0, depth
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		name := iter.Selector().Unquoted()
+		path := prefix + "." + name
+		field := iter.Value()
+		count++
+
+		l.result.Checked[UndocumentedField]++
+		if platoCue.Description(field) == "" {
+			l.report(UndocumentedField, path, field, fmt.Sprintf("%s has no doc comment", path), "")
+		}
+		l.fields = append(l.fields, namedField{name, path, field})
+		l.asciiName(name, path, field)
+		l.references(field, 0)
+
+		if isReference(field) {
+			continue
+		}
+		if isStruct(field) && depth < 16 {
+			n, d := l.fieldsOf(path, field, depth+1)
+			count += n
+			if d > deepest {
+				deepest = d
+			}
+			continue
+		}
+		l.scalar(path, field)
+	}
+	return count, deepest
+}
+
+// scalar checks that a string or number has constraints. Enums, literals,
+// references and disjunctions are constrained by their alternatives.
+func (l *linter) scalar(path string, val cue.Value) {
+	if isReference(val) || val.IsConcrete() {
+		return
+	}
+	if op, args := val.Expr(); op == cue.OrOp {
+		l.enumValues(path, args)
+		return
+	}
+	c := platoCue.ConstraintsOf(val)
+	switch kind := val.IncompleteKind(); {
+	case kind == cue.StringKind:
+		l.result.Checked[UnconstrainedString]++
+		if c.MinLength == nil && c.MaxLength == nil && len(c.Patterns) == 0 {
+			l.report(UnconstrainedString, path, val, fmt.Sprintf("%s accepts any string", path), "")
+		}
+	case kind&cue.NumberKind != 0 && kind&^cue.NumberKind == 0:
+		l.result.Checked[UnconstrainedNumber]++
+		if c.Minimum == nil && c.Maximum == nil && c.ExclusiveMinimum == nil && c.ExclusiveMaximum == nil {
+			l.report(UnconstrainedNumber, path, val, fmt.Sprintf("%s accepts any number", path), "")
+		}
+	}
+}
+
+// asciiName reports a definition or field name with runes outside ASCII,
+// which targets without Unicode identifiers have to transliterate or escape.
+// The fix is the transliterated name when there is one.
+func (l *linter) asciiName(name, path string, val cue.Value) {
+	l.result.Checked[NonASCIIName]++
+	if isASCII(name) {
+		return
+	}
+	suggestion := ""
+	if ascii := generator.Transliterate(name); isASCII(ascii) {
+		suggestion = fmt.Sprintf("Rename it to %s", ascii)
+	}
+	l.report(NonASCIIName, path, val, fmt.Sprintf("%s has a non-ASCII name", path), suggestion)
+}
+
+// enumValues reports string enum values with runes outside ASCII, which
+// generators turn into enum members and constants
+func (l *linter) enumValues(path string, alternatives []cue.Value) {
+	for _, alt := range alternatives {
+		s, err := alt.String()
+		if err != nil {
+			continue
+		}
+		l.result.Checked[NonASCIIEnumValue]++
+		if !isASCII(s) {
+			l.report(NonASCIIEnumValue, path, alt, fmt.Sprintf("%s has the non-ASCII value %q", path, s), "")
+		}
+	}
+}
+
+// fieldNames reports fields that do not follow the dominant style of the
+// project: camelCase or snake_case, whichever more fields use. Single-word
+// names fit both, and non-ASCII names are left to the non-ascii-name rule.
+func (l *linter) fieldNames() {
+	camel, snake := 0, 0
+	for _, f := range l.fields {
+		switch {
+		case camelCase.MatchString(f.name):
+			camel++
+		case snakeCase.MatchString(f.name):
+			snake++
+		}
+	}
+	style, expected := camelCase, "camelCase"
+	if snake > camel {
+		style, expected = snakeCase, "snake_case"
+	}
+
+	for _, f := range l.fields {
+		l.result.Checked[FieldNaming]++
+		if !isASCII(f.name) || oneWord.MatchString(f.name) || style.MatchString(f.name) {
+			continue
+		}
+		suggestion := fmt.Sprintf("Rename it to %s", toCamelCase(f.name))
+		if expected == "snake_case" {
+			suggestion = fmt.Sprintf("Rename it to %s", toSnakeCase(f.name))
+		}
+		l.report(FieldNaming, f.path, f.val,
+			fmt.Sprintf("%s is not %s like most fields", f.path, expected), suggestion)
+	}
+}
+
+// references marks the definitions a value refers to as used
+func (l *linter) references(val cue.Value, depth int) {
+	if depth > 16 {
+		return
+	}
+	if _, path := val.ReferencePath(); len(path.Selectors()) > 0 {
+		l.used[path.String()] = true
+		return
+	}
+	if op, args := val.Expr(); op == cue.OrOp || op == cue.AndOp {
+		for _, arg := range args {
+			l.references(arg, depth+1)
+		}
+		return
+	}
+	switch val.IncompleteKind() {
+	case cue.ListKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			l.references(elem, depth+1)
+		}
+	case cue.StructKind:
+		if elem := val.LookupPath(cue.MakePath(cue.AnyString)); elem.Exists() {
+			l.references(elem, depth+1)
+		}
+	}
+}
+
+// report adds a finding at the position of a value
+func (l *linter) report(code, path string, val cue.Value, msg, suggestion string) {
+	rule, _ := Lookup(code)
+	f := Finding{Code: code, Rule: rule.Name, Path: path, Message: msg, Suggestion: suggestion}
+	if pos := val.Pos(); pos.IsValid() {
+		f.File, f.Line = pos.Filename(), pos.Line()
+		if rel, err := filepath.Rel(l.wd, f.File); err == nil && !strings.HasPrefix(rel, "..") {
+			f.File = filepath.ToSlash(rel)
+		}
+	}
+	l.result.Findings = append(l.result.Findings, f)
+}
+
+// isStruct reports whether a value is a struct with fields
+func isStruct(val cue.Value) bool {
+	if val.IncompleteKind() != cue.StructKind {
+		return false
+	}
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return false
+	}
+	for iter.Next() {
+		if !iter.Selector().IsDefinition() {
+			return true
+		}
+	}
+	return false
+}
+
+// isReference reports whether a value refers to another one
+func isReference(val cue.Value) bool {
+	_, path := val.ReferencePath()
+	return len(path.Selectors()) > 0
+}
+
+// isASCII reports whether a string is all ASCII
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// words splits a name into lower case words at underscores, dashes and
+// case changes
+func words(name string) []string {
+	var out []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			out = append(out, strings.ToLower(cur.String()))
+			cur.Reset()
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+		case r >= 'A' && r <= 'Z' && i > 0 && (runes[i-1] >= 'a' && runes[i-1] <= 'z' || runes[i-1] >= '0' && runes[i-1] <= '9'):
+			flush()
+			cur.WriteRune(r)
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return out
+}
+
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		r, size := utf8.DecodeRuneInString(w)
+		b.WriteString(string(unicode.ToUpper(r)) + w[size:])
+	}
+	return b.String()
+}
+
+func toCamelCase(name string) string {
+	s := toPascalCase(name)
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func toSnakeCase(name string) string {
+	return strings.Join(words(name), "_")
+}
@@ -0,0 +1,177 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platoorg/plato-sl-cli/internal/audit"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// Manifest describes the generated artifacts of a schema release
+type Manifest struct {
+	Project    string     `json:"project"`
+	Version    string     `json:"version"`
+	SchemaHash string     `json:"schemaHash"`
+	Created    time.Time  `json:"created"`
+	Artifacts  []Artifact `json:"artifacts"`
+
+	// Metadata is the package metadata of platosl.yaml (license, authors,
+	// homepage), attached to the published release
+	Metadata *config.MetadataConfig `json:"metadata,omitempty"`
+
+	// Notes are the release notes, e.g. from 'platosl release notes'
+	Notes string `json:"notes,omitempty"`
+}
+
+// Artifact is one generated file in a manifest
+type Artifact struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+
+	// Dir is the output directory of generators writing several files,
+	// e.g. generated/java; Path is then a file inside it
+	Dir string `json:"dir,omitempty"`
+}
+
+// File returns the path of an artifact inside its output directory, or its
+// file name for single-file outputs
+func (a Artifact) File() string {
+	if a.Dir != "" {
+		if rel, err := filepath.Rel(filepath.FromSlash(a.Dir), filepath.FromSlash(a.Path)); err == nil {
+			return rel
+		}
+	}
+	return filepath.Base(filepath.FromSlash(a.Path))
+}
+
+// Status is the verification outcome for an artifact
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusMismatch Status = "mismatch"
+	StatusMissing  Status = "missing"
+)
+
+// Result is the verification result for one artifact
+type Result struct {
+	Artifact Artifact
+	Local    string
+	Hash     string
+	Status   Status
+}
+
+// AddFile hashes a file and adds it to the manifest under name
+func (m *Manifest) AddFile(name, path string) error {
+	return m.add(name, path, "")
+}
+
+// AddOutput adds the output of a generator under name: the file, or every
+// file of an output directory except hidden bookkeeping files such as the
+// list of generated files
+func (m *Manifest) AddOutput(name, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return m.add(name, path, "")
+	}
+
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return err
+		}
+		return m.add(name, file, path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk output directory %s: %w", path, err)
+	}
+	return nil
+}
+
+// add hashes a file and adds it to the manifest, in name and path order
+func (m *Manifest) add(name, path, dir string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact %s: %w", path, err)
+	}
+
+	artifact := Artifact{
+		Name: name,
+		Path: filepath.ToSlash(path),
+		Hash: audit.Hash(data),
+		Size: int64(len(data)),
+	}
+	if dir != "" {
+		artifact.Dir = filepath.ToSlash(dir)
+	}
+	m.Artifacts = append(m.Artifacts, artifact)
+	sort.SliceStable(m.Artifacts, func(i, j int) bool {
+		a, b := m.Artifacts[i], m.Artifacts[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Path < b.Path
+	})
+	return nil
+}
+
+// Load reads a manifest from a local file or an http(s) URL
+func Load(client *httpclient.Client, source string) (*Manifest, error) {
+	var m Manifest
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		if err := client.DoJSON(http.MethodGet, source, nil, &m); err != nil {
+			return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+		}
+		return &m, nil
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", source, err)
+	}
+	return &m, nil
+}
+
+// Verify compares the manifest artifacts with local copies. locate maps an
+// artifact to the local path of its vendored copy.
+func (m *Manifest) Verify(locate func(Artifact) string) []Result {
+	var results []Result
+
+	for _, artifact := range m.Artifacts {
+		result := Result{
+			Artifact: artifact,
+			Local:    locate(artifact),
+		}
+
+		data, err := os.ReadFile(result.Local)
+		if err != nil {
+			result.Status = StatusMissing
+		} else {
+			result.Hash = audit.Hash(data)
+			result.Status = StatusMismatch
+			if result.Hash == artifact.Hash {
+				result.Status = StatusOK
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
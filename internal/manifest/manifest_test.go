@@ -0,0 +1,58 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddOutputDirectory(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "java")
+	if err := os.MkdirAll(filepath.Join(out, "platosl"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Person.java", "Address.java"} {
+		if err := os.WriteFile(filepath.Join(out, "platosl", name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(out, ".platosl-files.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	single := filepath.Join(dir, "types.ts")
+	if err := os.WriteFile(single, []byte("export {};\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manifest{}
+	if err := m.AddOutput("java", out); err != nil {
+		t.Fatalf("AddOutput(java): %v", err)
+	}
+	if err := m.AddOutput("typescript", single); err != nil {
+		t.Fatalf("AddOutput(typescript): %v", err)
+	}
+
+	if len(m.Artifacts) != 3 {
+		t.Fatalf("Artifacts = %v, want two java files and types.ts", m.Artifacts)
+	}
+	want := []string{filepath.Join("platosl", "Address.java"), filepath.Join("platosl", "Person.java"), "types.ts"}
+	for i, a := range m.Artifacts {
+		if a.File() != want[i] {
+			t.Errorf("artifact %d: File() = %q, want %q", i, a.File(), want[i])
+		}
+	}
+
+	// Vendored copies are found by their path inside the output directory
+	results := m.Verify(func(a Artifact) string {
+		if a.Dir != "" {
+			return filepath.Join(out, a.File())
+		}
+		return single
+	})
+	for _, r := range results {
+		if r.Status != StatusOK {
+			t.Errorf("%s: %s", r.Artifact.Path, r.Status)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/endpoint"
+	"github.com/platoorg/plato-sl-cli/internal/fuzz"
+)
+
+// maxAttempts bounds the instances generated per response until one is valid
+const maxAttempts = 20
+
+// maxBodySize bounds request bodies read for validation
+const maxBodySize = 1 << 20
+
+// Options configures a mock server
+type Options struct {
+	// Latency delays every response
+	Latency time.Duration
+
+	// Jitter adds a random delay of up to this duration to the latency
+	Jitter time.Duration
+
+	// ErrorRate is the fraction of requests, from 0 to 1, answered with
+	// ErrorStatus instead of mock data
+	ErrorRate float64
+
+	// ErrorStatus is the status code of injected errors (default 500)
+	ErrorStatus int
+
+	// Seed seeds the mock data. The same seed gives the same response for
+	// the same method and path.
+	Seed int64
+
+	// Profiles shape the mock data of response definitions after sample
+	// data, by definition name, e.g. #User
+	Profiles map[string]*fuzz.Profile
+
+	// Logf logs each request, if set
+	Logf func(format string, args ...interface{})
+}
+
+// Server answers the endpoints declared with @http with mock data generated
+// from their response definitions
+type Server struct {
+	schemas   cue.Value
+	endpoints []endpoint.Endpoint
+	opts      Options
+
+	// mu serializes access to CUE values and the shared random source
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewServer creates a mock server for the endpoints of the schemas
+func NewServer(schemas cue.Value, endpoints []endpoint.Endpoint, opts Options) (*Server, error) {
+	if opts.ErrorRate < 0 || opts.ErrorRate > 1 {
+		return nil, fmt.Errorf("error rate must be between 0 and 1, got %v", opts.ErrorRate)
+	}
+	if opts.ErrorStatus == 0 {
+		opts.ErrorStatus = http.StatusInternalServerError
+	}
+	if opts.ErrorStatus < 400 || opts.ErrorStatus > 599 {
+		return nil, fmt.Errorf("error status must be between 400 and 599, got %d", opts.ErrorStatus)
+	}
+	if opts.Latency < 0 || opts.Jitter < 0 {
+		return nil, fmt.Errorf("latency and jitter must not be negative")
+	}
+
+	return &Server{
+		schemas:   schemas,
+		endpoints: endpoints,
+		opts:      opts,
+		rnd:       rand.New(rand.NewSource(opts.Seed)),
+	}, nil
+}
+
+// ServeHTTP implements http.Handler. Responses allow any origin, so
+// frontends on another port can call the server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	status := s.serve(w, r)
+	if s.opts.Logf != nil {
+		s.opts.Logf("%s %s %d %s", r.Method, r.URL.Path, status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// serve answers a request and returns the status code
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) int {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return http.StatusNoContent
+	}
+
+	e, params, err := endpoint.Find(s.endpoints, r.Method, r.URL.Path)
+	switch err {
+	case nil:
+	case endpoint.ErrMethodNotAllowed:
+		return writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not allowed on %s", r.Method, r.URL.Path), nil)
+	default:
+		return writeError(w, http.StatusNotFound, fmt.Sprintf("no endpoint for %s %s", r.Method, r.URL.Path), nil)
+	}
+
+	var body []byte
+	if e.Request != "" {
+		body, err = io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			return writeError(w, http.StatusBadRequest, "failed to read the request body", nil)
+		}
+	}
+
+	delay, inject := s.chaos()
+	time.Sleep(delay)
+	if inject {
+		return writeError(w, s.opts.ErrorStatus, "injected error", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Request != "" {
+		if status, msg, details := s.checkRequest(e, body); status != 0 {
+			return writeError(w, status, msg, details)
+		}
+	}
+
+	if !e.HasBody() {
+		w.WriteHeader(e.Status)
+		return e.Status
+	}
+	data, err := s.response(e, r.Method+" "+r.URL.Path, params)
+	if err != nil {
+		return writeError(w, http.StatusInternalServerError, err.Error(), nil)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	w.Write(data)
+	return e.Status
+}
+
+// chaos returns the delay of a response and whether to inject an error
+func (s *Server) chaos() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay := s.opts.Latency
+	if s.opts.Jitter > 0 {
+		delay += time.Duration(s.rnd.Int63n(int64(s.opts.Jitter) + 1))
+	}
+	return delay, s.opts.ErrorRate > 0 && s.rnd.Float64() < s.opts.ErrorRate
+}
+
+// checkRequest validates a request body against the request definition,
+// returning a status code and message when it is rejected
+func (s *Server) checkRequest(e endpoint.Endpoint, body []byte) (int, string, []string) {
+	def, err := platoCue.LookupDefinition(s.schemas, e.Request)
+	if err != nil {
+		return http.StatusInternalServerError, err.Error(), nil
+	}
+	if !json.Valid(body) {
+		return http.StatusBadRequest, "request body is not valid JSON", nil
+	}
+
+	result := platoCue.ValidateData(def, def.Context().CompileBytes(body))
+	if result.Valid {
+		return 0, "", nil
+	}
+	var details []string
+	for _, e := range result.Errors {
+		details = append(details, e.Message)
+	}
+	return http.StatusBadRequest, fmt.Sprintf("request body does not match %s", e.Request), details
+}
+
+// response generates the response body of an endpoint. The random source is
+// seeded from the request, so repeated requests get the same data. Path
+// parameters fill fields of the same name where the schema allows it, so
+// GET /users/42 returns a user with id 42.
+func (s *Server) response(e endpoint.Endpoint, request string, params map[string]string) ([]byte, error) {
+	def, err := platoCue.LookupDefinition(s.schemas, e.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(request))
+	rnd := rand.New(rand.NewSource(s.opts.Seed ^ int64(h.Sum64())))
+
+	var data []byte
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		instance := s.opts.Profiles[e.Response].Instance(def, rnd)
+		if obj, ok := instance.(map[string]interface{}); ok {
+			fillParams(def, obj, params)
+		}
+		data, err = fuzz.Encode(instance)
+		if err != nil {
+			return nil, err
+		}
+		if platoCue.ValidateData(def, def.Context().CompileBytes(data)).Valid {
+			return data, nil
+		}
+	}
+
+	if s.opts.Logf != nil {
+		s.opts.Logf("warning: no valid %s found in %d attempts, responding with an invalid one", e.Response, maxAttempts)
+	}
+	return data, nil
+}
+
+// fillParams sets fields named after path parameters to the parameter
+// values, as a number when the field takes one
+func fillParams(def cue.Value, obj map[string]interface{}, params map[string]string) {
+	for name, value := range params {
+		field := def.LookupPath(cue.MakePath(cue.Str(name)))
+		if !field.Exists() {
+			continue
+		}
+		candidates := []interface{}{value}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			candidates = append([]interface{}{n}, candidates...)
+		}
+		for _, candidate := range candidates {
+			data, err := fuzz.Encode(candidate)
+			if err != nil {
+				continue
+			}
+			if field.Unify(def.Context().CompileBytes(data)).Validate(cue.Concrete(true)) == nil {
+				obj[name] = candidate
+				break
+			}
+		}
+	}
+}
+
+// writeError writes a JSON error response and returns its status code
+func writeError(w http.ResponseWriter, status int, msg string, details []string) int {
+	body := map[string]interface{}{"error": msg}
+	if len(details) > 0 {
+		body["details"] = details
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+	return status
+}
@@ -0,0 +1,223 @@
+package packaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+)
+
+// DefaultVersion is the package version when metadata.version is not set
+const DefaultVersion = "0.0.0"
+
+// ecosystems maps generators to the package manifest written for them
+var ecosystems = map[string]string{
+	"typescript":      "npm",
+	"zod":             "npm",
+	"trpc":            "npm",
+	"valibot":         "npm",
+	"yup":             "npm",
+	"joi":             "npm",
+	"effect":          "npm",
+	"typebox":         "npm",
+	"mongoose":        "npm",
+	"elixir":          "hex",
+	"elixir-ecto":     "hex",
+	"elixir-absinthe": "hex",
+}
+
+// peerDependencies are the runtime libraries the output of a generator
+// imports
+var peerDependencies = map[string]map[string]string{
+	"zod":      {"zod": "^3.22.0"},
+	"valibot":  {"valibot": "^1.0.0"},
+	"yup":      {"yup": "^1.0.0"},
+	"joi":      {"joi": "^17.0.0"},
+	"effect":   {"effect": "^3.10.0"},
+	"typebox":  {"@sinclair/typebox": "^0.32.0"},
+	"mongoose": {"mongoose": "^8.0.0"},
+	"trpc":     {"@trpc/server": ">=10.0.0", "zod": "^3.22.0"},
+}
+
+// Package is a package manifest for generated code
+type Package struct {
+	// Path is where the manifest is written: next to the output, or for
+	// Hex next to the lib directory holding it
+	Path    string
+	Content []byte
+}
+
+// Build returns the package manifest of a generator's output: package.json
+// for JavaScript and TypeScript, mix.exs with Hex metadata for Elixir. Name
+// is the package name, defaulting to the project name.
+func Build(generator, output, name string, meta config.MetadataConfig) (*Package, error) {
+	switch ecosystems[generator] {
+	case "npm":
+		return npm(generator, output, name, meta)
+	case "hex":
+		return hex(generator, output, name, meta), nil
+	}
+	return nil, fmt.Errorf("generator %s has no packaging output (supported for JavaScript, TypeScript and Elixir generators)", generator)
+}
+
+// npmName matches valid npm package names, optionally scoped
+var npmName = regexp.MustCompile(`^(@[a-z0-9][a-z0-9._~-]*/)?[a-z0-9][a-z0-9._~-]*$`)
+
+// packageJSON is package.json, with its fields in the usual order
+type packageJSON struct {
+	Name             string            `json:"name"`
+	Version          string            `json:"version"`
+	Description      string            `json:"description"`
+	License          string            `json:"license"`
+	Author           string            `json:"author,omitempty"`
+	Contributors     []string          `json:"contributors,omitempty"`
+	Homepage         string            `json:"homepage,omitempty"`
+	Main             string            `json:"main"`
+	Types            string            `json:"types,omitempty"`
+	Files            []string          `json:"files"`
+	PeerDependencies map[string]string `json:"peerDependencies,omitempty"`
+}
+
+func npm(generator, output, name string, meta config.MetadataConfig) (*Package, error) {
+	name = strings.ToLower(name)
+	if !npmName.MatchString(name) {
+		return nil, fmt.Errorf("invalid npm package name %q (set the packageName option)", name)
+	}
+
+	file := filepath.Base(output)
+	pkg := packageJSON{
+		Name:             name,
+		Version:          version(meta),
+		Description:      "Generated by PlatoSL",
+		License:          meta.License,
+		Homepage:         meta.Homepage,
+		Main:             file,
+		Files:            []string{file},
+		PeerDependencies: peerDependencies[generator],
+	}
+	if pkg.License == "" {
+		pkg.License = "UNLICENSED"
+	}
+	if len(meta.Authors) > 0 {
+		pkg.Author, pkg.Contributors = meta.Authors[0], meta.Authors[1:]
+	}
+	if strings.HasSuffix(file, ".ts") {
+		pkg.Types = file
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pkg); err != nil {
+		return nil, fmt.Errorf("failed to format package.json: %w", err)
+	}
+	return &Package{Path: filepath.Join(filepath.Dir(output), "package.json"), Content: buf.Bytes()}, nil
+}
+
+func hex(generator, output, name string, meta config.MetadataConfig) *Package {
+	app := snakeCase(name)
+
+	// Mix compiles lib/ by default; an output elsewhere is compiled from
+	// its own directory
+	dir, files, paths := filepath.Dir(output), []string{filepath.Base(output), "mix.exs"}, `["."]`
+	if filepath.Base(dir) == "lib" {
+		dir, files, paths = filepath.Dir(dir), []string{"lib", "mix.exs"}, ""
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by PlatoSL\n")
+	b.WriteString("# DO NOT EDIT - This file is auto-generated\n\n")
+	fmt.Fprintf(&b, "defmodule %s.MixProject do\n", camelCase(app))
+	b.WriteString("  use Mix.Project\n\n")
+	b.WriteString("  def project do\n    [\n")
+	fmt.Fprintf(&b, "      app: :%s,\n", app)
+	fmt.Fprintf(&b, "      version: %q,\n", version(meta))
+	b.WriteString("      elixir: \"~> 1.14\",\n")
+	if paths != "" {
+		fmt.Fprintf(&b, "      elixirc_paths: %s,\n", paths)
+	}
+	b.WriteString("      description: \"Generated by PlatoSL\",\n")
+	b.WriteString("      package: package(),\n")
+	b.WriteString("      deps: deps()\n")
+	b.WriteString("    ]\n  end\n\n")
+
+	b.WriteString("  defp package do\n    [\n")
+	var licenses []string
+	if meta.License != "" {
+		licenses = append(licenses, fmt.Sprintf("%q", meta.License))
+	}
+	fmt.Fprintf(&b, "      licenses: [%s],\n", strings.Join(licenses, ", "))
+	links := ""
+	if meta.Homepage != "" {
+		links = fmt.Sprintf(`"Homepage" => %q`, meta.Homepage)
+	}
+	fmt.Fprintf(&b, "      links: %%{%s},\n", links)
+	fmt.Fprintf(&b, "      files: ~w(%s)\n", strings.Join(files, " "))
+	b.WriteString("    ]\n  end\n\n")
+
+	b.WriteString("  defp deps do\n")
+	switch generator {
+	case "elixir-ecto":
+		b.WriteString("    [{:ecto, \"~> 3.10\"}]\n")
+	case "elixir-absinthe":
+		b.WriteString("    [{:absinthe, \"~> 1.7\"}, {:jason, \"~> 1.4\"}]\n")
+	default:
+		b.WriteString("    []\n")
+	}
+	b.WriteString("  end\nend\n")
+
+	return &Package{Path: filepath.Join(dir, "mix.exs"), Content: []byte(b.String())}
+}
+
+func version(meta config.MetadataConfig) string {
+	if meta.Version == "" {
+		return DefaultVersion
+	}
+	return strings.TrimPrefix(meta.Version, "v")
+}
+
+// snakeCase converts a project name to an OTP application name, e.g.
+// my-schemas to my_schemas
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r + 'a' - 'A')
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	s := strings.Trim(b.String(), "_")
+	switch {
+	case s == "":
+		return "schemas"
+	case s[0] >= '0' && s[0] <= '9':
+		return "schemas_" + s
+	}
+	return s
+}
+
+// camelCase converts an application name to a module name, e.g.
+// my_schemas to MySchemas
+func camelCase(app string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(app, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,233 @@
+// Package project discovers the PlatoSL projects of a repository, each a
+// directory with its own platosl.yaml, and orders and loads them by their
+// dependencies on each other.
+package project
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// ConfigFile is the name of the config file of a project
+const ConfigFile = "platosl.yaml"
+
+// skipDirs are directories never searched for projects
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"cue.mod":      true,
+}
+
+// Project is a directory with a platosl.yaml
+type Project struct {
+	// Dir is the absolute directory of the project
+	Dir string
+
+	Config *config.Config
+}
+
+// Name returns the configured name of the project, or its directory name
+func (p *Project) Name() string {
+	if p.Config.Name != "" {
+		return p.Config.Name
+	}
+	return filepath.Base(p.Dir)
+}
+
+// Dependencies returns the absolute directories of the projects this one
+// depends on
+func (p *Project) Dependencies() []string {
+	return dependencies(p.Dir, p.Config)
+}
+
+// Discover finds the projects in the subdirectories of root, not including
+// root itself. Hidden directories, node_modules, vendor and cue.mod are
+// skipped.
+func Discover(root string) ([]*Project, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*Project
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, ConfigFile)); err != nil {
+			return nil
+		}
+		cfg, err := config.Load(filepath.Join(path, ConfigFile))
+		if err != nil {
+			return fmt.Errorf("%s: %w", relative(root, path), err)
+		}
+		projects = append(projects, &Project{Dir: path, Config: cfg})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// Order sorts projects so each comes after the projects it depends on,
+// keeping the given order otherwise. Dependencies outside the list are
+// loaded when the project is built but do not affect the order.
+func Order(projects []*Project) ([]*Project, error) {
+	byDir := make(map[string]*Project, len(projects))
+	for _, p := range projects {
+		byDir[p.Dir] = p
+	}
+
+	var ordered []*Project
+	state := make(map[string]int) // 1 visiting, 2 done
+	var visit func(p *Project, chain []string) error
+	visit = func(p *Project, chain []string) error {
+		chain = append(chain, p.Name())
+		switch state[p.Dir] {
+		case 1:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(chain, " → "))
+		case 2:
+			return nil
+		}
+		state[p.Dir] = 1
+		for _, dep := range p.Dependencies() {
+			if d, ok := byDir[dep]; ok {
+				if err := visit(d, chain); err != nil {
+					return err
+				}
+			}
+		}
+		state[p.Dir] = 2
+		ordered = append(ordered, p)
+		return nil
+	}
+
+	for _, p := range projects {
+		if err := visit(p, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Published loads the definitions published by the dependencies of the
+// project at dir, transitively, for the schemas of the project to use. The
+// value is built in the context of loader. ok is false when the project has
+// no dependencies.
+func Published(loader *platoCue.Loader, dir string, cfg *config.Config) (cue.Value, bool, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return cue.Value{}, false, err
+	}
+	r := &resolver{loader: loader, loaded: make(map[string]cue.Value), visiting: map[string]bool{dir: true}}
+	return r.dependencies(dir, cfg, []string{dir})
+}
+
+// resolver loads the published schemas of projects, each once
+type resolver struct {
+	loader   *platoCue.Loader
+	loaded   map[string]cue.Value
+	visiting map[string]bool
+}
+
+// dependencies unifies the published schemas of the dependencies of a project
+func (r *resolver) dependencies(dir string, cfg *config.Config, chain []string) (cue.Value, bool, error) {
+	var result cue.Value
+	found := false
+	for _, dep := range dependencies(dir, cfg) {
+		val, err := r.published(dep, append(chain, dep))
+		if err != nil {
+			return cue.Value{}, false, err
+		}
+		if found {
+			result = result.Unify(val)
+		} else {
+			result, found = val, true
+		}
+		if err := result.Err(); err != nil {
+			return cue.Value{}, false, fmt.Errorf("conflicting definitions in dependencies of %s: %w", dir, err)
+		}
+	}
+	return result, found, nil
+}
+
+// published loads the published schemas of a project, with its own
+// dependencies in scope and included
+func (r *resolver) published(dir string, chain []string) (cue.Value, error) {
+	if val, ok := r.loaded[dir]; ok {
+		return val, nil
+	}
+	if r.visiting[dir] {
+		names := make([]string, len(chain))
+		for i, c := range chain {
+			names[i] = filepath.Base(c)
+		}
+		return cue.Value{}, fmt.Errorf("dependency cycle: %s", strings.Join(names, " → "))
+	}
+	r.visiting[dir] = true
+	defer delete(r.visiting, dir)
+
+	cfg, err := config.Load(filepath.Join(dir, ConfigFile))
+	if err != nil {
+		return cue.Value{}, fmt.Errorf("dependency %s: %w", dir, err)
+	}
+
+	loader := r.loader
+	deps, hasDeps, err := r.dependencies(dir, cfg, chain)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	if hasDeps {
+		loader = loader.WithScope(deps)
+	}
+
+	paths := cfg.Publish
+	if len(paths) == 0 {
+		paths = cfg.Schemas
+	}
+	abs := make([]string, len(paths))
+	for i, p := range paths {
+		abs[i] = filepath.Join(dir, p)
+	}
+	val, err := loader.LoadPaths(abs)
+	if err != nil {
+		return cue.Value{}, fmt.Errorf("dependency %s: %w", cfg.Name, err)
+	}
+	if hasDeps {
+		val = val.Unify(deps)
+	}
+
+	r.loaded[dir] = val
+	return val, nil
+}
+
+// dependencies resolves the dependsOn directories of a config in dir
+func dependencies(dir string, cfg *config.Config) []string {
+	deps := make([]string, len(cfg.DependsOn))
+	for i, d := range cfg.DependsOn {
+		deps[i] = filepath.Clean(filepath.Join(dir, d))
+	}
+	return deps
+}
+
+// relative returns path relative to root for messages
+func relative(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,302 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/endpoint"
+)
+
+// maxBodySize bounds the bodies read for validation; larger bodies are
+// passed through unchecked
+const maxBodySize = 10 << 20
+
+// Options configures a proxy
+type Options struct {
+	// Upstream is the URL of the proxied service, e.g. http://localhost:8080
+	Upstream string
+
+	// Block rejects requests that do not match their request definition with
+	// 400, and replaces responses that do not match their response
+	// definition with 502. Otherwise violations are only reported.
+	Block bool
+
+	// Record receives a JSON line per violation, if set
+	Record io.Writer
+
+	// Logf logs each request and violation, if set
+	Logf func(format string, args ...interface{})
+}
+
+// Violation is a request or response body that does not match its definition
+type Violation struct {
+	Time       time.Time `json:"time"`
+	Endpoint   string    `json:"endpoint"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Direction  string    `json:"direction"` // request or response
+	Definition string    `json:"definition"`
+	Status     int       `json:"status,omitempty"`
+	Errors     []string  `json:"errors"`
+	Blocked    bool      `json:"blocked"`
+}
+
+// violationError blocks a response that does not match its definition
+type violationError struct {
+	v *Violation
+}
+
+func (e *violationError) Error() string {
+	return fmt.Sprintf("response does not match %s", e.v.Definition)
+}
+
+// Proxy forwards requests to an upstream service and checks the bodies of
+// the endpoints declared with @http against their definitions. Requests to
+// other paths are forwarded unchecked.
+type Proxy struct {
+	schemas   cue.Value
+	endpoints []endpoint.Endpoint
+	opts      Options
+	rp        *httputil.ReverseProxy
+
+	// mu serializes access to CUE values and the record
+	mu sync.Mutex
+}
+
+// endpointKey carries the matched endpoint from a request to its response
+type endpointKey struct{}
+
+// NewProxy creates a proxy checking the endpoints of the schemas
+func NewProxy(schemas cue.Value, endpoints []endpoint.Endpoint, opts Options) (*Proxy, error) {
+	upstream, err := url.Parse(opts.Upstream)
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+		return nil, fmt.Errorf("upstream must be an http or https URL, got %q", opts.Upstream)
+	}
+
+	p := &Proxy{schemas: schemas, endpoints: endpoints, opts: opts}
+	p.rp = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.SetXForwarded()
+			// Ask for uncompressed bodies, so responses can be validated
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: p.checkResponse,
+		ErrorHandler:   p.handleError,
+	}
+	return p, nil
+}
+
+// ServeHTTP implements http.Handler
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	p.serve(rec, r)
+	p.logf("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+}
+
+// serve checks the request body of a declared endpoint and forwards the request
+func (p *Proxy) serve(w http.ResponseWriter, r *http.Request) {
+	e, _, err := endpoint.Find(p.endpoints, r.Method, r.URL.Path)
+	if err != nil {
+		p.rp.ServeHTTP(w, r)
+		return
+	}
+	r = r.WithContext(context.WithValue(r.Context(), endpointKey{}, e))
+
+	if e.Request != "" && r.Body != nil {
+		body, complete, err := readBody(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to read the request body", nil)
+			return
+		}
+		r.Body = body
+		if complete != nil {
+			if v := p.check(e, r, "request", e.Request, 0, r.Header.Get("Content-Type"), complete); v != nil && p.opts.Block {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("request body does not match %s", e.Request), v.Errors)
+				return
+			}
+		}
+	}
+
+	p.rp.ServeHTTP(w, r)
+}
+
+// checkResponse validates the body of a successful response of a declared
+// endpoint. A 2xx status other than the declared one is also a violation.
+func (p *Proxy) checkResponse(resp *http.Response) error {
+	e, ok := resp.Request.Context().Value(endpointKey{}).(endpoint.Endpoint)
+	if !ok || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil
+	}
+	r := resp.Request
+
+	if resp.StatusCode != e.Status {
+		v := p.violation(e, r, "response", e.Response, resp.StatusCode,
+			[]string{fmt.Sprintf("expected status %d, got %d", e.Status, resp.StatusCode)})
+		return p.blockResponse(v)
+	}
+	if !e.HasBody() {
+		return nil
+	}
+
+	body, complete, err := readBody(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = body
+	if complete == nil {
+		return nil
+	}
+	return p.blockResponse(p.check(e, r, "response", e.Response, resp.StatusCode, resp.Header.Get("Content-Type"), complete))
+}
+
+// blockResponse turns a response violation into an error when blocking
+func (p *Proxy) blockResponse(v *Violation) error {
+	if v == nil || !p.opts.Block {
+		return nil
+	}
+	return &violationError{v: v}
+}
+
+// check validates a JSON body against a definition and reports a violation
+// when it does not match
+func (p *Proxy) check(e endpoint.Endpoint, r *http.Request, direction, definition string, status int, contentType string, body []byte) *Violation {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return p.violation(e, r, direction, definition, status, []string{fmt.Sprintf("expected a JSON content type, got %q", contentType)})
+	}
+	if !json.Valid(body) {
+		return p.violation(e, r, direction, definition, status, []string{"body is not valid JSON"})
+	}
+
+	p.mu.Lock()
+	var errs []string
+	def, err := platoCue.LookupDefinition(p.schemas, definition)
+	if err != nil {
+		errs = []string{err.Error()}
+	} else {
+		for _, ve := range platoCue.ValidateData(def, def.Context().CompileBytes(body)).Errors {
+			errs = append(errs, ve.Message)
+		}
+	}
+	p.mu.Unlock()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return p.violation(e, r, direction, definition, status, errs)
+}
+
+// violation logs and records a violation
+func (p *Proxy) violation(e endpoint.Endpoint, r *http.Request, direction, definition string, status int, errs []string) *Violation {
+	v := &Violation{
+		Time:       time.Now().UTC(),
+		Endpoint:   e.String(),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Direction:  direction,
+		Definition: definition,
+		Status:     status,
+		Errors:     errs,
+		Blocked:    p.opts.Block,
+	}
+
+	action := "violation"
+	if v.Blocked {
+		action = "blocked"
+	}
+	p.logf("%s: %s %s %s does not match %s: %s", action, v.Method, v.Path, direction, definition, summary(errs))
+
+	if p.opts.Record != nil {
+		p.mu.Lock()
+		json.NewEncoder(p.opts.Record).Encode(v)
+		p.mu.Unlock()
+	}
+	return v
+}
+
+// handleError answers blocked responses with 502 and the violations, and
+// unreachable upstreams with 502
+func (p *Proxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	var ve *violationError
+	if errors.As(err, &ve) {
+		writeError(w, http.StatusBadGateway, ve.Error(), ve.v.Errors)
+		return
+	}
+	p.logf("upstream error: %v", err)
+	writeError(w, http.StatusBadGateway, "upstream unavailable", nil)
+}
+
+func (p *Proxy) logf(format string, args ...interface{}) {
+	if p.opts.Logf != nil {
+		p.opts.Logf(format, args...)
+	}
+}
+
+// readBody reads a body for validation. It returns a replacement body with
+// the same content, and the content itself unless it exceeds maxBodySize.
+func readBody(body io.ReadCloser) (io.ReadCloser, []byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+	if len(data) > maxBodySize {
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), body), body}, nil, nil
+	}
+	body.Close()
+	return io.NopCloser(bytes.NewReader(data)), data, nil
+}
+
+// summary shortens a list of validation errors for a log line
+func summary(errs []string) string {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return fmt.Sprintf("%s (and %d more)", errs[0], len(errs)-1)
+}
+
+// statusRecorder captures the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streamed responses through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, msg string, details []string) {
+	body := map[string]interface{}{"error": msg}
+	if len(details) > 0 {
+		body["details"] = details
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
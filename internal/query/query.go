@@ -0,0 +1,364 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Run evaluates a jq-like expression against input and returns the results.
+//
+// The supported language is a small subset of jq: a pipeline of stages
+// separated by '|', where each stage is one of
+//
+//	.                 identity
+//	.a.b  .#Def       field access (definition names may start with #)
+//	.a[]  .a[0]       iterate a list (or struct values) / index a list
+//	select(cond)      keep values where cond holds
+//	keys, length      struct keys / collection length
+//
+// A condition is an expression, optionally compared to a JSON literal using
+// ==, !=, <, <=, > or >=. Conditions may be combined with 'and' / 'or'.
+func Run(expr string, input interface{}) ([]interface{}, error) {
+	stages, err := splitTopLevel(expr, "|")
+	if err != nil {
+		return nil, err
+	}
+
+	stream := []interface{}{input}
+	for _, stage := range stages {
+		stage = strings.TrimSpace(stage)
+
+		var next []interface{}
+		for _, val := range stream {
+			out, err := applyStage(stage, val)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		stream = next
+	}
+
+	return stream, nil
+}
+
+// applyStage applies a single pipeline stage to a value
+func applyStage(stage string, val interface{}) ([]interface{}, error) {
+	switch {
+	case stage == "keys":
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("keys: value is not a struct")
+		}
+		keys := make([]interface{}, 0, len(obj))
+		for _, k := range sortedKeys(obj) {
+			keys = append(keys, k)
+		}
+		return []interface{}{keys}, nil
+
+	case stage == "length":
+		switch v := val.(type) {
+		case []interface{}:
+			return []interface{}{len(v)}, nil
+		case map[string]interface{}:
+			return []interface{}{len(v)}, nil
+		case string:
+			return []interface{}{len([]rune(v))}, nil
+		case nil:
+			return []interface{}{0}, nil
+		default:
+			return nil, fmt.Errorf("length: value has no length")
+		}
+
+	case strings.HasPrefix(stage, "select(") && strings.HasSuffix(stage, ")"):
+		ok, err := evalCondition(stage[len("select("):len(stage)-1], val)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return []interface{}{val}, nil
+		}
+		return nil, nil
+
+	case strings.HasPrefix(stage, "."):
+		return evalPath(stage, val)
+
+	default:
+		return nil, fmt.Errorf("unsupported expression: %s", stage)
+	}
+}
+
+// evalCondition evaluates a select() condition against a value
+func evalCondition(cond string, val interface{}) (bool, error) {
+	if parts, err := splitTopLevel(cond, " or "); err == nil && len(parts) > 1 {
+		for _, part := range parts {
+			ok, err := evalCondition(part, val)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	if parts, err := splitTopLevel(cond, " and "); err == nil && len(parts) > 1 {
+		for _, part := range parts {
+			ok, err := evalCondition(part, val)
+			if err != nil || !ok {
+				return ok, err
+			}
+		}
+		return true, nil
+	}
+
+	cond = strings.TrimSpace(cond)
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		idx := strings.Index(cond, op)
+		if idx < 0 {
+			continue
+		}
+
+		left, err := Run(strings.TrimSpace(cond[:idx]), val)
+		if err != nil {
+			return false, err
+		}
+
+		var right interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(cond[idx+len(op):])), &right); err != nil {
+			return false, fmt.Errorf("invalid literal in condition %q", cond)
+		}
+
+		for _, l := range left {
+			if compare(l, op, right) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	results, err := Run(cond, val)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range results {
+		if truthy(r) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// evalPath evaluates a path expression such as .a.b[] or .#Def[0]
+func evalPath(path string, val interface{}) ([]interface{}, error) {
+	if !strings.HasPrefix(path, ".") {
+		return nil, fmt.Errorf("path must start with '.': %s", path)
+	}
+
+	stream := []interface{}{val}
+	rest := path[1:]
+
+	for rest != "" {
+		var next []interface{}
+
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated '[' in %s", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			for _, v := range stream {
+				out, err := index(v, inner)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, out...)
+			}
+
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			continue
+
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+
+			for _, v := range stream {
+				switch obj := v.(type) {
+				case map[string]interface{}:
+					next = append(next, obj[name])
+				case nil:
+					next = append(next, nil)
+				default:
+					return nil, fmt.Errorf("cannot access field %q of non-struct value", name)
+				}
+			}
+		}
+
+		stream = next
+	}
+
+	return stream, nil
+}
+
+// index applies a [] or [N] suffix to a value
+func index(val interface{}, inner string) ([]interface{}, error) {
+	if inner == "" || inner == "*" {
+		switch v := val.(type) {
+		case []interface{}:
+			return v, nil
+		case map[string]interface{}:
+			var out []interface{}
+			for _, k := range sortedKeys(v) {
+				out = append(out, v[k])
+			}
+			return out, nil
+		default:
+			return nil, fmt.Errorf("cannot iterate over non-collection value")
+		}
+	}
+
+	if key, err := strconv.Unquote(inner); err == nil {
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot access field %q of non-struct value", key)
+		}
+		return []interface{}{obj[key]}, nil
+	}
+
+	i, err := strconv.Atoi(inner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index %q", inner)
+	}
+	list, ok := val.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot index non-list value")
+	}
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return []interface{}{nil}, nil
+	}
+	return []interface{}{list[i]}, nil
+}
+
+// compare compares two decoded values with the given operator
+func compare(left interface{}, op string, right interface{}) bool {
+	if lf, ok := toFloat(left); ok {
+		if rf, ok := toFloat(right); ok {
+			switch op {
+			case "==":
+				return lf == rf
+			case "!=":
+				return lf != rf
+			case "<":
+				return lf < rf
+			case "<=":
+				return lf <= rf
+			case ">":
+				return lf > rf
+			case ">=":
+				return lf >= rf
+			}
+		}
+	}
+
+	if ls, ok := left.(string); ok {
+		if rs, ok := right.(string); ok {
+			switch op {
+			case "<":
+				return ls < rs
+			case "<=":
+				return ls <= rs
+			case ">":
+				return ls > rs
+			case ">=":
+				return ls >= rs
+			}
+		}
+	}
+
+	equal := fmt.Sprint(left) == fmt.Sprint(right)
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	}
+	return false
+}
+
+// toFloat converts numeric values to float64
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// truthy follows jq semantics: only false and null are falsy
+func truthy(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside brackets or quotes
+func splitTopLevel(s, sep string) ([]string, error) {
+	var parts []string
+	depth := 0
+	inString := false
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' && (i == 0 || s[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+
+	if depth != 0 || inString {
+		return nil, fmt.Errorf("unbalanced expression: %s", s)
+	}
+
+	return append(parts, s[start:]), nil
+}
+
+// sortedKeys returns the keys of a struct in sorted order
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
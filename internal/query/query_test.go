@@ -0,0 +1,47 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var input interface{}
+	if err := json.Unmarshal([]byte(`{
+		"#Order": {"name": "#Order", "fields": [
+			{"name": "id", "optional": false},
+			{"name": "note", "optional": true}
+		]},
+		"items": [{"sku": "a", "price": 50}, {"sku": "b", "price": 150}]
+	}`), &input); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{".#Order.fields[] | select(.optional) | .name", `["note"]`},
+		{".items[] | select(.price > 100) | .sku", `["b"]`},
+		{".items[] | select(.price > 10 and .sku != \"a\") | .sku", `["b"]`},
+		{".items[0].sku", `["a"]`},
+		{"keys", `[["#Order","items"]]`},
+		{".items | length", `[2]`},
+		{".#Order.fields[] | select(.name == \"id\") | .optional", `[false]`},
+	}
+	for _, tt := range tests {
+		results, err := Run(tt.expr, input)
+		if err != nil {
+			t.Errorf("Run(%q): %v", tt.expr, err)
+			continue
+		}
+		got, _ := json.Marshal(results)
+		if string(got) != tt.want {
+			t.Errorf("Run(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+
+	if _, err := Run(".items | keys", input); err == nil {
+		t.Errorf("keys of a list did not fail")
+	}
+}
@@ -0,0 +1,258 @@
+// Package release assembles the release notes of a schema version: how the
+// definitions changed, how the generated artifacts changed and which
+// projects consuming the schemas are affected, rendered through a markdown
+// template.
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"github.com/platoorg/plato-sl-cli/internal/project"
+)
+
+// Change is a change of a definition or field
+type Change struct {
+	// Path is the definition or field, e.g. #Order.total
+	Path string `json:"path"`
+
+	// Kind is added, removed or changed; Detail describes the change
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+
+	// Breaking is set when data or code valid before may no longer be
+	Breaking bool `json:"breaking"`
+}
+
+// Definition returns the top-level definition of a change, e.g. #Order
+func (c Change) Definition() string {
+	def, _, _ := strings.Cut(c.Path, ".")
+	return def
+}
+
+// Artifact is the change of a generator's output
+type Artifact struct {
+	Generator string `json:"generator"`
+	Path      string `json:"path"`
+
+	// Status is changed or unchanged; Added and Removed count the lines
+	Status  string `json:"status"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// Consumer is a project of the repository depending on the schemas that
+// references changed definitions
+type Consumer struct {
+	Project string `json:"project"`
+	Dir     string `json:"dir"`
+
+	// Uses lists the changed definitions the project references; Breaking
+	// is set when any of them changed in a breaking way
+	Uses     []string `json:"uses"`
+	Breaking bool     `json:"breaking"`
+}
+
+// Notes are the release notes of a schema version
+type Notes struct {
+	Project string    `json:"project"`
+	Version string    `json:"version,omitempty"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Date    time.Time `json:"date"`
+
+	Changes   []Change   `json:"changes"`
+	Artifacts []Artifact `json:"artifacts"`
+	Consumers []Consumer `json:"consumers"`
+}
+
+// Breaking returns the breaking changes
+func (n *Notes) Breaking() []Change {
+	var changes []Change
+	for _, c := range n.Changes {
+		if c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// NonBreaking returns the changes that are not breaking
+func (n *Notes) NonBreaking() []Change {
+	var changes []Change
+	for _, c := range n.Changes {
+		if !c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// ChangedArtifacts returns the artifacts whose output changed
+func (n *Notes) ChangedArtifacts() []Artifact {
+	var artifacts []Artifact
+	for _, a := range n.Artifacts {
+		if a.Status != "unchanged" {
+			artifacts = append(artifacts, a)
+		}
+	}
+	return artifacts
+}
+
+// DefaultTemplate renders release notes as markdown. Custom templates get
+// the same data: the Notes and its methods.
+const DefaultTemplate = `# {{.Project}}{{if .Version}} {{.Version}}{{end}}
+
+Schema changes from {{.From}} to {{.To}}, {{.Date.Format "2006-01-02"}}.
+{{- if not .Changes}}
+
+No definitions changed.
+{{- end}}
+{{- with .Breaking}}
+
+## Breaking changes
+{{range .}}
+- ` + "`{{.Path}}`" + `: {{.Detail}}
+{{- end}}
+{{- end}}
+{{- with .NonBreaking}}
+
+## Changes
+{{range .}}
+- ` + "`{{.Path}}`" + `: {{.Detail}}
+{{- end}}
+{{- end}}
+{{- with .ChangedArtifacts}}
+
+## Generated code
+
+| Generator | Output | Change |
+|-----------|--------|--------|
+{{- range .}}
+| {{.Generator}} | ` + "`{{.Path}}`" + ` | {{.Status}}{{if or .Added .Removed}} (+{{.Added}} -{{.Removed}}){{end}} |
+{{- end}}
+{{- end}}
+{{- with .Consumers}}
+
+## Affected consumers
+{{range .}}
+- **{{.Project}}** ({{.Dir}}) uses {{join .Uses ", "}}{{if .Breaking}}, with breaking changes{{end}}
+{{- end}}
+{{- end}}
+`
+
+// Render renders the notes through a text/template, the default template
+// when tmpl is empty. Templates can use join, like strings.Join.
+func (n *Notes) Render(tmpl string) ([]byte, error) {
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+	t, err := template.New("notes").Funcs(template.FuncMap{"join": strings.Join}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release notes template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, n); err != nil {
+		return nil, fmt.Errorf("failed to render release notes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Consumers returns the projects that depend on the project in dir and
+// reference definitions with changes, found by the references to #Name in
+// their schema files. Paths are relative to root.
+func Consumers(root, dir string, projects []*project.Project, changes []Change) ([]Consumer, error) {
+	changed := make(map[string]bool)
+	breaking := make(map[string]bool)
+	for _, c := range changes {
+		changed[c.Definition()] = true
+		breaking[c.Definition()] = breaking[c.Definition()] || c.Breaking
+	}
+
+	var consumers []Consumer
+	for _, p := range projects {
+		if p.Dir == dir || !slices.Contains(p.Dependencies(), dir) {
+			continue
+		}
+		refs, err := references(p)
+		if err != nil {
+			return nil, err
+		}
+		c := Consumer{Project: p.Name(), Dir: p.Dir}
+		if rel, err := filepath.Rel(root, p.Dir); err == nil {
+			c.Dir = filepath.ToSlash(rel)
+		}
+		for name := range refs {
+			if changed[name] {
+				c.Uses = append(c.Uses, name)
+				c.Breaking = c.Breaking || breaking[name]
+			}
+		}
+		if len(c.Uses) > 0 {
+			sort.Strings(c.Uses)
+			consumers = append(consumers, c)
+		}
+	}
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Project < consumers[j].Project })
+	return consumers, nil
+}
+
+// references returns the definitions referenced in the schema files of a
+// project, e.g. #Order
+func references(p *project.Project) (map[string]bool, error) {
+	refs := make(map[string]bool)
+	for _, schemaPath := range p.Config.Schemas {
+		root := filepath.Join(p.Dir, schemaPath)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "cue.mod") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".cue") {
+				return nil
+			}
+			f, err := parser.ParseFile(path, nil)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			// Labels declare definitions of the project's own; only the
+			// identifiers in values refer to others
+			var visit func(n ast.Node) bool
+			visit = func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.Field:
+					ast.Walk(n.Value, visit, nil)
+					return false
+				case *ast.Ident:
+					if strings.HasPrefix(n.Name, "#") {
+						refs[n.Name] = true
+					}
+				}
+				return true
+			}
+			ast.Walk(f, visit, nil)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,151 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// DiffSchemas lists how the definitions changed from one version of the
+// schemas to another, field by field. Both values must come from the same
+// CUE context. Removed definitions and fields, new required fields, fields
+// becoming required or optional and narrowed types are breaking; additions
+// of optional fields and definitions and widened types are not.
+func DiffSchemas(from, to cue.Value) []Change {
+	d := &differ{}
+	d.structs("", definitions(from), definitions(to))
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Path < d.changes[j].Path })
+	return d.changes
+}
+
+// differ collects the changes between two versions of the schemas
+type differ struct {
+	changes []Change
+}
+
+// field is a definition or field of a struct
+type field struct {
+	value    cue.Value
+	optional bool
+}
+
+func (d *differ) add(path, kind, detail string, breaking bool) {
+	d.changes = append(d.changes, Change{Path: path, Kind: kind, Detail: detail, Breaking: breaking})
+}
+
+// structs compares the fields of two structs under prefix
+func (d *differ) structs(prefix string, from, to map[string]field) {
+	for _, name := range sortedNames(from, to) {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		old, hadOld := from[name]
+		updated, hasNew := to[name]
+		kind := "field"
+		if strings.HasPrefix(name, "#") {
+			kind = "definition"
+		}
+
+		switch {
+		case !hasNew:
+			d.add(path, "removed", kind+" removed", true)
+		case !hadOld && kind == "definition":
+			d.add(path, "added", "definition added", false)
+		case !hadOld && updated.optional:
+			d.add(path, "added", fmt.Sprintf("optional field added (%s)", describe(updated.value)), false)
+		case !hadOld:
+			d.add(path, "added", fmt.Sprintf("required field added (%s)", describe(updated.value)), true)
+		default:
+			if old.optional && !updated.optional {
+				d.add(path, "changed", "optional field became required", true)
+			} else if !old.optional && updated.optional {
+				d.add(path, "changed", "required field became optional", true)
+			}
+			d.values(path, old.value, updated.value)
+		}
+	}
+}
+
+// values compares two versions of a value: the fields of structs, or the
+// types and constraints of anything else
+func (d *differ) values(path string, from, to cue.Value) {
+	if from.IncompleteKind() == cue.StructKind && to.IncompleteKind() == cue.StructKind {
+		d.structs(path, fields(from), fields(to))
+		return
+	}
+
+	widened := to.Subsume(from) == nil
+	narrowed := from.Subsume(to) == nil
+	switch {
+	case widened && narrowed:
+		// Equivalent
+	case widened:
+		d.add(path, "changed", fmt.Sprintf("widened from %s to %s", describe(from), describe(to)), false)
+	case narrowed:
+		d.add(path, "changed", fmt.Sprintf("narrowed from %s to %s", describe(from), describe(to)), true)
+	default:
+		d.add(path, "changed", fmt.Sprintf("changed from %s to %s", describe(from), describe(to)), true)
+	}
+}
+
+// definitions returns the top-level definitions of the schemas
+func definitions(val cue.Value) map[string]field {
+	defs := make(map[string]field)
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return defs
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			defs[iter.Selector().String()] = field{value: iter.Value()}
+		}
+	}
+	return defs
+}
+
+// fields returns the regular fields of a struct, optional ones included
+func fields(val cue.Value) map[string]field {
+	result := make(map[string]field)
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return result
+	}
+	for iter.Next() {
+		name := strings.TrimRight(iter.Selector().String(), "?!")
+		result[name] = field{value: iter.Value(), optional: iter.IsOptional()}
+	}
+	return result
+}
+
+// sortedNames returns the names of both field sets in order
+func sortedNames(a, b map[string]field) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var names []string
+	for _, m := range []map[string]field{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describe renders a value for a change description, e.g. int & >0,
+// shortened to its first line. Bounds get their kind, which CUE leaves
+// implied, e.g. number & >=0 rather than >=0.
+func describe(val cue.Value) string {
+	text := fmt.Sprint(val)
+	if strings.HasPrefix(text, ">") || strings.HasPrefix(text, "<") || strings.HasPrefix(text, "!=") || strings.HasPrefix(text, "=~") || strings.HasPrefix(text, "!~") {
+		text = val.IncompleteKind().String() + " & " + text
+	}
+	if line, _, cut := strings.Cut(text, "\n"); cut {
+		return line + " …"
+	}
+	return text
+}
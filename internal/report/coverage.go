@@ -0,0 +1,242 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Finding is a part of a schema that no data file exercises
+type Finding struct {
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Coverage is the result of analysing a data corpus against a definition
+type Coverage struct {
+	Definition string    `json:"definition"`
+	Files      int       `json:"files"`
+	Skipped    []string  `json:"skipped,omitempty"`
+	Findings   []Finding `json:"findings"`
+}
+
+// fieldUsage tracks how often a field is present in the data
+type fieldUsage struct {
+	optional bool
+	present  int
+}
+
+// branchUsage tracks which branches of a disjunction data matched
+type branchUsage struct {
+	labels  []string
+	matched []bool
+}
+
+// listUsage tracks whether a list ever had elements
+type listUsage struct {
+	nonEmpty bool
+}
+
+// coverageWalker accumulates usage across data files
+type coverageWalker struct {
+	fields   map[string]*fieldUsage
+	branches map[string]*branchUsage
+	lists    map[string]*listUsage
+}
+
+// AnalyzeCoverage reports constraints of def that the data files under paths
+// never exercise: optional fields never present, disjunction branches (such
+// as enum values) never used, and lists that are always empty. Files that
+// cannot be loaded are skipped and listed in the result.
+func AnalyzeCoverage(loader *platoCue.Loader, def cue.Value, definition string, paths []string) (*Coverage, error) {
+	files, err := collectDataFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &coverageWalker{
+		fields:   make(map[string]*fieldUsage),
+		branches: make(map[string]*branchUsage),
+		lists:    make(map[string]*listUsage),
+	}
+
+	result := &Coverage{Definition: definition}
+	for _, path := range files {
+		data, err := loader.LoadDataFile(path)
+		if err != nil {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+
+		result.Files++
+		w.walk(def, data, "")
+	}
+
+	if result.Files > 0 {
+		result.Findings = w.findings()
+	}
+	return result, nil
+}
+
+// walk records the usage of schema by a single data value
+func (w *coverageWalker) walk(schema, data cue.Value, path string) {
+	if op, args := schema.Expr(); op == cue.OrOp && len(args) > 1 {
+		usage := w.branches[path]
+		if usage == nil {
+			usage = &branchUsage{matched: make([]bool, len(args))}
+			for _, arg := range args {
+				usage.labels = append(usage.labels, branchLabel(arg))
+			}
+			w.branches[path] = usage
+		}
+
+		// Descend into the first branch the value matches
+		matched := -1
+		for i, arg := range args {
+			if matches(arg, data) {
+				usage.matched[i] = true
+				if matched < 0 {
+					matched = i
+				}
+			}
+		}
+		if matched < 0 {
+			return
+		}
+		schema = args[matched]
+	}
+
+	switch schema.IncompleteKind() {
+	case cue.StructKind:
+		if data.Kind() != cue.StructKind {
+			return
+		}
+		iter, err := schema.Fields(cue.Optional(true))
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			if iter.Selector().IsDefinition() {
+				continue
+			}
+			label := iter.Selector().Unquoted()
+			fieldPath := joinPath(path, label)
+
+			usage := w.fields[fieldPath]
+			if usage == nil {
+				usage = &fieldUsage{optional: iter.IsOptional()}
+				w.fields[fieldPath] = usage
+			}
+			if value := data.LookupPath(cue.MakePath(cue.Str(label))); value.Exists() {
+				usage.present++
+				w.walk(iter.Value(), value, fieldPath)
+			}
+		}
+
+	case cue.ListKind:
+		if data.Kind() != cue.ListKind {
+			return
+		}
+		usage := w.lists[path]
+		if usage == nil {
+			usage = &listUsage{}
+			w.lists[path] = usage
+		}
+
+		elem := schema.LookupPath(cue.MakePath(cue.AnyIndex))
+		items, err := data.List()
+		if err != nil {
+			return
+		}
+		for items.Next() {
+			usage.nonEmpty = true
+			if elem.Exists() {
+				w.walk(elem, items.Value(), path+"[]")
+			}
+		}
+	}
+}
+
+// findings lists the unexercised parts of the schema, sorted by path
+func (w *coverageWalker) findings() []Finding {
+	findings := []Finding{}
+
+	for path, usage := range w.fields {
+		if usage.optional && usage.present == 0 {
+			findings = append(findings, Finding{
+				Path:    path,
+				Kind:    "optional-field",
+				Message: "optional field never present",
+			})
+		}
+	}
+
+	for path, usage := range w.branches {
+		for i, matched := range usage.matched {
+			if matched {
+				continue
+			}
+			findings = append(findings, Finding{
+				Path:    displayPath(path),
+				Kind:    "branch",
+				Message: fmt.Sprintf("%s never used", usage.labels[i]),
+			})
+		}
+	}
+
+	for path, usage := range w.lists {
+		if !usage.nonEmpty {
+			findings = append(findings, Finding{
+				Path:    displayPath(path),
+				Kind:    "empty-list",
+				Message: "list is always empty",
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Message < findings[j].Message
+	})
+	return findings
+}
+
+// matches reports whether a data value is an instance of a disjunction branch
+func matches(branch, data cue.Value) bool {
+	return branch.Unify(data).Validate() == nil
+}
+
+// branchLabel describes a disjunction branch, e.g. "published", #Address or int
+func branchLabel(branch cue.Value) string {
+	if _, path := branch.ReferencePath(); len(path.Selectors()) > 0 {
+		return path.String()
+	}
+	if branch.IncompleteKind() == cue.StructKind {
+		return "struct branch"
+	}
+	if branch.IsConcrete() {
+		return "value " + fmt.Sprint(branch)
+	}
+	return "type " + fmt.Sprint(branch)
+}
+
+// joinPath appends a field label to a path
+func joinPath(path, label string) string {
+	if path == "" {
+		return label
+	}
+	return path + "." + label
+}
+
+// displayPath names the document root for findings on the definition itself
+func displayPath(path string) string {
+	if path == "" {
+		return "(document)"
+	}
+	return path
+}
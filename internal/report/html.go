@@ -0,0 +1,164 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// fieldChartLimit is the number of fields shown in the per-field chart
+const fieldChartLimit = 10
+
+// WriteHTML renders the validation results as a self-contained HTML page.
+// Styles and charts are inlined so the file can be shared or opened offline.
+func WriteHTML(w io.Writer, v *Validation) error {
+	data := struct {
+		*Validation
+		PassPercent int
+		Categories  []bar
+		Fields      []bar
+	}{
+		Validation: v,
+		Categories: bars(v.ByCategory()),
+		Fields:     bars(v.ByField(fieldChartLimit)),
+	}
+	if len(v.Files) > 0 {
+		data.PassPercent = v.Passed() * 100 / len(v.Files)
+	}
+
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+// bar is a chart row with its width relative to the largest count
+type bar struct {
+	Label string
+	Count int
+	Width int
+}
+
+// bars scales counts to percentage widths
+func bars(counts []Count) []bar {
+	max := 0
+	for _, c := range counts {
+		if c.Count > max {
+			max = c.Count
+		}
+	}
+
+	result := make([]bar, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, bar{Label: c.Label, Count: c.Count, Width: c.Count * 100 / max})
+	}
+	return result
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pie": func(percent int) template.CSS {
+		return template.CSS(fmt.Sprintf("background: conic-gradient(#2e9d5b 0 %d%%, #d64541 %d%% 100%%)", percent, percent))
+	},
+	"width": func(percent int) template.CSS {
+		return template.CSS(fmt.Sprintf("width: %d%%", percent))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #f5f6f8; color: #1f2328; }
+  header { background: #1f2937; color: #fff; padding: 24px 32px; }
+  header h1 { margin: 0 0 4px; font-size: 22px; }
+  header p { margin: 0; color: #cbd5e1; font-size: 14px; }
+  main { max-width: 1100px; margin: 0 auto; padding: 24px 32px 48px; }
+  .cards { display: flex; gap: 16px; flex-wrap: wrap; }
+  .card { background: #fff; border-radius: 8px; padding: 16px 20px; box-shadow: 0 1px 2px rgba(0,0,0,.08); flex: 1; min-width: 160px; }
+  .card .value { font-size: 28px; font-weight: 600; }
+  .card .label { color: #57606a; font-size: 13px; }
+  .pass { color: #2e9d5b; }
+  .fail { color: #d64541; }
+  .charts { display: grid; grid-template-columns: 200px 1fr 1fr; gap: 16px; margin-top: 16px; }
+  .pie { width: 140px; height: 140px; border-radius: 50%; margin: 8px auto; }
+  h2 { font-size: 16px; margin: 0 0 12px; }
+  .row { display: grid; grid-template-columns: 160px 1fr 40px; align-items: center; gap: 8px; font-size: 13px; margin-bottom: 6px; }
+  .row .name { overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+  .track { background: #eef0f3; border-radius: 4px; height: 12px; }
+  .fill { background: #d64541; border-radius: 4px; height: 12px; }
+  .count { text-align: right; color: #57606a; }
+  table { width: 100%; border-collapse: collapse; background: #fff; border-radius: 8px; overflow: hidden; box-shadow: 0 1px 2px rgba(0,0,0,.08); margin-top: 24px; }
+  th, td { text-align: left; padding: 10px 14px; border-bottom: 1px solid #eef0f3; font-size: 14px; vertical-align: top; }
+  th { background: #fafbfc; font-weight: 600; }
+  td.status { width: 70px; font-weight: 600; }
+  ul { margin: 0; padding-left: 18px; }
+  li { margin-bottom: 4px; }
+  code { background: #eef0f3; padding: 1px 4px; border-radius: 3px; font-size: 12px; }
+  .tag { display: inline-block; background: #fde8e7; color: #a8322d; border-radius: 10px; padding: 0 8px; font-size: 12px; margin-right: 6px; }
+  .muted { color: #57606a; }
+  @media (max-width: 800px) { .charts { grid-template-columns: 1fr; } }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Title}}</h1>
+  <p>Data validated against <strong>{{.Definition}}</strong> &middot; generated {{.Generated.Format "2006-01-02 15:04 MST"}}</p>
+</header>
+<main>
+  <section class="cards">
+    <div class="card"><div class="value">{{len .Files}}</div><div class="label">files checked</div></div>
+    <div class="card"><div class="value pass">{{.Passed}}</div><div class="label">passed</div></div>
+    <div class="card"><div class="value fail">{{.Failed}}</div><div class="label">failed</div></div>
+    <div class="card"><div class="value">{{.ErrorCount}}</div><div class="label">errors</div></div>
+  </section>
+
+  <section class="charts">
+    <div class="card">
+      <h2>Pass rate</h2>
+      <div class="pie" style="{{pie .PassPercent}}"></div>
+      <p class="muted" style="text-align: center; margin: 0;">{{.PassPercent}}% passed</p>
+    </div>
+    <div class="card">
+      <h2>Errors by kind</h2>
+      {{- range .Categories}}
+      <div class="row"><span class="name">{{.Label}}</span><div class="track"><div class="fill" style="{{width .Width}}"></div></div><span class="count">{{.Count}}</span></div>
+      {{- else}}
+      <p class="muted">No errors.</p>
+      {{- end}}
+    </div>
+    <div class="card">
+      <h2>Errors by field</h2>
+      {{- range .Fields}}
+      <div class="row"><span class="name" title="{{.Label}}">{{.Label}}</span><div class="track"><div class="fill" style="{{width .Width}}"></div></div><span class="count">{{.Count}}</span></div>
+      {{- else}}
+      <p class="muted">No errors.</p>
+      {{- end}}
+    </div>
+  </section>
+
+  <table>
+    <thead><tr><th>Status</th><th>File</th><th>Errors</th></tr></thead>
+    <tbody>
+    {{- range .Files}}
+      <tr>
+        {{- if .Passed}}
+        <td class="status pass">Pass</td><td>{{.Path}}</td><td class="muted">&mdash;</td>
+        {{- else}}
+        <td class="status fail">Fail</td><td>{{.Path}}</td>
+        <td><ul>
+          {{- range .Errors}}
+          <li><span class="tag">{{.Category}}</span>{{if .Path}}<code>{{.Path}}</code> {{end}}{{.Message}}</li>
+          {{- end}}
+        </ul></td>
+        {{- end}}
+      </tr>
+    {{- else}}
+      <tr><td colspan="3" class="muted">No data files found.</td></tr>
+    {{- end}}
+    </tbody>
+  </table>
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,249 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+	"github.com/platoorg/plato-sl-cli/internal/workers"
+)
+
+// FileResult is the validation outcome of a single data file
+type FileResult struct {
+	Path   string
+	Errors []Issue
+}
+
+// Passed reports whether the file validated without errors
+func (f FileResult) Passed() bool {
+	return len(f.Errors) == 0
+}
+
+// Issue is a single validation error with its breakdown category
+type Issue struct {
+	Category string
+	Path     string
+	Message  string
+}
+
+// Validation holds the results of validating data files against a definition
+type Validation struct {
+	Title      string
+	Definition string
+	Generated  time.Time
+	Files      []FileResult
+}
+
+// Count is a label with a number of occurrences, used for breakdown charts
+type Count struct {
+	Label string
+	Count int
+}
+
+// Validate validates every data file under paths against a definition.
+// Directories are walked recursively; files that fail to load are reported
+// as failures rather than aborting the run. Files are checked in parallel:
+// each worker calls load once for its own loader and definition, since CUE
+// values must not be shared between goroutines.
+func Validate(paths []string, load func() (*platoCue.Loader, cue.Value, error)) ([]FileResult, error) {
+	files, err := collectDataFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	type worker struct {
+		loader *platoCue.Loader
+		def    cue.Value
+	}
+
+	results := make([]FileResult, len(files))
+	err = workers.RunWeighted(len(files), func(i int) int64 {
+		return fileWeight(files[i])
+	}, func() (worker, error) {
+		loader, def, err := load()
+		return worker{loader: loader, def: def}, err
+	}, func(w worker, i int) error {
+		results[i] = validateFile(w.loader, w.def, files[i])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// validateFile validates a single data file against def
+func validateFile(loader *platoCue.Loader, def cue.Value, path string) FileResult {
+	result := FileResult{Path: path}
+
+	data, err := loader.LoadDataFile(path)
+	if err != nil {
+		result.Errors = []Issue{{Category: "unreadable file", Message: err.Error()}}
+		return result
+	}
+
+	validation := platoCue.ValidateData(def, data)
+	for _, verr := range validation.Errors {
+		// Skip disjunction summaries; their causes are listed separately
+		if strings.HasSuffix(verr.Message, "errors in empty disjunction:") {
+			continue
+		}
+		result.Errors = append(result.Errors, Issue{
+			Category: categorize(verr.Message),
+			Path:     fieldPath(verr.Path),
+			Message:  strings.TrimPrefix(verr.Message, verr.Path+": "),
+		})
+	}
+	return result
+}
+
+// fileWeight estimates the memory needed to check a data file
+func fileWeight(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return workers.FileWeight(info.Size())
+}
+
+// collectDataFiles expands directories into the data files they contain
+func collectDataFiles(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			if !seen[root] {
+				seen[root] = true
+				files = append(files, root)
+			}
+			continue
+		}
+
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !platoCue.IsDataFile(path) || seen[path] {
+				return nil
+			}
+			seen[path] = true
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// fieldPath strips the leading definition from an error path, so
+// "#Article.author.name" is reported as "author.name"
+func fieldPath(path string) string {
+	if strings.HasPrefix(path, "#") {
+		if idx := strings.Index(path, "."); idx > 0 {
+			return path[idx+1:]
+		}
+		return ""
+	}
+	return path
+}
+
+// categorize buckets a CUE error message for the error breakdown
+func categorize(msg string) string {
+	lower := strings.ToLower(msg)
+
+	switch {
+	case strings.Contains(lower, "incomplete value") || strings.Contains(lower, "non-concrete"):
+		return "missing field"
+	case strings.Contains(lower, "not allowed"):
+		return "unknown field"
+	case strings.Contains(lower, "mismatched types") || strings.Contains(lower, "cannot use"):
+		return "type mismatch"
+	case strings.Contains(lower, "invalid value") || strings.Contains(lower, "out of bound"):
+		return "constraint violation"
+	case strings.Contains(lower, "conflicting values") || strings.Contains(lower, "empty disjunction"):
+		return "invalid value"
+	default:
+		return "other"
+	}
+}
+
+// Passed returns the number of files without errors
+func (v *Validation) Passed() int {
+	n := 0
+	for _, f := range v.Files {
+		if f.Passed() {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the number of files with errors
+func (v *Validation) Failed() int {
+	return len(v.Files) - v.Passed()
+}
+
+// ErrorCount returns the total number of errors across all files
+func (v *Validation) ErrorCount() int {
+	n := 0
+	for _, f := range v.Files {
+		n += len(f.Errors)
+	}
+	return n
+}
+
+// ByCategory counts errors per category, most frequent first
+func (v *Validation) ByCategory() []Count {
+	return v.countBy(func(issue Issue) string { return issue.Category }, 0)
+}
+
+// ByField counts errors per field path, most frequent first, keeping the top limit
+func (v *Validation) ByField(limit int) []Count {
+	return v.countBy(func(issue Issue) string {
+		if issue.Path == "" {
+			return "(document)"
+		}
+		return issue.Path
+	}, limit)
+}
+
+// countBy tallies errors by key; limit <= 0 keeps all entries
+func (v *Validation) countBy(key func(Issue) string, limit int) []Count {
+	counts := make(map[string]int)
+	for _, f := range v.Files {
+		for _, issue := range f.Errors {
+			counts[key(issue)]++
+		}
+	}
+
+	result := make([]Count, 0, len(counts))
+	for label, n := range counts {
+		result = append(result, Count{Label: label, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Label < result[j].Label
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
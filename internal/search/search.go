@@ -0,0 +1,248 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/parser"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// Match is a definition or field whose name, doc comment or attributes
+// contain the searched text
+type Match struct {
+	// Path is the definition or field, e.g. #Person.email
+	Path string `json:"path"`
+
+	// Kind is definition or field
+	Kind string `json:"kind"`
+
+	// In says where the text was found: name, doc or attribute; Text is
+	// the name, the doc comment or the attribute, e.g. @unit(kg)
+	In   string `json:"in"`
+	Text string `json:"text"`
+
+	// Source is the file and line of the declaration, with the import path
+	// for declarations of imported packages
+	Source platoCue.SourceRef `json:"source"`
+}
+
+// File is a parsed schema file and the import path of its package, empty
+// for the project's own files
+type File struct {
+	AST     *ast.File
+	Package string
+}
+
+// LocalFiles parses the CUE files under dirs, skipping cue.mod and hidden
+// directories
+func LocalFiles(dirs []string) ([]File, error) {
+	var paths []string
+	for _, root := range dirs {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "cue.mod") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".cue") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	files := make([]File, 0, len(paths))
+	for _, path := range paths {
+		f, err := parser.ParseFile(path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		files = append(files, File{AST: f})
+	}
+	return files, nil
+}
+
+// ImportedFiles returns the files of the packages the schemas under dirs
+// import, directly or transitively, resolved through the CUE module cache
+// like deps.Resolve. The standard library is left out. A project without
+// cue.mod imports nothing.
+func ImportedFiles(root string, dirs []string) ([]File, error) {
+	if _, err := os.Stat(filepath.Join(root, "cue.mod", "module.cue")); err != nil {
+		return nil, nil
+	}
+
+	var args []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("schema directory %s is outside the CUE module at %s", dir, root)
+		}
+		args = append(args, "./"+filepath.ToSlash(filepath.Join(rel, "...")))
+	}
+
+	insts := load.Instances(args, &load.Config{ModuleRoot: root, Dir: root, Registry: platoCue.Registry()})
+	for _, inst := range insts {
+		if inst.Err != nil {
+			return nil, fmt.Errorf("failed to resolve imports: %w", inst.Err)
+		}
+	}
+
+	var files []File
+	visited := make(map[*build.Instance]bool)
+	var walk func(inst *build.Instance, local string)
+	walk = func(inst *build.Instance, local string) {
+		for _, imp := range inst.Imports {
+			if visited[imp] || imp.Module == "" {
+				continue
+			}
+			visited[imp] = true
+			if imp.Module != local {
+				for _, f := range imp.Files {
+					files = append(files, File{AST: f, Package: imp.ImportPath})
+				}
+			}
+			walk(imp, local)
+		}
+	}
+	for _, inst := range insts {
+		walk(inst, inst.Module)
+	}
+	return files, nil
+}
+
+// Search returns the definitions and fields of files whose name, doc
+// comment or attributes contain text, ignoring case. Name matches come
+// first, exact ones before partial ones, then doc and attribute matches,
+// each in source order. File names are made relative to root.
+func Search(files []File, text, root string) []Match {
+	s := &searcher{text: strings.ToLower(text), root: root}
+	for _, f := range files {
+		s.file = f
+		s.decls(f.AST.Decls, "")
+	}
+
+	sort.SliceStable(s.matches, func(i, j int) bool {
+		return s.rank(s.matches[i]) < s.rank(s.matches[j])
+	})
+	return s.matches
+}
+
+// searcher collects the matches of the file being searched
+type searcher struct {
+	text    string
+	root    string
+	file    File
+	matches []Match
+}
+
+// rank orders matches: exact names, other names, then docs and attributes
+func (s *searcher) rank(m Match) int {
+	switch {
+	case m.In == "name" && strings.ToLower(strings.TrimPrefix(m.Text, "#")) == strings.TrimPrefix(s.text, "#"):
+		return 0
+	case m.In == "name":
+		return 1
+	}
+	return 2
+}
+
+// decls searches the fields among decls and the structs in their values
+func (s *searcher) decls(decls []ast.Decl, prefix string) {
+	for _, decl := range decls {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		name, _, err := ast.LabelName(field.Label)
+		if err != nil {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		s.field(field, name, path)
+
+		for _, st := range structs(field.Value) {
+			s.decls(st.Elts, path)
+		}
+	}
+}
+
+// field records a match of a field's name, doc comment or attributes
+func (s *searcher) field(field *ast.Field, name, path string) {
+	kind := "field"
+	if strings.HasPrefix(name, "#") {
+		kind = "definition"
+	}
+	add := func(in, text string) {
+		s.matches = append(s.matches, Match{Path: path, Kind: kind, In: in, Text: text, Source: s.source(field)})
+	}
+
+	if s.contains(name) {
+		add("name", name)
+		return
+	}
+	for _, cg := range ast.Comments(field) {
+		if !cg.Doc {
+			continue
+		}
+		if doc := strings.TrimSpace(cg.Text()); s.contains(doc) {
+			add("doc", doc)
+			return
+		}
+	}
+	for _, attr := range field.Attrs {
+		if s.contains(attr.Text) {
+			add("attribute", attr.Text)
+			return
+		}
+	}
+}
+
+// contains reports whether s holds the searched text, ignoring case
+func (s *searcher) contains(str string) bool {
+	return strings.Contains(strings.ToLower(str), s.text)
+}
+
+// source returns where a field is declared
+func (s *searcher) source(field *ast.Field) platoCue.SourceRef {
+	pos := field.Pos()
+	file := pos.Filename()
+	if s.file.Package == "" {
+		if rel, err := filepath.Rel(s.root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	} else {
+		file = filepath.Base(file)
+	}
+	return platoCue.SourceRef{File: file, Line: pos.Line(), Package: s.file.Package}
+}
+
+// structs returns the struct literals of a value, e.g. both sides of
+// #Base & {...} or the element of [...{...}], without their nested ones
+func structs(expr ast.Expr) []*ast.StructLit {
+	var lits []*ast.StructLit
+	ast.Walk(expr, func(n ast.Node) bool {
+		if st, ok := n.(*ast.StructLit); ok {
+			lits = append(lits, st)
+			return false
+		}
+		return true
+	}, nil)
+	return lits
+}
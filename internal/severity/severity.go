@@ -0,0 +1,193 @@
+// Package severity reclassifies validation and lint findings by code, as
+// configured under validation.severity in platosl.yaml
+package severity
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Level is how a finding is reported
+type Level string
+
+// Levels, from the strictest
+const (
+	Error  Level = "error"
+	Warn   Level = "warn"
+	Ignore Level = "ignore"
+)
+
+// Levels lists the valid levels
+var Levels = []Level{Error, Warn, Ignore}
+
+// Override sets the level of the findings a selector matches: a code
+// (PSL2003), a rule name (unconstrained-string), a category
+// (documentation) or * for all findings. With a glob, only findings in
+// files it matches are affected.
+type Override struct {
+	Level    Level
+	Selector string
+	Glob     string
+}
+
+// String formats the override as it is written in platosl.yaml
+func (o Override) String() string {
+	s := string(o.Level) + " " + o.Selector
+	if o.Glob != "" {
+		s += " in " + o.Glob
+	}
+	return s
+}
+
+// Policy holds the overrides of a project. When several match a finding,
+// the last one wins, so exceptions are listed after the rules they narrow.
+type Policy struct {
+	root      string
+	overrides []Override
+}
+
+// Parse reads overrides written as "<level> <selector> [in <glob>]", e.g.
+// "ignore PSL2003 in schemas/legacy/**". Globs are relative to root; **
+// matches any number of directories, and a glob matching a directory
+// matches the files in it.
+func Parse(root string, entries []string) (*Policy, error) {
+	p := &Policy{root: root}
+	for _, entry := range entries {
+		o, err := parseOverride(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validation.severity entry %q: %w", entry, err)
+		}
+		p.overrides = append(p.overrides, o)
+	}
+	return p, nil
+}
+
+func parseOverride(entry string) (Override, error) {
+	fields := strings.Fields(entry)
+	if len(fields) != 2 && (len(fields) != 4 || fields[2] != "in") {
+		return Override{}, fmt.Errorf("expected <level> <code> [in <glob>]")
+	}
+
+	o := Override{Level: Level(strings.ToLower(fields[0])), Selector: fields[1]}
+	if o.Level == "warning" {
+		o.Level = Warn
+	}
+	valid := false
+	for _, l := range Levels {
+		valid = valid || o.Level == l
+	}
+	if !valid {
+		return Override{}, fmt.Errorf("unknown level %q (expected error, warn or ignore)", fields[0])
+	}
+
+	if len(fields) == 4 {
+		o.Glob = strings.TrimPrefix(path.Clean(filepath.ToSlash(fields[3])), "./")
+		if _, err := path.Match(o.Glob, ""); err != nil {
+			return Override{}, fmt.Errorf("invalid glob %q: %w", fields[3], err)
+		}
+	}
+	return o, nil
+}
+
+// Overrides returns the overrides in the order they apply
+func (p *Policy) Overrides() []Override {
+	if p == nil {
+		return nil
+	}
+	return p.overrides
+}
+
+// Level returns the level of a finding in file, identified by names: its
+// code, rule name and category. def applies when no override matches. A
+// finding without a file is only matched by overrides without a glob.
+func (p *Policy) Level(names []string, file string, def Level) Level {
+	if p == nil {
+		return def
+	}
+	level := def
+	rel := p.relative(file)
+	for _, o := range p.overrides {
+		if !selects(o.Selector, names) {
+			continue
+		}
+		if o.Glob != "" && (rel == "" || !matchGlob(o.Glob, rel)) {
+			continue
+		}
+		level = o.Level
+	}
+	return level
+}
+
+// Raises reports whether an override reports some of the findings a
+// selector matches as errors or warnings, for checks that are off by default
+func (p *Policy) Raises(names []string) bool {
+	for _, o := range p.Overrides() {
+		if o.Level != Ignore && selects(o.Selector, names) {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns a file path relative to the root of the policy, with
+// forward slashes
+func (p *Policy) relative(file string) string {
+	if file == "" {
+		return ""
+	}
+	if filepath.IsAbs(file) && p.root != "" {
+		if rel, err := filepath.Rel(p.root, file); err == nil {
+			file = rel
+		}
+	}
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(file)), "./")
+}
+
+func selects(selector string, names []string) bool {
+	if selector == "*" {
+		return true
+	}
+	for _, name := range names {
+		if strings.EqualFold(selector, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether a glob matches a file or one of its directories
+func matchGlob(glob, file string) bool {
+	pattern := strings.Split(glob, "/")
+	parts := strings.Split(file, "/")
+	for i := len(parts); i > 0; i-- {
+		if matchSegments(pattern, parts[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against glob segments, where **
+// stands for any number of segments
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
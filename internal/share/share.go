@@ -0,0 +1,351 @@
+package share
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// SchemaFile is the name of the extracted schema in a bundle
+const SchemaFile = "schema.cue"
+
+// Bundle is the minimal set of declarations needed to reproduce a definition
+type Bundle struct {
+	// Definition is the shared definition, e.g. "#Person"
+	Definition string
+
+	// Package is the CUE package of the extracted declarations
+	Package string
+
+	// Sources are the schema files the declarations were taken from
+	Sources []string
+
+	// Declarations are the top-level labels included, in source order
+	Declarations []string
+
+	// External lists imports of packages outside the standard library,
+	// which the bundle references but does not contain
+	External []string
+
+	// Files maps file names to contents: the schema and a README
+	Files map[string][]byte
+}
+
+// decl is a top-level declaration and the file it came from
+type decl struct {
+	name string
+	node ast.Decl
+	file *source
+}
+
+// source is a parsed schema file
+type source struct {
+	path    string
+	file    *ast.File
+	imports map[string]*ast.ImportSpec // by the name used in the file
+}
+
+// Build extracts a definition and every top-level declaration it references,
+// directly or transitively, from the CUE files under paths. The result is a
+// single self-contained file, checked to compile before it is returned.
+func Build(paths []string, definition, version string) (*Bundle, error) {
+	root := strings.SplitN(strings.TrimPrefix(definition, "#"), ".", 2)[0]
+	root = "#" + root
+
+	sources, err := parseSources(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	decls := make(map[string]*decl)
+	var pkg string
+	for _, src := range sources {
+		for _, d := range src.file.Decls {
+			name := declName(d)
+			if name == "" {
+				continue
+			}
+			if _, ok := decls[name]; !ok {
+				decls[name] = &decl{name: name, node: d, file: src}
+			}
+		}
+		if name := src.file.PackageName(); name != "" && pkg == "" {
+			pkg = name
+		}
+	}
+	if decls[root] == nil {
+		return nil, fmt.Errorf("definition %s not found in %s", root, strings.Join(paths, ", "))
+	}
+
+	// Follow references from the definition to other top-level declarations
+	needed := map[string]bool{root: true}
+	usedImports := make(map[*source]map[string]bool)
+	queue := []string{root}
+	for len(queue) > 0 {
+		d := decls[queue[0]]
+		queue = queue[1:]
+
+		for _, ref := range references(d.node) {
+			if _, ok := d.file.imports[ref]; ok {
+				if usedImports[d.file] == nil {
+					usedImports[d.file] = make(map[string]bool)
+				}
+				usedImports[d.file][ref] = true
+				continue
+			}
+			if decls[ref] != nil && !needed[ref] {
+				needed[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	bundle := &Bundle{Definition: definition, Package: pkg, Files: make(map[string][]byte)}
+
+	// Keep source order: files as given, declarations as written
+	out := &ast.File{}
+	if pkg != "" {
+		out.Decls = append(out.Decls, &ast.Package{Name: ast.NewIdent(pkg)})
+	}
+
+	imports := importDecl(sources, usedImports)
+	if imports != nil {
+		out.Decls = append(out.Decls, imports)
+		for _, spec := range imports.Specs {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if !isStdlib(path) {
+				bundle.External = append(bundle.External, path)
+			}
+		}
+	}
+
+	seenSources := make(map[*source]bool)
+	for _, src := range sources {
+		for _, d := range src.file.Decls {
+			name := declName(d)
+			if !needed[name] || decls[name].node != d {
+				continue
+			}
+			out.Decls = append(out.Decls, d)
+			bundle.Declarations = append(bundle.Declarations, name)
+			if !seenSources[src] {
+				seenSources[src] = true
+				bundle.Sources = append(bundle.Sources, src.path)
+			}
+		}
+	}
+
+	schema, err := format.Node(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format bundle: %w", err)
+	}
+
+	// The bundle must reproduce the definition on its own. Packages outside
+	// the standard library cannot resolve here, so skip the check for them.
+	if len(bundle.External) == 0 {
+		val := cuecontext.New().CompileBytes(schema)
+		if err := val.Err(); err != nil {
+			return nil, fmt.Errorf("extracted schema does not compile: %w", err)
+		}
+		if _, err := platoCue.LookupDefinition(val, definition); err != nil {
+			return nil, fmt.Errorf("extracted schema is missing %s: %w", definition, err)
+		}
+	}
+
+	bundle.Files[SchemaFile] = schema
+	bundle.Files["README.md"] = []byte(readme(bundle, version))
+	return bundle, nil
+}
+
+// WriteDir writes the bundle's files into dir
+func (b *Bundle) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, name := range b.FileNames() {
+		if err := os.WriteFile(filepath.Join(dir, name), b.Files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// FileNames returns the bundle's file names, sorted
+func (b *Bundle) FileNames() []string {
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseSources parses every .cue file under paths, skipping cue.mod and
+// hidden directories
+func parseSources(paths []string) ([]*source, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "cue.mod") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".cue") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	sources := make([]*source, 0, len(files))
+	for _, path := range files {
+		f, err := parser.ParseFile(path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		src := &source{path: path, file: f, imports: make(map[string]*ast.ImportSpec)}
+		for _, spec := range f.Imports {
+			src.imports[importName(spec)] = spec
+		}
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+// declName returns the label of a top-level field or let clause
+func declName(d ast.Decl) string {
+	switch n := d.(type) {
+	case *ast.Field:
+		if ident, ok := n.Label.(*ast.Ident); ok {
+			return ident.Name
+		}
+	case *ast.LetClause:
+		return n.Ident.Name
+	}
+	return ""
+}
+
+// references returns the identifiers a declaration refers to. Field labels
+// and the selected names of selector expressions are not references.
+func references(d ast.Decl) []string {
+	var refs []string
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			// Labels in parentheses or interpolations may reference values
+			if _, ok := n.Label.(*ast.Ident); !ok {
+				ast.Walk(n.Label, visit, nil)
+			}
+			ast.Walk(n.Value, visit, nil)
+			return false
+		case *ast.LetClause:
+			ast.Walk(n.Expr, visit, nil)
+			return false
+		case *ast.SelectorExpr:
+			ast.Walk(n.X, visit, nil)
+			return false
+		case *ast.Ident:
+			refs = append(refs, n.Name)
+		}
+		return true
+	}
+	ast.Walk(d, visit, nil)
+	return refs
+}
+
+// importDecl merges the imports used by the extracted declarations
+func importDecl(sources []*source, used map[*source]map[string]bool) *ast.ImportDecl {
+	seen := make(map[string]bool)
+	decl := &ast.ImportDecl{}
+	for _, src := range sources {
+		names := make([]string, 0, len(used[src]))
+		for name := range used[src] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			spec := src.imports[name]
+			key := name + " " + spec.Path.Value
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			// Fresh specs: positions from different files confuse the formatter
+			path, _ := strconv.Unquote(spec.Path.Value)
+			var alias *ast.Ident
+			if spec.Name != nil {
+				alias = ast.NewIdent(spec.Name.Name)
+			}
+			decl.Specs = append(decl.Specs, ast.NewImport(alias, path))
+		}
+	}
+	if len(decl.Specs) == 0 {
+		return nil
+	}
+	return decl
+}
+
+// importName returns the name an import is referred to by in its file
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	path, _ := strconv.Unquote(spec.Path.Value)
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		return path[i+1:]
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// isStdlib reports whether an import path is a CUE standard library package,
+// whose first path element has no dot
+func isStdlib(path string) bool {
+	first := strings.SplitN(path, "/", 2)[0]
+	return !strings.Contains(first, ".")
+}
+
+func readme(b *Bundle, version string) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "# %s\n\n", b.Definition)
+	fmt.Fprintf(&s, "Minimal schema to reproduce `%s`, extracted by platosl %s from:\n\n", b.Definition, version)
+	for _, src := range b.Sources {
+		fmt.Fprintf(&s, "- `%s`\n", filepath.ToSlash(src))
+	}
+	s.WriteString("\nTry it with the cue command or paste `" + SchemaFile + "` into https://cuelang.org/play:\n\n")
+	s.WriteString("```bash\n")
+	fmt.Fprintf(&s, "cue eval %s -e '%s'\n", SchemaFile, b.Definition)
+	fmt.Fprintf(&s, "cue vet %s data.json -d '%s'\n", SchemaFile, b.Definition)
+	s.WriteString("```\n")
+	if len(b.External) > 0 {
+		s.WriteString("\nThis schema imports packages that are not included: ")
+		for i, path := range b.External {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			fmt.Fprintf(&s, "`%s`", path)
+		}
+		s.WriteString(".\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,66 @@
+package share
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"person.cue": `package schemas
+
+import "strings"
+
+#Person: {
+	name:    string & strings.MinRunes(1)
+	address: #Address
+}
+
+#Unrelated: {id: int}
+`,
+		"address.cue": `package schemas
+
+#Address: {
+	city:    string
+	country: #Country
+}
+
+#Country: "de" | "fr"
+`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundle, err := Build([]string{dir}, "#Person", "dev")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	for _, name := range []string{"#Person", "#Address", "#Country"} {
+		if !slices.Contains(bundle.Declarations, name) {
+			t.Errorf("bundle lacks %s: %v", name, bundle.Declarations)
+		}
+	}
+	schema := string(bundle.Files[SchemaFile])
+	if strings.Contains(schema, "#Unrelated") {
+		t.Errorf("bundle contains an unreferenced declaration:\n%s", schema)
+	}
+	if !strings.Contains(schema, `import "strings"`) || len(bundle.External) != 0 {
+		t.Errorf("bundle should import strings and nothing external:\n%s", schema)
+	}
+	if !reflect.DeepEqual(bundle.FileNames(), []string{"README.md", SchemaFile}) {
+		t.Errorf("FileNames() = %v", bundle.FileNames())
+	}
+
+	if _, err := Build([]string{dir}, "#Missing", "dev"); err == nil {
+		t.Errorf("Build of a missing definition did not fail")
+	}
+}
@@ -0,0 +1,73 @@
+package share
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/platoorg/plato-sl-cli/internal/config"
+	"github.com/platoorg/plato-sl-cli/internal/httpclient"
+)
+
+// GistAPI is the GitHub endpoint for creating gists
+const GistAPI = "https://api.github.com/gists"
+
+// Gist uploads a bundle as a GitHub gist and returns its URL. Gists are
+// secret (unlisted) unless public is set.
+func Gist(client *httpclient.Client, b *Bundle, public bool) (string, error) {
+	type gistFile struct {
+		Content string `json:"content"`
+	}
+	body := struct {
+		Description string              `json:"description"`
+		Public      bool                `json:"public"`
+		Files       map[string]gistFile `json:"files"`
+	}{
+		Description: fmt.Sprintf("PlatoSL schema: %s", b.Definition),
+		Public:      public,
+		Files:       make(map[string]gistFile, len(b.Files)),
+	}
+	for name, data := range b.Files {
+		body.Files[name] = gistFile{Content: string(data)}
+	}
+
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.DoJSON("POST", GistAPI, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.HTMLURL == "" {
+		return "", fmt.Errorf("gist created but no URL was returned")
+	}
+	return resp.HTMLURL, nil
+}
+
+// Registry uploads a bundle as a scratch entry to a registry. The registry
+// receives POST <registry>/scratch with the definition, package, files and
+// the project's package metadata when set, and answers with the entry's URL.
+func Registry(client *httpclient.Client, registry string, b *Bundle, meta config.MetadataConfig) (string, error) {
+	files := make(map[string]string, len(b.Files))
+	for name, data := range b.Files {
+		files[name] = string(data)
+	}
+	body := map[string]interface{}{
+		"definition": b.Definition,
+		"package":    b.Package,
+		"files":      files,
+	}
+	if !meta.IsZero() {
+		body["metadata"] = meta
+	}
+
+	var resp struct {
+		URL string `json:"url"`
+	}
+	endpoint := strings.TrimSuffix(registry, "/") + "/scratch"
+	if err := client.DoJSON("POST", endpoint, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.URL == "" {
+		return "", fmt.Errorf("scratch entry created but no URL was returned")
+	}
+	return resp.URL, nil
+}
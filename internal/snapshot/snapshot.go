@@ -0,0 +1,149 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Snapshot is a copy of project files as they were at a git ref
+type Snapshot struct {
+	// Ref is the git ref the files were read from
+	Ref string
+
+	// Dir is a temporary directory mirroring the project layout
+	Dir string
+
+	// Files are the extracted paths, relative to the project directory
+	Files []string
+}
+
+// Extract copies the files under paths (relative to the current directory)
+// at a git ref into a temporary directory. Paths missing at the ref are
+// skipped. Call Remove when done.
+func Extract(ref string, paths []string) (*Snapshot, error) {
+	if err := git(nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q", ref)
+	}
+
+	var out bytes.Buffer
+	args := append([]string{"ls-tree", "-r", "-z", "--name-only", ref, "--"}, paths...)
+	if err := git(&out, args...); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "platosl-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	snap := &Snapshot{Ref: ref, Dir: dir}
+
+	for _, name := range strings.Split(out.String(), "\x00") {
+		if name == "" {
+			continue
+		}
+
+		var content bytes.Buffer
+		if err := git(&content, "show", ref+":./"+filepath.ToSlash(name)); err != nil {
+			snap.Remove()
+			return nil, err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			snap.Remove()
+			return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, content.Bytes(), 0644); err != nil {
+			snap.Remove()
+			return nil, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		snap.Files = append(snap.Files, filepath.FromSlash(name))
+	}
+
+	return snap, nil
+}
+
+// Copy copies the files under paths (relative to the current directory) in
+// the working tree into a temporary directory, to try changes without
+// touching the project. Missing paths are skipped. Call Remove when done.
+func Copy(paths []string) (*Snapshot, error) {
+	dir, err := os.MkdirTemp("", "platosl-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	snap := &Snapshot{Ref: "working tree", Dir: dir}
+
+	for _, root := range paths {
+		err := filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && name == root {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			content, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+			}
+			if err := os.WriteFile(target, content, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", target, err)
+			}
+			snap.Files = append(snap.Files, filepath.Clean(name))
+			return nil
+		})
+		if err != nil {
+			snap.Remove()
+			return nil, err
+		}
+	}
+
+	return snap, nil
+}
+
+// Path returns where a project-relative path lives in the snapshot
+func (s *Snapshot) Path(path string) string {
+	return filepath.Join(s.Dir, path)
+}
+
+// Remove deletes the snapshot directory
+func (s *Snapshot) Remove() error {
+	return os.RemoveAll(s.Dir)
+}
+
+// Toplevel returns the root directory of the git repository holding the
+// current directory
+func Toplevel() (string, error) {
+	var out bytes.Buffer
+	if err := git(&out, "rev-parse", "--show-toplevel"); err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(strings.TrimSpace(out.String())), nil
+}
+
+// git runs a git command in the current directory, writing stdout to out
+func git(out *bytes.Buffer, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	if out != nil {
+		cmd.Stdout = out
+	}
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package webbundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+//go:embed runtime.ts
+var runtimeSource string
+
+// header starts every generated TypeScript file
+const header = "// Generated by PlatoSL\n// DO NOT EDIT - This file is auto-generated\n\n"
+
+// Bundle is a set of compiled definitions for browser-side validation
+type Bundle struct {
+	Schemas map[string]*Schema
+
+	// Unsupported lists constraints the runtime does not check, which the
+	// server-side validation still enforces
+	Unsupported []string
+}
+
+// File is a written bundle file with its size
+type File struct {
+	Path     string
+	Size     int
+	GzipSize int
+}
+
+// Build compiles the definitions of a schema value. With only set, just
+// those definitions and the ones they reference are included.
+func Build(val cue.Value, only []string) (*Bundle, error) {
+	values := make(map[string]cue.Value)
+	names := make(map[string]bool)
+
+	iter, err := val.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate definitions: %w", err)
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			name := strings.TrimPrefix(iter.Selector().String(), "#")
+			values[name] = iter.Value()
+			names[name] = true
+		}
+	}
+
+	bundle := &Bundle{Schemas: make(map[string]*Schema)}
+	var queue []string
+	if len(only) == 0 {
+		for name := range values {
+			queue = append(queue, name)
+		}
+	}
+	for _, name := range only {
+		name = strings.TrimPrefix(name, "#")
+		if !names[name] {
+			return nil, fmt.Errorf("definition #%s not found", name)
+		}
+		queue = append(queue, name)
+	}
+	sort.Strings(queue)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if bundle.Schemas[name] != nil {
+			continue
+		}
+
+		schema, unsupported := compileDefinition(name, values[name], names)
+		bundle.Schemas[name] = schema
+		bundle.Unsupported = append(bundle.Unsupported, unsupported...)
+		queue = append(queue, schema.Refs...)
+	}
+
+	sort.Strings(bundle.Unsupported)
+	return bundle, nil
+}
+
+// Names returns the bundled definition names, sorted
+func (b *Bundle) Names() []string {
+	names := make([]string, 0, len(b.Schemas))
+	for name := range b.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write writes the bundle into dir:
+//
+//	schemas/<Name>.json  one compact schema per definition, loaded on demand
+//	bundle.json          all schemas in one file, for apps that load them at once
+//	runtime.ts           the validator
+//	index.ts             typed loaders, one dynamic import per definition
+func (b *Bundle) Write(dir string) ([]File, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var files []File
+	write := func(name string, data []byte) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, File{Path: path, Size: len(data), GzipSize: gzipSize(data)})
+		return nil
+	}
+
+	all := make(map[string]*Schema, len(b.Schemas))
+	for _, name := range b.Names() {
+		data, err := json.Marshal(b.Schemas[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		if err := write(filepath.Join("schemas", name+".json"), data); err != nil {
+			return nil, err
+		}
+		all[name] = b.Schemas[name]
+	}
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	if err := write("bundle.json", data); err != nil {
+		return nil, err
+	}
+	if err := write("runtime.ts", []byte(header+runtimeSource)); err != nil {
+		return nil, err
+	}
+	if err := write("index.ts", []byte(b.index())); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// index generates the entry module with one lazy loader per definition, so
+// bundlers split each schema into its own chunk
+func (b *Bundle) index() string {
+	var s strings.Builder
+	s.WriteString(header)
+	s.WriteString("import { compile, type Schema, type Validator } from \"./runtime\";\n\n")
+	s.WriteString("export type { Schema, Validator, ValidationError } from \"./runtime\";\n\n")
+
+	names := b.Names()
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	fmt.Fprintf(&s, "export type DefinitionName = %s;\n\n", strings.Join(quoted, " | "))
+
+	s.WriteString("const loaders: Record<DefinitionName, () => Promise<{ default: unknown }>> = {\n")
+	for _, name := range names {
+		fmt.Fprintf(&s, "  %s: () => import(\"./schemas/%s.json\"),\n", identifier(name), name)
+	}
+	s.WriteString("};\n\n")
+
+	s.WriteString(`const validators = new Map<DefinitionName, Promise<Validator>>();
+
+// loadSchema fetches one compiled definition
+export function loadSchema(name: string): Promise<Schema> {
+  const loader = loaders[name as DefinitionName];
+  if (!loader) {
+    return Promise.reject(new Error("unknown definition: " + name));
+  }
+  return loader().then((m) => m.default as Schema);
+}
+
+// load returns the validator of a definition, loading its schema and the
+// schemas it references on first use
+export function load(name: DefinitionName): Promise<Validator> {
+  let validator = validators.get(name);
+  if (!validator) {
+    validator = compile(name, loadSchema);
+    validators.set(name, validator);
+  }
+  return validator;
+}
+`)
+	return s.String()
+}
+
+// identifier quotes a property name unless it is a valid identifier
+func identifier(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+func gzipSize(data []byte) int {
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	w.Write(data)
+	w.Close()
+	return buf.Len()
+}
@@ -0,0 +1,289 @@
+package webbundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	platoCue "github.com/platoorg/plato-sl-cli/internal/cue"
+)
+
+// maxDepth bounds the expansion of anonymous nested values
+const maxDepth = 32
+
+// Node is the compact form of a schema node. Keys are short to keep the
+// bundle small:
+//
+//	t     type: s(tring), i(nt), n(umber), b(ool), null, any, l(ist),
+//	      o(bject), r(eference), u(nion), c(onstant)
+//	v     constant value
+//	e     allowed string values
+//	min, max, xmin, xmax   inclusive and exclusive numeric bounds
+//	minl, maxl             length bounds of strings (in runes) and lists
+//	re    patterns a string must match
+//	ne    values that are not allowed
+//	p, r, c, x             object properties, required names, closed, and
+//	                       the schema of other properties
+//	i     list element schema
+//	d     referenced definition
+//	o     union members
+type Node struct {
+	T    string            `json:"t"`
+	V    json.RawMessage   `json:"v,omitempty"`
+	E    []string          `json:"e,omitempty"`
+	Min  *float64          `json:"min,omitempty"`
+	Max  *float64          `json:"max,omitempty"`
+	XMin *float64          `json:"xmin,omitempty"`
+	XMax *float64          `json:"xmax,omitempty"`
+	MinL *int              `json:"minl,omitempty"`
+	MaxL *int              `json:"maxl,omitempty"`
+	Re   []string          `json:"re,omitempty"`
+	Ne   []json.RawMessage `json:"ne,omitempty"`
+	P    map[string]*Node  `json:"p,omitempty"`
+	R    []string          `json:"r,omitempty"`
+	C    bool              `json:"c,omitempty"`
+	X    *Node             `json:"x,omitempty"`
+	I    *Node             `json:"i,omitempty"`
+	D    string            `json:"d,omitempty"`
+	O    []*Node           `json:"o,omitempty"`
+}
+
+// Schema is the compiled form of one definition
+type Schema struct {
+	// Definition is the definition name without the leading #
+	Definition string `json:"d"`
+
+	// Refs are the definitions this one references directly
+	Refs []string `json:"refs,omitempty"`
+
+	// Root is the schema of the definition's value
+	Root *Node `json:"s"`
+}
+
+// compiler converts CUE values of one definition to nodes
+type compiler struct {
+	defs        map[string]bool // names of top-level definitions, without #
+	refs        map[string]bool
+	unsupported []string
+}
+
+// compileDefinition compiles one top-level definition
+func compileDefinition(name string, val cue.Value, defs map[string]bool) (*Schema, []string) {
+	c := &compiler{defs: defs, refs: make(map[string]bool)}
+	root := c.node(val, name, 0, false)
+
+	schema := &Schema{Definition: name, Root: root}
+	for ref := range c.refs {
+		schema.Refs = append(schema.Refs, ref)
+	}
+	sort.Strings(schema.Refs)
+	return schema, c.unsupported
+}
+
+// node converts a value; path names it in notes about unsupported
+// constraints. allowRef is false for the definition's own root, which
+// would otherwise reference itself.
+func (c *compiler) node(val cue.Value, path string, depth int, allowRef bool) *Node {
+	if depth > maxDepth {
+		return &Node{T: "any"}
+	}
+
+	if allowRef {
+		if name := c.definitionRef(val); name != "" {
+			c.refs[name] = true
+			return &Node{T: "r", D: name}
+		}
+	}
+
+	if op, args := val.Expr(); op == cue.OrOp {
+		return c.union(args, path, depth)
+	}
+
+	typed, kind := platoCue.TypedKind(val)
+	if val.IsConcrete() && kind&(cue.StringKind|cue.NumberKind|cue.BoolKind|cue.NullKind) == kind {
+		return &Node{T: "c", V: rawJSON(val)}
+	}
+
+	var n *Node
+	switch kind {
+	case cue.StringKind:
+		n = &Node{T: "s"}
+	case cue.IntKind:
+		n = &Node{T: "i"}
+	case cue.FloatKind, cue.NumberKind:
+		n = &Node{T: "n"}
+	case cue.BoolKind:
+		return &Node{T: "b"}
+	case cue.NullKind:
+		return &Node{T: "null"}
+	case cue.ListKind:
+		n = &Node{T: "l"}
+		if elem := typed.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			n.I = c.node(elem, path+"[]", depth+1, true)
+		}
+	case cue.StructKind:
+		return c.object(typed, path, depth)
+	default:
+		return &Node{T: "any"}
+	}
+
+	c.constraints(n, val, path)
+	return n
+}
+
+// union converts a disjunction. Disjunctions of string literals become
+// enums; anything else is a union of its members.
+func (c *compiler) union(args []cue.Value, path string, depth int) *Node {
+	var values []string
+	for _, arg := range args {
+		s, err := arg.String()
+		if err != nil || !arg.IsConcrete() {
+			values = nil
+			break
+		}
+		values = append(values, s)
+	}
+	if len(values) == len(args) {
+		return &Node{T: "s", E: values}
+	}
+
+	n := &Node{T: "u"}
+	for _, arg := range args {
+		n.O = append(n.O, c.node(arg, path, depth+1, true))
+	}
+	return n
+}
+
+// object converts a struct with its fields, closedness and pattern. Fields
+// with a default are not required, as CUE fills them in when missing.
+func (c *compiler) object(val cue.Value, path string, depth int) *Node {
+	n := &Node{T: "o", P: make(map[string]*Node)}
+
+	iter, err := val.Fields(cue.Optional(true))
+	if err != nil {
+		return &Node{T: "any"}
+	}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		label := iter.Selector().Unquoted()
+		n.P[label] = c.node(iter.Value(), path+"."+label, depth+1, true)
+		if _, ok := platoCue.ScalarDefault(iter.Value()); !ok && !iter.IsOptional() {
+			n.R = append(n.R, label)
+		}
+	}
+
+	if pattern := val.LookupPath(cue.MakePath(cue.AnyString)); pattern.Exists() {
+		n.X = c.node(pattern, path+".*", depth+1, true)
+	} else if !val.Allows(cue.Str("\x00")) {
+		n.C = true
+	}
+	return n
+}
+
+// constraints collects bounds, patterns and length limits from the
+// conjuncts of a string, number or list value
+func (c *compiler) constraints(n *Node, val cue.Value, path string) {
+	var visit func(v cue.Value, depth int)
+	visit = func(v cue.Value, depth int) {
+		op, args := v.Expr()
+		if depth > maxDepth {
+			return
+		}
+
+		switch op {
+		case cue.AndOp:
+			for _, arg := range args {
+				visit(arg, depth+1)
+			}
+		case cue.GreaterThanEqualOp:
+			n.Min = tighter(n.Min, number(args[0]), true)
+		case cue.GreaterThanOp:
+			n.XMin = tighter(n.XMin, number(args[0]), true)
+		case cue.LessThanEqualOp:
+			n.Max = tighter(n.Max, number(args[0]), false)
+		case cue.LessThanOp:
+			n.XMax = tighter(n.XMax, number(args[0]), false)
+		case cue.RegexMatchOp:
+			if s, err := args[0].String(); err == nil {
+				n.Re = append(n.Re, s)
+			}
+		case cue.NotEqualOp:
+			if args[0].IsConcrete() {
+				n.Ne = append(n.Ne, rawJSON(args[0]))
+			}
+		case cue.CallOp:
+			c.call(n, args, path)
+		case cue.NoOp:
+			// The type itself
+		default:
+			c.unsupported = append(c.unsupported, fmt.Sprintf("%s: %v", path, v))
+		}
+	}
+	visit(val, 0)
+}
+
+// call handles the length builtins; other calls are reported as unsupported
+func (c *compiler) call(n *Node, args []cue.Value, path string) {
+	name := ""
+	if _, sel := args[0].Expr(); len(sel) == 2 {
+		name, _ = sel[1].String()
+	}
+
+	var limit int64
+	var err error = fmt.Errorf("no argument")
+	if len(args) == 2 {
+		limit, err = args[1].Int64()
+	}
+	if err == nil {
+		l := int(limit)
+		switch name {
+		case "MinRunes", "MinItems":
+			n.MinL = &l
+			return
+		case "MaxRunes", "MaxItems":
+			n.MaxL = &l
+			return
+		}
+	}
+	c.unsupported = append(c.unsupported, fmt.Sprintf("%s: %s()", path, name))
+}
+
+// definitionRef returns the name of the top-level definition a value
+// refers to, if it is a plain reference
+func (c *compiler) definitionRef(val cue.Value) string {
+	_, ref := val.ReferencePath()
+	sels := ref.Selectors()
+	if len(sels) != 1 || !sels[0].IsDefinition() {
+		return ""
+	}
+	name := strings.TrimPrefix(sels[0].String(), "#")
+	if !c.defs[name] {
+		return ""
+	}
+	return name
+}
+
+// tighter keeps the stricter of two bounds
+func tighter(current *float64, next float64, lower bool) *float64 {
+	if current == nil || (lower && next > *current) || (!lower && next < *current) {
+		return &next
+	}
+	return current
+}
+
+func number(val cue.Value) float64 {
+	f, _ := val.Float64()
+	return f
+}
+
+func rawJSON(val cue.Value) json.RawMessage {
+	data, err := val.MarshalJSON()
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
@@ -0,0 +1,29 @@
+package webbundle
+
+import (
+	"slices"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestDefaultedFieldsAreNotRequired(t *testing.T) {
+	val := cuecontext.New().CompileString(`
+#User: {
+	name:   string
+	nick?:  string
+	role:   *"member" | "admin"
+	active: bool | *false
+	score:  float | *1.5
+}
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, _ := compileDefinition("User", val.LookupPath(cue.ParsePath("#User")), map[string]bool{"User": true})
+	if got := schema.Root.R; !slices.Equal(got, []string{"name"}) {
+		t.Errorf("required = %v, want [name]", got)
+	}
+}
@@ -0,0 +1,153 @@
+// Validator for schemas compiled by 'platosl bundle'. Checks types, enums,
+// required and unknown fields, numeric bounds, lengths and patterns.
+
+export interface Node {
+  t: string;
+  v?: unknown;
+  e?: string[];
+  min?: number;
+  max?: number;
+  xmin?: number;
+  xmax?: number;
+  minl?: number;
+  maxl?: number;
+  re?: string[];
+  ne?: unknown[];
+  p?: Record<string, Node>;
+  r?: string[];
+  c?: boolean;
+  x?: Node;
+  i?: Node;
+  d?: string;
+  o?: Node[];
+}
+
+export interface Schema {
+  d: string;
+  refs?: string[];
+  s: Node;
+}
+
+export interface ValidationError {
+  path: string;
+  message: string;
+}
+
+export type Validator = (data: unknown) => ValidationError[];
+
+type Defs = Map<string, Schema>;
+
+// compile loads a definition and everything it references, then returns a
+// synchronous validator
+export async function compile(name: string, load: (name: string) => Promise<Schema>): Promise<Validator> {
+  const defs: Defs = new Map();
+  const pending = [name];
+  while (pending.length > 0) {
+    const next = pending.pop()!;
+    if (defs.has(next)) continue;
+    const schema = await load(next);
+    defs.set(next, schema);
+    pending.push(...(schema.refs ?? []));
+  }
+
+  const root = defs.get(name)!.s;
+  return (data) => {
+    const errors: ValidationError[] = [];
+    check(root, data, "", errors, defs);
+    return errors;
+  };
+}
+
+const patterns = new Map<string, RegExp>();
+
+function pattern(source: string): RegExp {
+  let re = patterns.get(source);
+  if (!re) {
+    re = new RegExp(source, "u");
+    patterns.set(source, re);
+  }
+  return re;
+}
+
+function join(path: string, key: string | number): string {
+  if (typeof key === "number") return `${path}[${key}]`;
+  return path ? `${path}.${key}` : key;
+}
+
+function equal(a: unknown, b: unknown): boolean {
+  return a === b || JSON.stringify(a) === JSON.stringify(b);
+}
+
+function check(n: Node, v: unknown, path: string, errors: ValidationError[], defs: Defs): void {
+  const fail = (message: string) => {
+    errors.push({ path, message });
+  };
+
+  switch (n.t) {
+    case "any":
+      return;
+    case "c":
+      if (!equal(v, n.v)) fail(`must be ${JSON.stringify(n.v)}`);
+      return;
+    case "r":
+      check(defs.get(n.d!)!.s, v, path, errors, defs);
+      return;
+    case "u":
+      for (const member of n.o!) {
+        const memberErrors: ValidationError[] = [];
+        check(member, v, path, memberErrors, defs);
+        if (memberErrors.length === 0) return;
+      }
+      fail("does not match any allowed value");
+      return;
+    case "null":
+      if (v !== null) fail("must be null");
+      return;
+    case "b":
+      if (typeof v !== "boolean") fail("must be a boolean");
+      return;
+    case "s": {
+      if (typeof v !== "string") return fail("must be a string");
+      if (n.e && !n.e.includes(v)) return fail(`must be one of ${n.e.map((e) => JSON.stringify(e)).join(", ")}`);
+      const length = [...v].length;
+      if (n.minl !== undefined && length < n.minl) fail(`must be at least ${n.minl} characters`);
+      if (n.maxl !== undefined && length > n.maxl) fail(`must be at most ${n.maxl} characters`);
+      for (const re of n.re ?? []) {
+        if (!pattern(re).test(v)) fail(`must match ${re}`);
+      }
+      break;
+    }
+    case "i":
+    case "n":
+      if (typeof v !== "number" || !Number.isFinite(v)) return fail("must be a number");
+      if (n.t === "i" && !Number.isInteger(v)) return fail("must be an integer");
+      if (n.min !== undefined && v < n.min) fail(`must be >= ${n.min}`);
+      if (n.max !== undefined && v > n.max) fail(`must be <= ${n.max}`);
+      if (n.xmin !== undefined && v <= n.xmin) fail(`must be > ${n.xmin}`);
+      if (n.xmax !== undefined && v >= n.xmax) fail(`must be < ${n.xmax}`);
+      break;
+    case "l":
+      if (!Array.isArray(v)) return fail("must be a list");
+      if (n.minl !== undefined && v.length < n.minl) fail(`must have at least ${n.minl} items`);
+      if (n.maxl !== undefined && v.length > n.maxl) fail(`must have at most ${n.maxl} items`);
+      if (n.i) v.forEach((item, i) => check(n.i!, item, join(path, i), errors, defs));
+      return;
+    case "o": {
+      if (typeof v !== "object" || v === null || Array.isArray(v)) return fail("must be an object");
+      const obj = v as Record<string, unknown>;
+      for (const key of n.r ?? []) {
+        if (!(key in obj)) errors.push({ path: join(path, key), message: "is required" });
+      }
+      for (const [key, value] of Object.entries(obj)) {
+        const field = n.p?.[key] ?? n.x;
+        if (field) check(field, value, join(path, key), errors, defs);
+        else if (n.c) errors.push({ path: join(path, key), message: "is not allowed" });
+      }
+      return;
+    }
+  }
+
+  for (const ne of n.ne ?? []) {
+    if (equal(v, ne)) fail(`must not be ${JSON.stringify(ne)}`);
+  }
+}
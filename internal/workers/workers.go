@@ -0,0 +1,186 @@
+package workers
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// decodeOverhead estimates how much memory a data file takes once decoded
+// and evaluated, relative to its size on disk
+const decodeOverhead = 8
+
+var (
+	maxWorkers   int
+	memoryBudget int64
+)
+
+// Configure sets the limits shared by all worker pools. workers <= 0 uses
+// one worker per CPU; memory <= 0 leaves memory unbounded. A memory budget
+// also becomes the Go runtime's soft memory limit, so the garbage collector
+// works harder before the process outgrows a small container.
+func Configure(workers int, memory int64) {
+	maxWorkers = workers
+	memoryBudget = memory
+	if memory > 0 {
+		debug.SetMemoryLimit(memory)
+	}
+}
+
+// MaxWorkers returns the configured number of workers
+func MaxWorkers() int {
+	if maxWorkers > 0 {
+		return maxWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// MemoryBudget returns the configured memory budget in bytes, or 0
+func MemoryBudget() int64 {
+	return memoryBudget
+}
+
+// Each calls fn for every index in [0, n) on at most MaxWorkers goroutines.
+// Every call runs even if some fail; the error of the lowest failing index
+// is returned so results do not depend on scheduling.
+func Each(n int, fn func(i int) error) error {
+	return Run(n, func() (struct{}, error) { return struct{}{}, nil }, func(_ struct{}, i int) error {
+		return fn(i)
+	})
+}
+
+// Run is Each with per-worker state: every worker calls setup once and
+// passes the result to each of its calls. Use it for state that must not be
+// shared between goroutines, such as a CUE context and values built in it.
+func Run[S any](n int, setup func() (S, error), fn func(state S, i int) error) error {
+	return RunWeighted(n, nil, setup, fn)
+}
+
+// RunWeighted is Run for jobs with a memory cost. Jobs only start while the
+// sum of the weights of running jobs fits the memory budget; a job heavier
+// than the whole budget runs on its own. weight may be nil.
+func RunWeighted[S any](n int, weight func(i int) int64, setup func() (S, error), fn func(state S, i int) error) error {
+	if n == 0 {
+		return nil
+	}
+
+	workers := MaxWorkers()
+	if workers > n {
+		workers = n
+	}
+
+	budget := newBudget(memoryBudget)
+	errs := make([]error, n)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			state, err := setup()
+			for i := range jobs {
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				var cost int64
+				if weight != nil {
+					cost = weight(i)
+				}
+				budget.acquire(cost)
+				errs[i] = fn(state, i)
+				budget.release(cost)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FileWeight estimates the memory needed to check a data file of the given size
+func FileWeight(size int64) int64 {
+	return size * decodeOverhead
+}
+
+// budget is a weighted semaphore over the memory budget
+type budget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+func newBudget(limit int64) *budget {
+	b := &budget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire blocks until cost fits the budget
+func (b *budget) acquire(cost int64) {
+	if b.limit <= 0 || cost <= 0 {
+		return
+	}
+	b.mu.Lock()
+	for b.used > 0 && b.used+cost > b.limit {
+		b.cond.Wait()
+	}
+	b.used += cost
+	b.mu.Unlock()
+}
+
+// release returns cost to the budget
+func (b *budget) release(cost int64) {
+	if b.limit <= 0 || cost <= 0 {
+		return
+	}
+	b.mu.Lock()
+	b.used -= cost
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// ParseSize parses a memory size such as "512MiB", "2G" or "1048576".
+// Units are binary: K, M and G (with optional "i" and "B") mean KiB, MiB and GiB.
+func ParseSize(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	upper := strings.ToUpper(str)
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}} {
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(upper, "B"), "I")
+		if strings.HasSuffix(trimmed, unit.suffix) {
+			multiplier = unit.size
+			upper = strings.TrimSuffix(trimmed, unit.suffix)
+			break
+		}
+	}
+	if multiplier == 1 {
+		upper = strings.TrimSuffix(upper, "B")
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 512MiB, 2G)", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
@@ -27,7 +27,7 @@ echo "--------------------"
 mkdir -p "$TEST_DIR"
 cd "$TEST_DIR"
 
-$BIN init test-project --name "Test Project"
+$BIN init test-project --name "Test Project" --yes
 cd test-project
 
 if [ -f "platosl.yaml" ]; then
@@ -74,12 +74,12 @@ else
 fi
 echo ""
 
-# Test 4: Generate TypeScript with Zod
-echo "Test 4: platosl gen typescript --zod"
-echo "-------------------------------------"
-$BIN gen typescript --zod --output generated/types-zod.ts
+# Test 4: Generate Zod schemas
+echo "Test 4: platosl gen zod"
+echo "-----------------------"
+$BIN gen zod --output generated/types-zod.ts
 if [ -f "generated/types-zod.ts" ]; then
-    echo "✓ TypeScript with Zod generated"
+    echo "✓ Zod schemas generated"
     if grep -q "import { z } from 'zod'" generated/types-zod.ts; then
         echo "✓ Zod import found"
     else
@@ -87,7 +87,7 @@ if [ -f "generated/types-zod.ts" ]; then
         exit 1
     fi
 else
-    echo "✗ TypeScript with Zod not generated"
+    echo "✗ Zod schemas not generated"
     exit 1
 fi
 echo ""
@@ -151,6 +151,94 @@ $BIN build
 echo "✓ Build command works"
 echo ""
 
+# Test 10: Compose command
+echo "Test 10: platosl compose"
+echo "------------------------"
+if $BIN compose '#Person' --format json | grep -q '"definition": "#Person"'; then
+    echo "✓ Compose command works"
+else
+    echo "✗ Compose did not report #Person"
+    exit 1
+fi
+echo ""
+
+# Test 11: Query command
+echo "Test 11: platosl query"
+echo "----------------------"
+if [ "$($BIN query -c '.#Person.fields[] | select(.optional) | .name')" = '"age"' ]; then
+    echo "✓ Query command works"
+else
+    echo "✗ Query did not select the optional field"
+    exit 1
+fi
+echo ""
+
+# Test 12: Export with --path
+echo "Test 12: platosl export --path"
+echo "------------------------------"
+mkdir -p data
+cat > data/catalog.cue <<'CUE'
+items: [{name: "a", price: 1}, {name: "b", price: 2}]
+CUE
+if [ "$($BIN export data/catalog.cue --path 'items[*].name' --format ndjson | tr '\n' ' ')" = '"a" "b" ' ]; then
+    echo "✓ Export selects the path"
+else
+    echo "✗ Export did not select items[*].name"
+    exit 1
+fi
+if $BIN export data/catalog.cue --path 'does.not.exist' > /dev/null 2>&1; then
+    echo "✗ Export of a missing path succeeded"
+    exit 1
+fi
+echo "✓ Export fails on a missing path"
+echo ""
+
+# Test 13: Share command
+echo "Test 13: platosl share"
+echo "----------------------"
+$BIN share '#Person' -o shared
+if grep -q '#Person' shared/schema.cue; then
+    echo "✓ Share bundle written"
+else
+    echo "✗ Share bundle lacks #Person"
+    exit 1
+fi
+echo ""
+
+# Test 14: Build attestation
+echo "Test 14: platosl build --attest"
+echo "-------------------------------"
+$BIN build --attest
+if grep -q 'generated/types.ts' generated/provenance.json; then
+    echo "✓ Attestation written"
+else
+    echo "✗ Attestation lacks the generated files"
+    exit 1
+fi
+echo ""
+
+# Test 15: Upgrade config
+echo "Test 15: platosl upgrade-config"
+echo "-------------------------------"
+cat > v1.yaml <<'YAML'
+version: v1
+name: Legacy
+schemas:
+  - schemas/
+generate:
+  typescript:
+    enabled: true
+    output: generated/legacy.ts
+YAML
+$BIN upgrade-config --config v1.yaml
+if grep -q 'version: v2' v1.yaml && ! grep -q 'output:' v1.yaml; then
+    echo "✓ Config upgraded to v2"
+else
+    echo "✗ Config not upgraded"
+    exit 1
+fi
+echo ""
+
 # Cleanup
 echo "Cleaning up..."
 cd /